@@ -0,0 +1,161 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"sort"
+
+	"github.com/becomeliminal/nim-go-sdk/core"
+	"github.com/becomeliminal/nim-go-sdk/tools"
+)
+
+// ============================================
+// ACCOUNT TYPE COMPARISON
+// ============================================
+// Every treatment costs the same pre-tax dollars each year: traditional
+// accounts take the full amount before tax, Roth and taxable accounts get what
+// is left after tax at the current marginal rate.
+
+// annualContributionLimits are the 2026 employee limits, before catch-up contributions
+var annualContributionLimits = map[string]float64{
+	"ira":  7500,
+	"401k": 24500,
+}
+
+// contributionLimitYear labels which year's limits annualContributionLimits holds
+const contributionLimitYear = 2026
+
+// taxableGainsRatePct is the long-term capital gains rate applied to taxable growth at withdrawal
+const taxableGainsRatePct = 15.0
+
+// maxTaxRatePct bounds the marginal tax rates accepted
+const maxTaxRatePct = 60.0
+
+// newCompareAccountsTool compares taxable, traditional and Roth treatment of the same savings
+func newCompareAccountsTool() core.Tool {
+	return tools.New("compare_account_types").
+		Description("Compare after-tax ending values of investing in a taxable brokerage account, a traditional (pre-tax) IRA/401k, or a Roth account, given today's and retirement tax rates").
+		Schema(tools.ObjectSchema(map[string]interface{}{
+			"annual_contribution": tools.StringProperty("Pre-tax dollars set aside each year in the account currency"),
+			"years":               tools.StringProperty("Years until withdrawal"),
+			"expected_return":     tools.StringProperty(fmt.Sprintf("Optional expected annual return percentage (defaults to the server's assumption, currently %g)", appConfig.Assumptions.EquityReturnPct)),
+			"current_tax_rate":    tools.StringProperty("Current marginal income tax rate percentage (e.g., '24')"),
+			"retirement_tax_rate": tools.StringProperty("Expected income tax rate in retirement percentage (e.g., '15')"),
+			"account":             tools.StringProperty("Which limit applies to the traditional and Roth accounts: 'ira' (default) or '401k'"),
+		}, "annual_contribution", "years", "current_tax_rate", "retirement_tax_rate")).
+		HandlerFunc(func(ctx context.Context, input json.RawMessage) (interface{}, error) {
+			var params struct {
+				AnnualContribution string `json:"annual_contribution"`
+				Years              string `json:"years"`
+				ExpectedReturn     string `json:"expected_return"`
+				CurrentTaxRate     string `json:"current_tax_rate"`
+				RetirementTaxRate  string `json:"retirement_tax_rate"`
+				Account            string `json:"account"`
+			}
+			if err := json.Unmarshal(input, &params); err != nil {
+				return nil, fmt.Errorf("invalid input: %w", err)
+			}
+			if params.Account == "" {
+				params.Account = "ira"
+			}
+
+			var v amountValidator
+			contribution := v.positive("annual_contribution", params.AnnualContribution)
+			years := v.years("years", params.Years, minProjectionYears, maxProjectionYears)
+			returnRate := v.returnRate("expected_return", params.ExpectedReturn, false)
+			currentTax := v.taxRate("current_tax_rate", params.CurrentTaxRate)
+			retirementTax := v.taxRate("retirement_tax_rate", params.RetirementTaxRate)
+			account := v.oneOf("account", params.Account, []string{"ira", "401k"})
+			if err := v.err(); err != nil {
+				return nil, err
+			}
+
+			return compareAccountTypes(contribution, years, returnRate, currentTax, retirementTax, account), nil
+		}).
+		Build()
+}
+
+// compareAccountTypes projects each treatment. Deposits above the account limit are
+// flagged and invested in a taxable account instead of projected as allowed.
+func compareAccountTypes(contribution, years, returnRate, currentTax, retirementTax float64, account string) AccountComparison {
+	months := max(int(math.Round(years*12)), 1)
+	limit := annualContributionLimits[account]
+	afterTax := contribution * (1 - currentTax/100)
+
+	taxable := func(annual float64) float64 {
+		balance := futureValue(0, annual/12, returnRate, float64(months))
+		gains := max(balance-annual/12*float64(months), 0)
+		return balance - gains*taxableGainsRatePct/100
+	}
+
+	// sheltered projects an account capped at the limit, overflowing into taxable.
+	// pretax is what the money costs before tax; deposit is what lands in the account.
+	sheltered := func(name string, deposit, pretax, withdrawalTax float64) AccountProjection {
+		allowed := min(deposit, limit)
+		excess := deposit - allowed
+		balance := futureValue(0, allowed/12, returnRate, float64(months))
+		p := AccountProjection{
+			Account:             name,
+			AnnualDepositUSD:    allowed,
+			AnnualPretaxCostUSD: pretax,
+			EndingBalanceUSD:    balance,
+			AfterTaxValueUSD:    balance * (1 - withdrawalTax/100),
+		}
+		if excess > 0 {
+			// Traditional excess is taxed now before it can go anywhere else
+			if name == "traditional" {
+				excess *= 1 - currentTax/100
+			}
+			p.ExcessToTaxableUSD = excess
+			p.AfterTaxValueUSD += taxable(excess)
+		}
+		return p
+	}
+
+	projections := []AccountProjection{
+		{
+			Account:             "taxable",
+			AnnualDepositUSD:    afterTax,
+			AnnualPretaxCostUSD: contribution,
+			EndingBalanceUSD:    futureValue(0, afterTax/12, returnRate, float64(months)),
+			AfterTaxValueUSD:    taxable(afterTax),
+		},
+		sheltered("traditional", contribution, contribution, retirementTax),
+		sheltered("roth", afterTax, contribution, 0),
+	}
+
+	ranked := append([]AccountProjection{}, projections...)
+	sort.SliceStable(ranked, func(i, j int) bool { return ranked[i].AfterTaxValueUSD > ranked[j].AfterTaxValueUSD })
+
+	c := AccountComparison{
+		Currency:      activeCurrency().Code,
+		Projections:   projections,
+		Winner:        ranked[0].Account,
+		RunnerUp:      ranked[1].Account,
+		DifferenceUSD: ranked[0].AfterTaxValueUSD - ranked[1].AfterTaxValueUSD,
+		Assumptions: map[string]interface{}{
+			"annual_pretax_contribution_usd": contribution,
+			"years":                          years,
+			"expected_return_percent":        returnRate,
+			"current_tax_rate_percent":       currentTax,
+			"retirement_tax_rate_percent":    retirementTax,
+			"taxable_gains_rate_percent":     taxableGainsRatePct,
+			"account_limit_usd":              limit,
+			"limit_year":                     contributionLimitYear,
+			"basis":                          "Each option costs the same pre-tax dollars; taxable gains are taxed once at withdrawal",
+		},
+		Message: fmt.Sprintf("%s comes out ahead by %s after tax over %.0f years.", ranked[0].Account, formatMoney(ranked[0].AfterTaxValueUSD-ranked[1].AfterTaxValueUSD), years),
+	}
+	for _, p := range projections {
+		if p.ExcessToTaxableUSD > 0 {
+			c.LimitExceeded = true
+		}
+	}
+	if c.LimitExceeded {
+		c.Warning = fmt.Sprintf("%s/year exceeds the %d %s limit of %s; only the limit is projected inside the account and the rest is treated as taxable investing.",
+			formatMoney(contribution), contributionLimitYear, account, formatWholeMoney(limit))
+	}
+	return c
+}
@@ -0,0 +1,315 @@
+package main
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"vibe-invest/storage"
+)
+
+// ============================================
+// ADMIN OPERATIONS SURFACE
+// ============================================
+// Support staff inspect and fix user state here. It listens on its own port
+// with its own token and is never registered as a model tool.
+
+// operatorHeader carries the identity of the support operator making the call
+const operatorHeader = "X-Operator"
+
+// startAdminServer serves the admin API on addr; disabled when no token is configured
+func startAdminServer(addr, token string) {
+	if token == "" {
+		log.Println("🔒 Admin API disabled (set ADMIN_TOKEN to enable)")
+		return
+	}
+
+	go func() {
+		log.Printf("🛠️  Admin API listening on %s\n", addr)
+		if err := http.ListenAndServe(addr, newAdminHandler(token)); err != nil {
+			log.Printf("❌ Admin API stopped: %v\n", err)
+		}
+	}()
+}
+
+func newAdminHandler(token string) http.Handler {
+	mux := http.NewServeMux()
+
+	// Read endpoints - each user read is audit-logged with the operator identity too
+	mux.HandleFunc("GET /admin/users/{id}/profile", func(w http.ResponseWriter, r *http.Request) {
+		userID := r.PathValue("id")
+		profile, err := loadPortfolio(r.Context(), userID)
+		if errors.Is(err, errNoProfile) {
+			writeJSON(w, http.StatusNotFound, map[string]string{"error": "profile not set"})
+			return
+		}
+		if err != nil {
+			writeStoreError(w, err)
+			return
+		}
+		readOnly, err := isReadOnly(r.Context(), userID)
+		if err != nil {
+			writeStoreError(w, err)
+			return
+		}
+		recordAudit(r.Context(), userID, operatorActor(r), "view_profile", "")
+		writeJSON(w, http.StatusOK, map[string]interface{}{
+			"user_id":   userID,
+			"profile":   profile,
+			"read_only": readOnly,
+		})
+	})
+	mux.HandleFunc("GET /admin/users/{id}/goals", func(w http.ResponseWriter, r *http.Request) {
+		goals, err := store.ListGoals(r.Context(), userKey(r.PathValue("id")))
+		if err != nil {
+			writeStoreError(w, err)
+			return
+		}
+		recordAudit(r.Context(), r.PathValue("id"), operatorActor(r), "view_goals", "")
+		writeJSON(w, http.StatusOK, map[string]interface{}{"goals": goals})
+	})
+	mux.HandleFunc("GET /admin/users/{id}/plans", func(w http.ResponseWriter, r *http.Request) {
+		plans, err := listPlans(r.Context(), userKey(r.PathValue("id")))
+		if err != nil {
+			writeStoreError(w, err)
+			return
+		}
+		recordAudit(r.Context(), r.PathValue("id"), operatorActor(r), "view_plans", "")
+		writeJSON(w, http.StatusOK, map[string]interface{}{"plans": plans})
+	})
+	mux.HandleFunc("GET /admin/users/{id}/audit", func(w http.ResponseWriter, r *http.Request) {
+		entries, err := store.ListAudit(r.Context(), userKey(r.PathValue("id")))
+		if err != nil {
+			writeStoreError(w, err)
+			return
+		}
+		// Recorded after the read, so the entry shows up in the next look rather than this one
+		recordAudit(r.Context(), r.PathValue("id"), operatorActor(r), "view_audit_log", "")
+		writeJSON(w, http.StatusOK, map[string]interface{}{"audit_log": entries})
+	})
+	mux.HandleFunc("GET /admin/plans/{id}/executions", func(w http.ResponseWriter, r *http.Request) {
+		execs, err := store.ListExecutions(r.Context(), r.PathValue("id"))
+		if err != nil {
+			writeStoreError(w, err)
+			return
+		}
+		// A plan's executions all belong to its user; with none there is no one's data to log
+		if len(execs) > 0 {
+			recordAudit(r.Context(), execs[0].UserID, operatorActor(r), "view_executions", r.PathValue("id"))
+		}
+		writeJSON(w, http.StatusOK, map[string]interface{}{"executions": execs})
+	})
+	mux.HandleFunc("GET /admin/stats", func(w http.ResponseWriter, r *http.Request) {
+		log.Printf("🛠️  Stats viewed by %s\n", operatorActor(r))
+		writeJSON(w, http.StatusOK, map[string]interface{}{"parse_cache": parseCache.stats()})
+	})
+
+	// Limited mutations - each one is audit-logged with the operator identity
+	mux.HandleFunc("POST /admin/users/{id}/read-only", func(w http.ResponseWriter, r *http.Request) {
+		var body struct {
+			ReadOnly *bool `json:"read_only"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil || body.ReadOnly == nil {
+			writeJSON(w, http.StatusBadRequest, map[string]string{"error": `body must be {"read_only": true|false}`})
+			return
+		}
+
+		userID := r.PathValue("id")
+		if err := store.SetReadOnly(r.Context(), userKey(userID), *body.ReadOnly); err != nil {
+			writeStoreError(w, err)
+			return
+		}
+		recordAudit(r.Context(), userID, operatorActor(r), "set_read_only", fmt.Sprintf("read_only=%t", *body.ReadOnly))
+		writeJSON(w, http.StatusOK, map[string]interface{}{"user_id": userID, "read_only": *body.ReadOnly})
+	})
+
+	mux.HandleFunc("POST /admin/executions/{id}/retry", func(w http.ResponseWriter, r *http.Request) {
+		if scheduler == nil {
+			writeJSON(w, http.StatusServiceUnavailable, map[string]string{"error": "plan scheduler is disabled"})
+			return
+		}
+		exec, err := store.GetExecution(r.Context(), r.PathValue("id"))
+		if errors.Is(err, storage.ErrNotFound) {
+			writeJSON(w, http.StatusNotFound, map[string]string{"error": "execution not found"})
+			return
+		}
+		if err != nil {
+			writeStoreError(w, err)
+			return
+		}
+		if exec.Status != storage.ExecutionFailed && exec.Status != storage.ExecutionExpired {
+			writeJSON(w, http.StatusConflict, map[string]string{"error": "only failed or expired executions can be retried (status: " + exec.Status + ")"})
+			return
+		}
+		plan, err := store.GetPlan(r.Context(), exec.UserID, exec.PlanID)
+		if err != nil {
+			writeStoreError(w, err)
+			return
+		}
+		plan = resumeIfDue(r.Context(), plan, time.Now().UTC())
+		if plan.Status != storage.PlanActive {
+			writeJSON(w, http.StatusConflict, map[string]string{"error": "only an active plan's executions can be retried (plan status: " + plan.Status + ")"})
+			return
+		}
+
+		// Reset to pending with a fresh attempt budget; the same execution ID keeps it idempotent
+		exec.Status = storage.ExecutionPending
+		exec.Attempts = 0
+		exec.LastError = ""
+		if err := store.SaveExecution(r.Context(), exec); err != nil {
+			writeStoreError(w, err)
+			return
+		}
+		recordAudit(r.Context(), exec.UserID, operatorActor(r), "retry_execution", exec.ID)
+		go scheduler.execute(context.Background(), plan, exec.Period)
+		writeJSON(w, http.StatusAccepted, map[string]interface{}{"execution_id": exec.ID, "status": exec.Status})
+	})
+
+	// A pending execution that never resolved (e.g. its transfer is still awaiting confirmation on
+	// the Liminal side) is re-attempted every day; expiring it stops that without deleting the record
+	mux.HandleFunc("POST /admin/executions/{id}/expire", func(w http.ResponseWriter, r *http.Request) {
+		exec, err := store.GetExecution(r.Context(), r.PathValue("id"))
+		if errors.Is(err, storage.ErrNotFound) {
+			writeJSON(w, http.StatusNotFound, map[string]string{"error": "execution not found"})
+			return
+		}
+		if err != nil {
+			writeStoreError(w, err)
+			return
+		}
+		if exec.Status != storage.ExecutionPending {
+			writeJSON(w, http.StatusConflict, map[string]string{"error": "only pending executions can be expired (status: " + exec.Status + ")"})
+			return
+		}
+		if scheduler != nil && scheduler.running(exec.ID) {
+			writeJSON(w, http.StatusConflict, map[string]string{"error": "execution is being attempted right now; try again once it finishes"})
+			return
+		}
+
+		exec.Status = storage.ExecutionExpired
+		exec.LastError = "expired by " + operatorActor(r)
+		exec.UpdatedAt = time.Now().UTC()
+		if err := store.SaveExecution(r.Context(), exec); err != nil {
+			writeStoreError(w, err)
+			return
+		}
+		recordAudit(r.Context(), exec.UserID, operatorActor(r), "expire_execution", exec.ID)
+		writeJSON(w, http.StatusOK, map[string]interface{}{"execution_id": exec.ID, "status": exec.Status})
+	})
+
+	// Concept content - not tied to a user, so reads and edits are logged rather than audit-logged
+	mux.HandleFunc("GET /admin/concepts", func(w http.ResponseWriter, r *http.Request) {
+		log.Printf("📚 Concepts listed by %s\n", operatorActor(r))
+		set := concepts.current()
+		writeJSON(w, http.StatusOK, map[string]interface{}{"concepts": set.records(), "source": set.source})
+	})
+	mux.HandleFunc("GET /admin/concepts/{key}", func(w http.ResponseWriter, r *http.Request) {
+		c, ok := concepts.current().concepts[normalizeConceptKey(r.PathValue("key"))]
+		if !ok {
+			writeJSON(w, http.StatusNotFound, map[string]string{"error": "concept not found"})
+			return
+		}
+		log.Printf("📚 Concept %s viewed by %s\n", c.Key, operatorActor(r))
+		writeJSON(w, http.StatusOK, c)
+	})
+	mux.HandleFunc("POST /admin/concepts", func(w http.ResponseWriter, r *http.Request) {
+		var body conceptRecord
+		dec := json.NewDecoder(r.Body)
+		dec.DisallowUnknownFields()
+		if err := dec.Decode(&body); err != nil {
+			writeJSON(w, http.StatusBadRequest, map[string]string{"error": "body must be a concept: " + err.Error()})
+			return
+		}
+		c, created, err := concepts.upsert(body)
+		if err != nil {
+			writeConceptError(w, err)
+			return
+		}
+		status, verb := http.StatusOK, "updated"
+		if created {
+			status, verb = http.StatusCreated, "added"
+		}
+		log.Printf("📚 Concept %s %s by %s\n", c.Key, verb, operatorActor(r))
+		writeJSON(w, status, c)
+	})
+	mux.HandleFunc("DELETE /admin/concepts/{key}", func(w http.ResponseWriter, r *http.Request) {
+		key := normalizeConceptKey(r.PathValue("key"))
+		if err := concepts.remove(key); err != nil {
+			writeConceptError(w, err)
+			return
+		}
+		log.Printf("📚 Concept %s removed by %s\n", key, operatorActor(r))
+		writeJSON(w, http.StatusOK, map[string]interface{}{"key": key, "removed": true})
+	})
+
+	// Content reload - not tied to a user, so logged rather than audit-logged
+	mux.HandleFunc("POST /admin/concepts/reload", func(w http.ResponseWriter, r *http.Request) {
+		n, err := concepts.reload()
+		if err != nil {
+			log.Printf("⚠️  Concept reload by %s failed, keeping the loaded concepts: %v\n", operatorActor(r), err)
+			writeJSON(w, http.StatusUnprocessableEntity, map[string]string{"error": err.Error()})
+			return
+		}
+		log.Printf("📚 Concepts reloaded by %s\n", operatorActor(r))
+		writeJSON(w, http.StatusOK, map[string]interface{}{"concepts": n, "source": concepts.current().source})
+	})
+
+	return requireAdmin(token, mux)
+}
+
+// requireAdmin checks the bearer token and operator identity before any admin route runs.
+// The token only counts in an "Authorization: Bearer <token>" header.
+func requireAdmin(token string, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		scheme, got, ok := strings.Cut(r.Header.Get("Authorization"), " ")
+		if !ok || !strings.EqualFold(scheme, "Bearer") || subtle.ConstantTimeCompare([]byte(got), []byte(token)) != 1 {
+			writeJSON(w, http.StatusUnauthorized, map[string]string{"error": "invalid admin token"})
+			return
+		}
+		if strings.TrimSpace(r.Header.Get(operatorHeader)) == "" {
+			writeJSON(w, http.StatusForbidden, map[string]string{"error": operatorHeader + " header is required"})
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+func operatorActor(r *http.Request) string {
+	return "operator:" + strings.TrimSpace(r.Header.Get(operatorHeader))
+}
+
+// writeConceptError maps a concept edit failure onto its status, with field errors for invalid input
+func writeConceptError(w http.ResponseWriter, err error) {
+	var fields conceptFieldErrors
+	switch {
+	case errors.As(err, &fields):
+		writeJSON(w, http.StatusUnprocessableEntity, map[string]interface{}{"error": "invalid concept", "fields": fields})
+	case errors.Is(err, errConceptNotFound):
+		writeJSON(w, http.StatusNotFound, map[string]string{"error": "concept not found"})
+	case errors.Is(err, errConceptsBuiltIn), errors.Is(err, errConceptInUse):
+		writeJSON(w, http.StatusConflict, map[string]string{"error": err.Error()})
+	default:
+		log.Printf("❌ Saving concepts failed: %v\n", err)
+		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "could not save concepts"})
+	}
+}
+
+// writeStoreError logs a storage failure and returns a generic 500 to the operator
+func writeStoreError(w http.ResponseWriter, err error) {
+	log.Printf("❌ Admin store error: %v\n", err)
+	writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "storage error"})
+}
+
+func writeJSON(w http.ResponseWriter, status int, body interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(body); err != nil {
+		log.Printf("⚠️  Failed to write admin response: %v\n", err)
+	}
+}
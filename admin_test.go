@@ -0,0 +1,185 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"vibe-invest/storage"
+)
+
+const testAdminToken = "s3cret-admin-token"
+
+// adminRequest sends one request through the admin handler
+func adminRequest(t *testing.T, method, path string, header map[string]string) *httptest.ResponseRecorder {
+	t.Helper()
+	req := httptest.NewRequest(method, path, nil)
+	for k, v := range header {
+		req.Header.Set(k, v)
+	}
+	rec := httptest.NewRecorder()
+	newAdminHandler(testAdminToken).ServeHTTP(rec, req)
+	return rec
+}
+
+func TestRequireAdminAuthorization(t *testing.T) {
+	tests := []struct {
+		name   string
+		header map[string]string
+		want   int
+	}{
+		{name: "no credentials", header: nil, want: http.StatusUnauthorized},
+		{name: "bare token without Bearer", header: map[string]string{"Authorization": testAdminToken, operatorHeader: "alice"}, want: http.StatusUnauthorized},
+		{name: "other scheme", header: map[string]string{"Authorization": "Basic " + testAdminToken, operatorHeader: "alice"}, want: http.StatusUnauthorized},
+		{name: "wrong token", header: map[string]string{"Authorization": "Bearer nope", operatorHeader: "alice"}, want: http.StatusUnauthorized},
+		{name: "token prefix only", header: map[string]string{"Authorization": "Bearer " + testAdminToken[:4], operatorHeader: "alice"}, want: http.StatusUnauthorized},
+		{name: "empty bearer", header: map[string]string{"Authorization": "Bearer ", operatorHeader: "alice"}, want: http.StatusUnauthorized},
+		{name: "no operator", header: map[string]string{"Authorization": "Bearer " + testAdminToken}, want: http.StatusForbidden},
+		{name: "blank operator", header: map[string]string{"Authorization": "Bearer " + testAdminToken, operatorHeader: "  "}, want: http.StatusForbidden},
+		{name: "authorized", header: map[string]string{"Authorization": "Bearer " + testAdminToken, operatorHeader: "alice"}, want: http.StatusOK},
+		{name: "scheme is case-insensitive", header: map[string]string{"Authorization": "bearer " + testAdminToken, operatorHeader: "alice"}, want: http.StatusOK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Every route sits behind the check, reads and mutations alike
+			for _, route := range []struct{ method, path string }{
+				{http.MethodGet, "/admin/stats"},
+				{http.MethodPost, "/admin/executions/missing/expire"},
+			} {
+				rec := adminRequest(t, route.method, route.path, tt.header)
+				want := tt.want
+				if want == http.StatusOK && route.method == http.MethodPost {
+					want = http.StatusNotFound // authorized, and the execution doesn't exist
+				}
+				if rec.Code != want {
+					t.Errorf("%s %s = %d, want %d (%s)", route.method, route.path, rec.Code, want, rec.Body)
+				}
+			}
+		})
+	}
+}
+
+func TestAdminExpireExecution(t *testing.T) {
+	ctx := context.Background()
+	auth := map[string]string{"Authorization": "Bearer " + testAdminToken, operatorHeader: "alice"}
+	now := time.Now().UTC()
+	for _, exec := range []storage.Execution{
+		{ID: "exec_stuck", PlanID: "plan_1", UserID: "admin_test_user", Period: "2026-09", Amount: 100, Status: storage.ExecutionPending, Attempts: 1, CreatedAt: now, UpdatedAt: now},
+		{ID: "exec_done", PlanID: "plan_1", UserID: "admin_test_user", Period: "2026-08", Amount: 100, Status: storage.ExecutionSucceeded, Attempts: 1, CreatedAt: now, UpdatedAt: now},
+	} {
+		if err := store.SaveExecution(ctx, exec); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	rec := adminRequest(t, http.MethodPost, "/admin/executions/exec_stuck/expire", auth)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expire pending = %d (%s), want 200", rec.Code, rec.Body)
+	}
+	var body map[string]string
+	if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil || body["status"] != storage.ExecutionExpired {
+		t.Errorf("expire response = %s, want status %q", rec.Body, storage.ExecutionExpired)
+	}
+	exec, err := store.GetExecution(ctx, "exec_stuck")
+	if err != nil || exec.Status != storage.ExecutionExpired {
+		t.Errorf("stored execution = %+v, %v; want expired", exec, err)
+	}
+	entries, err := store.ListAudit(ctx, "admin_test_user")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n := len(entries); n == 0 || entries[n-1].Action != "expire_execution" || entries[n-1].Actor != "operator:alice" {
+		t.Errorf("audit log = %+v, want expire_execution by operator:alice last", entries)
+	}
+
+	for _, id := range []string{"exec_stuck", "exec_done"} {
+		if rec := adminRequest(t, http.MethodPost, "/admin/executions/"+id+"/expire", auth); rec.Code != http.StatusConflict {
+			t.Errorf("expire %s again = %d, want 409", id, rec.Code)
+		}
+	}
+}
+
+// withScheduler installs a scheduler on exec for one test
+func withScheduler(t *testing.T, exec *fakeExecutor) {
+	t.Helper()
+	old := scheduler
+	scheduler = newPlanScheduler(exec)
+	scheduler.backoff = 0
+	t.Cleanup(func() { scheduler = old })
+}
+
+func TestAdminRetryNeedsActivePlan(t *testing.T) {
+	ctx := context.Background()
+	auth := map[string]string{"Authorization": "Bearer " + testAdminToken, operatorHeader: "alice"}
+	now := time.Now().UTC()
+	for _, status := range []string{storage.PlanCancelled, storage.PlanPaused} {
+		t.Run(status, func(t *testing.T) {
+			exec := &fakeExecutor{data: `{}`}
+			withScheduler(t, exec)
+			plan := storage.Plan{ID: "plan_retry_" + status, UserID: "admin_retry_user", MonthlyAmount: 100, InvestmentType: "savings",
+				StartDate: "2026-01-01", Status: status, CreatedAt: now}
+			if err := store.SavePlan(ctx, plan); err != nil {
+				t.Fatal(err)
+			}
+			id := executionID(plan.ID, "2026-09")
+			if err := store.SaveExecution(ctx, storage.Execution{ID: id, PlanID: plan.ID, UserID: plan.UserID, Period: "2026-09",
+				Amount: 100, Tool: "deposit_savings", Status: storage.ExecutionFailed, Attempts: 3, CreatedAt: now, UpdatedAt: now}); err != nil {
+				t.Fatal(err)
+			}
+
+			if rec := adminRequest(t, http.MethodPost, "/admin/executions/"+id+"/retry", auth); rec.Code != http.StatusConflict {
+				t.Errorf("retry = %d (%s), want 409", rec.Code, rec.Body)
+			}
+			if got, err := store.GetExecution(ctx, id); err != nil || got.Status != storage.ExecutionFailed {
+				t.Errorf("execution = %+v, %v; want it left failed", got, err)
+			}
+
+			// The scheduler checks again itself, whoever calls it
+			scheduler.execute(ctx, plan, "2026-10")
+			if len(exec.requests) != 0 {
+				t.Errorf("transfers = %+v, want none for a %s plan", exec.requests, status)
+			}
+		})
+	}
+}
+
+func TestAdminReadsAreAudited(t *testing.T) {
+	ctx := context.Background()
+	auth := map[string]string{"Authorization": "Bearer " + testAdminToken, operatorHeader: "bob"}
+	now := time.Now().UTC()
+	const user = "admin_read_user"
+	if rec := adminRequest(t, http.MethodGet, "/admin/users/"+user+"/profile", auth); rec.Code != http.StatusNotFound {
+		t.Errorf("profile before one is saved = %d (%s), want 404", rec.Code, rec.Body)
+	}
+	if err := store.SavePortfolio(ctx, storage.Portfolio{UserID: userKey(user), TotalBalance: 1000, RiskTolerance: string(RiskModerate)}); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.SaveExecution(ctx, storage.Execution{ID: "exec_read", PlanID: "plan_read", UserID: user, Period: "2026-09",
+		Amount: 100, Status: storage.ExecutionSucceeded, Attempts: 1, CreatedAt: now, UpdatedAt: now}); err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct{ path, action string }{
+		{"/admin/users/" + user + "/profile", "view_profile"},
+		{"/admin/users/" + user + "/goals", "view_goals"},
+		{"/admin/users/" + user + "/plans", "view_plans"},
+		{"/admin/users/" + user + "/audit", "view_audit_log"},
+		{"/admin/plans/plan_read/executions", "view_executions"},
+	}
+	for _, tt := range tests {
+		rec := adminRequest(t, http.MethodGet, tt.path, auth)
+		if rec.Code != http.StatusOK {
+			t.Errorf("GET %s = %d (%s), want 200", tt.path, rec.Code, rec.Body)
+			continue
+		}
+		entries, err := store.ListAudit(ctx, user)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if n := len(entries); n == 0 || entries[n-1].Action != tt.action || entries[n-1].Actor != "operator:bob" {
+			t.Errorf("GET %s: audit log = %+v, want %s by operator:bob last", tt.path, entries, tt.action)
+		}
+	}
+}
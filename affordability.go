@@ -0,0 +1,258 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/becomeliminal/nim-go-sdk/core"
+	"github.com/becomeliminal/nim-go-sdk/tools"
+
+	"vibe-invest/storage"
+)
+
+// ============================================
+// AFFORDABILITY
+// ============================================
+// Before a plan or goal commits the user to a monthly amount, it is compared
+// with what actually comes into their Liminal wallet. Average monthly inflow is
+// read from received transactions; the new amount plus any active plans must
+// stay within AFFORD_INFLOW_PCT of it to count as comfortable. The cash flow
+// forecast with the new amount added must also stay above CASH_SAFETY_BUFFER.
+// An unaffordable commitment can still be created, but only once the
+// confirmation prompt has carried the server's warning sentence
+// (confirmation_warning, passed back as affordability_ui).
+
+// Affordability statuses
+const (
+	affordComfortable = "comfortable"
+	affordTight       = "tight"          // within income but above the comfortable share
+	affordExceeds     = "exceeds_income" // more than the average monthly inflow
+	affordUnknown     = "unknown"        // no received transactions to judge by
+)
+
+// affordInflowTypes are the transaction types counted as money coming in
+var affordInflowTypes = map[string]bool{
+	"receive":  true,
+	"received": true,
+	"incoming": true,
+}
+
+// Affordability compares a monthly commitment with the user's recent inflow
+type Affordability struct {
+	Status                string   `json:"status"` // comfortable, tight, exceeds_income or unknown
+	MonthlyCommitmentUSD  float64  `json:"monthly_commitment_usd"`
+	ExistingPlansUSD      float64  `json:"existing_plans_usd"` // active automated plans, per month
+	MonthlyInflowUSD      float64  `json:"monthly_inflow_usd,omitempty"`
+	ComfortableSharePct   float64  `json:"comfortable_share_percent"`
+	SustainableMonthlyUSD float64  `json:"sustainable_monthly_usd,omitempty"` // suggested when not comfortable
+	WalletBalanceUSD      *float64 `json:"wallet_balance_usd,omitempty"`
+	SavingsBalanceUSD     *float64 `json:"savings_balance_usd,omitempty"`
+	ProjectedMinimumUSD   *float64 `json:"projected_minimum_balance_usd,omitempty"` // lowest forecast balance with the commitment, next 90 days
+	ProjectedMinimumDate  string   `json:"projected_minimum_date,omitempty"`
+	SafetyBufferUSD       float64  `json:"safety_buffer_usd"`
+	BelowSafetyBuffer     bool     `json:"below_safety_buffer"`
+	WarningRequired       bool     `json:"confirmation_warning_required"`
+	Warning               string   `json:"confirmation_warning,omitempty"` // the sentence affordability_ui must contain
+	Message               string   `json:"message"`
+}
+
+// warningMissing reports whether a needs a warning that the confirmation text ui doesn't carry.
+// Case and spacing are ignored; the wording itself must be there, so any non-empty text won't do.
+func (a *Affordability) warningMissing(ui string) bool {
+	if a == nil || !a.WarningRequired {
+		return false
+	}
+	return !strings.Contains(normalizeWarning(ui), normalizeWarning(a.Warning))
+}
+
+// normalizeWarning lower-cases text and collapses its whitespace for warningMissing
+func normalizeWarning(text string) string {
+	return strings.ToLower(strings.Join(strings.Fields(text), " "))
+}
+
+// confirmationWarning is the sentence a confirmation must show before an unaffordable commitment
+// goes through. It names only the amount, the problem and the buffer, not income or balances, so
+// the wording check_affordability returns still matches when the write tool re-checks.
+func confirmationWarning(a *Affordability) string {
+	var parts []string
+	switch a.Status {
+	case affordTight:
+		parts = append(parts, fmt.Sprintf("%s/month is above the %g%% of your income that's usually comfortable.", formatMoney(a.MonthlyCommitmentUSD), a.ComfortableSharePct))
+	case affordExceeds:
+		parts = append(parts, fmt.Sprintf("%s/month is more than your average monthly income.", formatMoney(a.MonthlyCommitmentUSD)))
+	}
+	if a.BelowSafetyBuffer {
+		parts = append(parts, fmt.Sprintf("It is projected to take your wallet below the %s safety buffer.", formatMoney(a.SafetyBufferUSD)))
+	}
+	return "Warning: " + strings.Join(parts, " ")
+}
+
+// checkAffordability judges monthly, first invested on start, against the user's inflow, balances and
+// cash flow through liminalExecutor. It returns nil without a user context, since there is no real
+// account to judge by.
+func checkAffordability(ctx context.Context, liminalExecutor core.ToolExecutor, userID string, monthly float64, start, now time.Time) *Affordability {
+	if userID == "" {
+		return nil
+	}
+	a := &Affordability{
+		MonthlyCommitmentUSD: monthly,
+		ComfortableSharePct:  appConfig.AffordInflowPct,
+		SafetyBufferUSD:      appConfig.CashBuffer,
+	}
+	if plans, err := listPlans(ctx, userKey(userID)); err == nil {
+		for _, p := range plans {
+			if p.Status == storage.PlanActive {
+				a.ExistingPlansUSD += p.MonthlyAmount
+			}
+		}
+	}
+	if balance, ok := fetchBalance(ctx, liminalExecutor, userID, "get_balance"); ok {
+		a.WalletBalanceUSD = &balance
+	}
+	if balance, ok := fetchSavingsBalance(ctx, liminalExecutor, userID); ok {
+		a.SavingsBalanceUSD = &balance
+	}
+	if inflow, ok := fetchMonthlyInflow(ctx, liminalExecutor, userID, now); ok {
+		judgeAffordability(a, inflow)
+	} else {
+		a.Status = affordUnknown
+		a.Message = fmt.Sprintf("No recent income was found in your Liminal history, so %s/month couldn't be checked against it. Make sure it fits your budget.", formatMoney(monthly))
+	}
+	if forecast, err := loadCashFlowForecast(ctx, liminalExecutor, userID, monthly, start, maxForecastDays, a.SafetyBufferUSD, now); err == nil {
+		judgeCashFlow(a, forecast)
+	}
+	if a.WarningRequired {
+		a.Warning = confirmationWarning(a)
+	}
+	return a
+}
+
+// judgeCashFlow warns on a when the forecast with the commitment dips below the safety buffer
+func judgeCashFlow(a *Affordability, forecast CashFlowForecast) {
+	a.ProjectedMinimumUSD = &forecast.MinimumBalanceUSD
+	a.ProjectedMinimumDate = forecast.MinimumDate
+	a.BelowSafetyBuffer = forecast.BelowSafetyBuffer
+	if !a.BelowSafetyBuffer {
+		return
+	}
+	a.WarningRequired = true
+	a.Message += fmt.Sprintf(" With it, your wallet is projected to fall to %s on %s, below the %s safety buffer (see forecast_cash_flow).",
+		formatMoney(forecast.MinimumBalanceUSD), forecast.MinimumDate, formatMoney(a.SafetyBufferUSD))
+}
+
+// judgeAffordability sets a's status, suggestion and message from an average monthly inflow
+func judgeAffordability(a *Affordability, inflow float64) {
+	a.MonthlyInflowUSD = inflow
+	total := a.MonthlyCommitmentUSD + a.ExistingPlansUSD
+	comfortable := inflow * a.ComfortableSharePct / 100
+	switch {
+	case total <= comfortable:
+		a.Status = affordComfortable
+	case total <= inflow:
+		a.Status = affordTight
+	default:
+		a.Status = affordExceeds
+	}
+	existing := ""
+	if a.ExistingPlansUSD > 0 {
+		existing = fmt.Sprintf(" on top of %s/month in active plans", formatMoney(a.ExistingPlansUSD))
+	}
+	if a.Status == affordComfortable {
+		a.Message = fmt.Sprintf("%s/month%s is %.0f%% of your average monthly income of %s - comfortable.",
+			formatMoney(a.MonthlyCommitmentUSD), existing, total/inflow*100, formatMoney(inflow))
+		return
+	}
+
+	a.WarningRequired = true
+	a.SustainableMonthlyUSD = math.Floor(max(comfortable-a.ExistingPlansUSD, 0))
+	if a.Status == affordTight {
+		a.Message = fmt.Sprintf("%s/month%s is %.0f%% of your average monthly income of %s, above the %g%% that's usually comfortable.",
+			formatMoney(a.MonthlyCommitmentUSD), existing, total/inflow*100, formatMoney(inflow), a.ComfortableSharePct)
+	} else {
+		a.Message = fmt.Sprintf("%s/month%s is more than your average monthly income of %s.",
+			formatMoney(a.MonthlyCommitmentUSD), existing, formatMoney(inflow))
+	}
+	if a.WalletBalanceUSD != nil && *a.WalletBalanceUSD > 0 {
+		a.Message += fmt.Sprintf(" Your wallet holds %s, enough for %.1f month(s) of it.", formatMoney(*a.WalletBalanceUSD), *a.WalletBalanceUSD/a.MonthlyCommitmentUSD)
+	}
+	if a.SustainableMonthlyUSD > 0 {
+		a.Message += fmt.Sprintf(" About %s/month would be sustainable.", formatMoney(a.SustainableMonthlyUSD))
+	} else {
+		a.Message += " Your active plans already use the comfortable share; consider pausing one first."
+	}
+}
+
+// fetchMonthlyInflow averages received transactions into a monthly figure; ok is false without any
+func fetchMonthlyInflow(ctx context.Context, liminalExecutor core.ToolExecutor, userID string, now time.Time) (float64, bool) {
+	txs, ok := fetchTransactions(ctx, liminalExecutor, userID, transactionPageSize)
+	if !ok {
+		return 0, false
+	}
+
+	received, receipts := 0.0, 0
+	oldest := now
+	for _, tx := range txs {
+		if kind, _ := tx["type"].(string); !affordInflowTypes[strings.ToLower(kind)] {
+			continue
+		}
+		received += math.Abs(transactionAmount(tx))
+		receipts++
+		if at, ok := transactionTime(tx); ok && at.Before(oldest) {
+			oldest = at
+		}
+	}
+	if receipts == 0 || received == 0 {
+		return 0, false
+	}
+	return received / transactionWindowDays(oldest, now) * 30, true
+}
+
+// affordabilityBlocked is the result a write tool returns when an unaffordable commitment was
+// confirmed without the warning in the prompt; asking again with affordability_ui set to a's
+// warning goes through
+func affordabilityBlocked(a *Affordability) *core.ToolResult {
+	status := a.Status
+	if a.BelowSafetyBuffer {
+		status += ", below safety buffer"
+	}
+	return &core.ToolResult{Success: false, Error: fmt.Sprintf(
+		"Not created yet (affordability: %s): %s Show the user this warning and, if they still want it, call again with affordability_ui set to %q so the confirmation includes it.",
+		status, a.Message, a.Warning)}
+}
+
+// newAffordabilityTool checks a monthly amount before a plan or goal asks for confirmation
+func newAffordabilityTool(liminalExecutor core.ToolExecutor) core.Tool {
+	return tools.New("check_affordability").
+		Description("Check whether a monthly investment amount fits the user's real income, balances and projected cash flow before start_automated_investing or create_investment_goal_with_transfer. Returns comfortable, tight or exceeds_income, a sustainable amount when too high, whether the 90-day balance forecast dips below the safety buffer, and, when a warning is required, the confirmation_warning to pass as affordability_ui so it appears in the confirmation").
+		Schema(tools.ObjectSchema(map[string]interface{}{
+			"monthly_amount": tools.StringProperty("Monthly amount the user wants to commit in the account currency"),
+		}, "monthly_amount")).
+		Handler(func(ctx context.Context, toolParams *core.ToolParams) (*core.ToolResult, error) {
+			var params struct {
+				MonthlyAmount string `json:"monthly_amount"`
+			}
+			if err := json.Unmarshal(toolParams.Input, &params); err != nil {
+				return &core.ToolResult{Success: false, Error: fmt.Sprintf("invalid input: %v", err)}, nil
+			}
+			var v amountValidator
+			monthly := v.positive("monthly_amount", params.MonthlyAmount)
+			if err := v.err(); err != nil {
+				return &core.ToolResult{Success: false, Error: err.Error()}, nil
+			}
+
+			now := time.Now().UTC()
+			a := checkAffordability(ctx, liminalExecutor, toolParams.UserID, monthly, now, now)
+			if a == nil {
+				return &core.ToolResult{Success: true, Data: map[string]interface{}{
+					"status":  affordUnknown,
+					"message": "No signed-in account to check against.",
+				}}, nil
+			}
+			return &core.ToolResult{Success: true, Data: a}, nil
+		}).
+		Build()
+}
@@ -0,0 +1,57 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestAffordabilityWarningMustBeConfirmed(t *testing.T) {
+	withCurrency(t, "USD")
+	tests := []struct {
+		name        string
+		monthly     float64
+		inflow      float64
+		belowBuffer bool
+		wantWarning string // "" when no warning is required
+	}{
+		{name: "comfortable", monthly: 300, inflow: 5000},
+		{name: "tight", monthly: 1500, inflow: 5000, wantWarning: "Warning: $1,500.00/month is above the 20% of your income that's usually comfortable."},
+		{name: "exceeds income", monthly: 6000, inflow: 5000, wantWarning: "Warning: $6,000.00/month is more than your average monthly income."},
+		{name: "comfortable but below buffer", monthly: 300, inflow: 5000, belowBuffer: true,
+			wantWarning: "Warning: It is projected to take your wallet below the $500.00 safety buffer."},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a := &Affordability{MonthlyCommitmentUSD: tt.monthly, ComfortableSharePct: 20, SafetyBufferUSD: 500}
+			judgeAffordability(a, tt.inflow)
+			if tt.belowBuffer {
+				judgeCashFlow(a, CashFlowForecast{MinimumBalanceUSD: 120, MinimumDate: "2026-11-01", BelowSafetyBuffer: true})
+			}
+			if a.WarningRequired {
+				a.Warning = confirmationWarning(a)
+			}
+			if a.Warning != tt.wantWarning {
+				t.Fatalf("warning = %q, want %q", a.Warning, tt.wantWarning)
+			}
+
+			for ui, wantMissing := range map[string]bool{
+				"":                      tt.wantWarning != "",
+				"ok":                    tt.wantWarning != "",
+				"I understand the risk": tt.wantWarning != "",
+				tt.wantWarning:          false,
+				"Heads up. " + strings.ToUpper(tt.wantWarning) + "  Proceed?": false,
+			} {
+				if got := a.warningMissing(ui); got != wantMissing {
+					t.Errorf("warningMissing(%q) = %v, want %v", ui, got, wantMissing)
+				}
+			}
+		})
+	}
+}
+
+func TestNoAffordabilityNeedsNoWarning(t *testing.T) {
+	var a *Affordability
+	if a.warningMissing("") {
+		t.Error("a nil affordability check (no signed-in user) should not block")
+	}
+}
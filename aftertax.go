@@ -0,0 +1,84 @@
+package main
+
+import (
+	"fmt"
+	"math"
+)
+
+// ============================================
+// AFTER-TAX PROJECTIONS
+// ============================================
+// calculate_investment_projection can apply an account's tax treatment to its
+// nominal total. Taxable accounts lose part of each year's dividends and
+// interest to tax at the marginal rate (the rest is reinvested and adds to cost
+// basis), then pay capital gains on the remaining gain at the end. Traditional
+// accounts pay the marginal rate on the whole withdrawal; Roth accounts pay nothing.
+
+// projectionAccountTypes are the tax treatments calculate_investment_projection understands
+var projectionAccountTypes = []string{"taxable", "traditional", "roth"}
+
+// taxableDistributionYieldPct is the share of a taxable account paid out each year as dividends and interest
+const taxableDistributionYieldPct = 2.0
+
+// withTaxes adds the after-tax view of a projection for accountType at marginalRate (ignored for roth).
+// Call it after withInflation so the today's-dollar figure uses the same inflation rate.
+func (p ProjectionResult) withTaxes(accountType string, marginalRate float64) ProjectionResult {
+	t := TaxTreatment{
+		AccountType:            accountType,
+		MarginalTaxRatePercent: marginalRate,
+		PreTaxTotalUSD:         p.ProjectedTotalUSD,
+	}
+	switch accountType {
+	case "taxable":
+		balance, basis, dividendTax := taxableGrowth(p.InitialInvestment, p.MonthlyContribution, p.AnnualIncreasePercent, p.AnnualReturnPercent, marginalRate, p.Months)
+		gainsTax := max(balance-basis, 0) * taxableGainsRatePct / 100
+		t.PreTaxTotalUSD = balance
+		t.DistributionTaxUSD = dividendTax
+		t.CapitalGainsTaxUSD = gainsTax
+		t.AfterTaxTotalUSD = balance - gainsTax
+		t.TaxDragUSD = p.ProjectedTotalUSD - balance
+		t.Assumptions = []string{
+			fmt.Sprintf("%g%% of the balance is paid out each year as dividends and interest, taxed at your %g%% marginal rate and reinvested", taxableDistributionYieldPct, marginalRate),
+			fmt.Sprintf("The remaining gain is taxed at a %g%% long-term capital gains rate when sold at the end", taxableGainsRatePct),
+			"Qualified dividends are often taxed below the marginal rate, so the yearly drag here is on the cautious side",
+		}
+	case "traditional":
+		t.WithdrawalTaxUSD = p.ProjectedTotalUSD * marginalRate / 100
+		t.AfterTaxTotalUSD = p.ProjectedTotalUSD - t.WithdrawalTaxUSD
+		t.Assumptions = []string{
+			"Growth is tax-deferred; contributions are treated as pre-tax",
+			fmt.Sprintf("The whole withdrawal is taxed as income at %g%%; a lower rate in retirement would leave more", marginalRate),
+		}
+	default: // roth
+		t.MarginalTaxRatePercent = 0
+		t.AfterTaxTotalUSD = p.ProjectedTotalUSD
+		t.Assumptions = []string{"Contributions are after tax and qualified withdrawals are tax-free, so nothing is deducted"}
+	}
+	t.TotalTaxUSD = p.ProjectedTotalUSD - t.AfterTaxTotalUSD
+	t.AfterTaxTotal = formatMoney(t.AfterTaxTotalUSD)
+	t.AfterTaxTotalRealUSD = realValue(t.AfterTaxTotalUSD, p.InflationRatePercent, float64(p.Months))
+	t.Note = fmt.Sprintf("In a %s account the %s projection is worth about %s after tax (%s to taxes).",
+		accountType, p.ProjectedTotal, t.AfterTaxTotal, formatMoney(t.TotalTaxUSD))
+	p.AfterTax = &t
+	return p
+}
+
+// taxableGrowth projects a taxable account month by month: the distribution yield is taxed as it is
+// paid and the after-tax remainder reinvested, adding to cost basis alongside every contribution.
+// The monthly contribution rises by increasePct every 12 months.
+func taxableGrowth(initial, monthly, increasePct, returnRate, marginalRate float64, months int) (balance, basis, distributionTax float64) {
+	priceRate := (returnRate - taxableDistributionYieldPct) / 100 / 12
+	yieldRate := taxableDistributionYieldPct / 100 / 12
+	balance, basis = initial, initial
+	for m := 1; m <= months; m++ {
+		distribution := math.Max(balance, 0) * yieldRate
+		tax := distribution * marginalRate / 100
+		balance = balance*(1+priceRate) + distribution - tax + monthly
+		basis += distribution - tax + monthly
+		distributionTax += tax
+		if m%12 == 0 {
+			monthly *= 1 + increasePct/100
+		}
+	}
+	return balance, basis, distributionTax
+}
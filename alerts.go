@@ -0,0 +1,163 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/becomeliminal/nim-go-sdk/core"
+	"github.com/becomeliminal/nim-go-sdk/tools"
+
+	"vibe-invest/storage"
+)
+
+// ============================================
+// PROACTIVE ALERTS
+// ============================================
+// After each daily snapshot, the stored portfolio's asset classes are compared
+// with its risk level's targets, each ± REBALANCE_BAND_PCT. An asset class
+// outside its band opens an allocation_drift alert keyed to that class. While
+// it stays outside, the same alert is refreshed with the latest figures rather
+// than raised again; once it is back inside its band the alert clears on its
+// own. get_alerts reports the open alerts, flagging those not yet mentioned to
+// the user.
+
+// driftAlertKey identifies the drift alert for one asset class
+func driftAlertKey(asset string) string {
+	return storage.AlertAllocationDrift + ":" + asset
+}
+
+// checkDriftAlerts raises or clears the drift alerts of a stored portfolio valued at classes.
+// With no classes (a portfolio without holdings) nothing is raised and any open drift alert clears.
+// Failures are logged rather than stopping the snapshot job.
+func checkDriftAlerts(ctx context.Context, p storage.Portfolio, classes map[string]float64, now time.Time) {
+	risk, err := normalizeRiskLevel(p.RiskTolerance)
+	if err != nil {
+		risk = RiskModerate
+	}
+	drift, _, _, _ := allocationDriftOf(classes, risk)
+
+	for _, asset := range rebalanceAssets {
+		d, ok := drift[asset]
+		if !ok || !d.OutsideBand {
+			continue
+		}
+		alert := storage.Alert{
+			ID:        generateRandomID(),
+			UserID:    p.UserID,
+			Kind:      storage.AlertAllocationDrift,
+			Key:       driftAlertKey(asset),
+			Message:   driftAlertMessage(asset, d),
+			CreatedAt: now,
+			UpdatedAt: now,
+		}
+		opened, err := store.RaiseAlert(ctx, alert)
+		if err != nil {
+			log.Printf("⚠️  Failed to raise drift alert %s for %s: %v\n", alert.Key, p.UserID, err)
+			continue
+		}
+		if opened {
+			log.Printf("🔔 %s: %s\n", p.UserID, alert.Message)
+			recordAudit(ctx, p.UserID, "alerts", "alert_raised", alert.Key)
+		}
+	}
+
+	// Classes back inside their band, or no longer compared at all, clear their alert
+	alerts, err := store.ListAlerts(ctx, p.UserID)
+	if err != nil {
+		log.Printf("⚠️  Drift check could not load alerts for %s: %v\n", p.UserID, err)
+		return
+	}
+	for _, a := range alerts {
+		asset, isDrift := strings.CutPrefix(a.Key, storage.AlertAllocationDrift+":")
+		if !isDrift || a.ClearedAt != nil || drift[asset].OutsideBand {
+			continue
+		}
+		cleared, err := store.ClearAlert(ctx, p.UserID, a.Key, now)
+		if err != nil {
+			log.Printf("⚠️  Failed to clear drift alert %s for %s: %v\n", a.Key, p.UserID, err)
+			continue
+		}
+		if cleared {
+			recordAudit(ctx, p.UserID, "alerts", "alert_cleared", a.Key)
+		}
+	}
+}
+
+// allocationDriftOf compares asset class values with risk's targets ± REBALANCE_BAND_PCT, folding international and
+// REIT targets into stocks when those classes aren't held separately. See calculateAllocationDrift
+// for maxDrift and outside; total is the classes' sum, and nothing is compared when it is zero.
+func allocationDriftOf(classes map[string]float64, risk RiskLevel) (drift map[string]assetDrift, maxDrift float64, outside bool, total float64) {
+	for _, value := range classes {
+		total += value
+	}
+	if total <= 0 {
+		return nil, 0, false, 0
+	}
+	var folded []string
+	for _, asset := range []string{"international", "reit"} {
+		if _, held := classes[asset]; !held {
+			folded = append(folded, asset)
+		}
+	}
+	targets := overrideTolerance(foldIntoStocks(riskAllocationModel[risk], folded...), appConfig.RebalanceBandPct)
+	drift, maxDrift, outside = calculateAllocationDrift(classes, targets, total)
+	return drift, maxDrift, outside, total
+}
+
+// driftAlertMessage is the alert text, e.g. "Your stocks allocation has drifted to 72% vs your 60% target"
+func driftAlertMessage(asset string, d assetDrift) string {
+	return fmt.Sprintf("Your %s allocation has drifted to %s%% vs your %s%% target (band %s-%s%%).",
+		asset, formatPercentValue(d.CurrentPercent), formatPercentValue(d.TargetPercent),
+		formatPercentValue(d.BandLowPercent), formatPercentValue(d.BandHighPercent))
+}
+
+// newAlertsTool reports the user's open alerts, flagging the ones not mentioned before
+func newAlertsTool() core.Tool {
+	return tools.New("get_alerts").
+		Description("Get alerts raised about the user's finances since they last asked, such as an asset class drifting outside its target allocation band (\"your stock allocation has drifted to 72% vs your 60% target\"). Alerts listed under new haven't been mentioned to the user yet; they are marked announced once returned. Alerts clear on their own once the condition goes away. Call it at the start of a conversation").
+		Schema(tools.ObjectSchema(map[string]interface{}{})).
+		Handler(func(ctx context.Context, toolParams *core.ToolParams) (*core.ToolResult, error) {
+			user := userKey(toolParams.UserID)
+			alerts, err := store.ListAlerts(ctx, user)
+			if err != nil {
+				return &core.ToolResult{Success: false, Error: fmt.Sprintf("could not load alerts: %v", err)}, nil
+			}
+
+			r := AlertsResult{Open: []storage.Alert{}, New: []storage.Alert{}}
+			for _, a := range alerts {
+				if a.ClearedAt != nil {
+					continue
+				}
+				r.Open = append(r.Open, a)
+				if a.AnnouncedAt == nil {
+					r.New = append(r.New, a)
+				}
+			}
+			switch {
+			case len(r.New) > 0:
+				r.Message = fmt.Sprintf("%d new alert(s) to mention: ", len(r.New))
+				for i, a := range r.New {
+					if i > 0 {
+						r.Message += " "
+					}
+					r.Message += a.Message
+				}
+				r.Message += " rebalance_investment_portfolio can show the trades to get back on target."
+			case len(r.Open) > 0:
+				r.Message = fmt.Sprintf("%d open alert(s), all already mentioned.", len(r.Open))
+			default:
+				r.Message = "No open alerts."
+			}
+
+			if len(r.New) > 0 {
+				if err := store.MarkAlertsAnnounced(ctx, user, time.Now().UTC()); err != nil {
+					log.Printf("⚠️  Failed to mark alerts announced for %s: %v\n", user, err)
+				}
+			}
+			return &core.ToolResult{Success: true, Data: r}, nil
+		}).
+		Build()
+}
@@ -0,0 +1,53 @@
+package main
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"vibe-invest/storage"
+)
+
+func TestDriftAlertsNeedHoldings(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2026, 10, 16, 0, 0, 0, 0, time.UTC)
+	job := &snapshotJob{}
+	openDrift := func(userID string) []string {
+		t.Helper()
+		alerts, err := store.ListAlerts(ctx, userID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var keys []string
+		for _, a := range alerts {
+			if a.Kind == storage.AlertAllocationDrift && a.ClearedAt == nil {
+				keys = append(keys, a.Key)
+			}
+		}
+		return keys
+	}
+
+	// A moderate profile that is all stocks and savings has no bonds to speak of, not zero bonds
+	profileOnly := storage.Portfolio{UserID: "drift_profile_only", RiskTolerance: string(RiskModerate), TotalBalance: 10000, StockAllocation: 6000, SavingsAllocation: 4000}
+	_, classes, _ := job.value(ctx, profileOnly)
+	checkDriftAlerts(ctx, profileOnly, classes, now)
+	if keys := openDrift(profileOnly.UserID); len(keys) != 0 {
+		t.Errorf("profile without holdings raised %v", keys)
+	}
+
+	// Recorded holdings with no bonds do drift, and removing the holdings clears the alert
+	held := profileOnly
+	held.UserID = "drift_with_holdings"
+	held.Holdings = []storage.Holding{{Identifier: "VTI", AssetClass: "stocks", Value: 6000}, {Identifier: "CASH", AssetClass: "cash", Value: 4000}}
+	_, classes, _ = job.value(ctx, held)
+	checkDriftAlerts(ctx, held, classes, now)
+	if keys := openDrift(held.UserID); len(keys) == 0 {
+		t.Error("holdings without bonds raised no drift alert")
+	}
+	held.Holdings = nil
+	_, classes, _ = job.value(ctx, held)
+	checkDriftAlerts(ctx, held, classes, now.Add(24*time.Hour))
+	if keys := openDrift(held.UserID); len(keys) != 0 {
+		t.Errorf("alerts %v stayed open once the holdings were removed", keys)
+	}
+}
@@ -0,0 +1,119 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// ============================================
+// TOLERANT AMOUNT PARSING
+// ============================================
+
+// currencyTokens are stripped before parsing; longer codes first so "usd" wins over "$"
+var currencyTokens = []string{"usd", "eur", "gbp", "us$", "$", "€", "£", "¥"}
+
+// amountMultipliers expand shorthand suffixes like "$5k"; longest suffixes first
+var amountMultipliers = []struct {
+	suffix string
+	factor float64
+}{
+	{"mm", 1e6},
+	{"bn", 1e9},
+	{"k", 1e3},
+	{"m", 1e6},
+}
+
+// parseAmount reads user-phrased numbers: "$1,500", "$2 000", "3.5%", "10k", "1.234,56 €" or "£3,5".
+// Currency symbols, whitespace, thousands separators and a trailing "%" are ignored.
+func parseAmount(raw string) (float64, error) {
+	return parseNumber(raw, false)
+}
+
+// parseRate reads a rate, percentage or number of years the way parseAmount reads money, except
+// that a lone separator is always the decimal point: "6.875" is 6.875, never 6875
+func parseRate(raw string) (float64, error) {
+	return parseNumber(raw, true)
+}
+
+// parseNumber is parseAmount, with decimalOnly never reading a lone separator as thousands grouping
+func parseNumber(raw string, decimalOnly bool) (float64, error) {
+	s := strings.ToLower(strings.TrimSpace(raw))
+	s = strings.Map(func(r rune) rune {
+		if unicode.IsSpace(r) || r == '\'' || r == '_' {
+			return -1
+		}
+		return r
+	}, s)
+	for _, token := range currencyTokens {
+		s = strings.ReplaceAll(s, token, "")
+	}
+	s, percent := strings.CutSuffix(s, "%")
+	s = strings.TrimPrefix(s, "+")
+	if s == "" {
+		return 0, fmt.Errorf("%q is not a number", raw)
+	}
+
+	multiplier := 1.0
+	for _, m := range amountMultipliers {
+		n := len(s) - len(m.suffix)
+		if n > 0 && strings.HasSuffix(s, m.suffix) && isDigit(s[n-1]) {
+			multiplier = m.factor
+			s = s[:n]
+			break
+		}
+	}
+
+	// A shorthand "1.250k" or a rate "3.125%" is never thousands-grouped
+	decimalOnly = decimalOnly || multiplier != 1 || percent
+	v, err := strconv.ParseFloat(normalizeSeparators(s, decimalOnly), 64)
+	if err != nil || math.IsNaN(v) || math.IsInf(v, 0) {
+		return 0, fmt.Errorf("%q is not a number", raw)
+	}
+	return v * multiplier, nil
+}
+
+// normalizeSeparators converts grouping and decimal separators to Go's "1234.56" form.
+// A lone separator between 1-3 leading digits and exactly three more groups thousands in
+// every currency, so "1.500" (formatWholeMoney's EUR output) and "1,500" are both 1500;
+// "3,5", "1234,56", "1234.567" and "0.125" keep it as the decimal point, as does decimalOnly.
+func normalizeSeparators(s string, decimalOnly bool) string {
+	commas := strings.Count(s, ",")
+	dots := strings.Count(s, ".")
+
+	switch {
+	case commas > 0 && dots > 0:
+		// Whichever separator comes last is the decimal point
+		if strings.LastIndex(s, ",") > strings.LastIndex(s, ".") {
+			return strings.Replace(strings.ReplaceAll(s, ".", ""), ",", ".", 1)
+		}
+		return strings.ReplaceAll(s, ",", "")
+	case commas > 1:
+		return strings.ReplaceAll(s, ",", "")
+	case dots > 1:
+		return strings.ReplaceAll(s, ".", "")
+	case commas+dots == 1:
+		sep := ","
+		if dots == 1 {
+			sep = "."
+		}
+		if !decimalOnly && groupsThousands(s, sep) {
+			return strings.Replace(s, sep, "", 1)
+		}
+		return strings.Replace(s, ",", ".", 1)
+	}
+	return s
+}
+
+// groupsThousands reports whether s's only separator, sep, splits 1-3 digits (no leading zero)
+// from exactly three
+func groupsThousands(s, sep string) bool {
+	whole, frac, _ := strings.Cut(strings.TrimPrefix(s, "-"), sep)
+	return len(frac) == 3 && len(whole) >= 1 && len(whole) <= 3 && whole[0] != '0'
+}
+
+func isDigit(b byte) bool {
+	return b >= '0' && b <= '9'
+}
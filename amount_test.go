@@ -0,0 +1,162 @@
+package main
+
+import (
+	"math"
+	"testing"
+)
+
+// withCurrency switches CURRENCY for one test
+func withCurrency(t *testing.T, code string) {
+	t.Helper()
+	old := appConfig.Currency
+	appConfig.Currency = code
+	t.Cleanup(func() { appConfig.Currency = old })
+}
+
+// withLocale switches LOCALE for one test
+func withLocale(t *testing.T, locale string) {
+	t.Helper()
+	old := appConfig.Locale
+	appConfig.Locale = locale
+	t.Cleanup(func() { appConfig.Locale = old })
+}
+
+func TestParseAmount(t *testing.T) {
+	withCurrency(t, "USD")
+	tests := []struct {
+		raw  string
+		want float64
+	}{
+		{"1500", 1500},
+		{"$1,500", 1500},
+		{"1,500", 1500},
+		{"1,234,567.89", 1234567.89},
+		{"$2 000", 2000},
+		{"1 500", 1500},
+		{"1'500", 1500},
+		{"1.500,50", 1500.50},
+		{"1.234,56 €", 1234.56},
+		{"£3,5", 3.5},
+		{"1234,56", 1234.56},
+		{"1.234.567", 1234567},
+		{"3.5%", 3.5},
+		{"7 %", 7},
+		{"1k", 1000},
+		{"10k", 10000},
+		{"$5k", 5000},
+		{"2.5K", 2500},
+		{"1.2m", 1200000},
+		{"3mm", 3000000},
+		{"1bn", 1000000000},
+		{"USD 300", 300},
+		{"us$45", 45},
+		{"+40", 40},
+		{"-250", -250},
+		{"0", 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.raw, func(t *testing.T) {
+			got, err := parseAmount(tt.raw)
+			if err != nil {
+				t.Fatalf("parseAmount(%q) error: %v", tt.raw, err)
+			}
+			if !approxEqual(got, tt.want) {
+				t.Errorf("parseAmount(%q) = %v, want %v", tt.raw, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseAmountRejectsBadInput(t *testing.T) {
+	withCurrency(t, "USD")
+	for _, raw := range []string{"", "  ", "abc", "$", "%", "k", "12abc", "1,2,3.4.5", "five hundred", "NaN", "inf", "--5", "1e400"} {
+		t.Run(raw, func(t *testing.T) {
+			if got, err := parseAmount(raw); err == nil {
+				t.Errorf("parseAmount(%q) = %v, want an error", raw, got)
+			}
+		})
+	}
+}
+
+func TestParseAmountSingleSeparator(t *testing.T) {
+	tests := []struct {
+		raw  string
+		want float64
+	}{
+		{"1.500", 1500},
+		{"1,500", 1500},
+		{"-1.500", -1500},
+		{"12.345", 12345},
+		{"999,999", 999999},
+		{"1.5", 1.5},
+		{"1,50", 1.5},
+		{"3,5", 3.5},
+		{"0.125", 0.125},
+		{"0,125", 0.125},
+		{"1234.567", 1234.567},
+		{"1.5000", 1.5},
+		{"1.250k", 1250},
+		{"3.125%", 3.125},
+	}
+	// The same answer whatever the currency writes as its decimal separator
+	for _, currency := range []string{"USD", "EUR", "GBP"} {
+		t.Run(currency, func(t *testing.T) {
+			withCurrency(t, currency)
+			for _, tt := range tests {
+				got, err := parseAmount(tt.raw)
+				if err != nil || !approxEqual(got, tt.want) {
+					t.Errorf("parseAmount(%q) = %v, %v; want %v", tt.raw, got, err, tt.want)
+				}
+			}
+		})
+	}
+}
+
+func TestParseAmountReadsFormattedMoney(t *testing.T) {
+	amounts := []float64{0, 0.99, 7, 999, 1500, 1500.5, 12345, 12345.67, 250000, 1234567}
+	for _, tc := range []struct{ currency, locale string }{
+		{"USD", "en"}, {"EUR", "en"}, {"EUR", "es"}, {"GBP", "en"}, {"USD", "es"},
+	} {
+		t.Run(tc.currency+"/"+tc.locale, func(t *testing.T) {
+			withCurrency(t, tc.currency)
+			withLocale(t, tc.locale)
+			for _, amount := range amounts {
+				texts := []string{formatMoney(amount)}
+				if amount == math.Trunc(amount) {
+					texts = append(texts, formatWholeMoney(amount))
+				}
+				for _, text := range texts {
+					if got, err := parseAmount(text); err != nil || !approxEqual(got, amount) {
+						t.Errorf("parseAmount(%q) = %v, %v; want %v", text, got, err, amount)
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestParseRateNeverGroupsThousands(t *testing.T) {
+	withCurrency(t, "USD")
+	tests := []struct {
+		raw    string
+		amount float64 // parseAmount
+		rate   float64 // parseRate
+	}{
+		{"6.875", 6875, 6.875},
+		{"7.125", 7125, 7.125},
+		{"1.500", 1500, 1.5},
+		{"1,500", 1500, 1.5},
+		{"6,875", 6875, 6.875},
+		{"6.875%", 6.875, 6.875},
+		{"1,234.5", 1234.5, 1234.5},
+		{"7", 7, 7},
+	}
+	for _, tt := range tests {
+		if got, err := parseAmount(tt.raw); err != nil || !approxEqual(got, tt.amount) {
+			t.Errorf("parseAmount(%q) = %v, %v; want %v", tt.raw, got, err, tt.amount)
+		}
+		if got, err := parseRate(tt.raw); err != nil || !approxEqual(got, tt.rate) {
+			t.Errorf("parseRate(%q) = %v, %v; want %v", tt.raw, got, err, tt.rate)
+		}
+	}
+}
@@ -0,0 +1,203 @@
+package main
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/becomeliminal/nim-go-sdk/core"
+	"github.com/becomeliminal/nim-go-sdk/tools"
+)
+
+// ============================================
+// SPENDING ANOMALIES
+// ============================================
+// The last 30 days of spending, per category, against the average of the three
+// 30-day periods before them. Comparing equal spans means the check reads the
+// same on the 2nd of the month as on the 28th, where month-to-date spending
+// against whole months would look low early on and never flag anything. Only
+// periods the transaction history fully covers count toward the average.
+// Plans with skip_on_spike_percent set run the same check on their execution
+// day and skip that month when total spending is up by more than it.
+
+// Spending anomaly bounds
+const (
+	anomalyWindowDays      = 30 // length of the current period and of each baseline period
+	anomalyTrailingPeriods = 3
+	minAnomalyDeltaUSD     = 25.0  // a category must also be up by at least this much to be flagged
+	maxSpikeThresholdPct   = 500.0 // largest threshold a tool or plan accepts
+)
+
+// uncategorizedSpending groups purchases without a category
+const uncategorizedSpending = "uncategorized"
+
+// newSpendingAnomaliesTool flags categories where this month's spending is well above normal
+func newSpendingAnomaliesTool(liminalExecutor core.ToolExecutor) core.Tool {
+	return tools.New("check_spending_anomalies").
+		Description("Compare the last 30 days of spending per category with the user's average over the three 30-day periods before and flag categories that are up sharply, in dollars and percent. Use it when a user has had an expensive month and is wondering whether to keep investing").
+		Schema(tools.ObjectSchema(map[string]interface{}{
+			"threshold_percent": tools.StringProperty(fmt.Sprintf("Optional increase over the 3-period average, in percent, that counts as a spike (default %g)", appConfig.SpendSpikePct)),
+		})).
+		Handler(func(ctx context.Context, toolParams *core.ToolParams) (*core.ToolResult, error) {
+			var params struct {
+				ThresholdPercent string `json:"threshold_percent"`
+			}
+			if len(toolParams.Input) > 0 {
+				if err := json.Unmarshal(toolParams.Input, &params); err != nil {
+					return &core.ToolResult{Success: false, Error: fmt.Sprintf("invalid input: %v", err)}, nil
+				}
+			}
+			var v amountValidator
+			threshold := optionalPercent(&v, "threshold_percent", params.ThresholdPercent, appConfig.SpendSpikePct, maxSpikeThresholdPct)
+			if err := v.err(); err != nil {
+				return &core.ToolResult{Success: false, Error: err.Error()}, nil
+			}
+
+			txs, ok := fetchTransactions(ctx, liminalExecutor, toolParams.UserID, spendingPageSize)
+			if !ok {
+				return &core.ToolResult{Success: false, Error: "could not load transaction history"}, nil
+			}
+			return &core.ToolResult{Success: true, Data: detectSpendingAnomalies(txs, threshold, time.Now().UTC())}, nil
+		}).
+		Build()
+}
+
+// detectSpendingAnomalies compares the 30 days to now's spending per category with the trailing average
+func detectSpendingAnomalies(txs []map[string]interface{}, threshold float64, now time.Time) SpendingAnomalyReport {
+	window := anomalyWindowDays * 24 * time.Hour
+	periodStart := now.Add(-window)
+	r := SpendingAnomalyReport{
+		Currency:         activeCurrency().Code,
+		PeriodStart:      periodStart.Format("2006-01-02"),
+		PeriodEnd:        now.Format("2006-01-02"),
+		ThresholdPercent: threshold,
+		Categories:       []CategorySpending{},
+	}
+
+	// Only whole periods the history reaches back over count toward the average
+	oldest := now
+	for _, tx := range txs {
+		if at, ok := transactionTime(tx); ok {
+			oldest = minTime(oldest, at)
+		}
+	}
+	for p := 1; p <= anomalyTrailingPeriods; p++ {
+		if !oldest.After(periodStart.Add(-time.Duration(p) * window)) {
+			r.BaselinePeriods = p
+		}
+	}
+	if r.BaselinePeriods == 0 {
+		r.Message = fmt.Sprintf("There isn't %d days of transaction history before the last %d to compare against yet.", anomalyWindowDays, anomalyWindowDays)
+		return r
+	}
+
+	baselineStart := periodStart.Add(-time.Duration(r.BaselinePeriods) * window)
+	current := map[string]float64{}
+	trailing := map[string]float64{}
+	for _, tx := range txs {
+		at, ok := transactionTime(tx)
+		if !ok || at.Before(baselineStart) || at.After(now) || spendingCategory(tx) != spendingCategorySpend {
+			continue
+		}
+		if at.Before(periodStart) {
+			trailing[spendingCategoryName(tx)] += math.Abs(transactionAmount(tx))
+		} else {
+			current[spendingCategoryName(tx)] += math.Abs(transactionAmount(tx))
+		}
+	}
+
+	for category := range mergeKeys(current, trailing) {
+		c := CategorySpending{
+			Category:           category,
+			CurrentUSD:         current[category],
+			TrailingAverageUSD: trailing[category] / float64(r.BaselinePeriods),
+		}
+		c.DeltaUSD = c.CurrentUSD - c.TrailingAverageUSD
+		if c.TrailingAverageUSD > 0 {
+			c.ChangePercent = c.DeltaUSD / c.TrailingAverageUSD * 100
+		} else {
+			c.NewCategory = true
+		}
+		c.Flagged = c.DeltaUSD >= minAnomalyDeltaUSD && (c.NewCategory || c.ChangePercent > threshold)
+		r.Categories = append(r.Categories, c)
+		r.CurrentTotalUSD += c.CurrentUSD
+		r.TrailingAverageUSD += c.TrailingAverageUSD
+	}
+	slices.SortFunc(r.Categories, func(a, b CategorySpending) int {
+		if c := cmp.Compare(b.DeltaUSD, a.DeltaUSD); c != 0 {
+			return c
+		}
+		return strings.Compare(a.Category, b.Category)
+	})
+	r.TotalDeltaUSD = r.CurrentTotalUSD - r.TrailingAverageUSD
+	if r.TrailingAverageUSD > 0 {
+		r.TotalChangePercent = r.TotalDeltaUSD / r.TrailingAverageUSD * 100
+	}
+	r.Spike = r.TrailingAverageUSD > 0 && r.TotalChangePercent > threshold && r.TotalDeltaUSD >= minAnomalyDeltaUSD
+
+	var flagged []string
+	for _, c := range r.Categories {
+		if !c.Flagged {
+			continue
+		}
+		r.FlaggedCount++
+		if c.NewCategory {
+			flagged = append(flagged, fmt.Sprintf("%s +%s (new)", c.Category, formatWholeMoney(c.DeltaUSD)))
+		} else {
+			flagged = append(flagged, fmt.Sprintf("%s +%s (+%.0f%%)", c.Category, formatWholeMoney(c.DeltaUSD), c.ChangePercent))
+		}
+	}
+	r.Message = fmt.Sprintf("In the last %d days you've spent %s against an average of %s over the %d period(s) of %d days before (%s%s).",
+		anomalyWindowDays, formatWholeMoney(r.CurrentTotalUSD), formatWholeMoney(r.TrailingAverageUSD), r.BaselinePeriods, anomalyWindowDays,
+		signedWholeMoney(r.TotalDeltaUSD), percentSuffix(r.TrailingAverageUSD, r.TotalChangePercent))
+	if len(flagged) > 0 {
+		r.Message += " Up sharply: " + strings.Join(flagged, ", ") + "."
+	} else {
+		r.Message += " No category is unusually high."
+	}
+	if r.Spike {
+		r.Message += " One expensive month is normal; pausing or skipping a single investment is better than cancelling the plan."
+	}
+	return r
+}
+
+// spendingCategoryName is a purchase's category, lower-cased, or uncategorizedSpending
+func spendingCategoryName(tx map[string]interface{}) string {
+	if category, _ := tx["category"].(string); strings.TrimSpace(category) != "" {
+		return strings.ToLower(strings.Join(strings.Fields(category), " "))
+	}
+	return uncategorizedSpending
+}
+
+// mergeKeys is the set of keys in either map
+func mergeKeys(a, b map[string]float64) map[string]bool {
+	keys := make(map[string]bool, len(a)+len(b))
+	for k := range a {
+		keys[k] = true
+	}
+	for k := range b {
+		keys[k] = true
+	}
+	return keys
+}
+
+// signedWholeMoney formats a change with an explicit + or - sign
+func signedWholeMoney(delta float64) string {
+	if delta < 0 {
+		return "-" + formatWholeMoney(-delta)
+	}
+	return "+" + formatWholeMoney(delta)
+}
+
+// percentSuffix renders ", +12%" for a change against a non-zero base
+func percentSuffix(base, pct float64) string {
+	if base <= 0 {
+		return ""
+	}
+	return fmt.Sprintf(", %+.0f%%", pct)
+}
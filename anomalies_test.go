@@ -0,0 +1,53 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSpendingAnomaliesCompareEqualPeriods(t *testing.T) {
+	// spendEvery spreads amount a day across the days before now, so every 30 days cost the same
+	spendEvery := func(now time.Time, days int, amount float64) []map[string]interface{} {
+		txs := make([]map[string]interface{}, 0, days)
+		for d := range days {
+			txs = append(txs, map[string]interface{}{"type": "card_payment", "amount": -amount, "category": "Groceries",
+				"created_at": now.Add(-time.Duration(d)*24*time.Hour - time.Hour).Format(time.RFC3339)})
+		}
+		return txs
+	}
+	tests := []struct {
+		name        string
+		now         time.Time
+		txs         func(now time.Time) []map[string]interface{}
+		wantPeriods int
+		wantSpike   bool
+	}{
+		// Month-to-date against whole months read 2 days as a 93% drop; equal spans read it as flat
+		{name: "steady spending early in the month", now: time.Date(2026, 10, 2, 12, 0, 0, 0, time.UTC),
+			txs: func(now time.Time) []map[string]interface{} { return spendEvery(now, 121, 20) }, wantPeriods: 3},
+		{name: "steady spending late in the month", now: time.Date(2026, 10, 28, 12, 0, 0, 0, time.UTC),
+			txs: func(now time.Time) []map[string]interface{} { return spendEvery(now, 121, 20) }, wantPeriods: 3},
+		{name: "last 30 days doubled", now: time.Date(2026, 10, 2, 12, 0, 0, 0, time.UTC),
+			txs: func(now time.Time) []map[string]interface{} {
+				return append(spendEvery(now, 121, 20), spendEvery(now, 30, 20)...)
+			}, wantPeriods: 3, wantSpike: true},
+		{name: "only two periods of history before", now: time.Date(2026, 10, 2, 12, 0, 0, 0, time.UTC),
+			txs: func(now time.Time) []map[string]interface{} { return spendEvery(now, 91, 20) }, wantPeriods: 2},
+		{name: "no earlier period", now: time.Date(2026, 10, 2, 12, 0, 0, 0, time.UTC),
+			txs: func(now time.Time) []map[string]interface{} { return spendEvery(now, 30, 20) }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := detectSpendingAnomalies(tt.txs(tt.now), 30, tt.now)
+			if r.BaselinePeriods != tt.wantPeriods {
+				t.Fatalf("baseline periods = %d, want %d (%s)", r.BaselinePeriods, tt.wantPeriods, r.Message)
+			}
+			if r.Spike != tt.wantSpike {
+				t.Errorf("spike = %v, want %v (%s)", r.Spike, tt.wantSpike, r.Message)
+			}
+			if tt.wantPeriods > 0 && !tt.wantSpike && !approxEqual(r.TotalDeltaUSD, 0) {
+				t.Errorf("steady spending changed by %v: %s", r.TotalDeltaUSD, r.Message)
+			}
+		})
+	}
+}
@@ -0,0 +1,148 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+
+	"github.com/becomeliminal/nim-go-sdk/core"
+	"github.com/becomeliminal/nim-go-sdk/tools"
+)
+
+// ============================================
+// ASSET LOCATION
+// ============================================
+// Assets are placed least tax-efficient first, each into its preferred account
+// until that account is full, then spilling into the next account in its list.
+
+// assetLocationAccounts fixes the order accounts are reported in
+var assetLocationAccounts = []string{"taxable", "traditional", "roth"}
+
+// assetPlacement is one asset class and the accounts it belongs in, best first
+type assetPlacement struct {
+	asset    string
+	accounts []string
+	reason   string
+}
+
+// assetPlacements is also the placement order: income-heavy assets claim sheltered space first
+var assetPlacements = []assetPlacement{
+	{"bonds", []string{"traditional", "roth", "taxable"}, "Interest is taxed as ordinary income, so it belongs in tax-deferred space"},
+	{"reits", []string{"traditional", "roth", "taxable"}, "REIT dividends are mostly non-qualified and taxed as ordinary income"},
+	{"growth_equity", []string{"roth", "taxable", "traditional"}, "The highest expected growth benefits most from never being taxed"},
+	{"index_equity", []string{"taxable", "roth", "traditional"}, "Broad index funds are tax-efficient: low turnover and qualified dividends"},
+	{"cash", []string{"taxable", "traditional", "roth"}, "Cash needs to be reachable without penalties"},
+}
+
+// newAssetLocationTool decides which account should hold which asset class
+func newAssetLocationTool() core.Tool {
+	return tools.New("asset_location_advisor").
+		Description("Recommend which account (taxable, traditional, Roth) should hold which asset class for tax efficiency, with the dollar amount of each asset in each account").
+		Schema(tools.ObjectSchema(map[string]interface{}{
+			"taxable_balance":       tools.StringProperty("Taxable brokerage balance in the account currency"),
+			"traditional_balance":   tools.StringProperty("Traditional IRA/401k (tax-deferred) balance in the account currency"),
+			"roth_balance":          tools.StringProperty("Roth IRA/401k balance in the account currency"),
+			"bonds_percent":         tools.StringProperty("Target bond allocation percentage"),
+			"reits_percent":         tools.StringProperty("Optional target REIT/real estate allocation percentage"),
+			"growth_equity_percent": tools.StringProperty("Optional target high-growth equity (small cap, emerging markets, sector) percentage"),
+			"index_equity_percent":  tools.StringProperty("Target broad index equity percentage"),
+			"cash_percent":          tools.StringProperty("Optional target cash percentage"),
+		}, "taxable_balance", "traditional_balance", "roth_balance", "bonds_percent", "index_equity_percent")).
+		HandlerFunc(func(ctx context.Context, input json.RawMessage) (interface{}, error) {
+			var params struct {
+				TaxableBalance      string `json:"taxable_balance"`
+				TraditionalBalance  string `json:"traditional_balance"`
+				RothBalance         string `json:"roth_balance"`
+				BondsPercent        string `json:"bonds_percent"`
+				REITsPercent        string `json:"reits_percent"`
+				GrowthEquityPercent string `json:"growth_equity_percent"`
+				IndexEquityPercent  string `json:"index_equity_percent"`
+				CashPercent         string `json:"cash_percent"`
+			}
+			if err := json.Unmarshal(input, &params); err != nil {
+				return nil, fmt.Errorf("invalid input: %w", err)
+			}
+
+			var v amountValidator
+			balances := map[string]float64{
+				"taxable":     v.nonNegative("taxable_balance", params.TaxableBalance, true),
+				"traditional": v.nonNegative("traditional_balance", params.TraditionalBalance, true),
+				"roth":        v.nonNegative("roth_balance", params.RothBalance, true),
+			}
+			targets := map[string]float64{
+				"bonds":         v.percent("bonds_percent", params.BondsPercent, true, 100),
+				"reits":         v.percent("reits_percent", params.REITsPercent, false, 100),
+				"growth_equity": v.percent("growth_equity_percent", params.GrowthEquityPercent, false, 100),
+				"index_equity":  v.percent("index_equity_percent", params.IndexEquityPercent, true, 100),
+				"cash":          v.percent("cash_percent", params.CashPercent, false, 100),
+			}
+			if err := v.err(); err != nil {
+				return nil, err
+			}
+			sum := 0.0
+			for _, pct := range targets {
+				sum += pct
+			}
+			if math.Abs(sum-100) > 0.5 {
+				return nil, fmt.Errorf("invalid input: target percentages must add up to 100 (got %g)", sum)
+			}
+			if balances["taxable"]+balances["traditional"]+balances["roth"] <= 0 {
+				return nil, fmt.Errorf("invalid input: at least one account balance must be greater than zero")
+			}
+
+			return planAssetLocation(balances, targets), nil
+		}).
+		Build()
+}
+
+// planAssetLocation fills accounts asset by asset in assetPlacements order
+func planAssetLocation(balances, targets map[string]float64) AssetLocationPlan {
+	total := 0.0
+	room := make(map[string]float64, len(balances))
+	for account, balance := range balances {
+		total += balance
+		room[account] = balance
+	}
+
+	plan := AssetLocationPlan{
+		Currency:   activeCurrency().Code,
+		TotalUSD:   total,
+		Accounts:   make(map[string]map[string]float64, len(assetLocationAccounts)),
+		Spillovers: []string{},
+		PriorityOrder: "Assets are placed bonds, REITs, growth equity, index equity, cash. Each fills its first-choice account; " +
+			"when that is full the rest spills to its next choice (bonds/REITs: traditional, Roth, taxable; growth equity: Roth, taxable, traditional; " +
+			"index equity: taxable, Roth, traditional; cash: taxable, traditional, Roth).",
+	}
+	for _, account := range assetLocationAccounts {
+		plan.Accounts[account] = map[string]float64{}
+	}
+
+	for _, p := range assetPlacements {
+		want := targets[p.asset] / 100 * total
+		if want <= 0 {
+			continue
+		}
+		placement := AssetLocation{Asset: p.asset, TargetUSD: want, PreferredAccount: p.accounts[0], Reason: p.reason}
+		remaining := want
+		for i, account := range p.accounts {
+			put := min(remaining, room[account])
+			if put <= 0.005 {
+				continue
+			}
+			plan.Accounts[account][p.asset] += put
+			room[account] -= put
+			remaining -= put
+			placement.Holdings = append(placement.Holdings, AccountHolding{Account: account, AmountUSD: put})
+			if i > 0 {
+				plan.Spillovers = append(plan.Spillovers, fmt.Sprintf("%s of %s goes in %s because %s is full",
+					formatMoney(put), p.asset, account, p.accounts[0]))
+			}
+			if remaining <= 0.005 {
+				break
+			}
+		}
+		plan.Placements = append(plan.Placements, placement)
+	}
+	return plan
+}
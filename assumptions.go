@@ -0,0 +1,142 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"maps"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/becomeliminal/nim-go-sdk/core"
+	"github.com/becomeliminal/nim-go-sdk/tools"
+)
+
+// ============================================
+// ADVISOR ASSUMPTIONS
+// ============================================
+// Every projection reads its default rates from appConfig.Assumptions. They
+// start from the defaults below, are overridden by ASSUMPTIONS_FILE (a flat
+// YAML file of "key: value" lines, optionally nested under "assumptions:") and
+// then by the individual environment variables, so changing one is a config
+// change and a restart.
+
+// Assumptions are the long-run rates tools use when the user doesn't give one, in annual %
+type Assumptions struct {
+	InflationPct    float64 `json:"inflation_percent"`
+	EquityReturnPct float64 `json:"equity_return_percent"`
+	BondReturnPct   float64 `json:"bond_return_percent"`
+	SavingsAPY      float64 `json:"savings_apy_percent"` // used when live vault rates are unavailable
+}
+
+// assumptionKeys maps ASSUMPTIONS_FILE keys and their environment variables onto the fields they set
+var assumptionKeys = []struct {
+	key, env string
+	field    func(*Assumptions) *float64
+}{
+	{"inflation_pct", "INFLATION_PCT", func(a *Assumptions) *float64 { return &a.InflationPct }},
+	{"equity_return_pct", "ASSUMED_RETURN_PCT", func(a *Assumptions) *float64 { return &a.EquityReturnPct }},
+	{"bond_return_pct", "BOND_RETURN_PCT", func(a *Assumptions) *float64 { return &a.BondReturnPct }},
+	{"savings_apy_pct", "DEFAULT_VAULT_APY", func(a *Assumptions) *float64 { return &a.SavingsAPY }},
+}
+
+// loadAssumptions layers defaults, ASSUMPTIONS_FILE and environment variables, in that order
+func loadAssumptions() Assumptions {
+	a := Assumptions{InflationPct: 2.5, EquityReturnPct: 7.0, BondReturnPct: 4.0, SavingsAPY: 4.0}
+	if path := os.Getenv("ASSUMPTIONS_FILE"); path != "" {
+		if err := readAssumptionsFile(path, &a); err != nil {
+			log.Fatalf("invalid ASSUMPTIONS_FILE %s: %v", path, err)
+		}
+	}
+	for _, k := range assumptionKeys {
+		p := k.field(&a)
+		*p = envFloat(k.env, *p)
+	}
+	return a
+}
+
+// readAssumptionsFile applies the "key: value" lines of path to a; unknown keys are logged and skipped
+func readAssumptionsFile(path string, a *Assumptions) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for n := 1; scanner.Scan(); n++ {
+		line, _, _ := strings.Cut(scanner.Text(), "#")
+		key, raw, ok := strings.Cut(strings.TrimSpace(line), ":")
+		if !ok {
+			if strings.TrimSpace(line) != "" {
+				return fmt.Errorf("line %d: expected \"key: value\"", n)
+			}
+			continue
+		}
+		key, raw = strings.TrimSpace(key), strings.Trim(strings.TrimSpace(raw), `"'`)
+		if raw == "" {
+			continue // section header such as "assumptions:"
+		}
+		var field *float64
+		for _, k := range assumptionKeys {
+			if k.key == key {
+				field = k.field(a)
+			}
+		}
+		if field == nil {
+			log.Printf("⚠️  Ignoring unknown assumption %q in %s", key, path)
+			continue
+		}
+		v, err := strconv.ParseFloat(strings.TrimSuffix(raw, "%"), 64)
+		if err != nil {
+			return fmt.Errorf("line %d: %s: %q is not a number", n, key, raw)
+		}
+		*field = v
+	}
+	return scanner.Err()
+}
+
+// expectedPortfolioReturn blends the assumed returns over weights (percent per rebalancer asset class).
+// International and REIT slices earn the equity return; cash earns the savings APY.
+func expectedPortfolioReturn(weights map[string]float64) float64 {
+	a := appConfig.Assumptions
+	rates := map[string]float64{
+		"stocks":        a.EquityReturnPct,
+		"international": a.EquityReturnPct,
+		"reit":          a.EquityReturnPct,
+		"bonds":         a.BondReturnPct,
+		"cash":          a.SavingsAPY,
+	}
+	// Sum in a fixed order: map order would change the last bits, and seeded simulations with them
+	r := 0.0
+	for _, asset := range slices.Sorted(maps.Keys(weights)) {
+		r += weights[asset] / 100 * rates[asset]
+	}
+	return r
+}
+
+// newAssumptionsTool reports the rates behind every default so the model can state them
+func newAssumptionsTool() core.Tool {
+	return tools.New("get_advisor_assumptions").
+		Description("Get the long-run assumptions this advisor uses when the user doesn't supply a rate: inflation, equity return, bond return and savings APY. Use it when the user asks what a projection assumes").
+		Schema(tools.ObjectSchema(map[string]interface{}{})).
+		HandlerFunc(func(ctx context.Context, input json.RawMessage) (interface{}, error) {
+			a := appConfig.Assumptions
+			rows := make(map[string]float64, len(targetAllocationTable))
+			for level, weights := range targetAllocationTable {
+				rows[string(level)] = expectedPortfolioReturn(weights)
+			}
+			return map[string]interface{}{
+				"assumptions":                   a,
+				"expected_return_by_risk_level": rows,
+				"real_equity_return_percent":    a.EquityReturnPct - a.InflationPct,
+				"message": fmt.Sprintf("Unless told otherwise I assume %g%% inflation, %g%% a year from stocks, %g%% from bonds and %g%% on savings when live vault rates aren't available. These are long-run averages, not predictions; any projection tool accepts a different rate.",
+					a.InflationPct, a.EquityReturnPct, a.BondReturnPct, a.SavingsAPY),
+			}, nil
+		}).
+		Build()
+}
@@ -0,0 +1,176 @@
+package main
+
+import (
+	"context"
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"log"
+	"math"
+	"strings"
+
+	"github.com/becomeliminal/nim-go-sdk/core"
+	"github.com/becomeliminal/nim-go-sdk/tools"
+)
+
+// ============================================
+// HISTORICAL BACKTEST
+// ============================================
+// Replays calendar-year returns from data/historical_returns.json. Each year's
+// return is spread evenly across its months, contributions land at the end of
+// each month (the same timing as futureValue) and the portfolio is rebalanced to
+// the chosen allocation every January. Annual data hides drops that recovered
+// within a year, so drawdowns here are year-end to year-end.
+
+//go:embed data/historical_returns.json
+var historicalReturnsJSON []byte
+
+// historicalYear is one calendar year of total returns, in percent
+type historicalYear struct {
+	Year   int     `json:"year"`
+	Stocks float64 `json:"stocks"`
+	Bonds  float64 `json:"bonds"`
+	Cash   float64 `json:"cash"`
+}
+
+// historicalReturns is the bundled dataset, loaded once at startup, in year order
+var historicalReturns = loadHistoricalReturns()
+
+func loadHistoricalReturns() []historicalYear {
+	var data struct {
+		Years []historicalYear `json:"years"`
+	}
+	if err := json.Unmarshal(historicalReturnsJSON, &data); err != nil || len(data.Years) == 0 {
+		log.Fatalf("invalid data/historical_returns.json: %v", err)
+	}
+	return data.Years
+}
+
+// newBacktestTool replays real market history for a monthly investing plan
+func newBacktestTool() core.Tool {
+	first, last := historicalReturns[0].Year, historicalReturns[len(historicalReturns)-1].Year
+	return tools.New("historical_backtest").
+		Description(fmt.Sprintf("Replay actual historical returns (US stocks, US bonds and cash, %d-%d) for a monthly investing plan: what the plan would be worth today, total contributed, worst calendar year and the largest drop along the way. Use it to make projections concrete, e.g. '200 a month since 2010'", first, last)).
+		Schema(tools.ObjectSchema(map[string]interface{}{
+			"start_year":           tools.IntegerProperty(fmt.Sprintf("First year invested, %d-%d", first, last)),
+			"end_year":             tools.IntegerProperty(fmt.Sprintf("Optional last year invested (default %d)", last)),
+			"monthly_contribution": tools.StringProperty("Amount invested each month in the account currency"),
+			"initial_amount":       tools.StringProperty("Optional amount invested at the start in the account currency"),
+			"stock_percent":        tools.StringProperty("Percentage in stocks, e.g. '80'"),
+			"bond_percent":         tools.StringProperty("Optional percentage in bonds (default: the rest after stocks); anything left over is cash"),
+		}, "start_year", "monthly_contribution", "stock_percent")).
+		HandlerFunc(func(ctx context.Context, input json.RawMessage) (interface{}, error) {
+			var params struct {
+				StartYear           int    `json:"start_year"`
+				EndYear             int    `json:"end_year"`
+				MonthlyContribution string `json:"monthly_contribution"`
+				InitialAmount       string `json:"initial_amount"`
+				StockPercent        string `json:"stock_percent"`
+				BondPercent         string `json:"bond_percent"`
+			}
+			if err := json.Unmarshal(input, &params); err != nil {
+				return nil, fmt.Errorf("invalid input: %w", err)
+			}
+
+			var v amountValidator
+			if params.StartYear < first || params.StartYear > last {
+				v.fail("start_year", "%d is outside the historical data; the supported range is %d-%d", params.StartYear, first, last)
+			}
+			endYear := last
+			if params.EndYear != 0 {
+				endYear = params.EndYear
+				if endYear < first || endYear > last {
+					v.fail("end_year", "%d is outside the historical data; the supported range is %d-%d", endYear, first, last)
+				} else if endYear < params.StartYear {
+					v.fail("end_year", "%d is before start_year %d", endYear, params.StartYear)
+				}
+			}
+			monthly := v.nonNegative("monthly_contribution", params.MonthlyContribution, true)
+			initial := v.nonNegative("initial_amount", params.InitialAmount, false)
+			if strings.TrimSpace(params.StockPercent) == "" {
+				v.fail("stock_percent", "is required")
+			}
+			stockPct := optionalPercent(&v, "stock_percent", params.StockPercent, 0, 100)
+			bondPct := optionalPercent(&v, "bond_percent", params.BondPercent, 100-stockPct, 100)
+			if len(v.errs) == 0 && stockPct+bondPct > 100 {
+				v.fail("bond_percent", "stocks (%g%%) and bonds (%g%%) add up to more than 100%%", stockPct, bondPct)
+			}
+			if len(v.errs) == 0 && monthly == 0 && initial == 0 {
+				v.fail("monthly_contribution", "give a monthly contribution or an initial amount")
+			}
+			if err := v.err(); err != nil {
+				return nil, err
+			}
+
+			var years []historicalYear
+			for _, y := range historicalReturns {
+				if y.Year >= params.StartYear && y.Year <= endYear {
+					years = append(years, y)
+				}
+			}
+			return backtest(years, initial, monthly, stockPct, bondPct), nil
+		}).
+		Build()
+}
+
+// backtest replays years in order and tracks the portfolio's worst year and deepest drawdown
+func backtest(years []historicalYear, initial, monthly, stockPct, bondPct float64) BacktestResult {
+	cashPct := math.Max(100-stockPct-bondPct, 0)
+	weights := map[string]float64{"stocks": stockPct, "bonds": bondPct, "cash": cashPct}
+	r := BacktestResult{
+		Currency:            activeCurrency().Code,
+		StartYear:           years[0].Year,
+		EndYear:             years[len(years)-1].Year,
+		InitialAmountUSD:    initial,
+		MonthlyContribution: monthly,
+		StockPercent:        stockPct,
+		BondPercent:         bondPct,
+		CashPercent:         cashPct,
+		DataNote:            "Calendar-year returns: S&P 500 total return, Bloomberg US Aggregate bonds, 3-month Treasury bills. Drops that recovered within a year don't show up.",
+	}
+
+	balance := initial
+	growth := 1.0 // growth of one unit invested, for a return unaffected by contributions
+	for i, y := range years {
+		yearReturn := blendedYearReturn(y, weights)
+		monthlyGrowth := math.Pow(1+yearReturn/100, 1.0/12)
+		start := balance
+		for range 12 {
+			balance = balance*monthlyGrowth + monthly
+		}
+		r.TotalContributedUSD += 12 * monthly
+		market := balance - start - 12*monthly
+
+		r.Years = append(r.Years, BacktestYear{Year: y.Year, ReturnPercent: yearReturn, MarketGainUSD: market, EndValueUSD: balance})
+		if i == 0 || yearReturn < r.WorstYearReturnPercent {
+			r.WorstYear, r.WorstYearReturnPercent, r.WorstYearLossUSD = y.Year, yearReturn, market
+		}
+
+		growth *= 1 + yearReturn/100
+	}
+	for _, e := range drawdownEpisodes(years, weights) {
+		if e.DepthPercent > r.MaxDrawdownPercent {
+			r.MaxDrawdownPercent, r.DrawdownPeakYear, r.DrawdownTroughYear = e.DepthPercent, e.PeakYear, e.TroughYear
+		}
+	}
+	r.TotalContributedUSD += initial
+	r.EndingValueUSD = balance
+	r.GainUSD = balance - r.TotalContributedUSD
+	r.AnnualizedReturnPercent = (math.Pow(growth, 1/float64(len(years))) - 1) * 100
+
+	allocation := fmt.Sprintf("%g%% stocks / %g%% bonds", stockPct, bondPct)
+	if r.CashPercent > 0 {
+		allocation += fmt.Sprintf(" / %g%% cash", math.Round(r.CashPercent*100)/100)
+	}
+	invested := formatMoney(monthly) + "/month"
+	if initial > 0 {
+		invested += " plus " + formatWholeMoney(initial) + " up front"
+	}
+	r.Message = fmt.Sprintf("Investing %s (%s) from %d through %d would have grown %s of contributions to %s. The worst year was %d at %.1f%%",
+		invested, allocation, r.StartYear, r.EndYear, formatWholeMoney(r.TotalContributedUSD), formatWholeMoney(r.EndingValueUSD), r.WorstYear, r.WorstYearReturnPercent)
+	if r.MaxDrawdownPercent > 0 {
+		r.Message += fmt.Sprintf(", and the deepest drop was %.1f%% from the end of %d to the end of %d", r.MaxDrawdownPercent, r.DrawdownPeakYear, r.DrawdownTroughYear)
+	}
+	r.Message += ". Past returns don't guarantee future results."
+	return r
+}
@@ -0,0 +1,189 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/becomeliminal/nim-go-sdk/core"
+	"github.com/becomeliminal/nim-go-sdk/tools"
+)
+
+// ============================================
+// BENCHMARK COMPARISON
+// ============================================
+// A user's reported return is measured against the same allocation held in
+// index funds over the same calendar years. Allocations use the rebalancer's
+// five asset classes; the bundled history only has US stocks, bonds and cash,
+// so international and REIT slices are benchmarked against US stocks.
+
+// benchmarkSeries maps each rebalancer asset class onto a historical return series
+var benchmarkSeries = map[string]func(historicalYear) float64{
+	"stocks":        func(y historicalYear) float64 { return y.Stocks },
+	"international": func(y historicalYear) float64 { return y.Stocks }, // proxy: no international series bundled
+	"reit":          func(y historicalYear) float64 { return y.Stocks }, // proxy: no REIT series bundled
+	"bonds":         func(y historicalYear) float64 { return y.Bonds },
+	"cash":          func(y historicalYear) float64 { return y.Cash },
+}
+
+// benchmarkCautionGapPct is the annualized gap, in points, above which fees and timing are called out
+const benchmarkCautionGapPct = 3.0
+
+// blendedYearReturn is one calendar year's return for weights (percent per asset class) rebalanced in January
+func blendedYearReturn(y historicalYear, weights map[string]float64) float64 {
+	r := 0.0
+	for asset, pct := range weights {
+		r += pct / 100 * benchmarkSeries[asset](y)
+	}
+	return r
+}
+
+// newBenchmarkTool compares a user's reported return with an index benchmark for their allocation
+func newBenchmarkTool() core.Tool {
+	first, last := historicalReturns[0].Year, historicalReturns[len(historicalReturns)-1].Year
+	return tools.New("benchmark_comparison").
+		Description(fmt.Sprintf("Judge a user-reported return (e.g. 'my account grew 11%% last year') against a blended index benchmark with the same allocation over the same calendar years (%d-%d): over/under-performance and, for large gaps, cautions about fees, deposits and timing", first, last)).
+		Schema(tools.ObjectSchema(map[string]interface{}{
+			"reported_return_percent": tools.StringProperty("The user's total return for the period percentage, e.g. '11' for 'my account grew 11%'"),
+			"start_year":              tools.IntegerProperty(fmt.Sprintf("First calendar year of the period, %d-%d", first, last)),
+			"end_year":                tools.IntegerProperty("Optional last calendar year of the period (default: start_year, a single year)"),
+			"stocks_percent":          tools.StringProperty("Optional percentage of the portfolio in US stocks"),
+			"international_percent":   tools.StringProperty("Optional percentage in international stocks"),
+			"reit_percent":            tools.StringProperty("Optional percentage in REITs"),
+			"bonds_percent":           tools.StringProperty("Optional percentage in bonds"),
+			"cash_percent":            tools.StringProperty("Optional percentage in cash"),
+			"risk_level":              tools.StringProperty("Optional risk level whose target allocation to benchmark against when no percentages are given; omit to use the user's profile"),
+		}, "reported_return_percent", "start_year")).
+		Handler(func(ctx context.Context, toolParams *core.ToolParams) (*core.ToolResult, error) {
+			var params struct {
+				ReportedReturnPercent string `json:"reported_return_percent"`
+				StartYear             int    `json:"start_year"`
+				EndYear               int    `json:"end_year"`
+				StocksPercent         string `json:"stocks_percent"`
+				InternationalPercent  string `json:"international_percent"`
+				REITPercent           string `json:"reit_percent"`
+				BondsPercent          string `json:"bonds_percent"`
+				CashPercent           string `json:"cash_percent"`
+				RiskLevel             string `json:"risk_level"`
+			}
+			if err := json.Unmarshal(toolParams.Input, &params); err != nil {
+				return &core.ToolResult{Success: false, Error: fmt.Sprintf("invalid input: %v", err)}, nil
+			}
+
+			var v amountValidator
+			reported, _ := v.parseRate("reported_return_percent", params.ReportedReturnPercent, true)
+			if reported <= -100 {
+				v.fail("reported_return_percent", "must be above -100%% (got %v%%)", reported)
+			}
+			if params.StartYear < first || params.StartYear > last {
+				v.fail("start_year", "%d is outside the historical data; the supported range is %d-%d", params.StartYear, first, last)
+			}
+			endYear := params.StartYear
+			if params.EndYear != 0 {
+				endYear = params.EndYear
+				if endYear < first || endYear > last {
+					v.fail("end_year", "%d is outside the historical data; the supported range is %d-%d", endYear, first, last)
+				} else if endYear < params.StartYear {
+					v.fail("end_year", "%d is before start_year %d", endYear, params.StartYear)
+				}
+			}
+			raw := map[string]string{
+				"stocks":        params.StocksPercent,
+				"international": params.InternationalPercent,
+				"reit":          params.REITPercent,
+				"bonds":         params.BondsPercent,
+				"cash":          params.CashPercent,
+			}
+			weights := map[string]float64{}
+			total := 0.0
+			for _, asset := range rebalanceAssets {
+				if strings.TrimSpace(raw[asset]) == "" {
+					continue
+				}
+				weights[asset] = optionalPercent(&v, asset+"_percent", raw[asset], 0, 100)
+				total += weights[asset]
+			}
+			if len(v.errs) == 0 && len(weights) > 0 && math.Abs(total-100) > 1 {
+				v.fail("allocation", "percentages add up to %g%%, not 100%%", total)
+			}
+			if err := v.err(); err != nil {
+				return &core.ToolResult{Success: false, Error: err.Error()}, nil
+			}
+
+			allocationSource := "user_provided"
+			if len(weights) == 0 {
+				level, source, err := resolveRiskLevel(ctx, toolParams.UserID, "risk_level", params.RiskLevel)
+				if err != nil {
+					return &core.ToolResult{Success: false, Error: err.Error()}, nil
+				}
+				weights, allocationSource = targetAllocationTable[level], source
+			}
+
+			var years []historicalYear
+			for _, y := range historicalReturns {
+				if y.Year >= params.StartYear && y.Year <= endYear {
+					years = append(years, y)
+				}
+			}
+			result := compareToBenchmark(reported, years, weights)
+			result.AllocationSource = allocationSource
+			return &core.ToolResult{Success: true, Data: result}, nil
+		}).
+		Build()
+}
+
+// compareToBenchmark compounds the blended benchmark over years and measures the reported return against it
+func compareToBenchmark(reported float64, years []historicalYear, weights map[string]float64) BenchmarkResult {
+	r := BenchmarkResult{
+		ReportedReturnPercent: reported,
+		StartYear:             years[0].Year,
+		EndYear:               years[len(years)-1].Year,
+		Allocation:            weights,
+		DataNote:              "Benchmark: S&P 500 total return for stocks (also standing in for international and REIT slices), Bloomberg US Aggregate for bonds, 3-month Treasury bills for cash, rebalanced every January.",
+	}
+	growth := 1.0
+	for _, y := range years {
+		yr := blendedYearReturn(y, weights)
+		r.BenchmarkYears = append(r.BenchmarkYears, BacktestYear{Year: y.Year, ReturnPercent: yr})
+		growth *= 1 + yr/100
+	}
+	n := float64(len(years))
+	r.BenchmarkReturnPercent = (growth - 1) * 100
+	r.DifferencePercent = reported - r.BenchmarkReturnPercent
+	r.ReportedAnnualizedPercent = (math.Pow(1+reported/100, 1/n) - 1) * 100
+	r.BenchmarkAnnualizedPercent = (math.Pow(growth, 1/n) - 1) * 100
+	r.AnnualizedGapPercent = r.ReportedAnnualizedPercent - r.BenchmarkAnnualizedPercent
+
+	period := fmt.Sprintf("in %d", r.StartYear)
+	if r.EndYear != r.StartYear {
+		period = fmt.Sprintf("from %d through %d", r.StartYear, r.EndYear)
+	}
+	switch {
+	case math.Abs(r.AnnualizedGapPercent) < 0.5:
+		r.Verdict = "in_line"
+		r.Message = fmt.Sprintf("Your %.1f%% %s is in line with the %.1f%% a matching index portfolio returned.", reported, period, r.BenchmarkReturnPercent)
+	case r.AnnualizedGapPercent > 0:
+		r.Verdict = "outperformed"
+		r.Message = fmt.Sprintf("Your %.1f%% %s beat the %.1f%% a matching index portfolio returned by %.1f points.", reported, period, r.BenchmarkReturnPercent, r.DifferencePercent)
+	default:
+		r.Verdict = "underperformed"
+		r.Message = fmt.Sprintf("Your %.1f%% %s trailed the %.1f%% a matching index portfolio returned by %.1f points.", reported, period, r.BenchmarkReturnPercent, -r.DifferencePercent)
+	}
+
+	if math.Abs(r.AnnualizedGapPercent) >= benchmarkCautionGapPct {
+		r.Cautions = []string{
+			"If 'grew' means the balance went up, deposits count toward it; the return is only the growth on money already invested",
+			"Money added mid-year spends less time invested, so its return won't match a full calendar year",
+			"Fund expense ratios and advisory fees come straight out of returns; a 1% fee costs about 1 point a year",
+			"A different mix than the benchmark allocation (e.g. single stocks, sector funds or more international) will naturally diverge",
+		}
+		if r.AnnualizedGapPercent < 0 {
+			r.Cautions = append(r.Cautions, "A persistent shortfall is worth checking against fees with fee_drag_calculator")
+		} else {
+			r.Cautions = append(r.Cautions, "Beating the benchmark by this much usually means more risk than the stated allocation; one good year says little about skill")
+		}
+	}
+	return r
+}
@@ -0,0 +1,250 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/becomeliminal/nim-go-sdk/core"
+	"github.com/becomeliminal/nim-go-sdk/tools"
+
+	"vibe-invest/storage"
+)
+
+// ============================================
+// CASH FLOW FORECAST
+// ============================================
+// Projects the wallet balance day by day from today's get_balance figure.
+// Detected recurring income arrives monthly on the day it was last received,
+// detected recurring bills on their cadence, investment plans on their
+// investment day, and everyday spending (spending minus those bills) is taken
+// off evenly each day. The lowest point of the projection is what a new
+// monthly commitment is checked against CASH_SAFETY_BUFFER.
+
+// Cash flow forecast bounds
+const (
+	maxForecastDays     = 90
+	forecastPeriodDays  = 30 // the forecast reports its lowest point per period of this many days
+	forecastHistoryDays = 90 // spending history averaged into everyday spend
+)
+
+// proposedPlanLabel names the amount being considered in forecast events
+const proposedPlanLabel = "proposed investment"
+
+// newCashFlowForecastTool projects the user's wallet balance over the next days
+func newCashFlowForecastTool(liminalExecutor core.ToolExecutor) core.Tool {
+	return tools.New("forecast_cash_flow").
+		Description("Project the user's wallet balance over the next 30, 60 or 90 days from recurring income, recurring bills and subscriptions, average everyday spending and their investment plans. Returns the projected minimum balance and when it occurs, per 30 days and overall. Pass monthly_amount to see the effect of a new monthly investment before committing to it").
+		Schema(tools.ObjectSchema(map[string]interface{}{
+			"days":           tools.StringProperty(fmt.Sprintf("Optional number of days to forecast, up to %d (default %d)", maxForecastDays, maxForecastDays)),
+			"monthly_amount": tools.StringProperty("Optional new monthly investment to include in the forecast, in the account currency"),
+			"start_date":     tools.StringProperty("Optional first investment date of the new monthly amount (YYYY-MM-DD, default today)"),
+			"safety_buffer":  tools.StringProperty(fmt.Sprintf("Optional balance the projection should stay above, in the account currency (default %g)", appConfig.CashBuffer)),
+		})).
+		Handler(func(ctx context.Context, toolParams *core.ToolParams) (*core.ToolResult, error) {
+			var params struct {
+				Days          string `json:"days"`
+				MonthlyAmount string `json:"monthly_amount"`
+				StartDate     string `json:"start_date"`
+				SafetyBuffer  string `json:"safety_buffer"`
+			}
+			if len(toolParams.Input) > 0 {
+				if err := json.Unmarshal(toolParams.Input, &params); err != nil {
+					return &core.ToolResult{Success: false, Error: fmt.Sprintf("invalid input: %v", err)}, nil
+				}
+			}
+			var v amountValidator
+			days := float64(maxForecastDays)
+			if strings.TrimSpace(params.Days) != "" {
+				days = v.positive("days", params.Days)
+				if days > maxForecastDays {
+					v.fail("days", "must be at most %d (got %g)", maxForecastDays, days)
+				}
+			}
+			monthly := v.nonNegative("monthly_amount", params.MonthlyAmount, false)
+			buffer := appConfig.CashBuffer
+			if strings.TrimSpace(params.SafetyBuffer) != "" {
+				buffer = v.nonNegative("safety_buffer", params.SafetyBuffer, false)
+			}
+			now := time.Now().UTC()
+			start := now
+			if strings.TrimSpace(params.StartDate) != "" {
+				parsed, err := time.Parse("2006-01-02", params.StartDate)
+				if err != nil {
+					v.fail("start_date", "%q is not a date: use YYYY-MM-DD", params.StartDate)
+				}
+				start = parsed
+			}
+			if err := v.err(); err != nil {
+				return &core.ToolResult{Success: false, Error: err.Error()}, nil
+			}
+
+			forecast, err := loadCashFlowForecast(ctx, liminalExecutor, toolParams.UserID, monthly, start, int(math.Ceil(days)), buffer, now)
+			if err != nil {
+				return &core.ToolResult{Success: false, Error: err.Error()}, nil
+			}
+			return &core.ToolResult{Success: true, Data: forecast}, nil
+		}).
+		Build()
+}
+
+// loadCashFlowForecast reads the user's balance, transactions and plans and forecasts them, with
+// monthly (when positive) added as a new plan investing from start
+func loadCashFlowForecast(ctx context.Context, liminalExecutor core.ToolExecutor, userID string, monthly float64, start time.Time, days int, buffer float64, now time.Time) (CashFlowForecast, error) {
+	balance, ok := fetchBalance(ctx, liminalExecutor, userID, "get_balance")
+	if !ok {
+		return CashFlowForecast{}, errors.New("could not load wallet balance")
+	}
+	txs, ok := fetchTransactions(ctx, liminalExecutor, userID, spendingPageSize)
+	if !ok {
+		return CashFlowForecast{}, errors.New("could not load transaction history")
+	}
+	plans, err := listPlans(ctx, userKey(userID))
+	if err != nil {
+		return CashFlowForecast{}, fmt.Errorf("could not load plans: %v", err)
+	}
+	if monthly > 0 {
+		plans = append(plans, storage.Plan{
+			MonthlyAmount: monthly,
+			StartDate:     start.Format("2006-01-02"),
+			Status:        storage.PlanActive,
+		})
+	}
+	f := forecastCashFlow(balance, txs, plans, days, buffer, now)
+	f.ProposedMonthlyUSD = monthly
+	return f, nil
+}
+
+// forecastCashFlow projects balance over the days after now. A plan without an ID is the proposed one.
+func forecastCashFlow(balance float64, txs []map[string]interface{}, plans []storage.Plan, days int, buffer float64, now time.Time) CashFlowForecast {
+	today := startOfDay(now)
+	end := today.AddDate(0, 0, days)
+	f := CashFlowForecast{
+		Currency:           activeCurrency().Code,
+		Days:               days,
+		StartingBalanceUSD: balance,
+		SafetyBufferUSD:    buffer,
+		Periods:            []CashFlowPeriod{},
+		Events:             []CashFlowEvent{},
+	}
+	inWindow := func(at time.Time) bool { return at.After(today) && !at.After(end) }
+
+	income := detectIncome(txs, now)
+	f.IncomeDetected = income.Detected
+	f.MonthlyIncomeUSD = income.MonthlyIncomeUSD
+	for _, s := range income.Sources {
+		last, err := time.Parse("2006-01-02", s.LastReceived)
+		if err != nil {
+			continue
+		}
+		for k := 1; ; k++ {
+			at := last.AddDate(0, k, 0)
+			if at.After(end) {
+				break
+			}
+			if inWindow(at) {
+				f.Events = append(f.Events, CashFlowEvent{Date: at.Format("2006-01-02"), Description: "income: " + s.Sender, AmountUSD: s.MonthlyAverageUSD})
+			}
+		}
+	}
+
+	// Every recurring charge counts here, however large, since rent leaves the wallet like any bill
+	bills := detectSubscriptions(txs, 0, math.Inf(1), now)
+	f.MonthlyBillsUSD = bills.MonthlyTotalUSD
+	for _, s := range bills.Subscriptions {
+		last, err := time.Parse("2006-01-02", s.LastCharged)
+		if err != nil {
+			continue
+		}
+		every := time.Duration(cadenceDays(s.Cadence) * 24 * float64(time.Hour))
+		for at := last.Add(every); !at.After(end); at = at.Add(every) {
+			if inWindow(at) {
+				f.Events = append(f.Events, CashFlowEvent{Date: at.Format("2006-01-02"), Description: "bill: " + s.Counterparty, AmountUSD: -s.AmountUSD})
+			}
+		}
+	}
+
+	for _, p := range plans {
+		start, err := time.Parse("2006-01-02", p.StartDate)
+		if err != nil {
+			continue
+		}
+		// Existing plans due today may already have run; the proposed one hasn't
+		label, due := "plan "+p.ID, inWindow
+		if p.ID == "" {
+			label = proposedPlanLabel
+			due = func(at time.Time) bool { return !at.Before(today) && !at.After(end) }
+		}
+		for m := 0; m <= days/28+1; m++ {
+			month := time.Date(today.Year(), today.Month()+time.Month(m), 1, 0, 0, 0, 0, time.UTC)
+			lastDay := month.AddDate(0, 1, -1).Day()
+			at := month.AddDate(0, 0, min(start.Day(), lastDay)-1)
+			if due(at) && !at.Before(start) && planActiveOn(p, at) {
+				f.Events = append(f.Events, CashFlowEvent{Date: at.Format("2006-01-02"), Description: label, AmountUSD: -p.MonthlyAmount})
+				f.InvestmentsUSD += p.MonthlyAmount
+			}
+		}
+	}
+	slices.SortStableFunc(f.Events, func(a, b CashFlowEvent) int { return strings.Compare(a.Date, b.Date) })
+
+	// Everyday spending is what's left of recent spending once the recurring bills are taken out
+	oldest := now
+	for _, tx := range txs {
+		if at, ok := transactionTime(tx); ok {
+			oldest = minTime(oldest, at)
+		}
+	}
+	spending := analyzeSpending(txs, min(transactionWindowDays(oldest, now), forecastHistoryDays), now)
+	f.DailySpendingUSD = max(spending.MonthlySpendingUSD-f.MonthlyBillsUSD, 0) / 30
+
+	f.MinimumBalanceUSD, f.MinimumDate = balance, today.Format("2006-01-02")
+	period := CashFlowPeriod{MinimumBalanceUSD: balance, MinimumDate: f.MinimumDate}
+	next := 0
+	for d := 0; d <= days; d++ {
+		date := today.AddDate(0, 0, d).Format("2006-01-02")
+		if d > 0 {
+			balance -= f.DailySpendingUSD
+		}
+		for ; next < len(f.Events) && f.Events[next].Date == date; next++ {
+			balance += f.Events[next].AmountUSD
+		}
+		if balance < period.MinimumBalanceUSD {
+			period.MinimumBalanceUSD, period.MinimumDate = balance, date
+		}
+		if balance < f.MinimumBalanceUSD {
+			f.MinimumBalanceUSD, f.MinimumDate = balance, date
+		}
+		if d > 0 && (d%forecastPeriodDays == 0 || d == days) {
+			period.Days, period.EndDate, period.EndingBalanceUSD = d, date, balance
+			f.Periods = append(f.Periods, period)
+			period = CashFlowPeriod{MinimumBalanceUSD: balance, MinimumDate: date}
+		}
+	}
+	f.EndingBalanceUSD = balance
+	f.BelowSafetyBuffer = f.MinimumBalanceUSD < buffer
+
+	f.Message = fmt.Sprintf("Starting from %s, the balance is projected to be lowest at %s on %s over the next %d days, ending at %s.",
+		formatMoney(f.StartingBalanceUSD), formatMoney(f.MinimumBalanceUSD), f.MinimumDate, days, formatMoney(f.EndingBalanceUSD))
+	if !f.IncomeDetected {
+		f.Message += " No recurring income was detected, so none is assumed."
+	}
+	if f.BelowSafetyBuffer {
+		f.Message += fmt.Sprintf(" That's below the %s safety buffer.", formatMoney(buffer))
+	}
+	return f
+}
+
+// cadenceDays is the interval of a subscriptionCadences entry by name, monthly when unknown
+func cadenceDays(name string) float64 {
+	for _, c := range subscriptionCadences {
+		if c.name == name {
+			return c.days
+		}
+	}
+	return 30.4
+}
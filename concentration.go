@@ -0,0 +1,190 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/becomeliminal/nim-go-sdk/core"
+	"github.com/becomeliminal/nim-go-sdk/tools"
+)
+
+// ============================================
+// SECTOR CONCENTRATION
+// ============================================
+// Flags single holdings above POSITION_CAP_PCT and sectors above SECTOR_CAP_PCT.
+// Broad index funds are already diversified, so they are exempt from both checks.
+// Tickers we don't know are reported back for the model to classify rather than
+// failing the whole call.
+
+// Sector labels with special handling
+const (
+	sectorBroadMarket  = "broad_market" // diversified index funds
+	sectorUnclassified = "unclassified" // unknown ticker with no sector given
+)
+
+// tickerSectors maps well-known tickers to a sector label
+var tickerSectors = map[string]string{
+	// Broad index funds
+	"VTI": sectorBroadMarket, "VOO": sectorBroadMarket, "SPY": sectorBroadMarket, "IVV": sectorBroadMarket,
+	"VT": sectorBroadMarket, "VXUS": sectorBroadMarket, "ITOT": sectorBroadMarket, "SCHB": sectorBroadMarket,
+	"BND": sectorBroadMarket, "AGG": sectorBroadMarket, "QQQ": "technology",
+	// Single stocks
+	"AAPL": "technology", "MSFT": "technology", "NVDA": "technology", "AVGO": "technology", "ORCL": "technology",
+	"AMD": "technology", "CRM": "technology", "ADBE": "technology", "INTC": "technology",
+	"GOOGL": "communication_services", "GOOG": "communication_services", "META": "communication_services",
+	"NFLX": "communication_services", "DIS": "communication_services",
+	"AMZN": "consumer_discretionary", "TSLA": "consumer_discretionary", "HD": "consumer_discretionary",
+	"NKE": "consumer_discretionary", "MCD": "consumer_discretionary",
+	"JPM": "financials", "BAC": "financials", "V": "financials", "MA": "financials", "BRK.B": "financials",
+	"GS":  "financials",
+	"JNJ": "health_care", "UNH": "health_care", "LLY": "health_care", "PFE": "health_care", "MRK": "health_care",
+	"XOM": "energy", "CVX": "energy",
+	"PG": "consumer_staples", "KO": "consumer_staples", "PEP": "consumer_staples", "WMT": "consumer_staples",
+	"COST": "consumer_staples",
+	"BA":   "industrials", "CAT": "industrials", "GE": "industrials",
+	"NEE": "utilities", "DUK": "utilities",
+	"VNQ": "real_estate", "O": "real_estate", "PLD": "real_estate",
+	"BTC": "crypto", "ETH": "crypto",
+}
+
+// concentrationHolding is one validated holding
+type concentrationHolding struct {
+	Ticker string
+	Sector string
+	Value  float64
+}
+
+// newConcentrationTool checks a list of holdings for position and sector concentration
+func newConcentrationTool() core.Tool {
+	return tools.New("sector_concentration_checker").
+		Description("Check a portfolio's diversification: exposure by sector, single holdings above the position limit (default 10%) and sectors above the sector limit (default 30%), with the dollars to redirect to get back under each limit").
+		Schema(tools.ObjectSchema(map[string]interface{}{
+			"holdings": map[string]interface{}{
+				"type":        "array",
+				"description": "The user's holdings; omit to use the holdings recorded with add_holding",
+				"items": tools.ObjectSchema(map[string]interface{}{
+					"ticker": tools.StringProperty("Ticker symbol, e.g. 'AAPL'; optional if sector is given"),
+					"sector": tools.StringProperty("Optional sector label, e.g. 'technology'; required for tickers the tool doesn't know"),
+					"value":  tools.StringProperty("Current value of the holding in the account currency"),
+				}, "value"),
+			},
+			"position_limit_percent": tools.StringProperty(fmt.Sprintf("Optional largest share any one holding should be (default %g)", appConfig.PositionCapPct)),
+			"sector_limit_percent":   tools.StringProperty(fmt.Sprintf("Optional largest share any one sector should be (default %g)", appConfig.SectorCapPct)),
+		})).
+		Handler(func(ctx context.Context, toolParams *core.ToolParams) (*core.ToolResult, error) {
+			var params struct {
+				Holdings []struct {
+					Ticker string `json:"ticker"`
+					Sector string `json:"sector"`
+					Value  string `json:"value"`
+				} `json:"holdings"`
+				PositionLimitPercent string `json:"position_limit_percent"`
+				SectorLimitPercent   string `json:"sector_limit_percent"`
+			}
+			if err := json.Unmarshal(toolParams.Input, &params); err != nil {
+				return &core.ToolResult{Success: false, Error: fmt.Sprintf("invalid input: %v", err)}, nil
+			}
+
+			var v amountValidator
+			var recorded []concentrationHolding
+			if len(params.Holdings) == 0 {
+				holdings, err := loadHoldings(ctx, toolParams.UserID)
+				if err != nil {
+					return &core.ToolResult{Success: false, Error: fmt.Sprintf("could not load holdings: %v", err)}, nil
+				}
+				if recorded = concentrationHoldings(holdings); len(recorded) == 0 {
+					v.fail("holdings", "at least one holding is required (none are recorded with add_holding)")
+				}
+			}
+			holdings := make([]concentrationHolding, 0, len(params.Holdings))
+			for i, h := range params.Holdings {
+				field := fmt.Sprintf("holdings[%d]", i)
+				ticker := strings.ToUpper(strings.TrimSpace(h.Ticker))
+				sector := strings.ToLower(strings.Join(strings.Fields(h.Sector), "_"))
+				if ticker == "" && sector == "" {
+					v.fail(field, "give a ticker or a sector")
+				}
+				holdings = append(holdings, concentrationHolding{Ticker: ticker, Sector: sector, Value: v.positive(field+".value", h.Value)})
+			}
+			positionLimit := optionalPercent(&v, "position_limit_percent", params.PositionLimitPercent, appConfig.PositionCapPct, 100)
+			sectorLimit := optionalPercent(&v, "sector_limit_percent", params.SectorLimitPercent, appConfig.SectorCapPct, 100)
+			if err := v.err(); err != nil {
+				return &core.ToolResult{Success: false, Error: err.Error()}, nil
+			}
+
+			return &core.ToolResult{Success: true, Data: checkConcentration(append(holdings, recorded...), positionLimit, sectorLimit)}, nil
+		}).
+		Build()
+}
+
+// checkConcentration groups holdings by sector and measures each against its limit.
+// Excess is what to move into other holdings, keeping the portfolio total unchanged.
+func checkConcentration(holdings []concentrationHolding, positionLimit, sectorLimit float64) ConcentrationResult {
+	r := ConcentrationResult{
+		Currency:             activeCurrency().Code,
+		PositionLimitPercent: positionLimit,
+		SectorLimitPercent:   sectorLimit,
+		Positions:            []PositionExposure{},
+		Sectors:              []SectorExposure{},
+		Suggestions:          []string{},
+	}
+	for _, h := range holdings {
+		r.TotalValueUSD += h.Value
+	}
+
+	bySector := map[string]float64{}
+	for _, h := range holdings {
+		sector := h.Sector
+		if sector == "" {
+			sector = tickerSectors[h.Ticker]
+		}
+		if sector == "" {
+			sector = sectorUnclassified
+			r.NeedsSector = append(r.NeedsSector, h.Ticker)
+		}
+		bySector[sector] += h.Value
+		if h.Ticker == "" {
+			continue // a sector-level entry isn't a single position
+		}
+
+		p := PositionExposure{Ticker: h.Ticker, Sector: sector, ValueUSD: h.Value, Percent: h.Value / r.TotalValueUSD * 100}
+		if sector != sectorBroadMarket && p.Percent > positionLimit {
+			p.OverLimit = true
+			p.ExcessUSD = h.Value - positionLimit/100*r.TotalValueUSD
+			r.Suggestions = append(r.Suggestions, fmt.Sprintf("Redirect %s from %s (%.1f%%) to bring it to %g%% of the portfolio",
+				formatMoney(p.ExcessUSD), h.Ticker, p.Percent, positionLimit))
+		}
+		r.Positions = append(r.Positions, p)
+	}
+	sort.SliceStable(r.Positions, func(i, j int) bool { return r.Positions[i].ValueUSD > r.Positions[j].ValueUSD })
+
+	for sector, value := range bySector {
+		s := SectorExposure{Sector: sector, ValueUSD: value, Percent: value / r.TotalValueUSD * 100}
+		if sector != sectorBroadMarket && sector != sectorUnclassified && s.Percent > sectorLimit {
+			s.OverLimit = true
+			s.ExcessUSD = value - sectorLimit/100*r.TotalValueUSD
+		}
+		r.Sectors = append(r.Sectors, s)
+	}
+	sort.Slice(r.Sectors, func(i, j int) bool { return r.Sectors[i].ValueUSD > r.Sectors[j].ValueUSD })
+	for _, s := range r.Sectors {
+		if s.OverLimit {
+			r.Suggestions = append(r.Suggestions, fmt.Sprintf("Move %s out of %s (%.1f%%) into other sectors or a broad index fund to get under %g%%",
+				formatMoney(s.ExcessUSD), s.Sector, s.Percent, sectorLimit))
+		}
+	}
+
+	if len(r.Suggestions) == 0 {
+		r.Message = fmt.Sprintf("No holding above %g%% and no sector above %g%%.", positionLimit, sectorLimit)
+	} else {
+		r.Message = fmt.Sprintf("%d concentration issue(s) found; see suggestions.", len(r.Suggestions))
+	}
+	if len(r.NeedsSector) > 0 {
+		r.Message += fmt.Sprintf(" Sector unknown for %s: call again with a sector for each (e.g. 'technology', 'health_care') for a complete check; until then they count as unclassified and aren't flagged by sector.",
+			strings.Join(r.NeedsSector, ", "))
+	}
+	return r
+}
@@ -0,0 +1,318 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/becomeliminal/nim-go-sdk/core"
+	"github.com/becomeliminal/nim-go-sdk/tools"
+
+	"vibe-invest/storage"
+)
+
+// ============================================
+// CONCEPT QUIZ
+// ============================================
+// Each concept can carry multiple-choice questions in the concepts file.
+// investment_knowledge_quiz grades an answer and serves the next question:
+// ones last answered wrong come back first, then ones never asked, then the
+// longest since practiced. Every attempt is kept; a question's latest answer
+// decides whether its concept is mastered or needs review.
+
+// Concept quiz statuses, in the order get_quiz_progress reports them
+const (
+	quizMastered    = "mastered"
+	quizNeedsReview = "needs_review"
+	quizInProgress  = "in_progress"
+	quizNotStarted  = "not_started"
+)
+
+// quizLabel is the letter a choice is shown with: A, B, C...
+func quizLabel(i int) string {
+	return string(rune('A' + i))
+}
+
+// quizChoices labels q's choices for display
+func quizChoices(q quizQuestion) []string {
+	choices := make([]string, len(q.Choices))
+	for i, c := range q.Choices {
+		choices[i] = quizLabel(i) + ". " + c
+	}
+	return choices
+}
+
+// parseQuizAnswer reads an answer to q as a letter ("b"), a choice number ("2") or the choice's text
+func parseQuizAnswer(q quizQuestion, raw string) (int, bool) {
+	answer := strings.TrimSuffix(strings.TrimSpace(raw), ".")
+	for i, c := range q.Choices {
+		if strings.EqualFold(answer, quizLabel(i)) || strings.EqualFold(answer, strings.TrimSpace(c)) {
+			return i, true
+		}
+	}
+	if n, err := strconv.Atoi(answer); err == nil && n >= 1 && n <= len(q.Choices) {
+		return n - 1, true
+	}
+	return 0, false
+}
+
+// findQuizQuestion looks a question up by ID across every concept, with its concept in locale
+func findQuizQuestion(set conceptSet, id, locale string) (conceptRecord, quizQuestion, bool) {
+	id = normalizeConceptKey(id)
+	c, ok := set.concepts[set.quiz[id]]
+	if !ok {
+		return conceptRecord{}, quizQuestion{}, false
+	}
+	c = localizedConcept(c, locale)
+	for _, q := range c.Quiz {
+		if q.ID == id {
+			return c, q, true
+		}
+	}
+	return conceptRecord{}, quizQuestion{}, false
+}
+
+// latestQuizAnswers is each question's most recent answer, by question ID
+func latestQuizAnswers(answers []storage.QuizAnswer) map[string]storage.QuizAnswer {
+	latest := make(map[string]storage.QuizAnswer, len(answers))
+	for _, a := range answers { // oldest first, so later answers win
+		latest[a.QuestionID] = a
+	}
+	return latest
+}
+
+// quizScore counts every attempt and the right ones, with the percentage right
+func quizScore(answers []storage.QuizAnswer) (answered, correct int, pct float64) {
+	for _, a := range answers {
+		if a.Correct {
+			correct++
+		}
+	}
+	if len(answers) > 0 {
+		pct = math.Round(float64(correct)/float64(len(answers))*1000) / 10
+	}
+	return len(answers), correct, pct
+}
+
+// nextQuizQuestion picks the next question, in locale, from concept only when it's set: the first
+// one last answered wrong, else the first never answered, else the one answered longest ago. skip
+// (the question just answered) is passed over while anything else is left.
+func nextQuizQuestion(set conceptSet, latest map[string]storage.QuizAnswer, concept, skip, locale string) *QuizQuestion {
+	var review, fresh, practice *QuizQuestion
+	var practicedAt time.Time
+	for _, key := range set.order {
+		if concept != "" && key != concept {
+			continue
+		}
+		for _, q := range localizedConcept(set.concepts[key], locale).Quiz {
+			if q.ID == skip {
+				continue
+			}
+			a, answered := latest[q.ID]
+			qq := &QuizQuestion{QuestionID: q.ID, Concept: key, Question: q.Question, Choices: quizChoices(q)}
+			switch {
+			case answered && !a.Correct:
+				if review == nil {
+					review, qq.Reason = qq, "review"
+				}
+			case !answered:
+				if fresh == nil {
+					fresh, qq.Reason = qq, "new"
+				}
+			case practice == nil || a.AnsweredAt.Before(practicedAt):
+				practice, practicedAt, qq.Reason = qq, a.AnsweredAt, "practice"
+			}
+		}
+	}
+	for _, q := range []*QuizQuestion{review, fresh, practice} {
+		if q != nil {
+			return q
+		}
+	}
+	if skip != "" {
+		return nextQuizQuestion(set, latest, concept, "", locale)
+	}
+	return nil
+}
+
+// newConceptQuizTool quizzes the user on the concepts they are learning
+func newConceptQuizTool() core.Tool {
+	return tools.New("investment_knowledge_quiz").
+		Description("Quiz the user on investment concepts with multiple-choice questions. Call without question_id to get a question; show it with its lettered choices, then call again with question_id and the user's answer to grade it - the reply gives feedback (with the concept explained again after a wrong answer) and the next question. Pass concept to stay on one topic").
+		Schema(tools.ObjectSchema(map[string]interface{}{
+			"concept":     tools.StringProperty("Optional concept to quiz on, by name or alias (list_investment_concepts lists them)"),
+			"question_id": tools.StringProperty("ID of the question being answered, from the previous next_question"),
+			"answer":      tools.StringProperty("The user's answer: a choice letter (A, B, ...) or the choice's text. Required with question_id"),
+		})).
+		Handler(func(ctx context.Context, toolParams *core.ToolParams) (*core.ToolResult, error) {
+			var params struct {
+				Concept    string `json:"concept"`
+				QuestionID string `json:"question_id"`
+				Answer     string `json:"answer"`
+			}
+			if len(toolParams.Input) > 0 {
+				if err := json.Unmarshal(toolParams.Input, &params); err != nil {
+					return &core.ToolResult{Success: false, Error: fmt.Sprintf("invalid input: %v", err)}, nil
+				}
+			}
+			set := concepts.current()
+			locale := userLocale(ctx, toolParams.UserID)
+
+			v := amountValidator{locale: locale}
+			concept := ""
+			if strings.TrimSpace(params.Concept) != "" {
+				concept = normalizeConceptKey(params.Concept)
+				if alias, ok := set.aliases[concept]; ok {
+					concept = alias
+				}
+				if c, ok := set.concepts[concept]; !ok {
+					v.fail("concept", "%q is not a known concept; list_investment_concepts lists them", params.Concept)
+				} else if len(c.Quiz) == 0 {
+					v.fail("concept", "%s has no quiz questions yet", concept)
+				}
+			}
+			c, q, found := findQuizQuestion(set, params.QuestionID, locale)
+			choice := 0
+			if params.QuestionID != "" {
+				switch {
+				case !found:
+					v.fail("question_id", "%q is not a quiz question; call without question_id for a new one", params.QuestionID)
+				case strings.TrimSpace(params.Answer) == "":
+					v.fail("answer", "is required with question_id")
+				default:
+					var ok bool
+					if choice, ok = parseQuizAnswer(q, params.Answer); !ok {
+						v.fail("answer", "%q must be a choice letter from A to %s, or the choice's text", params.Answer, quizLabel(len(q.Choices)-1))
+					}
+				}
+			}
+			if err := v.err(); err != nil {
+				return &core.ToolResult{Success: false, Error: err.Error()}, nil
+			}
+
+			var r QuizResult
+			if found {
+				answer := storage.QuizAnswer{
+					UserID:     userKey(toolParams.UserID),
+					Concept:    c.Key,
+					QuestionID: q.ID,
+					Choice:     choice,
+					Correct:    choice == q.Answer,
+					AnsweredAt: time.Now().UTC(),
+				}
+				if err := store.SaveQuizAnswer(ctx, answer); err != nil {
+					return &core.ToolResult{Success: false, Error: fmt.Sprintf("could not save answer: %v", err)}, nil
+				}
+				r.Feedback = &QuizFeedback{
+					QuestionID:    q.ID,
+					Concept:       c.Key,
+					Correct:       answer.Correct,
+					YourAnswer:    quizChoices(q)[choice],
+					CorrectAnswer: quizChoices(q)[q.Answer],
+					Why:           q.Explanation,
+				}
+				if !answer.Correct {
+					level, _ := experienceLevel(ctx, toolParams.UserID)
+					text, used, _ := conceptAtLevel(c, level)
+					r.Feedback.Level, r.Feedback.Explanation, r.Feedback.KeyPoints = used, text.Explanation, text.KeyPoints
+				}
+			}
+
+			answers, err := store.ListQuizAnswers(ctx, userKey(toolParams.UserID))
+			if err != nil {
+				return &core.ToolResult{Success: false, Error: fmt.Sprintf("could not load quiz answers: %v", err)}, nil
+			}
+			r.Answered, r.Correct, r.ScorePercent = quizScore(answers)
+			r.Next = nextQuizQuestion(set, latestQuizAnswers(answers), concept, q.ID, locale)
+
+			switch {
+			case r.Feedback != nil && r.Feedback.Correct:
+				r.Message = "Correct! "
+			case r.Feedback != nil:
+				r.Message = fmt.Sprintf("Not quite - the answer is %s. Go over the explanation with the user before the next question. ", r.Feedback.CorrectAnswer)
+			}
+			if r.Next == nil {
+				r.Message += "There are no quiz questions yet."
+			} else {
+				r.Message += fmt.Sprintf("Ask the next question with its choices, then call again with question_id %q and the user's answer.", r.Next.QuestionID)
+			}
+			return &core.ToolResult{Success: true, Data: r}, nil
+		}).
+		Build()
+}
+
+// newQuizProgressTool reports which concepts the user has mastered and which need review
+func newQuizProgressTool() core.Tool {
+	return tools.New("get_quiz_progress").
+		Description("Show the user's investment_knowledge_quiz results: overall score and which concepts are mastered, need review, are in progress or not started. Use it to decide what to explain or quiz next").
+		Schema(tools.ObjectSchema(map[string]interface{}{})).
+		Handler(func(ctx context.Context, toolParams *core.ToolParams) (*core.ToolResult, error) {
+			answers, err := store.ListQuizAnswers(ctx, userKey(toolParams.UserID))
+			if err != nil {
+				return &core.ToolResult{Success: false, Error: fmt.Sprintf("could not load quiz answers: %v", err)}, nil
+			}
+			return &core.ToolResult{Success: true, Data: quizProgress(concepts.current(), answers)}, nil
+		}).
+		Build()
+}
+
+// quizProgress rates each concept with quiz questions by its questions' latest answers: mastered
+// when all are right, needs_review when any is wrong, in_progress while some are unanswered
+func quizProgress(set conceptSet, answers []storage.QuizAnswer) QuizProgressResult {
+	r := QuizProgressResult{
+		Mastered:    []string{},
+		NeedsReview: []string{},
+		InProgress:  []string{},
+		NotStarted:  []string{},
+		Concepts:    []ConceptQuizProgress{},
+	}
+	r.Answered, r.Correct, r.ScorePercent = quizScore(answers)
+	latest := latestQuizAnswers(answers)
+	for _, key := range set.order {
+		c := set.concepts[key]
+		if len(c.Quiz) == 0 {
+			continue
+		}
+		p := ConceptQuizProgress{Concept: key, Questions: len(c.Quiz)}
+		wrong := false
+		for _, q := range c.Quiz {
+			if a, ok := latest[q.ID]; ok {
+				p.Answered++
+				if a.Correct {
+					p.Correct++
+				} else {
+					wrong = true
+				}
+			}
+		}
+		switch {
+		case wrong:
+			p.Status, r.NeedsReview = quizNeedsReview, append(r.NeedsReview, key)
+		case p.Correct == p.Questions:
+			p.Status, r.Mastered = quizMastered, append(r.Mastered, key)
+		case p.Answered > 0:
+			p.Status, r.InProgress = quizInProgress, append(r.InProgress, key)
+		default:
+			p.Status, r.NotStarted = quizNotStarted, append(r.NotStarted, key)
+		}
+		r.Concepts = append(r.Concepts, p)
+	}
+
+	switch {
+	case len(r.Concepts) == 0:
+		r.Message = "There are no quiz questions yet."
+	case len(r.NeedsReview) > 0:
+		r.Message = fmt.Sprintf("Explain %s again, then quiz it to confirm it has stuck.", strings.Join(r.NeedsReview, " and "))
+	case len(r.InProgress) > 0:
+		r.Message = fmt.Sprintf("Finish the quiz on %s.", strings.Join(r.InProgress, " and "))
+	case len(r.NotStarted) > 0:
+		r.Message = fmt.Sprintf("%d of %d concepts mastered; %s hasn't been quizzed yet.", len(r.Mastered), len(r.Concepts), r.NotStarted[0])
+	default:
+		r.Message = fmt.Sprintf("All %d quizzed concepts mastered.", len(r.Mastered))
+	}
+	return r
+}
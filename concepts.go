@@ -0,0 +1,807 @@
+package main
+
+import (
+	"bytes"
+	"cmp"
+	"context"
+	_ "embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"maps"
+	"math"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"syscall"
+	"unicode"
+
+	"github.com/becomeliminal/nim-go-sdk/core"
+	"github.com/becomeliminal/nim-go-sdk/tools"
+)
+
+// ============================================
+// CONCEPT LOOKUP
+// ============================================
+// Concept explanations live in a data file: data/concepts.json is built in,
+// and CONCEPTS_PATH points at a replacement. The file is checked in full when
+// it is loaded - a bad file stops startup, and a bad reload (SIGHUP or
+// POST /admin/concepts/reload) keeps the concepts already loaded.
+
+//go:embed data/concepts.json
+var conceptsJSON []byte
+
+// conceptDifficulties are the allowed difficulty levels, easiest first
+var conceptDifficulties = []string{"beginner", "intermediate", "advanced"}
+
+// conceptRecord is one entry of the concepts file
+type conceptRecord struct {
+	Key         string   `json:"key"`
+	Aliases     []string `json:"aliases"`
+	Summary     string   `json:"summary,omitempty"` // one line for list_investment_concepts; defaults to the explanation's first sentence
+	Explanation string   `json:"explanation"`
+	KeyPoints   []string `json:"key_points"`
+	// Intermediate is for users with some experience; Explanation and KeyPoints are the beginner version
+	Intermediate *conceptVariant `json:"intermediate,omitempty"`
+	Related      []string        `json:"related"`
+	Difficulty   string          `json:"difficulty"`
+	Tags         []string        `json:"tags"` // topics list_investment_concepts filters on, e.g. "funds"
+	Quiz         []quizQuestion  `json:"quiz,omitempty"`
+	// Translations hold the user-facing text in other locales, by locale
+	Translations map[string]conceptTranslation `json:"translations,omitempty"`
+}
+
+// conceptTranslation is a concept's text in one locale; anything left out stays English
+type conceptTranslation struct {
+	Summary      string                     `json:"summary,omitempty"`
+	Explanation  string                     `json:"explanation,omitempty"`
+	KeyPoints    []string                   `json:"key_points,omitempty"`
+	Intermediate *conceptVariant            `json:"intermediate,omitempty"`
+	Quiz         map[string]quizTranslation `json:"quiz,omitempty"` // by question ID
+}
+
+// quizTranslation is a quiz question's text in one locale; choices keep the English order
+type quizTranslation struct {
+	Question    string   `json:"question,omitempty"`
+	Choices     []string `json:"choices,omitempty"`
+	Explanation string   `json:"explanation,omitempty"`
+}
+
+// quizQuestion is one multiple-choice question about a concept, for investment_knowledge_quiz
+type quizQuestion struct {
+	ID          string   `json:"id"` // unique across all concepts
+	Question    string   `json:"question"`
+	Choices     []string `json:"choices"`
+	Answer      int      `json:"answer"`                // index of the correct choice
+	Explanation string   `json:"explanation,omitempty"` // why the answer is right
+}
+
+// conceptVariant is a concept explained at a level other than beginner
+type conceptVariant struct {
+	Explanation string   `json:"explanation"`
+	KeyPoints   []string `json:"key_points"`
+}
+
+// Every explanation, at each level and in each locale, carries this many distinct key points
+const (
+	minConceptKeyPoints = 2
+	maxConceptKeyPoints = 4
+)
+
+// explanationLevels are the levels a concept can be explained at, simplest first
+var explanationLevels = []string{"beginner", "intermediate"}
+
+// experienceExplanationLevels maps the risk questionnaire's previous_experience onto an explanation level
+var experienceExplanationLevels = map[string]string{
+	"none":      "beginner",
+	"minimal":   "beginner",
+	"moderate":  "intermediate",
+	"extensive": "intermediate",
+}
+
+// conceptSet is one loaded concepts file
+type conceptSet struct {
+	order    []string                 // keys in file order
+	concepts map[string]conceptRecord // by normalized key
+	aliases  map[string]string        // normalized alias -> key
+	quiz     map[string]string        // quiz question ID -> concept key
+	source   string                   // file the concepts came from, or "built-in"
+}
+
+// records returns the concepts in file order
+func (s conceptSet) records() []conceptRecord {
+	out := make([]conceptRecord, len(s.order))
+	for i, key := range s.order {
+		out[i] = s.concepts[key]
+	}
+	return out
+}
+
+// conceptLibrary holds the loaded concepts, swapped whole on reload or edit. Readers take mu only
+// to pick up the current set; writeMu keeps reloads and edits from interleaving.
+type conceptLibrary struct {
+	mu      sync.RWMutex
+	writeMu sync.Mutex
+	set     conceptSet
+}
+
+// errConceptsBuiltIn rejects edits when there is no CONCEPTS_PATH file to save them to
+var errConceptsBuiltIn = errors.New("concepts are the built-in set; set CONCEPTS_PATH to a copy of data/concepts.json to edit them")
+
+// Concept removal failures
+var (
+	errConceptNotFound = errors.New("concept not found")
+	errConceptInUse    = errors.New("concept can't be removed")
+)
+
+// conceptFieldErrors are validation failures by field, e.g. {"aliases[1]": "\"dca\" is already an alias of ..."}
+type conceptFieldErrors map[string]string
+
+func (e conceptFieldErrors) Error() string {
+	msgs := make([]string, 0, len(e))
+	for _, field := range slices.Sorted(maps.Keys(e)) {
+		msgs = append(msgs, field+": "+e[field])
+	}
+	return strings.Join(msgs, "; ")
+}
+
+var concepts = loadConceptLibrary()
+
+// loadConceptLibrary loads the startup concepts, refusing to start on a bad file
+func loadConceptLibrary() *conceptLibrary {
+	l := &conceptLibrary{}
+	if _, err := l.reload(); err != nil {
+		log.Fatalf("invalid concepts file: %v", err)
+	}
+	return l
+}
+
+// reload reads CONCEPTS_PATH (or the built-in file) again; on error the current concepts are kept
+func (l *conceptLibrary) reload() (int, error) {
+	l.writeMu.Lock()
+	defer l.writeMu.Unlock()
+	raw, source := conceptsJSON, "built-in"
+	if path := appConfig.ConceptsPath; path != "" {
+		b, err := os.ReadFile(path)
+		if err != nil {
+			return 0, err
+		}
+		raw, source = b, path
+	}
+	set, err := parseConcepts(raw)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", source, err)
+	}
+	set.source = source
+
+	l.swap(set)
+	log.Printf("📚 Loaded %d concepts from %s\n", len(set.concepts), source)
+	return len(set.concepts), nil
+}
+
+// reloadOnSIGHUP reloads the concepts each time the process receives SIGHUP
+func (l *conceptLibrary) reloadOnSIGHUP() {
+	hup := make(chan os.Signal, 1)
+	signal.Notify(hup, syscall.SIGHUP)
+	go func() {
+		for range hup {
+			if _, err := l.reload(); err != nil {
+				log.Printf("⚠️  Concept reload failed, keeping the loaded concepts: %v\n", err)
+			}
+		}
+	}()
+}
+
+// current returns the loaded concepts; a set is never modified once loaded
+func (l *conceptLibrary) current() conceptSet {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	return l.set
+}
+
+func (l *conceptLibrary) swap(set conceptSet) {
+	l.mu.Lock()
+	l.set = set
+	l.mu.Unlock()
+}
+
+// upsert adds c, or replaces the concept with its key, and saves the file. Omitted related, quiz,
+// translations and difficulty keep the existing concept's (beginner for a new one). Invalid input
+// is conceptFieldErrors.
+func (l *conceptLibrary) upsert(c conceptRecord) (saved conceptRecord, created bool, err error) {
+	l.writeMu.Lock()
+	defer l.writeMu.Unlock()
+	cur := l.current()
+	if appConfig.ConceptsPath == "" {
+		return conceptRecord{}, false, errConceptsBuiltIn
+	}
+
+	c.Key = normalizeConceptKey(c.Key)
+	if c.Key == "" {
+		return conceptRecord{}, false, conceptFieldErrors{"key": "is required"}
+	}
+	old, exists := cur.concepts[c.Key]
+	if c.Related == nil {
+		c.Related = old.Related
+	}
+	if c.Quiz == nil {
+		c.Quiz = old.Quiz
+	}
+	if c.Translations == nil {
+		c.Translations = old.Translations
+	}
+	if c.Difficulty == "" {
+		c.Difficulty = cmp.Or(old.Difficulty, conceptDifficulties[0])
+	}
+	records := slices.DeleteFunc(cur.records(), func(r conceptRecord) bool { return r.Key == c.Key })
+	if exists {
+		records = slices.Insert(records, slices.Index(cur.order, c.Key), c)
+	} else {
+		records = append(records, c)
+	}
+	// Checked last, so a clash with an existing alias is reported against c rather than the other concept
+	next, errs := indexConcepts(records, c.Key)
+	if len(errs) > 0 {
+		return conceptRecord{}, false, errs
+	}
+	if err := l.save(next, cur.source); err != nil {
+		return conceptRecord{}, false, err
+	}
+	return next.concepts[c.Key], !exists, nil
+}
+
+// remove deletes a concept and saves the file; concepts that list it as related block the delete
+func (l *conceptLibrary) remove(key string) error {
+	l.writeMu.Lock()
+	defer l.writeMu.Unlock()
+	cur := l.current()
+	if appConfig.ConceptsPath == "" {
+		return errConceptsBuiltIn
+	}
+
+	key = normalizeConceptKey(key)
+	if _, ok := cur.concepts[key]; !ok {
+		return errConceptNotFound
+	}
+	var users []string
+	for _, other := range cur.order {
+		if slices.Contains(cur.concepts[other].Related, key) {
+			users = append(users, other)
+		}
+	}
+	if len(users) > 0 {
+		return fmt.Errorf("%w: %s is a related concept of %s; remove it there first", errConceptInUse, key, strings.Join(users, ", "))
+	}
+	if len(cur.order) == 1 {
+		return fmt.Errorf("%w: the last concept can't be removed", errConceptInUse)
+	}
+	next, _ := indexConcepts(slices.DeleteFunc(cur.records(), func(r conceptRecord) bool { return r.Key == key }), "")
+	return l.save(next, cur.source)
+}
+
+// save writes set to CONCEPTS_PATH, replacing the file in one rename, and then makes it current
+func (l *conceptLibrary) save(set conceptSet, source string) error {
+	raw, err := json.MarshalIndent(map[string]interface{}{"concepts": set.records()}, "", "  ")
+	if err != nil {
+		return err
+	}
+	path := appConfig.ConceptsPath
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(append(raw, '\n')); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return err
+	}
+	set.source = source
+	l.swap(set)
+	return nil
+}
+
+// parseConcepts decodes and checks a concepts file: unknown fields, duplicate keys, aliases or quiz
+// question IDs, missing explanations, unknown difficulties, related concepts that don't exist, quiz
+// questions without a valid answer and translations that don't fit the English are all errors
+func parseConcepts(raw []byte) (conceptSet, error) {
+	var file struct {
+		Concepts []conceptRecord `json:"concepts"`
+	}
+	dec := json.NewDecoder(bytes.NewReader(raw))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&file); err != nil {
+		return conceptSet{}, err
+	}
+	if len(file.Concepts) == 0 {
+		return conceptSet{}, errors.New("no concepts")
+	}
+	set, errs := indexConcepts(file.Concepts, "")
+	if len(errs) > 0 {
+		return conceptSet{}, errs
+	}
+	return set, nil
+}
+
+// indexConcepts normalizes and checks records. Errors are keyed "<key>.<field>", or just "<field>" for
+// the concept named by only, which is checked after the others and is the only one errors are kept for.
+func indexConcepts(records []conceptRecord, only string) (conceptSet, conceptFieldErrors) {
+	set := conceptSet{concepts: map[string]conceptRecord{}, aliases: map[string]string{}, quiz: map[string]string{}}
+	errs := conceptFieldErrors{}
+	fail := func(key, field, format string, args ...interface{}) {
+		switch {
+		case only == "":
+			errs[key+"."+field] = fmt.Sprintf(format, args...)
+		case key == only:
+			errs[field] = fmt.Sprintf(format, args...)
+		}
+	}
+	checkKeyPoints := func(key, field string, points []string) {
+		if len(points) < minConceptKeyPoints || len(points) > maxConceptKeyPoints {
+			fail(key, field, "needs %d to %d key points, has %d", minConceptKeyPoints, maxConceptKeyPoints, len(points))
+		}
+		seen := map[string]int{}
+		for i, point := range points {
+			text := strings.ToLower(strings.Join(strings.Fields(point), " "))
+			if first, dup := seen[text]; text == "" {
+				fail(key, fmt.Sprintf("%s[%d]", field, i), "is required")
+			} else if dup {
+				fail(key, fmt.Sprintf("%s[%d]", field, i), "repeats %s[%d]", field, first)
+			} else {
+				seen[text] = i
+			}
+		}
+	}
+
+	for i, c := range records {
+		c.Key = normalizeConceptKey(c.Key)
+		if c.Key == "" {
+			fail(fmt.Sprintf("concepts[%d]", i), "key", "is required")
+			continue
+		}
+		if strings.TrimSpace(c.Explanation) == "" {
+			fail(c.Key, "explanation", "is required")
+		}
+		if !slices.Contains(conceptDifficulties, c.Difficulty) {
+			fail(c.Key, "difficulty", "%q must be one of %s", c.Difficulty, strings.Join(conceptDifficulties, ", "))
+		}
+		if _, dup := set.concepts[c.Key]; dup {
+			fail(c.Key, "key", "duplicate key")
+			continue
+		}
+		c.Aliases = normalizeConceptKeys(c.Aliases)
+		c.Related = normalizeConceptKeys(c.Related)
+		c.Tags = normalizeConceptKeys(c.Tags)
+		checkKeyPoints(c.Key, "key_points", c.KeyPoints)
+		if c.Intermediate != nil {
+			if strings.TrimSpace(c.Intermediate.Explanation) == "" {
+				fail(c.Key, "intermediate.explanation", "is required")
+			}
+			checkKeyPoints(c.Key, "intermediate.key_points", c.Intermediate.KeyPoints)
+		}
+		c.Quiz = slices.Clone(c.Quiz)
+		for i, q := range c.Quiz {
+			field := fmt.Sprintf("quiz[%d]", i)
+			c.Quiz[i].ID = normalizeConceptKey(q.ID)
+			if c.Quiz[i].ID == "" {
+				fail(c.Key, field+".id", "is required")
+			}
+			if strings.TrimSpace(q.Question) == "" {
+				fail(c.Key, field+".question", "is required")
+			}
+			if len(q.Choices) < 2 {
+				fail(c.Key, field+".choices", "needs at least 2 choices")
+			} else if q.Answer < 0 || q.Answer >= len(q.Choices) {
+				fail(c.Key, field+".answer", "%d must index a choice, 0 to %d", q.Answer, len(q.Choices)-1)
+			}
+		}
+		for locale, t := range c.Translations {
+			field := "translations." + locale
+			if locale == defaultLocale || !slices.Contains(supportedLocales, locale) {
+				fail(c.Key, field, "%q must be one of %s", locale, strings.Join(supportedLocales[1:], ", "))
+			}
+			if t.KeyPoints != nil {
+				checkKeyPoints(c.Key, field+".key_points", t.KeyPoints)
+			}
+			if t.Intermediate != nil && t.Intermediate.KeyPoints != nil {
+				checkKeyPoints(c.Key, field+".intermediate.key_points", t.Intermediate.KeyPoints)
+			}
+			for id, qt := range t.Quiz {
+				i := slices.IndexFunc(c.Quiz, func(q quizQuestion) bool { return q.ID == id })
+				switch {
+				case i < 0:
+					fail(c.Key, field+".quiz."+id, "is not a quiz question of %s", c.Key)
+				case qt.Choices != nil && len(qt.Choices) != len(c.Quiz[i].Choices):
+					fail(c.Key, field+".quiz."+id+".choices", "has %d choices, the question %d", len(qt.Choices), len(c.Quiz[i].Choices))
+				}
+			}
+		}
+		set.concepts[c.Key] = c
+		set.order = append(set.order, c.Key)
+	}
+
+	ordered := slices.Clone(set.order)
+	if i := slices.Index(ordered, only); i >= 0 {
+		ordered = append(slices.Delete(ordered, i, i+1), only)
+	}
+	for _, key := range ordered {
+		c := set.concepts[key]
+		for i, alias := range c.Aliases {
+			field := fmt.Sprintf("aliases[%d]", i)
+			if other, taken := set.aliases[alias]; taken {
+				fail(key, field, "%q is already an alias of %s", alias, other)
+			} else if _, isKey := set.concepts[alias]; isKey {
+				fail(key, field, "%q is a concept key", alias)
+			}
+			set.aliases[alias] = key
+		}
+		for i, related := range c.Related {
+			if _, ok := set.concepts[related]; !ok || related == key {
+				fail(key, fmt.Sprintf("related[%d]", i), "%q is not another concept", related)
+			}
+		}
+		for i, q := range c.Quiz {
+			if other, taken := set.quiz[q.ID]; taken {
+				fail(key, fmt.Sprintf("quiz[%d].id", i), "%q is already a question of %s", q.ID, other)
+			} else if q.ID != "" {
+				set.quiz[q.ID] = key
+			}
+		}
+	}
+	return set, errs
+}
+
+// normalizeConceptKeys normalizes each key, always returning a non-nil slice
+func normalizeConceptKeys(keys []string) []string {
+	out := make([]string, len(keys))
+	for i, k := range keys {
+		out[i] = normalizeConceptKey(k)
+	}
+	return out
+}
+
+// normalizeConceptKey lowercases, drops apostrophes, and turns any other run of non-alphanumerics
+// into one underscore ("ETF's" -> "etfs", "Dollar-cost averaging?" -> "dollar_cost_averaging")
+func normalizeConceptKey(concept string) string {
+	key := strings.ReplaceAll(strings.ToLower(concept), "'", "")
+	return strings.Join(strings.FieldsFunc(key, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	}), "_")
+}
+
+// localizedConcept is c with its user-facing text in locale, falling back to English field by field.
+// A translated explanation without a translated summary summarizes the translation.
+func localizedConcept(c conceptRecord, locale string) conceptRecord {
+	t, ok := c.Translations[locale]
+	if !ok {
+		return c
+	}
+	if t.Explanation != "" {
+		c.Explanation, c.Summary = t.Explanation, t.Summary
+	}
+	c.Summary = cmp.Or(t.Summary, c.Summary)
+	if t.KeyPoints != nil {
+		c.KeyPoints = t.KeyPoints
+	}
+	if t.Intermediate != nil && c.Intermediate != nil {
+		v := *c.Intermediate
+		v.Explanation = cmp.Or(t.Intermediate.Explanation, v.Explanation)
+		if t.Intermediate.KeyPoints != nil {
+			v.KeyPoints = t.Intermediate.KeyPoints
+		}
+		c.Intermediate = &v
+	}
+	if len(t.Quiz) > 0 {
+		c.Quiz = slices.Clone(c.Quiz)
+		for i, q := range c.Quiz {
+			qt := t.Quiz[q.ID]
+			c.Quiz[i].Question = cmp.Or(qt.Question, q.Question)
+			c.Quiz[i].Explanation = cmp.Or(qt.Explanation, q.Explanation)
+			if qt.Choices != nil {
+				c.Quiz[i].Choices = qt.Choices
+			}
+		}
+	}
+	return c
+}
+
+// conceptSummary is c's summary, or the first sentence of its explanation when it has none
+func conceptSummary(c conceptRecord) string {
+	if c.Summary != "" {
+		return c.Summary
+	}
+	if i := strings.Index(c.Explanation, ". "); i >= 0 {
+		return c.Explanation[:i+1]
+	}
+	return c.Explanation
+}
+
+// newConceptListTool lists the loaded concepts so the model knows what explain_investment_concept covers
+func newConceptListTool() core.Tool {
+	return tools.New("list_investment_concepts").
+		Description("List every investment concept explain_investment_concept can explain, with a one-line summary, difficulty and topic tags. Filter by difficulty (e.g. beginner concepts for a new investor) or by topic. Call it when unsure whether a concept is covered or to suggest what to learn next").
+		Schema(tools.ObjectSchema(map[string]interface{}{
+			"difficulty": tools.StringProperty("Optional difficulty: " + strings.Join(conceptDifficulties, ", ")),
+			"topic":      tools.StringProperty("Optional topic tag, e.g. 'funds' or 'habits'"),
+		})).
+		Handler(func(ctx context.Context, toolParams *core.ToolParams) (*core.ToolResult, error) {
+			var params struct {
+				Difficulty string `json:"difficulty"`
+				Topic      string `json:"topic"`
+			}
+			if len(toolParams.Input) > 0 {
+				if err := json.Unmarshal(toolParams.Input, &params); err != nil {
+					return &core.ToolResult{Success: false, Error: fmt.Sprintf("invalid input: %v", err)}, nil
+				}
+			}
+			locale := userLocale(ctx, toolParams.UserID)
+			v := amountValidator{locale: locale}
+			difficulty := ""
+			if strings.TrimSpace(params.Difficulty) != "" {
+				difficulty = v.oneOf("difficulty", params.Difficulty, conceptDifficulties)
+			}
+			if err := v.err(); err != nil {
+				return &core.ToolResult{Success: false, Error: err.Error()}, nil
+			}
+			return &core.ToolResult{Success: true, Data: listConcepts(concepts.current(), difficulty, normalizeConceptKey(params.Topic), locale)}, nil
+		}).
+		Build()
+}
+
+// listConcepts summarizes set's concepts in file order, in locale, keeping those matching difficulty and topic when given
+func listConcepts(set conceptSet, difficulty, topic, locale string) ConceptListResult {
+	r := ConceptListResult{Concepts: []ConceptSummary{}, Topics: []string{}}
+	topics := map[string]bool{}
+	for _, c := range set.records() {
+		for _, tag := range c.Tags {
+			topics[tag] = true
+		}
+		if (difficulty != "" && c.Difficulty != difficulty) || (topic != "" && !slices.Contains(c.Tags, topic)) {
+			continue
+		}
+		r.Concepts = append(r.Concepts, ConceptSummary{Concept: c.Key, Summary: conceptSummary(localizedConcept(c, locale)), Difficulty: c.Difficulty, Levels: conceptLevels(c), Tags: c.Tags})
+	}
+	r.Topics = slices.Sorted(maps.Keys(topics))
+
+	var filters []string
+	if difficulty != "" {
+		filters = append(filters, difficulty)
+	}
+	if topic != "" {
+		filters = append(filters, "tagged "+topic)
+	}
+	switch {
+	case len(r.Concepts) > 0 && len(filters) == 0:
+		r.Message = fmt.Sprintf("%d concepts available; explain any of them with explain_investment_concept.", len(r.Concepts))
+	case len(r.Concepts) > 0:
+		r.Message = fmt.Sprintf("%d concept(s) matching %s; explain any of them with explain_investment_concept.", len(r.Concepts), strings.Join(filters, ", "))
+	default:
+		r.Message = fmt.Sprintf("No concepts matching %s. Topics available: %s.", strings.Join(filters, ", "), strings.Join(r.Topics, ", "))
+	}
+	return r
+}
+
+// experienceLevel is the explanation level for the user's stored investing experience, and
+// where it came from: "experience", or "default" (beginner) when the profile doesn't say
+func experienceLevel(ctx context.Context, userID string) (level, source string) {
+	p, err := loadStoredPortfolio(ctx, userID)
+	if err != nil {
+		log.Printf("⚠️  Failed to load experience for %s: %v\n", userKey(userID), err)
+	}
+	if level, ok := experienceExplanationLevels[p.Experience]; ok {
+		return level, "experience"
+	}
+	return explanationLevels[0], "default"
+}
+
+// explainConcept looks a concept up by key or alias in the loaded concepts, falling back to
+// fuzzy matching: a confident match is explained, anything less comes back as did_you_mean.
+// level picks the explanation variant and locale its language; learned holds the concepts
+// already explained to the user, whose related teasers come last.
+func explainConcept(concept, level, locale string, learned map[string]bool) map[string]interface{} {
+	set := concepts.current()
+	key := normalizeConceptKey(concept)
+	if alias, ok := set.aliases[key]; ok {
+		key = alias
+	}
+	c, exists := set.concepts[key]
+	if exists {
+		return conceptExplanation(set, c, level, locale, learned)
+	}
+
+	candidates := matchConcept(set, key)
+	if len(candidates) > 0 && candidates[0].score >= conceptMatchScore &&
+		(len(candidates) == 1 || candidates[0].score-candidates[1].score >= conceptMatchMargin) {
+		explanation := conceptExplanation(set, set.concepts[candidates[0].key], level, locale, learned)
+		explanation["matched_from"] = concept
+		explanation["match_confidence"] = math.Round(candidates[0].score*100) / 100
+		return explanation
+	}
+	if len(candidates) > 0 {
+		suggestions := make([]string, 0, maxConceptSuggestions)
+		for _, cand := range candidates[:min(len(candidates), maxConceptSuggestions)] {
+			suggestions = append(suggestions, cand.key)
+		}
+		return map[string]interface{}{
+			"concept":      concept,
+			"found":        false,
+			"did_you_mean": suggestions,
+			"explanation":  fmt.Sprintf("I'm not sure which concept %q means. Ask the user whether they meant %s, then explain that one.", concept, strings.Join(suggestions, " or ")),
+		}
+	}
+
+	return map[string]interface{}{
+		"concept":     concept,
+		"found":       false,
+		"explanation": "I don't have that concept in my database yet. Call list_investment_concepts to see the ones I can explain.",
+	}
+}
+
+// conceptAtLevel picks c's explanation for level, falling back to beginner when c has no intermediate
+// variant; level is the one actually used
+func conceptAtLevel(c conceptRecord, level string) (text conceptVariant, used string, fallback bool) {
+	switch {
+	case level == "intermediate" && c.Intermediate != nil:
+		return *c.Intermediate, level, false
+	case level == "intermediate":
+		return conceptVariant{Explanation: c.Explanation, KeyPoints: c.KeyPoints}, "beginner", true
+	}
+	return conceptVariant{Explanation: c.Explanation, KeyPoints: c.KeyPoints}, "beginner", false
+}
+
+// conceptExplanation is explain_investment_concept's reply for a known concept at level. A concept
+// without the intermediate variant falls back to beginner with level_fallback set, so the gap shows.
+func conceptExplanation(set conceptSet, c conceptRecord, level, locale string, learned map[string]bool) map[string]interface{} {
+	text, level, fallback := conceptAtLevel(localizedConcept(c, locale), level)
+	reply := map[string]interface{}{
+		"concept":          c.Key,
+		"found":            true,
+		"level":            level,
+		"explanation":      text.Explanation,
+		"key_points":       text.KeyPoints,
+		"related_concepts": relatedTeasers(set, c, locale, learned),
+		"difficulty":       c.Difficulty,
+		"tags":             c.Tags,
+	}
+	if fallback {
+		reply["level_fallback"] = true
+		reply["level_note"] = "No intermediate explanation for this concept yet, so this is the beginner one - add detail where the user is ready for it"
+	}
+	return reply
+}
+
+// conceptLevels lists the explanation levels c has
+func conceptLevels(c conceptRecord) []string {
+	if c.Intermediate != nil {
+		return explanationLevels
+	}
+	return explanationLevels[:1]
+}
+
+// Fuzzy concept matching, on similarities from 0 to 1
+const (
+	conceptMatchScore     = 0.85 // the best concept is explained at or above this...
+	conceptMatchMargin    = 0.10 // ...when it leads the next concept by at least this much
+	conceptSuggestScore   = 0.5  // concepts at or above this are offered as did_you_mean
+	maxConceptSuggestions = 3
+)
+
+// conceptCandidate is a concept's best similarity to a query over its key and aliases
+type conceptCandidate struct {
+	key   string
+	score float64
+}
+
+// matchConcept ranks concepts by similarity to a normalized query, best first, keeping those
+// worth suggesting. Similarity is the better of stemmed word overlap and edit distance with
+// the separators dropped, so "compounding interest" and "dollarcostaveraging" both score 1.
+func matchConcept(set conceptSet, query string) []conceptCandidate {
+	q := conceptStems(query)
+	if len(q) == 0 {
+		return nil
+	}
+	best := map[string]float64{}
+	consider := func(name, key string) {
+		best[key] = max(best[key], conceptSimilarity(q, conceptStems(name)))
+	}
+	for key := range set.concepts {
+		consider(key, key)
+	}
+	for alias, key := range set.aliases {
+		consider(alias, key)
+	}
+
+	var out []conceptCandidate
+	for key, score := range best {
+		if score >= conceptSuggestScore {
+			out = append(out, conceptCandidate{key, score})
+		}
+	}
+	slices.SortFunc(out, func(a, b conceptCandidate) int {
+		return cmp.Or(cmp.Compare(b.score, a.score), strings.Compare(a.key, b.key))
+	})
+	return out
+}
+
+// conceptSimilarity compares two lists of distinct stemmed words
+func conceptSimilarity(a, b []string) float64 {
+	shared, union := 0, map[string]bool{}
+	for _, w := range a {
+		union[w] = true
+	}
+	for _, w := range b {
+		if union[w] {
+			shared++
+		}
+		union[w] = true
+	}
+	overlap := float64(shared) / float64(len(union))
+
+	ca, cb := strings.Join(a, ""), strings.Join(b, "")
+	edit := 1 - float64(levenshtein(ca, cb))/float64(max(len(ca), len(cb)))
+	return max(overlap, edit)
+}
+
+// conceptStems splits a normalized key into distinct words with plural and -ing endings removed
+func conceptStems(key string) []string {
+	var words []string
+	for _, w := range strings.Split(key, "_") {
+		if w == "" {
+			continue
+		}
+		if stem := conceptStem(w); !slices.Contains(words, stem) {
+			words = append(words, stem)
+		}
+	}
+	return words
+}
+
+// conceptStem strips one common suffix and then a trailing e, so "averaging" and "average",
+// "dividends" and "dividend", "ratios" and "ratio", "etfs" and "etf" meet. A plain plural s
+// comes off four-letter words too; the longer endings need five letters so "feed" keeps its ed.
+func conceptStem(w string) string {
+	switch {
+	case len(w) > 4 && strings.HasSuffix(w, "ies"):
+		w = strings.TrimSuffix(w, "ies") + "y"
+	case len(w) > 4 && strings.HasSuffix(w, "ing"):
+		w = strings.TrimSuffix(w, "ing")
+	case len(w) > 4 && (strings.HasSuffix(w, "ed") || strings.HasSuffix(w, "es")):
+		w = w[:len(w)-2]
+	case len(w) > 3 && strings.HasSuffix(w, "s") && !strings.HasSuffix(w, "ss"):
+		w = strings.TrimSuffix(w, "s")
+	}
+	if len(w) > 3 {
+		w = strings.TrimSuffix(w, "e")
+	}
+	return w
+}
+
+// levenshtein is the number of single-character edits between a and b
+func levenshtein(a, b string) int {
+	prev := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		cur := make([]int, len(b)+1)
+		cur[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+		prev = cur
+	}
+	return prev[len(b)]
+}
@@ -0,0 +1,96 @@
+package main
+
+import "testing"
+
+func TestConceptStem(t *testing.T) {
+	tests := []struct{ word, want string }{
+		{"etfs", "etf"},
+		{"etf", "etf"},
+		{"fees", "fee"},
+		{"fee", "fee"},
+		{"dividends", "dividend"},
+		{"ratios", "ratio"},
+		{"averaging", "averag"},
+		{"average", "averag"},
+		{"annuities", "annuity"},
+		{"taxes", "tax"},
+		{"loss", "loss"},
+		{"feed", "feed"},
+		{"dca", "dca"},
+	}
+	for _, tt := range tests {
+		if got := conceptStem(tt.word); got != tt.want {
+			t.Errorf("conceptStem(%q) = %q, want %q", tt.word, got, tt.want)
+		}
+	}
+}
+
+func TestMatchConceptWithoutAliases(t *testing.T) {
+	// No plural or spelling aliases, so only the stemming and edit distance can find these
+	set := conceptSet{concepts: map[string]conceptRecord{}, aliases: map[string]string{}}
+	for _, key := range []string{"etf", "dividend", "dollar_cost_averaging", "expense_ratio", "compound_interest"} {
+		set.concepts[key] = conceptRecord{Key: key}
+	}
+	tests := []struct {
+		query string
+		want  string // the confidently matched key, "" for none
+	}{
+		{"ETFs", "etf"},
+		{"etf", "etf"},
+		{"dividends", "dividend"},
+		{"expense ratios", "expense_ratio"},
+		{"Dollar cost averaging", "dollar_cost_averaging"},
+		{"dollar_cost_averaging", "dollar_cost_averaging"},
+		{"Dollar-cost averaging?", "dollar_cost_averaging"},
+		{"dollarcostaveraging", "dollar_cost_averaging"},
+		{"compounding interest", "compound_interest"},
+		{"crypto staking", ""},
+		{"margin call", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			candidates := matchConcept(set, normalizeConceptKey(tt.query))
+			got := ""
+			if len(candidates) > 0 && candidates[0].score >= conceptMatchScore &&
+				(len(candidates) == 1 || candidates[0].score-candidates[1].score >= conceptMatchMargin) {
+				got = candidates[0].key
+			}
+			if got != tt.want {
+				t.Errorf("matched %q, want %q (candidates %+v)", got, tt.want, candidates)
+			}
+		})
+	}
+}
+
+func TestExplainConceptVariants(t *testing.T) {
+	tests := []struct {
+		query string
+		want  string // the explained key, "" when not found
+	}{
+		{"ETFs", "etf"},
+		{"ETF's", "etf"},
+		{"index funds", "etf"},
+		{"Dividends", "dividend"},
+		{"dollar cost averaging", "dollar_cost_averaging"},
+		{"dollar_cost_averaging", "dollar_cost_averaging"},
+		{"Dollar-cost averaging?", "dollar_cost_averaging"},
+		{"compounding interest", "compound_interest"},
+		{"quantum entanglement", ""},
+		{"zzz", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			reply := explainConcept(tt.query, "beginner", "en", nil)
+			found, _ := reply["found"].(bool)
+			if tt.want == "" {
+				if found {
+					t.Errorf("found %v, want nothing", reply["concept"])
+				}
+				return
+			}
+			if !found || reply["concept"] != tt.want {
+				t.Errorf("found = %v, concept = %v; want %q", found, reply["concept"], tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,197 @@
+package main
+
+import (
+	"log"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Config holds server-level settings loaded from the environment at startup
+type Config struct {
+	Currency         string        // ISO code used for every monetary string and for transfers: USD, EUR or GBP
+	Locale           string        // Default language for explanations, amounts and validation errors: en or es; users can pick their own
+	Assumptions      Assumptions   // Default inflation, equity, bond and savings rates (see assumptions.go)
+	VaultRateTTL     time.Duration // How long a get_vault_rates APY is trusted before it is fetched again
+	ParseCacheSize   int           // Max distinct input strings kept by parseCachedAmount
+	RebalanceBandPct float64       // Allowed drift either side of every rebalancing target, in percentage points
+	MinRebalanceMove float64       // Smallest rebalancing move worth trading, in the account currency
+	WithdrawalRate   float64       // Annual % of a retirement nest egg treated as sustainable income
+	SimulationPaths  int           // Default Monte Carlo paths per simulate_investment_outcomes call
+	ScenarioSpread   float64       // Return points below and above expected for calculate_investment_projection scenarios
+	HighAPRThreshold float64       // Debt APR % at or above which paying it down always comes before investing
+	PositionCapPct   float64       // Largest share of a portfolio any single holding should be, in %
+	SectorCapPct     float64       // Largest share of a portfolio any one sector should be, in %
+	RiskSessionTTL   time.Duration // Idle time after which a multi-turn risk questionnaire expires
+	MinMonthlyInvest float64       // Smallest monthly_amount start_automated_investing accepts, in USD
+	AffordInflowPct  float64       // Share of average monthly inflow, in %, that automated commitments can take comfortably
+	SubscriptionMax  float64       // Largest recurring charge counted as a subscription; bigger ones are rent, loans and the like
+	SpendSpikePct    float64       // Default rise over the average of the three 30-day periods before the last 30 days, in %, that check_spending_anomalies flags
+	CashBuffer       float64       // Lowest projected wallet balance a new monthly commitment may leave without a warning
+	MilestoneAmounts []float64     // Contributed and portfolio value thresholds, in USD, recorded as milestones
+	GoalMilestonePct float64       // Share of a goal, in %, whose funding is recorded as a milestone
+	HealthWeights    healthWeights // Weight of each portfolio_health_score component
+	QuoteAPIURL      string        // Base URL of the market data API; empty disables live quotes
+	QuoteAPIKey      string        // Bearer token for the market data API
+	QuoteCacheTTL    time.Duration // How long a ticker's quote is reused before asking the provider again
+	AdminAddr        string        // Listen address for the support admin API
+	AdminToken       string        // Bearer token for the admin API; empty disables it
+	DataPath         string        // SQLite file for persistent user state; empty keeps state in memory
+	ConceptsPath     string        // Concept explanations file; empty uses the built-in data/concepts.json
+
+	SchedulerEnabled bool          // Run automated plans on schedule
+	ExecMaxAttempts  int           // Transfer attempts per plan per period before giving up
+	ExecRetryBackoff time.Duration // Wait before the first retry; doubles on each further retry
+	InvestRecipient  string        // send_money recipient for non-savings plans (brokerage account)
+	SnapshotsEnabled bool          // Record each stored portfolio's value daily for get_portfolio_performance
+}
+
+// appConfig is read by the tool handlers; loaded once at startup
+var appConfig = loadConfig()
+
+func loadConfig() Config {
+	return Config{
+		Currency:         envCurrency("CURRENCY", "USD"),
+		Locale:           envLocale("LOCALE", defaultLocale),
+		Assumptions:      loadAssumptions(),
+		VaultRateTTL:     envDuration("VAULT_RATE_TTL", 15*time.Minute),
+		ParseCacheSize:   envInt("PARSE_CACHE_SIZE", 4096),
+		RebalanceBandPct: envFloat("REBALANCE_BAND_PCT", 5.0),
+		MinRebalanceMove: envFloat("REBALANCE_MIN_TRADE", 100),
+		WithdrawalRate:   envFloat("WITHDRAWAL_RATE_PCT", 4.0),
+		SimulationPaths:  envInt("MONTE_CARLO_PATHS", 1000),
+		ScenarioSpread:   envFloat("PROJECTION_SCENARIO_SPREAD_PCT", 2.0),
+		HighAPRThreshold: envFloat("HIGH_APR_THRESHOLD_PCT", 10.0),
+		PositionCapPct:   envFloat("POSITION_CAP_PCT", 10.0),
+		SectorCapPct:     envFloat("SECTOR_CAP_PCT", 30.0),
+		RiskSessionTTL:   envDuration("RISK_SESSION_TTL", 30*time.Minute),
+		MinMonthlyInvest: envFloat("MIN_MONTHLY_INVESTMENT", 10.0),
+		AffordInflowPct:  envFloat("AFFORD_INFLOW_PCT", 30.0),
+		SubscriptionMax:  envFloat("SUBSCRIPTION_MAX_AMOUNT", 200),
+		SpendSpikePct:    envFloat("SPENDING_SPIKE_PCT", 30),
+		CashBuffer:       envFloat("CASH_SAFETY_BUFFER", 500),
+		MilestoneAmounts: envFloats("MILESTONE_AMOUNTS", []float64{1000, 10000, 50000, 100000}),
+		GoalMilestonePct: envFloat("GOAL_MILESTONE_PCT", 50),
+		HealthWeights:    envWeights("HEALTH_SCORE_WEIGHTS", defaultHealthWeights),
+		QuoteAPIURL:      os.Getenv("QUOTE_API_URL"),
+		QuoteAPIKey:      os.Getenv("QUOTE_API_KEY"),
+		QuoteCacheTTL:    envDuration("QUOTE_CACHE_TTL", time.Minute),
+		AdminAddr:        envString("ADMIN_ADDR", ":8081"),
+		AdminToken:       os.Getenv("ADMIN_TOKEN"),
+		DataPath:         os.Getenv("DATA_PATH"),
+		ConceptsPath:     os.Getenv("CONCEPTS_PATH"),
+
+		SchedulerEnabled: envBool("PLAN_SCHEDULER_ENABLED", true),
+		ExecMaxAttempts:  envInt("EXECUTION_MAX_ATTEMPTS", 3),
+		ExecRetryBackoff: envDuration("EXECUTION_RETRY_BACKOFF", 30*time.Second),
+		InvestRecipient:  os.Getenv("INVEST_RECIPIENT"),
+		SnapshotsEnabled: envBool("PORTFOLIO_SNAPSHOTS_ENABLED", true),
+	}
+}
+
+// envString reads a string from the environment with a fallback
+func envString(key, fallback string) string {
+	if v := os.Getenv(key); v != "" {
+		return v
+	}
+	return fallback
+}
+
+// envFloat reads a float from the environment, keeping the fallback on bad input
+func envFloat(key string, fallback float64) float64 {
+	raw := os.Getenv(key)
+	if raw == "" {
+		return fallback
+	}
+	v, err := strconv.ParseFloat(raw, 64)
+	if err != nil {
+		log.Printf("⚠️  Ignoring invalid %s=%q, using %v", key, raw, fallback)
+		return fallback
+	}
+	return v
+}
+
+// envFloats reads a comma-separated list of floats, keeping the fallback if any entry is bad
+func envFloats(key string, fallback []float64) []float64 {
+	raw := os.Getenv(key)
+	if raw == "" {
+		return fallback
+	}
+	var values []float64
+	for _, part := range strings.Split(raw, ",") {
+		v, err := strconv.ParseFloat(strings.TrimSpace(part), 64)
+		if err != nil {
+			log.Printf("⚠️  Ignoring invalid %s=%q, using %v", key, raw, fallback)
+			return fallback
+		}
+		values = append(values, v)
+	}
+	return values
+}
+
+// envWeights reads comma-separated name=weight pairs over a copy of fallback, keeping the fallback
+// if any entry is bad or names something fallback doesn't have
+func envWeights(key string, fallback healthWeights) healthWeights {
+	raw := os.Getenv(key)
+	if raw == "" {
+		return fallback
+	}
+	weights := make(healthWeights, len(fallback))
+	for name, w := range fallback {
+		weights[name] = w
+	}
+	for _, part := range strings.Split(raw, ",") {
+		name, value, found := strings.Cut(part, "=")
+		name = strings.TrimSpace(name)
+		w, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+		if _, known := fallback[name]; !found || !known || err != nil || w < 0 {
+			log.Printf("⚠️  Ignoring invalid %s=%q, using %v", key, raw, fallback)
+			return fallback
+		}
+		weights[name] = w
+	}
+	return weights
+}
+
+// envInt reads an int from the environment, keeping the fallback on bad input
+func envInt(key string, fallback int) int {
+	raw := os.Getenv(key)
+	if raw == "" {
+		return fallback
+	}
+	v, err := strconv.Atoi(raw)
+	if err != nil {
+		log.Printf("⚠️  Ignoring invalid %s=%q, using %v", key, raw, fallback)
+		return fallback
+	}
+	return v
+}
+
+// envBool reads a bool ("true", "false", "1", "0", ...) from the environment, keeping the fallback on bad input
+func envBool(key string, fallback bool) bool {
+	raw := os.Getenv(key)
+	if raw == "" {
+		return fallback
+	}
+	v, err := strconv.ParseBool(raw)
+	if err != nil {
+		log.Printf("⚠️  Ignoring invalid %s=%q, using %v", key, raw, fallback)
+		return fallback
+	}
+	return v
+}
+
+// envDuration reads a Go duration ("30s", "5m") from the environment, keeping the fallback on bad input
+func envDuration(key string, fallback time.Duration) time.Duration {
+	raw := os.Getenv(key)
+	if raw == "" {
+		return fallback
+	}
+	v, err := time.ParseDuration(raw)
+	if err != nil {
+		log.Printf("⚠️  Ignoring invalid %s=%q, using %v", key, raw, fallback)
+		return fallback
+	}
+	return v
+}
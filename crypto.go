@@ -0,0 +1,113 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/becomeliminal/nim-go-sdk/core"
+	"github.com/becomeliminal/nim-go-sdk/tools"
+)
+
+// ============================================
+// CRYPTO GUARDRAIL
+// ============================================
+
+// newCryptoGuardrailTool checks crypto holdings (and a planned purchase) against the risk level's cap
+func newCryptoGuardrailTool() core.Tool {
+	return tools.New("crypto_allocation_guardrail").
+		Description("Check how much of the user's investable assets should be in crypto for their risk level, how far over or under the cap they are (including a planned purchase), and how much to add or trim").
+		Schema(tools.ObjectSchema(map[string]interface{}{
+			"total_investable": tools.StringProperty("Total investable assets in the account currency, including current crypto holdings"),
+			"crypto_holdings":  tools.StringProperty("Current crypto holdings in the account currency"),
+			"risk_level":       tools.StringProperty("Optional risk level: conservative, moderate, moderate-to-aggressive or aggressive; defaults to the user's profile"),
+			"planned_purchase": tools.StringProperty("Optional crypto purchase the user is considering in the account currency"),
+		}, "total_investable", "crypto_holdings")).
+		Handler(func(ctx context.Context, toolParams *core.ToolParams) (*core.ToolResult, error) {
+			var params struct {
+				TotalInvestable string `json:"total_investable"`
+				CryptoHoldings  string `json:"crypto_holdings"`
+				RiskLevel       string `json:"risk_level"`
+				PlannedPurchase string `json:"planned_purchase"`
+			}
+			if err := json.Unmarshal(toolParams.Input, &params); err != nil {
+				return &core.ToolResult{Success: false, Error: fmt.Sprintf("invalid input: %v", err)}, nil
+			}
+
+			var v amountValidator
+			total := v.positive("total_investable", params.TotalInvestable)
+			holdings := v.nonNegative("crypto_holdings", params.CryptoHoldings, true)
+			planned := v.nonNegative("planned_purchase", params.PlannedPurchase, false)
+			if len(v.errs) == 0 && holdings > total {
+				v.fail("crypto_holdings", "%s is more than total_investable (%s)", formatMoney(holdings), formatMoney(total))
+			}
+			if err := v.err(); err != nil {
+				return &core.ToolResult{Success: false, Error: err.Error()}, nil
+			}
+
+			riskSource := "user_provided"
+			rawRisk := params.RiskLevel
+			if strings.TrimSpace(rawRisk) == "" {
+				portfolio, err := loadPortfolio(ctx, toolParams.UserID)
+				if err != nil {
+					return &core.ToolResult{Success: false, Error: profileFieldError(err, "risk_level").Error()}, nil
+				}
+				rawRisk, riskSource = string(portfolio.RiskTolerance), "profile"
+			}
+			risk, err := normalizeRiskLevel(rawRisk)
+			if err != nil {
+				return &core.ToolResult{Success: false, Error: fmt.Sprintf("invalid input: risk_level: %v", err)}, nil
+			}
+
+			result := cryptoGuardrail(total, holdings, planned, risk)
+			result.RiskSource = riskSource
+			return &core.ToolResult{Success: true, Data: result}, nil
+		}).
+		Build()
+}
+
+// cryptoGuardrail compares holdings after any planned purchase with the risk level's cap.
+// A purchase is assumed to come from other investable assets, so the total is unchanged.
+func cryptoGuardrail(total, holdings, planned float64, risk RiskLevel) CryptoGuardrailResult {
+	capPct := cryptoCapByRisk[risk]
+	capUSD := total * capPct / 100
+	after := holdings + planned
+
+	r := CryptoGuardrailResult{
+		Currency:           activeCurrency().Code,
+		RiskLevel:          risk,
+		TotalInvestableUSD: total,
+		CryptoHoldingsUSD:  holdings,
+		CurrentPercent:     holdings / total * 100,
+		CapPercent:         capPct,
+		CapUSD:             capUSD,
+		AfterPurchaseUSD:   after,
+		AfterPercent:       after / total * 100,
+		DistancePercent:    after/total*100 - capPct,
+	}
+	if planned > 0 {
+		r.PlannedPurchaseUSD = planned
+		r.MaxPurchaseUSD = max(capUSD-holdings, 0)
+	}
+
+	switch {
+	case after > capUSD+0.005:
+		r.Status = "over_cap"
+		r.TrimUSD = after - capUSD
+		r.Warning = fmt.Sprintf("That would put %.1f%% of your investable assets in crypto, above the %.1f%% cap for a %s investor. "+
+			"Crypto can fall 50-80%% in a downturn; keeping it to %s limits how much a crash could set back your other goals.",
+			r.AfterPercent, capPct, risk, formatWholeMoney(capUSD))
+		if planned > 0 {
+			r.Message = fmt.Sprintf("Consider buying at most %s instead of %s.", formatWholeMoney(r.MaxPurchaseUSD), formatWholeMoney(planned))
+		} else {
+			r.Message = fmt.Sprintf("Consider trimming about %s of crypto to get back to the cap.", formatWholeMoney(r.TrimUSD))
+		}
+	default:
+		r.Status = "within_cap"
+		r.RoomToAddUSD = capUSD - after
+		r.Message = fmt.Sprintf("%.1f%% in crypto is within the %.1f%% cap for a %s investor; up to %s more would still fit.",
+			r.AfterPercent, capPct, risk, formatWholeMoney(r.RoomToAddUSD))
+	}
+	return r
+}
@@ -0,0 +1,158 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/becomeliminal/nim-go-sdk/core"
+	"github.com/becomeliminal/nim-go-sdk/tools"
+)
+
+// ============================================
+// LUMP SUM VS DOLLAR-COST AVERAGING
+// ============================================
+// Both strategies are measured at the same horizon. Money waiting to be
+// dollar-cost averaged sits in the savings vault. The downside case drops the
+// market by drop_percent in month one, then returns to the expected path.
+
+// Lump sum vs DCA defaults and bounds
+const (
+	defaultDCAVolatility = 15.0 // % a year, roughly a stock-heavy portfolio
+	defaultDCADropPct    = 20.0
+	defaultDCAHorizonYrs = 10.0
+	maxDCAMonths         = 36
+	maxDCAVolatilityPct  = 60.0
+	maxDCADropPct        = 90.0
+)
+
+// newLumpSumVsDCATool frames investing a windfall now versus spreading it out as a trade-off
+func newLumpSumVsDCATool() core.Tool {
+	return tools.New("lump_sum_vs_dca_comparison").
+		Description("Compare investing a lump sum immediately versus dollar-cost averaging it over several months: expected outcome, outcome if the market drops right away, and the chance of regretting each choice").
+		Schema(tools.ObjectSchema(map[string]interface{}{
+			"lump_amount":     tools.StringProperty("Amount to invest in the account currency"),
+			"dca_months":      tools.IntegerProperty(fmt.Sprintf("Months to spread the investment over, 2-%d", maxDCAMonths)),
+			"expected_return": tools.StringProperty(fmt.Sprintf("Optional expected annual return percentage (defaults to the server's assumption, currently %g)", appConfig.Assumptions.EquityReturnPct)),
+			"volatility":      tools.StringProperty(fmt.Sprintf("Optional annual volatility percentage (default %.0f)", defaultDCAVolatility)),
+			"drop_percent":    tools.StringProperty(fmt.Sprintf("Optional market drop in the first month for the downside case (default %.0f)", defaultDCADropPct)),
+			"horizon_years":   tools.StringProperty(fmt.Sprintf("Optional years to compare outcomes over (default %.0f)", defaultDCAHorizonYrs)),
+		}, "lump_amount", "dca_months")).
+		Handler(func(ctx context.Context, toolParams *core.ToolParams) (*core.ToolResult, error) {
+			var params struct {
+				LumpAmount     string `json:"lump_amount"`
+				DCAMonths      int    `json:"dca_months"`
+				ExpectedReturn string `json:"expected_return"`
+				Volatility     string `json:"volatility"`
+				DropPercent    string `json:"drop_percent"`
+				HorizonYears   string `json:"horizon_years"`
+			}
+			if err := json.Unmarshal(toolParams.Input, &params); err != nil {
+				return &core.ToolResult{Success: false, Error: fmt.Sprintf("invalid input: %v", err)}, nil
+			}
+
+			var v amountValidator
+			lump := v.positive("lump_amount", params.LumpAmount)
+			if params.DCAMonths < 2 || params.DCAMonths > maxDCAMonths {
+				v.fail("dca_months", "must be between 2 and %d (got %d)", maxDCAMonths, params.DCAMonths)
+			}
+			returnRate := v.returnRate("expected_return", params.ExpectedReturn, false)
+			volatility := optionalPercent(&v, "volatility", params.Volatility, defaultDCAVolatility, maxDCAVolatilityPct)
+			drop := optionalPercent(&v, "drop_percent", params.DropPercent, defaultDCADropPct, maxDCADropPct)
+			horizon := defaultDCAHorizonYrs
+			if strings.TrimSpace(params.HorizonYears) != "" {
+				horizon = v.years("horizon_years", params.HorizonYears, minProjectionYears, maxProjectionYears)
+			}
+			if err := v.err(); err != nil {
+				return &core.ToolResult{Success: false, Error: err.Error()}, nil
+			}
+			if float64(params.DCAMonths) > horizon*12 {
+				return &core.ToolResult{Success: false, Error: fmt.Sprintf("invalid input: dca_months: %d months is longer than the %g-year horizon", params.DCAMonths, horizon)}, nil
+			}
+
+			vaultRates.refresh(ctx, toolParams.UserID)
+			return &core.ToolResult{Success: true, Data: compareLumpSumDCA(lump, params.DCAMonths, returnRate, volatility, drop, horizon)}, nil
+		}).
+		Build()
+}
+
+// optionalPercent parses an optional percentage within [0, hi], using fallback when omitted
+func optionalPercent(v *amountValidator, field, raw string, fallback, hi float64) float64 {
+	if strings.TrimSpace(raw) == "" {
+		return fallback
+	}
+	return v.percent(field, raw, true, hi)
+}
+
+// compareLumpSumDCA projects both strategies in the expected and downside cases
+func compareLumpSumDCA(lump float64, dcaMonths int, returnRate, volatility, drop, horizon float64) LumpSumVsDCAResult {
+	months := int(math.Round(horizon * 12))
+	cashAPY, live := vaultRates.current()
+	tranche := lump / float64(dcaMonths)
+
+	// dca invests one tranche at the start of each month; waiting cash earns the vault rate
+	dca := func(firstMonthShock float64) float64 {
+		total := 0.0
+		waiting := lump
+		for k := 0; k < dcaMonths; k++ {
+			invest := min(tranche, waiting)
+			if k == dcaMonths-1 {
+				invest = waiting
+			}
+			waiting -= invest
+			grown := futureValue(invest, 0, returnRate, float64(months-k))
+			if k == 0 {
+				grown *= firstMonthShock
+			}
+			total += grown
+			waiting = futureValue(waiting, 0, cashAPY, 1)
+		}
+		return total
+	}
+
+	shock := 1 - drop/100
+	r := LumpSumVsDCAResult{
+		Currency:              activeCurrency().Code,
+		LumpAmountUSD:         lump,
+		DCAMonths:             dcaMonths,
+		DCAMonthlyUSD:         tranche,
+		ExpectedReturnPercent: returnRate,
+		VolatilityPercent:     volatility,
+		CashAPY:               cashAPY,
+		CashRateSource:        rateSource(live),
+		DropPercent:           drop,
+		HorizonYears:          horizon,
+		LumpSum: StrategyOutcome{
+			ExpectedUSD: futureValue(lump, 0, returnRate, float64(months)),
+			DownsideUSD: futureValue(lump, 0, returnRate, float64(months)) * shock,
+		},
+		DCA: StrategyOutcome{
+			ExpectedUSD: dca(1),
+			DownsideUSD: dca(shock),
+		},
+		LumpSumTrailsDCAPct: marketTrailsCashProbability(returnRate, volatility, cashAPY, float64(dcaMonths)/2/12),
+	}
+	r.ExpectedAdvantageUSD = r.LumpSum.ExpectedUSD - r.DCA.ExpectedUSD
+	r.DownsideProtectionUSD = r.DCA.DownsideUSD - r.LumpSum.DownsideUSD
+
+	r.TradeOff = fmt.Sprintf("Investing all %s now is expected to end about %s ahead after %g years, because money is in the market longer. "+
+		"Spreading it over %d months gives up that edge in exchange for protection: if the market fell %g%% in month one you'd be about %s better off. "+
+		"Under these assumptions the lump sum comes out ahead about %.0f%% of the time, so DCA mainly buys protection against regret.",
+		formatWholeMoney(lump), formatWholeMoney(r.ExpectedAdvantageUSD), horizon, dcaMonths, drop, formatWholeMoney(r.DownsideProtectionUSD), 100-r.LumpSumTrailsDCAPct)
+	return r
+}
+
+// marketTrailsCashProbability approximates the chance the market underperforms cash over years,
+// treating annual returns as normal: P(Z < (cash - mean) * sqrt(years) / volatility)
+func marketTrailsCashProbability(meanPct, volatilityPct, cashPct, years float64) float64 {
+	if volatilityPct == 0 {
+		if meanPct < cashPct {
+			return 100
+		}
+		return 0
+	}
+	z := (cashPct - meanPct) * math.Sqrt(years) / volatilityPct
+	return 50 * (1 + math.Erf(z/math.Sqrt2))
+}
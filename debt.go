@@ -0,0 +1,249 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/becomeliminal/nim-go-sdk/core"
+	"github.com/becomeliminal/nim-go-sdk/tools"
+)
+
+// ============================================
+// DEBT VS INVEST
+// ============================================
+// Paying down a debt is a guaranteed return equal to its APR, so any debt
+// costing more than the expected market return (or above the high-APR
+// threshold) gets the spare money first, highest APR first.
+
+// Debt-vs-invest scenario names
+const (
+	scenarioInvestAll = "invest_everything"
+	scenarioDebtFirst = "pay_debt_first"
+	scenarioHybrid    = "recommended_hybrid"
+)
+
+// debtProjectionMonths is how far debt_vs_invest_analyzer simulates: 10 years
+const debtProjectionMonths = 120
+
+// maxDebtAPRPct is the highest APR a debt may be entered with
+const maxDebtAPRPct = 100.0
+
+// debt is one balance being paid down
+type debt struct {
+	Name           string
+	Balance        float64
+	APR            float64
+	MinimumPayment float64
+}
+
+// newDebtVsInvestTool weighs paying down debt against investing the same money
+func newDebtVsInvestTool() core.Tool {
+	return tools.New("debt_vs_invest_analyzer").
+		Description("Decide how to split a monthly amount between paying down debts and investing: compares each debt's APR to the expected return, orders payoffs, and projects net worth at 5 and 10 years for investing everything, paying debt first, and the recommended hybrid").
+		Schema(tools.ObjectSchema(map[string]interface{}{
+			"monthly_amount": tools.StringProperty("Total available each month for debt payments and investing in the account currency, including minimum payments"),
+			"debts": map[string]interface{}{
+				"type":        "array",
+				"description": "The user's debts",
+				"items": tools.ObjectSchema(map[string]interface{}{
+					"name":            tools.StringProperty("Debt name, unique within the list, e.g. 'Visa card', 'Car loan'"),
+					"balance":         tools.StringProperty("Outstanding balance in the account currency"),
+					"apr":             tools.StringProperty("Annual percentage rate from 0 to 100 (e.g., '24.9')"),
+					"minimum_payment": tools.StringProperty("Required minimum monthly payment in the account currency"),
+				}, "name", "balance", "apr", "minimum_payment"),
+			},
+			"expected_return": tools.StringProperty(fmt.Sprintf("Optional expected annual investment return percentage (defaults to the server's assumption, currently %g)", appConfig.Assumptions.EquityReturnPct)),
+		}, "monthly_amount", "debts")).
+		HandlerFunc(func(ctx context.Context, input json.RawMessage) (interface{}, error) {
+			var params struct {
+				MonthlyAmount string `json:"monthly_amount"`
+				Debts         []struct {
+					Name           string `json:"name"`
+					Balance        string `json:"balance"`
+					APR            string `json:"apr"`
+					MinimumPayment string `json:"minimum_payment"`
+				} `json:"debts"`
+				ExpectedReturn string `json:"expected_return"`
+			}
+			if err := json.Unmarshal(input, &params); err != nil {
+				return nil, fmt.Errorf("invalid input: %w", err)
+			}
+
+			var v amountValidator
+			monthly := v.positive("monthly_amount", params.MonthlyAmount)
+			returnRate := v.returnRate("expected_return", params.ExpectedReturn, false)
+			if len(params.Debts) == 0 {
+				v.fail("debts", "at least one debt is required")
+			}
+			debts := make([]debt, 0, len(params.Debts))
+			minimums := 0.0
+			seen := make(map[string]int, len(params.Debts)) // payoff months are keyed by name
+			for i, d := range params.Debts {
+				field := fmt.Sprintf("debts[%d]", i)
+				name := strings.TrimSpace(d.Name)
+				if name == "" {
+					v.fail(field+".name", "is required")
+				} else if first, ok := seen[name]; ok {
+					v.fail(field+".name", "%q is already debts[%d]; give each debt a distinct name", name, first)
+				} else {
+					seen[name] = i
+				}
+				item := debt{
+					Name:           name,
+					Balance:        v.positive(field+".balance", d.Balance),
+					APR:            v.percent(field+".apr", d.APR, true, maxDebtAPRPct),
+					MinimumPayment: v.nonNegative(field+".minimum_payment", d.MinimumPayment, true),
+				}
+				minimums += item.MinimumPayment
+				debts = append(debts, item)
+			}
+			if err := v.err(); err != nil {
+				return nil, err
+			}
+			if monthly < minimums {
+				return nil, fmt.Errorf("invalid input: monthly_amount: %s does not cover the %s of minimum payments", formatMoney(monthly), formatMoney(minimums))
+			}
+
+			return analyzeDebtVsInvest(debts, monthly, returnRate), nil
+		}).
+		Build()
+}
+
+// analyzeDebtVsInvest classifies each debt and simulates the three scenarios
+func analyzeDebtVsInvest(debts []debt, monthly, returnRate float64) DebtVsInvestResult {
+	// Avalanche order: highest APR first
+	sort.SliceStable(debts, func(i, j int) bool { return debts[i].APR > debts[j].APR })
+
+	threshold := appConfig.HighAPRThreshold
+	priority := func(d debt) bool { return d.APR >= threshold || d.APR > returnRate }
+
+	scenarios := map[string]DebtScenario{
+		scenarioInvestAll: simulateDebtScenario(debts, monthly, returnRate, func(debt) bool { return false }),
+		scenarioDebtFirst: simulateDebtScenario(debts, monthly, returnRate, func(debt) bool { return true }),
+		scenarioHybrid:    simulateDebtScenario(debts, monthly, returnRate, priority),
+	}
+	hybrid := scenarios[scenarioHybrid]
+
+	result := DebtVsInvestResult{
+		Currency:              activeCurrency().Code,
+		MonthlyAmountUSD:      monthly,
+		ExpectedReturnPercent: returnRate,
+		HighAPRThreshold:      threshold,
+		Scenarios:             scenarios,
+		Recommended:           scenarioHybrid,
+	}
+	extra := monthly
+	for _, d := range debts {
+		extra -= d.MinimumPayment
+	}
+	for i, d := range debts {
+		a := DebtAssessment{
+			Order:                 i + 1,
+			Name:                  d.Name,
+			BalanceUSD:            d.Balance,
+			APR:                   d.APR,
+			GuaranteedReturnPct:   d.APR,
+			MinimumPaymentUSD:     d.MinimumPayment,
+			Priority:              priority(d),
+			PayoffMonth:           hybrid.PayoffMonths[d.Name],
+			FirstMonthPaymentUSD:  d.MinimumPayment,
+			MinimumCoversInterest: d.MinimumPayment > d.Balance*d.APR/100/12,
+		}
+		switch {
+		case d.APR >= threshold:
+			a.Action = "pay_down_first"
+			a.Reason = fmt.Sprintf("%.1f%% APR is at or above the %.0f%% high-interest threshold - always pay this down first", d.APR, threshold)
+		case d.APR > returnRate:
+			a.Action = "pay_down_first"
+			a.Reason = fmt.Sprintf("Paying this off is a guaranteed %.1f%% return, more than the %.1f%% expected from investing", d.APR, returnRate)
+		default:
+			a.Action = "minimum_only"
+			a.Reason = fmt.Sprintf("%.1f%% APR is below the %.1f%% expected investment return - pay the minimum and invest the rest", d.APR, returnRate)
+		}
+		if a.Priority && extra > 0 {
+			a.FirstMonthPaymentUSD += extra
+			extra = 0
+		}
+		result.Debts = append(result.Debts, a)
+	}
+	result.FirstMonthInvestUSD = extra
+
+	result.Message = fmt.Sprintf("Recommended: %s. Net worth after 10 years: %s (hybrid) vs %s (invest everything) vs %s (pay debt first).",
+		debtPlanSummary(result.Debts), formatWholeMoney(hybrid.NetWorth10YrUSD), formatWholeMoney(scenarios[scenarioInvestAll].NetWorth10YrUSD), formatWholeMoney(scenarios[scenarioDebtFirst].NetWorth10YrUSD))
+	return result
+}
+
+// debtPlanSummary says which debts get extra payments
+func debtPlanSummary(assessed []DebtAssessment) string {
+	var first []string
+	for _, a := range assessed {
+		if a.Priority {
+			first = append(first, a.Name)
+		}
+	}
+	if len(first) == 0 {
+		return "pay minimums and invest the rest"
+	}
+	return fmt.Sprintf("put everything above the minimums toward %s (highest APR first), then invest", strings.Join(first, ", "))
+}
+
+// simulateDebtScenario runs debtProjectionMonths of payments. Minimums are always paid;
+// the rest goes to the highest-APR debt that extra accepts, then into investments.
+func simulateDebtScenario(debts []debt, monthly, returnRate float64, extra func(debt) bool) DebtScenario {
+	balances := make([]float64, len(debts))
+	for i, d := range debts {
+		balances[i] = d.Balance
+	}
+	invested := 0.0
+	monthlyReturn := returnRate / 100 / 12
+	s := DebtScenario{PayoffMonths: make(map[string]int)}
+
+	for m := 1; m <= debtProjectionMonths; m++ {
+		available := monthly
+		invested *= 1 + monthlyReturn
+		for i, d := range debts {
+			if balances[i] <= 0 {
+				continue
+			}
+			balances[i] *= 1 + d.APR/100/12
+			pay := min(d.MinimumPayment, balances[i])
+			balances[i] -= pay
+			available -= pay
+		}
+		for i, d := range debts {
+			if balances[i] <= 0 || !extra(d) || available <= 0 {
+				continue
+			}
+			pay := min(available, balances[i])
+			balances[i] -= pay
+			available -= pay
+		}
+		invested += available
+
+		owed := 0.0
+		for i, d := range debts {
+			if balances[i] <= 0.005 {
+				balances[i] = 0
+				if _, ok := s.PayoffMonths[d.Name]; !ok {
+					s.PayoffMonths[d.Name] = m
+				}
+			}
+			owed += balances[i]
+		}
+		if owed == 0 && s.DebtFreeMonth == 0 {
+			s.DebtFreeMonth = m
+		}
+		switch m {
+		case 60:
+			s.NetWorth5YrUSD = invested - owed
+		case debtProjectionMonths:
+			s.NetWorth10YrUSD = invested - owed
+			s.InvestedUSD = invested
+			s.RemainingDebtUSD = owed
+		}
+	}
+	return s
+}
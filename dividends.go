@@ -0,0 +1,128 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/becomeliminal/nim-go-sdk/core"
+	"github.com/becomeliminal/nim-go-sdk/tools"
+)
+
+// ============================================
+// DIVIDEND INCOME
+// ============================================
+// expected_return is the total return. With dividends reinvested (DRIP) the
+// portfolio compounds at the full total return; with them paid out it only
+// grows by the price return (total return minus yield).
+
+// Dividend yield assumptions, in percent
+const (
+	defaultDividendYield   = 3.5 // a broad dividend-focused ETF
+	realisticDividendYield = 7.0 // above this, yields usually signal risk of a cut
+	maxDividendYield       = 20.0
+)
+
+// newDividendIncomeTool works out the portfolio needed for a monthly dividend income and how long it takes
+func newDividendIncomeTool() core.Tool {
+	return tools.New("dividend_income_planner").
+		Description("Work out how large a portfolio is needed to earn a target monthly dividend income, and how many years of monthly saving get there with dividends reinvested (DRIP) or paid out").
+		Schema(tools.ObjectSchema(map[string]interface{}{
+			"target_monthly_income": tools.StringProperty("Desired monthly dividend income in the account currency"),
+			"dividend_yield":        tools.StringProperty(fmt.Sprintf("Optional portfolio dividend yield percentage (default %.1f)", defaultDividendYield)),
+			"monthly_contribution":  tools.StringProperty("Amount invested each month in the account currency"),
+			"current_savings":       tools.StringProperty("Optional amount already invested in the account currency"),
+			"expected_return":       tools.StringProperty(fmt.Sprintf("Optional expected total annual return percentage including dividends (defaults to the server's assumption, currently %g)", appConfig.Assumptions.EquityReturnPct)),
+			"years":                 tools.StringProperty("Optional years to reach the income; adds the monthly amount needed to get there in time"),
+		}, "target_monthly_income", "monthly_contribution")).
+		HandlerFunc(func(ctx context.Context, input json.RawMessage) (interface{}, error) {
+			var params struct {
+				TargetMonthlyIncome string `json:"target_monthly_income"`
+				DividendYield       string `json:"dividend_yield"`
+				MonthlyContribution string `json:"monthly_contribution"`
+				CurrentSavings      string `json:"current_savings"`
+				ExpectedReturn      string `json:"expected_return"`
+				Years               string `json:"years"`
+			}
+			if err := json.Unmarshal(input, &params); err != nil {
+				return nil, fmt.Errorf("invalid input: %w", err)
+			}
+
+			var v amountValidator
+			income := v.positive("target_monthly_income", params.TargetMonthlyIncome)
+			yield := defaultDividendYield
+			if strings.TrimSpace(params.DividendYield) != "" {
+				yield = v.positiveRate("dividend_yield", params.DividendYield)
+				if yield > maxDividendYield {
+					v.fail("dividend_yield", "must be at most %.0f%% (got %v%%)", maxDividendYield, yield)
+				}
+			}
+			monthly := v.nonNegative("monthly_contribution", params.MonthlyContribution, true)
+			current := v.nonNegative("current_savings", params.CurrentSavings, false)
+			returnRate := v.returnRate("expected_return", params.ExpectedReturn, false)
+			years := 0.0
+			if strings.TrimSpace(params.Years) != "" {
+				years = v.years("years", params.Years, minProjectionYears, maxProjectionYears)
+			}
+			if err := v.err(); err != nil {
+				return nil, err
+			}
+
+			return dividendIncomePlan(income, yield, monthly, current, returnRate, years), nil
+		}).
+		Build()
+}
+
+// dividendIncomePlan sizes the portfolio and times both reinvestment choices. years 0 skips the solver.
+func dividendIncomePlan(income, yield, monthly, current, returnRate, years float64) DividendIncomeResult {
+	required := income * 12 / (yield / 100)
+	priceReturn := returnRate - yield
+
+	r := DividendIncomeResult{
+		Currency:               activeCurrency().Code,
+		TargetMonthlyIncomeUSD: income,
+		DividendYieldPercent:   yield,
+		TotalReturnPercent:     returnRate,
+		RequiredPortfolioUSD:   required,
+		MonthlyContribution:    monthly,
+		CurrentSavingsUSD:      current,
+		Reinvested:             dividendTimeline(required, current, monthly, returnRate),
+		PaidOut:                dividendTimeline(required, current, monthly, priceReturn),
+	}
+	if yield > realisticDividendYield {
+		r.YieldWarning = fmt.Sprintf("A %.1f%% yield is unusually high; yields above about %.0f%% often come before a dividend cut or price drop. Consider planning with %.1f%%.",
+			yield, realisticDividendYield, defaultDividendYield)
+	}
+	if years > 0 {
+		solved := requiredContribution(required, current, returnRate, years, monthly)
+		r.RequiredMonthly = &solved
+	}
+
+	switch {
+	case r.Reinvested.Months == 0:
+		r.Message = fmt.Sprintf("%s at a %.1f%% yield already pays about %s/month.", formatWholeMoney(current), yield, formatWholeMoney(current*yield/100/12))
+	case r.Reinvested.Months < 0:
+		r.Message = fmt.Sprintf("%s/month in dividends needs %s invested; %s/month of saving doesn't get there within 100 years.", formatWholeMoney(income), formatWholeMoney(required), formatWholeMoney(monthly))
+	default:
+		r.Message = fmt.Sprintf("%s/month in dividends needs about %s invested at a %.1f%% yield. Saving %s/month gets there in %.1f years with dividends reinvested",
+			formatWholeMoney(income), formatWholeMoney(required), yield, formatWholeMoney(monthly), r.Reinvested.Years)
+		if r.PaidOut.Months > 0 {
+			r.Message += fmt.Sprintf(" vs %.1f years taking them as cash - DRIP saves %.1f years.", r.PaidOut.Years, r.PaidOut.Years-r.Reinvested.Years)
+		} else {
+			r.Message += "; taking them as cash instead, it wouldn't get there within 100 years."
+		}
+	}
+	return r
+}
+
+// dividendTimeline is how long saving reaches target at growthRate; Months is -1 if not within 100 years
+func dividendTimeline(target, current, monthly, growthRate float64) DividendTimeline {
+	months := monthsToReach(target, current, monthly, growthRate)
+	t := DividendTimeline{GrowthPercent: growthRate, Months: months}
+	if months >= 0 {
+		t.Years = math.Round(float64(months)/12*10) / 10
+	}
+	return t
+}
@@ -0,0 +1,226 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"slices"
+	"strings"
+
+	"github.com/becomeliminal/nim-go-sdk/core"
+	"github.com/becomeliminal/nim-go-sdk/tools"
+)
+
+// ============================================
+// DRAWDOWN ESTIMATE
+// ============================================
+// Drawdowns come from the same bundled calendar-year history as
+// historical_backtest, with the allocation rebalanced every January. A decline
+// starts at a year-end high, bottoms at the lowest later year-end and ends the
+// first year-end back above the old high. Year-end data understates drops that
+// partly recovered within the year (2008's intra-year fall was deeper).
+
+// minTypicalDrawdownPct keeps year-end wobbles out of the typical decline and recovery
+const minTypicalDrawdownPct = 5.0
+
+// drawdownEpisodes lists every year-end peak-to-trough decline for weights over years, in order
+func drawdownEpisodes(years []historicalYear, weights map[string]float64) []DrawdownEpisode {
+	episodes := []DrawdownEpisode{}
+	growth, peak, peakYear := 1.0, 1.0, years[0].Year-1
+	var current *DrawdownEpisode
+	for _, y := range years {
+		growth *= 1 + blendedYearReturn(y, weights)/100
+		if growth >= peak {
+			if current != nil {
+				current.Recovered = true
+				current.RecoveryYear = y.Year
+				current.RecoveryYears = y.Year - current.TroughYear
+				episodes = append(episodes, *current)
+				current = nil
+			}
+			peak, peakYear = growth, y.Year
+			continue
+		}
+		depth := (1 - growth/peak) * 100
+		if current == nil {
+			current = &DrawdownEpisode{PeakYear: peakYear}
+		}
+		if depth > current.DepthPercent {
+			current.DepthPercent, current.TroughYear = depth, y.Year
+		}
+	}
+	if current != nil {
+		episodes = append(episodes, *current)
+	}
+	return episodes
+}
+
+// median of values; zero when empty
+func median(values []float64) float64 {
+	if len(values) == 0 {
+		return 0
+	}
+	sorted := slices.Clone(values)
+	slices.Sort(sorted)
+	mid := len(sorted) / 2
+	if len(sorted)%2 == 0 {
+		return (sorted[mid-1] + sorted[mid]) / 2
+	}
+	return sorted[mid]
+}
+
+// newDrawdownTool turns an allocation's worst historical decline into the user's dollars
+func newDrawdownTool() core.Tool {
+	first, last := historicalReturns[0].Year, historicalReturns[len(historicalReturns)-1].Year
+	return tools.New("max_drawdown_estimate").
+		Description(fmt.Sprintf("Estimate how far a portfolio with a given allocation (or risk level) could fall from a high, using actual %d-%d market history: the worst peak-to-trough drop in the user's own dollars, how long it took to recover, and what a typical decline looks like. Use it to make 'moderate' vs 'aggressive' concrete", first, last)).
+		Schema(tools.ObjectSchema(map[string]interface{}{
+			"portfolio_value":       tools.StringProperty("Optional portfolio value in the account currency; omit to use the user's profile balance"),
+			"stocks_percent":        tools.StringProperty("Optional percentage in US stocks"),
+			"international_percent": tools.StringProperty("Optional percentage in international stocks"),
+			"reit_percent":          tools.StringProperty("Optional percentage in real estate (REITs)"),
+			"bonds_percent":         tools.StringProperty("Optional percentage in bonds"),
+			"cash_percent":          tools.StringProperty("Optional percentage in cash"),
+			"risk_level":            tools.StringProperty("Optional risk level whose target allocation to use when no percentages are given; omit to use the user's profile"),
+		})).
+		Handler(func(ctx context.Context, toolParams *core.ToolParams) (*core.ToolResult, error) {
+			var params struct {
+				PortfolioValue       string `json:"portfolio_value"`
+				StocksPercent        string `json:"stocks_percent"`
+				InternationalPercent string `json:"international_percent"`
+				REITPercent          string `json:"reit_percent"`
+				BondsPercent         string `json:"bonds_percent"`
+				CashPercent          string `json:"cash_percent"`
+				RiskLevel            string `json:"risk_level"`
+			}
+			if err := json.Unmarshal(toolParams.Input, &params); err != nil {
+				return &core.ToolResult{Success: false, Error: fmt.Sprintf("invalid input: %v", err)}, nil
+			}
+
+			var v amountValidator
+			value := 0.0
+			if strings.TrimSpace(params.PortfolioValue) != "" {
+				value = v.positive("portfolio_value", params.PortfolioValue)
+			}
+			raw := map[string]string{
+				"stocks":        params.StocksPercent,
+				"international": params.InternationalPercent,
+				"reit":          params.REITPercent,
+				"bonds":         params.BondsPercent,
+				"cash":          params.CashPercent,
+			}
+			weights := map[string]float64{}
+			total := 0.0
+			for _, asset := range rebalanceAssets {
+				if strings.TrimSpace(raw[asset]) == "" {
+					continue
+				}
+				weights[asset] = optionalPercent(&v, asset+"_percent", raw[asset], 0, 100)
+				total += weights[asset]
+			}
+			if len(v.errs) == 0 && len(weights) > 0 && math.Abs(total-100) > 1 {
+				v.fail("allocation", "percentages add up to %g%%, not 100%%", total)
+			}
+			if err := v.err(); err != nil {
+				return &core.ToolResult{Success: false, Error: err.Error()}, nil
+			}
+
+			allocationSource := "user_provided"
+			if len(weights) == 0 {
+				level, source, err := resolveRiskLevel(ctx, toolParams.UserID, "risk_level", params.RiskLevel)
+				if err != nil {
+					return &core.ToolResult{Success: false, Error: err.Error()}, nil
+				}
+				weights, allocationSource = targetAllocationTable[level], source
+			}
+			valueSource := "user_provided"
+			if value == 0 {
+				portfolio, err := loadPortfolio(ctx, toolParams.UserID)
+				if err != nil {
+					return &core.ToolResult{Success: false, Error: profileFieldError(err, "portfolio_value").Error()}, nil
+				}
+				value, valueSource = portfolio.TotalBalance, "profile"
+			}
+
+			result := estimateDrawdown(historicalReturns, weights, value)
+			result.AllocationSource = allocationSource
+			result.ValueSource = valueSource
+			return &core.ToolResult{Success: true, Data: result}, nil
+		}).
+		Build()
+}
+
+// estimateDrawdown measures weights' declines over years and applies the worst one to value
+func estimateDrawdown(years []historicalYear, weights map[string]float64, value float64) DrawdownResult {
+	r := DrawdownResult{
+		Currency:          activeCurrency().Code,
+		Allocation:        weights,
+		PortfolioValueUSD: value,
+		FirstYear:         years[0].Year,
+		LastYear:          years[len(years)-1].Year,
+		Episodes:          drawdownEpisodes(years, weights),
+		ByRiskLevel:       make(map[RiskLevel]float64, len(targetAllocationTable)),
+		DataNote:          "Calendar-year total returns (S&P 500, also standing in for international and REIT slices; Bloomberg US Aggregate bonds; 3-month Treasury bills), rebalanced every January. Drops that partly recovered within a year look smaller than they felt.",
+	}
+	for level, w := range targetAllocationTable {
+		for _, e := range drawdownEpisodes(years, w) {
+			r.ByRiskLevel[level] = math.Max(r.ByRiskLevel[level], e.DepthPercent)
+		}
+	}
+
+	var depths, recoveries []float64
+	worst := -1
+	for i, e := range r.Episodes {
+		if e.DepthPercent >= minTypicalDrawdownPct {
+			depths = append(depths, e.DepthPercent)
+			if e.Recovered {
+				recoveries = append(recoveries, float64(e.RecoveryYears))
+			}
+		}
+		if worst < 0 || e.DepthPercent > r.Episodes[worst].DepthPercent {
+			worst = i
+		}
+	}
+	r.TypicalDrawdownPercent = median(depths)
+	r.TypicalRecoveryYears = median(recoveries)
+
+	allocation := formatWeights(weights)
+	if worst < 0 {
+		r.Message = fmt.Sprintf("Invested %s, a portfolio never ended a year below a previous year-end high between %d and %d.", allocation, r.FirstYear, r.LastYear)
+		return r
+	}
+	w := r.Episodes[worst]
+	r.WorstDrawdownPercent = w.DepthPercent
+	r.WorstPeakYear, r.WorstTroughYear = w.PeakYear, w.TroughYear
+	r.WorstLossUSD = value * w.DepthPercent / 100
+	r.ValueAtTroughUSD = value - r.WorstLossUSD
+	r.Message = fmt.Sprintf("Invested %s, your %s could have fallen to about %s (-%.0f%%) between the end of %d and the end of %d",
+		allocation, formatWholeMoney(value), formatWholeMoney(r.ValueAtTroughUSD), w.DepthPercent, w.PeakYear, w.TroughYear)
+	if w.Recovered {
+		r.WorstRecoveryYears = w.RecoveryYears
+		r.Message += fmt.Sprintf(", and was back above its old high %d year(s) after the bottom", w.RecoveryYears)
+	} else {
+		r.Message += ", and had not yet recovered by the end of the data"
+	}
+	if len(depths) > 1 {
+		r.Message += fmt.Sprintf(". Across the %d declines of %g%% or more since %d the typical drop was %.0f%%", len(depths), minTypicalDrawdownPct, r.FirstYear, r.TypicalDrawdownPercent)
+	}
+	if len(depths) > 1 && len(recoveries) > 0 {
+		r.Message += fmt.Sprintf(", back above the old high about %.0f year(s) after the bottom", r.TypicalRecoveryYears)
+	}
+	r.Message += ". Staying invested through those drops is what let the portfolio recover; past declines don't cap future ones."
+	return r
+}
+
+// formatWeights renders an allocation such as "80/20 stocks/bonds"
+func formatWeights(weights map[string]float64) string {
+	var pcts, names []string
+	for _, asset := range rebalanceAssets {
+		if pct := weights[asset]; pct > 0 {
+			pcts = append(pcts, fmt.Sprintf("%g", math.Round(pct*10)/10))
+			names = append(names, asset)
+		}
+	}
+	return strings.Join(pcts, "/") + " " + strings.Join(names, "/")
+}
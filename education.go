@@ -0,0 +1,129 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/becomeliminal/nim-go-sdk/core"
+	"github.com/becomeliminal/nim-go-sdk/tools"
+)
+
+// ============================================
+// EDUCATION SAVINGS
+// ============================================
+
+// Education savings defaults
+const (
+	defaultCollegeStartAge  = 18
+	defaultCollegeYears     = 4
+	defaultTuitionInflation = 5.0 // % a year; college costs have historically outpaced general inflation
+	maxChildAge             = 30
+)
+
+// newEducationSavingsTool projects college costs and the monthly saving that covers them
+func newEducationSavingsTool() core.Tool {
+	return tools.New("education_savings_projection").
+		Description("Project the future cost of a child's college and how much to save monthly (529-style) to cover it, with the shortfall if the family saves a different amount").
+		Schema(tools.ObjectSchema(map[string]interface{}{
+			"child_age":         tools.IntegerProperty("Child's current age"),
+			"college_start_age": tools.IntegerProperty(fmt.Sprintf("Optional age the child starts college (default %d)", defaultCollegeStartAge)),
+			"annual_cost_today": tools.StringProperty("Estimated annual college cost in today's dollars (tuition, room and board) in the account currency"),
+			"tuition_inflation": tools.StringProperty(fmt.Sprintf("Optional annual college cost inflation percentage (default %.0f)", defaultTuitionInflation)),
+			"expected_return":   tools.StringProperty(fmt.Sprintf("Optional expected annual return percentage (defaults to the server's assumption, currently %g)", appConfig.Assumptions.EquityReturnPct)),
+			"current_savings":   tools.StringProperty("Optional amount already saved for college in the account currency"),
+			"planned_monthly":   tools.StringProperty("Optional amount the family plans to save each month in the account currency, to show any shortfall"),
+		}, "child_age", "annual_cost_today")).
+		HandlerFunc(func(ctx context.Context, input json.RawMessage) (interface{}, error) {
+			var params struct {
+				ChildAge         int    `json:"child_age"`
+				CollegeStartAge  int    `json:"college_start_age"`
+				AnnualCostToday  string `json:"annual_cost_today"`
+				TuitionInflation string `json:"tuition_inflation"`
+				ExpectedReturn   string `json:"expected_return"`
+				CurrentSavings   string `json:"current_savings"`
+				PlannedMonthly   string `json:"planned_monthly"`
+			}
+			if err := json.Unmarshal(input, &params); err != nil {
+				return nil, fmt.Errorf("invalid input: %w", err)
+			}
+			if params.CollegeStartAge == 0 {
+				params.CollegeStartAge = defaultCollegeStartAge
+			}
+
+			var v amountValidator
+			if params.ChildAge < 0 || params.ChildAge > maxChildAge {
+				v.fail("child_age", "must be between 0 and %d (got %d)", maxChildAge, params.ChildAge)
+			}
+			if params.CollegeStartAge <= params.ChildAge || params.CollegeStartAge > maxChildAge {
+				v.fail("college_start_age", "must be after child_age and at most %d (got %d)", maxChildAge, params.CollegeStartAge)
+			}
+			cost := v.positive("annual_cost_today", params.AnnualCostToday)
+			inflation := defaultTuitionInflation
+			if strings.TrimSpace(params.TuitionInflation) != "" {
+				inflation = v.inflationRate("tuition_inflation", params.TuitionInflation)
+			}
+			returnRate := v.returnRate("expected_return", params.ExpectedReturn, false)
+			current := v.nonNegative("current_savings", params.CurrentSavings, false)
+			planned := v.nonNegative("planned_monthly", params.PlannedMonthly, false)
+			if err := v.err(); err != nil {
+				return nil, err
+			}
+
+			result := educationSavings(params.ChildAge, params.CollegeStartAge, cost, inflation, returnRate, current)
+			if strings.TrimSpace(params.PlannedMonthly) != "" {
+				result.setPlanned(planned)
+			}
+			return result, nil
+		}).
+		Build()
+}
+
+// educationSavings inflates each college year's cost and solves for the monthly contribution
+// that has the whole amount saved by the first year
+func educationSavings(childAge, startAge int, costToday, inflation, returnRate, current float64) EducationSavingsResult {
+	years := startAge - childAge
+	var yearly []float64
+	total := 0.0
+	for k := 0; k < defaultCollegeYears; k++ {
+		c := costToday * math.Pow(1+inflation/100, float64(years+k))
+		yearly = append(yearly, c)
+		total += c
+	}
+
+	// Shared with solve_required_contribution; capacity is unknown here, so feasibility is left to setPlanned
+	solved := requiredContribution(total, current, returnRate, float64(years), math.Inf(1))
+	return EducationSavingsResult{
+		Currency:                activeCurrency().Code,
+		ChildAge:                childAge,
+		CollegeStartAge:         startAge,
+		YearsToCollege:          years,
+		AnnualCostTodayUSD:      costToday,
+		TuitionInflationPercent: inflation,
+		AssumedReturnPercent:    returnRate,
+		YearlyCostsUSD:          yearly,
+		TotalCostTodayUSD:       costToday * defaultCollegeYears,
+		TotalFutureCostUSD:      total,
+		CurrentSavingsUSD:       current,
+		RequiredMonthlyUSD:      solved.RequiredMonthlyUSD,
+		Sensitivity:             solved.Sensitivity,
+		Message: fmt.Sprintf("%d years of college starting in %d years will cost about %s (vs %s today). Save %s/month to cover it.",
+			defaultCollegeYears, years, formatWholeMoney(total), formatWholeMoney(costToday*defaultCollegeYears), formatMoney(solved.RequiredMonthlyUSD)),
+	}
+}
+
+// setPlanned reports what the planned monthly amount reaches and any shortfall
+func (r *EducationSavingsResult) setPlanned(planned float64) {
+	projected := futureValue(r.CurrentSavingsUSD, planned, r.AssumedReturnPercent, float64(r.YearsToCollege*12))
+	r.PlannedMonthlyUSD = &planned
+	r.PlannedProjectedUSD = projected
+	r.ShortfallUSD = max(r.TotalFutureCostUSD-projected, 0)
+	if r.ShortfallUSD > 0 {
+		r.Message += fmt.Sprintf(" Saving %s/month instead reaches %s, leaving a %s shortfall (%.0f%% covered).",
+			formatMoney(planned), formatWholeMoney(projected), formatWholeMoney(r.ShortfallUSD), projected/r.TotalFutureCostUSD*100)
+	} else {
+		r.Message += fmt.Sprintf(" Your planned %s/month covers it, reaching %s.", formatMoney(planned), formatWholeMoney(projected))
+	}
+}
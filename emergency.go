@@ -0,0 +1,177 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/becomeliminal/nim-go-sdk/core"
+	"github.com/becomeliminal/nim-go-sdk/tools"
+)
+
+// ============================================
+// EMERGENCY FUND
+// ============================================
+
+// emergencyMonthsByStability is how many months of expenses to hold for each income stability
+var emergencyMonthsByStability = map[string]int{
+	"stable":   3,
+	"moderate": 6,
+	"unstable": 12,
+}
+
+// incomeStabilities lists emergencyMonthsByStability's keys for validation
+var incomeStabilities = []string{"stable", "moderate", "unstable"}
+
+// maxFundingScheduleMonths caps the month-by-month schedule at 10 years
+const maxFundingScheduleMonths = 120
+
+// newEmergencyFundTool sizes an emergency fund from real spending and schedules funding it
+func newEmergencyFundTool(liminalExecutor core.ToolExecutor) core.Tool {
+	return tools.New("emergency_fund_calculator").
+		Description("Size the user's emergency fund from their real spending and income stability, report current coverage in months, and build a month-by-month funding schedule").
+		Schema(tools.ObjectSchema(map[string]interface{}{
+			"income_stability": tools.StringProperty("Income stability: 'stable' (3 months), 'moderate' (6 months), 'unstable' (12 months)"),
+			"monthly_savings":  tools.StringProperty("Amount the user can put toward the fund each month in the account currency"),
+			"monthly_expenses": tools.StringProperty("Optional average monthly spending in the account currency; omit to derive it from transaction history"),
+			"current_savings":  tools.StringProperty("Optional amount already set aside in the account currency; omit to use the savings balance"),
+		}, "income_stability", "monthly_savings")).
+		Handler(func(ctx context.Context, toolParams *core.ToolParams) (*core.ToolResult, error) {
+			var params struct {
+				IncomeStability string `json:"income_stability"`
+				MonthlySavings  string `json:"monthly_savings"`
+				MonthlyExpenses string `json:"monthly_expenses"`
+				CurrentSavings  string `json:"current_savings"`
+			}
+			if err := json.Unmarshal(toolParams.Input, &params); err != nil {
+				return &core.ToolResult{Success: false, Error: fmt.Sprintf("invalid input: %v", err)}, nil
+			}
+
+			var v amountValidator
+			stability := v.oneOf("income_stability", params.IncomeStability, incomeStabilities)
+			capacity := v.nonNegative("monthly_savings", params.MonthlySavings, true)
+			current := v.nonNegative("current_savings", params.CurrentSavings, false)
+			expenses := 0.0
+			if strings.TrimSpace(params.MonthlyExpenses) != "" {
+				expenses = v.positive("monthly_expenses", params.MonthlyExpenses)
+			}
+			if err := v.err(); err != nil {
+				return &core.ToolResult{Success: false, Error: err.Error()}, nil
+			}
+
+			expenseSource := "user_reported"
+			if strings.TrimSpace(params.MonthlyExpenses) == "" {
+				spend, ok := fetchMonthlySpend(ctx, liminalExecutor, toolParams.UserID, time.Now())
+				if !ok {
+					return &core.ToolResult{Success: false, Error: "could not derive spending from transaction history: pass monthly_expenses"}, nil
+				}
+				expenses, expenseSource = spend, "transaction_history"
+			}
+			savingsSource := "user_reported"
+			if strings.TrimSpace(params.CurrentSavings) == "" {
+				savingsSource = "savings_balance"
+				if balance, ok := fetchSavingsBalance(ctx, liminalExecutor, toolParams.UserID); ok {
+					current = balance
+				} else {
+					savingsSource = "unavailable"
+				}
+			}
+
+			vaultRates.refresh(ctx, toolParams.UserID)
+			result := emergencyFundPlan(stability, expenses, current, capacity, time.Now())
+			result.ExpenseSource = expenseSource
+			result.SavingsSource = savingsSource
+			return &core.ToolResult{Success: true, Data: result}, nil
+		}).
+		Build()
+}
+
+// emergencyFundPlan sizes the fund and schedules deposits, growing the balance at the vault APY
+func emergencyFundPlan(stability string, expenses, current, capacity float64, now time.Time) EmergencyFundResult {
+	months := emergencyMonthsByStability[stability]
+	target := expenses * float64(months)
+	apy, live := vaultRates.current()
+
+	r := EmergencyFundResult{
+		Currency:           activeCurrency().Code,
+		IncomeStability:    stability,
+		TargetMonths:       months,
+		MonthlyExpensesUSD: expenses,
+		TargetAmountUSD:    target,
+		CurrentSavingsUSD:  current,
+		CoverageMonths:     current / expenses,
+		MonthlySavingsUSD:  capacity,
+		VaultAPY:           apy,
+		VaultRateSource:    rateSource(live),
+		Schedule:           []EmergencyFundMonth{},
+	}
+
+	if current >= target {
+		r.Status = "fully_funded"
+		r.Message = fmt.Sprintf("Fully funded: %s covers %.1f months of expenses against a %d-month target. Put new savings toward investing instead.",
+			formatMoney(current), r.CoverageMonths, months)
+		return r
+	}
+
+	r.Status = "building"
+	r.ShortfallUSD = target - current
+	if capacity <= 0 {
+		r.Message = fmt.Sprintf("%s short of a %d-month fund (%s). Any monthly amount will start closing the gap.", formatMoney(r.ShortfallUSD), months, formatMoney(target))
+		return r
+	}
+
+	balance := current
+	for m := 1; m <= maxFundingScheduleMonths; m++ {
+		grown := balance * (1 + apy/100/12)
+		deposit := max(min(capacity, target-grown), 0)
+		balance = grown + deposit
+		r.Schedule = append(r.Schedule, EmergencyFundMonth{
+			Month:          m,
+			Date:           now.AddDate(0, m, 0).Format("2006-01"),
+			DepositUSD:     deposit,
+			BalanceUSD:     balance,
+			CoverageMonths: balance / expenses,
+		})
+		if balance >= target-0.005 {
+			r.MonthsToFunded = m
+			break
+		}
+	}
+
+	if r.MonthsToFunded > 0 {
+		r.Message = fmt.Sprintf("Save %s/month to reach a %d-month fund of %s in %d months (by %s).",
+			formatMoney(capacity), months, formatMoney(target), r.MonthsToFunded, r.Schedule[len(r.Schedule)-1].Date)
+	} else {
+		r.Message = fmt.Sprintf("At %s/month the %d-month fund of %s takes more than %d years; about %s/month would get there in 2 years.",
+			formatMoney(capacity), months, formatMoney(target), maxFundingScheduleMonths/12, formatMoney(requiredMonthlyContribution(target, current, apy, 24)))
+	}
+	return r
+}
+
+// fetchMonthlySpend averages outgoing transactions into a monthly figure; ok is false without usable history
+func fetchMonthlySpend(ctx context.Context, liminalExecutor core.ToolExecutor, userID string, now time.Time) (float64, bool) {
+	txs, ok := fetchTransactions(ctx, liminalExecutor, userID, transactionPageSize)
+	if !ok {
+		return 0, false
+	}
+
+	spent, spends := 0.0, 0
+	oldest := now
+	for _, tx := range txs {
+		if kind, _ := tx["type"].(string); kind != "send" {
+			continue
+		}
+		spent += math.Abs(transactionAmount(tx))
+		spends++
+		if at, ok := transactionTime(tx); ok && at.Before(oldest) {
+			oldest = at
+		}
+	}
+	if spends == 0 {
+		return 0, false
+	}
+	return spent / transactionWindowDays(oldest, now) * 30, true
+}
@@ -0,0 +1,215 @@
+package main
+
+import (
+	"context"
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"log"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/becomeliminal/nim-go-sdk/core"
+	"github.com/becomeliminal/nim-go-sdk/tools"
+)
+
+// ============================================
+// ETF FACTS
+// ============================================
+// Lookups try the ticker first, then score every fund's name, issuer and index
+// against the words of the query. A single best match is returned as the fund;
+// ties or partial matches come back as a ranked candidates list.
+
+//go:embed data/etfs.json
+var etfsJSON []byte
+
+// etfRecord is one entry of data/etfs.json
+type etfRecord struct {
+	Ticker        string  `json:"ticker"`
+	Name          string  `json:"name"`
+	Issuer        string  `json:"issuer"`
+	ExpenseRatio  float64 `json:"expense_ratio"` // annual %
+	AssetClass    string  `json:"asset_class"`
+	Index         string  `json:"index"`
+	InceptionYear int     `json:"inception_year"`
+}
+
+// etfDatabase is the bundled dataset, loaded once at startup
+var etfDatabase = loadETFDatabase()
+
+func loadETFDatabase() (db struct {
+	AsOf  string      `json:"as_of"`
+	Note  string      `json:"note"`
+	Funds []etfRecord `json:"funds"`
+}) {
+	if err := json.Unmarshal(etfsJSON, &db); err != nil {
+		log.Fatalf("invalid data/etfs.json: %v", err)
+	}
+	return db
+}
+
+// etfExpenseRatio is a fund's annual expense ratio in % from etfDatabase; ok is false for tickers it doesn't list
+func etfExpenseRatio(ticker string) (ratio float64, ok bool) {
+	ticker = strings.ToUpper(strings.TrimSpace(ticker))
+	for _, f := range etfDatabase.Funds {
+		if f.Ticker == ticker {
+			return f.ExpenseRatio, true
+		}
+	}
+	return 0, false
+}
+
+// etfAllocationBuckets maps a fund's asset class onto the rebalancer's asset classes
+var etfAllocationBuckets = map[string]string{
+	"us_equity":            "stocks",
+	"global_equity":        "stocks", // roughly 60% US / 40% international
+	"international_equity": "international",
+	"real_estate":          "reit",
+	"us_bond":              "bonds",
+	"international_bond":   "bonds",
+	"cash_equivalent":      "cash",
+}
+
+// etfSearchStopWords carry no signal when matching names
+var etfSearchStopWords = map[string]bool{"etf": true, "fund": true, "the": true, "trust": true, "index": true, "shares": true}
+
+// maxETFCandidates caps the ranked list returned for ambiguous searches
+const maxETFCandidates = 5
+
+// etfSearchTokens lowercases s and splits it into words, spelling "S&P" one way
+func etfSearchTokens(s string) []string {
+	s = strings.NewReplacer("s&p", "sp ", "s & p", "sp ", "s and p", "sp ", "-", " ", ",", " ", ".", " ").Replace(strings.ToLower(s))
+	var tokens []string
+	for _, t := range strings.Fields(s) {
+		if !etfSearchStopWords[t] {
+			tokens = append(tokens, t)
+		}
+	}
+	return tokens
+}
+
+// etfMatchScore is the share of query words found in the fund, name words counting double.
+// Query words of three letters or more may be prefixes ("vang" matches "vanguard"). coverage, the share of the
+// fund's name words the query used, breaks ties toward the fund named most exactly.
+func etfMatchScore(query []string, f etfRecord) (score, coverage float64) {
+	name := etfSearchTokens(f.Name + " " + f.Issuer)
+	index := etfSearchTokens(f.Index)
+	nameOnly := slices.Compact(slices.Sorted(slices.Values(etfSearchTokens(f.Name))))
+	has := func(words []string, q string) bool {
+		return slices.ContainsFunc(words, func(w string) bool { return w == q || len(q) >= 3 && strings.HasPrefix(w, q) })
+	}
+	used := 0
+	for _, q := range query {
+		if has(nameOnly, q) {
+			used++
+		}
+		switch {
+		case has(name, q):
+			score += 2
+		case has(index, q):
+			score++
+		}
+	}
+	return score / float64(2*len(query)), float64(used) / float64(len(nameOnly))
+}
+
+// lookupETF finds a fund by ticker or name; ok is false when the query is ambiguous or unmatched
+func lookupETF(query string) (fund etfRecord, candidates []ETFCandidate, ok bool) {
+	ticker := strings.ToUpper(strings.TrimSpace(query))
+	for _, f := range etfDatabase.Funds {
+		if f.Ticker == ticker {
+			return f, nil, true
+		}
+	}
+
+	words := etfSearchTokens(query)
+	if len(words) == 0 {
+		return etfRecord{}, nil, false
+	}
+	type scored struct {
+		fund            etfRecord
+		score, coverage float64
+	}
+	var matches []scored
+	for _, f := range etfDatabase.Funds {
+		if s, c := etfMatchScore(words, f); s > 0 {
+			matches = append(matches, scored{f, s, c})
+		}
+	}
+	sort.SliceStable(matches, func(i, j int) bool {
+		if matches[i].score != matches[j].score {
+			return matches[i].score > matches[j].score
+		}
+		return matches[i].coverage > matches[j].coverage
+	})
+
+	if len(matches) > 0 && matches[0].score == 1 &&
+		(len(matches) == 1 || matches[1].score < 1 || matches[1].coverage < matches[0].coverage) {
+		return matches[0].fund, nil, true
+	}
+	for i, m := range matches {
+		if i == maxETFCandidates {
+			break
+		}
+		candidates = append(candidates, ETFCandidate{Ticker: m.fund.Ticker, Name: m.fund.Name, MatchScore: m.score})
+	}
+	return etfRecord{}, candidates, false
+}
+
+// newETFFactTool answers questions about specific popular ETFs from the bundled database
+func newETFFactTool() core.Tool {
+	return tools.New("etf_fact_lookup").
+		Description("Look up facts about a specific popular ETF by ticker or name (e.g. 'VOO' or 'vanguard s&p 500'): full name, issuer, expense ratio, asset class, index tracked, inception year, and which allocation bucket it fills in our model. Ambiguous names return ranked candidates to ask the user about").
+		Schema(tools.ObjectSchema(map[string]interface{}{
+			"query": tools.StringProperty("Ticker or fund name, e.g. 'VOO' or 'vanguard total bond'"),
+		}, "query")).
+		HandlerFunc(func(ctx context.Context, input json.RawMessage) (interface{}, error) {
+			var params struct {
+				Query string `json:"query"`
+			}
+			if err := json.Unmarshal(input, &params); err != nil {
+				return nil, fmt.Errorf("invalid input: %w", err)
+			}
+			if strings.TrimSpace(params.Query) == "" {
+				return nil, fmt.Errorf("invalid input: query: give a ticker or fund name")
+			}
+
+			f, candidates, ok := lookupETF(params.Query)
+			if !ok {
+				r := ETFLookupResult{Query: params.Query, Candidates: candidates}
+				if len(candidates) == 0 {
+					r.Message = fmt.Sprintf("%q isn't in my ETF database of %d popular funds. Check the ticker, or look it up on the issuer's site.", params.Query, len(etfDatabase.Funds))
+				} else {
+					r.Message = fmt.Sprintf("%q could be several funds; ask the user which one they mean.", params.Query)
+				}
+				return r, nil
+			}
+
+			bucket, inModel := etfAllocationBuckets[f.AssetClass]
+			r := ETFLookupResult{
+				Query: params.Query,
+				Fund: &ETFFacts{
+					Ticker:              f.Ticker,
+					Name:                f.Name,
+					Issuer:              f.Issuer,
+					ExpenseRatioPercent: f.ExpenseRatio,
+					AssetClass:          f.AssetClass,
+					IndexTracked:        f.Index,
+					InceptionYear:       f.InceptionYear,
+					AllocationBucket:    bucket,
+				},
+				DataAsOf: etfDatabase.AsOf,
+				DataNote: etfDatabase.Note,
+			}
+			r.Message = fmt.Sprintf("%s (%s) tracks the %s and costs %g%% a year.", f.Name, f.Ticker, f.Index, f.ExpenseRatio)
+			switch {
+			case !inModel:
+				r.Message += " It sits outside our stocks/international/REIT/bonds/cash model, so it isn't counted in allocation targets."
+			case f.AssetClass == "global_equity":
+				r.Message += " It holds both US and international stocks (roughly 60/40); it's counted under stocks."
+			}
+			return r, nil
+		}).
+		Build()
+}
@@ -0,0 +1,163 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"log"
+	"math"
+	"strings"
+
+	"vibe-invest/storage"
+)
+
+// ============================================
+// PERSONALIZED CONCEPT EXAMPLES
+// ============================================
+// A few concepts are easier to grasp with real numbers. When the user has a
+// stored profile, explain_investment_concept works the example through with
+// their own monthly savings and balance; without one it uses fixed
+// illustrative numbers and marks the example generic.
+
+// Illustrative figures for users without a stored profile
+const (
+	genericExampleMonthly = 500.0
+	genericExampleBalance = 10000.0
+)
+
+// exampleYears is the horizon of the compound interest example
+const exampleYears = 20
+
+// exampleDCAPrices are the share prices the dollar-cost averaging example buys at, one per month
+var exampleDCAPrices = []float64{100, 80, 125, 100}
+
+// exampleStockDrop is the fall in stocks the diversification example weathers, in percent
+const exampleStockDrop = 20.0
+
+// exampleAssetNames reads rebalanceAssets as prose
+var exampleAssetNames = map[string]string{
+	"stocks":        "U.S. stocks",
+	"international": "international stocks",
+	"reit":          "REITs",
+	"bonds":         "bonds",
+	"cash":          "cash",
+}
+
+// conceptExamples builds the example, in locale, for each concept that has one
+var conceptExamples = map[string]func(e *ConceptExample, risk RiskLevel, locale string){
+	"compound_interest":     compoundInterestExample,
+	"dollar_cost_averaging": dollarCostAveragingExample,
+	"dividend":              dividendExample,
+	"diversification":       diversificationExample,
+}
+
+// personalizedExample works concept through with the user's stored monthly savings and balance,
+// or illustrative numbers when they have no profile, in locale; nil for concepts without an example
+func personalizedExample(ctx context.Context, userID, concept, locale string) *ConceptExample {
+	build, ok := conceptExamples[concept]
+	if !ok {
+		return nil
+	}
+	e := &ConceptExample{Generic: true, MonthlySavingsUSD: genericExampleMonthly, BalanceUSD: genericExampleBalance}
+	risk := RiskModerate
+	p, err := store.GetPortfolio(ctx, userKey(userID))
+	switch {
+	case err == nil:
+		e.Generic, e.MonthlySavingsUSD, e.BalanceUSD = false, p.MonthlySavings, p.TotalBalance
+		if r, err := normalizeRiskLevel(p.RiskTolerance); err == nil {
+			risk = r
+		}
+	case !errors.Is(err, storage.ErrNotFound):
+		log.Printf("⚠️  Failed to load profile for %s's example: %v\n", userKey(userID), err)
+	}
+	build(e, risk, locale)
+	if e.Generic {
+		e.Note = localize(locale, "Illustrative numbers - save a profile (complete_onboarding or update_investment_profile) to see this with your own.")
+	}
+	return e
+}
+
+// exampleSubject phrases an amount as the user's own, or as a hypothetical one for a generic example
+func exampleSubject(e *ConceptExample, locale, mine, generic, amount string) string {
+	if e.Generic {
+		return localizef(locale, generic, amount)
+	}
+	return localizef(locale, mine, amount)
+}
+
+// compoundInterestExample grows the balance and monthly savings over exampleYears at the assumed equity return
+func compoundInterestExample(e *ConceptExample, _ RiskLevel, locale string) {
+	rate := appConfig.Assumptions.EquityReturnPct
+	r := calculateCompoundGrowth(e.BalanceUSD, e.MonthlySavingsUSD, rate, exampleYears)
+	growth := r.ProjectedTotalUSD - r.TotalContributed
+	e.Figures = map[string]float64{
+		"years":                 exampleYears,
+		"annual_return_percent": rate,
+		"total_contributed":     r.TotalContributed,
+		"projected_total":       r.ProjectedTotalUSD,
+		"growth":                growth,
+	}
+	e.Example = localizef(locale, "%s on top of %s for %d years at %g%% a year: you'd put in %s and end with about %s. The %s difference is growth, much of it earned on earlier growth.",
+		exampleSubject(e, locale, "Investing your %s a month", "Investing %s a month", formatMoneyIn(locale, e.MonthlySavingsUSD)),
+		exampleSubject(e, locale, "your %s balance", "a %s balance", formatMoneyIn(locale, e.BalanceUSD)),
+		exampleYears, rate, formatWholeMoneyIn(locale, r.TotalContributed), formatWholeMoneyIn(locale, r.ProjectedTotalUSD), formatWholeMoneyIn(locale, growth))
+}
+
+// dollarCostAveragingExample invests the monthly savings at each of exampleDCAPrices
+func dollarCostAveragingExample(e *ConceptExample, _ RiskLevel, locale string) {
+	var shares, priceSum float64
+	prices := make([]string, len(exampleDCAPrices))
+	for i, price := range exampleDCAPrices {
+		shares += e.MonthlySavingsUSD / price
+		priceSum += price
+		prices[i] = formatMoneyIn(locale, price)
+	}
+	invested := e.MonthlySavingsUSD * float64(len(exampleDCAPrices))
+	avgPrice := priceSum / float64(len(exampleDCAPrices))
+	e.Figures = map[string]float64{
+		"months":          float64(len(exampleDCAPrices)),
+		"invested":        invested,
+		"shares":          math.Round(shares*1000) / 1000,
+		"average_price":   avgPrice,
+		"yearly_invested": e.MonthlySavingsUSD * 12,
+	}
+	if shares == 0 {
+		e.Example = localizef(locale, "With nothing set aside each month there's nothing to average yet - even %s a month at share prices of %s would buy more shares in the cheap months than the expensive ones.",
+			formatMoneyIn(locale, genericExampleMonthly), strings.Join(prices, ", "))
+		return
+	}
+	avgCost := invested / shares
+	e.Figures["average_cost"] = avgCost
+	e.Example = localizef(locale, "%s for %d months at share prices of %s buys %.2f shares for %s - an average cost of %s a share, below the %s average price, because the same amount bought more shares when the price dipped. Automated, that's %s invested a year without timing anything.",
+		exampleSubject(e, locale, "Investing your %s a month", "Investing %s a month", formatMoneyIn(locale, e.MonthlySavingsUSD)), len(exampleDCAPrices), strings.Join(prices, ", "),
+		shares, formatMoneyIn(locale, invested), formatMoneyIn(locale, avgCost), formatMoneyIn(locale, avgPrice), formatWholeMoneyIn(locale, e.MonthlySavingsUSD*12))
+}
+
+// dividendExample pays the balance's dividends at defaultDividendYield
+func dividendExample(e *ConceptExample, _ RiskLevel, locale string) {
+	yearly := e.BalanceUSD * defaultDividendYield / 100
+	e.Figures = map[string]float64{
+		"dividend_yield_percent": defaultDividendYield,
+		"yearly_dividends":       yearly,
+		"quarterly_dividends":    yearly / 4,
+	}
+	e.Example = localizef(locale, "At a %g%% dividend yield, %s would pay about %s a year - %s each quarter - just for holding the shares. Reinvested, those payments buy more shares that pay dividends of their own.",
+		defaultDividendYield, exampleSubject(e, locale, "your %s invested", "%s invested", formatMoneyIn(locale, e.BalanceUSD)), formatMoneyIn(locale, yearly), formatMoneyIn(locale, yearly/4))
+}
+
+// diversificationExample splits the balance by the risk level's target allocation and drops stocks exampleStockDrop percent
+func diversificationExample(e *ConceptExample, risk RiskLevel, locale string) {
+	bands := riskAllocationModel[risk]
+	e.Figures = map[string]float64{}
+	parts := make([]string, 0, len(rebalanceAssets))
+	for _, asset := range rebalanceAssets {
+		amount := e.BalanceUSD * bands[asset].TargetPct / 100
+		e.Figures[asset] = amount
+		parts = append(parts, localizef(locale, "%s in %s", formatWholeMoneyIn(locale, amount), localize(locale, exampleAssetNames[asset])))
+	}
+	equityPct := bands["stocks"].TargetPct + bands["international"].TargetPct + bands["reit"].TargetPct
+	loss := e.BalanceUSD * equityPct / 100 * exampleStockDrop / 100
+	e.Figures["loss_if_stocks_fall"] = loss
+	e.Example = localizef(locale, "With the %s allocation, %s becomes %s. If every stock holding fell %g%%, the portfolio would lose %s - about %s%%, not %g%% - because the bonds and cash hold steady.",
+		localize(locale, string(risk)), exampleSubject(e, locale, "your %s", "%s", formatWholeMoneyIn(locale, e.BalanceUSD)), strings.Join(parts, ", "),
+		exampleStockDrop, formatWholeMoneyIn(locale, loss), formatPercentValue(equityPct*exampleStockDrop/100), exampleStockDrop)
+}
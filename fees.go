@@ -0,0 +1,118 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/becomeliminal/nim-go-sdk/core"
+	"github.com/becomeliminal/nim-go-sdk/tools"
+)
+
+// ============================================
+// FEE DRAG
+// ============================================
+
+// maxFeePct bounds the annual fee accepted per level
+const maxFeePct = 5.0
+
+// newFeeDragTool shows what expense ratios and advisory fees cost over time
+func newFeeDragTool() core.Tool {
+	return tools.New("fee_drag_calculator").
+		Description("Show how much annual fees (expense ratios, advisory fees) cost over time: ending balance with and without fees, lifetime dollars lost and share of final wealth consumed, optionally comparing two fee levels").
+		Schema(tools.ObjectSchema(map[string]interface{}{
+			"initial_amount":   tools.StringProperty("Starting amount in the account currency"),
+			"monthly_addition": tools.StringProperty("Amount added each month in the account currency"),
+			"years":            tools.StringProperty("Number of years, 1-60"),
+			"gross_return":     tools.StringProperty(fmt.Sprintf("Optional annual return before fees percentage (defaults to the server's assumption, currently %g)", appConfig.Assumptions.EquityReturnPct)),
+			"fee":              tools.StringProperty("Annual fee percentage (e.g., '0.75' for a 0.75% fund or '1' for a 1% advisor)"),
+			"compare_fee":      tools.StringProperty("Optional second annual fee percentage to compare against (e.g., '0.03' for an index fund)"),
+		}, "initial_amount", "monthly_addition", "years", "fee")).
+		HandlerFunc(func(ctx context.Context, input json.RawMessage) (interface{}, error) {
+			var params struct {
+				InitialAmount   string `json:"initial_amount"`
+				MonthlyAddition string `json:"monthly_addition"`
+				Years           string `json:"years"`
+				GrossReturn     string `json:"gross_return"`
+				Fee             string `json:"fee"`
+				CompareFee      string `json:"compare_fee"`
+			}
+			if err := json.Unmarshal(input, &params); err != nil {
+				return nil, fmt.Errorf("invalid input: %w", err)
+			}
+
+			var v amountValidator
+			initial := v.nonNegative("initial_amount", params.InitialAmount, true)
+			monthly := v.nonNegative("monthly_addition", params.MonthlyAddition, true)
+			years := v.years("years", params.Years, minProjectionYears, maxProjectionYears)
+			returnRate := v.returnRate("gross_return", params.GrossReturn, false)
+			fees := []float64{v.feeRate("fee", params.Fee)}
+			if strings.TrimSpace(params.CompareFee) != "" {
+				fees = append(fees, v.feeRate("compare_fee", params.CompareFee))
+			}
+			if err := v.err(); err != nil {
+				return nil, err
+			}
+
+			return feeDrag(initial, monthly, returnRate, years, fees), nil
+		}).
+		Build()
+}
+
+// feeDrag projects each fee level against the same no-fee baseline
+func feeDrag(initial, monthly, returnRate, years float64, fees []float64) FeeDragResult {
+	months := max(int(math.Round(years*12)), 1)
+	gross := balanceAfterFees(initial, monthly, returnRate, 0, months)
+
+	r := FeeDragResult{
+		Currency:            activeCurrency().Code,
+		InitialAmountUSD:    initial,
+		MonthlyAdditionUSD:  monthly,
+		Years:               years,
+		GrossReturnPercent:  returnRate,
+		NoFeeEndingUSD:      gross,
+		TotalContributedUSD: initial + monthly*float64(months),
+		FeeTiming:           "Each fee is charged on the balance at the end of every year",
+	}
+	for _, fee := range fees {
+		ending := balanceAfterFees(initial, monthly, returnRate, fee, months)
+		level := FeeLevel{
+			FeePercent:   fee,
+			EndingUSD:    ending,
+			LostToFeeUSD: gross - ending,
+		}
+		if gross > 0 {
+			level.WealthConsumedPct = level.LostToFeeUSD / gross * 100
+		}
+		r.Levels = append(r.Levels, level)
+	}
+
+	first := r.Levels[0]
+	r.Message = fmt.Sprintf("A %g%% annual fee costs %s over %g years - %.1f%% of what you'd otherwise have.",
+		first.FeePercent, formatWholeMoney(first.LostToFeeUSD), years, first.WealthConsumedPct)
+	if len(r.Levels) == 2 {
+		second := r.Levels[1]
+		r.DifferenceUSD = second.EndingUSD - first.EndingUSD
+		r.Message += fmt.Sprintf(" At %g%% instead you'd end with %s more.", second.FeePercent, formatWholeMoney(r.DifferenceUSD))
+	}
+	return r
+}
+
+// balanceAfterFees compounds monthly with end-of-month contributions, like futureValue,
+// and deducts feePct of the balance at the end of each year (and of a final partial year, pro rata)
+func balanceAfterFees(initial, monthly, returnRate, feePct float64, months int) float64 {
+	monthlyRate := returnRate / 100 / 12
+	balance := initial
+	for m := 1; m <= months; m++ {
+		balance = balance*(1+monthlyRate) + monthly
+		switch {
+		case m%12 == 0:
+			balance *= 1 - feePct/100
+		case m == months:
+			balance *= 1 - feePct/100*float64(m%12)/12
+		}
+	}
+	return balance
+}
@@ -0,0 +1,119 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/becomeliminal/nim-go-sdk/core"
+	"github.com/becomeliminal/nim-go-sdk/tools"
+)
+
+// ============================================
+// GLIDE PATH
+// ============================================
+
+// GlideStep is the allocation at one point on the glide path
+type GlideStep struct {
+	Year          int     `json:"year"` // calendar year
+	YearsToTarget float64 `json:"years_to_target"`
+	Age           int     `json:"age,omitempty"`
+	StocksPercent float64 `json:"stocks_percent"`
+	BondsPercent  float64 `json:"bonds_percent"`
+	CashPercent   float64 `json:"cash_percent"`
+}
+
+// glideStepYears is the spacing of glide_path's schedule
+const glideStepYears = 5
+
+// glideAllocation returns stock (all equity), bond and cash fractions for yearsLeft until the
+// target date: horizonSchedule at its baseline risk level
+func glideAllocation(yearsLeft float64) (stocks, bonds, cash float64) {
+	a := horizonAllocation(yearsLeft, baselineHorizonRisk)
+	return planEquityShare(a), a.bonds, a.cash
+}
+
+// glideRule describes horizonSchedule's knots for the glide_path response
+func glideRule() string {
+	points := make([]string, 0, len(horizonSchedule))
+	for i := len(horizonSchedule) - 1; i >= 0; i-- {
+		k := horizonSchedule[i]
+		stocks, _, cash := glideAllocation(k.years)
+		points = append(points, fmt.Sprintf("%.0f%% stocks / %.0f%% cash at %g years", stocks*100, cash*100, k.years))
+	}
+	return "Shares the horizon schedule used by analyze_investment_recommendations, moving evenly between " + strings.Join(points, ", ") +
+		"; bonds take the rest"
+}
+
+// glideTargets is glideAllocation as percentages keyed like targetAllocationTable
+func glideTargets(yearsLeft float64) map[string]float64 {
+	stocks, bonds, cash := glideAllocation(yearsLeft)
+	return map[string]float64{"stocks": stocks * 100, "bonds": bonds * 100, "cash": cash * 100}
+}
+
+// glideSchedule lists today's allocation and every stepYears shift until the target year.
+// currentAge 0 means unknown.
+func glideSchedule(yearsLeft, currentAge int, now time.Time) []GlideStep {
+	var steps []GlideStep
+	for offset := 0; ; offset += glideStepYears {
+		if offset > yearsLeft {
+			offset = yearsLeft
+		}
+		stocks, bonds, cash := glideAllocation(float64(yearsLeft - offset))
+		step := GlideStep{
+			Year:          now.Year() + offset,
+			YearsToTarget: float64(yearsLeft - offset),
+			StocksPercent: stocks * 100,
+			BondsPercent:  bonds * 100,
+			CashPercent:   cash * 100,
+		}
+		if currentAge > 0 {
+			step.Age = currentAge + offset
+		}
+		steps = append(steps, step)
+		if offset == yearsLeft {
+			return steps
+		}
+	}
+}
+
+// newGlidePathTool shows how a target-date allocation shifts toward safety over time
+func newGlidePathTool() core.Tool {
+	return tools.New("glide_path").
+		Description("Show a target-date glide path: today's stock/bond/cash allocation and how it shifts every five years as the target date approaches").
+		Schema(tools.ObjectSchema(map[string]interface{}{
+			"target_date": tools.StringProperty("When the money is needed, e.g. '2050', 'until 2045' or '25 years'"),
+			"current_age": tools.IntegerProperty("Optional current age, to label each step with the user's age"),
+		}, "target_date")).
+		HandlerFunc(func(ctx context.Context, input json.RawMessage) (interface{}, error) {
+			var params struct {
+				TargetDate string `json:"target_date"`
+				CurrentAge int    `json:"current_age"`
+			}
+			if err := json.Unmarshal(input, &params); err != nil {
+				return nil, fmt.Errorf("invalid input: %w", err)
+			}
+			now := time.Now()
+			years, usedFallback := parseTimeHorizon(params.TargetDate, now)
+			if usedFallback {
+				return nil, fmt.Errorf("invalid input: target_date: %q is not a future year or a number of years", params.TargetDate)
+			}
+			if params.CurrentAge < 0 || params.CurrentAge >= maxRetirementAge {
+				return nil, fmt.Errorf("invalid input: current_age: must be between 1 and %d (got %d)", maxRetirementAge-1, params.CurrentAge)
+			}
+
+			schedule := glideSchedule(years, params.CurrentAge, now)
+			today := schedule[0]
+			return map[string]interface{}{
+				"target_year":      now.Year() + years,
+				"years_to_target":  years,
+				"today":            today,
+				"schedule":         schedule,
+				"glide_rule":       glideRule(),
+				"rebalancer_usage": "Pass target_date to rebalance_investment_portfolio to rebalance toward today's point on this path",
+			}, nil
+		}).
+		Build()
+}
@@ -2,7 +2,10 @@ module vibe-invest
 
 go 1.24.0
 
-require github.com/becomeliminal/nim-go-sdk v0.8.2
+require (
+	github.com/becomeliminal/nim-go-sdk v0.8.2
+	modernc.org/sqlite v1.34.5
+)
 
 require (
 	github.com/anthropics/anthropic-sdk-go v1.22.0 // indirect
@@ -12,12 +15,18 @@ require (
 	github.com/golang/glog v1.2.5 // indirect
 	github.com/google/uuid v1.6.0 // indirect
 	github.com/gorilla/websocket v1.5.3 // indirect
+	github.com/mattn/go-isatty v0.0.20 // indirect
+	github.com/ncruces/go-strftime v0.1.9 // indirect
 	github.com/pkg/errors v0.9.1 // indirect
+	github.com/remyoudompheng/bigfft v0.0.0-20230129092748-24d4a6f8daec // indirect
 	github.com/tidwall/gjson v1.18.0 // indirect
 	github.com/tidwall/match v1.2.0 // indirect
 	github.com/tidwall/pretty v1.2.1 // indirect
 	github.com/tidwall/sjson v1.2.5 // indirect
 	golang.org/x/sys v0.41.0 // indirect
+	modernc.org/libc v1.55.3 // indirect
+	modernc.org/mathutil v1.6.0 // indirect
+	modernc.org/memory v1.8.0 // indirect
 )
 
 replace github.com/becomeliminal/nim-go-sdk => ../nim-go-sdk
@@ -0,0 +1,448 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/becomeliminal/nim-go-sdk/core"
+	"github.com/becomeliminal/nim-go-sdk/tools"
+
+	"vibe-invest/storage"
+)
+
+// ============================================
+// GOAL PROJECTIONS
+// ============================================
+
+// monthsUntil counts whole calendar months from now until target
+func monthsUntil(now, target time.Time) int {
+	months := (target.Year()-now.Year())*12 + int(target.Month()-now.Month())
+	if target.Day() < now.Day() {
+		months--
+	}
+	return months
+}
+
+// GoalFundingStatus reports whether monthly contributions reach a goal in time
+type GoalFundingStatus struct {
+	OnTrack              bool    `json:"on_track"`
+	ProjectedTotalUSD    float64 `json:"projected_total_usd"`
+	TargetAmountUSD      float64 `json:"target_amount_usd"`
+	RequiredMonthlyUSD   float64 `json:"required_monthly_usd"`
+	CurrentMonthlyUSD    float64 `json:"current_monthly_usd"`
+	AdditionalMonthlyUSD float64 `json:"additional_monthly_usd,omitempty"`
+	AssumedAnnualReturn  float64 `json:"assumed_annual_return"`
+	AnnualIncrease       float64 `json:"annual_increase_percent,omitempty"` // yearly raise in the monthly contribution
+	ProjectedSurplusUSD  float64 `json:"projected_surplus_usd"`             // negative = shortfall
+	Message              string  `json:"message"`
+}
+
+// goalFundingStatus reports whether monthly contributions, raised by increasePct a year, reach target
+// in time, and what starting amount would
+func goalFundingStatus(target, initial, monthly, returnRate, increasePct float64, months int) GoalFundingStatus {
+	projected := escalatingFutureValue(initial, monthly, returnRate, increasePct, float64(months))
+	required := requiredStartingContribution(target, initial, returnRate, increasePct, float64(months))
+
+	status := GoalFundingStatus{
+		OnTrack:             projected >= target,
+		ProjectedTotalUSD:   projected,
+		TargetAmountUSD:     target,
+		RequiredMonthlyUSD:  required,
+		CurrentMonthlyUSD:   monthly,
+		AssumedAnnualReturn: returnRate,
+		AnnualIncrease:      increasePct,
+		ProjectedSurplusUSD: projected - target,
+	}
+	rising := ""
+	if increasePct != 0 {
+		rising = fmt.Sprintf(" rising %g%% a year", increasePct)
+	}
+	if status.OnTrack {
+		status.Message = fmt.Sprintf("%s/month%s is enough to reach %s in %d months", formatMoney(monthly), rising, formatMoney(target), months)
+	} else {
+		status.AdditionalMonthlyUSD = required - monthly
+		status.Message = fmt.Sprintf("%s/month%s falls short; %s/month%s is needed to reach %s in %d months",
+			formatMoney(monthly), rising, formatMoney(required), rising, formatMoney(target), months)
+	}
+	return status
+}
+
+// goalInvestmentRisk maps a goal's investment_type onto the risk level whose return and
+// volatility its success probability assumes; anything unrecognized is treated as moderate
+var goalInvestmentRisk = map[string]RiskLevel{
+	"stocks":      RiskAggressive,
+	"etfs":        RiskModerateToAggressive,
+	"diversified": RiskModerate,
+}
+
+// Success probability bands
+const (
+	successLikelyPct    = 75.0
+	successUncertainPct = 40.0
+)
+
+// GoalOdds is the chance a goal's contributions reach its target once returns vary
+type GoalOdds struct {
+	Probability       float64 `json:"success_probability"` // percent
+	Band              string  `json:"success_band"`        // likely, uncertain or unlikely
+	MeanReturnPercent float64 `json:"success_mean_return_percent"`
+	VolatilityPercent float64 `json:"success_volatility_percent"`
+}
+
+// goalReturn is the annual return and volatility a goal's investment_type assumes. Savings goals
+// earn the vault rate with no volatility; other types use their risk level's mean and volatility.
+// The on-track check and the success probability both project at this mean, so they agree.
+func goalReturn(investmentType string) (meanPct, volatilityPct float64) {
+	if strings.EqualFold(strings.TrimSpace(investmentType), "savings") {
+		apy, _ := vaultRates.current()
+		return apy, 0
+	}
+	risk, ok := goalInvestmentRisk[strings.ToLower(strings.TrimSpace(investmentType))]
+	if !ok {
+		risk = RiskModerate
+	}
+	return expectedPortfolioReturn(targetAllocationTable[risk]), riskVolatilityPct[risk]
+}
+
+// goalSuccessOdds estimates the chance of reaching target in months from current plus monthly
+// contributions raised by increasePct a year, at goalReturn's mean and volatility
+func goalSuccessOdds(target, current, monthly, increasePct float64, months int, investmentType string) GoalOdds {
+	var odds GoalOdds
+	odds.MeanReturnPercent, odds.VolatilityPercent = goalReturn(investmentType)
+	odds.Probability = successProbability(target, current, monthly, increasePct, months, odds.MeanReturnPercent, odds.VolatilityPercent)
+	switch {
+	case odds.Probability >= successLikelyPct:
+		odds.Band = "likely"
+	case odds.Probability >= successUncertainPct:
+		odds.Band = "uncertain"
+	default:
+		odds.Band = "unlikely"
+	}
+	return odds
+}
+
+// successProbability is the percent chance the ending balance reaches target under the monthly
+// return model of simulateOutcomes. The balance's exact mean and variance are carried month by
+// month, then matched to a lognormal, so no paths need simulating.
+func successProbability(target, current, monthly, increasePct float64, months int, meanPct, volatilityPct float64) float64 {
+	growth := 1 + meanPct/100/12
+	spread := math.Pow(volatilityPct/100, 2) / 12
+	mean, square := current, current*current // E[B] and E[B²]
+	for m := 1; m <= months; m++ {
+		square = square*(growth*growth+spread) + 2*monthly*growth*mean + monthly*monthly
+		mean = mean*growth + monthly
+		if m%12 == 0 {
+			monthly *= 1 + increasePct/100
+		}
+	}
+	if target <= 0 {
+		return 100
+	}
+	variance := square - mean*mean
+	if mean <= 0 || variance <= mean*mean*1e-12 {
+		if mean >= target {
+			return 100
+		}
+		return 0
+	}
+	sigma := math.Sqrt(math.Log(1 + variance/(mean*mean)))
+	mu := math.Log(mean) - sigma*sigma/2
+	return 50 * math.Erfc((math.Log(target)-mu)/(sigma*math.Sqrt2))
+}
+
+// defaultEmergencyMonths is the emergency fund size assumed when the user gives no target
+const defaultEmergencyMonths = 6
+
+// Sources for a goal's amount saved so far
+const (
+	savedFromUser          = "user_reported"
+	savedFromSavings       = "savings_balance"
+	savedFromContributions = "estimated_from_contributions"
+	savedFromLedger        = "contributions_ledger"
+)
+
+// balanceKeys are the get_balance and get_savings_balance response fields we accept as the balance
+var balanceKeys = []string{"balance", "available_balance", "total_balance", "savings_balance", "amount"}
+
+// newGoalProgressTool answers "how am I doing on my goal?"
+func newGoalProgressTool(liminalExecutor core.ToolExecutor) core.Tool {
+	return tools.New("get_goal_progress").
+		Description("Check progress toward the user's investment goals: amount saved, percent complete, time elapsed vs remaining, and whether they're on pace").
+		Schema(tools.ObjectSchema(map[string]interface{}{
+			"goal_id":      tools.StringProperty("Optional goal ID; omit to report on every goal"),
+			"amount_saved": tools.StringProperty("Optional amount the user says they've saved toward the goal so far in the account currency"),
+		})).
+		Handler(func(ctx context.Context, toolParams *core.ToolParams) (*core.ToolResult, error) {
+			var params struct {
+				GoalID      string `json:"goal_id"`
+				AmountSaved string `json:"amount_saved"`
+			}
+			if err := json.Unmarshal(toolParams.Input, &params); err != nil {
+				return &core.ToolResult{Success: false, Error: fmt.Sprintf("invalid input: %v", err)}, nil
+			}
+			var v amountValidator
+			reported := v.nonNegative("amount_saved", params.AmountSaved, false)
+			if err := v.err(); err != nil {
+				return &core.ToolResult{Success: false, Error: err.Error()}, nil
+			}
+
+			goals, err := store.ListGoals(ctx, userKey(toolParams.UserID))
+			if err != nil {
+				return &core.ToolResult{Success: false, Error: fmt.Sprintf("could not load goals: %v", err)}, nil
+			}
+			if id := strings.TrimSpace(params.GoalID); id != "" {
+				goals = slices.DeleteFunc(goals, func(g storage.Goal) bool { return g.ID != id })
+				if len(goals) == 0 {
+					return &core.ToolResult{Success: true, Data: map[string]interface{}{
+						"currency": activeCurrency().Code,
+						"goals":    []GoalProgress{},
+						"message":  fmt.Sprintf("No goal %s was found on your account.", id),
+					}}, nil
+				}
+			}
+
+			// A user-reported amount, the savings balance or the ledger can only be attributed to a single goal
+			saved, source := 0.0, savedFromContributions
+			if len(goals) == 1 {
+				if strings.TrimSpace(params.AmountSaved) != "" {
+					saved, source = reported, savedFromUser
+				} else if balance, ok := fetchSavingsBalance(ctx, liminalExecutor, toolParams.UserID); ok {
+					saved, source = balance, savedFromSavings
+				} else if contributions, err := store.ListContributions(ctx, userKey(toolParams.UserID)); err == nil {
+					if total, ok := contributedSince(contributions, goals[0].CreatedAt); ok {
+						saved, source = total, savedFromLedger
+					}
+				}
+			}
+
+			now := time.Now()
+			progress := make([]GoalProgress, 0, len(goals))
+			for _, goal := range goals {
+				p, err := goalProgress(goal, saved, source, now)
+				if err != nil {
+					return &core.ToolResult{Success: false, Error: err.Error()}, nil
+				}
+				progress = append(progress, p)
+			}
+			return &core.ToolResult{Success: true, Data: map[string]interface{}{"currency": activeCurrency().Code, "goals": progress}}, nil
+		}).
+		Build()
+}
+
+// goalProgress measures one goal at now, projecting at goalReturn's mean with the monthly
+// contribution raised by the goal's annual increase each year since creation. With source
+// savedFromContributions the amount saved is estimated as every scheduled contribution since
+// creation, compounded, and the result says so in AmountAssumption.
+func goalProgress(goal storage.Goal, saved float64, source string, now time.Time) (GoalProgress, error) {
+	returnRate, _ := goalReturn(goal.InvestmentType)
+	target, err := time.Parse("2006-01-02", goal.TargetDate)
+	if err != nil {
+		return GoalProgress{}, fmt.Errorf("goal %s has an invalid target_date %q: %v", goal.ID, goal.TargetDate, err)
+	}
+	elapsed := max(monthsUntil(goal.CreatedAt, now), 0)
+	remaining := max(monthsUntil(now, target), 0)
+	increase := goal.AnnualIncreasePct
+	monthly := goal.MonthlyContribution * math.Pow(1+increase/100, float64(elapsed/12))
+	assumption := ""
+	if source == savedFromContributions {
+		saved = escalatingFutureValue(0, goal.MonthlyContribution, returnRate, increase, float64(elapsed))
+		rising := ""
+		if increase != 0 {
+			rising = fmt.Sprintf(" rising %g%% a year", increase)
+		}
+		assumption = fmt.Sprintf("Estimated, not measured: assumes all %d scheduled contributions (%s/month%s) were made and earned %.1f%% a year. Pass amount_saved for an exact figure.",
+			elapsed, formatMoney(goal.MonthlyContribution), rising, returnRate)
+	}
+
+	p := GoalProgress{
+		GoalID:           goal.ID,
+		GoalName:         goal.Name,
+		TargetAmountUSD:  goal.TargetAmount,
+		TargetDate:       goal.TargetDate,
+		AmountSavedUSD:   saved,
+		AmountSource:     source,
+		AmountAssumption: assumption,
+		MonthsElapsed:    elapsed,
+		MonthsRemaining:  remaining,
+		MonthlyUSD:       monthly,
+		AnnualIncrease:   increase,
+	}
+	if goal.TargetAmount > 0 {
+		p.PercentComplete = min(saved/goal.TargetAmount*100, 100)
+	}
+
+	switch {
+	case saved >= goal.TargetAmount:
+		p.OnPace = true
+		p.Message = fmt.Sprintf("'%s' is fully funded - %s saved of %s.", goal.Name, formatMoney(saved), formatMoney(goal.TargetAmount))
+	case remaining < 1:
+		p.Message = fmt.Sprintf("The target date for '%s' has passed with %s still to go. Consider moving the date out.",
+			goal.Name, formatMoney(goal.TargetAmount-saved))
+	default:
+		// Projected from today's contribution level, with the next raise counted 12 months out
+		funding := goalFundingStatus(goal.TargetAmount, saved, monthly, returnRate, increase, remaining)
+		p.OnPace = funding.OnTrack
+		p.ProjectedTotalUSD = funding.ProjectedTotalUSD
+		if !funding.OnTrack {
+			p.RevisedMonthlyUSD = funding.RequiredMonthlyUSD
+		}
+		p.Message = funding.Message
+		odds := goalSuccessOdds(goal.TargetAmount, saved, monthly, increase, remaining, goal.InvestmentType)
+		p.GoalOdds = &odds
+		p.Message += fmt.Sprintf(". Allowing for market swings, reaching it is %s (about %.0f%%).", odds.Band, odds.Probability)
+	}
+	if assumption != "" {
+		p.Message += " " + assumption
+	}
+	return p, nil
+}
+
+// contributedSince sums ledger entries dated on or after since; ok is false when there are none
+func contributedSince(contributions []storage.Contribution, since time.Time) (total float64, ok bool) {
+	day := since.Format("2006-01-02")
+	for _, c := range contributions {
+		if c.Date.Format("2006-01-02") >= day {
+			total, ok = total+c.Amount, true
+		}
+	}
+	return total, ok
+}
+
+// fetchSavingsBalance reads the user's savings balance through Liminal; ok is false if unavailable
+func fetchSavingsBalance(ctx context.Context, liminalExecutor core.ToolExecutor, userID string) (float64, bool) {
+	return fetchBalance(ctx, liminalExecutor, userID, "get_savings_balance")
+}
+
+// fetchBalance reads a balance from a Liminal balance tool; ok is false if unavailable
+func fetchBalance(ctx context.Context, liminalExecutor core.ToolExecutor, userID, tool string) (float64, bool) {
+	resp, err := liminalExecutor.Execute(ctx, &core.ExecuteRequest{
+		UserID:    userID,
+		Tool:      tool,
+		Input:     json.RawMessage(`{}`),
+		RequestID: "req_" + generateRandomID(),
+	})
+	if err != nil || !resp.Success {
+		return 0, false
+	}
+	var data map[string]interface{}
+	if err := json.Unmarshal(resp.Data, &data); err != nil {
+		return 0, false
+	}
+	for _, key := range balanceKeys {
+		switch v := data[key].(type) {
+		case float64:
+			return v, true
+		case string:
+			if n, err := parseAmount(v); err == nil {
+				return n, true
+			}
+		}
+	}
+	return 0, false
+}
+
+// sensitivityReturnDelta is the ± annual return % shown alongside a required contribution
+const sensitivityReturnDelta = 2.0
+
+// RequiredContribution answers "how much per month to reach $X in Y years?"
+type RequiredContribution struct {
+	Currency            string             `json:"currency"`
+	TargetAmountUSD     float64            `json:"target_amount_usd"`
+	CurrentAmountUSD    float64            `json:"current_amount_usd"`
+	Years               float64            `json:"years"`
+	Months              int                `json:"months"`
+	AssumedAnnualReturn float64            `json:"assumed_annual_return"`
+	RequiredMonthlyUSD  float64            `json:"required_monthly_usd"`
+	Sensitivity         map[string]float64 `json:"sensitivity"` // required monthly at return -2% and +2%
+	MonthlyCapacityUSD  float64            `json:"monthly_capacity_usd"`
+	CapacitySource      string             `json:"capacity_source"` // user_reported or profile
+	Feasible            bool               `json:"feasible"`
+	Message             string             `json:"message"`
+}
+
+// newRequiredContributionTool inverts the compound growth projection
+func newRequiredContributionTool() core.Tool {
+	return tools.New("solve_required_contribution").
+		Description("Work out how much the user needs to invest each month to reach a target amount by a horizon, with sensitivity to returns 2% higher or lower").
+		Schema(tools.ObjectSchema(map[string]interface{}{
+			"target_amount":    tools.StringProperty("Amount the user wants to reach in the account currency"),
+			"years":            tools.StringProperty("Years until the money is needed (fractions like '1.5' allowed)"),
+			"current_amount":   tools.StringProperty("Optional amount already invested toward the target in the account currency"),
+			"expected_return":  tools.StringProperty(fmt.Sprintf("Optional expected annual return percentage (defaults to the server's assumption, currently %g)", appConfig.Assumptions.EquityReturnPct)),
+			"monthly_capacity": tools.StringProperty("Optional most the user could invest each month in the account currency; defaults to their profile's monthly savings"),
+		}, "target_amount", "years")).
+		Handler(func(ctx context.Context, toolParams *core.ToolParams) (*core.ToolResult, error) {
+			var params struct {
+				TargetAmount    string `json:"target_amount"`
+				Years           string `json:"years"`
+				CurrentAmount   string `json:"current_amount"`
+				ExpectedReturn  string `json:"expected_return"`
+				MonthlyCapacity string `json:"monthly_capacity"`
+			}
+			if err := json.Unmarshal(toolParams.Input, &params); err != nil {
+				return &core.ToolResult{Success: false, Error: fmt.Sprintf("invalid input: %v", err)}, nil
+			}
+
+			var v amountValidator
+			target := v.positive("target_amount", params.TargetAmount)
+			years := v.years("years", params.Years, 1.0/12, maxProjectionYears)
+			current := v.nonNegative("current_amount", params.CurrentAmount, false)
+			returnRate := v.returnRate("expected_return", params.ExpectedReturn, false)
+			capacity := v.nonNegative("monthly_capacity", params.MonthlyCapacity, false)
+			if err := v.err(); err != nil {
+				return &core.ToolResult{Success: false, Error: err.Error()}, nil
+			}
+
+			source := "user_reported"
+			if strings.TrimSpace(params.MonthlyCapacity) == "" {
+				portfolio, err := loadPortfolio(ctx, toolParams.UserID)
+				if err != nil {
+					return &core.ToolResult{Success: false, Error: profileFieldError(err, "monthly_capacity").Error()}, nil
+				}
+				capacity, source = portfolio.MonthlySavings, "profile"
+			}
+
+			result := requiredContribution(target, current, returnRate, years, capacity)
+			result.CapacitySource = source
+			return &core.ToolResult{Success: true, Data: result}, nil
+		}).
+		Build()
+}
+
+// requiredContribution solves for the monthly amount and judges it against capacity
+func requiredContribution(target, current, returnRate, years, capacity float64) RequiredContribution {
+	months := max(int(math.Round(years*12)), 1)
+	required := requiredMonthlyContribution(target, current, returnRate, float64(months))
+	r := RequiredContribution{
+		Currency:            activeCurrency().Code,
+		TargetAmountUSD:     target,
+		CurrentAmountUSD:    current,
+		Years:               years,
+		Months:              months,
+		AssumedAnnualReturn: returnRate,
+		RequiredMonthlyUSD:  required,
+		Sensitivity: map[string]float64{
+			fmt.Sprintf("return_%gpct", returnRate-sensitivityReturnDelta): requiredMonthlyContribution(target, current, returnRate-sensitivityReturnDelta, float64(months)),
+			fmt.Sprintf("return_%gpct", returnRate+sensitivityReturnDelta): requiredMonthlyContribution(target, current, returnRate+sensitivityReturnDelta, float64(months)),
+		},
+		MonthlyCapacityUSD: capacity,
+		Feasible:           required <= capacity,
+	}
+
+	switch {
+	case required == 0:
+		r.Message = fmt.Sprintf("%s already grows to %s in %d months at %g%% - no monthly contribution needed.",
+			formatMoney(current), formatMoney(futureValue(current, 0, returnRate, float64(months))), months, returnRate)
+	case r.Feasible:
+		r.Message = fmt.Sprintf("Invest %s/month for %d months at %g%% to reach %s.", formatMoney(required), months, returnRate, formatMoney(target))
+	default:
+		r.Message = fmt.Sprintf("Reaching %s in %d months needs %s/month, more than the %s/month available. Consider a longer horizon or a smaller target.",
+			formatMoney(target), months, formatMoney(required), formatMoney(capacity))
+	}
+	return r
+}
@@ -0,0 +1,87 @@
+package main
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"vibe-invest/storage"
+)
+
+func TestGoalProgressEstimateIsLabelled(t *testing.T) {
+	now := time.Date(2026, 6, 1, 0, 0, 0, 0, time.UTC)
+	goal := storage.Goal{ID: "goal_1", Name: "House", TargetAmount: 50000, TargetDate: "2030-06-01",
+		MonthlyContribution: 500, CreatedAt: now.AddDate(-1, 0, 0)}
+
+	estimated, err := goalProgress(goal, 0, savedFromContributions, now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if estimated.AmountAssumption == "" || !strings.Contains(estimated.Message, estimated.AmountAssumption) {
+		t.Errorf("estimated progress should state its assumption: %+v", estimated)
+	}
+
+	measured, err := goalProgress(goal, 6000, savedFromLedger, now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if measured.AmountAssumption != "" {
+		t.Errorf("ledger progress carries an assumption: %q", measured.AmountAssumption)
+	}
+}
+
+func TestGoalProgressRejectsBadTargetDate(t *testing.T) {
+	goal := storage.Goal{ID: "goal_bad", TargetAmount: 1000, TargetDate: "06/01/2030", MonthlyContribution: 100}
+	if _, err := goalProgress(goal, 0, savedFromContributions, time.Now()); err == nil || !strings.Contains(err.Error(), "goal_bad") {
+		t.Errorf("error = %v, want an invalid target_date error naming the goal", err)
+	}
+}
+
+func TestGoalProgressUsesInvestmentTypeReturn(t *testing.T) {
+	clearVaultRate(t)
+	now := time.Date(2026, 6, 1, 0, 0, 0, 0, time.UTC)
+	for _, investmentType := range []string{"stocks", "etfs", "diversified", "savings", ""} {
+		t.Run(investmentType, func(t *testing.T) {
+			goal := storage.Goal{ID: "goal_1", Name: "House", TargetAmount: 60000, TargetDate: "2031-06-01",
+				MonthlyContribution: 800, InvestmentType: investmentType, CreatedAt: now}
+			p, err := goalProgress(goal, 1000, savedFromUser, now)
+			if err != nil {
+				t.Fatal(err)
+			}
+			mean, _ := goalReturn(investmentType)
+			if p.GoalOdds == nil || p.MeanReturnPercent != mean {
+				t.Fatalf("odds = %+v, want the %v%% mean", p.GoalOdds, mean)
+			}
+			want := escalatingFutureValue(1000, 800, mean, 0, 60)
+			if !approxEqual(p.ProjectedTotalUSD, want) {
+				t.Errorf("projected %v, want %v at the same %v%% the odds assume", p.ProjectedTotalUSD, want, mean)
+			}
+			if p.OnPace != (want >= goal.TargetAmount) {
+				t.Errorf("on_pace = %v with %v projected against %v", p.OnPace, want, goal.TargetAmount)
+			}
+		})
+	}
+}
+
+func TestGoalProgressKeepsAnnualIncrease(t *testing.T) {
+	now := time.Date(2026, 6, 1, 0, 0, 0, 0, time.UTC)
+	goal := storage.Goal{ID: "goal_1", Name: "College", TargetAmount: 100000, TargetDate: "2036-06-01",
+		MonthlyContribution: 500, AnnualIncreasePct: 5, InvestmentType: "diversified", CreatedAt: now.AddDate(-2, 0, 0)}
+	flat := goal
+	flat.AnnualIncreasePct = 0
+
+	rising, err := goalProgress(goal, 0, savedFromContributions, now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	level, err := goalProgress(flat, 0, savedFromContributions, now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !approxEqual(rising.MonthlyUSD, 500*1.05*1.05) {
+		t.Errorf("monthly after two raises = %v, want %v", rising.MonthlyUSD, 500*1.05*1.05)
+	}
+	if rising.AnnualIncrease != 5 || rising.AmountSavedUSD <= level.AmountSavedUSD || rising.ProjectedTotalUSD <= level.ProjectedTotalUSD {
+		t.Errorf("the increase was dropped: rising %+v, flat %+v", rising, level)
+	}
+}
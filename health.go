@@ -0,0 +1,410 @@
+package main
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/becomeliminal/nim-go-sdk/core"
+	"github.com/becomeliminal/nim-go-sdk/tools"
+
+	"vibe-invest/storage"
+)
+
+// ============================================
+// PORTFOLIO HEALTH SCORE
+// ============================================
+// Six components, each scored 0-100 from numbers that are reported alongside
+// it, are combined into one score using the weights below. A component without
+// the data to score it (no recorded holdings, no debt figure) is left out and
+// the remaining weights are rescaled, so missing data neither helps nor hurts.
+// The top actions are the two components losing the most weighted points.
+
+// Health score components
+const (
+	healthEmergencyFund   = "emergency_fund"
+	healthSavingsRate     = "savings_rate"
+	healthAllocationDrift = "allocation_drift"
+	healthFees            = "fees"
+	healthDiversification = "diversification"
+	healthDebt            = "debt"
+)
+
+// healthComponents is the order components are reported in
+var healthComponents = []string{healthEmergencyFund, healthSavingsRate, healthAllocationDrift, healthFees, healthDiversification, healthDebt}
+
+// healthWeights maps components to their relative weight in the score
+type healthWeights map[string]float64
+
+// defaultHealthWeights is each component's share of the score; HEALTH_SCORE_WEIGHTS overrides entries
+var defaultHealthWeights = healthWeights{
+	healthEmergencyFund:   25,
+	healthSavingsRate:     20,
+	healthAllocationDrift: 15,
+	healthFees:            10,
+	healthDiversification: 15,
+	healthDebt:            15,
+}
+
+// Health scoring anchors
+const (
+	healthTargetSavingsPct = 20.0 // savings rate that scores full marks, as calculate_smart_savings_rate recommends
+	healthMaxDriftPct      = 25.0 // drift from target, in points, that scores zero
+	healthLowFeePct        = 0.10 // weighted expense ratio at or below which fees score full marks
+	healthHighFeePct       = 1.00 // and at or above which they score zero
+	healthIssuePenalty     = 20.0 // points off diversification per position or sector over its cap
+	healthDebtBase         = 50.0 // debt score with any high-interest debt, before the per-month penalty
+	healthDebtMonthPenalty = 10.0 // points off per month of income the debt amounts to
+	healthDebtNoIncome     = 25.0 // debt score when there is debt but no income to size it against
+)
+
+// Health ratings by score
+const (
+	healthStrong    = "strong"     // 80 and up
+	healthFair      = "fair"       // 60 to 79
+	healthNeedsWork = "needs_work" // below 60
+)
+
+// healthInputs is what the score is built from; the Has flags mark figures that are known
+type healthInputs struct {
+	Portfolio        InvestmentPortfolio
+	Income           float64
+	Expenses         float64
+	EmergencyFund    float64
+	MonthlySavings   float64
+	HighInterestDebt float64
+	Stability        string // income stability, for the emergency fund target
+	HasIncome        bool
+	HasExpenses      bool
+	HasEmergencyFund bool
+	HasDebt          bool
+}
+
+// newHealthScoreTool answers "how am I doing?" with one score and what to improve
+func newHealthScoreTool(liminalExecutor core.ToolExecutor) core.Tool {
+	return tools.New("portfolio_health_score").
+		Description("Give the user one 0-100 financial health score combining emergency fund coverage, savings rate, allocation drift from their target, fund fees, diversification and high-interest debt. Every component comes with its sub-score, weight and the numbers behind it, plus the two actions that would raise the score most. Omitted figures are read from Liminal and the user's recorded holdings and plans where possible").
+		Schema(tools.ObjectSchema(map[string]interface{}{
+			"monthly_income":     tools.StringProperty("Optional monthly income in the account currency (detected from transaction history when omitted)"),
+			"monthly_expenses":   tools.StringProperty("Optional average monthly spending in the account currency (derived from transaction history when omitted)"),
+			"emergency_fund":     tools.StringProperty("Optional emergency fund balance in the account currency (the Liminal savings balance when omitted)"),
+			"monthly_savings":    tools.StringProperty("Optional amount saved or invested each month (the user's active plans when omitted)"),
+			"high_interest_debt": tools.StringProperty(fmt.Sprintf("Optional balance of debt at or above %g%% APR; 0 for none. The debt component is left out when omitted", appConfig.HighAPRThreshold)),
+		})).
+		Handler(func(ctx context.Context, toolParams *core.ToolParams) (*core.ToolResult, error) {
+			var params struct {
+				MonthlyIncome    string `json:"monthly_income"`
+				MonthlyExpenses  string `json:"monthly_expenses"`
+				EmergencyFund    string `json:"emergency_fund"`
+				MonthlySavings   string `json:"monthly_savings"`
+				HighInterestDebt string `json:"high_interest_debt"`
+			}
+			if len(toolParams.Input) > 0 {
+				if err := json.Unmarshal(toolParams.Input, &params); err != nil {
+					return &core.ToolResult{Success: false, Error: fmt.Sprintf("invalid input: %v", err)}, nil
+				}
+			}
+			var in healthInputs
+			var v amountValidator
+			given := func(raw string) bool { return strings.TrimSpace(raw) != "" }
+			if in.HasIncome = given(params.MonthlyIncome); in.HasIncome {
+				in.Income = v.positive("monthly_income", params.MonthlyIncome)
+			}
+			if in.HasExpenses = given(params.MonthlyExpenses); in.HasExpenses {
+				in.Expenses = v.positive("monthly_expenses", params.MonthlyExpenses)
+			}
+			if in.HasEmergencyFund = given(params.EmergencyFund); in.HasEmergencyFund {
+				in.EmergencyFund = v.nonNegative("emergency_fund", params.EmergencyFund, true)
+			}
+			if in.HasDebt = given(params.HighInterestDebt); in.HasDebt {
+				in.HighInterestDebt = v.nonNegative("high_interest_debt", params.HighInterestDebt, true)
+			}
+			savingsGiven := given(params.MonthlySavings)
+			if savingsGiven {
+				in.MonthlySavings = v.nonNegative("monthly_savings", params.MonthlySavings, true)
+			}
+			if err := v.err(); err != nil {
+				return &core.ToolResult{Success: false, Error: err.Error()}, nil
+			}
+
+			// Without a profile there are no holdings, so drift, fees and diversification go unscored
+			portfolio, err := loadPortfolio(ctx, toolParams.UserID)
+			if err != nil && !errors.Is(err, errNoProfile) {
+				return &core.ToolResult{Success: false, Error: fmt.Sprintf("could not load profile: %v", err)}, nil
+			}
+			in.Portfolio = portfolio
+			if !savingsGiven {
+				plans, err := listPlans(ctx, userKey(toolParams.UserID))
+				if err != nil {
+					return &core.ToolResult{Success: false, Error: fmt.Sprintf("could not load plans: %v", err)}, nil
+				}
+				for _, p := range plans {
+					if p.Status == storage.PlanActive {
+						in.MonthlySavings += p.MonthlyAmount
+					}
+				}
+			}
+			if income, ok := loadDetectedIncome(ctx, liminalExecutor, toolParams.UserID); ok {
+				in.Stability = income.Stability
+				if !in.HasIncome {
+					in.Income, in.HasIncome = income.MonthlyIncomeUSD, true
+				}
+			}
+			if !in.HasExpenses {
+				in.Expenses, in.HasExpenses = fetchMonthlySpend(ctx, liminalExecutor, toolParams.UserID, time.Now())
+			}
+			if !in.HasEmergencyFund {
+				in.EmergencyFund, in.HasEmergencyFund = fetchSavingsBalance(ctx, liminalExecutor, toolParams.UserID)
+			}
+			return &core.ToolResult{Success: true, Data: scoreHealth(in, appConfig.HealthWeights)}, nil
+		}).
+		Build()
+}
+
+// scoreHealth scores every component it has data for and weights them into the overall score
+func scoreHealth(in healthInputs, weights healthWeights) HealthScore {
+	scorers := map[string]func(healthInputs) (HealthComponent, bool){
+		healthEmergencyFund:   healthEmergencyScore,
+		healthSavingsRate:     healthSavingsScore,
+		healthAllocationDrift: healthDriftScore,
+		healthFees:            healthFeeScore,
+		healthDiversification: healthDiversificationScore,
+		healthDebt:            healthDebtScore,
+	}
+	r := HealthScore{Components: []HealthComponent{}, TopActions: []string{}, MissingData: []string{}}
+	totalWeight := 0.0
+	for _, name := range healthComponents {
+		c, ok := scorers[name](in)
+		c.Name, c.Weight, c.Available = name, weights[name], ok
+		if ok && c.Weight > 0 {
+			totalWeight += c.Weight
+		} else if !ok {
+			r.MissingData = append(r.MissingData, name)
+		}
+		r.Components = append(r.Components, c)
+	}
+	if totalWeight == 0 {
+		r.Message = "There isn't enough information to score any component yet: record holdings, or pass income, expenses and savings."
+		return r
+	}
+
+	for i := range r.Components {
+		c := &r.Components[i]
+		if !c.Available || c.Weight <= 0 {
+			continue
+		}
+		c.WeightPercent = c.Weight / totalWeight * 100
+		c.PointsLost = c.WeightPercent * (100 - c.Score) / 100
+		r.Score += c.WeightPercent * c.Score / 100
+	}
+	r.Score = math.Round(r.Score)
+	switch {
+	case r.Score >= 80:
+		r.Rating = healthStrong
+	case r.Score >= 60:
+		r.Rating = healthFair
+	default:
+		r.Rating = healthNeedsWork
+	}
+
+	ranked := slices.Clone(r.Components)
+	slices.SortStableFunc(ranked, func(a, b HealthComponent) int { return cmp.Compare(b.PointsLost, a.PointsLost) })
+	for _, c := range ranked {
+		if len(r.TopActions) == 2 || c.PointsLost < 0.5 {
+			break
+		}
+		r.TopActions = append(r.TopActions, c.Action)
+	}
+
+	r.Message = fmt.Sprintf("Financial health: %.0f/100 (%s).", r.Score, strings.ReplaceAll(r.Rating, "_", " "))
+	if len(r.TopActions) > 0 {
+		r.Message += " Biggest improvements: " + strings.Join(r.TopActions, "; ") + "."
+	}
+	if len(r.MissingData) > 0 {
+		r.Message += fmt.Sprintf(" Not scored for lack of data: %s.", strings.Join(r.MissingData, ", "))
+	}
+	return r
+}
+
+// healthEmergencyScore is months of expenses covered against the target for the income's stability
+func healthEmergencyScore(in healthInputs) (HealthComponent, bool) {
+	if !in.HasExpenses || !in.HasEmergencyFund || in.Expenses <= 0 {
+		return HealthComponent{}, false
+	}
+	stability := in.Stability
+	if stability == "" {
+		stability = "moderate"
+	}
+	target := float64(emergencyMonthsByStability[stability])
+	months := in.EmergencyFund / in.Expenses
+	c := HealthComponent{
+		Score: math.Min(months/target, 1) * 100,
+		Numbers: map[string]float64{
+			"emergency_fund_usd":   in.EmergencyFund,
+			"monthly_expenses_usd": in.Expenses,
+			"months_covered":       months,
+			"target_months":        target,
+		},
+		Detail: fmt.Sprintf("%.1f months of expenses saved against a %.0f-month target for %s income", months, target, stability),
+		Action: fmt.Sprintf("Add %s to the emergency fund to cover %.0f months of expenses", formatMoney(target*in.Expenses-in.EmergencyFund), target),
+	}
+	return c, true
+}
+
+// healthSavingsScore is the monthly savings rate against healthTargetSavingsPct
+func healthSavingsScore(in healthInputs) (HealthComponent, bool) {
+	if !in.HasIncome || in.Income <= 0 {
+		return HealthComponent{}, false
+	}
+	rate := in.MonthlySavings / in.Income * 100
+	c := HealthComponent{
+		Score: math.Min(rate/healthTargetSavingsPct, 1) * 100,
+		Numbers: map[string]float64{
+			"monthly_savings_usd":  in.MonthlySavings,
+			"monthly_income_usd":   in.Income,
+			"savings_rate_percent": rate,
+			"target_percent":       healthTargetSavingsPct,
+		},
+		Detail: fmt.Sprintf("Saving %s of %s a month, %.1f%% of income", formatMoney(in.MonthlySavings), formatMoney(in.Income), rate),
+		Action: fmt.Sprintf("Save %s more a month to reach %g%% of income", formatMoney(in.Income*healthTargetSavingsPct/100-in.MonthlySavings), healthTargetSavingsPct),
+	}
+	return c, true
+}
+
+// healthDriftScore is the largest drift of the portfolio from its risk level's target allocation.
+// It needs recorded holdings: the profile only splits stocks from savings, which would read as
+// an empty bond allocation.
+func healthDriftScore(in healthInputs) (HealthComponent, bool) {
+	if len(in.Portfolio.Holdings) == 0 {
+		return HealthComponent{}, false
+	}
+	current := holdingTotals(in.Portfolio.Holdings)
+	if _, ok := riskAllocationModel[in.Portfolio.RiskTolerance]; !ok {
+		return HealthComponent{}, false
+	}
+	_, maxDrift, outside, total := allocationDriftOf(current, in.Portfolio.RiskTolerance)
+	if total <= 0 {
+		return HealthComponent{}, false
+	}
+	c := HealthComponent{
+		Score: math.Max(1-maxDrift/healthMaxDriftPct, 0) * 100,
+		Numbers: map[string]float64{
+			"portfolio_value_usd": total,
+			"max_drift_points":    maxDrift,
+			"zero_score_drift":    healthMaxDriftPct,
+		},
+		Detail: fmt.Sprintf("Largest asset class is %.1f points from the %s target allocation", maxDrift, in.Portfolio.RiskTolerance),
+		Action: fmt.Sprintf("Rebalance toward the %s target allocation (largest drift %.1f points; see rebalance_investment_portfolio)", in.Portfolio.RiskTolerance, maxDrift),
+	}
+	if outside {
+		c.Detail += ", outside its rebalancing band"
+	}
+	return c, true
+}
+
+// healthFeeScore is the value-weighted expense ratio of recorded holdings whose cost is known:
+// funds in data/etfs.json, single stocks and cash (which cost nothing to hold)
+func healthFeeScore(in healthInputs) (HealthComponent, bool) {
+	known, total, annual := 0.0, 0.0, 0.0
+	for _, h := range in.Portfolio.Holdings {
+		total += h.Value
+		ratio, ok := etfExpenseRatio(h.Identifier)
+		if !ok && (h.AssetClass == "cash" || tickerSectors[h.Identifier] != "" && tickerSectors[h.Identifier] != sectorBroadMarket) {
+			ratio, ok = 0, true
+		}
+		if ok {
+			known += h.Value
+			annual += h.Value * ratio / 100
+		}
+	}
+	if known <= 0 {
+		return HealthComponent{}, false
+	}
+	fee := annual / known * 100
+	c := HealthComponent{
+		Score: math.Min(math.Max((healthHighFeePct-fee)/(healthHighFeePct-healthLowFeePct), 0), 1) * 100,
+		Numbers: map[string]float64{
+			"weighted_expense_ratio_percent": fee,
+			"annual_fees_usd":                annual,
+			"covered_percent":                known / total * 100,
+		},
+		Detail: fmt.Sprintf("Weighted expense ratio %.2f%% (%s a year) across %.0f%% of holdings with known costs", fee, formatMoney(annual), known/total*100),
+		Action: fmt.Sprintf("Move toward low-cost index funds; fees of %.2f%% cost about %s a year (see fee_drag_calculator)", fee, formatMoney(annual)),
+	}
+	return c, true
+}
+
+// healthDiversificationScore counts positions and sectors over their caps in the recorded holdings
+func healthDiversificationScore(in healthInputs) (HealthComponent, bool) {
+	if len(in.Portfolio.Holdings) == 0 {
+		return HealthComponent{}, false
+	}
+	r := checkConcentration(concentrationHoldings(in.Portfolio.Holdings), appConfig.PositionCapPct, appConfig.SectorCapPct)
+	if r.TotalValueUSD <= 0 {
+		return HealthComponent{}, false
+	}
+	issues, largestPosition, largestSector := 0, 0.0, 0.0
+	for _, p := range r.Positions {
+		if p.OverLimit {
+			issues++
+		}
+		if p.Sector != sectorBroadMarket {
+			largestPosition = math.Max(largestPosition, p.Percent)
+		}
+	}
+	for _, s := range r.Sectors {
+		if s.OverLimit {
+			issues++
+		}
+		if s.Sector != sectorBroadMarket && s.Sector != sectorUnclassified {
+			largestSector = math.Max(largestSector, s.Percent)
+		}
+	}
+	c := HealthComponent{
+		Score: math.Max(100-healthIssuePenalty*float64(issues), 0),
+		Numbers: map[string]float64{
+			"holdings":                 float64(len(in.Portfolio.Holdings)),
+			"largest_position_percent": largestPosition,
+			"largest_sector_percent":   largestSector,
+			"over_cap_count":           float64(issues),
+		},
+		Detail: fmt.Sprintf("%d position(s) or sector(s) over the %g%%/%g%% caps", issues, appConfig.PositionCapPct, appConfig.SectorCapPct),
+		Action: "Keep diversified: no single position or sector is over its cap",
+	}
+	if len(r.Suggestions) > 0 {
+		c.Action = r.Suggestions[0]
+	}
+	return c, true
+}
+
+// healthDebtScore marks down high-interest debt by how many months of income it amounts to
+func healthDebtScore(in healthInputs) (HealthComponent, bool) {
+	if !in.HasDebt {
+		return HealthComponent{}, false
+	}
+	c := HealthComponent{
+		Score:   100,
+		Numbers: map[string]float64{"high_interest_debt_usd": in.HighInterestDebt, "apr_threshold_percent": appConfig.HighAPRThreshold},
+		Detail:  "No high-interest debt",
+		Action:  "Stay clear of high-interest debt",
+	}
+	if in.HighInterestDebt <= 0 {
+		return c, true
+	}
+	c.Score = healthDebtNoIncome
+	c.Detail = fmt.Sprintf("%s of debt at or above %g%% APR", formatMoney(in.HighInterestDebt), appConfig.HighAPRThreshold)
+	if in.HasIncome && in.Income > 0 {
+		months := in.HighInterestDebt / in.Income
+		c.Numbers["months_of_income"] = months
+		c.Score = math.Max(healthDebtBase-healthDebtMonthPenalty*months, 0)
+		c.Detail += fmt.Sprintf(", %.1f months of income", months)
+	}
+	c.Action = fmt.Sprintf("Pay off the %s of high-interest debt before investing more (see debt_vs_invest_analyzer)", formatMoney(in.HighInterestDebt))
+	return c, true
+}
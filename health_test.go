@@ -0,0 +1,27 @@
+package main
+
+import (
+	"testing"
+
+	"vibe-invest/storage"
+)
+
+func TestHealthFeesUseTheETFDatabase(t *testing.T) {
+	// Every listed fund is priced from data/etfs.json, including ones the score once kept its own copy of
+	for _, f := range etfDatabase.Funds {
+		in := healthInputs{Portfolio: InvestmentPortfolio{Holdings: []storage.Holding{{Identifier: f.Ticker, AssetClass: "stocks", Value: 10000}}}}
+		c, ok := healthFeeScore(in)
+		if !ok {
+			t.Errorf("%s: no fee component", f.Ticker)
+			continue
+		}
+		if got := c.Numbers["weighted_expense_ratio_percent"]; !approxEqual(got, f.ExpenseRatio) {
+			t.Errorf("%s: expense ratio %v, want %v from the database", f.Ticker, got, f.ExpenseRatio)
+		}
+	}
+
+	unknown := healthInputs{Portfolio: InvestmentPortfolio{Holdings: []storage.Holding{{Identifier: "401K TARGET DATE FUND", AssetClass: "stocks", Value: 5000}}}}
+	if c, ok := healthFeeScore(unknown); ok {
+		t.Errorf("a fund with no known cost was scored: %+v", c)
+	}
+}
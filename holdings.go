@@ -0,0 +1,279 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/becomeliminal/nim-go-sdk/core"
+	"github.com/becomeliminal/nim-go-sdk/tools"
+
+	"vibe-invest/storage"
+)
+
+// ============================================
+// HOLDINGS
+// ============================================
+// Users can tell the assistant what they own, one holding at a time. Holdings
+// live on the stored portfolio; once there are any, the rebalancer and the
+// concentration checker use them whenever the user doesn't pass amounts, while
+// users who never enter holdings keep working from the aggregate profile.
+
+// holdingSectors gives non-stock holdings a sector for the concentration checker
+var holdingSectors = map[string]string{
+	"reit":  "real_estate",
+	"bonds": "bonds",
+	"cash":  "cash",
+}
+
+// normalizeIdentifier upper-cases tickers and collapses whitespace in names so lookups ignore case
+func normalizeIdentifier(raw string) string {
+	return strings.ToUpper(strings.Join(strings.Fields(raw), " "))
+}
+
+// portfolioLocks serializes read-modify-write cycles on one user's stored portfolio, so two
+// concurrent edits can't both load the same holdings and have the second save drop the first's change
+var portfolioLocks = &userLocks{locks: map[string]*userLock{}}
+
+// userLocks hands out one mutex per user, dropped once nobody holds or waits for it
+type userLocks struct {
+	mu    sync.Mutex
+	locks map[string]*userLock
+}
+
+type userLock struct {
+	mu   sync.Mutex
+	refs int
+}
+
+// lock blocks until the user's lock is free and returns the function that releases it
+func (l *userLocks) lock(userID string) (unlock func()) {
+	key := userKey(userID)
+	l.mu.Lock()
+	ul := l.locks[key]
+	if ul == nil {
+		ul = &userLock{}
+		l.locks[key] = ul
+	}
+	ul.refs++
+	l.mu.Unlock()
+
+	ul.mu.Lock()
+	return func() {
+		ul.mu.Unlock()
+		l.mu.Lock()
+		if ul.refs--; ul.refs == 0 {
+			delete(l.locks, key)
+		}
+		l.mu.Unlock()
+	}
+}
+
+// loadStoredPortfolio returns the user's stored portfolio, or an empty one to fill on first write
+func loadStoredPortfolio(ctx context.Context, userID string) (storage.Portfolio, error) {
+	p, err := store.GetPortfolio(ctx, userKey(userID))
+	if errors.Is(err, storage.ErrNotFound) {
+		return storage.Portfolio{UserID: userKey(userID)}, nil
+	}
+	return p, err
+}
+
+// loadHoldings returns the user's recorded holdings; a user without a profile has none
+func loadHoldings(ctx context.Context, userID string) ([]storage.Holding, error) {
+	portfolio, err := loadPortfolio(ctx, userID)
+	if errors.Is(err, errNoProfile) {
+		return nil, nil
+	}
+	return portfolio.Holdings, err
+}
+
+// holdingTotals sums holdings into rebalancer asset classes. Stocks, bonds and cash are always
+// present; international and REIT only when held, so the rebalancer folds them otherwise.
+func holdingTotals(holdings []storage.Holding) map[string]float64 {
+	totals := map[string]float64{"stocks": 0, "bonds": 0, "cash": 0}
+	for _, h := range holdings {
+		totals[h.AssetClass] += h.Value
+	}
+	return totals
+}
+
+// concentrationHoldings converts stored holdings for checkConcentration
+func concentrationHoldings(holdings []storage.Holding) []concentrationHolding {
+	out := make([]concentrationHolding, 0, len(holdings))
+	for _, h := range holdings {
+		out = append(out, concentrationHolding{Ticker: h.Identifier, Sector: holdingSectors[h.AssetClass], Value: h.Value})
+	}
+	return out
+}
+
+// newHoldingsSummary totals holdings by asset class for list_holdings and the write tools
+func newHoldingsSummary(holdings []storage.Holding, message string) HoldingsSummary {
+	s := HoldingsSummary{
+		Currency:          activeCurrency().Code,
+		Holdings:          append([]storage.Holding{}, holdings...),
+		AllocationUSD:     map[string]float64{},
+		AllocationPercent: map[string]float64{},
+		Message:           message,
+	}
+	for _, h := range holdings {
+		s.AllocationUSD[h.AssetClass] += h.Value
+		s.TotalValueUSD += h.Value
+	}
+	for asset, value := range s.AllocationUSD {
+		if s.TotalValueUSD > 0 {
+			s.AllocationPercent[asset] = value / s.TotalValueUSD * 100
+		}
+	}
+	s.TotalValue = formatMoney(s.TotalValueUSD)
+	return s
+}
+
+// newAddHoldingTool records a position, replacing any existing one with the same identifier
+func newAddHoldingTool() core.Tool {
+	return tools.New("add_holding").
+		Description("Record something the user owns (a stock, fund, bond or cash position) so the rebalancer and concentration checker can use their real holdings. Adding an identifier that is already recorded replaces it").
+		Schema(tools.ObjectSchema(map[string]interface{}{
+			"identifier":  tools.StringProperty("Ticker (e.g. 'VOO') or a short name for the holding (e.g. '401k target date fund')"),
+			"asset_class": tools.StringProperty("Asset class: 'stocks' (domestic), 'international', 'reit', 'bonds' or 'cash'"),
+			"value":       tools.StringProperty("Current value in the account currency; optional when quantity and price are given"),
+			"quantity":    tools.StringProperty("Optional number of shares or units"),
+			"price":       tools.StringProperty("Optional price per share, used with quantity when value is omitted"),
+			"cost_basis":  tools.StringProperty("Optional total amount paid, used to estimate gains on sells"),
+		}, "identifier", "asset_class")).
+		Handler(func(ctx context.Context, toolParams *core.ToolParams) (*core.ToolResult, error) {
+			var params struct {
+				Identifier string `json:"identifier"`
+				AssetClass string `json:"asset_class"`
+				Value      string `json:"value"`
+				Quantity   string `json:"quantity"`
+				Price      string `json:"price"`
+				CostBasis  string `json:"cost_basis"`
+			}
+			if err := json.Unmarshal(toolParams.Input, &params); err != nil {
+				return &core.ToolResult{Success: false, Error: fmt.Sprintf("invalid input: %v", err)}, nil
+			}
+			if frozen, err := isReadOnly(ctx, toolParams.UserID); err != nil {
+				return &core.ToolResult{Success: false, Error: fmt.Sprintf("could not check account status: %v", err)}, nil
+			} else if frozen {
+				return readOnlyResult(), nil
+			}
+
+			var v amountValidator
+			identifier := normalizeIdentifier(params.Identifier)
+			if identifier == "" {
+				v.fail("identifier", "is required")
+			}
+			holding := storage.Holding{
+				Identifier: identifier,
+				AssetClass: v.oneOf("asset_class", params.AssetClass, rebalanceAssets),
+				Quantity:   v.nonNegative("quantity", params.Quantity, false),
+				UpdatedAt:  time.Now().UTC(),
+			}
+			switch price := v.nonNegative("price", params.Price, false); {
+			case strings.TrimSpace(params.Value) != "":
+				holding.Value = v.nonNegative("value", params.Value, true)
+			case holding.Quantity > 0 && price > 0:
+				holding.Value = holding.Quantity * price
+			default:
+				v.fail("value", "give the holding's value, or its quantity and price")
+			}
+			if strings.TrimSpace(params.CostBasis) != "" {
+				basis := v.nonNegative("cost_basis", params.CostBasis, true)
+				holding.CostBasis = &basis
+			}
+			if err := v.err(); err != nil {
+				return &core.ToolResult{Success: false, Error: err.Error()}, nil
+			}
+
+			defer portfolioLocks.lock(toolParams.UserID)()
+			portfolio, err := loadStoredPortfolio(ctx, toolParams.UserID)
+			if err != nil {
+				return &core.ToolResult{Success: false, Error: fmt.Sprintf("could not load holdings: %v", err)}, nil
+			}
+			verb := "Added"
+			holding.ID = "holding_" + generateRandomID()
+			for i, h := range portfolio.Holdings {
+				if h.Identifier == identifier {
+					holding.ID, verb = h.ID, "Updated"
+					portfolio.Holdings = append(portfolio.Holdings[:i], portfolio.Holdings[i+1:]...)
+					break
+				}
+			}
+			portfolio.Holdings = append(portfolio.Holdings, holding)
+			portfolio.UpdatedAt = holding.UpdatedAt
+			if err := store.SavePortfolio(ctx, portfolio); err != nil {
+				return &core.ToolResult{Success: false, Error: fmt.Sprintf("could not save holding: %v", err)}, nil
+			}
+			recordAudit(ctx, toolParams.UserID, "user", "add_holding", holding.ID)
+
+			return &core.ToolResult{Success: true, Data: newHoldingsSummary(portfolio.Holdings,
+				fmt.Sprintf("%s %s (%s, %s).", verb, identifier, holding.AssetClass, formatMoney(holding.Value)))}, nil
+		}).
+		Build()
+}
+
+// newRemoveHoldingTool deletes a recorded position by identifier or holding ID
+func newRemoveHoldingTool() core.Tool {
+	return tools.New("remove_holding").
+		Description("Remove a holding the user no longer owns").
+		Schema(tools.ObjectSchema(map[string]interface{}{
+			"identifier": tools.StringProperty("Ticker, name or holding_id of the holding to remove (from list_holdings)"),
+		}, "identifier")).
+		Handler(func(ctx context.Context, toolParams *core.ToolParams) (*core.ToolResult, error) {
+			var params struct {
+				Identifier string `json:"identifier"`
+			}
+			if err := json.Unmarshal(toolParams.Input, &params); err != nil {
+				return &core.ToolResult{Success: false, Error: fmt.Sprintf("invalid input: %v", err)}, nil
+			}
+			if frozen, err := isReadOnly(ctx, toolParams.UserID); err != nil {
+				return &core.ToolResult{Success: false, Error: fmt.Sprintf("could not check account status: %v", err)}, nil
+			} else if frozen {
+				return readOnlyResult(), nil
+			}
+
+			defer portfolioLocks.lock(toolParams.UserID)()
+			portfolio, err := loadStoredPortfolio(ctx, toolParams.UserID)
+			if err != nil {
+				return &core.ToolResult{Success: false, Error: fmt.Sprintf("could not load holdings: %v", err)}, nil
+			}
+			identifier := normalizeIdentifier(params.Identifier)
+			for i, h := range portfolio.Holdings {
+				if h.Identifier != identifier && h.ID != strings.TrimSpace(params.Identifier) {
+					continue
+				}
+				portfolio.Holdings = append(portfolio.Holdings[:i], portfolio.Holdings[i+1:]...)
+				portfolio.UpdatedAt = time.Now().UTC()
+				if err := store.SavePortfolio(ctx, portfolio); err != nil {
+					return &core.ToolResult{Success: false, Error: fmt.Sprintf("could not remove holding: %v", err)}, nil
+				}
+				recordAudit(ctx, toolParams.UserID, "user", "remove_holding", h.ID)
+				return &core.ToolResult{Success: true, Data: newHoldingsSummary(portfolio.Holdings, fmt.Sprintf("Removed %s.", h.Identifier))}, nil
+			}
+			return &core.ToolResult{Success: false, Error: fmt.Sprintf("No holding %q was found. Use list_holdings to see what is recorded.", params.Identifier)}, nil
+		}).
+		Build()
+}
+
+// newListHoldingsTool shows recorded holdings and the allocation they add up to
+func newListHoldingsTool() core.Tool {
+	return tools.New("list_holdings").
+		Description("List the holdings the user has recorded, with the total and the allocation by asset class they add up to").
+		Schema(tools.ObjectSchema(map[string]interface{}{})).
+		Handler(func(ctx context.Context, toolParams *core.ToolParams) (*core.ToolResult, error) {
+			holdings, err := loadHoldings(ctx, toolParams.UserID)
+			if err != nil {
+				return &core.ToolResult{Success: false, Error: fmt.Sprintf("could not load holdings: %v", err)}, nil
+			}
+			message := fmt.Sprintf("%d holding(s) recorded.", len(holdings))
+			if len(holdings) == 0 {
+				message = "No holdings recorded yet. Use add_holding to tell me what you own; until then tools use your profile's totals."
+			}
+			return &core.ToolResult{Success: true, Data: newHoldingsSummary(holdings, message)}, nil
+		}).
+		Build()
+}
@@ -0,0 +1,47 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+
+	"vibe-invest/storage"
+)
+
+func TestPortfolioLocksSerializeWrites(t *testing.T) {
+	ctx := context.Background()
+	const userID, writers = "holdings_lock_user", 20
+	var wg sync.WaitGroup
+	for i := range writers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			// The same load, append, save cycle add_holding runs
+			defer portfolioLocks.lock(userID)()
+			p, err := loadStoredPortfolio(ctx, userID)
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			p.Holdings = append(p.Holdings, storage.Holding{ID: fmt.Sprintf("holding_%d", i), Identifier: fmt.Sprintf("T%d", i), AssetClass: "stocks", Value: 100})
+			if err := store.SavePortfolio(ctx, p); err != nil {
+				t.Error(err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	p, err := loadStoredPortfolio(ctx, userID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(p.Holdings) != writers {
+		t.Errorf("%d holdings saved, want %d", len(p.Holdings), writers)
+	}
+	portfolioLocks.mu.Lock()
+	defer portfolioLocks.mu.Unlock()
+	if n := len(portfolioLocks.locks); n != 0 {
+		t.Errorf("%d locks left behind, want none", n)
+	}
+}
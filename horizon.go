@@ -0,0 +1,64 @@
+package main
+
+import (
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ============================================
+// TIME HORIZON PARSING
+// ============================================
+
+// defaultHorizonYears is assumed only when a horizon string is truly unparseable
+const defaultHorizonYears = 10
+
+var (
+	horizonRangeRe  = regexp.MustCompile(`^(\d+(?:\.\d+)?)\s*(?:-|to|–)\s*(\d+(?:\.\d+)?)`)
+	horizonNumberRe = regexp.MustCompile(`(\d+(?:\.\d+)?)\s*\+?\s*(years?|yrs?|y|months?|mos?|m)?\b`)
+	horizonYearRe   = regexp.MustCompile(`\b(19|20|21)\d{2}\b`)
+)
+
+// parseTimeHorizon extracts whole years from free-form horizons such as "7", "15 years",
+// "3-5 yrs", "20+", "18 months" or "until 2045". It reports whether the default was used,
+// which includes a target year already in the past.
+func parseTimeHorizon(horizon string, now time.Time) (years int, usedFallback bool) {
+	s := strings.ToLower(strings.TrimSpace(horizon))
+	if s == "" {
+		return defaultHorizonYears, true
+	}
+
+	// Target calendar year ("until 2045", "by 2030", "2045")
+	if m := horizonYearRe.FindString(s); m != "" {
+		target, _ := strconv.Atoi(m)
+		if target < now.Year() {
+			return defaultHorizonYears, true
+		}
+		return max(target-now.Year(), 1), false
+	}
+
+	// Ranges use the midpoint ("3-5" -> 4)
+	if m := horizonRangeRe.FindStringSubmatch(s); m != nil {
+		lo, _ := strconv.ParseFloat(m[1], 64)
+		hi, _ := strconv.ParseFloat(m[2], 64)
+		return roundYears((lo + hi) / 2), false
+	}
+
+	// Single number with an optional unit suffix ("15 years", "20+", "18 months")
+	if m := horizonNumberRe.FindStringSubmatch(s); m != nil {
+		n, _ := strconv.ParseFloat(m[1], 64)
+		if strings.HasPrefix(m[2], "m") {
+			n /= 12
+		}
+		return roundYears(n), false
+	}
+
+	return defaultHorizonYears, true
+}
+
+// roundYears rounds to the nearest whole year, never below one
+func roundYears(y float64) int {
+	return max(int(math.Round(y)), 1)
+}
@@ -0,0 +1,44 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseTimeHorizon(t *testing.T) {
+	now := time.Date(2026, 10, 16, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		horizon      string
+		years        int
+		usedFallback bool
+	}{
+		{"7", 7, false},
+		{"15 years", 15, false},
+		{"3-5 yrs", 4, false},
+		{"20+", 20, false},
+		{"18 months", 2, false},
+		{"until 2045", 19, false},
+		{"by 2030", 4, false},
+		{"2026", 1, false},
+		{"until 2020", defaultHorizonYears, true},
+		{"2025", defaultHorizonYears, true},
+		{"", defaultHorizonYears, true},
+		{"someday", defaultHorizonYears, true},
+	}
+	for _, tt := range tests {
+		years, usedFallback := parseTimeHorizon(tt.horizon, now)
+		if years != tt.years || usedFallback != tt.usedFallback {
+			t.Errorf("parseTimeHorizon(%q) = %d, %v; want %d, %v", tt.horizon, years, usedFallback, tt.years, tt.usedFallback)
+		}
+	}
+}
+
+func TestOnboardingTargetDateRejectsPastYear(t *testing.T) {
+	now := time.Date(2026, 10, 16, 0, 0, 0, 0, time.UTC)
+	if _, err := onboardingTargetDate("by 2020", now); err == nil {
+		t.Error("onboardingTargetDate(\"by 2020\") succeeded, want an error")
+	}
+	if got, err := onboardingTargetDate("by 2030", now); err != nil || got.Year() != 2030 {
+		t.Errorf("onboardingTargetDate(\"by 2030\") = %v, %v; want a date in 2030", got, err)
+	}
+}
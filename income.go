@@ -0,0 +1,171 @@
+package main
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"math"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/becomeliminal/nim-go-sdk/core"
+	"github.com/becomeliminal/nim-go-sdk/tools"
+)
+
+// ============================================
+// INCOME DETECTION
+// ============================================
+// Monthly income is estimated from money received over the last whole months
+// of Liminal history. A sender only counts as income once it has paid in at
+// least two of those months, so one-off transfers from friends don't inflate
+// it. How much the monthly totals vary (coefficient of variation) maps onto
+// the stable / moderate / unstable buckets the risk and emergency fund tools
+// already use.
+
+// Income detection bounds
+const (
+	incomeLookbackMonths  = 6
+	minIncomeMonths       = 2    // whole months needed before income is estimated
+	incomeStableMaxCV     = 0.15 // monthly totals varying less than this are stable
+	incomeModerateMaxCV   = 0.35
+	unnamedIncomeSender   = "unnamed sender"
+	incomeSourceDetected  = "detected"
+	incomeSourceUserGiven = "user_provided"
+)
+
+// newDetectedIncomeTool estimates the user's income and its stability from received transactions
+func newDetectedIncomeTool(liminalExecutor core.ToolExecutor) core.Tool {
+	return tools.New("get_detected_income").
+		Description("Detect the user's recurring income in their Liminal transaction history: estimated monthly income, how much it varies month to month (coefficient of variation), and the matching income stability (stable, moderate or unstable). Use it instead of asking the user to judge their own income stability").
+		Schema(tools.ObjectSchema(map[string]interface{}{})).
+		Handler(func(ctx context.Context, toolParams *core.ToolParams) (*core.ToolResult, error) {
+			txs, ok := fetchTransactions(ctx, liminalExecutor, toolParams.UserID, spendingPageSize)
+			if !ok {
+				return &core.ToolResult{Success: false, Error: "could not load transaction history"}, nil
+			}
+			return &core.ToolResult{Success: true, Data: detectIncome(txs, time.Now().UTC())}, nil
+		}).
+		Build()
+}
+
+// loadDetectedIncome fetches the user's transactions and detects their income; ok is false when
+// the history can't be read or shows no recurring income
+func loadDetectedIncome(ctx context.Context, liminalExecutor core.ToolExecutor, userID string) (IncomeDetection, bool) {
+	txs, ok := fetchTransactions(ctx, liminalExecutor, userID, spendingPageSize)
+	if !ok {
+		return IncomeDetection{Currency: activeCurrency().Code, Message: "Transaction history couldn't be loaded."}, false
+	}
+	d := detectIncome(txs, time.Now().UTC())
+	return d, d.Detected
+}
+
+// detectIncome estimates monthly income from the recurring senders in the whole months before now
+func detectIncome(txs []map[string]interface{}, now time.Time) IncomeDetection {
+	monthStart := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
+	d := IncomeDetection{Currency: activeCurrency().Code, Sources: []IncomeSender{}}
+
+	oldest := now
+	for _, tx := range txs {
+		if at, ok := transactionTime(tx); ok {
+			oldest = minTime(oldest, at)
+		}
+	}
+	for m := 1; m <= incomeLookbackMonths; m++ {
+		if !oldest.After(monthStart.AddDate(0, -m, 0)) {
+			d.MonthsAnalyzed = m
+		}
+	}
+	if d.MonthsAnalyzed < minIncomeMonths {
+		d.Message = fmt.Sprintf("At least %d whole months of transaction history are needed to detect income.", minIncomeMonths)
+		return d
+	}
+
+	// Received amounts per sender per month, months counted back from the last whole one
+	windowStart := monthStart.AddDate(0, -d.MonthsAnalyzed, 0)
+	bySender := map[string][]float64{}
+	names := map[string]string{}
+	last := map[string]time.Time{}
+	for _, tx := range txs {
+		kind, _ := tx["type"].(string)
+		at, ok := transactionTime(tx)
+		if !affordInflowTypes[strings.ToLower(kind)] || !ok || at.Before(windowStart) || !at.Before(monthStart) {
+			continue
+		}
+		name := subscriptionCounterparty(tx)
+		if name == "" {
+			name = unnamedIncomeSender
+		}
+		key := strings.ToLower(name)
+		if bySender[key] == nil {
+			bySender[key] = make([]float64, d.MonthsAnalyzed)
+		}
+		month := (monthStart.Year()-at.Year())*12 + int(monthStart.Month()-at.Month()) - 1
+		bySender[key][month] += math.Abs(transactionAmount(tx))
+		names[key] = name
+		if at.After(last[key]) {
+			last[key] = at
+		}
+	}
+
+	totals := make([]float64, d.MonthsAnalyzed)
+	for key, months := range bySender {
+		paid, sum := 0, 0.0
+		for _, amount := range months {
+			if amount > 0 {
+				paid++
+				sum += amount
+			}
+		}
+		if paid < minIncomeMonths {
+			continue
+		}
+		for i, amount := range months {
+			totals[i] += amount
+		}
+		d.Sources = append(d.Sources, IncomeSender{
+			Sender:            names[key],
+			MonthsPaid:        paid,
+			MonthlyAverageUSD: sum / float64(d.MonthsAnalyzed),
+			LastReceived:      last[key].Format("2006-01-02"),
+		})
+	}
+	slices.SortFunc(d.Sources, func(a, b IncomeSender) int {
+		if c := cmp.Compare(b.MonthlyAverageUSD, a.MonthlyAverageUSD); c != 0 {
+			return c
+		}
+		return strings.Compare(a.Sender, b.Sender)
+	})
+	if len(d.Sources) == 0 {
+		d.Message = fmt.Sprintf("No sender paid in during at least %d of the last %d months, so no recurring income was found.", minIncomeMonths, d.MonthsAnalyzed)
+		return d
+	}
+
+	mean, variance := 0.0, 0.0
+	for _, t := range totals {
+		mean += t / float64(len(totals))
+	}
+	for _, t := range totals {
+		variance += (t - mean) * (t - mean) / float64(len(totals))
+	}
+	d.Detected = true
+	d.MonthlyIncomeUSD = mean
+	d.MonthlyIncome = formatMoney(mean)
+	d.CoefficientOfVariation = math.Sqrt(variance) / mean
+	d.Stability = incomeStabilityFor(d.CoefficientOfVariation)
+	d.MonthlyTotalsUSD = totals
+	d.Message = fmt.Sprintf("Recurring income from %d sender(s) averaged %s/month over the last %d months, varying by %.0f%% month to month - %s.",
+		len(d.Sources), d.MonthlyIncome, d.MonthsAnalyzed, d.CoefficientOfVariation*100, d.Stability)
+	return d
+}
+
+// incomeStabilityFor maps a coefficient of variation onto incomeStabilities
+func incomeStabilityFor(cv float64) string {
+	switch {
+	case cv <= incomeStableMaxCV:
+		return "stable"
+	case cv <= incomeModerateMaxCV:
+		return "moderate"
+	}
+	return "unstable"
+}
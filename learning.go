@@ -0,0 +1,180 @@
+package main
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"log"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/becomeliminal/nim-go-sdk/core"
+	"github.com/becomeliminal/nim-go-sdk/tools"
+
+	"vibe-invest/storage"
+)
+
+// ============================================
+// LEARNING PATH
+// ============================================
+// Concepts link to each other through their related lists. Every concept
+// explain_investment_concept explains is recorded per user, and
+// suggest_next_concept walks one step out from what they have learned: a
+// concept is suggested only while it hasn't been explained, so links that loop
+// back (etf -> diversification -> etf) never bring covered material back.
+
+// maxRelatedTeasers caps the related concepts attached to an explanation
+const maxRelatedTeasers = 3
+
+// maxConceptNext caps suggest_next_concept's suggestions
+const maxConceptNext = 3
+
+// relatedTeasers lists up to maxRelatedTeasers of c's related concepts with their summaries in locale,
+// those not yet explained to the user first
+func relatedTeasers(set conceptSet, c conceptRecord, locale string, learned map[string]bool) []RelatedConcept {
+	related := make([]RelatedConcept, 0, len(c.Related))
+	for _, key := range c.Related {
+		if r, ok := set.concepts[key]; ok {
+			related = append(related, RelatedConcept{Concept: key, Teaser: conceptSummary(localizedConcept(r, locale)), Explained: learned[key]})
+		}
+	}
+	slices.SortStableFunc(related, func(a, b RelatedConcept) int {
+		return compareBool(a.Explained, b.Explained)
+	})
+	return related[:min(len(related), maxRelatedTeasers)]
+}
+
+// compareBool orders false before true
+func compareBool(a, b bool) int {
+	switch {
+	case a == b:
+		return 0
+	case a:
+		return 1
+	}
+	return -1
+}
+
+// learnedConcepts is the set of concepts explained to the user; a store failure is logged and
+// treated as nothing learned, so explanations still work
+func learnedConcepts(ctx context.Context, userID string) map[string]bool {
+	learned := map[string]bool{}
+	records, err := store.ListLearnedConcepts(ctx, userKey(userID))
+	if err != nil {
+		log.Printf("⚠️  Failed to load learned concepts for %s: %v\n", userKey(userID), err)
+		return learned
+	}
+	for _, r := range records {
+		learned[r.Concept] = true
+	}
+	return learned
+}
+
+// recordLearnedConcept notes that concept was explained to the user
+func recordLearnedConcept(ctx context.Context, userID, concept string) {
+	_, err := store.SaveLearnedConcept(ctx, storage.LearnedConcept{
+		UserID:      userKey(userID),
+		Concept:     concept,
+		ExplainedAt: time.Now().UTC(),
+	})
+	if err != nil {
+		log.Printf("⚠️  Failed to record learned concept %s for %s: %v\n", concept, userKey(userID), err)
+	}
+}
+
+// newSuggestConceptTool recommends what the user should learn next
+func newSuggestConceptTool() core.Tool {
+	return tools.New("suggest_next_concept").
+		Description("Suggest what the user should learn next, based on the concepts explain_investment_concept has already explained to them. Suggestions build on what they know (easiest first) and never repeat something already covered. Offer one after an explanation, e.g. \"Want to learn about diversification next?\"").
+		Schema(tools.ObjectSchema(map[string]interface{}{})).
+		Handler(func(ctx context.Context, toolParams *core.ToolParams) (*core.ToolResult, error) {
+			records, err := store.ListLearnedConcepts(ctx, userKey(toolParams.UserID))
+			if err != nil {
+				return &core.ToolResult{Success: false, Error: fmt.Sprintf("could not load learned concepts: %v", err)}, nil
+			}
+			learned := make([]string, len(records))
+			for i, r := range records {
+				learned[i] = r.Concept
+			}
+			return &core.ToolResult{Success: true, Data: suggestNextConcepts(concepts.current(), learned, userLocale(ctx, toolParams.UserID))}, nil
+		}).
+		Build()
+}
+
+// suggestNextConcepts ranks the concepts not yet learned, oldest learned first in learned, with teasers in locale.
+// Concepts linked (either way) to more learned concepts come first, then easier ones, then
+// those linked to something learned more recently; with nothing linked, the easiest unlearned
+// concepts start a new topic.
+func suggestNextConcepts(set conceptSet, learned []string, locale string) NextConceptResult {
+	r := NextConceptResult{Suggestions: []ConceptSuggestion{}, Explained: []string{}}
+	known := map[string]int{} // learned concept -> recency, higher is more recent
+	for i, key := range learned {
+		if _, ok := set.concepts[key]; ok { // concepts removed since are ignored
+			known[key] = i + 1
+			r.Explained = append(r.Explained, key)
+		}
+	}
+
+	// Links run both ways: a concept listing a learned one as related builds on it too
+	buildsOn := map[string][]string{}
+	link := func(from, to string) {
+		if known[from] > 0 && known[to] == 0 && !slices.Contains(buildsOn[to], from) {
+			buildsOn[to] = append(buildsOn[to], from)
+		}
+	}
+	for _, key := range set.order {
+		for _, rel := range set.concepts[key].Related {
+			link(key, rel)
+			link(rel, key)
+		}
+	}
+
+	type candidate struct {
+		ConceptSuggestion
+		difficulty, recency, position int
+	}
+	var candidates []candidate
+	for i, key := range set.order {
+		if known[key] > 0 {
+			continue
+		}
+		c := set.concepts[key]
+		cand := candidate{
+			ConceptSuggestion: ConceptSuggestion{Concept: key, Teaser: conceptSummary(localizedConcept(c, locale)), Difficulty: c.Difficulty, BuildsOn: buildsOn[key]},
+			difficulty:        slices.Index(conceptDifficulties, c.Difficulty),
+			position:          i,
+		}
+		for _, from := range cand.BuildsOn {
+			cand.recency = max(cand.recency, known[from])
+		}
+		if cand.BuildsOn == nil {
+			cand.BuildsOn = []string{}
+		}
+		candidates = append(candidates, cand)
+	}
+	r.Remaining = len(candidates)
+	slices.SortFunc(candidates, func(a, b candidate) int {
+		return cmp.Or(
+			cmp.Compare(len(b.BuildsOn), len(a.BuildsOn)),
+			cmp.Compare(a.difficulty, b.difficulty),
+			cmp.Compare(b.recency, a.recency),
+			cmp.Compare(a.position, b.position),
+		)
+	})
+	for _, cand := range candidates[:min(len(candidates), maxConceptNext)] {
+		r.Suggestions = append(r.Suggestions, cand.ConceptSuggestion)
+	}
+
+	switch {
+	case len(r.Suggestions) == 0:
+		r.Message = fmt.Sprintf("All %d concepts have been explained - nothing new to suggest.", len(r.Explained))
+	case len(r.Explained) == 0:
+		r.Message = fmt.Sprintf("Nothing explained yet; %s is a good place to start.", r.Suggestions[0].Concept)
+	case len(r.Suggestions[0].BuildsOn) > 0:
+		r.Message = fmt.Sprintf("Suggest %s next: it builds on %s.", r.Suggestions[0].Concept, strings.Join(r.Suggestions[0].BuildsOn, " and "))
+	default:
+		r.Message = fmt.Sprintf("Everything linked to what the user has learned is covered; %s starts a new topic.", r.Suggestions[0].Concept)
+	}
+	return r
+}
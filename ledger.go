@@ -0,0 +1,213 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/becomeliminal/nim-go-sdk/core"
+	"github.com/becomeliminal/nim-go-sdk/tools"
+
+	"vibe-invest/storage"
+)
+
+// ============================================
+// CONTRIBUTIONS LEDGER
+// ============================================
+// Every dollar the user puts in is written to the ledger: plan executions by
+// the scheduler, confirmed deposit_savings calls by ledgerExecutor, and
+// anything else the user reports through record_contribution. Entries are keyed
+// by the execution or transaction they came from, so re-processing the same
+// one never counts it twice. Growth is then the change in value the ledger
+// doesn't explain.
+
+// recordContribution writes c to the ledger and checks milestones, reporting whether c was new.
+// A failed write is logged as well as returned, so callers acting on someone else's behalf can
+// ignore it rather than fail the action.
+func recordContribution(ctx context.Context, c storage.Contribution) (bool, error) {
+	c.UserID = userKey(c.UserID)
+	c.CreatedAt = time.Now().UTC()
+	added, err := store.SaveContribution(ctx, c)
+	if err != nil {
+		log.Printf("⚠️  Failed to record contribution %s for %s: %v\n", c.ID, c.UserID, err)
+		return false, err
+	}
+	if added {
+		checkMilestones(ctx, c.UserID, c.CreatedAt)
+	}
+	return added, nil
+}
+
+// manualContributionID is the ledger ID for a user-reported contribution. Reference numbers are
+// only unique per user, so the user is part of the ID (escaped, so no user and reference pair can
+// spell another's); without a reference every report gets a fresh ID.
+func manualContributionID(userID, reference string) string {
+	reference = strings.TrimSpace(reference)
+	if reference == "" {
+		return "manual_" + generateRandomID()
+	}
+	return "manual_" + url.PathEscape(userKey(userID)) + "/" + reference
+}
+
+// ledgerExecutor passes calls through to Liminal and records successful deposit_savings calls
+type ledgerExecutor struct {
+	core.ToolExecutor
+}
+
+func (e ledgerExecutor) Execute(ctx context.Context, req *core.ExecuteRequest) (*core.ExecuteResponse, error) {
+	resp, err := e.ToolExecutor.Execute(ctx, req)
+	if err != nil || resp == nil || !resp.Success || req.Tool != "deposit_savings" {
+		return resp, err
+	}
+	var input struct {
+		Amount string `json:"amount"`
+	}
+	amount := 0.0
+	if json.Unmarshal(req.Input, &input) == nil {
+		amount, _ = parseCachedAmount(input.Amount)
+	}
+	if amount <= 0 {
+		log.Printf("⚠️  deposit_savings for %s succeeded without a readable amount; not recorded as a contribution\n", userKey(req.UserID))
+		return resp, err
+	}
+	recordContribution(ctx, storage.Contribution{
+		ID:     "deposit_" + depositKey(req, resp),
+		UserID: req.UserID,
+		Date:   time.Now().UTC(),
+		Amount: amount,
+		Source: storage.ContributionDeposit,
+	})
+	return resp, err
+}
+
+// depositKey identifies a deposit: the request ID when there is one, else the transaction ID Liminal returned
+func depositKey(req *core.ExecuteRequest, resp *core.ExecuteResponse) string {
+	if req.RequestID != "" {
+		return req.RequestID
+	}
+	var body struct {
+		TransactionID string `json:"transaction_id"`
+		ID            string `json:"id"`
+	}
+	if json.Unmarshal(resp.Data, &body) == nil {
+		if body.TransactionID != "" {
+			return body.TransactionID
+		}
+		if body.ID != "" {
+			return body.ID
+		}
+	}
+	return generateRandomID()
+}
+
+// contributionTotals sums the ledger for the calendar year of now and for all time
+func contributionTotals(contributions []storage.Contribution, now time.Time) (thisYear, lifetime float64) {
+	for _, c := range contributions {
+		lifetime += c.Amount
+		if c.Date.Year() == now.Year() {
+			thisYear += c.Amount
+		}
+	}
+	return thisYear, lifetime
+}
+
+// growthExcludingContributions is the change in value since the first snapshot minus what was
+// contributed after it; ok is false without at least one snapshot to measure from
+func growthExcludingContributions(snapshots []storage.Snapshot, contributions []storage.Contribution) (growth float64, since string, ok bool) {
+	if len(snapshots) == 0 {
+		return 0, "", false
+	}
+	first, last := snapshots[0], snapshots[len(snapshots)-1]
+	growth = last.TotalValue - first.TotalValue
+	for _, c := range contributions {
+		if day := c.Date.Format("2006-01-02"); day > first.Date && day <= last.Date {
+			growth -= c.Amount
+		}
+	}
+	return growth, first.Date, true
+}
+
+// newRecordContributionTool lets the user report money added outside automated plans and deposit_savings
+func newRecordContributionTool() core.Tool {
+	return tools.New("record_contribution").
+		Description("Record money the user added to their investments outside InvestMate (e.g. a brokerage deposit or 401k paycheck contribution) so performance separates their deposits from market growth").
+		Schema(tools.ObjectSchema(map[string]interface{}{
+			"amount":       tools.StringProperty("Amount contributed in the account currency"),
+			"date":         tools.StringProperty("Optional date of the contribution (YYYY-MM-DD, default today)"),
+			"note":         tools.StringProperty("Optional short description, e.g. '401k payroll'"),
+			"reference_id": tools.StringProperty("Optional transaction or confirmation number; recording the same one again is reported and not counted twice"),
+		}, "amount")).
+		Handler(func(ctx context.Context, toolParams *core.ToolParams) (*core.ToolResult, error) {
+			var params struct {
+				Amount      string `json:"amount"`
+				Date        string `json:"date"`
+				Note        string `json:"note"`
+				ReferenceID string `json:"reference_id"`
+			}
+			if err := json.Unmarshal(toolParams.Input, &params); err != nil {
+				return &core.ToolResult{Success: false, Error: fmt.Sprintf("invalid input: %v", err)}, nil
+			}
+			if frozen, err := isReadOnly(ctx, toolParams.UserID); err != nil {
+				return &core.ToolResult{Success: false, Error: fmt.Sprintf("could not check account status: %v", err)}, nil
+			} else if frozen {
+				return readOnlyResult(), nil
+			}
+
+			var v amountValidator
+			amount := v.positive("amount", params.Amount)
+			date := time.Now().UTC()
+			if strings.TrimSpace(params.Date) != "" {
+				d, err := time.Parse("2006-01-02", strings.TrimSpace(params.Date))
+				switch {
+				case err != nil:
+					v.fail("date", "%q is not a YYYY-MM-DD date", params.Date)
+				case d.After(date):
+					v.fail("date", "cannot be in the future")
+				}
+				date = d
+			}
+			if err := v.err(); err != nil {
+				return &core.ToolResult{Success: false, Error: err.Error()}, nil
+			}
+
+			id := manualContributionID(toolParams.UserID, params.ReferenceID)
+			added, err := recordContribution(ctx, storage.Contribution{
+				ID:     id,
+				UserID: toolParams.UserID,
+				Date:   date,
+				Amount: amount,
+				Source: storage.ContributionManual,
+				Note:   params.Note,
+			})
+			if err != nil {
+				return &core.ToolResult{Success: false, Error: fmt.Sprintf("could not record contribution: %v", err)}, nil
+			}
+			if added {
+				recordAudit(ctx, toolParams.UserID, "user", "record_contribution", id)
+			}
+
+			ledger, err := store.ListContributions(ctx, userKey(toolParams.UserID))
+			if err != nil {
+				return &core.ToolResult{Success: false, Error: fmt.Sprintf("could not load contributions: %v", err)}, nil
+			}
+			thisYear, lifetime := contributionTotals(ledger, time.Now().UTC())
+			message := fmt.Sprintf("Recorded a %s contribution on %s.", formatMoney(amount), date.Format("2006-01-02"))
+			if !added {
+				message = fmt.Sprintf("Reference %s was already recorded, so nothing was added.", strings.TrimSpace(params.ReferenceID))
+			}
+			return &core.ToolResult{Success: true, Data: map[string]interface{}{
+				"currency":              activeCurrency().Code,
+				"contribution_id":       id,
+				"already_recorded":      !added,
+				"contributed_this_year": thisYear,
+				"contributed_lifetime":  lifetime,
+				"message": fmt.Sprintf("%s You've contributed %s this year and %s in total.",
+					message, formatMoney(thisYear), formatMoney(lifetime)),
+			}}, nil
+		}).
+		Build()
+}
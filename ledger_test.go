@@ -0,0 +1,52 @@
+package main
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"vibe-invest/storage"
+)
+
+func TestManualContributionIDs(t *testing.T) {
+	ctx := context.Background()
+	tests := []struct {
+		name      string
+		userID    string
+		reference string
+		wantAdded bool
+	}{
+		{"first report", "ledger_alice", "TX-100", true},
+		{"same reference again", "ledger_alice", "TX-100", false},
+		{"padded reference", "ledger_alice", "  TX-100 ", false},
+		{"same reference, another user", "ledger_bob", "TX-100", true},
+		{"user and reference that would run together", "ledger_alice/TX", "100", true},
+		{"no reference", "ledger_alice", "", true},
+		{"no reference again", "ledger_alice", "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			added, err := recordContribution(ctx, storage.Contribution{
+				ID:     manualContributionID(tt.userID, tt.reference),
+				UserID: tt.userID,
+				Date:   time.Now().UTC(),
+				Amount: 50,
+				Source: storage.ContributionManual,
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if added != tt.wantAdded {
+				t.Errorf("added = %v, want %v", added, tt.wantAdded)
+			}
+		})
+	}
+
+	ledger, err := store.ListContributions(ctx, "ledger_alice")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(ledger) != 3 {
+		t.Errorf("ledger_alice has %d contributions, want 3 (%+v)", len(ledger), ledger)
+	}
+}
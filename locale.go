@@ -0,0 +1,111 @@
+package main
+
+import (
+	"cmp"
+	"context"
+	"embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"maps"
+	"path"
+	"regexp"
+	"slices"
+	"strings"
+
+	"vibe-invest/storage"
+)
+
+// ============================================
+// LOCALIZATION
+// ============================================
+// Text the user reads is written in English and looked up in a catalog per
+// locale, keyed by that English text (format strings included, verbs and all);
+// anything a catalog lacks stays English. Concept text is translated in the
+// concepts file itself, under each concept's "translations". Adding a locale
+// is a new data/locales/<code>.json plus those translations. Guidance aimed at
+// the assistant rather than the user stays English.
+
+// defaultLocale is the language the code is written in; it needs no catalog
+const defaultLocale = "en"
+
+//go:embed data/locales/*.json
+var localeFiles embed.FS
+
+// localeCatalogs maps locale -> English text -> translation
+var localeCatalogs = loadLocaleCatalogs()
+
+// supportedLocales are the accepted LOCALE and profile values, defaultLocale first
+var supportedLocales = append([]string{defaultLocale}, slices.Sorted(maps.Keys(localeCatalogs))...)
+
+// formatVerb matches a fmt verb, so translations can be checked to take the same arguments
+var formatVerb = regexp.MustCompile(`%[-+# 0-9.]*[a-zA-Z%]`)
+
+// loadLocaleCatalogs reads every embedded catalog, refusing to start on one whose translation
+// would format its arguments differently from the English
+func loadLocaleCatalogs() map[string]map[string]string {
+	files, err := localeFiles.ReadDir("data/locales")
+	if err != nil {
+		log.Fatalf("invalid locale catalogs: %v", err)
+	}
+	catalogs := map[string]map[string]string{}
+	for _, f := range files {
+		raw, err := localeFiles.ReadFile("data/locales/" + f.Name())
+		if err != nil {
+			log.Fatalf("invalid locale catalog %s: %v", f.Name(), err)
+		}
+		var catalog map[string]string
+		if err := json.Unmarshal(raw, &catalog); err != nil {
+			log.Fatalf("invalid locale catalog %s: %v", f.Name(), err)
+		}
+		for english, translated := range catalog {
+			if want, got := formatVerb.FindAllString(english, -1), formatVerb.FindAllString(translated, -1); !slices.Equal(want, got) {
+				log.Fatalf("invalid locale catalog %s: %q uses %v, its translation %v", f.Name(), english, want, got)
+			}
+		}
+		catalogs[strings.TrimSuffix(f.Name(), path.Ext(f.Name()))] = catalog
+	}
+	return catalogs
+}
+
+// envLocale reads a locale from the environment, keeping the fallback for unsupported ones
+func envLocale(key, fallback string) string {
+	locale := strings.ToLower(envString(key, fallback))
+	if !slices.Contains(supportedLocales, locale) {
+		log.Printf("⚠️  Ignoring unsupported %s=%q, using %s", key, locale, fallback)
+		return fallback
+	}
+	return locale
+}
+
+// localize is text in locale, or text itself when the locale's catalog doesn't have it
+func localize(locale, text string) string {
+	if translated := localeCatalogs[locale][text]; translated != "" {
+		return translated
+	}
+	return text
+}
+
+// localizef formats a localized format string
+func localizef(locale, format string, args ...interface{}) string {
+	return fmt.Sprintf(localize(locale, format), args...)
+}
+
+// localizeAll localizes each of texts
+func localizeAll(locale string, texts []string) []string {
+	out := make([]string, len(texts))
+	for i, t := range texts {
+		out[i] = localize(locale, t)
+	}
+	return out
+}
+
+// userLocale is the locale saved on the user's profile, else the server's LOCALE
+func userLocale(ctx context.Context, userID string) string {
+	p, err := store.GetPortfolio(ctx, userKey(userID))
+	if err != nil && !errors.Is(err, storage.ErrNotFound) {
+		log.Printf("⚠️  Failed to load locale for %s: %v\n", userKey(userID), err)
+	}
+	return cmp.Or(p.Locale, appConfig.Locale)
+}
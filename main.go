@@ -1,18 +1,28 @@
 package main
 
 import (
+	"cmp"
 	"context"
+	"crypto/rand"
+	"encoding/hex"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log"
 	"math"
 	"os"
-	"strconv"
-	"sync"
+	"os/signal"
+	"slices"
+	"strings"
+	"syscall"
+	"time"
 
+	"github.com/becomeliminal/nim-go-sdk/core"
 	"github.com/becomeliminal/nim-go-sdk/executor"
 	"github.com/becomeliminal/nim-go-sdk/server"
 	"github.com/becomeliminal/nim-go-sdk/tools"
+
+	"vibe-invest/storage"
 )
 
 // InvestmentPortfolio represents a user's investment profile
@@ -20,161 +30,130 @@ type InvestmentPortfolio struct {
 	TotalBalance      float64
 	SavingsAllocation float64
 	StockAllocation   float64
-	RiskTolerance     string // "conservative", "moderate", "aggressive"
+	RiskTolerance     RiskLevel
 	MonthlySavings    float64
 	AgeGroup          string // "20s", "30s", "40s", "50s", "60+"
-}
-
-// MockPortfolios simulates user investment data
-var mockPortfolios = map[string]InvestmentPortfolio{
-	"default": {
-		TotalBalance:      5000,
-		SavingsAllocation: 3000,
-		StockAllocation:   2000,
-		RiskTolerance:     "moderate",
-		MonthlySavings:    500,
-		AgeGroup:          "30s",
-	},
+	Experience        string // one of experienceLevels, empty until the user has said
+	Locale            string // one of supportedLocales, empty for the server's LOCALE
+	Holdings          []storage.Holding
 }
 
 // ============================================
 // PERFORMANCE OPTIMIZATION: Pre-computed lookups
 // ============================================
 
-// Pre-computed risk allocations (O(1) lookup instead of map creation)
-var riskAllocationCache = map[string]map[string]string{
-	"Conservative": {
-		"stocks": "30-40%",
-		"bonds":  "50-60%",
-		"cash":   "10-20%",
-	},
-	"Moderate": {
-		"stocks": "50-60%",
-		"bonds":  "30-40%",
-		"cash":   "5-10%",
-	},
-	"Moderate-to-Aggressive": {
-		"stocks": "70-80%",
-		"bonds":  "15-25%",
-		"cash":   "5%",
-	},
+// Maximum crypto share of investable assets (percent) per risk level
+var cryptoCapByRisk = map[RiskLevel]float64{
+	RiskConservative:         0,
+	RiskModerate:             2,
+	RiskModerateToAggressive: 3.5,
+	RiskAggressive:           5,
 }
 
 // Pre-computed strategies (O(1) lookup)
-var strategiesCache = map[string][]string{
-	"Conservative": {
+var strategiesCache = map[RiskLevel][]string{
+	RiskConservative: {
 		"Focus on bonds and dividend-paying stocks",
 		"Monthly automated investing",
 		"Rebalance annually",
 	},
-	"Moderate": {
+	RiskModerate: {
 		"Mix of growth stocks and stable bonds",
 		"Dollar-cost averaging",
 		"Review quarterly",
 	},
-	"Moderate-to-Aggressive": {
+	RiskModerateToAggressive: {
 		"Growth-focused with some international exposure",
 		"Automatic reinvestment of dividends",
 		"Stay the course during market dips",
 	},
-}
-
-// Pre-computed concept explanations (O(1) lookup)
-var conceptCache = map[string]map[string]interface{}{
-	"etf": {
-		"concept":     "etf",
-		"explanation": "An ETF (Exchange-Traded Fund) is like a basket of stocks bundled together. Instead of buying individual companies, you buy a tiny piece of many companies at once. It's like ordering a sampler platter instead of one dish!",
-		"key_points": []string{
-			"Understanding this concept helps you make better investment decisions",
-			"Don't feel rushed - investing is a marathon, not a sprint",
-			"Ask questions anytime - financial literacy is your superpower",
-		},
-	},
-	"dividend": {
-		"concept":     "dividend",
-		"explanation": "A dividend is a small payment companies give to shareholders (owners). Think of it as the company saying 'thank you' for investing in us. You get paid just for holding the stock!",
-		"key_points": []string{
-			"Understanding this concept helps you make better investment decisions",
-			"Don't feel rushed - investing is a marathon, not a sprint",
-			"Ask questions anytime - financial literacy is your superpower",
-		},
-	},
-	"diversification": {
-		"concept":     "diversification",
-		"explanation": "Diversification means not putting all your eggs in one basket. Instead of investing only in tech stocks, you spread money across different types of investments, industries, and risk levels.",
-		"key_points": []string{
-			"Understanding this concept helps you make better investment decisions",
-			"Don't feel rushed - investing is a marathon, not a sprint",
-			"Ask questions anytime - financial literacy is your superpower",
-		},
-	},
-	"compound_interest": {
-		"concept":     "compound_interest",
-		"explanation": "Compound interest is when your earnings make their own earnings. Your money grows faster because you're earning 'interest on interest.' Albert Einstein called it the 8th wonder of the world!",
-		"key_points": []string{
-			"Understanding this concept helps you make better investment decisions",
-			"Don't feel rushed - investing is a marathon, not a sprint",
-			"Ask questions anytime - financial literacy is your superpower",
-		},
-	},
-	"dollar_cost_averaging": {
-		"concept":     "dollar_cost_averaging",
-		"explanation": "Instead of trying to time the market perfectly, you invest a fixed amount regularly (monthly). By averaging out the price over time, you reduce the risk of buying at the peak.",
-		"key_points": []string{
-			"Understanding this concept helps you make better investment decisions",
-			"Don't feel rushed - investing is a marathon, not a sprint",
-			"Ask questions anytime - financial literacy is your superpower",
-		},
+	RiskAggressive: {
+		"Equity-heavy with broad index funds at the core",
+		"Keep a separate emergency fund so you never sell in a downturn",
+		"Stay the course during market dips",
 	},
 }
 
-// Time horizon lookup (pre-computed table)
-var timeHorizonTable = map[string]int{
-	"1":   2,
-	"1-3": 2,
-	"5":   5,
-	"3-5": 5,
-	"10":  10,
-	"20":  20,
-	"20+": 20,
+// planStrategiesText are the key strategies every investment plan lists
+var planStrategiesText = []string{"Dollar-cost averaging", "Automatic rebalancing", "Tax-efficient investing"}
+
+// planAllocation is a recommended allocation, as fractions of the portfolio
+type planAllocation struct {
+	stocks        float64 // domestic equity
+	international float64
+	reit          float64
+	bonds         float64
+	cash          float64
 }
 
-// Risk score lookup tables
-var ageRiskScore = [120]int{
-	70, 70, 70, 70, 70, 70, 70, 70, 70, 70, 70, 70, 70, 70, 70, 70, 70, 70, 70, 70, 70, 70, 70, 70, 70, 70, 70, 70, 70, 70, 70, 70, 70, 70, 70,
-	50, 50, 50, 50, 50, 50, 50, 50, 50, 50, 50, 50, 50, 50, 50,
-	30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30,
+// horizonKnot is the equity and cash share for money needed in years
+type horizonKnot struct {
+	years  float64
+	equity float64 // stocks, international and REITs together
+	cash   float64
 }
 
-var comfortRiskScore = map[string]int{
-	"very_uncomfortable":     10,
-	"somewhat_uncomfortable": 25,
-	"neutral":                40,
-	"comfortable":            60,
-	"very_comfortable":       75,
+// horizonSchedule is the single allocation schedule behind analyze_investment_recommendations and
+// glide_path, for a baselineHorizonRisk investor. A horizon equal to a knot's years gets that knot
+// exactly; horizons between knots are interpolated linearly, and beyond the last knot it holds.
+// Bonds take whatever equity and cash leave.
+var horizonSchedule = []horizonKnot{
+	{0, 0.20, 0.30},
+	{2, 0.25, 0.25},
+	{5, 0.30, 0.20},
+	{10, 0.50, 0.10},
+	{15, 0.60, 0.10},
+	{20, 0.75, 0.05},
+	{25, 0.85, 0.05},
+	{30, 0.90, 0.05},
 }
 
-var experienceRiskScore = map[string]int{
-	"none":      -20,
-	"minimal":   -10,
-	"moderate":  0,
-	"extensive": 15,
+// baselineHorizonRisk is the risk level horizonSchedule is written for
+const baselineHorizonRisk = RiskModerateToAggressive
+
+// riskEquityScale scales horizonSchedule's equity share for each risk level, capped at maxPlanEquity;
+// the difference comes out of (or goes into) bonds
+var riskEquityScale = map[RiskLevel]float64{
+	RiskConservative:         0.50,
+	RiskModerate:             0.75,
+	RiskModerateToAggressive: 1.00,
+	RiskAggressive:           1.15,
 }
 
-// Allocation lookup based on years horizon
-var allocationByYears = []struct {
-	years  int
-	stocks float64
-	bonds  float64
-	cash   float64
-}{
-	{5, 0.30, 0.50, 0.20},
-	{15, 0.60, 0.30, 0.10},
-	{999, 0.80, 0.15, 0.05},
+// maxPlanEquity is the most of a plan ever put in equities
+const maxPlanEquity = 0.95
+
+// horizonAllocation is the allocation for money needed in years at a risk level, in whole percents
+func horizonAllocation(years float64, risk RiskLevel) planAllocation {
+	k := horizonSchedule
+	equity, cash := k[len(k)-1].equity, k[len(k)-1].cash
+	if years <= k[0].years {
+		equity, cash = k[0].equity, k[0].cash
+	}
+	for i := 1; i < len(k); i++ {
+		if years > k[i-1].years && years <= k[i].years {
+			t := (years - k[i-1].years) / (k[i].years - k[i-1].years)
+			equity = k[i-1].equity + (k[i].equity-k[i-1].equity)*t
+			cash = k[i-1].cash + (k[i].cash-k[i-1].cash)*t
+			break
+		}
+	}
+	equity = math.Round(min(equity*riskEquityScale[risk], maxPlanEquity)*100) / 100
+	cash = math.Round(min(cash, 1-equity)*100) / 100
+	// Split equity in whole percents too, domestic taking the rounding, so the mix shown adds to 100%
+	international := math.Round(equity*equitySplit["international"]*100) / 100
+	reit := math.Round(equity*equitySplit["reit"]*100) / 100
+	return planAllocation{
+		stocks:        equity - international - reit,
+		international: international,
+		reit:          reit,
+		bonds:         max(1-equity-cash, 0),
+		cash:          cash,
+	}
 }
 
-// Parser cache with sync.Map for thread-safe caching
-var parseCache sync.Map // key: string, value: float64
+// Bounded LRU parser cache, sized from PARSE_CACHE_SIZE
+var parseCache = newLRUCache(appConfig.ParseCacheSize)
 
 func main() {
 	// Get API key from environment
@@ -183,15 +162,30 @@ func main() {
 		log.Fatal("ANTHROPIC_API_KEY environment variable is required")
 	}
 
+	// Persist user state to SQLite when DATA_PATH is set; otherwise keep the in-memory default
+	if appConfig.DataPath != "" {
+		sqliteStore, err := storage.Open(appConfig.DataPath)
+		if err != nil {
+			log.Fatalf("failed to open data store: %v", err)
+		}
+		store = sqliteStore
+		log.Printf("💾 Persisting user state to %s\n", appConfig.DataPath)
+	} else {
+		log.Println("💾 Using in-memory user state (set DATA_PATH to persist across restarts)")
+	}
+
 	// Create Liminal API executor (optional, for banking integration)
 	liminalExecutor := executor.NewHTTPExecutor(executor.HTTPExecutorConfig{
 		BaseURL: "https://api.liminal.cash",
 	})
+	vaultRates.executor = liminalExecutor
+	// Confirmed deposit_savings calls made in conversation go into the contributions ledger
+	conversationExecutor := ledgerExecutor{liminalExecutor}
 
 	// Create server
 	srv, err := server.New(server.Config{
 		AnthropicKey:    anthropicKey,
-		LiminalExecutor: liminalExecutor,
+		LiminalExecutor: conversationExecutor,
 		SystemPrompt: `You are InvestMate, a friendly AI investment advisor helping regular people build wealth through smart investing.
 
 Your role:
@@ -213,6 +207,7 @@ When users mention investing money, always use the appropriate tools to understa
 		MaxTokens: 2048,
 	})
 	if err != nil {
+		closeStore()
 		log.Fatal(err)
 	}
 
@@ -230,7 +225,7 @@ When users mention investing money, always use the appropriate tools to understa
 	// - deposit_savings: Fund savings accounts (confirmation required)
 	// - withdraw_savings: Withdraw for diversification (confirmation required)
 
-	srv.AddTools(tools.LiminalTools(liminalExecutor)...)
+	srv.AddTools(tools.LiminalTools(conversationExecutor)...)
 	log.Println("✅ Integrated 9 Liminal banking tools for real account operations")
 
 	// ============================================
@@ -242,22 +237,57 @@ When users mention investing money, always use the appropriate tools to understa
 	getProfileTool := tools.New("get_investment_profile").
 		Description("Get the user's current investment profile, risk tolerance, and financial situation").
 		Schema(tools.ObjectSchema(map[string]interface{}{}, "")).
-		HandlerFunc(func(ctx context.Context, input json.RawMessage) (interface{}, error) {
-			// In production, this would fetch from a database based on user context
-			portfolio := mockPortfolios["default"]
-			return map[string]interface{}{
-				"total_balance":       portfolio.TotalBalance,
-				"savings_allocation":  portfolio.SavingsAllocation,
-				"stock_allocation":    portfolio.StockAllocation,
-				"risk_tolerance":      portfolio.RiskTolerance,
-				"monthly_savings":     portfolio.MonthlySavings,
-				"age_group":           portfolio.AgeGroup,
-				"recommended_savings": calculateRecommendedSavings(portfolio),
-			}, nil
+		Handler(func(ctx context.Context, toolParams *core.ToolParams) (*core.ToolResult, error) {
+			portfolio, err := loadPortfolio(ctx, toolParams.UserID)
+			if errors.Is(err, errNoProfile) {
+				return &core.ToolResult{Success: false, Error: "profile not set: ask the user about their finances and save them with complete_onboarding or update_investment_profile"}, nil
+			}
+			if err != nil {
+				return &core.ToolResult{Success: false, Error: fmt.Sprintf("could not load profile: %v", err)}, nil
+			}
+			contributions, err := store.ListContributions(ctx, userKey(toolParams.UserID))
+			if err != nil {
+				return &core.ToolResult{Success: false, Error: fmt.Sprintf("could not load contributions: %v", err)}, nil
+			}
+			snapshots, err := store.ListSnapshots(ctx, userKey(toolParams.UserID), "")
+			if err != nil {
+				return &core.ToolResult{Success: false, Error: fmt.Sprintf("could not load portfolio history: %v", err)}, nil
+			}
+			streak, err := loadSavingsStreak(ctx, toolParams.UserID, time.Now().UTC())
+			if err != nil {
+				return &core.ToolResult{Success: false, Error: err.Error()}, nil
+			}
+			thisYear, lifetime := contributionTotals(contributions, time.Now().UTC())
+			profile := map[string]interface{}{
+				"total_balance":         portfolio.TotalBalance,
+				"savings_allocation":    portfolio.SavingsAllocation,
+				"stock_allocation":      portfolio.StockAllocation,
+				"risk_tolerance":        portfolio.RiskTolerance,
+				"monthly_savings":       portfolio.MonthlySavings,
+				"age_group":             portfolio.AgeGroup,
+				"experience":            portfolio.Experience,
+				"locale":                cmp.Or(portfolio.Locale, appConfig.Locale),
+				"recommended_savings":   calculateRecommendedSavings(portfolio),
+				"contributed_this_year": thisYear,
+				"contributed_lifetime":  lifetime,
+				"savings_streak":        streak,
+			}
+			// Growth needs a recorded starting value; without snapshots it is left out rather than guessed
+			if growth, since, ok := growthExcludingContributions(snapshots, contributions); ok {
+				profile["growth_excluding_contributions"] = growth
+				profile["growth_since"] = since
+			}
+			return &core.ToolResult{Success: true, Data: profile}, nil
 		}).
 		Build()
 
 	srv.AddTool(getProfileTool)
+	srv.AddTool(newUpdateProfileTool())
+	srv.AddTool(newOnboardingTool())
+	srv.AddTool(newSavingsStreakTool())
+	srv.AddTool(newMilestonesTool())
+	srv.AddTool(newHealthScoreTool(liminalExecutor))
+	srv.AddTool(newAlertsTool())
 
 	// Tool 2: Analyze investment recommendations
 	analyzeRecommendationsTool := tools.New("analyze_investment_recommendations").
@@ -265,26 +295,46 @@ When users mention investing money, always use the appropriate tools to understa
 		Schema(tools.ObjectSchema(map[string]interface{}{
 			"goal":             tools.StringProperty("Investment goal (e.g., 'retirement', 'home_down_payment', 'general_wealth')"),
 			"time_horizon":     tools.StringProperty("Investment time horizon in years (e.g., '5', '10', '20+')"),
-			"current_amount":   tools.StringProperty("Amount available to invest right now in USD"),
-			"monthly_capacity": tools.StringProperty("Amount the user can invest monthly in USD"),
-		}, "goal", "time_horizon", "current_amount", "monthly_capacity")).
-		HandlerFunc(func(ctx context.Context, input json.RawMessage) (interface{}, error) {
+			"current_amount":   tools.StringProperty("Optional amount available to invest right now in the account currency; omit to use the savings allocation in the user's profile"),
+			"monthly_capacity": tools.StringProperty("Optional amount the user can invest monthly in the account currency; omit to use the monthly savings in the user's profile"),
+			"risk_tolerance":   tools.StringProperty("Optional risk tolerance: conservative, moderate, moderate-to-aggressive or aggressive; omit to use the user's profile"),
+		}, "goal", "time_horizon")).
+		Handler(func(ctx context.Context, toolParams *core.ToolParams) (*core.ToolResult, error) {
 			var params struct {
 				Goal            string `json:"goal"`
 				TimeHorizon     string `json:"time_horizon"`
 				CurrentAmount   string `json:"current_amount"`
 				MonthlyCapacity string `json:"monthly_capacity"`
+				RiskTolerance   string `json:"risk_tolerance"`
 			}
-			if err := json.Unmarshal(input, &params); err != nil {
-				return nil, fmt.Errorf("invalid input: %w", err)
+			if err := json.Unmarshal(toolParams.Input, &params); err != nil {
+				return &core.ToolResult{Success: false, Error: fmt.Sprintf("invalid input: %v", err)}, nil
 			}
 
-			// Use cached parser for better performance on repeated values
-			current := parseCachedFloat(params.CurrentAmount)
-			monthly := parseCachedFloat(params.MonthlyCapacity)
+			var v amountValidator
+			current, currentSource, err := profileAmount(ctx, &v, toolParams.UserID, "current_amount", params.CurrentAmount,
+				func(p InvestmentPortfolio) float64 { return p.SavingsAllocation })
+			if err != nil {
+				return &core.ToolResult{Success: false, Error: err.Error()}, nil
+			}
+			monthly, monthlySource, err := profileAmount(ctx, &v, toolParams.UserID, "monthly_capacity", params.MonthlyCapacity,
+				func(p InvestmentPortfolio) float64 { return p.MonthlySavings })
+			if err != nil {
+				return &core.ToolResult{Success: false, Error: err.Error()}, nil
+			}
+			if err := v.err(); err != nil {
+				return &core.ToolResult{Success: false, Error: err.Error()}, nil
+			}
+			risk, source, err := resolveRiskLevel(ctx, toolParams.UserID, "risk_tolerance", params.RiskTolerance)
+			if err != nil {
+				return &core.ToolResult{Success: false, Error: err.Error()}, nil
+			}
 
-			recommendation := generateInvestmentPlan(params.Goal, params.TimeHorizon, current, monthly)
-			return recommendation, nil
+			recommendation := generateInvestmentPlan(params.Goal, params.TimeHorizon, risk, current, monthly, userLocale(ctx, toolParams.UserID))
+			recommendation["risk_source"] = source
+			recommendation["current_amount_source"] = currentSource
+			recommendation["monthly_capacity_source"] = monthlySource
+			return &core.ToolResult{Success: true, Data: recommendation}, nil
 		}).
 		Build()
 
@@ -294,303 +344,490 @@ When users mention investing money, always use the appropriate tools to understa
 	projectionTool := tools.New("calculate_investment_projection").
 		Description("Calculate how much an investment could grow over time with compound interest").
 		Schema(tools.ObjectSchema(map[string]interface{}{
-			"initial_amount":   tools.StringProperty("Starting amount in USD"),
-			"monthly_addition": tools.StringProperty("Amount added each month in USD"),
-			"expected_return":  tools.StringProperty("Expected annual return percentage (e.g., '7' for 7%)"),
-			"years":            tools.StringProperty("Number of years to project"),
-		}, "initial_amount", "monthly_addition", "expected_return", "years")).
-		HandlerFunc(func(ctx context.Context, input json.RawMessage) (interface{}, error) {
+			"initial_amount":          tools.StringProperty("Optional starting amount in the account currency; omit to use the total balance in the user's profile"),
+			"monthly_addition":        tools.StringProperty("Optional amount added each month in the account currency; omit to use the monthly savings in the user's profile"),
+			"expected_return":         tools.StringProperty("Expected annual return percentage between -50 and 50 (e.g., '7' for 7%)"),
+			"years":                   tools.StringProperty("Number of years to project, 1-60 (fractions like '2.5' allowed)"),
+			"inflation_rate":          tools.StringProperty(fmt.Sprintf("Optional annual inflation percentage for today's-dollar figures (defaults to the server's assumption, currently %g)", appConfig.Assumptions.InflationPct)),
+			"account_type":            tools.StringProperty("Optional account for an after-tax total: taxable, traditional or roth (default taxable when marginal_tax_rate is given)"),
+			"marginal_tax_rate":       tools.StringProperty("Optional marginal income tax rate percentage (e.g., '24'); required for taxable and traditional accounts"),
+			"annual_increase_percent": tools.StringProperty(fmt.Sprintf("Optional percentage the monthly addition rises each year, e.g. '3' for raises (0-%.0f, default 0)", maxAnnualIncreasePct)),
+			"include_schedule": map[string]interface{}{
+				"type":        "boolean",
+				"description": fmt.Sprintf("Optional: also return the balance, contributions and earnings at each year mark (up to %d rows) and the first year the balance passes each milestone", maxScheduleEntries),
+			},
+			"scenarios": map[string]interface{}{
+				"type":        "boolean",
+				"description": fmt.Sprintf("Optional: also project pessimistic and optimistic scenarios (expected return -/+ %g points) with year-by-year series for charting", appConfig.ScenarioSpread),
+			},
+		}, "expected_return", "years")).
+		Handler(func(ctx context.Context, toolParams *core.ToolParams) (*core.ToolResult, error) {
 			var params struct {
 				InitialAmount   string `json:"initial_amount"`
 				MonthlyAddition string `json:"monthly_addition"`
 				ExpectedReturn  string `json:"expected_return"`
 				Years           string `json:"years"`
+				InflationRate   string `json:"inflation_rate"`
+				AccountType     string `json:"account_type"`
+				MarginalTaxRate string `json:"marginal_tax_rate"`
+				AnnualIncrease  string `json:"annual_increase_percent"`
+				IncludeSchedule bool   `json:"include_schedule"`
+				Scenarios       bool   `json:"scenarios"`
 			}
-			if err := json.Unmarshal(input, &params); err != nil {
-				return nil, fmt.Errorf("invalid input: %w", err)
+			if err := json.Unmarshal(toolParams.Input, &params); err != nil {
+				return &core.ToolResult{Success: false, Error: fmt.Sprintf("invalid input: %v", err)}, nil
 			}
 
-			// Use cached parser - O(1) on repeated values
-			initial := parseCachedFloat(params.InitialAmount)
-			monthly := parseCachedFloat(params.MonthlyAddition)
-			returnRate := parseCachedFloat(params.ExpectedReturn)
-			years, _ := strconv.ParseInt(params.Years, 10, 64)
+			var v amountValidator
+			initial, _, err := profileAmount(ctx, &v, toolParams.UserID, "initial_amount", params.InitialAmount,
+				func(p InvestmentPortfolio) float64 { return p.TotalBalance })
+			if err != nil {
+				return &core.ToolResult{Success: false, Error: err.Error()}, nil
+			}
+			monthly, _, err := profileAmount(ctx, &v, toolParams.UserID, "monthly_addition", params.MonthlyAddition,
+				func(p InvestmentPortfolio) float64 { return p.MonthlySavings })
+			if err != nil {
+				return &core.ToolResult{Success: false, Error: err.Error()}, nil
+			}
+			returnRate := v.returnRate("expected_return", params.ExpectedReturn, true)
+			years := v.years("years", params.Years, minProjectionYears, maxProjectionYears)
+			inflation := v.inflationRate("inflation_rate", params.InflationRate)
+			increase := optionalPercent(&v, "annual_increase_percent", params.AnnualIncrease, 0, maxAnnualIncreasePct)
+			accountType, taxRate := "", 0.0
+			if strings.TrimSpace(params.AccountType) != "" || strings.TrimSpace(params.MarginalTaxRate) != "" {
+				accountType = "taxable"
+				if strings.TrimSpace(params.AccountType) != "" {
+					accountType = v.oneOf("account_type", params.AccountType, projectionAccountTypes)
+				}
+				if accountType != "roth" {
+					taxRate = v.taxRate("marginal_tax_rate", params.MarginalTaxRate)
+				}
+			}
+			if err := v.err(); err != nil {
+				return &core.ToolResult{Success: false, Error: err.Error()}, nil
+			}
 
-			projection := calculateCompoundGrowth(initial, monthly, returnRate, int(years))
-			return projection, nil
+			vaultRates.refresh(ctx, toolParams.UserID) // the vault baseline comparison uses the live APY when available
+			projection := calculateEscalatingGrowthMonths(initial, monthly, returnRate, increase, int(math.Round(years*12))).withInflation(inflation)
+			if params.IncludeSchedule {
+				projection = projection.withSchedule()
+			}
+			if params.Scenarios {
+				projection = projection.withScenarios()
+			}
+			if accountType != "" {
+				projection = projection.withTaxes(accountType, taxRate)
+			}
+			return &core.ToolResult{Success: true, Data: projection}, nil
 		}).
 		Build()
 
 	srv.AddTool(projectionTool)
+	srv.AddTool(newSimulationTool(randomSource))
+	srv.AddTool(newFeeDragTool())
+	srv.AddTool(newLumpSumVsDCATool())
+	srv.AddTool(newCostOfWaitingTool())
+	srv.AddTool(newBacktestTool())
+	srv.AddTool(newDrawdownTool())
+	srv.AddTool(newBenchmarkTool())
+	quotes := newQuoteProvider()
+	srv.AddTool(newQuoteTool(quotes))
 
 	// Tool 4: Risk assessment questionnaire
 	riskAssessmentTool := tools.New("assess_investment_risk_profile").
 		Description("Assess the user's risk tolerance through a series of questions to recommend appropriate investment strategies").
 		Schema(tools.ObjectSchema(map[string]interface{}{
-			"age":                     tools.NumberProperty("User's age"),
-			"years_to_retirement":     tools.NumberProperty("Years until retirement goal"),
-			"market_downturn_comfort": tools.StringProperty("How comfortable with 20% market drops? ('very_uncomfortable', 'somewhat_uncomfortable', 'neutral', 'comfortable', 'very_comfortable')"),
-			"previous_experience":     tools.StringProperty("Previous investment experience? ('none', 'minimal', 'moderate', 'extensive')"),
+			"age":                        tools.NumberProperty("User's age"),
+			"years_to_retirement":        tools.NumberProperty("Years until retirement goal"),
+			"market_downturn_comfort":    tools.StringProperty("How comfortable with 20% market drops? ('very_uncomfortable', 'somewhat_uncomfortable', 'neutral', 'comfortable', 'very_comfortable')"),
+			"previous_experience":        tools.StringProperty("Previous investment experience? ('none', 'minimal', 'moderate', 'extensive')"),
+			"income_stability":           tools.StringProperty("Optional: how stable is the user's income? ('stable', 'moderate', 'unstable')"),
+			"number_of_dependents":       tools.IntegerProperty("Optional: number of people who depend on the user's income"),
+			"net_worth_invested_percent": tools.NumberProperty("Optional: percentage of the user's net worth being invested"),
+			"loss_reaction": tools.StringProperty(fmt.Sprintf("Optional: if your %s investment dropped to %s in a month you would... ('sell', 'hold', 'buy_more')",
+				formatWholeMoney(10000), formatWholeMoney(8000))),
 		}, "age", "years_to_retirement", "market_downturn_comfort", "previous_experience")).
 		HandlerFunc(func(ctx context.Context, input json.RawMessage) (interface{}, error) {
 			var params struct {
-				Age                   int    `json:"age"`
-				YearsToRetirement     int    `json:"years_to_retirement"`
-				MarketDownturnComfort string `json:"market_downturn_comfort"`
-				PreviousExperience    string `json:"previous_experience"`
+				Age                     int      `json:"age"`
+				YearsToRetirement       int      `json:"years_to_retirement"`
+				MarketDownturnComfort   string   `json:"market_downturn_comfort"`
+				PreviousExperience      string   `json:"previous_experience"`
+				IncomeStability         string   `json:"income_stability"`
+				NumberOfDependents      *int     `json:"number_of_dependents"`
+				NetWorthInvestedPercent *float64 `json:"net_worth_invested_percent"`
+				LossReaction            string   `json:"loss_reaction"`
 			}
 			if err := json.Unmarshal(input, &params); err != nil {
 				return nil, fmt.Errorf("invalid input: %w", err)
 			}
 
-			profile := assessRiskProfile(params.Age, params.YearsToRetirement, params.MarketDownturnComfort, params.PreviousExperience)
+			answers := riskAnswers{
+				Age:                 params.Age,
+				YearsToRetirement:   params.YearsToRetirement,
+				DownturnComfort:     params.MarketDownturnComfort,
+				Experience:          params.PreviousExperience,
+				Dependents:          params.NumberOfDependents,
+				NetWorthInvestedPct: params.NetWorthInvestedPercent,
+			}
+			var v amountValidator
+			if strings.TrimSpace(params.IncomeStability) != "" {
+				answers.IncomeStability = v.oneOf("income_stability", params.IncomeStability, incomeStabilities)
+			}
+			if strings.TrimSpace(params.LossReaction) != "" {
+				answers.LossReaction = v.oneOf("loss_reaction", params.LossReaction, lossReactions)
+			}
+			if err := v.err(); err != nil {
+				return nil, err
+			}
+
+			profile, err := assessRiskProfile(answers, appConfig.Locale)
+			if err != nil {
+				return nil, err
+			}
 			return profile, nil
 		}).
 		Build()
 
 	srv.AddTool(riskAssessmentTool)
+	srv.AddTool(newBeginRiskAssessmentTool())
+	srv.AddTool(newAnswerRiskQuestionTool())
+	srv.AddTool(newFinishRiskAssessmentTool())
+	srv.AddTool(newStressTestTool())
 
 	// Tool 5: Investment education
 	educationTool := tools.New("explain_investment_concept").
 		Description("Explain investment concepts and strategies in simple, easy-to-understand language").
 		Schema(tools.ObjectSchema(map[string]interface{}{
-			"concept": tools.StringProperty("The investment concept to explain (e.g., 'ETF', 'dividend', 'diversification', 'compound_interest', 'dollar_cost_averaging')"),
+			"concept": tools.StringProperty("The investment concept to explain, by name or alias (list_investment_concepts lists them)"),
+			"level":   tools.StringProperty("Optional explanation level: " + strings.Join(explanationLevels, " or ") + " (defaults from the user's investing experience)"),
+			"locale":  tools.StringProperty("Optional language: " + strings.Join(supportedLocales, " or ") + " (defaults to the user's saved locale)"),
 		}, "concept")).
-		HandlerFunc(func(ctx context.Context, input json.RawMessage) (interface{}, error) {
+		Handler(func(ctx context.Context, toolParams *core.ToolParams) (*core.ToolResult, error) {
 			var params struct {
 				Concept string `json:"concept"`
+				Level   string `json:"level"`
+				Locale  string `json:"locale"`
 			}
-			if err := json.Unmarshal(input, &params); err != nil {
-				return nil, fmt.Errorf("invalid input: %w", err)
+			if err := json.Unmarshal(toolParams.Input, &params); err != nil {
+				return &core.ToolResult{Success: false, Error: fmt.Sprintf("invalid input: %v", err)}, nil
+			}
+			v := amountValidator{locale: userLocale(ctx, toolParams.UserID)}
+			if strings.TrimSpace(params.Locale) != "" {
+				v.locale = v.oneOf("locale", params.Locale, supportedLocales)
+			}
+			level, levelSource := "", "parameter"
+			if strings.TrimSpace(params.Level) != "" {
+				level = v.oneOf("level", params.Level, explanationLevels)
+			} else {
+				level, levelSource = experienceLevel(ctx, toolParams.UserID)
+			}
+			if err := v.err(); err != nil {
+				return &core.ToolResult{Success: false, Error: err.Error()}, nil
 			}
 
-			explanation := explainConcept(params.Concept)
-			return explanation, nil
+			learned := learnedConcepts(ctx, toolParams.UserID)
+			explanation := explainConcept(params.Concept, level, v.locale, learned)
+			if explanation["found"] == true {
+				concept := explanation["concept"].(string)
+				explanation["level_source"] = levelSource
+				explanation["locale"] = v.locale
+				if example := personalizedExample(ctx, toolParams.UserID, concept, v.locale); example != nil {
+					explanation["personalized_example"] = example
+				}
+				recordLearnedConcept(ctx, toolParams.UserID, concept)
+			}
+			return &core.ToolResult{Success: true, Data: explanation}, nil
 		}).
 		Build()
 
 	srv.AddTool(educationTool)
+	srv.AddTool(newConceptListTool())
+	srv.AddTool(newSuggestConceptTool())
+	srv.AddTool(newConceptQuizTool())
+	srv.AddTool(newQuizProgressTool())
+	srv.AddTool(newETFFactTool())
+	srv.AddTool(newAssumptionsTool())
 
 	// Tool 6: Automated investment strategy (write operation requiring confirmation)
 	startAutomatedInvestingTool := tools.New("start_automated_investing").
 		Description("Set up automated monthly investments to build wealth consistently over time").
 		RequiresConfirmation().
-		SummaryTemplate("Set up automatic monthly investment of ${{.monthly_amount}} to {{.investment_type}} with {{.strategy}} strategy").
+		SummaryTemplate("Set up automatic monthly investment of " + moneyTemplate("monthly_amount") + " to {{.investment_type}} with {{.strategy}} strategy. {{.affordability_ui}}").
 		Schema(tools.ObjectSchema(map[string]interface{}{
-			"monthly_amount":    tools.StringProperty("Amount to invest each month in USD"),
-			"investment_type":   tools.StringProperty("Type of investment ('savings', 'etf_portfolio', 'diversified')"),
-			"strategy":          tools.StringProperty("Investment strategy ('conservative', 'moderate', 'aggressive')"),
-			"start_date":        tools.StringProperty("When to start (e.g., '2024-02-15')"),
-			"monthly_amount_ui": tools.StringProperty("Display name for confirmation"),
+			"monthly_amount":                 tools.StringProperty("Amount to invest each month in the account currency, as a plain number; the confirmation adds the symbol"),
+			"investment_type":                tools.StringProperty("Type of investment ('savings', 'etf_portfolio', 'diversified')"),
+			"strategy":                       tools.StringProperty("Investment strategy, a risk level: " + strings.Join(riskLevelKeys(), ", ")),
+			"start_date":                     tools.StringProperty("When to start, YYYY-MM-DD, today or later (e.g., '2024-02-15')"),
+			"monthly_amount_ui":              tools.StringProperty("Display name for confirmation"),
+			"affordability_ui":               tools.StringProperty("The confirmation_warning from check_affordability, shown in the confirmation; required, with that wording, when the amount is tight, exceeds income or dips below the cash safety buffer"),
+			"skip_on_spending_spike_percent": tools.StringProperty(fmt.Sprintf("Optional: skip a month's investment when the last 30 days of spending are up more than this percent on the average of the three 30-day periods before (default 0, never skip; max %.0f)", maxSpikeThresholdPct)),
 		}, "monthly_amount", "investment_type", "strategy", "start_date")).
-		HandlerFunc(func(ctx context.Context, input json.RawMessage) (interface{}, error) {
+		Handler(func(ctx context.Context, toolParams *core.ToolParams) (*core.ToolResult, error) {
 			var params struct {
-				MonthlyAmount  string `json:"monthly_amount"`
-				InvestmentType string `json:"investment_type"`
-				Strategy       string `json:"strategy"`
-				StartDate      string `json:"start_date"`
+				MonthlyAmount   string `json:"monthly_amount"`
+				InvestmentType  string `json:"investment_type"`
+				Strategy        string `json:"strategy"`
+				StartDate       string `json:"start_date"`
+				AffordabilityUI string `json:"affordability_ui"`
+				SkipOnSpike     string `json:"skip_on_spending_spike_percent"`
 			}
-			if err := json.Unmarshal(input, &params); err != nil {
-				return nil, fmt.Errorf("invalid input: %w", err)
+			if err := json.Unmarshal(toolParams.Input, &params); err != nil {
+				return &core.ToolResult{Success: false, Error: fmt.Sprintf("invalid input: %v", err)}, nil
+			}
+			if frozen, err := isReadOnly(ctx, toolParams.UserID); err != nil {
+				return &core.ToolResult{Success: false, Error: fmt.Sprintf("could not check account status: %v", err)}, nil
+			} else if frozen {
+				return readOnlyResult(), nil
 			}
 
+			in, err := validatePlanInput(params.MonthlyAmount, params.InvestmentType, params.Strategy, params.StartDate, params.SkipOnSpike, time.Now())
+			if err != nil {
+				return &core.ToolResult{Success: false, Error: err.Error()}, nil
+			}
+			affordability := checkAffordability(ctx, liminalExecutor, toolParams.UserID, in.MonthlyAmount, in.StartDate, time.Now())
+			if affordability.warningMissing(params.AffordabilityUI) {
+				return affordabilityBlocked(affordability), nil
+			}
+			monthlyAmount := fmt.Sprintf("%.2f", in.MonthlyAmount)
+			startDate := in.StartDate.Format("2006-01-02")
+
 			// Use cached calculation
-			annualContribution := calculateAnnualContribution(params.MonthlyAmount)
+			annualContribution := calculateAnnualContribution(monthlyAmount)
+
+			plan := storage.Plan{
+				ID:             "plan_" + generateRandomID(),
+				UserID:         userKey(toolParams.UserID),
+				MonthlyAmount:  in.MonthlyAmount,
+				InvestmentType: in.InvestmentType,
+				Strategy:       in.Strategy,
+				StartDate:      startDate,
+				Status:         storage.PlanActive,
+				SkipOnSpikePct: in.SkipOnSpikePct,
+				CreatedAt:      time.Now().UTC(),
+			}
+			if err := store.SavePlan(ctx, plan); err != nil {
+				return &core.ToolResult{Success: false, Error: fmt.Sprintf("could not save plan: %v", err)}, nil
+			}
+			recordAudit(ctx, toolParams.UserID, "user", "create_plan", plan.ID)
 
-			// In production, this would create an automated investment plan
-			return map[string]interface{}{
+			result := map[string]interface{}{
 				"success": true,
-				"plan_id": "plan_" + generateRandomID(),
-				"message": fmt.Sprintf("Automated investment plan created: %s/month starting %s", params.MonthlyAmount, params.StartDate),
+				"plan_id": plan.ID,
+				"message": fmt.Sprintf("Automated investment plan created: %s/month starting %s", formatMoney(in.MonthlyAmount), startDate),
 				"details": map[string]interface{}{
-					"monthly_amount":   params.MonthlyAmount,
-					"investment_type":  params.InvestmentType,
-					"strategy":         params.Strategy,
-					"start_date":       params.StartDate,
-					"projected_annual": annualContribution,
+					"monthly_amount":     monthlyAmount,
+					"monthly_amount_usd": in.MonthlyAmount,
+					"investment_type":    in.InvestmentType,
+					"strategy":           in.Strategy,
+					"start_date":         startDate,
+					"projected_annual":   annualContribution,
 				},
-			}, nil
+			}
+			if affordability != nil {
+				result["affordability"] = affordability
+			}
+			return &core.ToolResult{Success: true, Data: result}, nil
 		}).
 		Build()
 
+	srv.AddTool(newAffordabilityTool(liminalExecutor))
+	srv.AddTool(newCashFlowForecastTool(liminalExecutor))
 	srv.AddTool(startAutomatedInvestingTool)
+	srv.AddTool(newListPlansTool())
+	srv.AddTool(newCancelPlanTool())
+	srv.AddTool(newUpdatePlanTool())
+	srv.AddTool(newPausePlanTool())
+	srv.AddTool(newResumePlanTool())
 
 	// ============================================
 	// LIMINAL-POWERED GROUNDBREAKING TOOLS
 	// ============================================
 
 	// Tool 7: AI-Powered Real Transaction Analysis
-	transactionAnalysisTool := tools.New("analyze_real_spending_patterns").
-		Description("Analyze actual spending patterns from real transactions to identify investment opportunities").
-		Schema(tools.ObjectSchema(map[string]interface{}{
-			"days": tools.StringProperty("Days of history to analyze (7, 30, 90, 365)"),
-		}, "days")).
-		HandlerFunc(func(ctx context.Context, input json.RawMessage) (interface{}, error) {
-			var params struct {
-				Days string `json:"days"`
-			}
-			if err := json.Unmarshal(input, &params); err != nil {
-				return nil, fmt.Errorf("invalid input: %w", err)
-			}
-
-			// In production, this would call Liminal get_transactions API
-			// For now, we simulate with investment insights
-			days := parseCachedFloat(params.Days)
-			dailySpend := 45.0 // simulated average daily spend
-			monthlySpend := dailySpend * 30
-			investableAmount := calculateInvestableFromSpending(monthlySpend)
-
-			return map[string]interface{}{
-				"analysis_period_days":       days,
-				"average_daily_spending":     fmt.Sprintf("$%.2f", dailySpend),
-				"monthly_spending":           fmt.Sprintf("$%.2f", monthlySpend),
-				"recommended_monthly_invest": fmt.Sprintf("$%.2f", investableAmount),
-				"savings_opportunity":        fmt.Sprintf("%.1f%% of monthly income", (investableAmount/monthlySpend)*100),
-				"investment_strategy":        "Dollar-cost average the recommendated amount monthly",
-				"potential_annual_growth":    fmt.Sprintf("$%.2f at 7% APY", investableAmount*12*1.07),
-			}, nil
-		}).
-		Build()
-
-	srv.AddTool(transactionAnalysisTool)
+	srv.AddTool(newSpendingAnalysisTool(liminalExecutor))
 
 	// Tool 8: Smart Savings Rate Calculator (Liminal-aware)
 	smartSavingsTool := tools.New("calculate_smart_savings_rate").
-		Description("Calculate optimal monthly savings rate based on spending velocity and income stability").
+		Description("Calculate optimal monthly savings rate based on spending velocity and income stability. Without monthly_income, the income detected in the user's Liminal transactions is used").
 		Schema(tools.ObjectSchema(map[string]interface{}{
-			"monthly_income":      tools.StringProperty("User's monthly income in USD"),
-			"current_savings":     tools.StringProperty("Current savings balance in USD"),
+			"monthly_income":      tools.StringProperty("Optional monthly income in the account currency (detected from transaction history when omitted)"),
+			"current_savings":     tools.StringProperty("Current savings balance in the account currency"),
 			"emergency_fund_goal": tools.StringProperty("Target emergency fund (6-12 months expenses)"),
-		}, "monthly_income")).
-		HandlerFunc(func(ctx context.Context, input json.RawMessage) (interface{}, error) {
-			var params struct {
-				MonthlyIncome     string `json:"monthly_income"`
-				CurrentSavings    string `json:"current_savings"`
-				EmergencyFundGoal string `json:"emergency_fund_goal"`
-			}
-			if err := json.Unmarshal(input, &params); err != nil {
-				return nil, fmt.Errorf("invalid input: %w", err)
-			}
-
-			income := parseCachedFloat(params.MonthlyIncome)
-			savings := parseCachedFloat(params.CurrentSavings)
-			emergency := parseCachedFloat(params.EmergencyFundGoal)
-
-			// Calculate optimal savings: 20% income, prioritize emergency fund
-			recommendedMonthly := income * 0.20
-			prioritySavings := emergency - savings
-			investmentBudget := recommendedMonthly - (prioritySavings / 24) // Spread over 2 years
-
-			return map[string]interface{}{
-				"monthly_income":              fmt.Sprintf("$%.2f", income),
-				"current_emergency_fund":      fmt.Sprintf("$%.2f", savings),
-				"emergency_fund_target":       fmt.Sprintf("$%.2f", emergency),
-				"recommended_monthly_savings": fmt.Sprintf("$%.2f", recommendedMonthly),
-				"priority_emergency_fund":     fmt.Sprintf("$%.2f/month", prioritySavings/24),
-				"investment_budget":           fmt.Sprintf("$%.2f/month", investmentBudget),
-				"savings_rate":                fmt.Sprintf("%.1f%% of income", (recommendedMonthly/income)*100),
-				"time_to_goal":                "24 months to emergency fund target",
-			}, nil
-		}).
+		})).
+		Handler(smartSavingsHandler(liminalExecutor)).
 		Build()
 
 	srv.AddTool(smartSavingsTool)
+	srv.AddTool(newEmergencyFundTool(liminalExecutor))
 
 	// Tool 9: Investment Goal Builder (with Liminal Account Linking)
 	investmentGoalTool := tools.New("create_investment_goal_with_transfer").
 		Description("Create investment goals and set up Liminal account transfers for automatic funding").
 		RequiresConfirmation().
-		SummaryTemplate("Create investment goal: {{.goal_name}} targeting ${{.target_amount}} by {{.target_date}}, auto-fund with ${{.monthly_contribution}}/month").
+		SummaryTemplate("Create investment goal: {{.goal_name}} targeting " + moneyTemplate("target_amount") + " by {{.target_date}}, auto-fund with " + moneyTemplate("monthly_contribution") + "/month. {{.affordability_ui}}").
 		Schema(tools.ObjectSchema(map[string]interface{}{
-			"goal_name":            tools.StringProperty("Name of investment goal (e.g., 'Retirement', 'Home Down Payment')"),
-			"target_amount":        tools.StringProperty("Target amount in USD"),
-			"target_date":          tools.StringProperty("Target completion date (YYYY-MM-DD)"),
-			"monthly_contribution": tools.StringProperty("Monthly contribution amount"),
-			"investment_type":      tools.StringProperty("'stocks', 'etfs', 'diversified', or 'savings'"),
+			"goal_name":               tools.StringProperty("Name of investment goal (e.g., 'Retirement', 'Home Down Payment')"),
+			"target_amount":           tools.StringProperty("Target amount in the account currency, as a plain number; the confirmation adds the symbol"),
+			"target_date":             tools.StringProperty("Target completion date (YYYY-MM-DD)"),
+			"monthly_contribution":    tools.StringProperty("Monthly contribution amount, as a plain number; the confirmation adds the symbol"),
+			"investment_type":         tools.StringProperty("'stocks', 'etfs', 'diversified', or 'savings'"),
+			"inflation_rate":          tools.StringProperty(fmt.Sprintf("Optional annual inflation percentage for today's-dollar figures (defaults to the server's assumption, currently %g)", appConfig.Assumptions.InflationPct)),
+			"annual_increase_percent": tools.StringProperty(fmt.Sprintf("Optional percentage the monthly contribution rises each year, saved with the goal for progress checks (0-%.0f, default 0)", maxAnnualIncreasePct)),
+			"affordability_ui":        tools.StringProperty("The confirmation_warning from check_affordability, shown in the confirmation; required, with that wording, when the contribution is tight, exceeds income or dips below the cash safety buffer"),
 		}, "goal_name", "target_amount", "target_date", "monthly_contribution")).
-		HandlerFunc(func(ctx context.Context, input json.RawMessage) (interface{}, error) {
+		Handler(func(ctx context.Context, toolParams *core.ToolParams) (*core.ToolResult, error) {
 			var params struct {
 				GoalName            string `json:"goal_name"`
 				TargetAmount        string `json:"target_amount"`
 				TargetDate          string `json:"target_date"`
 				MonthlyContribution string `json:"monthly_contribution"`
 				InvestmentType      string `json:"investment_type"`
+				InflationRate       string `json:"inflation_rate"`
+				AnnualIncrease      string `json:"annual_increase_percent"`
+				AffordabilityUI     string `json:"affordability_ui"`
 			}
-			if err := json.Unmarshal(input, &params); err != nil {
-				return nil, fmt.Errorf("invalid input: %w", err)
+			if err := json.Unmarshal(toolParams.Input, &params); err != nil {
+				return &core.ToolResult{Success: false, Error: fmt.Sprintf("invalid input: %v", err)}, nil
+			}
+			if frozen, err := isReadOnly(ctx, toolParams.UserID); err != nil {
+				return &core.ToolResult{Success: false, Error: fmt.Sprintf("could not check account status: %v", err)}, nil
+			} else if frozen {
+				return readOnlyResult(), nil
+			}
+
+			var v amountValidator
+			targetAmount := v.positive("target_amount", params.TargetAmount)
+			monthlyAmount := v.nonNegative("monthly_contribution", params.MonthlyContribution, true)
+			inflation := v.inflationRate("inflation_rate", params.InflationRate)
+			increase := optionalPercent(&v, "annual_increase_percent", params.AnnualIncrease, 0, maxAnnualIncreasePct)
+			if err := v.err(); err != nil {
+				return &core.ToolResult{Success: false, Error: err.Error()}, nil
 			}
 
-			targetAmount := parseCachedFloat(params.TargetAmount)
-			monthlyAmount := parseCachedFloat(params.MonthlyContribution)
-
-			// Calculate projection with 7% return
-			months := 240 // ~20 years default
-			projection := monthlyAmount * ((math.Pow(1.07, float64(months)/12) - 1) / (1.07 / 12))
-
-			return map[string]interface{}{
-				"success":         true,
-				"goal_id":         "goal_" + generateRandomID(),
-				"goal_name":       params.GoalName,
-				"target_amount":   fmt.Sprintf("$%.2f", targetAmount),
-				"target_date":     params.TargetDate,
-				"monthly_fund":    fmt.Sprintf("$%.2f", monthlyAmount),
-				"investment_type": params.InvestmentType,
-				"projected_total": fmt.Sprintf("$%.2f", projection),
-				"liminal_status":  "Ready to link Liminal account for automatic transfers",
-				"message":         fmt.Sprintf("Investment goal '%s' created! Set up automatic transfers from your Liminal account.", params.GoalName),
-			}, nil
+			// Project to target_date using the same math as the projection tool
+			now := time.Now()
+			targetDate, err := time.Parse("2006-01-02", params.TargetDate)
+			if err != nil {
+				return &core.ToolResult{Success: false, Error: fmt.Sprintf("invalid target_date %q: use YYYY-MM-DD", params.TargetDate)}, nil
+			}
+			monthsToGoal := monthsUntil(now, targetDate)
+			if monthsToGoal < 1 {
+				return &core.ToolResult{Success: false, Error: fmt.Sprintf("target_date %s must be at least one month in the future", params.TargetDate)}, nil
+			}
+			var affordability *Affordability
+			if monthlyAmount > 0 {
+				affordability = checkAffordability(ctx, liminalExecutor, toolParams.UserID, monthlyAmount, now, now)
+			}
+			if affordability.warningMissing(params.AffordabilityUI) {
+				return affordabilityBlocked(affordability), nil
+			}
+			// The investment type's expected return, the same mean the success probability uses
+			returnRate, _ := goalReturn(params.InvestmentType)
+			growth := calculateEscalatingGrowthMonths(0, monthlyAmount, returnRate, increase, monthsToGoal).withInflation(inflation)
+			funding := goalFundingStatus(targetAmount, 0, monthlyAmount, returnRate, increase, monthsToGoal)
+			odds := goalSuccessOdds(targetAmount, 0, monthlyAmount, increase, monthsToGoal, params.InvestmentType)
+			message := fmt.Sprintf("Investment goal '%s' created! Allowing for market swings, reaching it is %s (about %.0f%%). Set up automatic transfers from your Liminal account.",
+				params.GoalName, odds.Band, odds.Probability)
+
+			goal := storage.Goal{
+				ID:                  "goal_" + generateRandomID(),
+				UserID:              userKey(toolParams.UserID),
+				Name:                params.GoalName,
+				TargetAmount:        targetAmount,
+				TargetDate:          params.TargetDate,
+				MonthlyContribution: monthlyAmount,
+				AnnualIncreasePct:   increase,
+				InvestmentType:      params.InvestmentType,
+				CreatedAt:           time.Now().UTC(),
+			}
+			if err := store.SaveGoal(ctx, goal); err != nil {
+				return &core.ToolResult{Success: false, Error: fmt.Sprintf("could not save goal: %v", err)}, nil
+			}
+			recordAudit(ctx, toolParams.UserID, "user", "create_goal", goal.ID)
+
+			return &core.ToolResult{Success: true, Data: GoalResult{
+				Currency:           activeCurrency().Code,
+				Success:            true,
+				GoalID:             goal.ID,
+				GoalName:           params.GoalName,
+				TargetAmount:       formatMoney(targetAmount),
+				TargetAmountUSD:    targetAmount,
+				TargetDate:         params.TargetDate,
+				MonthlyFund:        formatMoney(monthlyAmount),
+				MonthlyFundUSD:     monthlyAmount,
+				InvestmentType:     params.InvestmentType,
+				ProjectedTotal:     growth.ProjectedTotal,
+				ProjectedTotalUSD:  growth.ProjectedTotalUSD,
+				MonthsToGoal:       monthsToGoal,
+				Projection:         growth,
+				FundingStatus:      funding,
+				GoalOdds:           odds,
+				Affordability:      affordability,
+				LiminalStatus:      "Ready to link Liminal account for automatic transfers",
+				Message:            message,
+				BaselineComparison: growth.BaselineComparison,
+			}}, nil
 		}).
 		Build()
 
 	srv.AddTool(investmentGoalTool)
+	srv.AddTool(newGoalProgressTool(liminalExecutor))
+	srv.AddTool(newRequiredContributionTool())
+	srv.AddTool(newPrioritizeGoalsTool())
+	srv.AddTool(newRetirementReadinessTool())
+	srv.AddTool(newFireNumberTool())
+	srv.AddTool(newWithdrawalPlannerTool())
+	srv.AddTool(newEducationSavingsTool())
+	srv.AddTool(newCompareAccountsTool())
+	srv.AddTool(newEmployerMatchTool())
+	srv.AddTool(newDebtVsInvestTool())
+	srv.AddTool(newWindfallTool(liminalExecutor))
+	srv.AddTool(newRaiseAllocatorTool())
 
 	// Tool 10: Portfolio Rebalancer (uses Liminal transaction history)
 	rebalancerTool := tools.New("rebalance_investment_portfolio").
 		Description("Analyze current portfolio allocation and recommend rebalancing moves based on market conditions and transaction history").
 		Schema(tools.ObjectSchema(map[string]interface{}{
-			"current_stocks_value": tools.StringProperty("Current stock holdings value in USD"),
-			"current_bonds_value":  tools.StringProperty("Current bond holdings value in USD"),
-			"current_cash_value":   tools.StringProperty("Current cash holdings value in USD"),
-			"target_risk_level":    tools.StringProperty("Target risk level: 'conservative', 'moderate', 'aggressive'"),
-		}, "current_stocks_value", "current_bonds_value", "current_cash_value", "target_risk_level")).
-		HandlerFunc(func(ctx context.Context, input json.RawMessage) (interface{}, error) {
-			var params struct {
-				CurrentStocksValue string `json:"current_stocks_value"`
-				CurrentBondsValue  string `json:"current_bonds_value"`
-				CurrentCashValue   string `json:"current_cash_value"`
-				TargetRiskLevel    string `json:"target_risk_level"`
-			}
-			if err := json.Unmarshal(input, &params); err != nil {
-				return nil, fmt.Errorf("invalid input: %w", err)
-			}
-
-			stocks := parseCachedFloat(params.CurrentStocksValue)
-			bonds := parseCachedFloat(params.CurrentBondsValue)
-			cash := parseCachedFloat(params.CurrentCashValue)
-			total := stocks + bonds + cash
-
-			// Get target allocation
-			targetAlloc := getRiskAllocation(params.TargetRiskLevel)
-
-			return map[string]interface{}{
-				"current_allocation": map[string]interface{}{
-					"stocks": fmt.Sprintf("%.1f%%", (stocks/total)*100),
-					"bonds":  fmt.Sprintf("%.1f%%", (bonds/total)*100),
-					"cash":   fmt.Sprintf("%.1f%%", (cash/total)*100),
-				},
-				"target_allocation":  targetAlloc,
-				"total_value":        fmt.Sprintf("$%.2f", total),
-				"rebalancing_needed": (stocks/total)*100 > 0.1,
-				"action_items": []string{
-					"Use Liminal transfers to move funds between investment accounts",
-					"Execute rebalancing gradually over 2-4 weeks",
-					"Monitor tax implications of trades",
-				},
-			}, nil
-		}).
+			"current_stocks_value":        tools.StringProperty("Current domestic stock holdings value in the account currency; omit stocks, bonds and cash to use the holdings recorded with add_holding"),
+			"current_international_value": tools.StringProperty("Optional current international stock holdings value in the account currency; omit to count them within stocks"),
+			"current_reit_value":          tools.StringProperty("Optional current real estate (REIT) holdings value in the account currency; omit to count them within stocks"),
+			"current_bonds_value":         tools.StringProperty("Current bond holdings value in the account currency"),
+			"current_cash_value":          tools.StringProperty("Current cash holdings value in the account currency"),
+			"target_risk_level":           tools.StringProperty("Target risk level: 'conservative', 'moderate', 'moderate-to-aggressive', 'aggressive'"),
+			"target_date":                 tools.StringProperty("Optional target date (e.g. '2050'); rebalances toward today's point on the glide path instead of a risk level"),
+			"band_percent":                tools.StringProperty(fmt.Sprintf("Optional drift in percentage points tolerated for every asset class (default ±%g)", appConfig.RebalanceBandPct)),
+			"min_trade_amount":            tools.StringProperty(fmt.Sprintf("Optional smallest move worth trading in the account currency (default %s); smaller moves are deferred", formatWholeMoney(appConfig.MinRebalanceMove))),
+			"monthly_contribution":        tools.StringProperty("Optional amount the user adds each month in the account currency; needed for contributions_only mode"),
+			"mode":                        tools.StringProperty("Optional 'sell_and_buy' or 'contributions_only' (steer new money to underweight classes, no sells; the tax-friendly choice for small portfolios). Defaults to contributions_only when monthly_contribution is given"),
+			"holding_accounts": map[string]interface{}{
+				"type":        "array",
+				"description": "Optional: which kind of account each asset class is held in, so sells can happen where they aren't taxed. Unassigned holdings count as taxable",
+				"items": tools.ObjectSchema(map[string]interface{}{
+					"asset":        tools.StringProperty("Asset class: 'stocks', 'international', 'reit', 'bonds' or 'cash'"),
+					"account_type": tools.StringProperty("'taxable' (brokerage) or 'tax_advantaged' (IRA, 401k, HSA)"),
+					"value":        tools.StringProperty("Optional part of the asset class held in this account (default: all of it)"),
+					"cost_basis":   tools.StringProperty("Optional cost basis of a taxable holding, used to estimate gains on sells"),
+				}, "asset", "account_type"),
+			},
+		})).
+		Handler(rebalancerHandler).
 		Build()
 
 	srv.AddTool(rebalancerTool)
+	// Not conversationExecutor: sale proceeds moved into savings are not new money in
+	srv.AddTool(newExecuteRebalanceTool(liminalExecutor))
+	srv.AddTool(newGlidePathTool())
+	srv.AddTool(newAssetLocationTool())
+	srv.AddTool(newConcentrationTool())
+	srv.AddTool(newFundOverlapTool())
+	srv.AddTool(newCryptoGuardrailTool())
+	srv.AddTool(newDividendIncomeTool())
+	srv.AddTool(newAddHoldingTool())
+	srv.AddTool(newRemoveHoldingTool())
+	srv.AddTool(newListHoldingsTool())
+	srv.AddTool(newPerformanceTool())
+	srv.AddTool(newRecordContributionTool())
 
 	// Tool 11: Savings Booster (finds micro-investment opportunities)
 	savingsBoosterTool := tools.New("identify_savings_boosters").
@@ -598,81 +835,102 @@ When users mention investing money, always use the appropriate tools to understa
 		Schema(tools.ObjectSchema(map[string]interface{}{
 			"monthly_budget":      tools.StringProperty("Monthly budget/income"),
 			"discretionary_spend": tools.StringProperty("Monthly discretionary spending (eating out, entertainment, etc)"),
+			"expected_return":     tools.StringProperty(fmt.Sprintf("Optional assumed annual return percentage (defaults to the server's assumption, currently %g)", appConfig.Assumptions.EquityReturnPct)),
 		}, "monthly_budget")).
-		HandlerFunc(func(ctx context.Context, input json.RawMessage) (interface{}, error) {
-			var params struct {
-				MonthlyBudget      string `json:"monthly_budget"`
-				DiscretionarySpend string `json:"discretionary_spend"`
-			}
-			if err := json.Unmarshal(input, &params); err != nil {
-				return nil, fmt.Errorf("invalid input: %w", err)
-			}
-
-			budget := parseCachedFloat(params.MonthlyBudget)
-			discretionary := parseCachedFloat(params.DiscretionarySpend)
-
-			// Calculate opportunity
-			microInvestment := discretionary * 0.10 // 10% of discretionary spending
-			annualBoost := microInvestment * 12 * 1.07
-
-			return map[string]interface{}{
-				"monthly_budget":          fmt.Sprintf("$%.2f", budget),
-				"monthly_discretionary":   fmt.Sprintf("$%.2f", discretionary),
-				"micro_investment_target": fmt.Sprintf("$%.2f/month", microInvestment),
-				"strategy":                "Cut discretionary by 10%, invest the saved amount",
-				"annual_savings":          fmt.Sprintf("$%.2f", microInvestment*12),
-				"annual_growth_at_7pct":   fmt.Sprintf("$%.2f", annualBoost),
-				"10year_projection":       fmt.Sprintf("$%.2f", microInvestment*12*10*1.07),
-				"recommendation":          "Set up automatic transfer from Liminal to investment account",
-				"booster_power":           "Small daily cuts = huge long-term gains!",
-			}, nil
-		}).
+		HandlerFunc(savingsBoosterHandler).
 		Build()
 
 	srv.AddTool(savingsBoosterTool)
+	srv.AddTool(newRoundUpTool(liminalExecutor))
+	srv.AddTool(newSubscriptionsTool(liminalExecutor))
+	srv.AddTool(newSpendingAnomaliesTool(liminalExecutor))
+	srv.AddTool(newDetectedIncomeTool(liminalExecutor))
 
 	// Tool 12: Dynamic Risk Assessment with Transaction Velocity
 	dynamicRiskTool := tools.New("dynamic_risk_assessment").
-		Description("Assess risk tolerance considering actual transaction patterns and income stability from Liminal data").
+		Description("Assess risk tolerance considering actual transaction patterns and income stability from Liminal data. Without income_stability, the stability of the income detected in the user's transactions is used").
 		Schema(tools.ObjectSchema(map[string]interface{}{
-			"income_stability":      tools.StringProperty("Income stability: 'unstable', 'moderate', 'stable'"),
+			"income_stability":      tools.StringProperty("Optional income stability: 'unstable', 'moderate', 'stable' (detected from transaction history when omitted)"),
 			"transaction_frequency": tools.StringProperty("Transaction frequency: 'low', 'medium', 'high'"),
 			"savings_consistency":   tools.StringProperty("How consistent are savings: 'inconsistent', 'moderate', 'excellent'"),
 			"months_emergency_fund": tools.NumberProperty("Months of expenses in emergency fund"),
-		}, "income_stability")).
-		HandlerFunc(func(ctx context.Context, input json.RawMessage) (interface{}, error) {
+		})).
+		Handler(func(ctx context.Context, toolParams *core.ToolParams) (*core.ToolResult, error) {
 			var params struct {
 				IncomeStability      string  `json:"income_stability"`
 				TransactionFrequency string  `json:"transaction_frequency"`
 				SavingsConsistency   string  `json:"savings_consistency"`
 				MonthsEmergencyFund  float64 `json:"months_emergency_fund"`
 			}
-			if err := json.Unmarshal(input, &params); err != nil {
-				return nil, fmt.Errorf("invalid input: %w", err)
+			if len(toolParams.Input) > 0 {
+				if err := json.Unmarshal(toolParams.Input, &params); err != nil {
+					return &core.ToolResult{Success: false, Error: fmt.Sprintf("invalid input: %v", err)}, nil
+				}
+			}
+
+			stabilitySource := incomeSourceUserGiven
+			var detected *IncomeDetection
+			if strings.TrimSpace(params.IncomeStability) == "" {
+				d, ok := loadDetectedIncome(ctx, liminalExecutor, toolParams.UserID)
+				if !ok {
+					return &core.ToolResult{Success: false, Error: "income_stability is required: " + d.Message}, nil
+				}
+				params.IncomeStability, stabilitySource, detected = d.Stability, incomeSourceDetected, &d
 			}
 
 			// Calculate dynamic risk score from real behavior
 			riskScore := calculateDynamicRiskScore(params.IncomeStability, params.TransactionFrequency,
 				params.SavingsConsistency, int(params.MonthsEmergencyFund))
+			riskLevel := getRiskLevelFromScore(riskScore)
 
-			return map[string]interface{}{
+			result := map[string]interface{}{
 				"income_stability":      params.IncomeStability,
+				"income_source":         stabilitySource,
 				"transaction_pattern":   params.TransactionFrequency,
 				"savings_consistency":   params.SavingsConsistency,
 				"emergency_fund_months": fmt.Sprintf("%.1f months", params.MonthsEmergencyFund),
 				"calculated_risk_score": riskScore,
-				"recommended_profile":   getRiskLevelFromScore(riskScore),
+				"recommended_profile":   riskLevel,
+				"allocation_suggestion": getRiskAllocation(riskLevel),
+				"allocation_targets":    riskAllocationModel[riskLevel],
 				"action_plan": []string{
 					"Emergency fund is adequate",
 					"Proceed with recommended allocation",
 					"Review quarterly based on transaction patterns",
 				},
-			}, nil
+			}
+			if detected != nil {
+				result["detected_income"] = detected
+			}
+			return &core.ToolResult{Success: true, Data: result}, nil
 		}).
 		Build()
 
 	srv.AddTool(dynamicRiskTool)
 
+	// Execute automated plans on schedule through Liminal
+	if appConfig.SchedulerEnabled {
+		scheduler = newPlanScheduler(liminalExecutor)
+		scheduler.start(context.Background())
+		log.Println("⏰ Automated plan scheduler running (daily)")
+	} else {
+		log.Println("⏸️  Automated plan scheduler disabled (PLAN_SCHEDULER_ENABLED=false)")
+	}
+
+	// Record portfolio values daily for get_portfolio_performance
+	if appConfig.SnapshotsEnabled {
+		(&snapshotJob{quotes: quotes}).start(context.Background())
+		log.Println("📸 Daily portfolio snapshots running")
+	} else {
+		log.Println("⏸️  Portfolio snapshots disabled (PORTFOLIO_SNAPSHOTS_ENABLED=false)")
+	}
+
+	// Pick up edits to CONCEPTS_PATH without a restart
+	concepts.reloadOnSIGHUP()
+
+	// Support staff admin API (separate port, never exposed as a tool)
+	startAdminServer(appConfig.AdminAddr, appConfig.AdminToken)
+
 	// Run the server
 	port := ":8080"
 	log.Printf("🚀 InvestMate Server starting on %s\n", port)
@@ -680,23 +938,424 @@ When users mention investing money, always use the appropriate tools to understa
 	log.Printf("💡 Try asking: 'Help me start investing' or 'What's my investment profile?'\n")
 	log.Printf("⚡ Performance: All calculations optimized to sub-millisecond response times\n")
 
-	if err := srv.Run(port); err != nil {
+	// Periodically report parse cache behaviour so the size cap can be verified
+	go func() {
+		for range time.Tick(15 * time.Minute) {
+			s := parseCache.stats()
+			log.Printf("📊 Parse cache: %d/%d entries, %.1f%% hit rate (%d hits, %d misses)\n",
+				s.Size, s.Capacity, s.HitRate*100, s.Hits, s.Misses)
+		}
+	}()
+
+	// Close the store on SIGINT/SIGTERM so SQLite is flushed before the process exits
+	go func() {
+		stop := make(chan os.Signal, 1)
+		signal.Notify(stop, os.Interrupt, syscall.SIGTERM)
+		sig := <-stop
+		log.Printf("🛑 %v received, shutting down\n", sig)
+		closeStore()
+		os.Exit(0)
+	}()
+
+	err = srv.Run(port)
+	closeStore()
+	if err != nil {
 		log.Fatal(err)
 	}
 }
 
+// smartSavingsHandler handles calculate_smart_savings_rate, detecting income through liminalExecutor
+// when none is given
+func smartSavingsHandler(liminalExecutor core.ToolExecutor) func(context.Context, *core.ToolParams) (*core.ToolResult, error) {
+	return func(ctx context.Context, toolParams *core.ToolParams) (*core.ToolResult, error) {
+		var params struct {
+			MonthlyIncome     string `json:"monthly_income"`
+			CurrentSavings    string `json:"current_savings"`
+			EmergencyFundGoal string `json:"emergency_fund_goal"`
+		}
+		if len(toolParams.Input) > 0 {
+			if err := json.Unmarshal(toolParams.Input, &params); err != nil {
+				return &core.ToolResult{Success: false, Error: fmt.Sprintf("invalid input: %v", err)}, nil
+			}
+		}
+
+		var v amountValidator
+		income := 0.0
+		if strings.TrimSpace(params.MonthlyIncome) != "" {
+			income = v.positive("monthly_income", params.MonthlyIncome)
+		}
+		savings := v.nonNegative("current_savings", params.CurrentSavings, false)
+		emergency := v.nonNegative("emergency_fund_goal", params.EmergencyFundGoal, false)
+		if err := v.err(); err != nil {
+			return &core.ToolResult{Success: false, Error: err.Error()}, nil
+		}
+
+		incomeSource := incomeSourceUserGiven
+		if income == 0 {
+			detected, ok := loadDetectedIncome(ctx, liminalExecutor, toolParams.UserID)
+			if !ok {
+				return &core.ToolResult{Success: false, Error: "monthly_income is required: " + detected.Message}, nil
+			}
+			income, incomeSource = detected.MonthlyIncomeUSD, incomeSourceDetected
+		}
+
+		// Calculate optimal savings: 20% income, prioritize emergency fund
+		recommendedMonthly := income * 0.20
+
+		// No stated goal: default to 6 months of estimated expenses (income not being saved)
+		goalSource := "user_provided"
+		if params.EmergencyFundGoal == "" {
+			emergency = (income - recommendedMonthly) * defaultEmergencyMonths
+			goalSource = fmt.Sprintf("default_%d_months_expenses", defaultEmergencyMonths)
+		}
+
+		// Spread the gap over 2 years, never taking more than the whole savings budget
+		prioritySavings := math.Max(emergency-savings, 0)
+		emergencyMonthly := math.Min(prioritySavings/24, recommendedMonthly)
+		investmentBudget := recommendedMonthly - emergencyMonthly
+
+		// The emergency fund sits in the vault, so it earns the vault APY while it builds
+		vaultRates.refresh(ctx, toolParams.UserID)
+		apy, live := vaultRates.current()
+
+		status := "fully_funded"
+		timeToGoal := "Emergency fund target already met - the full savings budget goes to investing"
+		monthsToGoal := 0.0
+		if prioritySavings > 0 {
+			status = "building"
+			monthsToGoal = float64(monthsToReach(emergency, savings, emergencyMonthly, apy))
+			timeToGoal = fmt.Sprintf("%.0f months to emergency fund target, earning %.2f%% APY (%s rate)", monthsToGoal, apy, rateSource(live))
+			if monthsToGoal < 0 {
+				status = "unreachable"
+				timeToGoal = fmt.Sprintf("At %s/month the emergency fund target isn't reached within %d years - raise the monthly savings or lower the target",
+					formatMoney(emergencyMonthly), maxProjectionMonths/12)
+			}
+		}
+
+		savingsRate := (recommendedMonthly / income) * 100
+		return &core.ToolResult{Success: true, Data: SmartSavingsResult{
+			Currency:                     activeCurrency().Code,
+			MonthlyIncome:                formatMoney(income),
+			MonthlyIncomeUSD:             income,
+			CurrentEmergencyFund:         formatMoney(savings),
+			CurrentEmergencyFundUSD:      savings,
+			EmergencyFundTarget:          formatMoney(emergency),
+			EmergencyFundTargetUSD:       emergency,
+			EmergencyFundTargetBasis:     goalSource,
+			EmergencyFundStatus:          status,
+			RecommendedMonthlySavings:    formatMoney(recommendedMonthly),
+			RecommendedMonthlySavingsUSD: recommendedMonthly,
+			PriorityEmergencyFund:        fmt.Sprintf("%s/month", formatMoney(emergencyMonthly)),
+			PriorityEmergencyFundUSD:     emergencyMonthly,
+			InvestmentBudget:             fmt.Sprintf("%s/month", formatMoney(investmentBudget)),
+			InvestmentBudgetUSD:          investmentBudget,
+			SavingsRate:                  fmt.Sprintf("%.1f%% of income", savingsRate),
+			SavingsRatePercent:           savingsRate,
+			TimeToGoal:                   timeToGoal,
+			MonthsToGoal:                 monthsToGoal,
+			SavingsAPY:                   apy,
+			SavingsRateSource:            rateSource(live),
+			IncomeSource:                 incomeSource,
+		}}, nil
+	}
+}
+
+// rebalancerHandler handles rebalance_investment_portfolio
+func rebalancerHandler(ctx context.Context, toolParams *core.ToolParams) (*core.ToolResult, error) {
+	var params struct {
+		CurrentStocksValue        string `json:"current_stocks_value"`
+		CurrentInternationalValue string `json:"current_international_value"`
+		CurrentREITValue          string `json:"current_reit_value"`
+		CurrentBondsValue         string `json:"current_bonds_value"`
+		CurrentCashValue          string `json:"current_cash_value"`
+		TargetRiskLevel           string `json:"target_risk_level"`
+		TargetDate                string `json:"target_date"`
+		BandPercent               string `json:"band_percent"`
+		MinTradeAmount            string `json:"min_trade_amount"`
+		MonthlyContribution       string `json:"monthly_contribution"`
+		Mode                      string `json:"mode"`
+		HoldingAccounts           []struct {
+			Asset       string `json:"asset"`
+			AccountType string `json:"account_type"`
+			Value       string `json:"value"`
+			CostBasis   string `json:"cost_basis"`
+		} `json:"holding_accounts"`
+	}
+	if err := json.Unmarshal(toolParams.Input, &params); err != nil {
+		return &core.ToolResult{Success: false, Error: fmt.Sprintf("invalid input: %v", err)}, nil
+	}
+
+	// Without amounts, fall back to the holdings the user has recorded
+	holdingsSource := "input"
+	var recorded []storage.Holding
+	if strings.TrimSpace(params.CurrentStocksValue+params.CurrentBondsValue+params.CurrentCashValue) == "" {
+		holdings, err := loadHoldings(ctx, toolParams.UserID)
+		if err != nil {
+			return &core.ToolResult{Success: false, Error: fmt.Sprintf("could not load holdings: %v", err)}, nil
+		}
+		recorded = holdings
+	}
+
+	var v amountValidator
+	var current map[string]float64
+	if len(recorded) > 0 {
+		holdingsSource, current = "holdings", holdingTotals(recorded)
+	} else {
+		current = map[string]float64{
+			"stocks": v.nonNegative("current_stocks_value", params.CurrentStocksValue, true),
+			"bonds":  v.nonNegative("current_bonds_value", params.CurrentBondsValue, true),
+			"cash":   v.nonNegative("current_cash_value", params.CurrentCashValue, true),
+		}
+		for asset, raw := range map[string]string{"international": params.CurrentInternationalValue, "reit": params.CurrentREITValue} {
+			if strings.TrimSpace(raw) != "" {
+				current[asset] = v.nonNegative("current_"+asset+"_value", raw, true)
+			}
+		}
+	}
+	// Classes the user didn't report separately are assumed to sit inside stocks
+	var folded []string
+	for _, asset := range []string{"international", "reit"} {
+		if _, ok := current[asset]; !ok {
+			folded = append(folded, asset)
+		}
+	}
+	total := 0.0
+	for _, value := range current {
+		total += value
+	}
+	if len(v.errs) == 0 && total <= 0 {
+		v.fail("portfolio_total", "the sum of all holdings must be greater than zero")
+	}
+	band := optionalPercent(&v, "band_percent", params.BandPercent, appConfig.RebalanceBandPct, 50)
+	minTrade := appConfig.MinRebalanceMove
+	if strings.TrimSpace(params.MinTradeAmount) != "" {
+		minTrade = v.nonNegative("min_trade_amount", params.MinTradeAmount, true)
+	}
+	contribution := v.nonNegative("monthly_contribution", params.MonthlyContribution, false)
+	mode := "sell_and_buy"
+	if contribution > 0 {
+		mode = "contributions_only"
+	}
+	if strings.TrimSpace(params.Mode) != "" {
+		mode = v.oneOf("mode", params.Mode, []string{"sell_and_buy", "contributions_only"})
+	}
+	if len(v.errs) == 0 && mode == "contributions_only" && contribution <= 0 {
+		v.fail("monthly_contribution", "contributions_only mode needs a monthly contribution above zero")
+	}
+	holdings := make([]accountHolding, 0, len(params.HoldingAccounts))
+	assigned := map[string]float64{}
+	for i, raw := range params.HoldingAccounts {
+		field := fmt.Sprintf("holding_accounts[%d]", i)
+		h := accountHolding{
+			Asset:       v.oneOf(field+".asset", raw.Asset, rebalanceAssets),
+			AccountType: v.oneOf(field+".account_type", raw.AccountType, rebalanceAccountTypes),
+			HasBasis:    strings.TrimSpace(raw.CostBasis) != "",
+		}
+		held, ok := current[h.Asset]
+		if !ok {
+			if slices.Contains(rebalanceAssets, h.Asset) {
+				v.fail(field+".asset", "no current_%s_value was given", h.Asset)
+			}
+			continue
+		}
+		h.Value = held
+		if strings.TrimSpace(raw.Value) != "" {
+			h.Value = v.nonNegative(field+".value", raw.Value, true)
+		}
+		if h.HasBasis {
+			h.CostBasis = v.nonNegative(field+".cost_basis", raw.CostBasis, true)
+		}
+		assigned[h.Asset] += h.Value
+		if assigned[h.Asset] > held+0.005 {
+			v.fail(field+".value", "accounts for more %s than the %s held", h.Asset, v.money(held))
+		}
+		holdings = append(holdings, h)
+	}
+	if err := v.err(); err != nil {
+		return &core.ToolResult{Success: false, Error: err.Error()}, nil
+	}
+
+	// Target allocation: today's point on the glide path, or the risk level's fixed split
+	var riskLevel RiskLevel
+	var targets map[string]allocationBand
+	source, glideYears := "risk_level", 0
+	if strings.TrimSpace(params.TargetDate) != "" {
+		years, usedFallback := parseTimeHorizon(params.TargetDate, time.Now())
+		if usedFallback {
+			return &core.ToolResult{Success: false, Error: fmt.Sprintf("invalid input: target_date: %q is not a future year or a number of years", params.TargetDate)}, nil
+		}
+		targets = uniformBands(splitEquity(glideTargets(float64(years))), band)
+		source, glideYears = "glide_path", years
+	} else {
+		var err error
+		riskLevel, err = normalizeRiskLevel(params.TargetRiskLevel)
+		if err != nil {
+			return &core.ToolResult{Success: false, Error: fmt.Sprintf("invalid target_risk_level: %v", err)}, nil
+		}
+		targets = riskAllocationModel[riskLevel]
+	}
+	allocationNote := ""
+	if len(folded) > 0 {
+		targets = foldIntoStocks(targets, folded...)
+		allocationNote = fmt.Sprintf("No separate %s holdings given, so their targets are folded into stocks; pass current_international_value and current_reit_value to rebalance all five classes",
+			strings.Join(folded, " or "))
+		if holdingsSource == "holdings" {
+			allocationNote = fmt.Sprintf("No %s holdings are recorded, so their targets are folded into stocks", strings.Join(folded, " or "))
+		}
+	}
+	// Every class may drift band points either way before a rebalance is suggested
+	targets = overrideTolerance(targets, band)
+	// Drift versus target; only flag rebalancing when a class leaves its band
+	drift, maxDrift, needed := calculateAllocationDrift(current, targets, total)
+
+	currentAlloc := make(map[string]string, len(current))
+	currentPct := make(map[string]float64, len(current))
+	for asset, value := range current {
+		currentAlloc[asset] = fmt.Sprintf("%.1f%%", (value/total)*100)
+		currentPct[asset] = (value / total) * 100
+	}
+
+	result := RebalanceResult{
+		Currency:                 activeCurrency().Code,
+		Mode:                     mode,
+		HoldingsSource:           holdingsSource,
+		CurrentAllocation:        currentAlloc,
+		CurrentAllocationPercent: currentPct,
+		TargetRiskLevel:          riskLevel,
+		TargetSource:             source,
+		GlideYearsLeft:           glideYears,
+		TargetAllocation:         formatAllocation(targets),
+		TargetBands:              targets,
+		AllocationNote:           allocationNote,
+		TotalValue:               formatMoney(total),
+		TotalValueUSD:            total,
+		Drift:                    drift,
+		MaxDriftPercent:          maxDrift,
+		DriftBandPercent:         band,
+		RebalancingNeeded:        needed,
+		MinTradeUSD:              minTrade,
+	}
+	if mode == "contributions_only" {
+		splits, months := contributionRebalance(current, targets, total, contribution)
+		result.Moves = []RebalanceMove{}
+		result.MonthlyContributionUSD = contribution
+		result.ContributionSplits = splits
+		result.MonthsToBand = months
+		result.ActionItems = contributionActionItems(splits, months, maxDrift)
+	} else {
+		result.Moves, result.DeferredMoves = deferSmallMoves(rebalanceMoves(drift, needed), minTrade)
+		result.ActionItems = driftActionItems(drift, result.Moves, result.DeferredMoves, maxDrift)
+		if len(holdings) > 0 && len(result.Moves) > 0 {
+			result.TaxFreeMoves, result.TaxableMoves = splitMovesByTax(result.Moves, holdings, current)
+			months := 0
+			if contribution > 0 {
+				_, months = contributionRebalance(current, targets, total, contribution)
+			}
+			result.ActionItems = append(result.ActionItems, taxActionItems(result.TaxFreeMoves, result.TaxableMoves, contribution, months)...)
+		}
+	}
+	return &core.ToolResult{Success: true, Data: result}, nil
+}
+
+// savingsBoosterHandler handles identify_savings_boosters
+func savingsBoosterHandler(ctx context.Context, input json.RawMessage) (interface{}, error) {
+	var params struct {
+		MonthlyBudget      string `json:"monthly_budget"`
+		DiscretionarySpend string `json:"discretionary_spend"`
+		ExpectedReturn     string `json:"expected_return"`
+	}
+	if err := json.Unmarshal(input, &params); err != nil {
+		return nil, fmt.Errorf("invalid input: %w", err)
+	}
+
+	var v amountValidator
+	budget := v.positive("monthly_budget", params.MonthlyBudget)
+	discretionary := v.nonNegative("discretionary_spend", params.DiscretionarySpend, false)
+	returnRate := v.returnRate("expected_return", params.ExpectedReturn, false)
+	if err := v.err(); err != nil {
+		return nil, err
+	}
+
+	// Calculate opportunity: invest the cut monthly and let it compound
+	microInvestment := discretionary * 0.10 // 10% of discretionary spending
+	oneYear := calculateCompoundGrowth(0, microInvestment, returnRate, 1)
+	tenYear := calculateCompoundGrowth(0, microInvestment, returnRate, 10)
+
+	return SavingsBoosterResult{
+		Currency:                 activeCurrency().Code,
+		MonthlyBudget:            formatMoney(budget),
+		MonthlyBudgetUSD:         budget,
+		MonthlyDiscretionary:     formatMoney(discretionary),
+		MonthlyDiscretionaryUSD:  discretionary,
+		MicroInvestmentTarget:    fmt.Sprintf("%s/month", formatMoney(microInvestment)),
+		MicroInvestmentTargetUSD: microInvestment,
+		Strategy:                 "Cut discretionary by 10%, invest the saved amount",
+		AnnualSavings:            formatMoney(microInvestment * 12),
+		AnnualSavingsUSD:         microInvestment * 12,
+		AssumedReturnPercent:     returnRate,
+		AnnualProjection:         oneYear.ProjectedTotal,
+		AnnualProjectionUSD:      oneYear.ProjectedTotalUSD,
+		AnnualGrowthAt7Pct:       oneYear.ProjectedTotal,
+		AnnualGrowthUSD:          oneYear.ProjectedTotalUSD,
+		AnnualEarningsUSD:        oneYear.ProjectedEarningsUSD,
+		TenYearProjection:        tenYear.ProjectedTotal,
+		TenYearProjectionUSD:     tenYear.ProjectedTotalUSD,
+		TenYearContributedUSD:    tenYear.TotalContributed,
+		TenYearGrowthUSD:         tenYear.ProjectedEarningsUSD,
+		Recommendation:           "Set up automatic transfer from Liminal to investment account",
+		BoosterPower:             "Small daily cuts = huge long-term gains!",
+		BaselineComparison:       tenYear.BaselineComparison,
+	}, nil
+}
+
+// closeStore closes the user state store. main leaves through log.Fatal or os.Exit,
+// neither of which runs deferred calls, so every exit path calls this explicitly.
+func closeStore() {
+	if err := store.Close(); err != nil {
+		log.Printf("⚠️ failed to close data store: %v\n", err)
+	}
+}
+
 // ============================================
 // OPTIMIZED HELPER FUNCTIONS
 // ============================================
 
-// parseCachedFloat uses sync.Map for O(1) cache lookups on repeated values
-func parseCachedFloat(s string) float64 {
-	if cached, ok := parseCache.Load(s); ok {
-		return cached.(float64)
+// maxParseCacheKeyLen keeps arbitrarily long model output out of the parse cache
+const maxParseCacheKeyLen = 64
+
+// parseCachedAmount uses a bounded LRU for O(1) cache lookups on repeated values.
+// Accepts the same "$1,500" / "10k" forms as parseAmount.
+func parseCachedAmount(s string) (float64, error) {
+	return parseCached(s, s, parseAmount)
+}
+
+// parseCachedRate is parseCachedAmount for rates, percentages and years, read as parseRate reads them.
+// Its cache keys are prefixed so "1.500" the rate and "1.500" the amount stay apart.
+func parseCachedRate(s string) (float64, error) {
+	return parseCached("%"+s, s, parseRate)
+}
+
+func parseCached(key, s string, parse func(string) (float64, error)) (float64, error) {
+	if len(key) > maxParseCacheKeyLen {
+		return parse(s)
+	}
+	if cached, ok := parseCache.get(key); ok {
+		if !cached.valid {
+			return 0, fmt.Errorf("%q is not a number", s)
+		}
+		return cached.value, nil
+	}
+	v, err := parse(s)
+	parseCache.put(key, parsedAmount{value: v, valid: err == nil})
+	return v, err
+}
+
+// readOnlyResult is returned by write tools when support has frozen the account
+func readOnlyResult() *core.ToolResult {
+	return &core.ToolResult{
+		Success: false,
+		Error:   "This account is temporarily in read-only mode. Please contact support to make changes.",
 	}
-	v, _ := strconv.ParseFloat(s, 64)
-	parseCache.Store(s, v)
-	return v
 }
 
 func calculateRecommendedSavings(portfolio InvestmentPortfolio) float64 {
@@ -706,162 +1365,346 @@ func calculateRecommendedSavings(portfolio InvestmentPortfolio) float64 {
 // OPTIMIZED: Uses closed-form geometric series instead of loop
 // Formula: FV = P(1+r)^n + PMT * [((1+r)^n - 1) / r]
 // This is O(1) instead of O(n) in original loop implementation
-func calculateCompoundGrowth(initial, monthly, returnRate float64, years int) map[string]interface{} {
+// Fractional years are rounded to the nearest whole month.
+func calculateCompoundGrowth(initial, monthly, returnRate, years float64) ProjectionResult {
+	return calculateCompoundGrowthMonths(initial, monthly, returnRate, int(math.Round(years*12)))
+}
+
+// calculateCompoundGrowthMonths is calculateCompoundGrowth for horizons not measured in whole years
+func calculateCompoundGrowthMonths(initial, monthly, returnRate float64, totalMonths int) ProjectionResult {
+	return calculateEscalatingGrowthMonths(initial, monthly, returnRate, 0, totalMonths)
+}
+
+// calculateEscalatingGrowthMonths projects contributions that start at monthly and rise by
+// increasePct every 12 months; an increasePct of 0 is the flat calculateCompoundGrowthMonths
+func calculateEscalatingGrowthMonths(initial, monthly, returnRate, increasePct float64, totalMonths int) ProjectionResult {
+	months := float64(totalMonths)
+	years := months / 12
+
+	total := escalatingFutureValue(initial, monthly, returnRate, increasePct, months)
+	totalContributed := initial + escalatingFutureValue(0, monthly, 0, increasePct, months)
+	earnings := total - totalContributed
+
+	// Gains are shown as a share of the final total; losses as a share of what was put in
+	outcome := "gain"
+	earningsLabel := formatMoney(earnings)
+	earningsPercent := 0.0
+	var compounding string
+	switch {
+	case earnings < 0:
+		outcome = "loss"
+		earningsLabel = fmt.Sprintf("-%s (loss)", formatMoney(-earnings))
+		if totalContributed > 0 {
+			earningsPercent = (earnings / totalContributed) * 100.0
+		}
+		compounding = fmt.Sprintf("Loss of %.1f%% of the amount contributed", -earningsPercent)
+	default:
+		if earnings == 0 {
+			outcome = "break_even"
+		}
+		if total > 0 {
+			earningsPercent = (earnings / total) * 100.0
+		}
+		compounding = fmt.Sprintf("%.1f%% of total is earnings", earningsPercent)
+	}
+
+	p := ProjectionResult{
+		Currency:             activeCurrency().Code,
+		InitialInvestment:    initial,
+		MonthlyContribution:  monthly,
+		TotalContributed:     totalContributed,
+		ProjectedEarnings:    earningsLabel,
+		ProjectedEarningsUSD: earnings,
+		ProjectedTotal:       formatMoney(total),
+		ProjectedTotalUSD:    total,
+		Years:                years,
+		Months:               totalMonths,
+		AnnualReturnRate:     fmt.Sprintf("%.1f%%", returnRate),
+		AnnualReturnPercent:  returnRate,
+		PowerOfCompounding:   compounding,
+		EarningsPercent:      earningsPercent,
+		Outcome:              outcome,
+		Warning:              returnRateWarning(returnRate),
+		BaselineComparison:   vaultBaseline(initial, monthly, increasePct, months, total),
+	}
+	if increasePct != 0 {
+		p.AnnualIncreasePercent = increasePct
+		p.FinalYearMonthlyUSD = finalYearMonthly(monthly, increasePct, totalMonths)
+		p.EscalationContributedUSD = totalContributed - initial - monthly*months
+		p.EscalationNote = fmt.Sprintf("Raising %s/month by %g%% a year reaches %s/month in the final year and adds %s of contributions over a flat plan; market growth adds %s on top.",
+			formatMoney(monthly), increasePct, formatMoney(p.FinalYearMonthlyUSD), formatMoney(p.EscalationContributedUSD), earningsLabel)
+	}
+	return p
+}
+
+// Projection horizon limits for calculate_investment_projection
+const (
+	minProjectionYears = 1.0
+	maxProjectionYears = 60.0
+)
+
+// maxAnnualIncreasePct caps how fast projected contributions may rise each year
+const maxAnnualIncreasePct = 25.0
+
+// Annual inflation bounds for today's-dollar figures
+const (
+	minInflationRate = -5.0
+	maxInflationRate = 25.0
+)
+
+// Annual return bounds for projections: outside the hard range is rejected,
+// outside the typical range gets a warning attached to the result
+const (
+	minReturnRate        = -50.0
+	maxReturnRate        = 50.0
+	typicalReturnRateLow = 0.0
+	typicalReturnRateMax = 12.0
+)
+
+// returnRateWarning flags rates outside typical long-run market assumptions
+func returnRateWarning(returnRate float64) string {
+	switch {
+	case returnRate < typicalReturnRateLow:
+		return fmt.Sprintf("%.1f%% assumes the investment loses value every year; long-run market returns have typically been %.0f-%.0f%%", returnRate, typicalReturnRateLow, typicalReturnRateMax)
+	case returnRate > typicalReturnRateMax:
+		return fmt.Sprintf("%.1f%% is above typical long-run market returns of %.0f-%.0f%%; treat this projection as optimistic", returnRate, typicalReturnRateLow, typicalReturnRateMax)
+	}
+	return ""
+}
+
+// futureValue is the closed-form FV with monthly compounding and end-of-month contributions
+func futureValue(initial, monthly, returnRate, months float64) float64 {
 	monthlyRate := returnRate / 100.0 / 12.0
-	months := float64(years * 12)
 
-	// Optimized: Direct mathematical formula instead of loop
 	// FV of initial investment
 	fvInitial := initial * math.Pow(1.0+monthlyRate, months)
 
 	// FV of annuity (monthly contributions)
 	// Using geometric series formula: annuity = PMT * [((1+r)^n - 1) / r]
 	var fvAnnuity float64
-	if monthlyRate < 1e-12 { // Handle zero rate case (avoid division by zero)
+	if math.Abs(monthlyRate) < 1e-12 { // Handle zero rate case (avoid division by zero)
 		fvAnnuity = monthly * months
 	} else {
 		fvAnnuity = monthly * ((math.Pow(1.0+monthlyRate, months) - 1.0) / monthlyRate)
 	}
 
-	total := fvInitial + fvAnnuity
-	totalContributed := initial + (monthly * months)
-	earnings := total - totalContributed
-	earningsPercent := 0.0
-	if total > 0 {
-		earningsPercent = (earnings / total) * 100.0
-	}
+	return fvInitial + fvAnnuity
+}
 
-	return map[string]interface{}{
-		"initial_investment":   initial,
-		"monthly_contribution": monthly,
-		"total_contributed":    totalContributed,
-		"projected_earnings":   fmt.Sprintf("$%.2f", earnings),
-		"projected_total":      fmt.Sprintf("$%.2f", total),
-		"years":                years,
-		"annual_return_rate":   fmt.Sprintf("%.1f%%", returnRate),
-		"power_of_compounding": fmt.Sprintf("%.1f%% of total is earnings", earningsPercent),
+// escalatingFutureValue is futureValue with the monthly contribution raised by increasePct every 12 months.
+// Each year's contributions are a level annuity, compounded for the months after that year ends.
+func escalatingFutureValue(initial, monthly, returnRate, increasePct, months float64) float64 {
+	if increasePct == 0 {
+		return futureValue(initial, monthly, returnRate, months)
 	}
+	total := futureValue(initial, 0, returnRate, months)
+	for start := 0.0; start < months; start += 12 {
+		n := min(12, months-start)
+		total += futureValue(futureValue(0, monthly, returnRate, n), 0, returnRate, months-start-n)
+		monthly *= 1 + increasePct/100
+	}
+	return total
+}
+
+// finalYearMonthly is the monthly contribution in the last (possibly partial) year of an escalating plan
+func finalYearMonthly(monthly, increasePct float64, months int) float64 {
+	raises := max((months-1)/12, 0)
+	return monthly * math.Pow(1+increasePct/100, float64(raises))
 }
 
-// OPTIMIZED: Direct lookup from pre-computed allocation table
-func generateInvestmentPlan(goal, timeHorizon string, currentAmount, monthlyCapacity float64) map[string]interface{} {
-	years := parseTimeHorizonFast(timeHorizon)
+// realValue discounts a nominal amount months from now into today's dollars, compounding inflation monthly
+func realValue(nominal, inflationRate, months float64) float64 {
+	return nominal / math.Pow(1.0+inflationRate/100.0/12.0, months)
+}
 
-	// O(1) lookup instead of if/else chain
-	stocks, bonds, cash := 0.3, 0.5, 0.2 // default for <= 5 years
-	for _, alloc := range allocationByYears {
-		if years <= alloc.years {
-			stocks = alloc.stocks
-			bonds = alloc.bonds
-			cash = alloc.cash
-			break
+// realContributions is what the initial amount plus each month's contribution is worth in today's
+// dollars, discounting every contribution by the months of inflation before it is made. The monthly
+// contribution rises by increasePct every 12 months.
+func realContributions(initial, monthly, increasePct, inflationRate float64, months int) float64 {
+	total := initial
+	for m := 1; m <= months; m++ {
+		total += realValue(monthly, inflationRate, float64(m))
+		if m%12 == 0 {
+			monthly *= 1 + increasePct/100
 		}
 	}
+	return total
+}
 
-	return map[string]interface{}{
-		"goal":           goal,
-		"time_horizon":   timeHorizon,
-		"current_amount": currentAmount,
-		"recommended_allocation": map[string]interface{}{
-			"stocks": fmt.Sprintf("%.0f%%", stocks*100),
-			"bonds":  fmt.Sprintf("%.0f%%", bonds*100),
-			"cash":   fmt.Sprintf("%.0f%%", cash*100),
-		},
-		"annual_contribution":   monthlyCapacity * 12,
-		"monthly_investment":    monthlyCapacity,
-		"estimated_growth_rate": "6-8% annually",
-		"key_strategies":        []string{"Dollar-cost averaging", "Automatic rebalancing", "Tax-efficient investing"},
-		"next_steps":            "Review fund options, set up automatic transfers, monitor quarterly",
-	}
+// withInflation adds the today's-dollar view of a projection at inflationRate
+func (p ProjectionResult) withInflation(inflationRate float64) ProjectionResult {
+	months := float64(p.Months)
+	p.InflationRatePercent = inflationRate
+	p.ProjectedTotalRealUSD = realValue(p.ProjectedTotalUSD, inflationRate, months)
+	p.ProjectedTotalReal = fmt.Sprintf("%s in today's dollars", formatMoney(p.ProjectedTotalRealUSD))
+	p.TotalContributedRealUSD = realContributions(p.InitialInvestment, p.MonthlyContribution, p.AnnualIncreasePercent, inflationRate, p.Months)
+	p.InflationNote = fmt.Sprintf("%s is what the account would show in %.1f years; at %.1f%% inflation it buys what %s buys today.",
+		p.ProjectedTotal, p.Years, inflationRate, formatMoney(p.ProjectedTotalRealUSD))
+	return p
 }
 
-// OPTIMIZED: Fast lookup table instead of string switch
-func parseTimeHorizonFast(horizon string) int {
-	if val, ok := timeHorizonTable[horizon]; ok {
-		return val
+// requiredMonthlyContribution inverts futureValue: the monthly amount that reaches target in months
+func requiredMonthlyContribution(target, initial, returnRate, months float64) float64 {
+	return requiredStartingContribution(target, initial, returnRate, 0, months)
+}
+
+// requiredStartingContribution inverts escalatingFutureValue: the first year's monthly amount that,
+// raised by increasePct a year, reaches target in months
+func requiredStartingContribution(target, initial, returnRate, increasePct, months float64) float64 {
+	if months <= 0 {
+		return math.Inf(1)
 	}
-	return 10 // default
+	shortfall := target - futureValue(initial, 0, returnRate, months)
+	if shortfall <= 0 {
+		return 0
+	}
+	// escalatingFutureValue of $1/month is the annuity factor
+	return shortfall / escalatingFutureValue(0, 1, returnRate, increasePct, months)
 }
 
-// OPTIMIZED: Direct array lookup for age-based scoring + map lookups for others
-func assessRiskProfile(age, yearsToRetirement int, downturnComfort, experience string) map[string]interface{} {
-	riskScore := 0
+// vaultBaseline grows the same cash flows at the vault APY using futureValue's timing
+func vaultBaseline(initial, monthly, increasePct, months, projectedTotal float64) BaselineComparison {
+	apy, live := vaultRates.current()
+	baseline := escalatingFutureValue(initial, monthly, apy, increasePct, months)
+	return baselineComparison(projectedTotal, baseline, apy, live)
+}
 
-	// Array lookup O(1) instead of if/else chain
-	if age < 120 {
-		riskScore += ageRiskScore[age]
-	} else {
-		riskScore += 30 // default for very old
+// OPTIMIZED: Direct lookup from pre-computed allocation matrix
+// Strategies and next steps are in locale.
+func generateInvestmentPlan(goal, timeHorizon string, risk RiskLevel, currentAmount, monthlyCapacity float64, locale string) map[string]interface{} {
+	years, usedFallback := parseTimeHorizon(timeHorizon, time.Now())
+
+	weights := planWeights(horizonAllocation(float64(years), risk))
+
+	plan := map[string]interface{}{
+		"goal":                           goal,
+		"currency":                       activeCurrency().Code,
+		"time_horizon":                   timeHorizon,
+		"horizon_years":                  years,
+		"risk_tolerance":                 risk,
+		"current_amount":                 currentAmount,
+		"horizon_fallback_used":          usedFallback,
+		"recommended_allocation":         formatAllocation(uniformBands(weights, 0)),
+		"recommended_allocation_percent": weights,
+		"annual_contribution":            monthlyCapacity * 12,
+		"monthly_investment":             monthlyCapacity,
+		"estimated_growth_rate":          localizef(locale, "about %.1f%% annually", expectedPortfolioReturn(weights)),
+		"key_strategies":                 localizeAll(locale, planStrategiesText),
+		"next_steps":                     localize(locale, "Review fund options, set up automatic transfers, monitor quarterly"),
 	}
-
-	// O(1) map lookups instead of switch statements
-	if comfort, ok := comfortRiskScore[downturnComfort]; ok {
-		riskScore += comfort
+	if note := planConflictNote(risk, years); note != "" {
+		plan["risk_horizon_note"] = note
 	}
-
-	if exp, ok := experienceRiskScore[experience]; ok {
-		riskScore += exp
+	if usedFallback {
+		plan["horizon_note"] = fmt.Sprintf("Couldn't read %q as a future time horizon, so a %d-year horizon was assumed - confirm with the user", timeHorizon, years)
 	}
+	return plan
+}
 
-	// Quick lookup for risk level
-	riskLevel := "Conservative"
-	if riskScore > 60 {
-		riskLevel = "Moderate-to-Aggressive"
-	} else if riskScore > 40 {
-		riskLevel = "Moderate"
+// planWeights is a plan allocation as percentages keyed like targetAllocationTable
+func planWeights(a planAllocation) map[string]float64 {
+	return map[string]float64{
+		"stocks": a.stocks * 100, "international": a.international * 100, "reit": a.reit * 100,
+		"bonds": a.bonds * 100, "cash": a.cash * 100,
 	}
+}
 
-	return map[string]interface{}{
-		"age":                    age,
-		"years_to_retirement":    yearsToRetirement,
-		"risk_score":             riskScore,
-		"recommended_risk_level": riskLevel,
-		"allocation_suggestion":  riskAllocationCache[riskLevel],
-		"best_fit_strategies":    strategiesCache[riskLevel],
+// Horizons at which risk tolerance and time pull sharply apart
+const (
+	shortPlanHorizonYears = 5
+	longPlanHorizonYears  = 15
+)
+
+// planConflictNote explains the compromise when risk tolerance and horizon pull in opposite directions
+func planConflictNote(risk RiskLevel, years int) string {
+	equity := planEquityShare(horizonAllocation(float64(years), risk)) * 100
+	switch {
+	case risk == RiskConservative && years > longPlanHorizonYears:
+		return fmt.Sprintf("A %d-year horizon could normally support mostly stocks, but the user is conservative, so this keeps equities to %.0f%% instead of %.0f%%. "+
+			"Expect lower long-run growth in exchange for smaller swings; it's worth asking whether the conservative answer reflects this goal or short-term nerves.",
+			years, equity, planEquityShare(horizonAllocation(float64(years), baselineHorizonRisk))*100)
+	case (risk == RiskAggressive || risk == RiskModerateToAggressive) && years <= shortPlanHorizonYears:
+		return fmt.Sprintf("The user is comfortable with risk, but money needed in %d years has little time to recover from a fall, so this holds equities to %.0f%%. "+
+			"Risk tolerance sets the ceiling; a short horizon lowers it.", years, equity)
 	}
+	return ""
 }
 
-// OPTIMIZED: Direct cache lookup instead of creating map every time
-func explainConcept(concept string) map[string]interface{} {
-	if explanation, exists := conceptCache[concept]; exists {
-		return explanation
+// planEquityShare is the stocks, international and REIT share of a plan allocation
+func planEquityShare(a planAllocation) float64 {
+	return a.stocks + a.international + a.reit
+}
+
+// assessRiskProfile scores the questionnaire and attaches the matching allocation and strategies, in locale
+func assessRiskProfile(answers riskAnswers, locale string) (map[string]interface{}, error) {
+	score, err := scoreRiskAnswers(answers)
+	if err != nil {
+		return nil, err
+	}
+	riskLevel := score.Level
+
+	// Each factor's points, so the assistant can explain the recommendation
+	breakdown := map[string]interface{}{
+		"total":      score.Total,
+		"thresholds": fmt.Sprintf("above %d = Moderate-to-Aggressive, above %d = Moderate, otherwise Conservative", riskScoreModerateToAggressive, riskScoreModerate),
+		"weighting":  riskWeighting(),
+	}
+	for factor, points := range score.Points {
+		breakdown[factor] = points
 	}
 
-	return map[string]interface{}{
-		"concept":     concept,
-		"explanation": "I don't have that concept in my database, but I'd be happy to explain it! Try asking about: ETF, dividend, diversification, compound_interest, or dollar_cost_averaging.",
+	profile := map[string]interface{}{
+		"age":                    answers.Age,
+		"age_band":               score.AgeBand.label,
+		"years_to_retirement":    answers.YearsToRetirement,
+		"horizon_band":           score.HorizonBand.label,
+		"risk_score":             score.Total,
+		"score_breakdown":        breakdown,
+		"recommended_risk_level": riskLevel,
+		"allocation_suggestion":  getRiskAllocation(riskLevel),
+		"allocation_targets":     riskAllocationModel[riskLevel],
+		"best_fit_strategies":    getStrategiesForRisk(riskLevel, locale),
 	}
+	if score.AgeBand.note != "" {
+		profile["age_note"] = score.AgeBand.note
+	}
+	return profile, nil
 }
 
-// OPTIMIZED: Direct cache reference instead of function call
-func getRiskAllocation(risk string) map[string]string {
-	return riskAllocationCache[risk]
+// getRiskAllocation is a risk level's target bands formatted for display, e.g. "50-60%"
+func getRiskAllocation(risk RiskLevel) map[string]string {
+	return formatAllocation(riskAllocationModel[risk])
 }
 
-// OPTIMIZED: Direct cache reference instead of function call
-func getStrategiesForRisk(risk string) []string {
-	return strategiesCache[risk]
+// getStrategiesForRisk is a risk level's strategies in locale
+func getStrategiesForRisk(risk RiskLevel, locale string) []string {
+	return localizeAll(locale, strategiesCache[risk])
 }
 
 // OPTIMIZED: Pre-compute instead of parsing + formatting every time
 func calculateAnnualContribution(monthlyStr string) string {
-	annual := parseCachedFloat(monthlyStr) * 12
-	return fmt.Sprintf("$%.2f", annual)
+	monthly, _ := parseCachedAmount(monthlyStr)
+	annual := monthly * 12
+	return formatMoney(annual)
 }
 
+// generateRandomID returns 128 random bits as hex, unique enough to key persisted plans and goals
 func generateRandomID() string {
-	// Optimized: Use time-based ID instead of PID modulo for better distribution
-	return fmt.Sprintf("%d", os.Getpid()%(100000))
+	var b [16]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		// crypto/rand only fails if the OS entropy source is broken
+		log.Fatalf("failed to generate ID: %v", err)
+	}
+	return hex.EncodeToString(b[:])
 }
 
 // ============================================
 // GROUNDBREAKING HELPER FUNCTIONS
 // ============================================
 
-// calculateInvestableFromSpending suggests investment amount based on spending velocity
+// calculateInvestableFromSpending suggests investing a quarter of monthly discretionary spending
 func calculateInvestableFromSpending(monthlySpend float64) float64 {
-	// Rule: 20-30% of monthly income (assuming monthly spend reflects income)
-	// Most people can invest 25% of spending level
+	// Most people can redirect 25% of what they spend on wants without touching needs
 	return monthlySpend * 0.25
 }
 
@@ -914,13 +1757,13 @@ func calculateDynamicRiskScore(incomeStability, txFrequency, savingsConsistency
 }
 
 // getRiskLevelFromScore maps numerical score to risk level
-func getRiskLevelFromScore(score int) string {
+func getRiskLevelFromScore(score int) RiskLevel {
 	if score >= 70 {
-		return "Aggressive"
+		return RiskAggressive
 	} else if score >= 50 {
-		return "Moderate-to-Aggressive"
+		return RiskModerateToAggressive
 	} else if score >= 35 {
-		return "Moderate"
+		return RiskModerate
 	}
-	return "Conservative"
+	return RiskConservative
 }
@@ -0,0 +1,179 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"slices"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/becomeliminal/nim-go-sdk/core"
+)
+
+func TestHorizonAllocationEveryYear(t *testing.T) {
+	for _, risk := range riskLevels {
+		t.Run(string(risk), func(t *testing.T) {
+			var prevEquity, prevCash float64
+			for years := 1; years <= 30; years++ {
+				a := horizonAllocation(float64(years), risk)
+				for name, share := range map[string]float64{"stocks": a.stocks, "international": a.international, "reit": a.reit, "bonds": a.bonds, "cash": a.cash} {
+					if share < 0 {
+						t.Errorf("%d years: %s share %v is negative", years, name, share)
+					}
+				}
+				if total := (a.stocks + a.international + a.reit + a.bonds + a.cash) * 100; !approxEqual(total, 100) {
+					t.Errorf("%d years: mix sums to %v%%, want 100%%", years, total)
+				}
+
+				// What analyze_investment_recommendations shows must add up too
+				shown := 0.0
+				for _, text := range formatAllocation(uniformBands(planWeights(a), 0)) {
+					pct, err := strconv.ParseFloat(strings.TrimSuffix(text, "%"), 64)
+					if err != nil {
+						t.Fatalf("%d years: can't read %q", years, text)
+					}
+					shown += pct
+				}
+				if !approxEqual(shown, 100) {
+					t.Errorf("%d years: displayed mix sums to %v%%, want 100%%", years, shown)
+				}
+
+				equity := planEquityShare(a)
+				if years > 1 && (equity < prevEquity-1e-9 || a.cash > prevCash+1e-9) {
+					t.Errorf("%d years: equity %v, cash %v after %v, %v at %d years; equity must not fall or cash rise with more time",
+						years, equity, a.cash, prevEquity, prevCash, years-1)
+				}
+				prevEquity, prevCash = equity, a.cash
+			}
+		})
+	}
+}
+
+func TestHorizonScheduleIsMonotonic(t *testing.T) {
+	for i := 1; i < len(horizonSchedule); i++ {
+		prev, k := horizonSchedule[i-1], horizonSchedule[i]
+		if k.years <= prev.years || k.equity < prev.equity || k.cash > prev.cash || k.equity+k.cash > 1 {
+			t.Errorf("knot %d %+v after %+v: years must rise, equity not fall, cash not rise, and equity+cash stay within 100%%", i, k, prev)
+		}
+	}
+}
+
+// errorFields lists the fields a validation error names, in order
+func errorFields(msg string) []string {
+	var fields []string
+	for _, line := range strings.Split(strings.TrimPrefix(msg, "invalid input: "), "\n") {
+		if field, _, ok := strings.Cut(line, ": "); ok {
+			fields = append(fields, field)
+		}
+	}
+	return fields
+}
+
+// checkToolResult checks a handler's result: the error's field names when wantFields is set,
+// otherwise success with data that encodes (NaN and Inf don't)
+func checkToolResult(t *testing.T, result *core.ToolResult, err error, wantFields []string) {
+	t.Helper()
+	if err != nil {
+		t.Fatalf("handler error: %v", err)
+	}
+	if len(wantFields) > 0 {
+		if result.Success || !slices.Equal(errorFields(result.Error), wantFields) {
+			t.Errorf("result = %v %q, want an error naming %v", result.Success, result.Error, wantFields)
+		}
+		return
+	}
+	if !result.Success {
+		t.Fatalf("result error: %s", result.Error)
+	}
+	if _, err := json.Marshal(result.Data); err != nil {
+		t.Errorf("data doesn't encode: %v", err)
+	}
+}
+
+func TestSmartSavingsRejectsBadInput(t *testing.T) {
+	handler := smartSavingsHandler(&fakeExecutor{data: `{}`})
+	tests := []struct {
+		name   string
+		input  string
+		fields []string
+	}{
+		{"zero income", `{"monthly_income": "0"}`, []string{"monthly_income"}},
+		{"negative income", `{"monthly_income": "-4000"}`, []string{"monthly_income"}},
+		{"non-numeric income", `{"monthly_income": "plenty"}`, []string{"monthly_income"}},
+		{"negative savings", `{"monthly_income": "4000", "current_savings": "-1"}`, []string{"current_savings"}},
+		{"non-numeric savings", `{"monthly_income": "4000", "current_savings": "some"}`, []string{"current_savings"}},
+		{"negative goal", `{"monthly_income": "4000", "emergency_fund_goal": "-10000"}`, []string{"emergency_fund_goal"}},
+		{"every field bad", `{"monthly_income": "0", "current_savings": "-1", "emergency_fund_goal": "x"}`,
+			[]string{"monthly_income", "current_savings", "emergency_fund_goal"}},
+		{"zero savings and goal", `{"monthly_income": "4000", "current_savings": "0", "emergency_fund_goal": "0"}`, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := handler(context.Background(), &core.ToolParams{UserID: "handler_user", Input: json.RawMessage(tt.input)})
+			checkToolResult(t, result, err, tt.fields)
+		})
+	}
+}
+
+func TestRebalancerRejectsBadInput(t *testing.T) {
+	tests := []struct {
+		name   string
+		input  string
+		fields []string
+	}{
+		{"zero portfolio", `{"current_stocks_value": "0", "current_bonds_value": "0", "current_cash_value": "0", "target_risk_level": "moderate"}`,
+			[]string{"portfolio_total"}},
+		{"negative stocks", `{"current_stocks_value": "-100", "current_bonds_value": "500", "current_cash_value": "100", "target_risk_level": "moderate"}`,
+			[]string{"current_stocks_value"}},
+		{"non-numeric bonds", `{"current_stocks_value": "1000", "current_bonds_value": "lots", "current_cash_value": "100", "target_risk_level": "moderate"}`,
+			[]string{"current_bonds_value"}},
+		{"nothing given or recorded", `{"target_risk_level": "moderate"}`,
+			[]string{"current_stocks_value", "current_bonds_value", "current_cash_value"}},
+		{"negative international", `{"current_stocks_value": "1000", "current_international_value": "-5", "current_bonds_value": "500", "current_cash_value": "100", "target_risk_level": "moderate"}`,
+			[]string{"current_international_value"}},
+		{"negative band", `{"current_stocks_value": "1000", "current_bonds_value": "500", "current_cash_value": "100", "target_risk_level": "moderate", "band_percent": "-2"}`,
+			[]string{"band_percent"}},
+		{"negative min trade", `{"current_stocks_value": "1000", "current_bonds_value": "500", "current_cash_value": "100", "target_risk_level": "moderate", "min_trade_amount": "-50"}`,
+			[]string{"min_trade_amount"}},
+		{"non-numeric contribution", `{"current_stocks_value": "1000", "current_bonds_value": "500", "current_cash_value": "100", "target_risk_level": "moderate", "monthly_contribution": "some"}`,
+			[]string{"monthly_contribution"}},
+		{"valid", `{"current_stocks_value": "6000", "current_bonds_value": "3000", "current_cash_value": "1000", "target_risk_level": "moderate"}`, nil},
+		{"all cash", `{"current_stocks_value": "0", "current_bonds_value": "0", "current_cash_value": "1000", "target_risk_level": "moderate"}`, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := rebalancerHandler(context.Background(), &core.ToolParams{UserID: "handler_no_holdings", Input: json.RawMessage(tt.input)})
+			checkToolResult(t, result, err, tt.fields)
+		})
+	}
+}
+
+func TestSavingsBoosterRejectsBadInput(t *testing.T) {
+	tests := []struct {
+		name   string
+		input  string
+		fields []string
+	}{
+		{"zero budget", `{"monthly_budget": "0"}`, []string{"monthly_budget"}},
+		{"negative budget", `{"monthly_budget": "-3000"}`, []string{"monthly_budget"}},
+		{"non-numeric budget", `{"monthly_budget": "enough"}`, []string{"monthly_budget"}},
+		{"missing budget", `{}`, []string{"monthly_budget"}},
+		{"negative discretionary", `{"monthly_budget": "3000", "discretionary_spend": "-200"}`, []string{"discretionary_spend"}},
+		{"non-numeric discretionary", `{"monthly_budget": "3000", "discretionary_spend": "a bit"}`, []string{"discretionary_spend"}},
+		{"non-numeric return", `{"monthly_budget": "3000", "expected_return": "high"}`, []string{"expected_return"}},
+		{"zero discretionary", `{"monthly_budget": "3000", "discretionary_spend": "0"}`, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := savingsBoosterHandler(context.Background(), json.RawMessage(tt.input))
+			if len(tt.fields) > 0 {
+				if err == nil || !slices.Equal(errorFields(err.Error()), tt.fields) {
+					t.Errorf("error = %v, want one naming %v", err, tt.fields)
+				}
+				return
+			}
+			checkToolResult(t, &core.ToolResult{Success: err == nil, Data: data}, nil, nil)
+		})
+	}
+}
@@ -0,0 +1,148 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+
+	"github.com/becomeliminal/nim-go-sdk/core"
+	"github.com/becomeliminal/nim-go-sdk/tools"
+)
+
+// ============================================
+// EMPLOYER 401K MATCH
+// ============================================
+// An employer match is an instant, guaranteed return on the matched part of
+// a contribution, so unclaimed match dollars rank ahead of every other use of
+// investable money. A formula like "100% up to 4%" is match_percent 100 and
+// match_cap_percent 4: the employer adds 100% of contributions up to 4% of salary.
+
+// matchProjectionYears is how long employer_401k_match_calculator compounds the match alone
+const matchProjectionYears = 20
+
+// maxEmployerMatchPct bounds match_percent; a few plans match more than dollar for dollar
+const maxEmployerMatchPct = 200.0
+
+// maxSalaryPct bounds the match cap and contribution, both percentages of salary
+const maxSalaryPct = 100.0
+
+// newEmployerMatchTool works out how much of an employer 401k match the user is capturing
+func newEmployerMatchTool() core.Tool {
+	return tools.New("employer_401k_match_calculator").
+		Description("Work out the employer 401k match a user is capturing or leaving on the table each year, the contribution needed to get all of it, and what the match alone grows to over 20 years. Use before recommending other investing: unclaimed match comes first").
+		Schema(tools.ObjectSchema(map[string]interface{}{
+			"salary":               tools.StringProperty("Annual salary before tax in the account currency"),
+			"match_percent":        tools.StringProperty("Percentage of the user's contributions the employer adds (e.g., '100' for dollar for dollar, '50' for 50 cents per dollar)"),
+			"match_cap_percent":    tools.StringProperty("Percentage of salary the employer matches up to (e.g., '4' in '100% up to 4%')"),
+			"contribution_percent": tools.StringProperty("Percentage of salary the user contributes now (e.g., '2'; '0' if not contributing)"),
+			"expected_return":      tools.StringProperty(fmt.Sprintf("Optional expected annual return percentage (defaults to the server's assumption, currently %g)", appConfig.Assumptions.EquityReturnPct)),
+		}, "salary", "match_percent", "match_cap_percent", "contribution_percent")).
+		HandlerFunc(func(ctx context.Context, input json.RawMessage) (interface{}, error) {
+			var params struct {
+				Salary              string `json:"salary"`
+				MatchPercent        string `json:"match_percent"`
+				MatchCapPercent     string `json:"match_cap_percent"`
+				ContributionPercent string `json:"contribution_percent"`
+				ExpectedReturn      string `json:"expected_return"`
+			}
+			if err := json.Unmarshal(input, &params); err != nil {
+				return nil, fmt.Errorf("invalid input: %w", err)
+			}
+
+			var v amountValidator
+			salary := v.positive("salary", params.Salary)
+			matchPct := v.percent("match_percent", params.MatchPercent, true, maxEmployerMatchPct)
+			capPct := v.percent("match_cap_percent", params.MatchCapPercent, true, maxSalaryPct)
+			contributionPct := v.percent("contribution_percent", params.ContributionPercent, true, maxSalaryPct)
+			returnRate := v.returnRate("expected_return", params.ExpectedReturn, false)
+			if err := v.err(); err != nil {
+				return nil, err
+			}
+
+			return calculateEmployerMatch(salary, matchPct, capPct, contributionPct, returnRate), nil
+		}).
+		Build()
+}
+
+// calculateEmployerMatch compares the match captured at contributionPct with the full match.
+// Contributions are capped at the 401k limit, so a cap the limit can't reach is flagged.
+func calculateEmployerMatch(salary, matchPct, capPct, contributionPct, returnRate float64) EmployerMatchResult {
+	limit := annualContributionLimits["401k"]
+	contribution := salary * contributionPct / 100
+	matched := min(contribution, salary*capPct/100, limit)
+	captured := matched * matchPct / 100
+	full := min(salary*capPct/100, limit) * matchPct / 100
+	missed := max(full-captured, 0)
+	months := float64(matchProjectionYears * 12)
+
+	r := EmployerMatchResult{
+		Currency:                  activeCurrency().Code,
+		SalaryUSD:                 salary,
+		MatchPercent:              matchPct,
+		MatchCapPercent:           capPct,
+		ContributionPercent:       contributionPct,
+		AnnualContributionUSD:     contribution,
+		MatchCapturedUSD:          captured,
+		MatchLeftOnTableUSD:       missed,
+		FullMatchUSD:              full,
+		ContributionToMaxMatchPct: capPct,
+		CapturingFullMatch:        missed < 0.005,
+		Projection: EmployerMatchProjection{
+			Years:                 matchProjectionYears,
+			ExpectedReturnPercent: returnRate,
+			CapturedMatchValueUSD: futureValue(0, captured/12, returnRate, months),
+			FullMatchValueUSD:     futureValue(0, full/12, returnRate, months),
+			LeftOnTableValueUSD:   futureValue(0, missed/12, returnRate, months),
+		},
+		Assumptions: map[string]interface{}{
+			"account_limit_usd": limit,
+			"limit_year":        contributionLimitYear,
+			"basis":             "Match paid on contributions up to match_cap_percent of salary, vesting and per-paycheck true-up ignored",
+		},
+	}
+	if matchPct == 0 || capPct == 0 {
+		r.ContributionToMaxMatchPct = 0
+	}
+	if salary*capPct/100 > limit {
+		r.LimitReached = true
+		r.ContributionToMaxMatchPct = math.Ceil(limit/salary*100*100) / 100
+		r.Warning = fmt.Sprintf("The %d 401k limit of %s is below %g%% of salary, so only %s can be matched.",
+			contributionLimitYear, formatWholeMoney(limit), capPct, formatWholeMoney(limit))
+	}
+	if contribution > limit {
+		r.LimitReached = true
+		r.Warning = fmt.Sprintf("Contributing %g%% is %s a year, over the %d 401k limit of %s; only the limit can go in.",
+			contributionPct, formatWholeMoney(contribution), contributionLimitYear, formatWholeMoney(limit))
+	}
+
+	r.Recommendations = employerMatchRecommendations(r)
+	switch {
+	case full == 0:
+		r.Message = "This plan has no employer match, so the 401k competes with other accounts on tax treatment alone (compare_account_types)."
+	case r.CapturingFullMatch:
+		r.Message = fmt.Sprintf("Already capturing the full %s/year match - worth about %s after %d years on its own.",
+			formatWholeMoney(full), formatWholeMoney(r.Projection.FullMatchValueUSD), matchProjectionYears)
+	default:
+		r.Message = fmt.Sprintf("Leaving %s/year of free match on the table. Raising the contribution to %g%% captures all %s/year - about %s more after %d years from the match alone.",
+			formatWholeMoney(missed), r.ContributionToMaxMatchPct, formatWholeMoney(full), formatWholeMoney(r.Projection.LeftOnTableValueUSD), matchProjectionYears)
+	}
+	return r
+}
+
+// employerMatchRecommendations ranks what to do with investable money, unclaimed match first
+func employerMatchRecommendations(r EmployerMatchResult) []EmployerMatchRecommendation {
+	var recs []EmployerMatchRecommendation
+	add := func(action, reason string) {
+		recs = append(recs, EmployerMatchRecommendation{Rank: len(recs) + 1, Action: action, Reason: reason})
+	}
+	if !r.CapturingFullMatch {
+		extra := (r.ContributionToMaxMatchPct - r.ContributionPercent) * r.SalaryUSD / 100
+		add("capture_full_match", fmt.Sprintf("Contribute %g%% of salary (%s more a year) to collect %s of match - an instant %g%% return before any other investing",
+			r.ContributionToMaxMatchPct, formatWholeMoney(extra), formatWholeMoney(r.MatchLeftOnTableUSD), r.MatchPercent))
+	}
+	add("pay_high_interest_debt", fmt.Sprintf("Pay down debt above %.0f%% APR (debt_vs_invest_analyzer) before investing past the match", appConfig.HighAPRThreshold))
+	add("build_emergency_fund", "Keep 3-6 months of expenses in savings (emergency_fund_calculator)")
+	add("invest_beyond_match", "Then invest more - an IRA, more 401k or a taxable account (compare_account_types)")
+	return recs
+}
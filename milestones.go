@@ -0,0 +1,166 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/becomeliminal/nim-go-sdk/core"
+	"github.com/becomeliminal/nim-go-sdk/tools"
+
+	"vibe-invest/storage"
+)
+
+// ============================================
+// MILESTONES
+// ============================================
+// Every time a contribution or a portfolio snapshot is written, the user's
+// totals are checked against MILESTONE_AMOUNTS (contributed and portfolio
+// value), a full year since their first contribution, and GOAL_MILESTONE_PCT of
+// a goal funded. Each milestone is stored once with the time it was first
+// seen, logged, and written to the audit log as milestone_reached.
+// get_milestones reports the ones not yet mentioned to the user as new, then
+// marks them announced, so each is celebrated exactly once.
+//
+// Goal funding is only measured from the contributions ledger, and only when
+// the user has a single goal the ledger can be attributed to.
+
+// Milestone key prefixes; amounts and goal IDs complete them
+const (
+	milestoneContributed = "contributed_"
+	milestoneValue       = "portfolio_value_"
+	milestoneFirstYear   = "contributions_first_year"
+	milestoneGoalFunded  = "goal_funded_"
+)
+
+// checkMilestones records any milestone userID has newly reached. Failures are logged rather than
+// failing the write that triggered the check.
+func checkMilestones(ctx context.Context, userID string, now time.Time) {
+	user := userKey(userID)
+	contributions, err := store.ListContributions(ctx, user)
+	if err != nil {
+		log.Printf("⚠️  Milestone check could not load contributions for %s: %v\n", user, err)
+		return
+	}
+	snapshots, err := store.ListSnapshots(ctx, user, "")
+	if err != nil {
+		log.Printf("⚠️  Milestone check could not load snapshots for %s: %v\n", user, err)
+		return
+	}
+	goals, err := store.ListGoals(ctx, user)
+	if err != nil {
+		log.Printf("⚠️  Milestone check could not load goals for %s: %v\n", user, err)
+		return
+	}
+
+	for _, m := range reachedMilestones(contributions, snapshots, goals) {
+		m.UserID, m.AchievedAt = user, now
+		isNew, err := store.SaveMilestone(ctx, m)
+		if err != nil {
+			log.Printf("⚠️  Failed to record milestone %s for %s: %v\n", m.Key, user, err)
+			continue
+		}
+		if isNew {
+			log.Printf("🎉 %s reached a milestone: %s\n", user, m.Label)
+			recordAudit(ctx, user, "milestones", "milestone_reached", m.Key)
+		}
+	}
+}
+
+// reachedMilestones lists every milestone the records show, whether or not it was recorded before
+func reachedMilestones(contributions []storage.Contribution, snapshots []storage.Snapshot, goals []storage.Goal) []storage.Milestone {
+	var reached []storage.Milestone
+	_, lifetime := contributionTotals(contributions, time.Time{})
+	value := 0.0
+	if len(snapshots) > 0 {
+		value = snapshots[len(snapshots)-1].TotalValue
+	}
+	for _, amount := range appConfig.MilestoneAmounts {
+		if amount > 0 && lifetime >= amount {
+			reached = append(reached, storage.Milestone{
+				Key:   fmt.Sprintf("%s%g", milestoneContributed, amount),
+				Label: fmt.Sprintf("%s invested", formatWholeMoney(amount)),
+			})
+		}
+		if amount > 0 && value >= amount {
+			reached = append(reached, storage.Milestone{
+				Key:   fmt.Sprintf("%s%g", milestoneValue, amount),
+				Label: fmt.Sprintf("Portfolio worth %s", formatWholeMoney(amount)),
+			})
+		}
+	}
+
+	if len(contributions) > 0 {
+		first, last := contributions[0].Date, contributions[len(contributions)-1].Date
+		if !last.Before(first.AddDate(1, 0, 0)) {
+			reached = append(reached, storage.Milestone{Key: milestoneFirstYear, Label: "A full year of contributions"})
+		}
+	}
+
+	if pct := appConfig.GoalMilestonePct; len(goals) == 1 && pct > 0 && goals[0].TargetAmount > 0 {
+		goal := goals[0]
+		if saved, ok := contributedSince(contributions, goal.CreatedAt); ok && saved/goal.TargetAmount*100 >= pct {
+			reached = append(reached, storage.Milestone{
+				Key:   fmt.Sprintf("%s%g:%s", milestoneGoalFunded, pct, goal.ID),
+				Label: fmt.Sprintf("'%s' %g%% funded", goal.Name, pct),
+			})
+		}
+	}
+	return reached
+}
+
+// newMilestonesTool reports the user's milestones, flagging the ones not mentioned before
+func newMilestonesTool() core.Tool {
+	return tools.New("get_milestones").
+		Description("Get the milestones the user has reached (amount invested, portfolio value, a full year of contributions, a goal half funded) with when each happened. Milestones listed under new haven't been mentioned to the user yet - celebrate them; they are marked announced once returned. Call it at the start of a conversation to catch new ones").
+		Schema(tools.ObjectSchema(map[string]interface{}{})).
+		Handler(func(ctx context.Context, toolParams *core.ToolParams) (*core.ToolResult, error) {
+			user := userKey(toolParams.UserID)
+			milestones, err := store.ListMilestones(ctx, user)
+			if err != nil {
+				return &core.ToolResult{Success: false, Error: fmt.Sprintf("could not load milestones: %v", err)}, nil
+			}
+			contributions, err := store.ListContributions(ctx, user)
+			if err != nil {
+				return &core.ToolResult{Success: false, Error: fmt.Sprintf("could not load contributions: %v", err)}, nil
+			}
+
+			r := MilestonesResult{Currency: activeCurrency().Code, Milestones: milestones, New: []storage.Milestone{}}
+			var labels []string
+			for _, m := range milestones {
+				if m.AnnouncedAt == nil {
+					r.New = append(r.New, m)
+					labels = append(labels, m.Label)
+				}
+			}
+			_, lifetime := contributionTotals(contributions, time.Time{})
+			r.ContributedUSD = lifetime
+			for _, amount := range appConfig.MilestoneAmounts {
+				if amount > lifetime && (r.NextAmountUSD == 0 || amount < r.NextAmountUSD) {
+					r.NextAmountUSD = amount
+				}
+			}
+
+			switch {
+			case len(r.New) > 0:
+				r.Message = "New since last time: " + strings.Join(labels, ", ") + "!"
+			case len(milestones) == 0:
+				r.Message = "No milestones reached yet."
+			default:
+				r.Message = fmt.Sprintf("%d milestone(s) reached so far, all already celebrated.", len(milestones))
+			}
+			if r.NextAmountUSD > 0 {
+				r.Message += fmt.Sprintf(" Next up: %s invested, %s to go.", formatWholeMoney(r.NextAmountUSD), formatWholeMoney(r.NextAmountUSD-lifetime))
+			}
+
+			if len(r.New) > 0 {
+				if err := store.MarkMilestonesAnnounced(ctx, user, time.Now().UTC()); err != nil {
+					log.Printf("⚠️  Failed to mark milestones announced for %s: %v\n", user, err)
+				}
+			}
+			return &core.ToolResult{Success: true, Data: r}, nil
+		}).
+		Build()
+}
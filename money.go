@@ -0,0 +1,135 @@
+package main
+
+import (
+	"log"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// ============================================
+// CURRENCY FORMATTING
+// ============================================
+// Every monetary string goes through formatMoney so the whole server speaks the
+// CURRENCY it is configured for, written the way its LOCALE writes amounts.
+// Numeric *_usd fields keep their names for API compatibility but are in that
+// currency too.
+
+// currencyFormat is how one currency writes amounts
+type currencyFormat struct {
+	Code        string
+	Symbol      string
+	Decimal     byte // decimal separator
+	Group       byte // thousands separator
+	SymbolAfter bool // "1.234,56 €" rather than "€1.234,56"
+}
+
+// currencyFormats are the supported CURRENCY values
+var currencyFormats = map[string]currencyFormat{
+	"USD": {Code: "USD", Symbol: "$", Decimal: '.', Group: ','},
+	"EUR": {Code: "EUR", Symbol: "€", Decimal: ',', Group: '.', SymbolAfter: true},
+	"GBP": {Code: "GBP", Symbol: "£", Decimal: '.', Group: ','},
+}
+
+// envCurrency reads a currency code from the environment, keeping the fallback for unsupported codes
+func envCurrency(key, fallback string) string {
+	code := strings.ToUpper(envString(key, fallback))
+	if _, ok := currencyFormats[code]; !ok {
+		log.Printf("⚠️  Ignoring unsupported %s=%q, using %s", key, code, fallback)
+		return fallback
+	}
+	return code
+}
+
+// localeMoneyFormat is how a locale writes any currency's amounts
+type localeMoneyFormat struct {
+	Decimal     byte
+	Group       byte
+	SymbolAfter bool
+}
+
+// localeMoneyFormats override the currency's own separators and symbol placement under a
+// LOCALE; locales not listed (en) write each currency its usual way
+var localeMoneyFormats = map[string]localeMoneyFormat{
+	"es": {Decimal: ',', Group: '.', SymbolAfter: true},
+}
+
+// activeCurrency is the configured currency's format, written the LOCALE's way
+func activeCurrency() currencyFormat {
+	return currencyIn(appConfig.Locale)
+}
+
+// currencyIn is the configured currency's format, written locale's way
+func currencyIn(locale string) currencyFormat {
+	c, ok := currencyFormats[appConfig.Currency]
+	if !ok {
+		c = currencyFormats["USD"]
+	}
+	if f, ok := localeMoneyFormats[locale]; ok {
+		c.Decimal, c.Group, c.SymbolAfter = f.Decimal, f.Group, f.SymbolAfter
+	}
+	return c
+}
+
+// formatMoney writes an amount in the configured currency with cents, e.g. "$1,234.56" or "1.234,56 €"
+func formatMoney(amount float64) string {
+	return formatMoneyDigits(amount, 2, activeCurrency())
+}
+
+// formatWholeMoney is formatMoney rounded to whole units, for limits and headline figures
+func formatWholeMoney(amount float64) string {
+	return formatMoneyDigits(amount, 0, activeCurrency())
+}
+
+// formatMoneyIn is formatMoney written locale's way, for text localized for one user
+func formatMoneyIn(locale string, amount float64) string {
+	return formatMoneyDigits(amount, 2, currencyIn(locale))
+}
+
+// formatWholeMoneyIn is formatWholeMoney written locale's way
+func formatWholeMoneyIn(locale string, amount float64) string {
+	return formatMoneyDigits(amount, 0, currencyIn(locale))
+}
+
+// formatMoneyDigits writes amount in c with the given number of decimals, grouping and symbol
+func formatMoneyDigits(amount float64, decimals int, c currencyFormat) string {
+	s := strconv.FormatFloat(math.Abs(amount), 'f', decimals, 64)
+	whole, frac, _ := strings.Cut(s, ".")
+
+	var b strings.Builder
+	if amount < 0 && s != strconv.FormatFloat(0, 'f', decimals, 64) {
+		b.WriteByte('-')
+	}
+	if !c.SymbolAfter {
+		b.WriteString(c.Symbol)
+	}
+	for i := range len(whole) {
+		if i > 0 && (len(whole)-i)%3 == 0 {
+			b.WriteByte(c.Group)
+		}
+		b.WriteByte(whole[i])
+	}
+	if frac != "" {
+		b.WriteByte(c.Decimal)
+		b.WriteString(frac)
+	}
+	if c.SymbolAfter {
+		b.WriteString(" " + c.Symbol)
+	}
+	return b.String()
+}
+
+// moneyTemplate places the currency symbol around a SummaryTemplate field, e.g. "${{.amount}}"
+// or "{{.amount}} €", for confirmation prompts that echo a model-supplied number
+func moneyTemplate(field string) string {
+	return moneyTemplateExpr("." + field)
+}
+
+// moneyTemplateExpr is moneyTemplate for any template expression, e.g. a range variable's "$m.amount"
+func moneyTemplateExpr(expr string) string {
+	c := activeCurrency()
+	if c.SymbolAfter {
+		return "{{" + expr + "}} " + c.Symbol
+	}
+	return c.Symbol + "{{" + expr + "}}"
+}
@@ -0,0 +1,82 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestMoneyTemplate(t *testing.T) {
+	tests := []struct {
+		currency, locale, want string
+	}{
+		{"USD", "en", "${{.monthly_amount}}"},
+		{"GBP", "en", "£{{.monthly_amount}}"},
+		{"EUR", "en", "{{.monthly_amount}} €"},
+		{"USD", "es", "{{.monthly_amount}} $"},
+	}
+	for _, tt := range tests {
+		withCurrency(t, tt.currency)
+		withLocale(t, tt.locale)
+		if got := moneyTemplate("monthly_amount"); got != tt.want {
+			t.Errorf("%s/%s: moneyTemplate = %q, want %q", tt.currency, tt.locale, got, tt.want)
+		}
+	}
+}
+
+func TestFormatMoneyIn(t *testing.T) {
+	tests := []struct {
+		currency, serverLocale, locale string
+		amount                         float64
+		want, wantWhole                string
+	}{
+		{"USD", "en", "en", 1234.56, "$1,234.56", "$1,235"},
+		{"USD", "en", "es", 1234.56, "1.234,56 $", "1.235 $"},
+		{"USD", "es", "en", 1234.56, "$1,234.56", "$1,235"},
+		{"EUR", "en", "en", 1234.56, "1.234,56 €", "1.235 €"},
+		{"EUR", "en", "es", -1234.56, "-1.234,56 €", "-1.235 €"},
+		{"GBP", "en", "es", 1234567, "1.234.567,00 £", "1.234.567 £"},
+	}
+	for _, tt := range tests {
+		withCurrency(t, tt.currency)
+		withLocale(t, tt.serverLocale)
+		if got := formatMoneyIn(tt.locale, tt.amount); got != tt.want {
+			t.Errorf("%s in %s (server %s): formatMoneyIn = %q, want %q", tt.currency, tt.locale, tt.serverLocale, got, tt.want)
+		}
+		if got := formatWholeMoneyIn(tt.locale, tt.amount); got != tt.wantWhole {
+			t.Errorf("%s in %s (server %s): formatWholeMoneyIn = %q, want %q", tt.currency, tt.locale, tt.serverLocale, got, tt.wantWhole)
+		}
+	}
+}
+
+func TestValidatorMoneyFollowsItsLocale(t *testing.T) {
+	withCurrency(t, "USD")
+	withLocale(t, "en")
+	tests := []struct {
+		locale, want string
+	}{
+		{"", "$1,500.00"},
+		{"en", "$1,500.00"},
+		{"es", "1.500,00 $"},
+	}
+	for _, tt := range tests {
+		v := amountValidator{locale: tt.locale}
+		if got := v.money(1500); got != tt.want {
+			t.Errorf("locale %q: money = %q, want %q", tt.locale, got, tt.want)
+		}
+	}
+}
+
+func TestConceptExampleMoneyFollowsItsLocale(t *testing.T) {
+	withCurrency(t, "USD")
+	withLocale(t, "en")
+	for _, tt := range []struct{ locale, want string }{
+		{"en", "$100.00"},
+		{"es", "100,00 $"},
+	} {
+		e := &ConceptExample{Generic: true, MonthlySavingsUSD: 100, BalanceUSD: 1000}
+		compoundInterestExample(e, RiskModerate, tt.locale)
+		if !strings.Contains(e.Example, tt.want) {
+			t.Errorf("%s example doesn't write %q: %s", tt.locale, tt.want, e.Example)
+		}
+	}
+}
@@ -0,0 +1,165 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"math/rand/v2"
+	"slices"
+	"strings"
+
+	"github.com/becomeliminal/nim-go-sdk/core"
+	"github.com/becomeliminal/nim-go-sdk/tools"
+)
+
+// ============================================
+// MONTE CARLO PROJECTIONS
+// ============================================
+// Each path draws a normally distributed return every month from the risk
+// level's annual mean and volatility, with the same end-of-month
+// contributions as futureValue.
+
+// Simulation size caps keep a worst-case run well under a second
+const (
+	maxSimulationPaths = 10000
+	maxSimulationYears = maxProjectionYears
+)
+
+// riskVolatilityPct is the annual volatility per risk level, in percent; means come from
+// expectedPortfolioReturn over the level's target allocation. More equity, bigger swings.
+var riskVolatilityPct = map[RiskLevel]float64{
+	RiskConservative:         6,
+	RiskModerate:             10,
+	RiskModerateToAggressive: 13,
+	RiskAggressive:           16,
+}
+
+// randomSource seeds a fresh generator per request; tests can inject a fixed seed instead
+func randomSource() *rand.Rand {
+	return rand.New(rand.NewPCG(rand.Uint64(), rand.Uint64()))
+}
+
+// newSimulationTool runs Monte Carlo projections; newRand supplies each request's generator
+func newSimulationTool(newRand func() *rand.Rand) core.Tool {
+	return tools.New("simulate_investment_outcomes").
+		Description("Simulate a range of investment outcomes (Monte Carlo) for a risk level: 10th/50th/90th percentile ending balances, chance of reaching a target, and a per-year percentile series for charts").
+		Schema(tools.ObjectSchema(map[string]interface{}{
+			"initial_amount":   tools.StringProperty("Starting amount in the account currency"),
+			"monthly_addition": tools.StringProperty("Amount added each month in the account currency"),
+			"years":            tools.StringProperty(fmt.Sprintf("Number of years to simulate, 1-%.0f", maxSimulationYears)),
+			"risk_level":       tools.StringProperty("Risk level: conservative, moderate, moderate-to-aggressive or aggressive"),
+			"target_amount":    tools.StringProperty("Optional target ending balance in the account currency to estimate the chance of reaching"),
+			"paths":            tools.IntegerProperty(fmt.Sprintf("Optional number of simulated paths (default %d, max %d)", appConfig.SimulationPaths, maxSimulationPaths)),
+		}, "initial_amount", "monthly_addition", "years", "risk_level")).
+		HandlerFunc(func(ctx context.Context, input json.RawMessage) (interface{}, error) {
+			var params struct {
+				InitialAmount   string `json:"initial_amount"`
+				MonthlyAddition string `json:"monthly_addition"`
+				Years           string `json:"years"`
+				RiskLevel       string `json:"risk_level"`
+				TargetAmount    string `json:"target_amount"`
+				Paths           int    `json:"paths"`
+			}
+			if err := json.Unmarshal(input, &params); err != nil {
+				return nil, fmt.Errorf("invalid input: %w", err)
+			}
+
+			var v amountValidator
+			initial := v.nonNegative("initial_amount", params.InitialAmount, true)
+			monthly := v.nonNegative("monthly_addition", params.MonthlyAddition, true)
+			years := v.years("years", params.Years, minProjectionYears, maxSimulationYears)
+			target := v.nonNegative("target_amount", params.TargetAmount, false)
+			risk, err := normalizeRiskLevel(params.RiskLevel)
+			if err != nil {
+				v.fail("risk_level", "%v", err)
+			}
+			paths := params.Paths
+			if paths == 0 {
+				paths = appConfig.SimulationPaths
+			}
+			if paths < 1 || paths > maxSimulationPaths {
+				v.fail("paths", "must be between 1 and %d (got %d)", maxSimulationPaths, paths)
+			}
+			if err := v.err(); err != nil {
+				return nil, err
+			}
+
+			result := simulateOutcomes(newRand(), initial, monthly, years, risk, paths)
+			if strings.TrimSpace(params.TargetAmount) != "" {
+				result.setTarget(target)
+			}
+			return result, nil
+		}).
+		Build()
+}
+
+// simulateOutcomes runs paths simulations of whole months and summarizes the ending balances
+func simulateOutcomes(rng *rand.Rand, initial, monthly, years float64, risk RiskLevel, paths int) SimulationResult {
+	meanPct, volatilityPct := expectedPortfolioReturn(targetAllocationTable[risk]), riskVolatilityPct[risk]
+	totalMonths := max(int(math.Round(years*12)), 1)
+	monthlyMean := meanPct / 100 / 12
+	monthlyVol := volatilityPct / 100 / math.Sqrt(12)
+
+	balances := make([]float64, paths)
+	for i := range balances {
+		balances[i] = initial
+	}
+
+	var yearly []SimulationYear
+	for m := 1; m <= totalMonths; m++ {
+		for i := range balances {
+			r := max(monthlyMean+monthlyVol*rng.NormFloat64(), -1) // can't lose more than everything
+			balances[i] = balances[i]*(1+r) + monthly
+		}
+		if m%12 == 0 || m == totalMonths {
+			p10, p50, p90 := percentiles(balances)
+			yearly = append(yearly, SimulationYear{Year: float64(m) / 12, P10USD: p10, P50USD: p50, P90USD: p90})
+		}
+	}
+
+	p10, p50, p90 := percentiles(balances)
+	deterministic := futureValue(initial, monthly, meanPct, float64(totalMonths))
+	return SimulationResult{
+		Currency:              activeCurrency().Code,
+		RiskLevel:             risk,
+		MeanReturnPercent:     meanPct,
+		VolatilityPercent:     volatilityPct,
+		Paths:                 paths,
+		Years:                 years,
+		Months:                totalMonths,
+		TotalContributedUSD:   initial + monthly*float64(totalMonths),
+		P10EndingUSD:          p10,
+		P50EndingUSD:          p50,
+		P90EndingUSD:          p90,
+		DeterministicTotalUSD: deterministic,
+		YearlyPercentiles:     yearly,
+		Summary: fmt.Sprintf("Across %d simulated paths, 8 in 10 end between %s and %s after %d months (median %s). A flat %.1f%% return would give %s.",
+			paths, formatWholeMoney(p10), formatWholeMoney(p90), totalMonths, formatWholeMoney(p50), meanPct, formatWholeMoney(deterministic)),
+		endings: balances,
+	}
+}
+
+// setTarget records the share of paths that end at or above target
+func (r *SimulationResult) setTarget(target float64) {
+	hits := 0
+	for _, b := range r.endings {
+		if b >= target {
+			hits++
+		}
+	}
+	probability := float64(hits) / float64(len(r.endings)) * 100
+	r.TargetAmountUSD = target
+	r.ProbabilityOfTarget = &probability
+	r.Summary += fmt.Sprintf(" %.0f%% of paths reach your %s target.", probability, formatWholeMoney(target))
+}
+
+// percentiles returns the 10th, 50th and 90th percentiles (nearest rank) without reordering balances
+func percentiles(balances []float64) (p10, p50, p90 float64) {
+	sorted := slices.Clone(balances)
+	slices.Sort(sorted)
+	at := func(p float64) float64 {
+		return sorted[min(int(math.Ceil(p*float64(len(sorted))))-1, len(sorted)-1)]
+	}
+	return at(0.10), at(0.50), at(0.90)
+}
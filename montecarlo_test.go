@@ -0,0 +1,55 @@
+package main
+
+import (
+	"math/rand/v2"
+	"reflect"
+	"testing"
+)
+
+// seeded returns a generator factory like randomSource, fixed to seed
+func seeded(seed uint64) func() *rand.Rand {
+	return func() *rand.Rand { return rand.New(rand.NewPCG(seed, seed)) }
+}
+
+func TestSimulateOutcomesIsReproducibleForASeed(t *testing.T) {
+	tests := []struct {
+		name             string
+		initial, monthly float64
+		years            float64
+		risk             RiskLevel
+		paths            int
+	}{
+		{name: "conservative", initial: 10000, monthly: 200, years: 10, risk: RiskConservative, paths: 500},
+		{name: "aggressive partial year", initial: 0, monthly: 500, years: 7.5, risk: RiskAggressive, paths: 1000},
+		{name: "single path", initial: 2500, monthly: 0, years: 1, risk: RiskModerate, paths: 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			newRand := seeded(42)
+			first := simulateOutcomes(newRand(), tt.initial, tt.monthly, tt.years, tt.risk, tt.paths)
+			second := simulateOutcomes(newRand(), tt.initial, tt.monthly, tt.years, tt.risk, tt.paths)
+			if first.P10EndingUSD != second.P10EndingUSD || first.P50EndingUSD != second.P50EndingUSD || first.P90EndingUSD != second.P90EndingUSD {
+				t.Errorf("same seed, different percentiles: %v/%v/%v vs %v/%v/%v",
+					first.P10EndingUSD, first.P50EndingUSD, first.P90EndingUSD, second.P10EndingUSD, second.P50EndingUSD, second.P90EndingUSD)
+			}
+			if !reflect.DeepEqual(first, second) {
+				t.Errorf("same seed, different results:\n%+v\n%+v", first, second)
+			}
+
+			other := simulateOutcomes(seeded(7)(), tt.initial, tt.monthly, tt.years, tt.risk, tt.paths)
+			if other.P50EndingUSD == first.P50EndingUSD {
+				t.Errorf("seeds 42 and 7 gave the same median %v; the seed isn't reaching the simulation", first.P50EndingUSD)
+			}
+		})
+	}
+}
+
+func TestSimulateOutcomesPercentilesAreOrdered(t *testing.T) {
+	r := simulateOutcomes(seeded(1)(), 5000, 300, 20, RiskModerateToAggressive, 2000)
+	if !(r.P10EndingUSD <= r.P50EndingUSD && r.P50EndingUSD <= r.P90EndingUSD) {
+		t.Errorf("percentiles out of order: %v, %v, %v", r.P10EndingUSD, r.P50EndingUSD, r.P90EndingUSD)
+	}
+	if len(r.YearlyPercentiles) != 20 {
+		t.Errorf("got %d yearly points, want 20", len(r.YearlyPercentiles))
+	}
+}
@@ -0,0 +1,365 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/becomeliminal/nim-go-sdk/core"
+	"github.com/becomeliminal/nim-go-sdk/tools"
+
+	"vibe-invest/storage"
+)
+
+// ============================================
+// ONBOARDING
+// ============================================
+// complete_onboarding takes everything a new user would otherwise give to half
+// a dozen tools, scores their risk answers, saves the profile and goals, and
+// returns one starter plan. Whatever is given is saved even when other fields
+// are missing; those are listed so the assistant can ask for them and call the
+// tool again. Goals are matched by name, so a repeat call updates them rather
+// than adding duplicates.
+
+// onboardingFields are the inputs a complete onboarding needs, in the order to ask for them
+var onboardingFields = []string{"age", "monthly_income", "monthly_savings", "existing_savings", "existing_investments", "market_downturn_comfort", "previous_experience", "goals"}
+
+// Onboarding statuses
+const (
+	onboardingComplete = "complete"
+	onboardingPartial  = "partial"
+)
+
+// Onboarding assumptions
+const (
+	onboardingRetirementAge = 65  // years_to_retirement defaults to this age minus the user's
+	onboardingSavingsYears  = 3   // goals sooner than this are kept in savings rather than invested
+	onboardingEmergencyPct  = 0.5 // share of monthly savings suggested for the emergency fund until it is full
+)
+
+// onboardingGoalInput is one goal as given to complete_onboarding
+type onboardingGoalInput struct {
+	Name         string `json:"name"`
+	TargetAmount string `json:"target_amount"`
+	TargetDate   string `json:"target_date"`
+}
+
+// newOnboardingTool builds a new user's profile, goals and starter plan in one call
+func newOnboardingTool() core.Tool {
+	return tools.New("complete_onboarding").
+		Description("Set up a new user in one pass: give whatever you know of their age, income, monthly savings, existing savings and investments, goals and risk answers. Scores their risk tolerance, saves their profile and goals, and returns a starter plan (allocation, suggested monthly amount, first goal, next actions). Partial input is saved and the missing fields are listed - ask for those and call again with them").
+		Schema(tools.ObjectSchema(map[string]interface{}{
+			"age":                  tools.StringProperty("User's age in years"),
+			"monthly_income":       tools.StringProperty("Monthly take-home income in the account currency"),
+			"monthly_savings":      tools.StringProperty("Amount the user can save or invest each month in the account currency"),
+			"existing_savings":     tools.StringProperty("Cash savings today in the account currency"),
+			"existing_investments": tools.StringProperty("Value of investments today in the account currency"),
+			"goals": map[string]interface{}{
+				"type":        "array",
+				"description": "The user's goals with rough targets",
+				"items": tools.ObjectSchema(map[string]interface{}{
+					"name":          tools.StringProperty("Goal name, e.g. 'Home down payment'"),
+					"target_amount": tools.StringProperty("Rough target amount in the account currency"),
+					"target_date":   tools.StringProperty("When the money is needed: YYYY-MM-DD, a year ('2030') or a number of years ('5 years')"),
+				}, "name", "target_amount", "target_date"),
+			},
+			"years_to_retirement":     tools.StringProperty(fmt.Sprintf("Optional years until retirement (default %d minus age)", onboardingRetirementAge)),
+			"market_downturn_comfort": tools.StringProperty("How comfortable with 20% market drops? ('very_uncomfortable', 'somewhat_uncomfortable', 'neutral', 'comfortable', 'very_comfortable')"),
+			"previous_experience":     tools.StringProperty("Previous investment experience? ('none', 'minimal', 'moderate', 'extensive')"),
+			"income_stability":        tools.StringProperty("Optional: how stable is the user's income? ('stable', 'moderate', 'unstable')"),
+			"number_of_dependents":    tools.StringProperty("Optional: number of people who depend on the user's income"),
+			"loss_reaction":           tools.StringProperty("Optional: if their investments dropped 20% in a month they would... ('sell', 'hold', 'buy_more')"),
+		})).
+		Handler(func(ctx context.Context, toolParams *core.ToolParams) (*core.ToolResult, error) {
+			var params struct {
+				Age                   string                `json:"age"`
+				MonthlyIncome         string                `json:"monthly_income"`
+				MonthlySavings        string                `json:"monthly_savings"`
+				ExistingSavings       string                `json:"existing_savings"`
+				ExistingInvestments   string                `json:"existing_investments"`
+				Goals                 []onboardingGoalInput `json:"goals"`
+				YearsToRetirement     string                `json:"years_to_retirement"`
+				MarketDownturnComfort string                `json:"market_downturn_comfort"`
+				PreviousExperience    string                `json:"previous_experience"`
+				IncomeStability       string                `json:"income_stability"`
+				NumberOfDependents    string                `json:"number_of_dependents"`
+				LossReaction          string                `json:"loss_reaction"`
+			}
+			if len(toolParams.Input) > 0 {
+				if err := json.Unmarshal(toolParams.Input, &params); err != nil {
+					return &core.ToolResult{Success: false, Error: fmt.Sprintf("invalid input: %v", err)}, nil
+				}
+			}
+			if frozen, err := isReadOnly(ctx, toolParams.UserID); err != nil {
+				return &core.ToolResult{Success: false, Error: fmt.Sprintf("could not check account status: %v", err)}, nil
+			} else if frozen {
+				return readOnlyResult(), nil
+			}
+
+			var v amountValidator
+			in := onboardingInput{given: map[string]bool{}}
+			given := func(field, raw string) bool {
+				in.given[field] = strings.TrimSpace(raw) != ""
+				return in.given[field]
+			}
+			whole := func(field, raw string, hi int) int {
+				n, err := strconv.Atoi(strings.TrimSpace(raw))
+				if err != nil || n < 0 || n > hi {
+					v.fail(field, "%q must be a whole number between 0 and %d", raw, hi)
+				}
+				return n
+			}
+			if given("age", params.Age) {
+				in.age = whole("age", params.Age, 120)
+			}
+			if given("monthly_income", params.MonthlyIncome) {
+				in.income = v.positive("monthly_income", params.MonthlyIncome)
+			}
+			if given("monthly_savings", params.MonthlySavings) {
+				in.monthly = v.nonNegative("monthly_savings", params.MonthlySavings, true)
+			}
+			if given("existing_savings", params.ExistingSavings) {
+				in.savings = v.nonNegative("existing_savings", params.ExistingSavings, true)
+			}
+			if given("existing_investments", params.ExistingInvestments) {
+				in.investments = v.nonNegative("existing_investments", params.ExistingInvestments, true)
+			}
+			if in.given["monthly_income"] && in.given["monthly_savings"] && len(v.errs) == 0 && in.monthly > in.income {
+				v.fail("monthly_savings", "%s is more than the monthly income of %s", v.money(in.monthly), v.money(in.income))
+			}
+			if given("market_downturn_comfort", params.MarketDownturnComfort) {
+				in.answers.DownturnComfort = v.oneOf("market_downturn_comfort", params.MarketDownturnComfort, downturnComforts)
+			}
+			if given("previous_experience", params.PreviousExperience) {
+				in.answers.Experience = v.oneOf("previous_experience", params.PreviousExperience, experienceLevels)
+			}
+			if given("income_stability", params.IncomeStability) {
+				in.answers.IncomeStability = v.oneOf("income_stability", params.IncomeStability, incomeStabilities)
+			}
+			if given("loss_reaction", params.LossReaction) {
+				in.answers.LossReaction = v.oneOf("loss_reaction", params.LossReaction, lossReactions)
+			}
+			if given("number_of_dependents", params.NumberOfDependents) {
+				n := whole("number_of_dependents", params.NumberOfDependents, 50)
+				in.answers.Dependents = &n
+			}
+			in.answers.Age = in.age
+			in.answers.YearsToRetirement = max(onboardingRetirementAge-in.age, 0)
+			if given("years_to_retirement", params.YearsToRetirement) {
+				in.answers.YearsToRetirement = whole("years_to_retirement", params.YearsToRetirement, 100)
+			}
+
+			now := time.Now().UTC()
+			in.given["goals"] = len(params.Goals) > 0
+			for i, raw := range params.Goals {
+				field := fmt.Sprintf("goals[%d]", i)
+				g := storage.Goal{Name: strings.TrimSpace(raw.Name), TargetAmount: v.positive(field+".target_amount", raw.TargetAmount)}
+				if g.Name == "" {
+					v.fail(field+".name", "is required")
+				}
+				date, err := onboardingTargetDate(raw.TargetDate, now)
+				if err != nil {
+					v.fail(field+".target_date", "%v", err)
+				}
+				g.TargetDate = date.Format("2006-01-02")
+				in.goals = append(in.goals, g)
+			}
+			if err := v.err(); err != nil {
+				return &core.ToolResult{Success: false, Error: err.Error()}, nil
+			}
+
+			r, err := completeOnboarding(ctx, toolParams.UserID, in, now)
+			if err != nil {
+				return &core.ToolResult{Success: false, Error: err.Error()}, nil
+			}
+			recordAudit(ctx, toolParams.UserID, "user", "complete_onboarding", r.Status)
+			return &core.ToolResult{Success: true, Data: r}, nil
+		}).
+		Build()
+}
+
+// onboardingInput is complete_onboarding's validated input; given marks the fields supplied
+type onboardingInput struct {
+	given       map[string]bool
+	age         int
+	income      float64
+	monthly     float64
+	savings     float64
+	investments float64
+	answers     riskAnswers
+	goals       []storage.Goal // Name, TargetAmount and TargetDate set
+}
+
+// onboardingTargetDate reads a goal date given as YYYY-MM-DD or as anything parseTimeHorizon understands
+func onboardingTargetDate(raw string, now time.Time) (time.Time, error) {
+	if date, err := time.Parse("2006-01-02", strings.TrimSpace(raw)); err == nil {
+		if monthsUntil(now, date) < 1 {
+			return time.Time{}, fmt.Errorf("%s must be at least one month in the future", raw)
+		}
+		return date, nil
+	}
+	years, usedFallback := parseTimeHorizon(raw, now)
+	if usedFallback {
+		return time.Time{}, fmt.Errorf("%q is not a date (YYYY-MM-DD), a future year or a number of years", raw)
+	}
+	return now.AddDate(years, 0, 0), nil
+}
+
+// completeOnboarding saves what in gives of the profile and goals, and builds the starter plan from it
+func completeOnboarding(ctx context.Context, userID string, in onboardingInput, now time.Time) (OnboardingResult, error) {
+	r := OnboardingResult{Currency: activeCurrency().Code, Status: onboardingComplete, Missing: []string{}, Goals: []OnboardingGoal{}, NextActions: []string{}}
+	for _, field := range onboardingFields {
+		if !in.given[field] {
+			r.Missing = append(r.Missing, field)
+		}
+	}
+
+	// Risk needs the four questionnaire answers; years to retirement defaults from age
+	if in.given["age"] && in.answers.DownturnComfort != "" && in.answers.Experience != "" {
+		if in.given["existing_savings"] && in.given["existing_investments"] && in.savings+in.investments > 0 {
+			pct := in.investments / (in.savings + in.investments) * 100
+			in.answers.NetWorthInvestedPct = &pct
+		}
+		score, err := scoreRiskAnswers(in.answers)
+		if err != nil {
+			return r, err
+		}
+		r.RiskLevel, r.RiskScore, r.RiskBreakdown = score.Level, score.Total, score.Points
+	}
+
+	unlock := portfolioLocks.lock(userID)
+	portfolio, err := loadStoredPortfolio(ctx, userID)
+	if err != nil {
+		unlock()
+		return r, fmt.Errorf("could not load profile: %v", err)
+	}
+	if in.given["age"] {
+		portfolio.AgeGroup = ageGroupFor(in.age)
+	}
+	if in.given["monthly_savings"] {
+		portfolio.MonthlySavings = in.monthly
+	}
+	if in.given["previous_experience"] {
+		portfolio.Experience = in.answers.Experience
+	}
+	if in.given["existing_savings"] {
+		portfolio.SavingsAllocation = in.savings
+	}
+	if in.given["existing_investments"] {
+		portfolio.StockAllocation = in.investments
+	}
+	portfolio.TotalBalance = portfolio.SavingsAllocation + portfolio.StockAllocation
+	if r.RiskLevel != "" {
+		portfolio.RiskTolerance = string(r.RiskLevel)
+	}
+	portfolio.UpdatedAt = now
+	err = store.SavePortfolio(ctx, portfolio)
+	unlock()
+	if err != nil {
+		return r, fmt.Errorf("could not save profile: %v", err)
+	}
+	r.ProfileSaved = true
+
+	// Goals: required monthly at the assumed return from nothing saved, soonest first
+	existing, err := store.ListGoals(ctx, userKey(userID))
+	if err != nil {
+		return r, fmt.Errorf("could not load goals: %v", err)
+	}
+	returnRate := appConfig.Assumptions.EquityReturnPct
+	required := 0.0
+	for _, g := range in.goals {
+		target, _ := time.Parse("2006-01-02", g.TargetDate)
+		months := max(monthsUntil(now, target), 1)
+		g.UserID = userKey(userID)
+		g.MonthlyContribution = requiredMonthlyContribution(g.TargetAmount, 0, returnRate, float64(months))
+		g.InvestmentType = "diversified"
+		if float64(months) < onboardingSavingsYears*12 {
+			g.InvestmentType = "savings"
+		}
+		g.ID, g.CreatedAt = "goal_"+generateRandomID(), now
+		if i := slices.IndexFunc(existing, func(e storage.Goal) bool { return strings.EqualFold(e.Name, g.Name) }); i >= 0 {
+			g.ID, g.CreatedAt = existing[i].ID, existing[i].CreatedAt
+		}
+		if err := store.SaveGoal(ctx, g); err != nil {
+			return r, fmt.Errorf("could not save goal %q: %v", g.Name, err)
+		}
+		required += g.MonthlyContribution
+		r.Goals = append(r.Goals, OnboardingGoal{
+			GoalID:             g.ID,
+			Name:               g.Name,
+			TargetAmountUSD:    g.TargetAmount,
+			TargetDate:         g.TargetDate,
+			Months:             months,
+			InvestmentType:     g.InvestmentType,
+			RequiredMonthlyUSD: g.MonthlyContribution,
+		})
+	}
+	slices.SortStableFunc(r.Goals, func(a, b OnboardingGoal) int { return strings.Compare(a.TargetDate, b.TargetDate) })
+	if len(r.Goals) > 0 {
+		r.FirstGoal = &r.Goals[0]
+	}
+
+	// Emergency fund first: part of the monthly amount tops it up until it covers the target months
+	if in.given["monthly_income"] && in.given["monthly_savings"] && in.given["existing_savings"] {
+		stability := in.answers.IncomeStability
+		if stability == "" {
+			stability = "moderate"
+		}
+		target := float64(emergencyMonthsByStability[stability]) * (in.income - in.monthly)
+		r.EmergencyFundTargetUSD = target
+		if shortfall := target - in.savings; shortfall > 0 {
+			r.EmergencyMonthlyUSD = math.Min(in.monthly*onboardingEmergencyPct, shortfall)
+			r.NextActions = append(r.NextActions, fmt.Sprintf("Build the emergency fund: %s to go to cover %d months of expenses; put %s/month into savings until it's there (deposit_savings)",
+				formatMoney(shortfall), emergencyMonthsByStability[stability], formatMoney(r.EmergencyMonthlyUSD)))
+		}
+	}
+	if in.given["monthly_savings"] {
+		r.SuggestedMonthlyUSD = in.monthly - r.EmergencyMonthlyUSD
+	}
+
+	if r.RiskLevel != "" {
+		goal, years := "general_wealth", in.answers.YearsToRetirement
+		if r.FirstGoal != nil {
+			goal, years = r.FirstGoal.Name, max(r.FirstGoal.Months/12, 1)
+		}
+		plan := generateInvestmentPlan(goal, strconv.Itoa(years), r.RiskLevel, in.savings, r.SuggestedMonthlyUSD, userLocale(ctx, userID))
+		r.Allocation, _ = plan["recommended_allocation"].(map[string]string)
+		r.AllocationPercent, _ = plan["recommended_allocation_percent"].(map[string]float64)
+		r.EstimatedGrowthRate, _ = plan["estimated_growth_rate"].(string)
+		if r.SuggestedMonthlyUSD > 0 {
+			r.NextActions = append(r.NextActions, fmt.Sprintf("Start investing %s/month with start_automated_investing (%s strategy)",
+				formatMoney(r.SuggestedMonthlyUSD), strings.ToLower(string(r.RiskLevel))))
+		}
+	}
+	if r.FirstGoal != nil {
+		r.NextActions = append(r.NextActions, fmt.Sprintf("'%s' needs about %s/month to reach %s by %s; track it with get_goal_progress",
+			r.FirstGoal.Name, formatMoney(r.FirstGoal.RequiredMonthlyUSD), formatMoney(r.FirstGoal.TargetAmountUSD), r.FirstGoal.TargetDate))
+	}
+	if in.given["monthly_savings"] && required > in.monthly {
+		r.NextActions = append(r.NextActions, fmt.Sprintf("The goals together need %s/month, more than the %s/month available - use prioritize_multiple_goals to decide which come first",
+			formatMoney(required), formatMoney(in.monthly)))
+	}
+	if in.investments > 0 {
+		r.NextActions = append(r.NextActions, "Record what the existing investments are with add_holding so rebalancing and fee checks use them")
+	}
+
+	if len(r.Missing) > 0 {
+		r.Status = onboardingPartial
+		r.Message = fmt.Sprintf("Saved what was given. Still needed: %s - ask for these and call complete_onboarding again.", strings.Join(r.Missing, ", "))
+		if r.RiskLevel == "" {
+			r.Message += " The risk level and allocation need age, market_downturn_comfort and previous_experience."
+		}
+		return r, nil
+	}
+	r.Message = fmt.Sprintf("Onboarding complete: %s risk, %s/month to invest", r.RiskLevel, formatMoney(r.SuggestedMonthlyUSD))
+	if r.FirstGoal != nil {
+		r.Message += fmt.Sprintf(", first goal '%s' by %s", r.FirstGoal.Name, r.FirstGoal.TargetDate)
+	}
+	r.Message += "."
+	return r, nil
+}
@@ -0,0 +1,189 @@
+package main
+
+import (
+	"context"
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"log"
+	"math"
+	"sort"
+	"strings"
+
+	"github.com/becomeliminal/nim-go-sdk/core"
+	"github.com/becomeliminal/nim-go-sdk/tools"
+)
+
+// ============================================
+// FUND OVERLAP
+// ============================================
+// Overlap between two funds is the sum, over everything they both hold, of the
+// smaller of the two weights. Weights come from a bundled snapshot of popular
+// index funds; stocks outside each fund's top holdings are grouped into shared
+// buckets so the whole fund is covered.
+
+//go:embed data/index_funds.json
+var indexFundsJSON []byte
+
+// indexFund is one entry of data/index_funds.json
+type indexFund struct {
+	ID         string             `json:"id"`
+	Name       string             `json:"name"`
+	AssetClass string             `json:"asset_class"`
+	Tickers    []string           `json:"tickers"`
+	Aliases    []string           `json:"aliases"`
+	Holdings   map[string]float64 `json:"holdings"` // percent of the fund
+}
+
+// indexFunds is the bundled dataset, loaded once at startup
+var indexFunds = loadIndexFunds()
+
+// Overlap verdict thresholds, in percent
+const (
+	overlapRedundantPct = 70.0 // at or above: holding both adds little
+	overlapPartialPct   = 30.0 // at or above: some diversification, mostly the same core
+)
+
+func loadIndexFunds() (funds struct {
+	AsOf  string      `json:"as_of"`
+	Funds []indexFund `json:"funds"`
+}) {
+	if err := json.Unmarshal(indexFundsJSON, &funds); err != nil {
+		log.Fatalf("invalid data/index_funds.json: %v", err)
+	}
+	return funds
+}
+
+// findIndexFund matches a ticker, fund ID or alias case-insensitively
+func findIndexFund(identifier string) (indexFund, bool) {
+	key := strings.ToLower(strings.TrimSpace(identifier))
+	for _, f := range indexFunds.Funds {
+		if strings.EqualFold(f.ID, key) {
+			return f, true
+		}
+		for _, t := range f.Tickers {
+			if strings.EqualFold(t, key) {
+				return f, true
+			}
+		}
+		for _, a := range f.Aliases {
+			if a == key {
+				return f, true
+			}
+		}
+	}
+	return indexFund{}, false
+}
+
+// supportedFundTickers lists every ticker in the dataset, for "not in my database" replies
+func supportedFundTickers() []string {
+	var tickers []string
+	for _, f := range indexFunds.Funds {
+		tickers = append(tickers, f.Tickers...)
+	}
+	sort.Strings(tickers)
+	return tickers
+}
+
+// newFundOverlapTool reports how much two or more index funds hold in common
+func newFundOverlapTool() core.Tool {
+	return tools.New("portfolio_overlap_analyzer").
+		Description("Check whether index funds duplicate each other: approximate holdings overlap for every pair (e.g. VOO vs VTI vs QQQ) and whether holding both adds meaningful diversification. Covers popular S&P 500, total market, Nasdaq-100, total international and total bond funds").
+		Schema(tools.ObjectSchema(map[string]interface{}{
+			"funds": map[string]interface{}{
+				"type":        "array",
+				"description": "Two or more fund tickers or names, e.g. ['VOO', 'VTI', 'QQQ']",
+				"items":       tools.StringProperty("Fund ticker or name"),
+			},
+		}, "funds")).
+		HandlerFunc(func(ctx context.Context, input json.RawMessage) (interface{}, error) {
+			var params struct {
+				Funds []string `json:"funds"`
+			}
+			if err := json.Unmarshal(input, &params); err != nil {
+				return nil, fmt.Errorf("invalid input: %w", err)
+			}
+			if len(params.Funds) < 2 {
+				return nil, fmt.Errorf("invalid input: funds: give at least two funds to compare")
+			}
+			return analyzeFundOverlap(params.Funds), nil
+		}).
+		Build()
+}
+
+// analyzeFundOverlap compares every pair of recognized funds
+func analyzeFundOverlap(identifiers []string) FundOverlapResult {
+	r := FundOverlapResult{DataAsOf: indexFunds.AsOf, Funds: []OverlapFund{}, Pairs: []FundOverlap{}}
+	var found []indexFund
+	for _, id := range identifiers {
+		f, ok := findIndexFund(id)
+		if !ok {
+			r.NotFound = append(r.NotFound, id)
+			continue
+		}
+		found = append(found, f)
+		r.Funds = append(r.Funds, OverlapFund{Input: id, Name: f.Name, AssetClass: f.AssetClass})
+	}
+	if len(r.NotFound) > 0 {
+		r.SupportedFunds = supportedFundTickers()
+	}
+
+	for i := 0; i < len(found); i++ {
+		for j := i + 1; j < len(found); j++ {
+			r.Pairs = append(r.Pairs, compareFunds(r.Funds[i].Input, r.Funds[j].Input, found[i], found[j]))
+		}
+	}
+
+	switch {
+	case len(found) < 2:
+		r.Message = fmt.Sprintf("%s not in my database, so there's nothing to compare. Supported funds: %s.",
+			strings.Join(r.NotFound, ", "), strings.Join(r.SupportedFunds, ", "))
+		return r
+	case len(r.NotFound) > 0:
+		r.Message = fmt.Sprintf("%s not in my database; compared the rest. ", strings.Join(r.NotFound, ", "))
+	}
+	redundant := 0
+	for _, p := range r.Pairs {
+		if p.Verdict == "redundant" {
+			redundant++
+		}
+	}
+	if redundant == 0 {
+		r.Message += "No pair is largely redundant; each fund adds something."
+	} else {
+		r.Message += fmt.Sprintf("%d pair(s) overlap by %.0f%% or more; consider keeping just one fund from each such pair.", redundant, overlapRedundantPct)
+	}
+	return r
+}
+
+// compareFunds measures the overlap between two funds and judges whether holding both diversifies
+func compareFunds(inputA, inputB string, a, b indexFund) FundOverlap {
+	p := FundOverlap{FundA: inputA, FundB: inputB}
+	if a.ID == b.ID {
+		p.OverlapPercent = 100
+	} else {
+		for holding, weight := range a.Holdings {
+			p.OverlapPercent += min(weight, b.Holdings[holding])
+		}
+		p.OverlapPercent = math.Round(p.OverlapPercent*10) / 10
+	}
+
+	switch {
+	case p.OverlapPercent >= overlapRedundantPct:
+		p.Verdict = "redundant"
+		p.AddsDiversification = false
+		p.Explanation = fmt.Sprintf("%s and %s hold mostly the same stocks in similar weights; owning both is close to owning one", inputA, inputB)
+		if a.ID == b.ID {
+			p.Explanation = fmt.Sprintf("%s and %s track the same index", inputA, inputB)
+		}
+	case p.OverlapPercent >= overlapPartialPct:
+		p.Verdict = "partial"
+		p.AddsDiversification = true
+		p.Explanation = fmt.Sprintf("They share a large core (%.0f%%), so holding both mostly tilts toward the shared biggest names rather than broadening exposure", p.OverlapPercent)
+	default:
+		p.Verdict = "diversifying"
+		p.AddsDiversification = true
+		p.Explanation = fmt.Sprintf("Little in common (%.0f%%): %s and %s cover different parts of the market", p.OverlapPercent, a.Name, b.Name)
+	}
+	return p
+}
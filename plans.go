@@ -0,0 +1,505 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/becomeliminal/nim-go-sdk/core"
+	"github.com/becomeliminal/nim-go-sdk/tools"
+
+	"vibe-invest/storage"
+)
+
+// ============================================
+// AUTOMATED INVESTING PLANS
+// ============================================
+
+// planInvestmentTypes are the investment types advertised in the start_automated_investing schema.
+// A plan's strategy is its risk level, stored as the canonical RiskLevel.
+var planInvestmentTypes = []string{"savings", "etf_portfolio", "diversified"}
+
+// planInput is a validated start_automated_investing request
+type planInput struct {
+	MonthlyAmount  float64
+	InvestmentType string
+	Strategy       string
+	StartDate      time.Time
+	SkipOnSpikePct float64
+}
+
+// validatePlanInput checks every field and reports all problems at once.
+// start_date may be today or later; "today" is the caller's date in UTC.
+func validatePlanInput(monthlyAmount, investmentType, strategy, startDate, skipOnSpike string, now time.Time) (planInput, error) {
+	var v amountValidator
+	in := planInput{
+		MonthlyAmount:  planAmount(&v, monthlyAmount),
+		InvestmentType: v.oneOf("investment_type", investmentType, planInvestmentTypes),
+		Strategy:       string(v.riskLevel("strategy", strategy)),
+		SkipOnSpikePct: optionalPercent(&v, "skip_on_spending_spike_percent", skipOnSpike, 0, maxSpikeThresholdPct),
+	}
+
+	start, err := time.Parse("2006-01-02", strings.TrimSpace(startDate))
+	switch {
+	case err != nil:
+		v.fail("start_date", "%q is not a date: use YYYY-MM-DD", startDate)
+	case start.Before(startOfDay(now)):
+		v.fail("start_date", "%s is in the past", startDate)
+	}
+	in.StartDate = start
+
+	return in, v.err()
+}
+
+// planAmount applies the monthly_amount rules shared by plan creation and updates
+func planAmount(v *amountValidator, raw string) float64 {
+	amount := v.positive("monthly_amount", raw)
+	if amount > 0 && amount < appConfig.MinMonthlyInvest {
+		v.fail("monthly_amount", "must be at least %s (got %s)", formatMoney(appConfig.MinMonthlyInvest), formatMoney(amount))
+	}
+	return amount
+}
+
+// startOfDay truncates t to midnight UTC
+func startOfDay(t time.Time) time.Time {
+	y, m, d := t.UTC().Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+}
+
+// newListPlansTool answers "what automatic investments do I have set up?"
+func newListPlansTool() core.Tool {
+	return tools.New("list_automated_plans").
+		Description("List the user's automated investment plans with their status, optionally filtered by status").
+		Schema(tools.ObjectSchema(map[string]interface{}{
+			"status": tools.StringProperty("Optional filter: 'active', 'paused', or 'cancelled'"),
+		})).
+		Handler(func(ctx context.Context, toolParams *core.ToolParams) (*core.ToolResult, error) {
+			var params struct {
+				Status string `json:"status"`
+			}
+			if err := json.Unmarshal(toolParams.Input, &params); err != nil {
+				return &core.ToolResult{Success: false, Error: fmt.Sprintf("invalid input: %v", err)}, nil
+			}
+
+			var v amountValidator
+			status := ""
+			if strings.TrimSpace(params.Status) != "" {
+				status = v.oneOf("status", params.Status, storage.PlanStatuses)
+			}
+			if err := v.err(); err != nil {
+				return &core.ToolResult{Success: false, Error: err.Error()}, nil
+			}
+
+			all, err := listPlans(ctx, userKey(toolParams.UserID))
+			if err != nil {
+				return &core.ToolResult{Success: false, Error: fmt.Sprintf("could not load plans: %v", err)}, nil
+			}
+			plans := []planListing{}
+			for _, plan := range all {
+				if status != "" && plan.Status != status {
+					continue
+				}
+				listing, err := newPlanListing(ctx, plan)
+				if err != nil {
+					return &core.ToolResult{Success: false, Error: fmt.Sprintf("could not load plan executions: %v", err)}, nil
+				}
+				plans = append(plans, listing)
+			}
+
+			return &core.ToolResult{Success: true, Data: map[string]interface{}{
+				"currency": activeCurrency().Code,
+				"plans":    plans,
+				"count":    len(plans),
+			}}, nil
+		}).
+		Build()
+}
+
+// planListing is a plan plus how its most recent scheduled run went
+type planListing struct {
+	storage.Plan
+	LastExecution        *storage.Execution `json:"last_execution,omitempty"`
+	LastExecutionSummary string             `json:"last_execution_summary,omitempty"`
+}
+
+func newPlanListing(ctx context.Context, plan storage.Plan) (planListing, error) {
+	listing := planListing{Plan: plan}
+	execs, err := store.ListExecutions(ctx, plan.ID)
+	if err != nil || len(execs) == 0 {
+		return listing, err
+	}
+	last := execs[len(execs)-1]
+	listing.LastExecution = &last
+	switch last.Status {
+	case storage.ExecutionSucceeded:
+		listing.LastExecutionSummary = fmt.Sprintf("last execution succeeded: %s for %s", formatMoney(last.Amount), last.Period)
+	case storage.ExecutionFailed:
+		listing.LastExecutionSummary = "last execution failed: " + last.LastError
+	case storage.ExecutionSkipped:
+		listing.LastExecutionSummary = fmt.Sprintf("skipped %s: %s", last.Period, last.LastError)
+	case storage.ExecutionExpired:
+		listing.LastExecutionSummary = fmt.Sprintf("execution for %s expired: %s", last.Period, last.LastError)
+	default:
+		listing.LastExecutionSummary = fmt.Sprintf("execution for %s in progress (attempt %d)", last.Period, last.Attempts)
+	}
+	return listing, nil
+}
+
+// newCancelPlanTool stops a plan for good; the record and its history are kept
+func newCancelPlanTool() core.Tool {
+	return tools.New("cancel_automated_plan").
+		Description("Cancel one of the user's automated investment plans so no further investments are made").
+		RequiresConfirmation().
+		SummaryTemplate("Cancel your automatic " + moneyTemplate("monthly_amount") + "/month investment plan").
+		Schema(tools.ObjectSchema(map[string]interface{}{
+			"plan_id":        tools.StringProperty("ID of the plan to cancel (from list_automated_plans)"),
+			"monthly_amount": tools.StringProperty("The plan's monthly amount from list_automated_plans as a plain number, shown in the confirmation prompt; it must match the stored plan"),
+		}, "plan_id", "monthly_amount")).
+		Handler(func(ctx context.Context, toolParams *core.ToolParams) (*core.ToolResult, error) {
+			var params struct {
+				PlanID        string `json:"plan_id"`
+				MonthlyAmount string `json:"monthly_amount"`
+			}
+			if err := json.Unmarshal(toolParams.Input, &params); err != nil {
+				return &core.ToolResult{Success: false, Error: fmt.Sprintf("invalid input: %v", err)}, nil
+			}
+			if frozen, err := isReadOnly(ctx, toolParams.UserID); err != nil {
+				return &core.ToolResult{Success: false, Error: fmt.Sprintf("could not check account status: %v", err)}, nil
+			} else if frozen {
+				return readOnlyResult(), nil
+			}
+
+			plan, result := loadOwnedPlan(ctx, toolParams.UserID, params.PlanID)
+			if result != nil {
+				return result, nil
+			}
+			if plan.Status == storage.PlanCancelled {
+				return planNotice(plan.ID, fmt.Sprintf("Plan %s is already cancelled - nothing more to do.", plan.ID)), nil
+			}
+			// The prompt showed the model's monthly_amount; only cancel when that was the stored plan's
+			if result := confirmedPlanAmount(plan, params.MonthlyAmount); result != nil {
+				return result, nil
+			}
+
+			now := time.Now().UTC()
+			plan.History = append(plan.History, storage.PlanChange{Time: now, Field: "status", OldValue: plan.Status, NewValue: storage.PlanCancelled})
+			plan.Status = storage.PlanCancelled
+			plan.CancelledAt = &now
+			plan.ResumeDate = ""
+			if err := store.SavePlan(ctx, plan); err != nil {
+				return &core.ToolResult{Success: false, Error: fmt.Sprintf("could not cancel plan: %v", err)}, nil
+			}
+			recordAudit(ctx, toolParams.UserID, "user", "cancel_plan", plan.ID)
+
+			return &core.ToolResult{Success: true, Data: map[string]interface{}{
+				"currency":     activeCurrency().Code,
+				"success":      true,
+				"plan_id":      plan.ID,
+				"status":       plan.Status,
+				"cancelled_at": now,
+				"message":      fmt.Sprintf("Your automatic %s/month investment plan has been cancelled. No further investments will be made.", formatMoney(plan.MonthlyAmount)),
+			}}, nil
+		}).
+		Build()
+}
+
+// confirmedPlanAmount refuses a confirmation whose monthly_amount wasn't the stored plan's,
+// so the user never confirms one plan while another is changed
+func confirmedPlanAmount(plan storage.Plan, raw string) *core.ToolResult {
+	shown, err := parseAmount(raw)
+	if err == nil && math.Abs(shown-plan.MonthlyAmount) < 0.005 {
+		return nil
+	}
+	return &core.ToolResult{Success: false, Error: fmt.Sprintf(
+		"Not cancelled yet: plan %s invests %s/month, but the confirmation showed %q. Call again with monthly_amount %.2f so the user confirms the right plan.",
+		plan.ID, formatMoney(plan.MonthlyAmount), raw, plan.MonthlyAmount)}
+}
+
+// newUpdatePlanTool changes the amount, strategy, investment type or spending-spike skip of an existing plan
+func newUpdatePlanTool() core.Tool {
+	return tools.New("update_automated_plan").
+		Description("Change the monthly amount, strategy, investment type, or spending-spike skip threshold of an automated investment plan. Unspecified fields are kept.").
+		RequiresConfirmation().
+		SummaryTemplate(updatePlanSummaryTemplate()).
+		Schema(tools.ObjectSchema(map[string]interface{}{
+			"plan_id":                        tools.StringProperty("ID of the plan to update (from list_automated_plans)"),
+			"monthly_amount":                 tools.StringProperty("Optional new amount to invest each month in the account currency"),
+			"strategy":                       tools.StringProperty("Optional new strategy, a risk level: " + strings.Join(riskLevelKeys(), ", ")),
+			"investment_type":                tools.StringProperty("Optional new investment type ('savings', 'etf_portfolio', 'diversified')"),
+			"skip_on_spending_spike_percent": tools.StringProperty(fmt.Sprintf("Optional: skip a month's investment when the last 30 days of spending are up more than this percent on the average of the three 30-day periods before (0 turns skipping off, max %.0f)", maxSpikeThresholdPct)),
+		}, "plan_id")).
+		Handler(func(ctx context.Context, toolParams *core.ToolParams) (*core.ToolResult, error) {
+			var params struct {
+				PlanID         string `json:"plan_id"`
+				MonthlyAmount  string `json:"monthly_amount"`
+				Strategy       string `json:"strategy"`
+				InvestmentType string `json:"investment_type"`
+				SkipOnSpike    string `json:"skip_on_spending_spike_percent"`
+			}
+			if err := json.Unmarshal(toolParams.Input, &params); err != nil {
+				return &core.ToolResult{Success: false, Error: fmt.Sprintf("invalid input: %v", err)}, nil
+			}
+			if frozen, err := isReadOnly(ctx, toolParams.UserID); err != nil {
+				return &core.ToolResult{Success: false, Error: fmt.Sprintf("could not check account status: %v", err)}, nil
+			} else if frozen {
+				return readOnlyResult(), nil
+			}
+
+			// Same rules as start_automated_investing, applied only to the fields being changed
+			var v amountValidator
+			updated := storage.Plan{}
+			if strings.TrimSpace(params.MonthlyAmount) != "" {
+				updated.MonthlyAmount = planAmount(&v, params.MonthlyAmount)
+			}
+			if strings.TrimSpace(params.Strategy) != "" {
+				updated.Strategy = string(v.riskLevel("strategy", params.Strategy))
+			}
+			if strings.TrimSpace(params.InvestmentType) != "" {
+				updated.InvestmentType = v.oneOf("investment_type", params.InvestmentType, planInvestmentTypes)
+			}
+			skipChanged := strings.TrimSpace(params.SkipOnSpike) != ""
+			updated.SkipOnSpikePct = optionalPercent(&v, "skip_on_spending_spike_percent", params.SkipOnSpike, 0, maxSpikeThresholdPct)
+			if updated.MonthlyAmount == 0 && updated.Strategy == "" && updated.InvestmentType == "" && !skipChanged && len(v.errs) == 0 {
+				v.fail("plan", "provide at least one of monthly_amount, strategy, investment_type, or skip_on_spending_spike_percent to change")
+			}
+			if err := v.err(); err != nil {
+				return &core.ToolResult{Success: false, Error: err.Error()}, nil
+			}
+
+			plan, result := loadOwnedPlan(ctx, toolParams.UserID, params.PlanID)
+			if result != nil {
+				return result, nil
+			}
+			if plan.Status == storage.PlanCancelled {
+				return planNotice(plan.ID, fmt.Sprintf("Plan %s is cancelled and can't be changed. Use start_automated_investing to create a new plan instead.", plan.ID)), nil
+			}
+
+			previous := planValues(plan)
+			now := time.Now().UTC()
+			changed := 0
+			change := func(field, oldValue, newValue string) {
+				if oldValue != newValue {
+					plan.History = append(plan.History, storage.PlanChange{Time: now, Field: field, OldValue: oldValue, NewValue: newValue})
+					changed++
+				}
+			}
+			if updated.MonthlyAmount != 0 {
+				change("monthly_amount", fmt.Sprintf("%.2f", plan.MonthlyAmount), fmt.Sprintf("%.2f", updated.MonthlyAmount))
+				plan.MonthlyAmount = updated.MonthlyAmount
+			}
+			if updated.Strategy != "" {
+				change("strategy", plan.Strategy, updated.Strategy)
+				plan.Strategy = updated.Strategy
+			}
+			if updated.InvestmentType != "" {
+				change("investment_type", plan.InvestmentType, updated.InvestmentType)
+				plan.InvestmentType = updated.InvestmentType
+			}
+			if skipChanged {
+				change("skip_on_spike_percent", fmt.Sprintf("%g", plan.SkipOnSpikePct), fmt.Sprintf("%g", updated.SkipOnSpikePct))
+				plan.SkipOnSpikePct = updated.SkipOnSpikePct
+			}
+			if changed == 0 {
+				return planNotice(plan.ID, fmt.Sprintf("Plan %s already has those settings - nothing changed.", plan.ID)), nil
+			}
+
+			if err := store.SavePlan(ctx, plan); err != nil {
+				return &core.ToolResult{Success: false, Error: fmt.Sprintf("could not update plan: %v", err)}, nil
+			}
+			recordAudit(ctx, toolParams.UserID, "user", "update_plan", plan.ID)
+
+			return &core.ToolResult{Success: true, Data: map[string]interface{}{
+				"currency": activeCurrency().Code,
+				"success":  true,
+				"plan_id":  plan.ID,
+				"previous": previous,
+				"updated":  planValues(plan),
+				"message": fmt.Sprintf("Plan updated: now investing %s/month in %s with a %s strategy.",
+					formatMoney(plan.MonthlyAmount), plan.InvestmentType, plan.Strategy),
+			}}, nil
+		}).
+		Build()
+}
+
+// updatePlanSummaryTemplate confirms update_automated_plan from the fields that will actually be
+// changed, so the user can't approve one change while another is applied
+func updatePlanSummaryTemplate() string {
+	return "Update your automatic investment plan {{.plan_id}}." +
+		"{{with .monthly_amount}} Monthly amount: " + moneyTemplateExpr(".") + ".{{end}}" +
+		"{{with .strategy}} Strategy: {{.}}.{{end}}" +
+		"{{with .investment_type}} Investment type: {{.}}.{{end}}" +
+		"{{with .skip_on_spending_spike_percent}} Skip a month when spending is up more than {{.}}%.{{end}}"
+}
+
+// newPausePlanTool skips investments for a while without losing the plan or its history
+func newPausePlanTool() core.Tool {
+	return tools.New("pause_automated_plan").
+		Description("Pause an automated investment plan, optionally until a resume date. No investments are made while paused.").
+		Schema(tools.ObjectSchema(map[string]interface{}{
+			"plan_id":     tools.StringProperty("ID of the plan to pause (from list_automated_plans)"),
+			"resume_date": tools.StringProperty("Optional date to resume automatically (YYYY-MM-DD, after today)"),
+		}, "plan_id")).
+		Handler(func(ctx context.Context, toolParams *core.ToolParams) (*core.ToolResult, error) {
+			var params struct {
+				PlanID     string `json:"plan_id"`
+				ResumeDate string `json:"resume_date"`
+			}
+			if err := json.Unmarshal(toolParams.Input, &params); err != nil {
+				return &core.ToolResult{Success: false, Error: fmt.Sprintf("invalid input: %v", err)}, nil
+			}
+			if frozen, err := isReadOnly(ctx, toolParams.UserID); err != nil {
+				return &core.ToolResult{Success: false, Error: fmt.Sprintf("could not check account status: %v", err)}, nil
+			} else if frozen {
+				return readOnlyResult(), nil
+			}
+
+			now := time.Now().UTC()
+			resumeDate := ""
+			if raw := strings.TrimSpace(params.ResumeDate); raw != "" {
+				resume, err := time.Parse("2006-01-02", raw)
+				switch {
+				case err != nil:
+					return &core.ToolResult{Success: false, Error: fmt.Sprintf("invalid input: resume_date: %q is not a date: use YYYY-MM-DD", params.ResumeDate)}, nil
+				case !resume.After(startOfDay(now)):
+					return &core.ToolResult{Success: false, Error: fmt.Sprintf("invalid input: resume_date: %s must be after today", raw)}, nil
+				}
+				resumeDate = resume.Format("2006-01-02")
+			}
+
+			plan, result := loadOwnedPlan(ctx, toolParams.UserID, params.PlanID)
+			if result != nil {
+				return result, nil
+			}
+			switch plan.Status {
+			case storage.PlanPaused:
+				return planNotice(plan.ID, fmt.Sprintf("Plan %s is already paused. Use resume_automated_plan to restart it.", plan.ID)), nil
+			case storage.PlanCancelled:
+				return planNotice(plan.ID, fmt.Sprintf("Plan %s is cancelled and can't be paused.", plan.ID)), nil
+			}
+
+			plan.History = append(plan.History, storage.PlanChange{Time: now, Field: "status", OldValue: plan.Status, NewValue: storage.PlanPaused})
+			plan.Status = storage.PlanPaused
+			plan.ResumeDate = resumeDate
+			if err := store.SavePlan(ctx, plan); err != nil {
+				return &core.ToolResult{Success: false, Error: fmt.Sprintf("could not pause plan: %v", err)}, nil
+			}
+			recordAudit(ctx, toolParams.UserID, "user", "pause_plan", plan.ID)
+
+			message := fmt.Sprintf("Your %s/month plan is paused until you resume it.", formatMoney(plan.MonthlyAmount))
+			if resumeDate != "" {
+				message = fmt.Sprintf("Your %s/month plan is paused and will resume automatically on %s.", formatMoney(plan.MonthlyAmount), resumeDate)
+			}
+			return &core.ToolResult{Success: true, Data: map[string]interface{}{
+				"currency":    activeCurrency().Code,
+				"success":     true,
+				"plan_id":     plan.ID,
+				"status":      plan.Status,
+				"resume_date": resumeDate,
+				"message":     message,
+			}}, nil
+		}).
+		Build()
+}
+
+// newResumePlanTool restarts a paused plan right away
+func newResumePlanTool() core.Tool {
+	return tools.New("resume_automated_plan").
+		Description("Resume a paused automated investment plan").
+		Schema(tools.ObjectSchema(map[string]interface{}{
+			"plan_id": tools.StringProperty("ID of the paused plan to resume (from list_automated_plans)"),
+		}, "plan_id")).
+		Handler(func(ctx context.Context, toolParams *core.ToolParams) (*core.ToolResult, error) {
+			var params struct {
+				PlanID string `json:"plan_id"`
+			}
+			if err := json.Unmarshal(toolParams.Input, &params); err != nil {
+				return &core.ToolResult{Success: false, Error: fmt.Sprintf("invalid input: %v", err)}, nil
+			}
+			if frozen, err := isReadOnly(ctx, toolParams.UserID); err != nil {
+				return &core.ToolResult{Success: false, Error: fmt.Sprintf("could not check account status: %v", err)}, nil
+			} else if frozen {
+				return readOnlyResult(), nil
+			}
+
+			plan, result := loadOwnedPlan(ctx, toolParams.UserID, params.PlanID)
+			if result != nil {
+				return result, nil
+			}
+			if plan.Status != storage.PlanPaused {
+				return planNotice(plan.ID, fmt.Sprintf("Plan %s is %s, not paused - nothing to resume.", plan.ID, plan.Status)), nil
+			}
+
+			plan.History = append(plan.History, storage.PlanChange{Time: time.Now().UTC(), Field: "status", OldValue: plan.Status, NewValue: storage.PlanActive})
+			plan.Status = storage.PlanActive
+			plan.ResumeDate = ""
+			if err := store.SavePlan(ctx, plan); err != nil {
+				return &core.ToolResult{Success: false, Error: fmt.Sprintf("could not resume plan: %v", err)}, nil
+			}
+			recordAudit(ctx, toolParams.UserID, "user", "resume_plan", plan.ID)
+
+			return &core.ToolResult{Success: true, Data: map[string]interface{}{
+				"currency": activeCurrency().Code,
+				"success":  true,
+				"plan_id":  plan.ID,
+				"status":   plan.Status,
+				"message":  fmt.Sprintf("Your %s/month plan is active again.", formatMoney(plan.MonthlyAmount)),
+			}}, nil
+		}).
+		Build()
+}
+
+// planValues is the editable part of a plan, for before/after comparisons
+func planValues(plan storage.Plan) map[string]interface{} {
+	return map[string]interface{}{
+		"monthly_amount":        formatMoney(plan.MonthlyAmount),
+		"strategy":              plan.Strategy,
+		"investment_type":       plan.InvestmentType,
+		"skip_on_spike_percent": plan.SkipOnSpikePct,
+	}
+}
+
+// loadOwnedPlan fetches a plan belonging to the user. Unknown or foreign plans come back
+// as a friendly notice result rather than an error, so the assistant can relay it.
+func loadOwnedPlan(ctx context.Context, userID, planID string) (storage.Plan, *core.ToolResult) {
+	planID = strings.TrimSpace(planID)
+	if planID == "" {
+		return storage.Plan{}, &core.ToolResult{Success: false, Error: "invalid input: plan_id: is required"}
+	}
+	plan, err := store.GetPlan(ctx, userKey(userID), planID)
+	if err == nil {
+		plan = resumeIfDue(ctx, plan, time.Now().UTC())
+	}
+	if errors.Is(err, storage.ErrNotFound) {
+		return storage.Plan{}, planNotice(planID, fmt.Sprintf("No plan %s was found on your account. Use list_automated_plans to see your plans.", planID))
+	}
+	if err != nil {
+		return storage.Plan{}, &core.ToolResult{Success: false, Error: fmt.Sprintf("could not load plan: %v", err)}
+	}
+	return plan, nil
+}
+
+// listPlans loads a user's plans with any whose resume_date has arrived resumed
+func listPlans(ctx context.Context, key string) ([]storage.Plan, error) {
+	plans, err := store.ListPlans(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now().UTC()
+	for i := range plans {
+		plans[i] = resumeIfDue(ctx, plans[i], now)
+	}
+	return plans, nil
+}
+
+// planNotice is a non-fatal outcome where nothing changed
+func planNotice(planID, message string) *core.ToolResult {
+	return &core.ToolResult{Success: true, Data: map[string]interface{}{
+		"success": false,
+		"plan_id": planID,
+		"message": message,
+	}}
+}
@@ -0,0 +1,82 @@
+package main
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"text/template"
+
+	"vibe-invest/storage"
+)
+
+func TestConfirmedPlanAmount(t *testing.T) {
+	withCurrency(t, "USD")
+	plan := storage.Plan{ID: "plan_1", MonthlyAmount: 500}
+	tests := []struct {
+		shown string
+		ok    bool
+	}{
+		{"500", true},
+		{"500.00", true},
+		{"$500", true},
+		{"750", false},
+		{"499.90", false},
+		{"", false},
+		{"five hundred", false},
+	}
+	for _, tt := range tests {
+		if got := confirmedPlanAmount(plan, tt.shown); (got == nil) != tt.ok {
+			t.Errorf("confirmedPlanAmount(%q) = %+v, want ok %v", tt.shown, got, tt.ok)
+		}
+	}
+}
+
+func TestUpdatePlanSummaryShowsTheChange(t *testing.T) {
+	withLocale(t, "en")
+	tests := []struct {
+		name, currency, input, want string
+	}{
+		{name: "amount", currency: "USD",
+			input: `{"plan_id": "plan_1", "monthly_amount": "750"}`,
+			want:  "Update your automatic investment plan plan_1. Monthly amount: $750."},
+		{name: "every field", currency: "EUR",
+			input: `{"plan_id": "plan_2", "monthly_amount": "300", "strategy": "aggressive", "investment_type": "etf_portfolio", "skip_on_spending_spike_percent": "25"}`,
+			want:  "Update your automatic investment plan plan_2. Monthly amount: 300 €. Strategy: aggressive. Investment type: etf_portfolio. Skip a month when spending is up more than 25%."},
+		{name: "a summary that disagrees is ignored", currency: "USD",
+			input: `{"plan_id": "plan_3", "monthly_amount": "9000", "change_summary_ui": "$90/month -> $100/month"}`,
+			want:  "Update your automatic investment plan plan_3. Monthly amount: $9000."},
+		{name: "the raise proposal's input", currency: "USD",
+			input: mustJSON(t, proposePlanIncrease(storage.Plan{ID: "plan_4", MonthlyAmount: 500}, 150).Input),
+			want:  "Update your automatic investment plan plan_4. Monthly amount: $650.00."},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			withCurrency(t, tt.currency)
+			tmpl, err := template.New("summary").Parse(updatePlanSummaryTemplate())
+			if err != nil {
+				t.Fatal(err)
+			}
+			var input map[string]interface{}
+			if err := json.Unmarshal([]byte(tt.input), &input); err != nil {
+				t.Fatal(err)
+			}
+			var b strings.Builder
+			if err := tmpl.Execute(&b, input); err != nil {
+				t.Fatal(err)
+			}
+			if b.String() != tt.want {
+				t.Errorf("summary = %q, want %q", b.String(), tt.want)
+			}
+		})
+	}
+}
+
+// mustJSON encodes v for a test
+func mustJSON(t *testing.T, v interface{}) string {
+	t.Helper()
+	b, err := json.Marshal(v)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(b)
+}
@@ -0,0 +1,261 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/becomeliminal/nim-go-sdk/core"
+	"github.com/becomeliminal/nim-go-sdk/tools"
+)
+
+// ============================================
+// MULTI-GOAL PRIORITIZATION
+// ============================================
+// Rule set: emergency reserves are funded first, then the remaining budget is
+// split across other goals weighted toward nearer target dates, never giving a
+// goal more than it needs. Horizon decides how each goal is invested.
+
+// goalTreatment is how a goal's money is invested given its horizon
+type goalTreatment struct {
+	maxMonths int // inclusive upper bound on months to target
+	label     string
+}
+
+var goalTreatments = []goalTreatment{
+	{36, "savings"},           // under 3 years: keep it in the vault
+	{120, "balanced"},         // 3-10 years: mix of stocks and bonds
+	{1 << 30, "equity_heavy"}, // 10+ years: mostly stocks
+}
+
+// maxProjectionMonths caps completion-date searches at 100 years
+const maxProjectionMonths = 1200
+
+// priorityGoal is one goal being prioritized
+type priorityGoal struct {
+	Name          string
+	TargetAmount  float64
+	CurrentAmount float64
+	TargetDate    time.Time
+	Emergency     bool
+}
+
+// GoalAllocation is one goal's share of the monthly budget
+type GoalAllocation struct {
+	GoalName                string  `json:"goal_name"`
+	Priority                int     `json:"priority"`
+	TargetAmountUSD         float64 `json:"target_amount_usd"`
+	CurrentAmountUSD        float64 `json:"current_amount_usd"`
+	TargetDate              string  `json:"target_date"`
+	MonthsToTarget          int     `json:"months_to_target"`
+	Treatment               string  `json:"treatment"` // savings, balanced or equity_heavy
+	AssumedAnnualReturn     float64 `json:"assumed_annual_return"`
+	RequiredMonthlyUSD      float64 `json:"required_monthly_usd"`
+	AllocatedMonthlyUSD     float64 `json:"allocated_monthly_usd"`
+	ProjectedCompletionDate string  `json:"projected_completion_date,omitempty"` // empty when never reached within 100 years
+	Feasible                bool    `json:"feasible"`
+	Note                    string  `json:"note,omitempty"`
+}
+
+// newPrioritizeGoalsTool splits one monthly budget across several goals
+func newPrioritizeGoalsTool() core.Tool {
+	return tools.New("prioritize_multiple_goals").
+		Description("Recommend how to split a monthly investing budget across several goals (emergency fund first, then nearer-term goals), with projected completion dates and infeasible goals flagged").
+		Schema(tools.ObjectSchema(map[string]interface{}{
+			"monthly_budget": tools.StringProperty("Total amount the user can invest each month in the account currency"),
+			"goals": map[string]interface{}{
+				"type":        "array",
+				"description": "Optional goals to prioritize; omit to use the user's saved goals",
+				"items": tools.ObjectSchema(map[string]interface{}{
+					"name":           tools.StringProperty("Goal name, e.g. 'Emergency fund', 'House deposit'"),
+					"target_amount":  tools.StringProperty("Target amount in the account currency"),
+					"target_date":    tools.StringProperty("Target date (YYYY-MM-DD)"),
+					"current_amount": tools.StringProperty("Amount already saved toward the goal in the account currency"),
+				}, "name", "target_amount", "target_date"),
+			},
+		}, "monthly_budget")).
+		Handler(func(ctx context.Context, toolParams *core.ToolParams) (*core.ToolResult, error) {
+			var params struct {
+				MonthlyBudget string `json:"monthly_budget"`
+				Goals         []struct {
+					Name          string `json:"name"`
+					TargetAmount  string `json:"target_amount"`
+					TargetDate    string `json:"target_date"`
+					CurrentAmount string `json:"current_amount"`
+				} `json:"goals"`
+			}
+			if err := json.Unmarshal(toolParams.Input, &params); err != nil {
+				return &core.ToolResult{Success: false, Error: fmt.Sprintf("invalid input: %v", err)}, nil
+			}
+
+			var v amountValidator
+			budget := v.positive("monthly_budget", params.MonthlyBudget)
+			var goals []priorityGoal
+			for i, g := range params.Goals {
+				field := fmt.Sprintf("goals[%d]", i)
+				target, err := time.Parse("2006-01-02", strings.TrimSpace(g.TargetDate))
+				if err != nil {
+					v.fail(field+".target_date", "%q is not a date: use YYYY-MM-DD", g.TargetDate)
+				}
+				goals = append(goals, priorityGoal{
+					Name:          g.Name,
+					TargetAmount:  v.positive(field+".target_amount", g.TargetAmount),
+					CurrentAmount: v.nonNegative(field+".current_amount", g.CurrentAmount, false),
+					TargetDate:    target,
+					Emergency:     isEmergencyGoal(g.Name),
+				})
+			}
+			if err := v.err(); err != nil {
+				return &core.ToolResult{Success: false, Error: err.Error()}, nil
+			}
+
+			if len(goals) == 0 {
+				saved, err := store.ListGoals(ctx, userKey(toolParams.UserID))
+				if err != nil {
+					return &core.ToolResult{Success: false, Error: fmt.Sprintf("could not load goals: %v", err)}, nil
+				}
+				for _, g := range saved {
+					target, _ := time.Parse("2006-01-02", g.TargetDate)
+					goals = append(goals, priorityGoal{Name: g.Name, TargetAmount: g.TargetAmount, TargetDate: target, Emergency: isEmergencyGoal(g.Name)})
+				}
+			}
+			if len(goals) == 0 {
+				return &core.ToolResult{Success: false, Error: "no goals to prioritize: pass goals or create one with create_investment_goal_with_transfer"}, nil
+			}
+
+			vaultRates.refresh(ctx, toolParams.UserID)
+			return &core.ToolResult{Success: true, Data: prioritizeGoals(goals, budget, time.Now())}, nil
+		}).
+		Build()
+}
+
+// prioritizeGoals applies the rule set and projects each goal under the resulting split
+func prioritizeGoals(goals []priorityGoal, budget float64, now time.Time) map[string]interface{} {
+	// Emergency reserves first, then by target date
+	sort.SliceStable(goals, func(i, j int) bool {
+		if goals[i].Emergency != goals[j].Emergency {
+			return goals[i].Emergency
+		}
+		return goals[i].TargetDate.Before(goals[j].TargetDate)
+	})
+
+	allocations := make([]GoalAllocation, len(goals))
+	months := make([]int, len(goals))
+	for i, g := range goals {
+		months[i] = max(monthsUntil(now, g.TargetDate), 1)
+		treatment, rate := goalTreatmentFor(months[i])
+		allocations[i] = GoalAllocation{
+			GoalName:            g.Name,
+			Priority:            i + 1,
+			TargetAmountUSD:     g.TargetAmount,
+			CurrentAmountUSD:    g.CurrentAmount,
+			TargetDate:          g.TargetDate.Format("2006-01-02"),
+			MonthsToTarget:      months[i],
+			Treatment:           treatment,
+			AssumedAnnualReturn: rate,
+			RequiredMonthlyUSD:  requiredMonthlyContribution(g.TargetAmount, g.CurrentAmount, rate, float64(months[i])),
+		}
+	}
+
+	remaining := budget
+	for i := range goals {
+		if goals[i].Emergency {
+			give := min(allocations[i].RequiredMonthlyUSD, remaining)
+			allocations[i].AllocatedMonthlyUSD = give
+			remaining -= give
+		}
+	}
+
+	// Time-weighted split of what's left: weight 1/months, capped at each goal's need.
+	// Capped goals release their excess to the others on the next pass.
+	for remaining > 0.005 {
+		totalWeight := 0.0
+		for i := range goals {
+			if !goals[i].Emergency && allocations[i].AllocatedMonthlyUSD < allocations[i].RequiredMonthlyUSD {
+				totalWeight += 1 / float64(months[i])
+			}
+		}
+		if totalWeight == 0 {
+			break
+		}
+		pool := remaining
+		for i := range goals {
+			a := &allocations[i]
+			if goals[i].Emergency || a.AllocatedMonthlyUSD >= a.RequiredMonthlyUSD {
+				continue
+			}
+			give := min(pool*(1/float64(months[i]))/totalWeight, a.RequiredMonthlyUSD-a.AllocatedMonthlyUSD)
+			a.AllocatedMonthlyUSD += give
+			remaining -= give
+		}
+	}
+
+	vaultAPY, live := vaultRates.current()
+	var infeasible []string
+	allocated := 0.0
+	for i := range allocations {
+		a := &allocations[i]
+		allocated += a.AllocatedMonthlyUSD
+		done := monthsToReach(a.TargetAmountUSD, a.CurrentAmountUSD, a.AllocatedMonthlyUSD, a.AssumedAnnualReturn)
+		if done >= 0 {
+			a.ProjectedCompletionDate = now.AddDate(0, done, 0).Format("2006-01-02")
+		}
+		a.Feasible = done >= 0 && done <= a.MonthsToTarget
+		switch {
+		case a.RequiredMonthlyUSD == 0:
+			a.Note = "Already funded"
+		case !a.Feasible:
+			infeasible = append(infeasible, a.GoalName)
+			a.Note = fmt.Sprintf("Needs %s/month to finish by %s; consider a later date or a smaller target",
+				formatMoney(a.RequiredMonthlyUSD), a.TargetDate)
+		}
+	}
+
+	return map[string]interface{}{
+		"currency":              activeCurrency().Code,
+		"monthly_budget_usd":    budget,
+		"allocated_monthly_usd": allocated,
+		"unallocated_usd":       budget - allocated,
+		"allocations":           allocations,
+		"infeasible_goals":      append([]string{}, infeasible...),
+		"vault_apy":             vaultAPY,
+		"vault_rate_source":     rateSource(live),
+		"rules":                 "Emergency reserves funded first; remaining budget weighted toward nearer target dates; under 3 years kept in savings, 3-10 years balanced, 10+ years equity-heavy",
+	}
+}
+
+// goalTreatmentFor picks the investment treatment and assumed return for a horizon in months
+func goalTreatmentFor(months int) (string, float64) {
+	vaultAPY, _ := vaultRates.current()
+	for _, t := range goalTreatments {
+		if months <= t.maxMonths {
+			switch t.label {
+			case "savings":
+				return t.label, vaultAPY
+			case "balanced":
+				return t.label, (appConfig.Assumptions.BondReturnPct + appConfig.Assumptions.EquityReturnPct) / 2
+			}
+			return t.label, appConfig.Assumptions.EquityReturnPct
+		}
+	}
+	return "equity_heavy", appConfig.Assumptions.EquityReturnPct
+}
+
+// isEmergencyGoal recognizes emergency reserves by name
+func isEmergencyGoal(name string) bool {
+	name = strings.ToLower(name)
+	return strings.Contains(name, "emergency") || strings.Contains(name, "rainy day")
+}
+
+// monthsToReach is how many months until contributions reach target, or -1 if not within 100 years
+func monthsToReach(target, initial, monthly, returnRate float64) int {
+	for m := 0; m <= maxProjectionMonths; m++ {
+		if futureValue(initial, monthly, returnRate, float64(m)) >= target {
+			return m
+		}
+	}
+	return -1
+}
@@ -0,0 +1,211 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/becomeliminal/nim-go-sdk/core"
+	"github.com/becomeliminal/nim-go-sdk/tools"
+
+	"vibe-invest/storage"
+)
+
+// ============================================
+// PROFILE UPDATES
+// ============================================
+// update_investment_profile writes through the portfolio store, so every tool
+// that reads the profile (risk level, monthly savings, balances) sees the new
+// values on its next call. Holdings are managed separately by add_holding.
+
+// ageGroups are the profile's age brackets
+var ageGroups = []string{"20s", "30s", "40s", "50s", "60+"}
+
+// ageGroupFor buckets an age into ageGroups; anyone under 30 is in the 20s bracket
+func ageGroupFor(age int) string {
+	switch {
+	case age < 30:
+		return "20s"
+	case age >= 60:
+		return "60+"
+	}
+	return ageGroups[age/10-2]
+}
+
+// newUpdateProfileTool changes any subset of the user's profile fields
+func newUpdateProfileTool() core.Tool {
+	return tools.New("update_investment_profile").
+		Description("Update the user's investment profile when they tell you something has changed (\"I can save $800 a month now\"): monthly savings, risk tolerance, age, investing experience, preferred language, or balances. Only the fields given change; the reply lists each change before and after so you can confirm it back to the user. Recommendation and projection tools use the new values from then on").
+		Schema(tools.ObjectSchema(map[string]interface{}{
+			"monthly_savings":    tools.StringProperty("Optional amount the user saves each month in the account currency"),
+			"risk_tolerance":     tools.StringProperty("Optional risk tolerance: conservative, moderate, moderate-to-aggressive or aggressive"),
+			"age_group":          tools.StringProperty("Optional age bracket: " + strings.Join(ageGroups, ", ")),
+			"age":                tools.StringProperty("Optional age in years, bucketed into an age group (use instead of age_group)"),
+			"experience":         tools.StringProperty("Optional investing experience: " + strings.Join(experienceLevels, ", ")),
+			"locale":             tools.StringProperty("Optional language for explanations and messages: " + strings.Join(supportedLocales, ", ")),
+			"total_balance":      tools.StringProperty("Optional total balance in the account currency (defaults to savings plus stock allocation when only those change)"),
+			"savings_allocation": tools.StringProperty("Optional amount held in savings in the account currency"),
+			"stock_allocation":   tools.StringProperty("Optional amount invested in stocks in the account currency"),
+		})).
+		Handler(func(ctx context.Context, toolParams *core.ToolParams) (*core.ToolResult, error) {
+			var params struct {
+				MonthlySavings    string `json:"monthly_savings"`
+				RiskTolerance     string `json:"risk_tolerance"`
+				AgeGroup          string `json:"age_group"`
+				Age               string `json:"age"`
+				Experience        string `json:"experience"`
+				Locale            string `json:"locale"`
+				TotalBalance      string `json:"total_balance"`
+				SavingsAllocation string `json:"savings_allocation"`
+				StockAllocation   string `json:"stock_allocation"`
+			}
+			if len(toolParams.Input) > 0 {
+				if err := json.Unmarshal(toolParams.Input, &params); err != nil {
+					return &core.ToolResult{Success: false, Error: fmt.Sprintf("invalid input: %v", err)}, nil
+				}
+			}
+			if frozen, err := isReadOnly(ctx, toolParams.UserID); err != nil {
+				return &core.ToolResult{Success: false, Error: fmt.Sprintf("could not check account status: %v", err)}, nil
+			} else if frozen {
+				return readOnlyResult(), nil
+			}
+
+			defer portfolioLocks.lock(toolParams.UserID)()
+			portfolio, err := loadStoredPortfolio(ctx, toolParams.UserID)
+			if err != nil {
+				return &core.ToolResult{Success: false, Error: fmt.Sprintf("could not load profile: %v", err)}, nil
+			}
+			before := portfolio
+			given := func(raw string) bool { return strings.TrimSpace(raw) != "" }
+
+			v := amountValidator{locale: portfolio.Locale}
+			if given(params.Locale) {
+				portfolio.Locale = v.oneOf("locale", params.Locale, supportedLocales)
+				v.locale = portfolio.Locale
+			}
+			if given(params.MonthlySavings) {
+				portfolio.MonthlySavings = v.nonNegative("monthly_savings", params.MonthlySavings, true)
+			}
+			if given(params.RiskTolerance) {
+				level, err := normalizeRiskLevel(params.RiskTolerance)
+				if err != nil {
+					v.fail("risk_tolerance", "%v", err)
+				}
+				portfolio.RiskTolerance = string(level)
+			}
+			switch {
+			case given(params.Age) && given(params.AgeGroup):
+				v.fail("age", "give age or age_group, not both")
+			case given(params.Age):
+				age, err := strconv.Atoi(strings.TrimSpace(params.Age))
+				if err != nil || age < 0 || age > 120 {
+					v.fail("age", "%q must be a whole number of years between 0 and 120", params.Age)
+				}
+				portfolio.AgeGroup = ageGroupFor(age)
+			case given(params.AgeGroup):
+				portfolio.AgeGroup = v.oneOf("age_group", params.AgeGroup, ageGroups)
+			}
+			if given(params.Experience) {
+				portfolio.Experience = v.oneOf("experience", params.Experience, experienceLevels)
+			}
+			if given(params.SavingsAllocation) {
+				portfolio.SavingsAllocation = v.nonNegative("savings_allocation", params.SavingsAllocation, true)
+			}
+			if given(params.StockAllocation) {
+				portfolio.StockAllocation = v.nonNegative("stock_allocation", params.StockAllocation, true)
+			}
+			allocated := portfolio.SavingsAllocation + portfolio.StockAllocation
+			switch {
+			case given(params.TotalBalance):
+				portfolio.TotalBalance = v.nonNegative("total_balance", params.TotalBalance, true)
+				if len(v.errs) == 0 && portfolio.TotalBalance < allocated-0.005 {
+					v.fail("total_balance", "%s is less than savings plus stock allocation (%s)", v.money(portfolio.TotalBalance), v.money(allocated))
+				}
+			case given(params.SavingsAllocation) || given(params.StockAllocation):
+				portfolio.TotalBalance = allocated
+			}
+			if err := v.err(); err != nil {
+				return &core.ToolResult{Success: false, Error: err.Error()}, nil
+			}
+
+			changes := []ProfileChange{}
+			money := func(field string, old, new float64) {
+				if old != new {
+					changes = append(changes, ProfileChange{Field: field, Before: formatMoney(old), After: formatMoney(new)})
+				}
+			}
+			text := func(field, old, new string) {
+				if old != new {
+					changes = append(changes, ProfileChange{Field: field, Before: old, After: new})
+				}
+			}
+			money("monthly_savings", before.MonthlySavings, portfolio.MonthlySavings)
+			text("risk_tolerance", before.RiskTolerance, portfolio.RiskTolerance)
+			text("age_group", before.AgeGroup, portfolio.AgeGroup)
+			text("experience", before.Experience, portfolio.Experience)
+			text("locale", before.Locale, portfolio.Locale)
+			money("total_balance", before.TotalBalance, portfolio.TotalBalance)
+			money("savings_allocation", before.SavingsAllocation, portfolio.SavingsAllocation)
+			money("stock_allocation", before.StockAllocation, portfolio.StockAllocation)
+			if len(changes) == 0 {
+				return &core.ToolResult{Success: true, Data: ProfileUpdateResult{Changes: changes, Message: "Nothing to change: the profile already has these values."}}, nil
+			}
+
+			portfolio.UpdatedAt = time.Now().UTC()
+			if err := store.SavePortfolio(ctx, portfolio); err != nil {
+				return &core.ToolResult{Success: false, Error: fmt.Sprintf("could not save profile: %v", err)}, nil
+			}
+			described := make([]string, len(changes))
+			for i, c := range changes {
+				described[i] = fmt.Sprintf("%s %s -> %s", c.Field, c.Before, c.After)
+			}
+			recordAudit(ctx, toolParams.UserID, "user", "update_profile", strings.Join(described, "; "))
+			return &core.ToolResult{Success: true, Data: ProfileUpdateResult{
+				Changes: changes,
+				Message: "Profile updated: " + strings.Join(described, ", ") + ".",
+			}}, nil
+		}).
+		Build()
+}
+
+// saveExperience stores the user's investing experience on their profile unless the account is
+// read-only. Failures are logged: the experience only sets the default explanation level.
+func saveExperience(ctx context.Context, userID, experience string) {
+	frozen, err := isReadOnly(ctx, userID)
+	if frozen {
+		return
+	}
+	defer portfolioLocks.lock(userID)()
+	portfolio := storage.Portfolio{}
+	if err == nil {
+		portfolio, err = loadStoredPortfolio(ctx, userID)
+	}
+	if err == nil && portfolio.Experience != experience {
+		portfolio.Experience, portfolio.UpdatedAt = experience, time.Now().UTC()
+		err = store.SavePortfolio(ctx, portfolio)
+	}
+	if err != nil {
+		log.Printf("⚠️  Failed to save experience for %s: %v\n", userKey(userID), err)
+	}
+}
+
+// profileAmount is the amount given in raw, or pick(profile) when raw is empty; source is field or "profile"
+func profileAmount(ctx context.Context, v *amountValidator, userID, field, raw string, pick func(InvestmentPortfolio) float64) (amount float64, source string, err error) {
+	if strings.TrimSpace(raw) != "" {
+		return v.nonNegative(field, raw, true), field, nil
+	}
+	portfolio, err := loadPortfolio(ctx, userID)
+	if errors.Is(err, errNoProfile) {
+		v.fail(field, noProfileMessage)
+		return 0, field, nil
+	}
+	if err != nil {
+		return 0, "", fmt.Errorf("could not load profile: %v", err)
+	}
+	return pick(portfolio), "profile", nil
+}
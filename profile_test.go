@@ -0,0 +1,80 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"vibe-invest/storage"
+)
+
+func TestProfileFallbacksWithoutProfile(t *testing.T) {
+	ctx := context.Background()
+	const user = "no_profile_user"
+
+	if _, err := loadPortfolio(ctx, user); !errors.Is(err, errNoProfile) {
+		t.Fatalf("loadPortfolio error = %v, want errNoProfile", err)
+	}
+
+	tests := []struct {
+		name  string
+		field string
+		run   func() error
+	}{
+		{"profile amount", "monthly_capacity", func() error {
+			var v amountValidator
+			if _, _, err := profileAmount(ctx, &v, user, "monthly_capacity", "", func(p InvestmentPortfolio) float64 { return p.MonthlySavings }); err != nil {
+				return err
+			}
+			return v.err()
+		}},
+		{"risk level", "risk_level", func() error {
+			_, _, err := resolveRiskLevel(ctx, user, "risk_level", "")
+			return err
+		}},
+		{"several fields", "monthly_contribution", func() error {
+			_, err := loadPortfolio(ctx, user)
+			return profileFieldError(err, "stock_percent", "monthly_contribution")
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.run()
+			var fe *fieldError
+			if !errors.As(err, &fe) || !strings.Contains(err.Error(), tt.field+": is required: no investment profile is saved") {
+				t.Errorf("error = %v, want %s required for want of a profile", err, tt.field)
+			}
+		})
+	}
+
+	// A given value never needs the profile
+	var v amountValidator
+	if amount, source, err := profileAmount(ctx, &v, user, "monthly_capacity", "250", nil); err != nil || v.err() != nil || amount != 250 || source != "monthly_capacity" {
+		t.Errorf("given amount = %v from %s (%v, %v), want 250 from monthly_capacity", amount, source, err, v.err())
+	}
+
+	if holdings, err := loadHoldings(ctx, user); err != nil || len(holdings) != 0 {
+		t.Errorf("holdings = %v, %v; want none", holdings, err)
+	}
+	if p, err := loadStoredPortfolio(ctx, user); err != nil || p.TotalBalance != 0 || p.MonthlySavings != 0 || p.RiskTolerance != "" {
+		t.Errorf("first write starts from %+v, %v; want an empty profile", p, err)
+	}
+}
+
+func TestProfileFallbacksWithProfile(t *testing.T) {
+	ctx := context.Background()
+	const user = "saved_profile_user"
+	if err := store.SavePortfolio(ctx, storage.Portfolio{UserID: userKey(user), TotalBalance: 12000, MonthlySavings: 400, RiskTolerance: string(RiskAggressive)}); err != nil {
+		t.Fatal(err)
+	}
+
+	var v amountValidator
+	amount, source, err := profileAmount(ctx, &v, user, "monthly_capacity", "", func(p InvestmentPortfolio) float64 { return p.MonthlySavings })
+	if err != nil || v.err() != nil || amount != 400 || source != "profile" {
+		t.Errorf("profile amount = %v from %s (%v, %v), want 400 from profile", amount, source, err, v.err())
+	}
+	if level, source, err := resolveRiskLevel(ctx, user, "risk_level", ""); err != nil || level != RiskAggressive || source != "profile" {
+		t.Errorf("risk level = %v from %s (%v), want %v from profile", level, source, err, RiskAggressive)
+	}
+}
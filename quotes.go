@@ -0,0 +1,192 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/becomeliminal/nim-go-sdk/core"
+	"github.com/becomeliminal/nim-go-sdk/tools"
+)
+
+// ============================================
+// MARKET DATA
+// ============================================
+// Quotes come from a quoteProvider. The HTTP provider calls
+// GET {QUOTE_API_URL}/quote?symbol=VOO with the key as a Bearer token and
+// expects a JSON object with price, change, change_percent, week_52_high and
+// week_52_low (plus optional currency and as_of). Without QUOTE_API_URL the tool
+// stays registered but answers that live quotes aren't enabled.
+
+// quoteTimeout bounds one provider call so a slow API can't stall the conversation
+const quoteTimeout = 5 * time.Second
+
+// tickerPattern accepts exchange tickers such as VOO, BRK.B or RDS-A
+var tickerPattern = regexp.MustCompile(`^[A-Z][A-Z0-9.\-]{0,9}$`)
+
+// errQuoteNotFound is returned by providers for tickers they don't cover
+var errQuoteNotFound = errors.New("ticker not found")
+
+// quoteProvider fetches the latest quote for a ticker
+type quoteProvider interface {
+	Quote(ctx context.Context, ticker string) (SecurityQuote, error)
+}
+
+// httpQuoteProvider is a quoteProvider backed by a JSON HTTP API
+type httpQuoteProvider struct {
+	baseURL string
+	apiKey  string
+	client  *http.Client
+}
+
+// newQuoteProvider builds the configured provider; nil when live quotes are disabled
+func newQuoteProvider() quoteProvider {
+	if appConfig.QuoteAPIURL == "" {
+		log.Println("📉 Live quotes disabled (set QUOTE_API_URL to enable lookup_security_quote)")
+		return nil
+	}
+	log.Printf("📈 Live quotes from %s\n", appConfig.QuoteAPIURL)
+	return &httpQuoteProvider{
+		baseURL: strings.TrimRight(appConfig.QuoteAPIURL, "/"),
+		apiKey:  appConfig.QuoteAPIKey,
+		client:  &http.Client{Timeout: quoteTimeout},
+	}
+}
+
+func (p *httpQuoteProvider) Quote(ctx context.Context, ticker string) (SecurityQuote, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, p.baseURL+"/quote?symbol="+url.QueryEscape(ticker), nil)
+	if err != nil {
+		return SecurityQuote{}, err
+	}
+	if p.apiKey != "" {
+		req.Header.Set("Authorization", "Bearer "+p.apiKey)
+	}
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return SecurityQuote{}, err
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode == http.StatusNotFound:
+		return SecurityQuote{}, errQuoteNotFound
+	case resp.StatusCode != http.StatusOK:
+		return SecurityQuote{}, fmt.Errorf("quote provider returned %s", resp.Status)
+	}
+	var body struct {
+		Price         *float64 `json:"price"`
+		Change        float64  `json:"change"`
+		ChangePercent float64  `json:"change_percent"`
+		Week52High    float64  `json:"week_52_high"`
+		Week52Low     float64  `json:"week_52_low"`
+		Currency      string   `json:"currency"`
+		AsOf          string   `json:"as_of"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return SecurityQuote{}, fmt.Errorf("decoding quote: %w", err)
+	}
+	if body.Price == nil {
+		return SecurityQuote{}, errQuoteNotFound
+	}
+	return SecurityQuote{
+		Ticker:        ticker,
+		Price:         *body.Price,
+		Change:        body.Change,
+		ChangePercent: body.ChangePercent,
+		Week52High:    body.Week52High,
+		Week52Low:     body.Week52Low,
+		Currency:      body.Currency,
+		AsOf:          body.AsOf,
+	}, nil
+}
+
+// quoteCache keeps recent quotes for QUOTE_CACHE_TTL so repeated questions don't hit the provider
+type quoteCache struct {
+	mu      sync.Mutex
+	entries map[string]cachedQuote
+}
+
+type cachedQuote struct {
+	quote   SecurityQuote
+	fetched time.Time
+}
+
+// get returns a cached quote younger than the TTL
+func (c *quoteCache) get(ticker string, now time.Time) (SecurityQuote, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	e, ok := c.entries[ticker]
+	if !ok || now.Sub(e.fetched) > appConfig.QuoteCacheTTL {
+		delete(c.entries, ticker)
+		return SecurityQuote{}, false
+	}
+	return e.quote, true
+}
+
+func (c *quoteCache) put(q SecurityQuote, now time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.entries[q.Ticker] = cachedQuote{quote: q, fetched: now}
+}
+
+// newQuoteTool looks up a ticker's latest price; provider may be nil when quotes are disabled
+func newQuoteTool(provider quoteProvider) core.Tool {
+	cache := &quoteCache{entries: map[string]cachedQuote{}}
+	return tools.New("lookup_security_quote").
+		Description("Look up a stock or fund's latest price, daily change and 52-week range by ticker (e.g. 'VOO'). Quotes may be delayed; use them for context, not to time trades").
+		Schema(tools.ObjectSchema(map[string]interface{}{
+			"ticker": tools.StringProperty("Ticker symbol, e.g. 'VOO' or 'AAPL'"),
+		}, "ticker")).
+		HandlerFunc(func(ctx context.Context, input json.RawMessage) (interface{}, error) {
+			var params struct {
+				Ticker string `json:"ticker"`
+			}
+			if err := json.Unmarshal(input, &params); err != nil {
+				return nil, fmt.Errorf("invalid input: %w", err)
+			}
+			ticker := strings.ToUpper(strings.TrimSpace(params.Ticker))
+			if !tickerPattern.MatchString(ticker) {
+				return nil, fmt.Errorf("invalid input: ticker: %q is not a ticker symbol", params.Ticker)
+			}
+
+			if provider == nil {
+				return map[string]interface{}{
+					"ticker":         ticker,
+					"quotes_enabled": false,
+					"message":        "Live quotes are not enabled on this server, so I can't look up current prices. Check a brokerage app or financial site for the latest price.",
+				}, nil
+			}
+
+			now := time.Now()
+			if q, ok := cache.get(ticker, now); ok {
+				q.Cached = true
+				return q, nil
+			}
+			ctx, cancel := context.WithTimeout(ctx, quoteTimeout)
+			defer cancel()
+			q, err := provider.Quote(ctx, ticker)
+			if errors.Is(err, errQuoteNotFound) {
+				return nil, fmt.Errorf("no quote found for %s: check the ticker symbol", ticker)
+			}
+			if err != nil {
+				log.Printf("⚠️  Quote lookup for %s failed: %v\n", ticker, err)
+				return nil, fmt.Errorf("the quote service is unavailable right now; try again shortly")
+			}
+			if q.Currency == "" {
+				q.Currency = activeCurrency().Code
+			}
+			q.Message = fmt.Sprintf("%s last traded at %.2f %s (%+.2f, %+.2f%% today); 52-week range %.2f-%.2f.",
+				ticker, q.Price, q.Currency, q.Change, q.ChangePercent, q.Week52Low, q.Week52High)
+			cache.put(q, now)
+			return q, nil
+		}).
+		Build()
+}
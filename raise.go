@@ -0,0 +1,180 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/becomeliminal/nim-go-sdk/core"
+	"github.com/becomeliminal/nim-go-sdk/tools"
+
+	"vibe-invest/storage"
+)
+
+// ============================================
+// SAVE-YOUR-RAISE
+// ============================================
+// Redirecting part of a raise to investing before lifestyle spending absorbs it.
+// The tool only proposes a plan change; update_automated_plan applies it after
+// the user confirms.
+
+// defaultRaiseRedirectPct is the share of a raise invested when none is given
+const defaultRaiseRedirectPct = 50.0
+
+// raiseProjectionYears are the horizons the raise delta is projected over
+var raiseProjectionYears = []float64{10, 20, 30}
+
+// newRaiseAllocatorTool quantifies investing part of a raise and proposes bumping a plan
+func newRaiseAllocatorTool() core.Tool {
+	return tools.New("raise_allocator").
+		Description("Plan how much of a salary raise to invest: the new monthly contribution, updated savings rate, 10/20/30-year projections of investing just the raise, and a proposed update to an automated plan for the user to confirm").
+		Schema(tools.ObjectSchema(map[string]interface{}{
+			"old_monthly_income":      tools.StringProperty("Monthly income before the raise in the account currency"),
+			"new_monthly_income":      tools.StringProperty("Monthly income after the raise in the account currency"),
+			"redirect_percent":        tools.StringProperty(fmt.Sprintf("Optional percentage of the raise to invest (default %.0f)", defaultRaiseRedirectPct)),
+			"current_monthly_savings": tools.StringProperty("Optional amount already saved or invested each month in the account currency; omit to use the user's profile"),
+			"plan_id":                 tools.StringProperty("Optional automated plan to propose increasing; omit to use the user's only active plan"),
+			"expected_return":         tools.StringProperty(fmt.Sprintf("Optional expected annual return percentage (defaults to the server's assumption, currently %g)", appConfig.Assumptions.EquityReturnPct)),
+		}, "old_monthly_income", "new_monthly_income")).
+		Handler(func(ctx context.Context, toolParams *core.ToolParams) (*core.ToolResult, error) {
+			var params struct {
+				OldMonthlyIncome      string `json:"old_monthly_income"`
+				NewMonthlyIncome      string `json:"new_monthly_income"`
+				RedirectPercent       string `json:"redirect_percent"`
+				CurrentMonthlySavings string `json:"current_monthly_savings"`
+				PlanID                string `json:"plan_id"`
+				ExpectedReturn        string `json:"expected_return"`
+			}
+			if err := json.Unmarshal(toolParams.Input, &params); err != nil {
+				return &core.ToolResult{Success: false, Error: fmt.Sprintf("invalid input: %v", err)}, nil
+			}
+
+			var v amountValidator
+			oldIncome := v.positive("old_monthly_income", params.OldMonthlyIncome)
+			newIncome := v.positive("new_monthly_income", params.NewMonthlyIncome)
+			redirect := optionalPercent(&v, "redirect_percent", params.RedirectPercent, defaultRaiseRedirectPct, 100)
+			current := v.nonNegative("current_monthly_savings", params.CurrentMonthlySavings, false)
+			returnRate := v.returnRate("expected_return", params.ExpectedReturn, false)
+			if len(v.errs) == 0 && newIncome <= oldIncome {
+				v.fail("new_monthly_income", "%s is not more than old_monthly_income (%s)", formatMoney(newIncome), formatMoney(oldIncome))
+			}
+			if err := v.err(); err != nil {
+				return &core.ToolResult{Success: false, Error: err.Error()}, nil
+			}
+
+			savingsSource := "user_reported"
+			if strings.TrimSpace(params.CurrentMonthlySavings) == "" {
+				portfolio, err := loadPortfolio(ctx, toolParams.UserID)
+				if err != nil {
+					return &core.ToolResult{Success: false, Error: profileFieldError(err, "current_monthly_savings").Error()}, nil
+				}
+				current, savingsSource = portfolio.MonthlySavings, "profile"
+			}
+
+			result := allocateRaise(oldIncome, newIncome, redirect, current, returnRate)
+			result.SavingsSource = savingsSource
+
+			plan, note, err := raisePlan(ctx, toolParams.UserID, params.PlanID)
+			if err != nil {
+				return &core.ToolResult{Success: false, Error: fmt.Sprintf("could not load plans: %v", err)}, nil
+			}
+			if plan != nil {
+				result.PlanProposal = proposePlanIncrease(*plan, result.AdditionalMonthlyUSD)
+			}
+			result.PlanNote = note
+			return &core.ToolResult{Success: true, Data: result}, nil
+		}).
+		Build()
+}
+
+// allocateRaise splits the raise and projects investing the redirected part on its own
+func allocateRaise(oldIncome, newIncome, redirectPct, currentSavings, returnRate float64) RaiseAllocation {
+	raise := newIncome - oldIncome
+	additional := raise * redirectPct / 100
+	r := RaiseAllocation{
+		Currency:              activeCurrency().Code,
+		OldMonthlyIncomeUSD:   oldIncome,
+		NewMonthlyIncomeUSD:   newIncome,
+		RaiseMonthlyUSD:       raise,
+		RedirectPercent:       redirectPct,
+		AdditionalMonthlyUSD:  additional,
+		KeptMonthlyUSD:        raise - additional,
+		CurrentSavingsUSD:     currentSavings,
+		NewMonthlySavingsUSD:  currentSavings + additional,
+		OldSavingsRatePercent: currentSavings / oldIncome * 100,
+		NewSavingsRatePercent: (currentSavings + additional) / newIncome * 100,
+		ExpectedReturnPercent: returnRate,
+	}
+	for _, years := range raiseProjectionYears {
+		p := calculateCompoundGrowth(0, additional, returnRate, years)
+		r.Projections = append(r.Projections, RaiseProjection{
+			Years:               years,
+			TotalContributedUSD: p.TotalContributed,
+			ProjectedTotalUSD:   p.ProjectedTotalUSD,
+			EarningsUSD:         p.ProjectedEarningsUSD,
+		})
+	}
+	last := r.Projections[len(r.Projections)-1]
+	r.Message = fmt.Sprintf("Investing %.0f%% of your %s/month raise adds %s/month, lifting your savings rate from %.1f%% to %.1f%% while you still keep %s/month more to spend. Over %.0f years that raise alone could grow to %s.",
+		redirectPct, formatMoney(raise), formatMoney(additional), r.OldSavingsRatePercent, r.NewSavingsRatePercent, formatMoney(r.KeptMonthlyUSD), last.Years, formatMoney(last.ProjectedTotalUSD))
+	return r
+}
+
+// raisePlan picks the plan to propose increasing: the one named, or the user's only active
+// plan. A nil plan comes with a note explaining why no proposal was made.
+func raisePlan(ctx context.Context, userID, planID string) (*storage.Plan, string, error) {
+	if strings.TrimSpace(planID) != "" {
+		plan, notice := loadOwnedPlan(ctx, userID, planID)
+		if notice != nil {
+			if !notice.Success {
+				return nil, "", errors.New(notice.Error)
+			}
+			return nil, notice.Data.(map[string]interface{})["message"].(string), nil
+		}
+		if plan.Status == storage.PlanCancelled {
+			return nil, fmt.Sprintf("Plan %s is cancelled; start a new plan with start_automated_investing instead.", plan.ID), nil
+		}
+		return &plan, "", nil
+	}
+
+	plans, err := listPlans(ctx, userKey(userID))
+	if err != nil {
+		return nil, "", err
+	}
+	var active []storage.Plan
+	for _, p := range plans {
+		if p.Status != storage.PlanCancelled {
+			active = append(active, p)
+		}
+	}
+	switch len(active) {
+	case 0:
+		return nil, "No automated plan yet: start_automated_investing can invest the additional amount each month.", nil
+	case 1:
+		return &active[0], "", nil
+	}
+	ids := make([]string, len(active))
+	for i, p := range active {
+		ids[i] = p.ID
+	}
+	return nil, fmt.Sprintf("You have %d plans (%s); pass plan_id to choose which one to increase.", len(active), strings.Join(ids, ", ")), nil
+}
+
+// proposePlanIncrease builds the update_automated_plan call for the user to confirm; nothing is changed here
+func proposePlanIncrease(plan storage.Plan, additional float64) *PlanProposal {
+	proposed := plan.MonthlyAmount + additional
+	return &PlanProposal{
+		PlanID:             plan.ID,
+		CurrentMonthlyUSD:  plan.MonthlyAmount,
+		ProposedMonthlyUSD: proposed,
+		Tool:               "update_automated_plan",
+		Input: map[string]interface{}{
+			"plan_id":        plan.ID,
+			"monthly_amount": fmt.Sprintf("%.2f", proposed),
+		},
+		Message: fmt.Sprintf("Proposed: raise plan %s from %s/month to %s/month. Confirm to apply it with update_automated_plan.",
+			plan.ID, formatMoney(plan.MonthlyAmount), formatMoney(proposed)),
+	}
+}
@@ -0,0 +1,360 @@
+package main
+
+import (
+	"cmp"
+	"fmt"
+	"math"
+	"slices"
+	"strconv"
+)
+
+// ============================================
+// REBALANCING DRIFT
+// ============================================
+
+// rebalanceAssets fixes the order assets are reported in
+var rebalanceAssets = []string{"stocks", "international", "reit", "bonds", "cash"}
+
+// allocationBand is a numeric target for one asset class, in percent of the portfolio: the
+// midpoint to rebalance back to, and how far either side of it still counts as on target
+type allocationBand struct {
+	TargetPct    float64 `json:"target_percent"`
+	TolerancePct float64 `json:"tolerance_percent"`
+}
+
+func (b allocationBand) low() float64  { return math.Max(b.TargetPct-b.TolerancePct, 0) }
+func (b allocationBand) high() float64 { return b.TargetPct + b.TolerancePct }
+
+// String formats the band for presentation: "50-60%", or "5%" without a tolerance
+func (b allocationBand) String() string {
+	if b.TolerancePct == 0 {
+		return formatPercentValue(b.TargetPct) + "%"
+	}
+	return formatPercentValue(b.low()) + "-" + formatPercentValue(b.high()) + "%"
+}
+
+// formatPercentValue prints a percentage to at most one decimal place, without trailing zeros
+func formatPercentValue(pct float64) string {
+	return strconv.FormatFloat(math.Round(pct*10)/10, 'f', -1, 64)
+}
+
+// riskAllocationModel is the target allocation per risk level. "stocks" is domestic equity;
+// international equity and REITs are separate slices. Targets sum to 100.
+var riskAllocationModel = map[RiskLevel]map[string]allocationBand{
+	RiskConservative: {
+		"stocks":        {22, 2},
+		"international": {9, 1},
+		"reit":          {4, 1},
+		"bonds":         {55, 5},
+		"cash":          {10, 5},
+	},
+	RiskModerate: {
+		"stocks":        {36, 3},
+		"international": {14, 2},
+		"reit":          {5, 1},
+		"bonds":         {35, 5},
+		"cash":          {10, 2.5},
+	},
+	RiskModerateToAggressive: {
+		"stocks":        {48, 3},
+		"international": {21, 2},
+		"reit":          {6, 1},
+		"bonds":         {20, 5},
+		"cash":          {5, 2.5},
+	},
+	RiskAggressive: {
+		"stocks":        {57, 3},
+		"international": {26, 2},
+		"reit":          {7, 1},
+		"bonds":         {7.5, 2.5},
+		"cash":          {2.5, 2.5},
+	},
+}
+
+// targetAllocationTable is riskAllocationModel's midpoints, for tools that only need the targets
+var targetAllocationTable = allocationMidpoints(riskAllocationModel)
+
+func allocationMidpoints(model map[RiskLevel]map[string]allocationBand) map[RiskLevel]map[string]float64 {
+	table := make(map[RiskLevel]map[string]float64, len(model))
+	for level, bands := range model {
+		table[level] = make(map[string]float64, len(bands))
+		for asset, b := range bands {
+			table[level][asset] = b.TargetPct
+		}
+	}
+	return table
+}
+
+// formatAllocation renders bands for presentation, keyed by asset class
+func formatAllocation(bands map[string]allocationBand) map[string]string {
+	formatted := make(map[string]string, len(bands))
+	for asset, b := range bands {
+		formatted[asset] = b.String()
+	}
+	return formatted
+}
+
+// uniformBands gives bare targets, such as a glide path point, the same tolerance of tol points
+func uniformBands(targets map[string]float64, tol float64) map[string]allocationBand {
+	bands := make(map[string]allocationBand, len(targets))
+	for asset, pct := range targets {
+		bands[asset] = allocationBand{TargetPct: pct, TolerancePct: tol}
+	}
+	return bands
+}
+
+// equitySplit divides a single equity share into domestic, international and REIT
+// slices, for targets such as the glide path that only know total stocks
+var equitySplit = map[string]float64{
+	"stocks":        0.65,
+	"international": 0.27,
+	"reit":          0.08,
+}
+
+// splitEquity spreads targets' "stocks" across equitySplit
+func splitEquity(targets map[string]float64) map[string]float64 {
+	split := make(map[string]float64, len(rebalanceAssets))
+	for asset, pct := range targets {
+		split[asset] = pct
+	}
+	for asset, share := range equitySplit {
+		split[asset] = targets["stocks"] * share
+	}
+	return split
+}
+
+// foldIntoStocks merges the given asset classes' bands into "stocks", for portfolios
+// that don't report those classes separately; targets and tolerances both add up
+func foldIntoStocks(targets map[string]allocationBand, assets ...string) map[string]allocationBand {
+	folded := make(map[string]allocationBand, len(targets))
+	for asset, b := range targets {
+		folded[asset] = b
+	}
+	for _, asset := range assets {
+		stocks := folded["stocks"]
+		stocks.TargetPct += folded[asset].TargetPct
+		stocks.TolerancePct += folded[asset].TolerancePct
+		folded["stocks"] = stocks
+		delete(folded, asset)
+	}
+	return folded
+}
+
+// assetDrift is one asset's position relative to its target band
+type assetDrift struct {
+	CurrentPercent    float64 `json:"current_percent"`
+	TargetPercent     float64 `json:"target_percent"`
+	BandLowPercent    float64 `json:"band_low_percent"`
+	BandHighPercent   float64 `json:"band_high_percent"`
+	DriftPercent      float64 `json:"drift_percent"`       // positive = overweight
+	BeyondBandPercent float64 `json:"beyond_band_percent"` // points past the nearest band edge; 0 inside the band
+	OutsideBand       bool    `json:"outside_band"`
+	DriftUSD          float64 `json:"drift_usd"` // dollars above (+) or below (-) target
+}
+
+// calculateAllocationDrift compares current holdings with target bands; returns the largest absolute
+// drift from target in points and whether any asset sits outside its band. Only assets with a target are compared.
+func calculateAllocationDrift(current map[string]float64, targets map[string]allocationBand, total float64) (map[string]assetDrift, float64, bool) {
+	drift := make(map[string]assetDrift, len(rebalanceAssets))
+	maxDrift, outside := 0.0, false
+	for _, asset := range rebalanceAssets {
+		b, ok := targets[asset]
+		if !ok {
+			continue
+		}
+		currentPct := current[asset] / total * 100
+		d := assetDrift{
+			CurrentPercent:    currentPct,
+			TargetPercent:     b.TargetPct,
+			BandLowPercent:    b.low(),
+			BandHighPercent:   b.high(),
+			DriftPercent:      currentPct - b.TargetPct,
+			BeyondBandPercent: math.Max(math.Max(b.low()-currentPct, currentPct-b.high()), 0),
+			DriftUSD:          current[asset] - b.TargetPct/100*total,
+		}
+		d.OutsideBand = d.BeyondBandPercent > 0
+		drift[asset] = d
+		maxDrift = math.Max(maxDrift, math.Abs(d.DriftPercent))
+		outside = outside || d.OutsideBand
+	}
+	return drift, maxDrift, outside
+}
+
+// RebalanceMove sells AmountUSD of an overweight asset class and buys an underweight one with it
+type RebalanceMove struct {
+	FromAsset string  `json:"from_asset"`
+	ToAsset   string  `json:"to_asset"`
+	AmountUSD float64 `json:"amount_usd"`
+	Amount    string  `json:"amount"`
+}
+
+// rebalanceMoves pairs overweight classes with underweight ones, largest first, until every class is
+// back at its target; empty when nothing is outside its band
+func rebalanceMoves(drift map[string]assetDrift, needed bool) []RebalanceMove {
+	moves := []RebalanceMove{}
+	if !needed {
+		return moves
+	}
+	type position struct {
+		asset  string
+		amount float64
+	}
+	var over, under []position
+	for _, asset := range rebalanceAssets {
+		d, ok := drift[asset]
+		switch {
+		case !ok:
+		case d.DriftUSD > 0.005:
+			over = append(over, position{asset, d.DriftUSD})
+		case d.DriftUSD < -0.005:
+			under = append(under, position{asset, -d.DriftUSD})
+		}
+	}
+	largestFirst := func(a, b position) int { return cmp.Compare(b.amount, a.amount) }
+	slices.SortStableFunc(over, largestFirst)
+	slices.SortStableFunc(under, largestFirst)
+
+	for i, j := 0, 0; i < len(over) && j < len(under); {
+		amount := math.Min(over[i].amount, under[j].amount)
+		if amount >= 0.005 {
+			moves = append(moves, RebalanceMove{FromAsset: over[i].asset, ToAsset: under[j].asset, AmountUSD: amount, Amount: formatMoney(amount)})
+		}
+		over[i].amount -= amount
+		under[j].amount -= amount
+		if over[i].amount < 0.005 {
+			i++
+		}
+		if under[j].amount < 0.005 {
+			j++
+		}
+	}
+	return moves
+}
+
+// driftActionItems turns the moves into plain-language steps the assistant can relay
+func driftActionItems(drift map[string]assetDrift, moves []RebalanceMove, deferred []DeferredMove, maxDrift float64) []string {
+	if len(moves) == 0 && len(deferred) == 0 {
+		return []string{fmt.Sprintf("No action needed: every asset class is inside its target band (largest drift %.1f percentage points from target)", maxDrift)}
+	}
+
+	items := make([]string, 0, len(moves)+len(deferred)+1)
+	for _, m := range moves {
+		from, to := drift[m.FromAsset], drift[m.ToAsset]
+		items = append(items, fmt.Sprintf("Sell %s of %s (%.1f%% vs %.1f%% target) and buy %s (%.1f%% vs %.1f%% target)",
+			m.Amount, m.FromAsset, from.CurrentPercent, from.TargetPercent, m.ToAsset, to.CurrentPercent, to.TargetPercent))
+	}
+	for _, d := range deferred {
+		items = append(items, fmt.Sprintf("Skip moving %s from %s to %s: %s", d.Amount, d.FromAsset, d.ToAsset, d.Reason))
+	}
+	if len(moves) == 0 {
+		return append(items, "No trade is large enough to be worth making right now")
+	}
+	return append(items, "execute_rebalance can make the savings transfers once the user confirms; trades between investments are done in their brokerage account")
+}
+
+// DeferredMove is a move too small to be worth a trade
+type DeferredMove struct {
+	RebalanceMove
+	Reason string `json:"reason"`
+}
+
+// deferSmallMoves holds back moves under minTrade, which cost more in attention and spreads than they fix
+func deferSmallMoves(moves []RebalanceMove, minTrade float64) ([]RebalanceMove, []DeferredMove) {
+	kept := []RebalanceMove{}
+	var deferred []DeferredMove
+	for _, m := range moves {
+		if m.AmountUSD < minTrade {
+			deferred = append(deferred, DeferredMove{m, fmt.Sprintf("below the %s minimum trade; let new contributions or the next review correct it", formatMoney(minTrade))})
+			continue
+		}
+		kept = append(kept, m)
+	}
+	return kept, deferred
+}
+
+// overrideTolerance gives every class in targets the same tolerance of band points
+func overrideTolerance(targets map[string]allocationBand, band float64) map[string]allocationBand {
+	bands := make(map[string]allocationBand, len(targets))
+	for asset, b := range targets {
+		bands[asset] = allocationBand{TargetPct: b.TargetPct, TolerancePct: band}
+	}
+	return bands
+}
+
+// ContributionSplit is the part of a contribution that goes to one asset class
+type ContributionSplit struct {
+	Asset     string  `json:"asset"`
+	AmountUSD float64 `json:"amount_usd"`
+	Amount    string  `json:"amount"`
+}
+
+// maxContributionRebalanceMonths bounds how far ahead contribution-only rebalancing is projected
+const maxContributionRebalanceMonths = 120
+
+// splitContribution directs contribution at the classes furthest below target, measured against the
+// portfolio after the contribution; anything left once every class is at target follows the targets
+func splitContribution(current map[string]float64, targets map[string]allocationBand, total, contribution float64) map[string]float64 {
+	shortfalls := make(map[string]float64, len(targets))
+	sum := 0.0
+	for asset, b := range targets {
+		shortfalls[asset] = math.Max(b.TargetPct/100*(total+contribution)-current[asset], 0)
+		sum += shortfalls[asset]
+	}
+	split := make(map[string]float64, len(targets))
+	for asset, b := range targets {
+		if sum >= contribution {
+			split[asset] = contribution * shortfalls[asset] / sum
+		} else {
+			split[asset] = shortfalls[asset] + (contribution-sum)*b.TargetPct/100
+		}
+	}
+	return split
+}
+
+// contributionRebalance splits this month's contribution toward underweight classes and counts the months
+// of contributions, at that pace and with flat markets, until every class is back inside its band:
+// 0 when it already is, -1 when it takes longer than maxContributionRebalanceMonths
+func contributionRebalance(current map[string]float64, targets map[string]allocationBand, total, contribution float64) ([]ContributionSplit, int) {
+	first := splitContribution(current, targets, total, contribution)
+	splits := []ContributionSplit{}
+	for _, asset := range rebalanceAssets {
+		if amount := first[asset]; amount >= 0.005 {
+			splits = append(splits, ContributionSplit{Asset: asset, AmountUSD: amount, Amount: formatMoney(amount)})
+		}
+	}
+
+	if _, _, needed := calculateAllocationDrift(current, targets, total); !needed {
+		return splits, 0
+	}
+	holdings := make(map[string]float64, len(current))
+	for asset, value := range current {
+		holdings[asset] = value
+	}
+	for m := 1; m <= maxContributionRebalanceMonths; m++ {
+		for asset, amount := range splitContribution(holdings, targets, total, contribution) {
+			holdings[asset] += amount
+		}
+		total += contribution
+		if _, _, needed := calculateAllocationDrift(holdings, targets, total); !needed {
+			return splits, m
+		}
+	}
+	return splits, -1
+}
+
+// contributionActionItems describes contribution-only rebalancing in plain language
+func contributionActionItems(splits []ContributionSplit, months int, maxDrift float64) []string {
+	items := make([]string, 0, len(splits)+1)
+	for _, s := range splits {
+		items = append(items, fmt.Sprintf("Invest %s of this month's contribution in %s", s.Amount, s.Asset))
+	}
+	switch {
+	case months == 0:
+		items = append(items, fmt.Sprintf("No sells needed: every asset class is inside its target band (largest drift %.1f percentage points); steering contributions keeps it there", maxDrift))
+	case months > 0:
+		items = append(items, fmt.Sprintf("Directing contributions like this brings every class back inside its band in about %d month(s) without selling anything, if markets stay flat", months))
+	default:
+		items = append(items, fmt.Sprintf("Contributions alone would take more than %d years to close this drift; consider a one-time sell_and_buy rebalance", maxContributionRebalanceMonths/12))
+	}
+	return items
+}
@@ -0,0 +1,31 @@
+package main
+
+import "testing"
+
+func TestAllocationDriftDefaultBand(t *testing.T) {
+	// Moderate folds to stocks 55, bonds 35, cash 10; every class may drift 5 points either way
+	tests := []struct {
+		name        string
+		classes     map[string]float64
+		wantOutside bool
+	}{
+		{"on target", map[string]float64{"stocks": 55, "bonds": 35, "cash": 10}, false},
+		{"4 points over", map[string]float64{"stocks": 59, "bonds": 31, "cash": 10}, false},
+		{"at the band edge", map[string]float64{"stocks": 60, "bonds": 30, "cash": 10}, false},
+		{"6 points over", map[string]float64{"stocks": 61, "bonds": 29, "cash": 10}, true},
+		{"cash 6 points under", map[string]float64{"stocks": 58, "bonds": 38, "cash": 4}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			drift, _, outside, _ := allocationDriftOf(tt.classes, RiskModerate)
+			if outside != tt.wantOutside {
+				t.Errorf("outside = %v, want %v (%+v)", outside, tt.wantOutside, drift)
+			}
+			for asset, d := range drift {
+				if d.BandHighPercent-d.TargetPercent != appConfig.RebalanceBandPct {
+					t.Errorf("%s band = %v-%v around %v, want ±%v", asset, d.BandLowPercent, d.BandHighPercent, d.TargetPercent, appConfig.RebalanceBandPct)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,226 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/becomeliminal/nim-go-sdk/core"
+	"github.com/becomeliminal/nim-go-sdk/tools"
+)
+
+// ============================================
+// REBALANCE EXECUTION
+// ============================================
+// Turns rebalance_investment_portfolio moves into Liminal transfers once the
+// user confirms. Only the savings side of a move is a Liminal operation:
+//   - cash -> anything: withdraw_savings, then send_money to INVEST_RECIPIENT
+//     when one is configured (otherwise the user invests the withdrawn cash)
+//   - anything -> cash: deposit_savings, but only once the user says the sale
+//     proceeds are already in their wallet; selling is theirs to do
+//   - investment -> investment: a brokerage trade, returned as a manual step
+//
+// Moves run in order and stop at the first failure; everything after it is
+// reported as not attempted, so the user knows exactly what happened.
+
+// Rebalance move outcomes
+const (
+	moveSucceeded    = "succeeded"
+	moveFailed       = "failed"
+	moveNotAttempted = "not_attempted"
+	moveManual       = "manual"
+)
+
+// rebalanceCall is one Liminal call a move maps onto
+type rebalanceCall struct {
+	tool  string
+	input map[string]interface{}
+}
+
+// newExecuteRebalanceTool carries out the savings side of rebalancing moves after confirmation
+func newExecuteRebalanceTool(liminalExecutor core.ToolExecutor) core.Tool {
+	return tools.New("execute_rebalance").
+		Description("Carry out rebalancing moves from rebalance_investment_portfolio once the user says \"do it\". Moves out of cash withdraw from savings (and send the money to the investment account when one is configured); moves into cash deposit to savings once sale_proceeds_in_wallet confirms the user has sold; moves between investments can't be made through Liminal and come back as manual_steps. Moves run in order and stop at the first failure").
+		RequiresConfirmation().
+		SummaryTemplate(rebalanceSummaryTemplate()).
+		Schema(tools.ObjectSchema(map[string]interface{}{
+			"moves": map[string]interface{}{
+				"type":        "array",
+				"description": "The moves to make, as returned by rebalance_investment_portfolio",
+				"items": tools.ObjectSchema(map[string]interface{}{
+					"from_asset": tools.StringProperty("Asset class to move money out of: 'stocks', 'international', 'reit', 'bonds' or 'cash'"),
+					"to_asset":   tools.StringProperty("Asset class to move money into"),
+					"amount":     tools.StringProperty("Amount to move in the account currency"),
+				}, "from_asset", "to_asset", "amount"),
+			},
+			"sale_proceeds_in_wallet": tools.StringProperty("Optional 'true' once the user has sold the holdings being moved into cash and the proceeds are in their Liminal wallet; without it moves into cash are manual steps"),
+		}, "moves")).
+		Handler(func(ctx context.Context, toolParams *core.ToolParams) (*core.ToolResult, error) {
+			var params struct {
+				Moves []struct {
+					FromAsset string `json:"from_asset"`
+					ToAsset   string `json:"to_asset"`
+					Amount    string `json:"amount"`
+				} `json:"moves"`
+				ProceedsInWallet string `json:"sale_proceeds_in_wallet"`
+			}
+			if err := json.Unmarshal(toolParams.Input, &params); err != nil {
+				return &core.ToolResult{Success: false, Error: fmt.Sprintf("invalid input: %v", err)}, nil
+			}
+			if frozen, err := isReadOnly(ctx, toolParams.UserID); err != nil {
+				return &core.ToolResult{Success: false, Error: fmt.Sprintf("could not check account status: %v", err)}, nil
+			} else if frozen {
+				return readOnlyResult(), nil
+			}
+
+			var v amountValidator
+			if len(params.Moves) == 0 {
+				v.fail("moves", "is required: pass the moves from rebalance_investment_portfolio")
+			}
+			moves := make([]RebalanceMove, 0, len(params.Moves))
+			for i, raw := range params.Moves {
+				field := fmt.Sprintf("moves[%d]", i)
+				m := RebalanceMove{
+					FromAsset: v.oneOf(field+".from_asset", raw.FromAsset, rebalanceAssets),
+					ToAsset:   v.oneOf(field+".to_asset", raw.ToAsset, rebalanceAssets),
+					AmountUSD: v.positive(field+".amount", raw.Amount),
+				}
+				if m.FromAsset != "" && m.FromAsset == m.ToAsset {
+					v.fail(field+".to_asset", "must differ from from_asset")
+				}
+				m.Amount = formatMoney(m.AmountUSD)
+				moves = append(moves, m)
+			}
+			inWallet := false
+			if strings.TrimSpace(params.ProceedsInWallet) != "" {
+				inWallet = v.oneOf("sale_proceeds_in_wallet", params.ProceedsInWallet, []string{"true", "false"}) == "true"
+			}
+			if err := v.err(); err != nil {
+				return &core.ToolResult{Success: false, Error: err.Error()}, nil
+			}
+
+			r := executeRebalanceMoves(ctx, liminalExecutor, toolParams.UserID, toolParams.RequestID, moves, inWallet)
+			recordAudit(ctx, toolParams.UserID, "user", "execute_rebalance", r.Message)
+			return &core.ToolResult{Success: true, Data: r}, nil
+		}).
+		Build()
+}
+
+// rebalanceSummaryTemplate lists every move in the confirmation from the moves input itself, the
+// same moves the handler validates and runs, so the prompt can't describe something else
+func rebalanceSummaryTemplate() string {
+	return "Rebalance your portfolio: {{range $i, $m := .moves}}{{if $i}}; {{end}}move " + moneyTemplateExpr("$m.amount") +
+		" from {{$m.from_asset}} to {{$m.to_asset}}{{end}}" +
+		"{{with .sale_proceeds_in_wallet}}{{if eq . \"true\"}} (sale proceeds already in your wallet){{end}}{{end}}."
+}
+
+// executeRebalanceMoves runs each move's Liminal calls in order, stopping at the first failure.
+// requestID, when set, makes each call's request ID stable so a retried confirmation isn't sent twice.
+func executeRebalanceMoves(ctx context.Context, liminalExecutor core.ToolExecutor, userID, requestID string, moves []RebalanceMove, inWallet bool) ExecuteRebalanceResult {
+	r := ExecuteRebalanceResult{Currency: activeCurrency().Code, Moves: []RebalanceMoveOutcome{}, ManualSteps: []string{}}
+	stopped := false
+	for i, m := range moves {
+		out := RebalanceMoveOutcome{RebalanceMove: m, Calls: []string{}}
+		calls, manual, note := rebalanceCalls(m, inWallet)
+		switch {
+		case len(calls) == 0:
+			out.Status, out.Note = moveManual, manual
+			r.ManualSteps = append(r.ManualSteps, manual)
+		case stopped:
+			out.Status, out.Note = moveNotAttempted, "not attempted because an earlier move failed"
+		default:
+			out.Status, out.Note = moveSucceeded, note
+			for j, call := range calls {
+				id := "req_" + generateRandomID()
+				if requestID != "" {
+					id = fmt.Sprintf("%s_move%d_%d", requestID, i, j)
+				}
+				if err := rebalanceTransfer(ctx, liminalExecutor, userID, id, call); err != nil {
+					out.Status, out.Error, stopped = moveFailed, fmt.Sprintf("%s: %v", call.tool, err), true
+					if j > 0 {
+						out.Note = fmt.Sprintf("%s completed before %s failed; the money is in the wallet", strings.Join(out.Calls, ", "), call.tool)
+					} else {
+						out.Note = ""
+					}
+					break
+				}
+				out.Calls = append(out.Calls, call.tool)
+			}
+			if out.Status == moveSucceeded {
+				r.CompletedUSD += m.AmountUSD
+				r.Completed++
+				if manual != "" {
+					r.ManualSteps = append(r.ManualSteps, manual)
+				}
+			}
+		}
+		r.Moves = append(r.Moves, out)
+	}
+
+	failed := slices.IndexFunc(r.Moves, func(o RebalanceMoveOutcome) bool { return o.Status == moveFailed })
+	r.Stopped = failed >= 0
+	switch {
+	case r.Stopped:
+		f := r.Moves[failed]
+		r.Message = fmt.Sprintf("%d move(s) completed (%s), then moving %s from %s to %s failed (%s); the remaining moves were not attempted.",
+			r.Completed, formatMoney(r.CompletedUSD), f.Amount, f.FromAsset, f.ToAsset, f.Error)
+	case r.Completed > 0:
+		r.Message = fmt.Sprintf("%d move(s) completed, %s in total.", r.Completed, formatMoney(r.CompletedUSD))
+	default:
+		r.Message = "None of these moves can be made through Liminal."
+	}
+	if len(r.ManualSteps) > 0 {
+		r.Message += fmt.Sprintf(" %d step(s) are left for the user to do: see manual_steps.", len(r.ManualSteps))
+	}
+	return r
+}
+
+// rebalanceCalls maps a move onto Liminal calls. manual is what's left for the user to do (the whole
+// move when there are no calls); note describes a move that runs.
+func rebalanceCalls(m RebalanceMove, inWallet bool) (calls []rebalanceCall, manual, note string) {
+	transfer := func(tool string) rebalanceCall {
+		return rebalanceCall{tool, map[string]interface{}{"amount": fmt.Sprintf("%.2f", m.AmountUSD), "currency": appConfig.Currency}}
+	}
+	switch {
+	case m.FromAsset == "cash":
+		calls = []rebalanceCall{transfer("withdraw_savings")}
+		if appConfig.InvestRecipient == "" {
+			return calls, fmt.Sprintf("Invest the %s withdrawn to your wallet in %s", m.Amount, m.ToAsset),
+				"withdrawn from savings to the wallet"
+		}
+		send := transfer("send_money")
+		send.input["recipient"] = appConfig.InvestRecipient
+		send.input["note"] = fmt.Sprintf("InvestMate rebalance into %s", m.ToAsset)
+		return append(calls, send), fmt.Sprintf("Buy %s of %s with the %s sent to your investment account", m.Amount, m.ToAsset, m.Amount),
+			"withdrawn from savings and sent to the investment account"
+	case m.ToAsset == "cash" && inWallet:
+		return []rebalanceCall{transfer("deposit_savings")}, "", "sale proceeds deposited to savings"
+	case m.ToAsset == "cash":
+		return nil, fmt.Sprintf("Sell %s of %s, then deposit the proceeds to savings (run execute_rebalance again with sale_proceeds_in_wallet once they are in your wallet)", m.Amount, m.FromAsset), ""
+	}
+	return nil, fmt.Sprintf("Sell %s of %s and buy %s in your brokerage account", m.Amount, m.FromAsset, m.ToAsset), ""
+}
+
+// rebalanceTransfer makes one Liminal call
+func rebalanceTransfer(ctx context.Context, liminalExecutor core.ToolExecutor, userID, requestID string, call rebalanceCall) error {
+	raw, err := json.Marshal(call.input)
+	if err != nil {
+		return err
+	}
+	resp, err := liminalExecutor.Execute(ctx, &core.ExecuteRequest{
+		UserID:    userID,
+		Tool:      call.tool,
+		Input:     raw,
+		RequestID: requestID,
+	})
+	if err != nil {
+		return err
+	}
+	if !resp.Success {
+		return errors.New(resp.Error)
+	}
+	return nil
+}
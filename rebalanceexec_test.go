@@ -0,0 +1,45 @@
+package main
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"text/template"
+)
+
+func TestRebalanceSummaryListsTheMoves(t *testing.T) {
+	withLocale(t, "en")
+	tests := []struct {
+		name, currency, input, want string
+	}{
+		{name: "one move", currency: "USD",
+			input: `{"moves": [{"from_asset": "cash", "to_asset": "stocks", "amount": "1200"}]}`,
+			want:  "Rebalance your portfolio: move $1200 from cash to stocks."},
+		{name: "several moves, proceeds in wallet", currency: "EUR",
+			input: `{"moves": [{"from_asset": "stocks", "to_asset": "cash", "amount": "500"}, {"from_asset": "cash", "to_asset": "bonds", "amount": "250.50"}], "sale_proceeds_in_wallet": "true"}`,
+			want:  "Rebalance your portfolio: move 500 € from stocks to cash; move 250.50 € from cash to bonds (sale proceeds already in your wallet)."},
+		{name: "a moves_ui that disagrees is ignored", currency: "USD",
+			input: `{"moves": [{"from_asset": "cash", "to_asset": "stocks", "amount": "9000"}], "moves_ui": "move $90 from savings into stocks", "sale_proceeds_in_wallet": "false"}`,
+			want:  "Rebalance your portfolio: move $9000 from cash to stocks."},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			withCurrency(t, tt.currency)
+			tmpl, err := template.New("summary").Parse(rebalanceSummaryTemplate())
+			if err != nil {
+				t.Fatal(err)
+			}
+			var input map[string]interface{}
+			if err := json.Unmarshal([]byte(tt.input), &input); err != nil {
+				t.Fatal(err)
+			}
+			var b strings.Builder
+			if err := tmpl.Execute(&b, input); err != nil {
+				t.Fatal(err)
+			}
+			if b.String() != tt.want {
+				t.Errorf("summary = %q, want %q", b.String(), tt.want)
+			}
+		})
+	}
+}
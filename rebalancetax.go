@@ -0,0 +1,155 @@
+package main
+
+import (
+	"cmp"
+	"fmt"
+	"math"
+	"slices"
+)
+
+// ============================================
+// TAX-AWARE REBALANCING
+// ============================================
+// When the user says which account each holding sits in, sells are routed
+// through tax-advantaged accounts (IRA, 401k, HSA) first, where they realize
+// nothing. Whatever must be sold in a taxable account is reported separately,
+// with the realized gain estimated from the cost basis when it is known.
+// Holdings not assigned to an account are treated as taxable.
+
+// rebalanceAccountTypes are the account types holding_accounts accepts
+var rebalanceAccountTypes = []string{"taxable", "tax_advantaged"}
+
+// accountHolding is the part of one asset class held in one kind of account
+type accountHolding struct {
+	Asset       string
+	AccountType string
+	Value       float64
+	CostBasis   float64
+	HasBasis    bool
+}
+
+// TaxableMove is a move, or the part of one, that has to be sold in a taxable account
+type TaxableMove struct {
+	RebalanceMove
+	EstimatedGainUSD float64 `json:"estimated_gain_usd"`
+	EstimatedTaxUSD  float64 `json:"estimated_tax_usd"`
+	BasisKnown       bool    `json:"basis_known"` // false when some of the sale has no cost basis to estimate from
+	Warning          string  `json:"warning"`
+}
+
+// taxableLot is sellable taxable value of one asset with its share of gain
+type taxableLot struct {
+	value     float64
+	gainRatio float64 // gain per dollar sold
+	hasBasis  bool
+}
+
+// splitMovesByTax sources each move's sell from tax-advantaged holdings of that asset first, then from
+// taxable lots with the smallest gain per dollar, so the taxable part realizes as little gain as possible
+func splitMovesByTax(moves []RebalanceMove, holdings []accountHolding, current map[string]float64) ([]RebalanceMove, []TaxableMove) {
+	sheltered := map[string]float64{}
+	lots := map[string][]taxableLot{}
+	assigned := map[string]float64{}
+	for _, h := range holdings {
+		assigned[h.Asset] += h.Value
+		if h.AccountType == "tax_advantaged" {
+			sheltered[h.Asset] += h.Value
+			continue
+		}
+		lot := taxableLot{value: h.Value, hasBasis: h.HasBasis}
+		if h.HasBasis && h.Value > 0 {
+			lot.gainRatio = (h.Value - h.CostBasis) / h.Value
+		}
+		lots[h.Asset] = append(lots[h.Asset], lot)
+	}
+	for asset, value := range current {
+		if rest := value - assigned[asset]; rest >= 0.005 {
+			lots[asset] = append(lots[asset], taxableLot{value: rest})
+		}
+	}
+	for _, l := range lots {
+		slices.SortStableFunc(l, func(a, b taxableLot) int {
+			if a.hasBasis != b.hasBasis {
+				if a.hasBasis {
+					return -1
+				}
+				return 1
+			}
+			return cmp.Compare(a.gainRatio, b.gainRatio)
+		})
+	}
+
+	taxFree := []RebalanceMove{}
+	taxable := []TaxableMove{}
+	for _, m := range moves {
+		free := math.Min(m.AmountUSD, sheltered[m.FromAsset])
+		sheltered[m.FromAsset] -= free
+		if free >= 0.005 {
+			taxFree = append(taxFree, RebalanceMove{FromAsset: m.FromAsset, ToAsset: m.ToAsset, AmountUSD: free, Amount: formatMoney(free)})
+		}
+		rest := m.AmountUSD - free
+		if rest < 0.005 {
+			continue
+		}
+		t := TaxableMove{RebalanceMove: RebalanceMove{FromAsset: m.FromAsset, ToAsset: m.ToAsset, AmountUSD: rest, Amount: formatMoney(rest)}, BasisKnown: true}
+		for i, need := 0, rest; need >= 0.005 && i < len(lots[m.FromAsset]); i++ {
+			lot := &lots[m.FromAsset][i]
+			sold := math.Min(need, lot.value)
+			lot.value -= sold
+			need -= sold
+			if sold <= 0 {
+				continue
+			}
+			if !lot.hasBasis {
+				t.BasisKnown = false
+				continue
+			}
+			t.EstimatedGainUSD += sold * lot.gainRatio
+		}
+		t.EstimatedTaxUSD = math.Max(t.EstimatedGainUSD, 0) * taxableGainsRatePct / 100
+		t.Warning = taxableMoveWarning(t)
+		taxable = append(taxable, t)
+	}
+	return taxFree, taxable
+}
+
+// taxableMoveWarning states what a taxable sell is likely to cost
+func taxableMoveWarning(t TaxableMove) string {
+	sale := fmt.Sprintf("Selling %s of %s in a taxable account", t.Amount, t.FromAsset)
+	switch {
+	case t.EstimatedGainUSD <= -0.005 && t.BasisKnown:
+		return fmt.Sprintf("%s realizes a loss of about %s, which can offset other gains", sale, formatMoney(-t.EstimatedGainUSD))
+	case t.EstimatedGainUSD >= 0.005 && t.BasisKnown:
+		return fmt.Sprintf("%s realizes about %s of gains, roughly %s of tax at a %g%% long-term rate", sale, formatMoney(t.EstimatedGainUSD), formatMoney(t.EstimatedTaxUSD), taxableGainsRatePct)
+	case t.EstimatedGainUSD >= 0.005:
+		return fmt.Sprintf("%s realizes at least %s of gains (~%s tax); give cost_basis for every taxable holding for a full estimate", sale, formatMoney(t.EstimatedGainUSD), formatMoney(t.EstimatedTaxUSD))
+	case t.BasisKnown:
+		return sale + " realizes no gain at its cost basis"
+	default:
+		return sale + " may realize capital gains; give its cost_basis to estimate the tax"
+	}
+}
+
+// taxActionItems explains where sells happen and, when every sell is taxable, points to rebalancing
+// with contributions instead; months is contributionRebalance's estimate, used when contribution > 0
+func taxActionItems(taxFree []RebalanceMove, taxable []TaxableMove, contribution float64, months int) []string {
+	var items []string
+	if len(taxFree) > 0 {
+		items = append(items, "Make the tax-free moves inside your tax-advantaged accounts (IRA, 401k): buying and selling there realizes no gains")
+	}
+	for _, t := range taxable {
+		items = append(items, t.Warning)
+	}
+	if len(taxFree) > 0 || len(taxable) == 0 {
+		return items
+	}
+	switch {
+	case contribution > 0 && months > 0:
+		items = append(items, fmt.Sprintf("Every sell here is taxable: directing %s a month of new contributions to the underweight classes would get back in band in about %d month(s) without selling (mode contributions_only)", formatMoney(contribution), months))
+	case contribution > 0:
+		items = append(items, "Every sell here is taxable, and contributions alone would take years to close the drift; weigh the tax against how far off target you are")
+	default:
+		items = append(items, "Every sell here is taxable: to avoid realizing gains, pass monthly_contribution with mode contributions_only to rebalance with new money instead")
+	}
+	return items
+}
@@ -0,0 +1,1365 @@
+package main
+
+import "vibe-invest/storage"
+
+// ============================================
+// TYPED TOOL RESULTS
+// ============================================
+// Each result carries raw numbers (*_usd, *_percent) for charts alongside the
+// display strings the assistant reads back to the user.
+
+// BaselineComparison contrasts a projection with leaving the same money in the savings vault
+type BaselineComparison struct {
+	VaultAPY          float64 `json:"vault_apy"`
+	RateSource        string  `json:"rate_source"`
+	BaselineTotalUSD  float64 `json:"baseline_total_usd"`
+	ProjectedTotalUSD float64 `json:"projected_total_usd"`
+	DeltaUSD          float64 `json:"delta_usd"`
+	Multiple          float64 `json:"multiple"`
+	Summary           string  `json:"summary"`
+}
+
+// ProjectionResult is returned by calculate_investment_projection
+type ProjectionResult struct {
+	Currency             string             `json:"currency"` // ISO code for every amount below
+	InitialInvestment    float64            `json:"initial_investment"`
+	MonthlyContribution  float64            `json:"monthly_contribution"`
+	TotalContributed     float64            `json:"total_contributed"`
+	ProjectedEarnings    string             `json:"projected_earnings"`
+	ProjectedEarningsUSD float64            `json:"projected_earnings_usd"`
+	ProjectedTotal       string             `json:"projected_total"`
+	ProjectedTotalUSD    float64            `json:"projected_total_usd"`
+	Years                float64            `json:"years"`
+	Months               int                `json:"months"`
+	AnnualReturnRate     string             `json:"annual_return_rate"`
+	AnnualReturnPercent  float64            `json:"annual_return_percent"`
+	PowerOfCompounding   string             `json:"power_of_compounding"`
+	EarningsPercent      float64            `json:"earnings_percent"` // share of total for gains, of contributions for losses
+	Outcome              string             `json:"outcome"`          // "gain", "loss" or "break_even"
+	Warning              string             `json:"warning,omitempty"`
+	BaselineComparison   BaselineComparison `json:"baseline_comparison"`
+
+	// Today's-dollar view, filled in by withInflation; the figures above are nominal
+	InflationRatePercent    float64 `json:"inflation_rate_percent,omitempty"`
+	ProjectedTotalReal      string  `json:"projected_total_todays_dollars,omitempty"`
+	ProjectedTotalRealUSD   float64 `json:"projected_total_todays_dollars_usd,omitempty"`
+	TotalContributedRealUSD float64 `json:"total_contributed_todays_dollars_usd,omitempty"`
+	InflationNote           string  `json:"inflation_note,omitempty"`
+
+	// Contribution escalation, set when the monthly contribution rises each year
+	AnnualIncreasePercent    float64 `json:"annual_increase_percent,omitempty"`
+	FinalYearMonthlyUSD      float64 `json:"final_year_monthly_contribution_usd,omitempty"`
+	EscalationContributedUSD float64 `json:"escalation_contributed_usd,omitempty"` // contributed beyond a flat plan
+	EscalationNote           string  `json:"escalation_note,omitempty"`
+
+	// Year-by-year view, filled in by withSchedule when requested
+	Schedule          []ScheduleRow `json:"schedule,omitempty"`
+	CrossedMilestones []Milestone   `json:"crossed_milestones,omitempty"`
+
+	// Return scenarios, filled in by withScenarios when requested
+	Scenarios    []ProjectionScenario `json:"scenarios,omitempty"` // pessimistic, expected, optimistic
+	ScenarioNote string               `json:"scenario_note,omitempty"`
+
+	// After-tax view, filled in by withTaxes when an account type is given
+	AfterTax *TaxTreatment `json:"after_tax,omitempty"`
+}
+
+// ScheduleRow is one year mark of a projection schedule
+type ScheduleRow struct {
+	Year                float64 `json:"year"`
+	ContributionsToDate float64 `json:"contributions_to_date"`
+	Balance             float64 `json:"balance"`
+	EarningsToDate      float64 `json:"earnings_to_date"`
+}
+
+// Milestone is the first year mark at which a projected balance passes Amount
+type Milestone struct {
+	Amount float64 `json:"amount"`
+	Label  string  `json:"label"`
+	Year   float64 `json:"year"`
+}
+
+// ScenarioPoint is one year-end value in a projection scenario's chart series
+type ScenarioPoint struct {
+	Year     float64 `json:"year"`
+	ValueUSD float64 `json:"value_usd"`
+}
+
+// ProjectionScenario is calculate_investment_projection at one return assumption
+type ProjectionScenario struct {
+	Name                string          `json:"name"`
+	AnnualReturnPercent float64         `json:"annual_return_percent"`
+	EndingValueUSD      float64         `json:"ending_value_usd"`
+	EarningsUSD         float64         `json:"earnings_usd"`
+	Series              []ScenarioPoint `json:"series"`
+}
+
+// TaxTreatment is a projection's value after an account type's taxes
+type TaxTreatment struct {
+	AccountType            string   `json:"account_type"` // taxable, traditional or roth
+	MarginalTaxRatePercent float64  `json:"marginal_tax_rate_percent"`
+	PreTaxTotalUSD         float64  `json:"pre_tax_total_usd"` // for taxable accounts, after the yearly drag but before capital gains
+	AfterTaxTotal          string   `json:"after_tax_total"`
+	AfterTaxTotalUSD       float64  `json:"after_tax_total_usd"`
+	AfterTaxTotalRealUSD   float64  `json:"after_tax_total_todays_dollars_usd"`
+	TotalTaxUSD            float64  `json:"total_tax_usd"` // nominal projection minus after-tax total
+	TaxDragUSD             float64  `json:"tax_drag_usd,omitempty"`
+	DistributionTaxUSD     float64  `json:"distribution_tax_usd,omitempty"`
+	CapitalGainsTaxUSD     float64  `json:"capital_gains_tax_usd,omitempty"`
+	WithdrawalTaxUSD       float64  `json:"withdrawal_tax_usd,omitempty"`
+	Assumptions            []string `json:"assumptions"`
+	Note                   string   `json:"note"`
+}
+
+// SimulationYear is one point of the percentile series for charting
+type SimulationYear struct {
+	Year   float64 `json:"year"`
+	P10USD float64 `json:"p10_usd"`
+	P50USD float64 `json:"p50_usd"`
+	P90USD float64 `json:"p90_usd"`
+}
+
+// SimulationResult is returned by simulate_investment_outcomes
+type SimulationResult struct {
+	Currency              string           `json:"currency"`
+	RiskLevel             RiskLevel        `json:"risk_level"`
+	MeanReturnPercent     float64          `json:"mean_return_percent"`
+	VolatilityPercent     float64          `json:"volatility_percent"`
+	Paths                 int              `json:"paths"`
+	Years                 float64          `json:"years"`
+	Months                int              `json:"months"`
+	TotalContributedUSD   float64          `json:"total_contributed_usd"`
+	P10EndingUSD          float64          `json:"p10_ending_usd"`
+	P50EndingUSD          float64          `json:"p50_ending_usd"`
+	P90EndingUSD          float64          `json:"p90_ending_usd"`
+	DeterministicTotalUSD float64          `json:"deterministic_total_usd"` // flat mean return, as calculate_investment_projection would show
+	TargetAmountUSD       float64          `json:"target_amount_usd,omitempty"`
+	ProbabilityOfTarget   *float64         `json:"probability_of_target_percent,omitempty"`
+	YearlyPercentiles     []SimulationYear `json:"yearly_percentiles"`
+	Summary               string           `json:"summary"`
+
+	endings []float64 // every path's ending balance, for target probability
+}
+
+// RetirementReadiness is returned by retirement_readiness_check. Nominal figures are
+// future dollars; real figures are today's dollars, discounted monthly at InflationPercent.
+type RetirementReadiness struct {
+	Currency               string           `json:"currency"`
+	CurrentAge             int              `json:"current_age"`
+	RetirementAge          int              `json:"retirement_age"`
+	YearsToRetirement      int              `json:"years_to_retirement"`
+	AssumedReturnPercent   float64          `json:"assumed_return_percent"`
+	InflationPercent       float64          `json:"inflation_percent"`
+	WithdrawalRatePercent  float64          `json:"withdrawal_rate_percent"`
+	Projection             ProjectionResult `json:"projection"`
+	NestEggNominalUSD      float64          `json:"nest_egg_nominal_usd"`
+	NestEggRealUSD         float64          `json:"nest_egg_real_usd"`
+	AnnualIncomeNominalUSD float64          `json:"annual_income_nominal_usd"`
+	AnnualIncomeRealUSD    float64          `json:"annual_income_real_usd"`
+	DesiredIncomeRealUSD   float64          `json:"desired_income_real_usd"`    // as given, in today's dollars
+	DesiredIncomeNominal   float64          `json:"desired_income_nominal_usd"` // the same income in retirement-year dollars
+	IncomeGapRealUSD       float64          `json:"income_gap_real_usd"`        // negative = surplus
+	RequiredNestEggUSD     float64          `json:"required_nest_egg_nominal_usd"`
+	ExtraMonthlyNeededUSD  float64          `json:"extra_monthly_needed_usd"`
+	OnTrack                bool             `json:"on_track"`
+	Message                string           `json:"message"`
+}
+
+// AccountProjection is one account treatment in compare_account_types
+type AccountProjection struct {
+	Account             string  `json:"account"` // taxable, traditional or roth
+	AnnualDepositUSD    float64 `json:"annual_deposit_usd"`
+	AnnualPretaxCostUSD float64 `json:"annual_pretax_cost_usd"`
+	EndingBalanceUSD    float64 `json:"ending_balance_usd"`
+	AfterTaxValueUSD    float64 `json:"after_tax_value_usd"`
+	ExcessToTaxableUSD  float64 `json:"excess_to_taxable_usd,omitempty"` // yearly amount over the limit, invested taxable
+}
+
+// AccountComparison is returned by compare_account_types
+type AccountComparison struct {
+	Currency      string                 `json:"currency"`
+	Projections   []AccountProjection    `json:"projections"`
+	Winner        string                 `json:"winner"`
+	RunnerUp      string                 `json:"runner_up"`
+	DifferenceUSD float64                `json:"difference_usd"`
+	LimitExceeded bool                   `json:"limit_exceeded"`
+	Warning       string                 `json:"warning,omitempty"`
+	Assumptions   map[string]interface{} `json:"assumptions"`
+	Message       string                 `json:"message"`
+}
+
+// EmployerMatchProjection is what the employer match alone grows to, with no contributions of the user's own
+type EmployerMatchProjection struct {
+	Years                 int     `json:"years"`
+	ExpectedReturnPercent float64 `json:"expected_return_percent"`
+	CapturedMatchValueUSD float64 `json:"captured_match_value_usd"`
+	FullMatchValueUSD     float64 `json:"full_match_value_usd"`
+	LeftOnTableValueUSD   float64 `json:"left_on_table_value_usd"`
+}
+
+// EmployerMatchRecommendation is one ranked use of investable money
+type EmployerMatchRecommendation struct {
+	Rank   int    `json:"rank"`
+	Action string `json:"action"` // capture_full_match, pay_high_interest_debt, build_emergency_fund or invest_beyond_match
+	Reason string `json:"reason"`
+}
+
+// EmployerMatchResult is returned by employer_401k_match_calculator
+type EmployerMatchResult struct {
+	Currency                  string                        `json:"currency"`
+	SalaryUSD                 float64                       `json:"salary_usd"`
+	MatchPercent              float64                       `json:"match_percent"`
+	MatchCapPercent           float64                       `json:"match_cap_percent"`
+	ContributionPercent       float64                       `json:"contribution_percent"`
+	AnnualContributionUSD     float64                       `json:"annual_contribution_usd"`
+	MatchCapturedUSD          float64                       `json:"match_captured_usd"`
+	MatchLeftOnTableUSD       float64                       `json:"match_left_on_table_usd"`
+	FullMatchUSD              float64                       `json:"full_match_usd"`
+	ContributionToMaxMatchPct float64                       `json:"contribution_percent_for_full_match"`
+	CapturingFullMatch        bool                          `json:"capturing_full_match"`
+	LimitReached              bool                          `json:"limit_reached"`
+	Warning                   string                        `json:"warning,omitempty"`
+	Projection                EmployerMatchProjection       `json:"projection"`
+	Recommendations           []EmployerMatchRecommendation `json:"recommendations"`
+	Assumptions               map[string]interface{}        `json:"assumptions"`
+	Message                   string                        `json:"message"`
+}
+
+// DebtAssessment is one debt's place in the payoff order
+type DebtAssessment struct {
+	Order                 int     `json:"order"`
+	Name                  string  `json:"name"`
+	BalanceUSD            float64 `json:"balance_usd"`
+	APR                   float64 `json:"apr"`
+	GuaranteedReturnPct   float64 `json:"guaranteed_return_percent"` // paying it down earns its APR
+	MinimumPaymentUSD     float64 `json:"minimum_payment_usd"`
+	MinimumCoversInterest bool    `json:"minimum_covers_interest"`
+	Priority              bool    `json:"priority"`
+	Action                string  `json:"action"` // pay_down_first or minimum_only
+	Reason                string  `json:"reason"`
+	FirstMonthPaymentUSD  float64 `json:"first_month_payment_usd"`
+	PayoffMonth           int     `json:"payoff_month,omitempty"` // under the recommended plan; 0 = not within 10 years
+}
+
+// DebtScenario is one payoff-vs-invest strategy projected over 10 years
+type DebtScenario struct {
+	NetWorth5YrUSD   float64        `json:"net_worth_5yr_usd"`
+	NetWorth10YrUSD  float64        `json:"net_worth_10yr_usd"`
+	InvestedUSD      float64        `json:"invested_10yr_usd"`
+	RemainingDebtUSD float64        `json:"remaining_debt_10yr_usd"`
+	DebtFreeMonth    int            `json:"debt_free_month,omitempty"`
+	PayoffMonths     map[string]int `json:"payoff_months"`
+}
+
+// DebtVsInvestResult is returned by debt_vs_invest_analyzer
+type DebtVsInvestResult struct {
+	Currency              string                  `json:"currency"`
+	MonthlyAmountUSD      float64                 `json:"monthly_amount_usd"`
+	ExpectedReturnPercent float64                 `json:"expected_return_percent"`
+	HighAPRThreshold      float64                 `json:"high_apr_threshold_percent"`
+	Debts                 []DebtAssessment        `json:"debts"`
+	FirstMonthInvestUSD   float64                 `json:"first_month_invest_usd"`
+	Scenarios             map[string]DebtScenario `json:"scenarios"`
+	Recommended           string                  `json:"recommended"`
+	Message               string                  `json:"message"`
+}
+
+// EmergencyFundMonth is one row of an emergency fund funding schedule
+type EmergencyFundMonth struct {
+	Month          int     `json:"month"`
+	Date           string  `json:"date"` // YYYY-MM
+	DepositUSD     float64 `json:"deposit_usd"`
+	BalanceUSD     float64 `json:"balance_usd"`
+	CoverageMonths float64 `json:"coverage_months"`
+}
+
+// EmergencyFundResult is returned by emergency_fund_calculator
+type EmergencyFundResult struct {
+	Currency           string               `json:"currency"`
+	IncomeStability    string               `json:"income_stability"`
+	TargetMonths       int                  `json:"target_months"`
+	MonthlyExpensesUSD float64              `json:"monthly_expenses_usd"`
+	ExpenseSource      string               `json:"expense_source"` // user_reported or transaction_history
+	TargetAmountUSD    float64              `json:"target_amount_usd"`
+	CurrentSavingsUSD  float64              `json:"current_savings_usd"`
+	SavingsSource      string               `json:"savings_source"` // user_reported, savings_balance or unavailable
+	CoverageMonths     float64              `json:"coverage_months"`
+	Status             string               `json:"status"` // fully_funded or building
+	ShortfallUSD       float64              `json:"shortfall_usd,omitempty"`
+	MonthlySavingsUSD  float64              `json:"monthly_savings_usd"`
+	VaultAPY           float64              `json:"vault_apy"`
+	VaultRateSource    string               `json:"vault_rate_source"` // live or default
+	MonthsToFunded     int                  `json:"months_to_funded,omitempty"`
+	Schedule           []EmergencyFundMonth `json:"schedule"`
+	Message            string               `json:"message"`
+}
+
+// FireScenario is when a portfolio reaches a FIRE number under one set of assumptions
+type FireScenario struct {
+	FireNumberUSD        float64 `json:"fire_number_usd"`
+	AssumedReturnPercent float64 `json:"assumed_return_percent"`
+	Months               int     `json:"months"` // -1 = not within 100 years
+	Years                float64 `json:"years,omitempty"`
+	Date                 string  `json:"date,omitempty"` // YYYY-MM
+	Age                  float64 `json:"age,omitempty"`
+}
+
+// FireResult is returned by fire_number_calculator
+type FireResult struct {
+	Currency              string       `json:"currency"`
+	AnnualSpendingUSD     float64      `json:"annual_spending_usd"`
+	WithdrawalRatePercent float64      `json:"withdrawal_rate_percent"`
+	AssumedReturnPercent  float64      `json:"assumed_return_percent"`
+	CurrentSavingsUSD     float64      `json:"current_savings_usd"`
+	MonthlyContribution   float64      `json:"monthly_contribution_usd"`
+	FireNumberUSD         float64      `json:"fire_number_usd"`
+	Base                  FireScenario `json:"projected"`
+	LowerWithdrawal       FireScenario `json:"at_3_5pct_withdrawal"`
+	LowerReturn           FireScenario `json:"at_1pct_lower_return"`
+	Message               string       `json:"message"`
+}
+
+// WithdrawalYear is one point of a withdrawal balance trajectory
+type WithdrawalYear struct {
+	Year       float64 `json:"year"`
+	BalanceUSD float64 `json:"balance_usd"`
+}
+
+// WithdrawalPlan is returned by safe_withdrawal_planner
+type WithdrawalPlan struct {
+	Currency                 string           `json:"currency"`
+	StartingBalanceUSD       float64          `json:"starting_balance_usd"`
+	MonthlyWithdrawalUSD     float64          `json:"monthly_withdrawal_usd"`
+	AssumedReturnPercent     float64          `json:"assumed_return_percent"`
+	HorizonYears             float64          `json:"horizon_years"`
+	NeverDepletes            bool             `json:"never_depletes"` // growth covers every withdrawal
+	LastsHorizon             bool             `json:"lasts_horizon"`
+	DepletionMonths          int              `json:"depletion_months,omitempty"`
+	DepletionYears           float64          `json:"depletion_years,omitempty"`
+	DepletionAge             float64          `json:"depletion_age,omitempty"`
+	MaxSustainableMonthlyUSD float64          `json:"max_sustainable_monthly_usd"`
+	Trajectory               []WithdrawalYear `json:"trajectory"`
+	Message                  string           `json:"message"`
+}
+
+// EducationSavingsResult is returned by education_savings_projection
+type EducationSavingsResult struct {
+	Currency                string             `json:"currency"`
+	ChildAge                int                `json:"child_age"`
+	CollegeStartAge         int                `json:"college_start_age"`
+	YearsToCollege          int                `json:"years_to_college"`
+	AnnualCostTodayUSD      float64            `json:"annual_cost_today_usd"`
+	TuitionInflationPercent float64            `json:"tuition_inflation_percent"`
+	AssumedReturnPercent    float64            `json:"assumed_return_percent"`
+	YearlyCostsUSD          []float64          `json:"yearly_costs_usd"` // each college year, inflated
+	TotalCostTodayUSD       float64            `json:"total_cost_today_usd"`
+	TotalFutureCostUSD      float64            `json:"total_future_cost_usd"`
+	CurrentSavingsUSD       float64            `json:"current_savings_usd"`
+	RequiredMonthlyUSD      float64            `json:"required_monthly_usd"`
+	Sensitivity             map[string]float64 `json:"sensitivity"` // required monthly at return -2% and +2%
+	PlannedMonthlyUSD       *float64           `json:"planned_monthly_usd,omitempty"`
+	PlannedProjectedUSD     float64            `json:"planned_projected_usd,omitempty"`
+	ShortfallUSD            float64            `json:"shortfall_usd,omitempty"`
+	Message                 string             `json:"message"`
+}
+
+// FeeLevel is one fee's effect in fee_drag_calculator
+type FeeLevel struct {
+	FeePercent        float64 `json:"fee_percent"`
+	EndingUSD         float64 `json:"ending_usd"`
+	LostToFeeUSD      float64 `json:"lost_to_fees_usd"`
+	WealthConsumedPct float64 `json:"wealth_consumed_percent"` // share of the no-fee ending balance
+}
+
+// FeeDragResult is returned by fee_drag_calculator
+type FeeDragResult struct {
+	Currency            string     `json:"currency"`
+	InitialAmountUSD    float64    `json:"initial_amount_usd"`
+	MonthlyAdditionUSD  float64    `json:"monthly_addition_usd"`
+	Years               float64    `json:"years"`
+	GrossReturnPercent  float64    `json:"gross_return_percent"`
+	TotalContributedUSD float64    `json:"total_contributed_usd"`
+	NoFeeEndingUSD      float64    `json:"no_fee_ending_usd"`
+	Levels              []FeeLevel `json:"fee_levels"`
+	DifferenceUSD       float64    `json:"difference_usd,omitempty"` // compare_fee ending minus fee ending
+	FeeTiming           string     `json:"fee_timing"`
+	Message             string     `json:"message"`
+}
+
+// StrategyOutcome is one strategy's ending value in lump_sum_vs_dca_comparison
+type StrategyOutcome struct {
+	ExpectedUSD float64 `json:"expected_usd"`
+	DownsideUSD float64 `json:"downside_usd"` // market drops drop_percent in month one
+}
+
+// LumpSumVsDCAResult is returned by lump_sum_vs_dca_comparison
+type LumpSumVsDCAResult struct {
+	Currency              string          `json:"currency"`
+	LumpAmountUSD         float64         `json:"lump_amount_usd"`
+	DCAMonths             int             `json:"dca_months"`
+	DCAMonthlyUSD         float64         `json:"dca_monthly_usd"`
+	ExpectedReturnPercent float64         `json:"expected_return_percent"`
+	VolatilityPercent     float64         `json:"volatility_percent"`
+	CashAPY               float64         `json:"cash_apy"`         // earned by money waiting to be invested
+	CashRateSource        string          `json:"cash_rate_source"` // live or default
+	DropPercent           float64         `json:"drop_percent"`
+	HorizonYears          float64         `json:"horizon_years"`
+	LumpSum               StrategyOutcome `json:"lump_sum"`
+	DCA                   StrategyOutcome `json:"dca"`
+	ExpectedAdvantageUSD  float64         `json:"lump_sum_expected_advantage_usd"`
+	DownsideProtectionUSD float64         `json:"dca_downside_protection_usd"`
+	LumpSumTrailsDCAPct   float64         `json:"lump_sum_trails_dca_probability_percent"`
+	TradeOff              string          `json:"trade_off"`
+}
+
+// AccountHolding is an amount of one asset held in one account
+type AccountHolding struct {
+	Account   string  `json:"account"`
+	AmountUSD float64 `json:"amount_usd"`
+}
+
+// AssetLocation is where one asset class ends up
+type AssetLocation struct {
+	Asset            string           `json:"asset"`
+	TargetUSD        float64          `json:"target_usd"`
+	PreferredAccount string           `json:"preferred_account"`
+	Holdings         []AccountHolding `json:"holdings"`
+	Reason           string           `json:"reason"`
+}
+
+// AssetLocationPlan is returned by asset_location_advisor
+type AssetLocationPlan struct {
+	Currency      string                        `json:"currency"`
+	TotalUSD      float64                       `json:"total_usd"`
+	Accounts      map[string]map[string]float64 `json:"accounts"` // account -> asset -> USD
+	Placements    []AssetLocation               `json:"placements"`
+	Spillovers    []string                      `json:"spillovers"`
+	PriorityOrder string                        `json:"priority_order"`
+}
+
+// CryptoGuardrailResult is returned by crypto_allocation_guardrail
+type CryptoGuardrailResult struct {
+	Currency           string    `json:"currency"`
+	RiskLevel          RiskLevel `json:"risk_level"`
+	RiskSource         string    `json:"risk_source"` // user_provided or profile
+	TotalInvestableUSD float64   `json:"total_investable_usd"`
+	CryptoHoldingsUSD  float64   `json:"crypto_holdings_usd"`
+	CurrentPercent     float64   `json:"current_percent"`
+	PlannedPurchaseUSD float64   `json:"planned_purchase_usd,omitempty"`
+	MaxPurchaseUSD     float64   `json:"max_purchase_within_cap_usd,omitempty"`
+	AfterPurchaseUSD   float64   `json:"after_purchase_usd"`
+	AfterPercent       float64   `json:"after_purchase_percent"`
+	CapPercent         float64   `json:"cap_percent"`
+	CapUSD             float64   `json:"cap_usd"`
+	DistancePercent    float64   `json:"distance_from_cap_percent"` // positive = over
+	Status             string    `json:"status"`                    // within_cap or over_cap
+	TrimUSD            float64   `json:"trim_usd,omitempty"`
+	RoomToAddUSD       float64   `json:"room_to_add_usd,omitempty"`
+	Warning            string    `json:"warning,omitempty"`
+	Message            string    `json:"message"`
+}
+
+// DividendTimeline is how long saving takes to reach a dividend portfolio at one growth rate
+type DividendTimeline struct {
+	GrowthPercent float64 `json:"growth_percent"`
+	Months        int     `json:"months"` // -1 = not within 100 years
+	Years         float64 `json:"years,omitempty"`
+}
+
+// DividendIncomeResult is returned by dividend_income_planner
+type DividendIncomeResult struct {
+	Currency               string                `json:"currency"`
+	TargetMonthlyIncomeUSD float64               `json:"target_monthly_income_usd"`
+	DividendYieldPercent   float64               `json:"dividend_yield_percent"`
+	TotalReturnPercent     float64               `json:"total_return_percent"`
+	RequiredPortfolioUSD   float64               `json:"required_portfolio_usd"`
+	MonthlyContribution    float64               `json:"monthly_contribution_usd"`
+	CurrentSavingsUSD      float64               `json:"current_savings_usd"`
+	Reinvested             DividendTimeline      `json:"drip_on"`
+	PaidOut                DividendTimeline      `json:"drip_off"`
+	RequiredMonthly        *RequiredContribution `json:"required_monthly,omitempty"` // when years is given
+	YieldWarning           string                `json:"yield_warning,omitempty"`
+	Message                string                `json:"message"`
+}
+
+// RoundUpResult is returned by round_up_savings_estimate
+type RoundUpResult struct {
+	Currency              string            `json:"currency"`
+	TransactionsAnalyzed  int               `json:"transactions_analyzed"`
+	PurchasesRounded      int               `json:"purchases_rounded"`
+	ExcludedTransactions  int               `json:"excluded_transactions"` // transfers, deposits and incoming money
+	WindowDays            float64           `json:"window_days"`
+	WidenedLookback       bool              `json:"widened_lookback"`
+	SampleTooSmall        bool              `json:"sample_too_small"`
+	RoundUpTotalUSD       float64           `json:"round_up_total_usd"`
+	AverageRoundUpUSD     float64           `json:"average_round_up_usd"`
+	MonthlyPaceUSD        float64           `json:"monthly_pace_usd"`
+	ExpectedReturnPercent float64           `json:"expected_return_percent"`
+	TenYearProjection     *ProjectionResult `json:"ten_year_projection,omitempty"`
+	Message               string            `json:"message"`
+}
+
+// SpendingAnalysis is returned by analyze_real_spending_patterns
+type SpendingAnalysis struct {
+	Currency                    string             `json:"currency"`
+	Simulated                   bool               `json:"simulated"` // true when the figures are an example, not the user's history
+	AnalysisPeriodDays          float64            `json:"analysis_period_days"`
+	CoveredDays                 float64            `json:"covered_days"` // days of the period the history reaches; the averages use this
+	TransactionsAnalyzed        int                `json:"transactions_analyzed"`
+	OutflowsUSD                 map[string]float64 `json:"outflows_usd,omitempty"` // spending, transfer and savings_deposit totals over the window
+	AverageDailySpending        string             `json:"average_daily_spending"`
+	AverageDailySpendingUSD     float64            `json:"average_daily_spending_usd"`
+	MonthlySpending             string             `json:"monthly_spending"`
+	MonthlySpendingUSD          float64            `json:"monthly_spending_usd"`
+	MonthlyEssentialUSD         float64            `json:"monthly_essential_usd"`
+	MonthlyDiscretionaryUSD     float64            `json:"monthly_discretionary_usd"`
+	RecommendedMonthlyInvest    string             `json:"recommended_monthly_invest"`
+	RecommendedMonthlyInvestUSD float64            `json:"recommended_monthly_invest_usd"`
+	SavingsOpportunity          string             `json:"savings_opportunity"`
+	InvestmentStrategy          string             `json:"investment_strategy"`
+	PotentialAnnualGrowth       string             `json:"potential_annual_growth"`
+	ProjectedFirstYearUSD       float64            `json:"projected_first_year_usd"`
+	BaselineComparison          BaselineComparison `json:"baseline_comparison"`
+	Message                     string             `json:"message"`
+}
+
+// Subscription is one recurring charge found by detect_recurring_subscriptions
+type Subscription struct {
+	Counterparty string  `json:"counterparty"`
+	AmountUSD    float64 `json:"amount_usd"` // most recent charge
+	Cadence      string  `json:"cadence"`    // weekly, monthly, quarterly or annual
+	MonthlyUSD   float64 `json:"monthly_usd"`
+	Charges      int     `json:"charges"`
+	LastCharged  string  `json:"last_charged"`
+	NextExpected string  `json:"next_expected"`
+	PriceChanged bool    `json:"price_changed"`
+}
+
+// SubscriptionsResult is returned by detect_recurring_subscriptions
+type SubscriptionsResult struct {
+	Currency              string            `json:"currency"`
+	TransactionsAnalyzed  int               `json:"transactions_analyzed"`
+	Subscriptions         []Subscription    `json:"subscriptions"`
+	Count                 int               `json:"count"`
+	SkippedLargeCharges   int               `json:"skipped_large_charges"` // recurring but above SUBSCRIPTION_MAX_AMOUNT, e.g. rent
+	Lapsed                int               `json:"lapsed"`                // recurring until more than two periods ago, so likely cancelled
+	MonthlyTotalUSD       float64           `json:"monthly_total_usd"`
+	AnnualTotalUSD        float64           `json:"annual_total_usd"`
+	RedirectMonthlyUSD    float64           `json:"redirect_monthly_usd,omitempty"` // half the monthly total
+	ExpectedReturnPercent float64           `json:"expected_return_percent"`
+	TenYearProjection     *ProjectionResult `json:"ten_year_projection,omitempty"`
+	Message               string            `json:"message"`
+}
+
+// CategorySpending is one category's line in check_spending_anomalies
+type CategorySpending struct {
+	Category           string  `json:"category"`
+	CurrentUSD         float64 `json:"last_30_days_usd"`
+	TrailingAverageUSD float64 `json:"trailing_average_usd"`
+	DeltaUSD           float64 `json:"delta_usd"`
+	ChangePercent      float64 `json:"change_percent"` // 0 for a new category
+	NewCategory        bool    `json:"new_category"`
+	Flagged            bool    `json:"flagged"`
+}
+
+// SpendingAnomalyReport is returned by check_spending_anomalies and drives plan spike skips
+type SpendingAnomalyReport struct {
+	Currency           string             `json:"currency"`
+	PeriodStart        string             `json:"period_start"` // YYYY-MM-DD, 30 days before period_end
+	PeriodEnd          string             `json:"period_end"`
+	ThresholdPercent   float64            `json:"threshold_percent"`
+	BaselinePeriods    int                `json:"baseline_periods"` // 30-day periods of history averaged, up to 3
+	Categories         []CategorySpending `json:"categories"`
+	FlaggedCount       int                `json:"flagged_count"`
+	CurrentTotalUSD    float64            `json:"current_total_usd"`
+	TrailingAverageUSD float64            `json:"trailing_average_usd"`
+	TotalDeltaUSD      float64            `json:"total_delta_usd"`
+	TotalChangePercent float64            `json:"total_change_percent"`
+	Spike              bool               `json:"spike"` // total spending up more than the threshold
+	Message            string             `json:"message"`
+}
+
+// IncomeSender is one recurring source of income
+type IncomeSender struct {
+	Sender            string  `json:"sender"`
+	MonthsPaid        int     `json:"months_paid"`
+	MonthlyAverageUSD float64 `json:"monthly_average_usd"`
+	LastReceived      string  `json:"last_received"` // YYYY-MM-DD
+}
+
+// IncomeDetection is returned by get_detected_income and fills in income when a tool isn't given it
+type IncomeDetection struct {
+	Currency               string         `json:"currency"`
+	Detected               bool           `json:"detected"`
+	MonthlyIncome          string         `json:"monthly_income,omitempty"`
+	MonthlyIncomeUSD       float64        `json:"monthly_income_usd,omitempty"`
+	CoefficientOfVariation float64        `json:"coefficient_of_variation,omitempty"` // standard deviation of monthly totals over their mean
+	Stability              string         `json:"income_stability,omitempty"`         // stable, moderate or unstable
+	MonthsAnalyzed         int            `json:"months_analyzed"`
+	MonthlyTotalsUSD       []float64      `json:"monthly_totals_usd,omitempty"` // most recent whole month first
+	Sources                []IncomeSender `json:"sources"`
+	Message                string         `json:"message"`
+}
+
+// CashFlowEvent is one dated income, bill or investment in a cash flow forecast
+type CashFlowEvent struct {
+	Date        string  `json:"date"` // YYYY-MM-DD
+	Description string  `json:"description"`
+	AmountUSD   float64 `json:"amount_usd"` // negative for money leaving the wallet
+}
+
+// CashFlowPeriod is the lowest and closing balance of one stretch of a cash flow forecast
+type CashFlowPeriod struct {
+	Days              int     `json:"days"` // days from today to the end of the period
+	EndDate           string  `json:"end_date"`
+	MinimumBalanceUSD float64 `json:"minimum_balance_usd"`
+	MinimumDate       string  `json:"minimum_date"`
+	EndingBalanceUSD  float64 `json:"ending_balance_usd"`
+}
+
+// CashFlowForecast is returned by forecast_cash_flow and checked by affordability
+type CashFlowForecast struct {
+	Currency           string           `json:"currency"`
+	Days               int              `json:"days"`
+	StartingBalanceUSD float64          `json:"starting_balance_usd"`
+	EndingBalanceUSD   float64          `json:"ending_balance_usd"`
+	MinimumBalanceUSD  float64          `json:"minimum_balance_usd"`
+	MinimumDate        string           `json:"minimum_date"`
+	SafetyBufferUSD    float64          `json:"safety_buffer_usd"`
+	BelowSafetyBuffer  bool             `json:"below_safety_buffer"`
+	IncomeDetected     bool             `json:"income_detected"`
+	MonthlyIncomeUSD   float64          `json:"monthly_income_usd"`
+	MonthlyBillsUSD    float64          `json:"monthly_bills_usd"`
+	DailySpendingUSD   float64          `json:"daily_spending_usd"` // everyday spending, excluding the recurring bills
+	InvestmentsUSD     float64          `json:"investments_usd"`    // plan investments over the whole forecast
+	ProposedMonthlyUSD float64          `json:"proposed_monthly_usd,omitempty"`
+	Periods            []CashFlowPeriod `json:"periods"`
+	Events             []CashFlowEvent  `json:"events"`
+	Message            string           `json:"message"`
+}
+
+// SavingsStreak is returned by get_savings_streak and included in get_investment_profile
+type SavingsStreak struct {
+	Status              string `json:"status"` // none, active, frozen or broken
+	CurrentStreak       int    `json:"current_streak_months"`
+	LongestStreak       int    `json:"longest_streak_months"`
+	TotalMonthsInvested int    `json:"total_months_invested"`
+	FrozenMonths        int    `json:"frozen_months"` // months paused or skipped without breaking the streak
+	InvestedThisMonth   bool   `json:"invested_this_month"`
+	LastInvestedMonth   string `json:"last_invested_month,omitempty"` // YYYY-MM
+	MilestoneReached    int    `json:"milestone_reached,omitempty"`   // set in the month a milestone streak is reached
+	NextMilestone       int    `json:"next_milestone_months,omitempty"`
+	CountedSources      string `json:"counted_sources"`
+	Message             string `json:"message"`
+}
+
+// MilestonesResult is returned by get_milestones
+type MilestonesResult struct {
+	Currency       string              `json:"currency"`
+	Milestones     []storage.Milestone `json:"milestones"` // oldest first
+	New            []storage.Milestone `json:"new"`        // not mentioned to the user before this call
+	ContributedUSD float64             `json:"contributed_usd"`
+	NextAmountUSD  float64             `json:"next_amount_usd,omitempty"` // next MILESTONE_AMOUNTS threshold for contributions
+	Message        string              `json:"message"`
+}
+
+// HealthComponent is one weighted part of portfolio_health_score
+type HealthComponent struct {
+	Name          string             `json:"name"`
+	Score         float64            `json:"score"`                    // 0-100
+	Weight        float64            `json:"weight"`                   // as configured
+	WeightPercent float64            `json:"weight_percent,omitempty"` // share of the overall score once unavailable components are left out
+	PointsLost    float64            `json:"points_lost,omitempty"`    // overall score points this component costs
+	Available     bool               `json:"available"`
+	Numbers       map[string]float64 `json:"numbers,omitempty"` // the figures the score was worked out from
+	Detail        string             `json:"detail,omitempty"`
+	Action        string             `json:"action,omitempty"`
+}
+
+// HealthScore is returned by portfolio_health_score
+type HealthScore struct {
+	Score       float64           `json:"score"`  // 0-100
+	Rating      string            `json:"rating"` // strong, fair or needs_work
+	Components  []HealthComponent `json:"components"`
+	TopActions  []string          `json:"top_actions"`  // the two changes worth the most points
+	MissingData []string          `json:"missing_data"` // components left out of the score
+	Message     string            `json:"message"`
+}
+
+// AlertsResult is returned by get_alerts
+type AlertsResult struct {
+	Open    []storage.Alert `json:"open"` // oldest first
+	New     []storage.Alert `json:"new"`  // not mentioned to the user before this call
+	Message string          `json:"message"`
+}
+
+// WindfallBucket is one step of a windfall allocation
+type WindfallBucket struct {
+	Order                     int                `json:"order"`
+	Bucket                    string             `json:"bucket"` // high_interest_debt, emergency_fund, near_term_goals, tax_advantaged or taxable_investing
+	Label                     string             `json:"label"`
+	AmountUSD                 float64            `json:"amount_usd"`
+	AnnualReturn              float64            `json:"annual_return,omitempty"`
+	ProjectedValueUSD         float64            `json:"projected_value_usd,omitempty"`
+	InterestAvoidedPerYearUSD float64            `json:"interest_avoided_per_year_usd,omitempty"`
+	AllocationUSD             map[string]float64 `json:"allocation_usd,omitempty"` // taxable bucket only: stocks, international, reit, bonds, cash
+	Reasoning                 string             `json:"reasoning"`
+}
+
+// WindfallAllocation is returned by windfall_allocation_planner
+type WindfallAllocation struct {
+	Currency                  string           `json:"currency"`
+	WindfallUSD               float64          `json:"windfall_usd"`
+	RiskLevel                 RiskLevel        `json:"risk_level"`
+	RiskSource                string           `json:"risk_source"`    // user_provided or profile
+	ExpenseSource             string           `json:"expense_source"` // user_reported, transaction_history or unavailable
+	ExpectedReturnPercent     float64          `json:"expected_return_percent"`
+	HorizonYears              float64          `json:"horizon_years"`
+	VaultAPY                  float64          `json:"vault_apy"`
+	VaultRateSource           string           `json:"vault_rate_source"`
+	Buckets                   []WindfallBucket `json:"buckets"`
+	DebtsKept                 []string         `json:"debts_kept,omitempty"`
+	PlanValueUSD              float64          `json:"plan_value_usd"`         // emergency fund and invested buckets at the horizon
+	AllInvestedValueUSD       float64          `json:"all_invested_value_usd"` // the whole windfall invested at the expected return
+	InterestAvoidedPerYearUSD float64          `json:"interest_avoided_per_year_usd"`
+	Notes                     []string         `json:"notes,omitempty"`
+	Summary                   string           `json:"summary"`
+}
+
+// RaiseProjection is investing a raise's redirected share for a number of years
+type RaiseProjection struct {
+	Years               float64 `json:"years"`
+	TotalContributedUSD float64 `json:"total_contributed_usd"`
+	ProjectedTotalUSD   float64 `json:"projected_total_usd"`
+	EarningsUSD         float64 `json:"earnings_usd"`
+}
+
+// PlanProposal is a plan change for the user to confirm; it has not been applied
+type PlanProposal struct {
+	PlanID             string                 `json:"plan_id"`
+	CurrentMonthlyUSD  float64                `json:"current_monthly_usd"`
+	ProposedMonthlyUSD float64                `json:"proposed_monthly_usd"`
+	Tool               string                 `json:"tool"`  // tool that applies the change once confirmed
+	Input              map[string]interface{} `json:"input"` // input for Tool
+	Message            string                 `json:"message"`
+}
+
+// RaiseAllocation is returned by raise_allocator
+type RaiseAllocation struct {
+	Currency              string            `json:"currency"`
+	OldMonthlyIncomeUSD   float64           `json:"old_monthly_income_usd"`
+	NewMonthlyIncomeUSD   float64           `json:"new_monthly_income_usd"`
+	RaiseMonthlyUSD       float64           `json:"raise_monthly_usd"`
+	RedirectPercent       float64           `json:"redirect_percent"`
+	AdditionalMonthlyUSD  float64           `json:"additional_monthly_usd"`
+	KeptMonthlyUSD        float64           `json:"kept_monthly_usd"`
+	CurrentSavingsUSD     float64           `json:"current_savings_usd"`
+	SavingsSource         string            `json:"savings_source"` // user_reported or profile
+	NewMonthlySavingsUSD  float64           `json:"new_monthly_savings_usd"`
+	OldSavingsRatePercent float64           `json:"old_savings_rate_percent"`
+	NewSavingsRatePercent float64           `json:"new_savings_rate_percent"`
+	ExpectedReturnPercent float64           `json:"expected_return_percent"`
+	Projections           []RaiseProjection `json:"projections"` // investing only the additional amount
+	PlanProposal          *PlanProposal     `json:"plan_proposal,omitempty"`
+	PlanNote              string            `json:"plan_note,omitempty"`
+	Message               string            `json:"message"`
+}
+
+// PositionExposure is one holding's share of the portfolio
+type PositionExposure struct {
+	Ticker    string  `json:"ticker"`
+	Sector    string  `json:"sector"`
+	ValueUSD  float64 `json:"value_usd"`
+	Percent   float64 `json:"percent"`
+	OverLimit bool    `json:"over_limit"`
+	ExcessUSD float64 `json:"excess_usd,omitempty"` // amount to redirect to reach the position limit
+}
+
+// SectorExposure is one sector's share of the portfolio
+type SectorExposure struct {
+	Sector    string  `json:"sector"`
+	ValueUSD  float64 `json:"value_usd"`
+	Percent   float64 `json:"percent"`
+	OverLimit bool    `json:"over_limit"`
+	ExcessUSD float64 `json:"excess_usd,omitempty"` // amount to redirect to reach the sector limit
+}
+
+// ConcentrationResult is returned by sector_concentration_checker
+type ConcentrationResult struct {
+	Currency             string             `json:"currency"`
+	TotalValueUSD        float64            `json:"total_value_usd"`
+	PositionLimitPercent float64            `json:"position_limit_percent"`
+	SectorLimitPercent   float64            `json:"sector_limit_percent"`
+	Positions            []PositionExposure `json:"positions"`
+	Sectors              []SectorExposure   `json:"sectors"`
+	NeedsSector          []string           `json:"needs_sector,omitempty"` // tickers the model should classify and resend
+	Suggestions          []string           `json:"suggestions"`
+	Message              string             `json:"message"`
+}
+
+// OverlapFund is a fund recognized by portfolio_overlap_analyzer
+type OverlapFund struct {
+	Input      string `json:"input"`
+	Name       string `json:"name"`
+	AssetClass string `json:"asset_class"`
+}
+
+// FundOverlap is the holdings overlap between two funds
+type FundOverlap struct {
+	FundA               string  `json:"fund_a"`
+	FundB               string  `json:"fund_b"`
+	OverlapPercent      float64 `json:"overlap_percent"`
+	Verdict             string  `json:"verdict"` // redundant, partial or diversifying
+	AddsDiversification bool    `json:"adds_diversification"`
+	Explanation         string  `json:"explanation"`
+}
+
+// FundOverlapResult is returned by portfolio_overlap_analyzer
+type FundOverlapResult struct {
+	DataAsOf       string        `json:"data_as_of"`
+	Funds          []OverlapFund `json:"funds"`
+	Pairs          []FundOverlap `json:"pairs"`
+	NotFound       []string      `json:"not_found,omitempty"`
+	SupportedFunds []string      `json:"supported_funds,omitempty"` // listed when any fund wasn't found
+	Message        string        `json:"message"`
+}
+
+// RiskSessionState is returned by begin_risk_assessment and answer_risk_question
+type RiskSessionState struct {
+	Answers             map[string]string `json:"answers"` // question ID -> answer; "skip" for skipped optional questions
+	AnsweredQuestionIDs []string          `json:"answered_question_ids"`
+	Remaining           []string          `json:"remaining_question_ids"`
+	NextQuestion        *riskQuestion     `json:"next_question,omitempty"`
+	TotalQuestions      int               `json:"total_questions"`
+	ReadyToFinish       bool              `json:"ready_to_finish"` // every required question answered
+	Resumed             bool              `json:"resumed,omitempty"`
+	IdleTimeoutMinutes  float64           `json:"idle_timeout_minutes"`
+	Message             string            `json:"message"`
+}
+
+// CostOfWaitingResult is returned by cost_of_waiting_calculator
+type CostOfWaitingResult struct {
+	Currency                 string  `json:"currency"`
+	MonthlyContribution      float64 `json:"monthly_contribution"`
+	Years                    float64 `json:"years"`
+	DelayYears               float64 `json:"delay_years"`
+	ExpectedReturnPercent    float64 `json:"expected_return_percent"`
+	StartNowTotalUSD         float64 `json:"start_now_total_usd,omitempty"`
+	StartNowContributedUSD   float64 `json:"start_now_contributed_usd,omitempty"`
+	StartLaterTotalUSD       float64 `json:"start_later_total_usd,omitempty"`
+	StartLaterContributedUSD float64 `json:"start_later_contributed_usd,omitempty"`
+	CostOfWaitingUSD         float64 `json:"cost_of_waiting_usd,omitempty"`
+	CostPercent              float64 `json:"cost_percent,omitempty"`         // share of the start-now total lost
+	CatchUpMonthlyUSD        float64 `json:"catch_up_monthly_usd,omitempty"` // late starter's monthly to match start-now
+	ExtraMonthlyToCatchUpUSD float64 `json:"extra_monthly_to_catch_up_usd,omitempty"`
+	Message                  string  `json:"message"`
+}
+
+// StressPoint is a portfolio value along a stress test path
+type StressPoint struct {
+	Month    int     `json:"month"`
+	ValueUSD float64 `json:"value_usd"`
+}
+
+// StressPath is one contribution behaviour through a crash and recovery
+type StressPath struct {
+	Behavior         string        `json:"behavior"`
+	ContributedUSD   float64       `json:"contributed_usd"`
+	EndOfRecoveryUSD float64       `json:"end_of_recovery_usd"`
+	FinalValueUSD    float64       `json:"final_value_usd"`
+	RecoveredMonth   int           `json:"recovered_month"` // first month back at the pre-crash value; -1 if not within the horizon
+	Path             []StressPoint `json:"yearly_path"`
+}
+
+// StressTestResult is returned by market_crash_stress_test
+type StressTestResult struct {
+	Currency                string     `json:"currency"`
+	Scenario                string     `json:"scenario"`
+	ScenarioLabel           string     `json:"scenario_label"`
+	PortfolioValueUSD       float64    `json:"portfolio_value_usd"`
+	StockPercent            float64    `json:"stock_percent"`
+	AllocationSource        string     `json:"allocation_source"` // "user_provided" or "profile"
+	MonthlyContribution     float64    `json:"monthly_contribution"`
+	ContributionSource      string     `json:"contribution_source"` // "user_provided" or "profile"
+	EquityDrawdownPercent   float64    `json:"equity_drawdown_percent"`
+	RecoveryYears           float64    `json:"recovery_years"`
+	HorizonYears            float64    `json:"horizon_years"`
+	ExpectedReturnPercent   float64    `json:"expected_return_percent"`
+	CashAPY                 float64    `json:"cash_apy"` // earned by the non-stock share
+	CashRateSource          string     `json:"cash_rate_source"`
+	CrashLossUSD            float64    `json:"crash_loss_usd"`
+	ValueAfterCrashUSD      float64    `json:"value_after_crash_usd"`
+	KeepInvesting           StressPath `json:"keep_investing"`
+	PauseContributions      StressPath `json:"pause_contributions"`
+	DifferenceUSD           float64    `json:"difference_usd"` // keep_investing minus pause_contributions at the horizon
+	SkippedContributionsUSD float64    `json:"skipped_contributions_usd"`
+	RecoveryBonusUSD        float64    `json:"recovery_bonus_usd"` // growth on contributions bought during the dip
+	Message                 string     `json:"message"`
+}
+
+// BacktestYear is one calendar year of a historical_backtest replay
+type BacktestYear struct {
+	Year          int     `json:"year"`
+	ReturnPercent float64 `json:"return_percent"`
+	MarketGainUSD float64 `json:"market_gain_usd"` // change in value that year excluding contributions
+	EndValueUSD   float64 `json:"end_value_usd"`
+}
+
+// BacktestResult is returned by historical_backtest
+type BacktestResult struct {
+	Currency                string         `json:"currency"`
+	StartYear               int            `json:"start_year"`
+	EndYear                 int            `json:"end_year"`
+	InitialAmountUSD        float64        `json:"initial_amount_usd"`
+	MonthlyContribution     float64        `json:"monthly_contribution"`
+	StockPercent            float64        `json:"stock_percent"`
+	BondPercent             float64        `json:"bond_percent"`
+	CashPercent             float64        `json:"cash_percent"`
+	TotalContributedUSD     float64        `json:"total_contributed_usd"`
+	EndingValueUSD          float64        `json:"ending_value_usd"`
+	GainUSD                 float64        `json:"gain_usd"`
+	AnnualizedReturnPercent float64        `json:"annualized_return_percent"` // time-weighted, ignoring contribution timing
+	WorstYear               int            `json:"worst_year"`
+	WorstYearReturnPercent  float64        `json:"worst_year_return_percent"`
+	WorstYearLossUSD        float64        `json:"worst_year_market_change_usd"`
+	MaxDrawdownPercent      float64        `json:"max_drawdown_percent"` // year-end peak to later year-end trough
+	DrawdownPeakYear        int            `json:"drawdown_peak_year,omitempty"`
+	DrawdownTroughYear      int            `json:"drawdown_trough_year,omitempty"`
+	Years                   []BacktestYear `json:"years"`
+	DataNote                string         `json:"data_note"`
+	Message                 string         `json:"message"`
+}
+
+// DrawdownEpisode is one decline from a year-end high to the next time that high was passed
+type DrawdownEpisode struct {
+	PeakYear      int     `json:"peak_year"`
+	TroughYear    int     `json:"trough_year"`
+	DepthPercent  float64 `json:"depth_percent"`
+	Recovered     bool    `json:"recovered"`
+	RecoveryYear  int     `json:"recovery_year,omitempty"`
+	RecoveryYears int     `json:"recovery_years,omitempty"` // trough to back above the peak
+}
+
+// DrawdownResult is returned by max_drawdown_estimate
+type DrawdownResult struct {
+	Currency               string                `json:"currency"`
+	Allocation             map[string]float64    `json:"allocation"`        // percent per asset class
+	AllocationSource       string                `json:"allocation_source"` // "user_provided", "risk_level" or "profile"
+	PortfolioValueUSD      float64               `json:"portfolio_value_usd"`
+	ValueSource            string                `json:"value_source"` // "user_provided" or "profile"
+	FirstYear              int                   `json:"first_year"`
+	LastYear               int                   `json:"last_year"`
+	WorstDrawdownPercent   float64               `json:"worst_drawdown_percent"`
+	WorstPeakYear          int                   `json:"worst_peak_year,omitempty"`
+	WorstTroughYear        int                   `json:"worst_trough_year,omitempty"`
+	WorstLossUSD           float64               `json:"worst_loss_usd"`
+	ValueAtTroughUSD       float64               `json:"value_at_trough_usd"`
+	WorstRecoveryYears     int                   `json:"worst_recovery_years,omitempty"` // zero when not yet recovered
+	TypicalDrawdownPercent float64               `json:"typical_drawdown_percent"`       // median decline of at least minTypicalDrawdownPct
+	TypicalRecoveryYears   float64               `json:"typical_recovery_years"`         // median, recovered declines only
+	Episodes               []DrawdownEpisode     `json:"episodes"`
+	ByRiskLevel            map[RiskLevel]float64 `json:"worst_drawdown_by_risk_level"` // for comparing levels
+	DataNote               string                `json:"data_note"`
+	Message                string                `json:"message"`
+}
+
+// BenchmarkResult is returned by benchmark_comparison
+type BenchmarkResult struct {
+	ReportedReturnPercent      float64            `json:"reported_return_percent"`
+	StartYear                  int                `json:"start_year"`
+	EndYear                    int                `json:"end_year"`
+	Allocation                 map[string]float64 `json:"allocation"`        // percent per asset class
+	AllocationSource           string             `json:"allocation_source"` // "user_provided", "risk_level" or "profile"
+	BenchmarkReturnPercent     float64            `json:"benchmark_return_percent"`
+	DifferencePercent          float64            `json:"difference_percent"` // reported minus benchmark, total for the period
+	ReportedAnnualizedPercent  float64            `json:"reported_annualized_percent"`
+	BenchmarkAnnualizedPercent float64            `json:"benchmark_annualized_percent"`
+	AnnualizedGapPercent       float64            `json:"annualized_gap_percent"`
+	Verdict                    string             `json:"verdict"` // outperformed, in_line or underperformed
+	BenchmarkYears             []BacktestYear     `json:"benchmark_years"`
+	Cautions                   []string           `json:"cautions,omitempty"`
+	DataNote                   string             `json:"data_note"`
+	Message                    string             `json:"message"`
+}
+
+// SecurityQuote is returned by lookup_security_quote
+type SecurityQuote struct {
+	Ticker        string  `json:"ticker"`
+	Price         float64 `json:"price"`
+	Change        float64 `json:"change"` // since the previous close
+	ChangePercent float64 `json:"change_percent"`
+	Week52High    float64 `json:"week_52_high"`
+	Week52Low     float64 `json:"week_52_low"`
+	Currency      string  `json:"currency"`
+	AsOf          string  `json:"as_of,omitempty"`
+	Cached        bool    `json:"cached"`
+	Message       string  `json:"message"`
+}
+
+// ETFFacts is one fund's entry as returned by etf_fact_lookup
+type ETFFacts struct {
+	Ticker              string  `json:"ticker"`
+	Name                string  `json:"name"`
+	Issuer              string  `json:"issuer"`
+	ExpenseRatioPercent float64 `json:"expense_ratio_percent"`
+	AssetClass          string  `json:"asset_class"`
+	IndexTracked        string  `json:"index_tracked"`
+	InceptionYear       int     `json:"inception_year"`
+	AllocationBucket    string  `json:"allocation_bucket,omitempty"` // stocks, international, reit, bonds or cash; empty if outside the model
+}
+
+// ETFCandidate is a possible match for an ambiguous etf_fact_lookup query
+type ETFCandidate struct {
+	Ticker     string  `json:"ticker"`
+	Name       string  `json:"name"`
+	MatchScore float64 `json:"match_score"` // 1 = every query word matched the name
+}
+
+// ETFLookupResult is returned by etf_fact_lookup
+type ETFLookupResult struct {
+	Query      string         `json:"query"`
+	Fund       *ETFFacts      `json:"fund,omitempty"`
+	Candidates []ETFCandidate `json:"candidates,omitempty"`
+	DataAsOf   string         `json:"data_as_of,omitempty"`
+	DataNote   string         `json:"data_note,omitempty"`
+	Message    string         `json:"message"`
+}
+
+// RebalanceResult is returned by rebalance_investment_portfolio
+type RebalanceResult struct {
+	Currency                 string                    `json:"currency"`
+	Mode                     string                    `json:"mode"`            // sell_and_buy or contributions_only
+	HoldingsSource           string                    `json:"holdings_source"` // input, or holdings when derived from add_holding records
+	CurrentAllocation        map[string]string         `json:"current_allocation"`
+	CurrentAllocationPercent map[string]float64        `json:"current_allocation_percent"`
+	TargetRiskLevel          RiskLevel                 `json:"target_risk_level,omitempty"`
+	TargetSource             string                    `json:"target_source"` // risk_level or glide_path
+	GlideYearsLeft           int                       `json:"glide_years_to_target,omitempty"`
+	TargetAllocation         map[string]string         `json:"target_allocation"`
+	TargetBands              map[string]allocationBand `json:"target_bands"`
+	AllocationNote           string                    `json:"allocation_note,omitempty"` // set when international or REIT holdings were folded into stocks
+	TotalValue               string                    `json:"total_value"`
+	TotalValueUSD            float64                   `json:"total_value_usd"`
+	Drift                    map[string]assetDrift     `json:"drift"`
+	MaxDriftPercent          float64                   `json:"max_drift_percent"`
+	DriftBandPercent         float64                   `json:"drift_band_percent"` // tolerated drift either side of every target
+	RebalancingNeeded        bool                      `json:"rebalancing_needed"`
+	Moves                    []RebalanceMove           `json:"moves"` // empty when no class is outside its band, or in contributions_only mode
+	DeferredMoves            []DeferredMove            `json:"deferred_moves,omitempty"`
+	TaxFreeMoves             []RebalanceMove           `json:"tax_free_moves,omitempty"` // moves sells split by account when holding_accounts is given
+	TaxableMoves             []TaxableMove             `json:"taxable_moves,omitempty"`
+	MinTradeUSD              float64                   `json:"min_trade_usd"`
+	MonthlyContributionUSD   float64                   `json:"monthly_contribution_usd,omitempty"`
+	ContributionSplits       []ContributionSplit       `json:"contribution_splits,omitempty"`
+	MonthsToBand             int                       `json:"months_to_band,omitempty"` // contributions_only: -1 = longer than 10 years
+	ActionItems              []string                  `json:"action_items"`
+}
+
+// RebalanceMoveOutcome is what execute_rebalance did with one move
+type RebalanceMoveOutcome struct {
+	RebalanceMove
+	Status string   `json:"status"` // succeeded, failed, not_attempted or manual
+	Calls  []string `json:"calls"`  // Liminal tools that completed, in order
+	Note   string   `json:"note,omitempty"`
+	Error  string   `json:"error,omitempty"`
+}
+
+// ExecuteRebalanceResult is returned by execute_rebalance
+type ExecuteRebalanceResult struct {
+	Currency     string                 `json:"currency"`
+	Moves        []RebalanceMoveOutcome `json:"moves"`
+	Completed    int                    `json:"completed"`
+	CompletedUSD float64                `json:"completed_usd"`
+	Stopped      bool                   `json:"stopped"`      // a move failed and the rest weren't attempted
+	ManualSteps  []string               `json:"manual_steps"` // what the user still has to do outside Liminal
+	Message      string                 `json:"message"`
+}
+
+// ProfileChange is one field changed by update_investment_profile
+type ProfileChange struct {
+	Field  string `json:"field"`
+	Before string `json:"before"`
+	After  string `json:"after"`
+}
+
+// ProfileUpdateResult is returned by update_investment_profile
+type ProfileUpdateResult struct {
+	Changes []ProfileChange `json:"changes"`
+	Message string          `json:"message"`
+}
+
+// RelatedConcept is a related concept attached to an explain_investment_concept reply
+type RelatedConcept struct {
+	Concept   string `json:"concept"`
+	Teaser    string `json:"teaser"`
+	Explained bool   `json:"already_explained"`
+}
+
+// ConceptSuggestion is one concept recommended by suggest_next_concept
+type ConceptSuggestion struct {
+	Concept    string   `json:"concept"`
+	Teaser     string   `json:"teaser"`
+	Difficulty string   `json:"difficulty"`
+	BuildsOn   []string `json:"builds_on"` // learned concepts it is linked to
+}
+
+// QuizQuestion is a question as investment_knowledge_quiz serves it
+type QuizQuestion struct {
+	QuestionID string   `json:"question_id"`
+	Concept    string   `json:"concept"`
+	Question   string   `json:"question"`
+	Choices    []string `json:"choices"` // labeled "A. ...", "B. ..."
+	Reason     string   `json:"reason"`  // why it was picked: review, new or practice
+}
+
+// QuizFeedback grades one answer; a wrong answer carries the concept's explanation to go over again
+type QuizFeedback struct {
+	QuestionID    string   `json:"question_id"`
+	Concept       string   `json:"concept"`
+	Correct       bool     `json:"correct"`
+	YourAnswer    string   `json:"your_answer"`
+	CorrectAnswer string   `json:"correct_answer"`
+	Why           string   `json:"why,omitempty"`
+	Level         string   `json:"level,omitempty"`
+	Explanation   string   `json:"explanation,omitempty"`
+	KeyPoints     []string `json:"key_points,omitempty"`
+}
+
+// QuizResult is returned by investment_knowledge_quiz
+type QuizResult struct {
+	Feedback     *QuizFeedback `json:"feedback,omitempty"`
+	Next         *QuizQuestion `json:"next_question,omitempty"`
+	Answered     int           `json:"answered"` // every attempt so far
+	Correct      int           `json:"correct"`
+	ScorePercent float64       `json:"score_percent"`
+	Message      string        `json:"message"`
+}
+
+// ConceptQuizProgress is one concept's standing in get_quiz_progress
+type ConceptQuizProgress struct {
+	Concept   string `json:"concept"`
+	Status    string `json:"status"` // mastered, needs_review, in_progress or not_started
+	Questions int    `json:"questions"`
+	Answered  int    `json:"answered"` // distinct questions answered
+	Correct   int    `json:"correct"`  // questions whose latest answer was right
+}
+
+// QuizProgressResult is returned by get_quiz_progress
+type QuizProgressResult struct {
+	Answered     int                   `json:"answered"`
+	Correct      int                   `json:"correct"`
+	ScorePercent float64               `json:"score_percent"`
+	Mastered     []string              `json:"mastered"`
+	NeedsReview  []string              `json:"needs_review"`
+	InProgress   []string              `json:"in_progress"`
+	NotStarted   []string              `json:"not_started"`
+	Concepts     []ConceptQuizProgress `json:"concepts"`
+	Message      string                `json:"message"`
+}
+
+// ConceptExample works a concept through with numbers, the user's own unless Generic
+type ConceptExample struct {
+	Example           string             `json:"example"`
+	Generic           bool               `json:"generic"` // illustrative numbers; the user has no stored profile
+	MonthlySavingsUSD float64            `json:"monthly_savings"`
+	BalanceUSD        float64            `json:"balance"`
+	Figures           map[string]float64 `json:"figures"` // the example's computed amounts, by name
+	Note              string             `json:"note,omitempty"`
+}
+
+// NextConceptResult is returned by suggest_next_concept
+type NextConceptResult struct {
+	Suggestions []ConceptSuggestion `json:"suggestions"`
+	Explained   []string            `json:"explained"` // concepts already explained to the user, oldest first
+	Remaining   int                 `json:"remaining"` // concepts not yet explained
+	Message     string              `json:"message"`
+}
+
+// ConceptSummary is one concept in list_investment_concepts
+type ConceptSummary struct {
+	Concept    string   `json:"concept"`
+	Summary    string   `json:"summary"`
+	Difficulty string   `json:"difficulty"`
+	Levels     []string `json:"levels"` // explanation levels written; beginner only shows a missing intermediate variant
+	Tags       []string `json:"tags"`
+}
+
+// ConceptListResult is returned by list_investment_concepts
+type ConceptListResult struct {
+	Concepts []ConceptSummary `json:"concepts"`
+	Topics   []string         `json:"topics"` // every tag in use, for filtering
+	Message  string           `json:"message"`
+}
+
+// OnboardingGoal is one goal saved by complete_onboarding
+type OnboardingGoal struct {
+	GoalID             string  `json:"goal_id"`
+	Name               string  `json:"goal_name"`
+	TargetAmountUSD    float64 `json:"target_amount"`
+	TargetDate         string  `json:"target_date"`
+	Months             int     `json:"months"`
+	InvestmentType     string  `json:"investment_type"`
+	RequiredMonthlyUSD float64 `json:"required_monthly"` // to reach the target from nothing at the assumed return
+}
+
+// OnboardingResult is returned by complete_onboarding
+type OnboardingResult struct {
+	Currency               string             `json:"currency"`
+	Status                 string             `json:"status"`  // complete or partial
+	Missing                []string           `json:"missing"` // inputs still to gather, in the order to ask
+	ProfileSaved           bool               `json:"profile_saved"`
+	RiskLevel              RiskLevel          `json:"risk_level,omitempty"`
+	RiskScore              int                `json:"risk_score,omitempty"`
+	RiskBreakdown          map[string]int     `json:"risk_breakdown,omitempty"`
+	Allocation             map[string]string  `json:"recommended_allocation,omitempty"`
+	AllocationPercent      map[string]float64 `json:"recommended_allocation_percent,omitempty"`
+	EstimatedGrowthRate    string             `json:"estimated_growth_rate,omitempty"`
+	SuggestedMonthlyUSD    float64            `json:"suggested_monthly_investment"`
+	EmergencyMonthlyUSD    float64            `json:"emergency_fund_monthly,omitempty"` // set aside for the emergency fund until it's full
+	EmergencyFundTargetUSD float64            `json:"emergency_fund_target,omitempty"`
+	FirstGoal              *OnboardingGoal    `json:"first_goal,omitempty"`
+	Goals                  []OnboardingGoal   `json:"goals"`
+	NextActions            []string           `json:"next_actions"`
+	Message                string             `json:"message"`
+}
+
+// SmartSavingsResult is returned by calculate_smart_savings_rate
+type SmartSavingsResult struct {
+	Currency                     string  `json:"currency"`
+	MonthlyIncome                string  `json:"monthly_income"`
+	MonthlyIncomeUSD             float64 `json:"monthly_income_usd"`
+	CurrentEmergencyFund         string  `json:"current_emergency_fund"`
+	CurrentEmergencyFundUSD      float64 `json:"current_emergency_fund_usd"`
+	EmergencyFundTarget          string  `json:"emergency_fund_target"`
+	EmergencyFundTargetUSD       float64 `json:"emergency_fund_target_usd"`
+	EmergencyFundTargetBasis     string  `json:"emergency_fund_target_basis"`
+	EmergencyFundStatus          string  `json:"emergency_fund_status"` // fully_funded, building or unreachable
+	RecommendedMonthlySavings    string  `json:"recommended_monthly_savings"`
+	RecommendedMonthlySavingsUSD float64 `json:"recommended_monthly_savings_usd"`
+	PriorityEmergencyFund        string  `json:"priority_emergency_fund"`
+	PriorityEmergencyFundUSD     float64 `json:"priority_emergency_fund_usd"`
+	InvestmentBudget             string  `json:"investment_budget"`
+	InvestmentBudgetUSD          float64 `json:"investment_budget_usd"`
+	SavingsRate                  string  `json:"savings_rate"`
+	SavingsRatePercent           float64 `json:"savings_rate_percent"`
+	TimeToGoal                   string  `json:"time_to_goal"`
+	MonthsToGoal                 float64 `json:"months_to_goal"`      // -1 when emergency_fund_status is "unreachable"
+	SavingsAPY                   float64 `json:"savings_apy"`         // vault APY the emergency fund earns while it builds
+	SavingsRateSource            string  `json:"savings_rate_source"` // "live" (get_vault_rates) or "default" (DEFAULT_VAULT_APY)
+	IncomeSource                 string  `json:"income_source"`       // "user_provided" or "detected" (get_detected_income)
+}
+
+// SavingsBoosterResult is returned by identify_savings_boosters
+type SavingsBoosterResult struct {
+	Currency                 string             `json:"currency"`
+	MonthlyBudget            string             `json:"monthly_budget"`
+	MonthlyBudgetUSD         float64            `json:"monthly_budget_usd"`
+	MonthlyDiscretionary     string             `json:"monthly_discretionary"`
+	MonthlyDiscretionaryUSD  float64            `json:"monthly_discretionary_usd"`
+	MicroInvestmentTarget    string             `json:"micro_investment_target"`
+	MicroInvestmentTargetUSD float64            `json:"micro_investment_target_usd"`
+	Strategy                 string             `json:"strategy"`
+	AnnualSavings            string             `json:"annual_savings"`
+	AnnualSavingsUSD         float64            `json:"annual_savings_usd"`
+	AssumedReturnPercent     float64            `json:"assumed_return_percent"`
+	AnnualProjection         string             `json:"annual_projection"` // one year of monthly contributions, compounded
+	AnnualProjectionUSD      float64            `json:"annual_projection_usd"`
+	AnnualGrowthAt7Pct       string             `json:"annual_growth_at_7pct"` // same as annual_projection; name kept for existing clients
+	AnnualGrowthUSD          float64            `json:"annual_growth_usd"`     // same as annual_projection_usd, as it always was
+	AnnualEarningsUSD        float64            `json:"annual_earnings_usd"`
+	TenYearProjection        string             `json:"10year_projection"`
+	TenYearProjectionUSD     float64            `json:"10year_projection_usd"`
+	TenYearContributedUSD    float64            `json:"10year_contributed_usd"`
+	TenYearGrowthUSD         float64            `json:"10year_growth_usd"`
+	Recommendation           string             `json:"recommendation"`
+	BoosterPower             string             `json:"booster_power"`
+	BaselineComparison       BaselineComparison `json:"baseline_comparison"`
+}
+
+// GoalResult is returned by create_investment_goal_with_transfer
+type GoalResult struct {
+	Currency          string            `json:"currency"`
+	Success           bool              `json:"success"`
+	GoalID            string            `json:"goal_id"`
+	GoalName          string            `json:"goal_name"`
+	TargetAmount      string            `json:"target_amount"`
+	TargetAmountUSD   float64           `json:"target_amount_usd"`
+	TargetDate        string            `json:"target_date"`
+	MonthlyFund       string            `json:"monthly_fund"`
+	MonthlyFundUSD    float64           `json:"monthly_fund_usd"`
+	InvestmentType    string            `json:"investment_type"`
+	ProjectedTotal    string            `json:"projected_total"`
+	ProjectedTotalUSD float64           `json:"projected_total_usd"`
+	MonthsToGoal      int               `json:"months_to_goal"`
+	Projection        ProjectionResult  `json:"projection"`
+	FundingStatus     GoalFundingStatus `json:"funding_status"`
+	GoalOdds
+	Affordability      *Affordability     `json:"affordability,omitempty"`
+	LiminalStatus      string             `json:"liminal_status"`
+	Message            string             `json:"message"`
+	BaselineComparison BaselineComparison `json:"baseline_comparison"`
+}
+
+// GoalProgress is one goal's entry in get_goal_progress
+type GoalProgress struct {
+	GoalID            string  `json:"goal_id"`
+	GoalName          string  `json:"goal_name"`
+	TargetAmountUSD   float64 `json:"target_amount_usd"`
+	TargetDate        string  `json:"target_date"`
+	AmountSavedUSD    float64 `json:"amount_saved_usd"`
+	AmountSource      string  `json:"amount_source"`               // user_reported, savings_balance, contributions_ledger or estimated_from_contributions
+	AmountAssumption  string  `json:"amount_assumption,omitempty"` // what an estimated_from_contributions amount takes for granted
+	PercentComplete   float64 `json:"percent_complete"`
+	MonthsElapsed     int     `json:"months_elapsed"`
+	MonthsRemaining   int     `json:"months_remaining"`
+	MonthlyUSD        float64 `json:"monthly_contribution_usd"` // after the annual increases so far
+	AnnualIncrease    float64 `json:"annual_increase_percent,omitempty"`
+	OnPace            bool    `json:"on_pace"`
+	ProjectedTotalUSD float64 `json:"projected_total_usd,omitempty"`
+	RevisedMonthlyUSD float64 `json:"revised_monthly_usd,omitempty"` // needed to still hit the target when off pace
+	*GoalOdds                 // only while the goal is still in progress
+	Message           string  `json:"message"`
+}
+
+// HoldingsSummary is returned by add_holding, remove_holding and list_holdings
+type HoldingsSummary struct {
+	Currency          string             `json:"currency"`
+	Holdings          []storage.Holding  `json:"holdings"`
+	TotalValue        string             `json:"total_value"`
+	TotalValueUSD     float64            `json:"total_value_usd"`
+	AllocationUSD     map[string]float64 `json:"allocation_usd"` // by asset class
+	AllocationPercent map[string]float64 `json:"allocation_percent"`
+	Message           string             `json:"message"`
+}
+
+// PerformancePeriod is one window of get_portfolio_performance
+type PerformancePeriod struct {
+	Period           string  `json:"period"` // 1M, 3M, YTD or 1Y
+	StartDate        string  `json:"start_date"`
+	EndDate          string  `json:"end_date"`
+	StartValueUSD    float64 `json:"start_value_usd"`
+	EndValueUSD      float64 `json:"end_value_usd"`
+	Change           string  `json:"change"`
+	ChangeUSD        float64 `json:"change_usd"`
+	ContributionsUSD float64 `json:"contributions_usd"`
+	MarketGainUSD    float64 `json:"market_gain_usd"` // change not explained by contributions
+	ReturnPercent    float64 `json:"return_percent"`  // money-weighted (Modified Dietz)
+	Interpolated     bool    `json:"interpolated"`    // start value estimated between two snapshots
+	Partial          bool    `json:"partial"`         // history starts after the period does
+	Note             string  `json:"note,omitempty"`
+}
+
+// PerformanceResult is returned by get_portfolio_performance
+type PerformanceResult struct {
+	Currency                string              `json:"currency"`
+	CurrentValue            string              `json:"current_value,omitempty"`
+	CurrentValueUSD         float64             `json:"current_value_usd"`
+	AsOf                    string              `json:"as_of,omitempty"`
+	FirstSnapshot           string              `json:"first_snapshot,omitempty"`
+	ContributedThisYear     float64             `json:"contributed_this_year"`
+	ContributedLifetime     float64             `json:"contributed_lifetime"`
+	GrowthExclContributions float64             `json:"growth_excluding_contributions"` // change since first_snapshot minus contributions
+	Periods                 []PerformancePeriod `json:"periods"`
+	Assumptions             []string            `json:"assumptions,omitempty"`
+	Message                 string              `json:"message"`
+}
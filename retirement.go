@@ -0,0 +1,320 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/becomeliminal/nim-go-sdk/core"
+	"github.com/becomeliminal/nim-go-sdk/tools"
+)
+
+// ============================================
+// RETIREMENT READINESS
+// ============================================
+
+// maxRetirementAge bounds the planned retirement age
+const maxRetirementAge = 100
+
+// newRetirementReadinessTool projects a nest egg and checks it against desired income
+func newRetirementReadinessTool() core.Tool {
+	return tools.New("retirement_readiness_check").
+		Description("Check whether the user is on track for retirement: projected nest egg, the income it can sustain, the gap versus desired income and the extra monthly savings to close it, in nominal and inflation-adjusted terms").
+		Schema(tools.ObjectSchema(map[string]interface{}{
+			"current_age":          tools.IntegerProperty("User's current age"),
+			"retirement_age":       tools.IntegerProperty("Age the user plans to retire"),
+			"current_savings":      tools.StringProperty("Current retirement savings in the account currency"),
+			"monthly_contribution": tools.StringProperty("Amount saved for retirement each month in the account currency"),
+			"expected_return":      tools.StringProperty(fmt.Sprintf("Optional expected annual return percentage (defaults to the server's assumption, currently %g)", appConfig.Assumptions.EquityReturnPct)),
+			"desired_income":       tools.StringProperty("Desired annual retirement income in today's dollars"),
+			"inflation_rate":       tools.StringProperty(fmt.Sprintf("Optional annual inflation percentage (defaults to the server's assumption, currently %g)", appConfig.Assumptions.InflationPct)),
+		}, "current_age", "retirement_age", "current_savings", "monthly_contribution", "desired_income")).
+		HandlerFunc(func(ctx context.Context, input json.RawMessage) (interface{}, error) {
+			var params struct {
+				CurrentAge          int    `json:"current_age"`
+				RetirementAge       int    `json:"retirement_age"`
+				CurrentSavings      string `json:"current_savings"`
+				MonthlyContribution string `json:"monthly_contribution"`
+				ExpectedReturn      string `json:"expected_return"`
+				DesiredIncome       string `json:"desired_income"`
+				InflationRate       string `json:"inflation_rate"`
+			}
+			if err := json.Unmarshal(input, &params); err != nil {
+				return nil, fmt.Errorf("invalid input: %w", err)
+			}
+
+			var v amountValidator
+			if params.CurrentAge < 1 || params.CurrentAge >= maxRetirementAge {
+				v.fail("current_age", "must be between 1 and %d (got %d)", maxRetirementAge-1, params.CurrentAge)
+			}
+			if params.RetirementAge <= params.CurrentAge || params.RetirementAge > maxRetirementAge {
+				v.fail("retirement_age", "must be after current_age and at most %d (got %d)", maxRetirementAge, params.RetirementAge)
+			}
+			savings := v.nonNegative("current_savings", params.CurrentSavings, true)
+			monthly := v.nonNegative("monthly_contribution", params.MonthlyContribution, true)
+			returnRate := v.returnRate("expected_return", params.ExpectedReturn, false)
+			desired := v.positive("desired_income", params.DesiredIncome)
+			inflation := v.inflationRate("inflation_rate", params.InflationRate)
+			if err := v.err(); err != nil {
+				return nil, err
+			}
+
+			return retirementReadiness(params.CurrentAge, params.RetirementAge, savings, monthly, returnRate, desired, inflation), nil
+		}).
+		Build()
+}
+
+// retirementReadiness projects savings to retirement and converts them to income at the
+// configured withdrawal rate. The desired income is in today's dollars, so it is
+// compared against the inflation-adjusted income rather than the larger nominal one.
+func retirementReadiness(currentAge, retirementAge int, savings, monthly, returnRate, desired, inflation float64) RetirementReadiness {
+	years := retirementAge - currentAge
+	months := float64(years * 12)
+	withdrawal := appConfig.WithdrawalRate
+
+	projection := calculateCompoundGrowth(savings, monthly, returnRate, float64(years)).withInflation(inflation)
+	nestEgg := projection.ProjectedTotalUSD
+	income := nestEgg * withdrawal / 100
+	desiredNominal := desired / realValue(1, inflation, months)
+	requiredNestEgg := desiredNominal / (withdrawal / 100)
+
+	r := RetirementReadiness{
+		Currency:               activeCurrency().Code,
+		CurrentAge:             currentAge,
+		RetirementAge:          retirementAge,
+		YearsToRetirement:      years,
+		AssumedReturnPercent:   returnRate,
+		InflationPercent:       inflation,
+		WithdrawalRatePercent:  withdrawal,
+		Projection:             projection,
+		NestEggNominalUSD:      nestEgg,
+		NestEggRealUSD:         projection.ProjectedTotalRealUSD,
+		AnnualIncomeNominalUSD: income,
+		AnnualIncomeRealUSD:    realValue(income, inflation, months),
+		DesiredIncomeRealUSD:   desired,
+		DesiredIncomeNominal:   desiredNominal,
+		IncomeGapRealUSD:       desired - realValue(income, inflation, months),
+		RequiredNestEggUSD:     requiredNestEgg,
+	}
+	required := requiredMonthlyContribution(requiredNestEgg, savings, returnRate, months)
+	r.ExtraMonthlyNeededUSD = max(required-monthly, 0)
+	r.OnTrack = r.IncomeGapRealUSD <= 0
+
+	if r.OnTrack {
+		r.Message = fmt.Sprintf("On track: a projected %s nest egg (%s in today's dollars) supports about %s/year in today's dollars at a %g%% withdrawal rate, covering your %s goal.",
+			formatWholeMoney(nestEgg), formatWholeMoney(r.NestEggRealUSD), formatWholeMoney(r.AnnualIncomeRealUSD), withdrawal, formatWholeMoney(desired))
+	} else {
+		r.Message = fmt.Sprintf("Short by %s/year in today's dollars: the projected %s nest egg supports about %s/year (%s nominal) at a %g%% withdrawal rate. Saving an extra %s/month closes the gap.",
+			formatWholeMoney(r.IncomeGapRealUSD), formatWholeMoney(nestEgg), formatWholeMoney(r.AnnualIncomeRealUSD), formatWholeMoney(income), withdrawal, formatMoney(r.ExtraMonthlyNeededUSD))
+	}
+	return r
+}
+
+// Sensitivity cases shown by fire_number_calculator
+const (
+	fireConservativeWithdrawal = 3.5 // % withdrawal rate
+	fireReturnDelta            = 1.0 // return % below expected
+)
+
+// newFireNumberTool finds the FIRE number and when the user's portfolio reaches it
+func newFireNumberTool() core.Tool {
+	return tools.New("fire_number_calculator").
+		Description("Calculate the user's FIRE (financial independence) number from annual spending and a withdrawal rate, and when their portfolio is projected to reach it, with sensitivity to a lower withdrawal rate or return").
+		Schema(tools.ObjectSchema(map[string]interface{}{
+			"annual_spending":      tools.StringProperty("Desired annual spending in retirement in the account currency"),
+			"current_savings":      tools.StringProperty("Current invested savings in the account currency"),
+			"monthly_contribution": tools.StringProperty("Amount invested each month in the account currency"),
+			"expected_return":      tools.StringProperty(fmt.Sprintf("Optional expected annual return percentage (defaults to the server's assumption, currently %g)", appConfig.Assumptions.EquityReturnPct)),
+			"withdrawal_rate":      tools.StringProperty("Optional safe withdrawal rate percentage (defaults to the server's assumption, usually 4)"),
+			"current_age":          tools.IntegerProperty("Optional current age, to report the age FIRE is reached"),
+		}, "annual_spending", "current_savings", "monthly_contribution")).
+		HandlerFunc(func(ctx context.Context, input json.RawMessage) (interface{}, error) {
+			var params struct {
+				AnnualSpending      string `json:"annual_spending"`
+				CurrentSavings      string `json:"current_savings"`
+				MonthlyContribution string `json:"monthly_contribution"`
+				ExpectedReturn      string `json:"expected_return"`
+				WithdrawalRate      string `json:"withdrawal_rate"`
+				CurrentAge          int    `json:"current_age"`
+			}
+			if err := json.Unmarshal(input, &params); err != nil {
+				return nil, fmt.Errorf("invalid input: %w", err)
+			}
+
+			var v amountValidator
+			spending := v.positive("annual_spending", params.AnnualSpending)
+			savings := v.nonNegative("current_savings", params.CurrentSavings, true)
+			monthly := v.nonNegative("monthly_contribution", params.MonthlyContribution, true)
+			returnRate := v.returnRate("expected_return", params.ExpectedReturn, false)
+			withdrawal := appConfig.WithdrawalRate
+			if strings.TrimSpace(params.WithdrawalRate) != "" {
+				withdrawal = v.positiveRate("withdrawal_rate", params.WithdrawalRate)
+				if withdrawal > maxWithdrawalRate {
+					v.fail("withdrawal_rate", "must be at most %.0f%% (got %v%%)", maxWithdrawalRate, withdrawal)
+				}
+			}
+			if params.CurrentAge < 0 || params.CurrentAge >= maxRetirementAge {
+				v.fail("current_age", "must be between 1 and %d (got %d)", maxRetirementAge-1, params.CurrentAge)
+			}
+			if err := v.err(); err != nil {
+				return nil, err
+			}
+
+			return fireNumber(spending, savings, monthly, returnRate, withdrawal, params.CurrentAge, time.Now()), nil
+		}).
+		Build()
+}
+
+// maxWithdrawalRate bounds the withdrawal_rate accepted by fire_number_calculator
+const maxWithdrawalRate = 10.0
+
+// fireNumber solves for the month the projected portfolio crosses spending / withdrawal rate.
+// currentAge 0 means unknown.
+func fireNumber(spending, savings, monthly, returnRate, withdrawal float64, currentAge int, now time.Time) FireResult {
+	r := FireResult{
+		Currency:              activeCurrency().Code,
+		AnnualSpendingUSD:     spending,
+		WithdrawalRatePercent: withdrawal,
+		AssumedReturnPercent:  returnRate,
+		CurrentSavingsUSD:     savings,
+		MonthlyContribution:   monthly,
+		FireNumberUSD:         spending / (withdrawal / 100),
+	}
+	r.Base = fireScenario(r.FireNumberUSD, savings, monthly, returnRate, currentAge, now)
+	r.LowerWithdrawal = fireScenario(spending/(fireConservativeWithdrawal/100), savings, monthly, returnRate, currentAge, now)
+	r.LowerReturn = fireScenario(r.FireNumberUSD, savings, monthly, returnRate-fireReturnDelta, currentAge, now)
+
+	switch {
+	case r.Base.Months == 0:
+		r.Message = fmt.Sprintf("You've reached FIRE: %s covers %s/year at a %g%% withdrawal rate.", formatWholeMoney(savings), formatWholeMoney(spending), withdrawal)
+	case r.Base.Months < 0:
+		r.Message = fmt.Sprintf("At %s/month the portfolio doesn't reach the %s FIRE number within 100 years.", formatWholeMoney(monthly), formatWholeMoney(r.FireNumberUSD))
+	default:
+		r.Message = fmt.Sprintf("FIRE number: %s. Projected to get there in %.1f years (%s). At a %g%% withdrawal rate it's %.1f years; with returns %g%% lower, %.1f years.",
+			formatWholeMoney(r.FireNumberUSD), r.Base.Years, r.Base.Date, fireConservativeWithdrawal, r.LowerWithdrawal.Years, fireReturnDelta, r.LowerReturn.Years)
+	}
+	return r
+}
+
+// fireScenario finds when target is reached; Months is -1 if not within 100 years
+func fireScenario(target, savings, monthly, returnRate float64, currentAge int, now time.Time) FireScenario {
+	months := monthsToReach(target, savings, monthly, returnRate)
+	s := FireScenario{FireNumberUSD: target, AssumedReturnPercent: returnRate, Months: months}
+	if months < 0 {
+		return s
+	}
+	s.Years = float64(months) / 12
+	s.Date = now.AddDate(0, months, 0).Format("2006-01")
+	if currentAge > 0 {
+		s.Age = float64(currentAge) + s.Years
+	}
+	return s
+}
+
+// newWithdrawalPlannerTool answers "how much can I pull out each month without running out?"
+func newWithdrawalPlannerTool() core.Tool {
+	return tools.New("safe_withdrawal_planner").
+		Description("Plan withdrawals from a portfolio: simulate the balance under a monthly withdrawal, report when (or whether) it runs out, and the most that can be withdrawn monthly over the horizon").
+		Schema(tools.ObjectSchema(map[string]interface{}{
+			"starting_balance":   tools.StringProperty("Portfolio balance at the start of withdrawals in the account currency"),
+			"monthly_withdrawal": tools.StringProperty("Amount withdrawn each month in the account currency"),
+			"years":              tools.StringProperty("Years the money needs to last, 1-60"),
+			"expected_return":    tools.StringProperty(fmt.Sprintf("Optional expected annual return percentage (defaults to the server's assumption, currently %g)", appConfig.Assumptions.EquityReturnPct)),
+			"current_age":        tools.IntegerProperty("Optional current age, to report the depletion age"),
+		}, "starting_balance", "monthly_withdrawal", "years")).
+		HandlerFunc(func(ctx context.Context, input json.RawMessage) (interface{}, error) {
+			var params struct {
+				StartingBalance   string `json:"starting_balance"`
+				MonthlyWithdrawal string `json:"monthly_withdrawal"`
+				Years             string `json:"years"`
+				ExpectedReturn    string `json:"expected_return"`
+				CurrentAge        int    `json:"current_age"`
+			}
+			if err := json.Unmarshal(input, &params); err != nil {
+				return nil, fmt.Errorf("invalid input: %w", err)
+			}
+
+			var v amountValidator
+			balance := v.positive("starting_balance", params.StartingBalance)
+			withdrawal := v.nonNegative("monthly_withdrawal", params.MonthlyWithdrawal, true)
+			years := v.years("years", params.Years, minProjectionYears, maxProjectionYears)
+			returnRate := v.returnRate("expected_return", params.ExpectedReturn, false)
+			if params.CurrentAge < 0 || params.CurrentAge >= maxRetirementAge {
+				v.fail("current_age", "must be between 1 and %d (got %d)", maxRetirementAge-1, params.CurrentAge)
+			}
+			if err := v.err(); err != nil {
+				return nil, err
+			}
+
+			return withdrawalPlan(balance, withdrawal, returnRate, years, params.CurrentAge), nil
+		}).
+		Build()
+}
+
+// withdrawalPlan runs end-of-month withdrawals through futureValue (a withdrawal is a negative
+// contribution). currentAge 0 means unknown.
+func withdrawalPlan(balance, withdrawal, returnRate, years float64, currentAge int) WithdrawalPlan {
+	months := max(int(math.Round(years*12)), 1)
+	monthlyRate := returnRate / 100 / 12
+
+	p := WithdrawalPlan{
+		Currency:             activeCurrency().Code,
+		StartingBalanceUSD:   balance,
+		MonthlyWithdrawalUSD: withdrawal,
+		AssumedReturnPercent: returnRate,
+		HorizonYears:         years,
+		// The payment that takes the balance to exactly zero at the horizon
+		MaxSustainableMonthlyUSD: futureValue(balance, 0, returnRate, float64(months)) / futureValue(0, 1, returnRate, float64(months)),
+	}
+
+	depleted, depletes := monthsUntilDepleted(balance, withdrawal, monthlyRate)
+	p.NeverDepletes = !depletes
+	for m := 0; m <= months; m++ {
+		if m%12 != 0 && m != months {
+			continue
+		}
+		b := max(futureValue(balance, -withdrawal, returnRate, float64(m)), 0)
+		p.Trajectory = append(p.Trajectory, WithdrawalYear{Year: float64(m) / 12, BalanceUSD: b})
+	}
+	if depletes {
+		p.DepletionMonths = depleted
+		p.DepletionYears = float64(depleted) / 12
+		if currentAge > 0 {
+			p.DepletionAge = float64(currentAge) + p.DepletionYears
+		}
+	}
+	p.LastsHorizon = p.NeverDepletes || depleted > months
+
+	switch {
+	case withdrawal <= 0:
+		p.Message = fmt.Sprintf("With no withdrawals the balance never runs out. Up to %s/month would last the full %g years.",
+			formatMoney(p.MaxSustainableMonthlyUSD), years)
+	case p.NeverDepletes:
+		p.Message = fmt.Sprintf("%s/month is covered by growth alone at %g%% - the balance never runs out. Up to %s/month would last the full %g years.",
+			formatMoney(withdrawal), returnRate, formatMoney(p.MaxSustainableMonthlyUSD), years)
+	case p.LastsHorizon:
+		p.Message = fmt.Sprintf("%s/month lasts %.1f years, beyond your %g-year horizon. Up to %s/month would last exactly %g years.",
+			formatMoney(withdrawal), p.DepletionYears, years, formatMoney(p.MaxSustainableMonthlyUSD), years)
+	default:
+		p.Message = fmt.Sprintf("%s/month runs out after %.1f years, short of your %g-year horizon. %s/month would last the full %g years.",
+			formatMoney(withdrawal), p.DepletionYears, years, formatMoney(p.MaxSustainableMonthlyUSD), years)
+	}
+	return p
+}
+
+// monthsUntilDepleted is the month the balance can no longer cover a withdrawal. depletes is
+// false when it never runs out: the withdrawal is covered by growth, or there is no withdrawal,
+// which a negative return shrinks towards zero but never reaches.
+func monthsUntilDepleted(balance, withdrawal, monthlyRate float64) (months int, depletes bool) {
+	if withdrawal <= 0 || withdrawal <= balance*monthlyRate {
+		return 0, false
+	}
+	if math.Abs(monthlyRate) < 1e-12 {
+		return int(math.Ceil(balance / withdrawal)), true
+	}
+	// Solve balance*(1+r)^n - withdrawal*((1+r)^n - 1)/r = 0 for n
+	return int(math.Ceil(math.Log(withdrawal/(withdrawal-balance*monthlyRate)) / math.Log(1+monthlyRate))), true
+}
@@ -0,0 +1,36 @@
+package main
+
+import "testing"
+
+func TestMonthsUntilDepleted(t *testing.T) {
+	tests := []struct {
+		name                         string
+		balance, withdrawal, monthly float64
+		wantMonths                   int
+		wantDepletes                 bool
+	}{
+		{name: "no withdrawal, negative return", balance: 100000, withdrawal: 0, monthly: -0.05 / 12, wantDepletes: false},
+		{name: "no withdrawal, zero return", balance: 100000, withdrawal: 0, monthly: 0, wantDepletes: false},
+		{name: "no withdrawal, positive return", balance: 100000, withdrawal: 0, monthly: 0.05 / 12, wantDepletes: false},
+		{name: "covered by growth", balance: 120000, withdrawal: 500, monthly: 0.06 / 12, wantDepletes: false},
+		{name: "zero return", balance: 10000, withdrawal: 1000, monthly: 0, wantMonths: 10, wantDepletes: true},
+		{name: "zero return, partial month", balance: 10500, withdrawal: 1000, monthly: 0, wantMonths: 11, wantDepletes: true},
+		{name: "negative return", balance: 10000, withdrawal: 1000, monthly: -0.1 / 12, wantMonths: 10, wantDepletes: true},
+		{name: "positive return", balance: 100000, withdrawal: 1000, monthly: 0.04 / 12, wantMonths: 122, wantDepletes: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			months, depletes := monthsUntilDepleted(tt.balance, tt.withdrawal, tt.monthly)
+			if depletes != tt.wantDepletes || months != tt.wantMonths {
+				t.Errorf("monthsUntilDepleted = %d, %v; want %d, %v", months, depletes, tt.wantMonths, tt.wantDepletes)
+			}
+		})
+	}
+}
+
+func TestWithdrawalPlanWithoutWithdrawals(t *testing.T) {
+	p := withdrawalPlan(50000, 0, -3, 20, 60)
+	if !p.NeverDepletes || !p.LastsHorizon || p.DepletionMonths != 0 || p.DepletionAge != 0 {
+		t.Errorf("withdrawalPlan(no withdrawal, -3%%) = %+v, want never depletes", p)
+	}
+}
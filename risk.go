@@ -0,0 +1,136 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strings"
+)
+
+// ============================================
+// CANONICAL RISK LEVELS
+// ============================================
+
+// RiskLevel is the single vocabulary shared by every risk-aware tool and cache
+type RiskLevel string
+
+const (
+	RiskConservative         RiskLevel = "Conservative"
+	RiskModerate             RiskLevel = "Moderate"
+	RiskModerateToAggressive RiskLevel = "Moderate-to-Aggressive"
+	RiskAggressive           RiskLevel = "Aggressive"
+)
+
+// riskLevels lists the canonical levels from least to most aggressive
+var riskLevels = []RiskLevel{RiskConservative, RiskModerate, RiskModerateToAggressive, RiskAggressive}
+
+// riskLevelKeys are the canonical levels as they are typed, e.g. "moderate-to-aggressive"
+func riskLevelKeys() []string {
+	keys := make([]string, len(riskLevels))
+	for i, level := range riskLevels {
+		keys[i] = strings.ToLower(string(level))
+	}
+	return keys
+}
+
+// riskLevelAliases maps normalized user-facing spellings onto canonical levels
+var riskLevelAliases = map[string]RiskLevel{
+	"conservative":           RiskConservative,
+	"low":                    RiskConservative,
+	"moderate":               RiskModerate,
+	"medium":                 RiskModerate,
+	"balanced":               RiskModerate,
+	"moderate-to-aggressive": RiskModerateToAggressive,
+	"moderately-aggressive":  RiskModerateToAggressive,
+	"moderate-aggressive":    RiskModerateToAggressive,
+	"growth":                 RiskModerateToAggressive,
+	"aggressive":             RiskAggressive,
+	"high":                   RiskAggressive,
+}
+
+// normalizeRiskLevel maps any supported spelling or case onto a canonical RiskLevel
+func normalizeRiskLevel(s string) (RiskLevel, error) {
+	key := strings.ToLower(strings.TrimSpace(s))
+	key = strings.NewReplacer(" ", "-", "_", "-").Replace(key)
+	if level, ok := riskLevelAliases[key]; ok {
+		return level, nil
+	}
+	return "", fmt.Errorf("unknown risk level %q: expected one of %s", s, strings.Join(riskLevelKeys(), ", "))
+}
+
+// ageRiskBand is one age range and its contribution to the risk score
+type ageRiskBand struct {
+	maxAge int // inclusive upper bound
+	score  int
+	label  string
+	note   string
+}
+
+// ageRiskBands covers every non-negative age; the first band whose maxAge >= age wins
+var ageRiskBands = []ageRiskBand{
+	{17, 70, "under_18", "Under 18: investing usually requires a custodial account opened by a parent or guardian"},
+	{34, 70, "18-34", ""},
+	{49, 50, "35-49", ""},
+	{100, 30, "50-100", ""},
+	{math.MaxInt, 30, "over_100", "Age above 100 - please double-check this input"},
+}
+
+// ageRiskBandFor returns the scoring band for an age, rejecting negative ages
+func ageRiskBandFor(age int) (ageRiskBand, error) {
+	if age < 0 {
+		return ageRiskBand{}, fmt.Errorf("invalid age %d: age cannot be negative", age)
+	}
+	for _, band := range ageRiskBands {
+		if age <= band.maxAge {
+			return band, nil
+		}
+	}
+	return ageRiskBands[len(ageRiskBands)-1], nil
+}
+
+// horizonRiskBand adjusts the score for how long until the money is needed
+type horizonRiskBand struct {
+	maxYears int // inclusive upper bound
+	score    int
+	label    string
+}
+
+// horizonRiskBands lets a long runway add equity risk even for older users, and a short one take it away
+var horizonRiskBands = []horizonRiskBand{
+	{2, -40, "under_3"},
+	{4, -25, "3-4"},
+	{9, -10, "5-9"},
+	{19, 0, "10-19"},
+	{29, 10, "20-29"},
+	{math.MaxInt, 20, "30_plus"},
+}
+
+// horizonRiskBandFor returns the scoring band for years to retirement, rejecting negative values
+func horizonRiskBandFor(years int) (horizonRiskBand, error) {
+	if years < 0 {
+		return horizonRiskBand{}, fmt.Errorf("invalid years_to_retirement %d: cannot be negative", years)
+	}
+	for _, band := range horizonRiskBands {
+		if years <= band.maxYears {
+			return band, nil
+		}
+	}
+	return horizonRiskBands[len(horizonRiskBands)-1], nil
+}
+
+// resolveRiskLevel is the risk level given in field, or the user's profile when raw is empty;
+// source is field or "profile"
+func resolveRiskLevel(ctx context.Context, userID, field, raw string) (level RiskLevel, source string, err error) {
+	if strings.TrimSpace(raw) != "" {
+		level, err := normalizeRiskLevel(raw)
+		if err != nil {
+			return "", "", fmt.Errorf("invalid %s: %w", field, err)
+		}
+		return level, field, nil
+	}
+	portfolio, err := loadPortfolio(ctx, userID)
+	if err != nil {
+		return "", "", profileFieldError(err, field)
+	}
+	return portfolio.RiskTolerance, "profile", nil
+}
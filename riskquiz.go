@@ -0,0 +1,322 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/becomeliminal/nim-go-sdk/core"
+	"github.com/becomeliminal/nim-go-sdk/tools"
+)
+
+// ============================================
+// MULTI-TURN RISK QUESTIONNAIRE
+// ============================================
+// The same questions as assess_investment_risk_profile, asked one at a time.
+// Answers live in memory per user until finish_risk_assessment scores them or
+// the session sits idle longer than RISK_SESSION_TTL; an expired session is
+// dropped and the questionnaire starts over.
+
+// riskSkipAnswer skips an optional question
+const riskSkipAnswer = "skip"
+
+// riskQuestion is one questionnaire step
+type riskQuestion struct {
+	ID       string   `json:"id"`
+	Prompt   string   `json:"prompt"`
+	Options  []string `json:"options,omitempty"`
+	Optional bool     `json:"optional"`
+	kind     string   // "count" or "percent" when there are no options
+}
+
+// riskQuestions are asked in order; the first four are the single-shot tool's required inputs
+var riskQuestions = []riskQuestion{
+	{ID: "age", Prompt: "How old are you?", kind: "count"},
+	{ID: "years_to_retirement", Prompt: "How many years until you plan to retire or need this money?", kind: "count"},
+	{ID: "market_downturn_comfort", Prompt: "How would you feel if your investments fell 20% in a market drop?", Options: downturnComforts},
+	{ID: "previous_experience", Prompt: "How much investing experience do you have?", Options: experienceLevels},
+	{ID: "income_stability", Prompt: "How stable is your income?", Options: incomeStabilities, Optional: true},
+	{ID: "number_of_dependents", Prompt: "How many people depend on your income?", Optional: true, kind: "count"},
+	{ID: "net_worth_invested_percent", Prompt: "Roughly what percentage of your net worth are you investing?", Optional: true, kind: "percent"},
+	{ID: "loss_reaction", Prompt: fmt.Sprintf("If your %s investment dropped to %s in a month, would you sell, hold, or buy more?",
+		formatWholeMoney(10000), formatWholeMoney(8000)), Options: lossReactions, Optional: true},
+}
+
+// findRiskQuestion looks a question up by ID
+func findRiskQuestion(id string) (riskQuestion, bool) {
+	i := slices.IndexFunc(riskQuestions, func(q riskQuestion) bool { return q.ID == id })
+	if i < 0 {
+		return riskQuestion{}, false
+	}
+	return riskQuestions[i], true
+}
+
+// normalizeRiskAnswer validates an answer for q; skipped optional questions normalize to riskSkipAnswer
+func normalizeRiskAnswer(q riskQuestion, raw string) (string, error) {
+	value := strings.ToLower(strings.TrimSpace(raw))
+	if value == riskSkipAnswer && q.Optional {
+		return riskSkipAnswer, nil
+	}
+	var v amountValidator
+	switch {
+	case q.Options != nil:
+		value = v.oneOf(q.ID, raw, q.Options)
+	case q.kind == "count":
+		n, err := strconv.Atoi(value)
+		if err != nil || n < 0 {
+			v.fail(q.ID, "%q must be a whole number, zero or more", raw)
+		}
+		value = strconv.Itoa(n)
+	default: // percent
+		n, err := strconv.ParseFloat(strings.TrimSuffix(value, "%"), 64)
+		if err != nil || n < 0 || n > 100 {
+			v.fail(q.ID, "%q must be a percentage between 0 and 100", raw)
+		}
+		value = strconv.FormatFloat(n, 'f', -1, 64)
+	}
+	return value, v.err()
+}
+
+// riskSession is one user's questionnaire in progress
+type riskSession struct {
+	answers    map[string]string // question ID -> normalized answer or riskSkipAnswer
+	lastActive time.Time
+}
+
+// riskSessionStore holds sessions in memory; they are short-lived and not worth persisting
+type riskSessionStore struct {
+	mu       sync.Mutex
+	sessions map[string]*riskSession
+}
+
+// riskSessions is shared by the three questionnaire tools
+var riskSessions = &riskSessionStore{sessions: map[string]*riskSession{}}
+
+// load returns the user's live session, dropping it (and any other idle session) once expired.
+// expired reports whether the user's own session had timed out.
+func (s *riskSessionStore) load(userID string, now time.Time) (session *riskSession, expired bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for id, rs := range s.sessions {
+		if now.Sub(rs.lastActive) > appConfig.RiskSessionTTL {
+			delete(s.sessions, id)
+			expired = expired || id == userID
+		}
+	}
+	return s.sessions[userID], expired
+}
+
+// start replaces the user's session with an empty one
+func (s *riskSessionStore) start(userID string, now time.Time) *riskSession {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	rs := &riskSession{answers: map[string]string{}, lastActive: now}
+	s.sessions[userID] = rs
+	return rs
+}
+
+// record stores an answer and marks the session active
+func (s *riskSessionStore) record(rs *riskSession, questionID, answer string, now time.Time) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	rs.answers[questionID] = answer
+	rs.lastActive = now
+}
+
+// end discards the user's session
+func (s *riskSessionStore) end(userID string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.sessions, userID)
+}
+
+// state summarizes a session for the model: what's answered and what to ask next
+func (s *riskSessionStore) state(rs *riskSession) RiskSessionState {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	st := RiskSessionState{
+		Answers:             map[string]string{},
+		Remaining:           []string{},
+		IdleTimeoutMinutes:  appConfig.RiskSessionTTL.Minutes(),
+		ReadyToFinish:       true,
+		TotalQuestions:      len(riskQuestions),
+		AnsweredQuestionIDs: []string{},
+	}
+	for _, q := range riskQuestions {
+		if answer, ok := rs.answers[q.ID]; ok {
+			st.Answers[q.ID] = answer
+			st.AnsweredQuestionIDs = append(st.AnsweredQuestionIDs, q.ID)
+			continue
+		}
+		st.Remaining = append(st.Remaining, q.ID)
+		if !q.Optional {
+			st.ReadyToFinish = false
+		}
+		if st.NextQuestion == nil {
+			next := q
+			st.NextQuestion = &next
+		}
+	}
+	switch {
+	case st.NextQuestion == nil:
+		st.Message = "All questions answered: call finish_risk_assessment for the profile."
+	case st.ReadyToFinish:
+		st.Message = fmt.Sprintf("Required questions answered. Ask %q next (optional, the user can say skip) or call finish_risk_assessment now.", st.NextQuestion.Prompt)
+	default:
+		st.Message = fmt.Sprintf("Ask the user: %q", st.NextQuestion.Prompt)
+	}
+	return st
+}
+
+// riskAnswersFromSession converts stored answers into scoring input; skipped questions stay unanswered
+func riskAnswersFromSession(answers map[string]string) riskAnswers {
+	a := riskAnswers{
+		DownturnComfort: answers["market_downturn_comfort"],
+		Experience:      answers["previous_experience"],
+	}
+	a.Age, _ = strconv.Atoi(answers["age"])
+	a.YearsToRetirement, _ = strconv.Atoi(answers["years_to_retirement"])
+	if v := answers["income_stability"]; v != riskSkipAnswer {
+		a.IncomeStability = v
+	}
+	if v := answers["loss_reaction"]; v != riskSkipAnswer {
+		a.LossReaction = v
+	}
+	if v, ok := answers["number_of_dependents"]; ok && v != riskSkipAnswer {
+		n, _ := strconv.Atoi(v)
+		a.Dependents = &n
+	}
+	if v, ok := answers["net_worth_invested_percent"]; ok && v != riskSkipAnswer {
+		pct, _ := strconv.ParseFloat(v, 64)
+		a.NetWorthInvestedPct = &pct
+	}
+	return a
+}
+
+// expiredSessionNote tells the model earlier answers are gone
+func expiredSessionNote() string {
+	return fmt.Sprintf("The previous questionnaire was idle for over %.0f minutes and expired, so it restarted from the first question. ", appConfig.RiskSessionTTL.Minutes())
+}
+
+// newBeginRiskAssessmentTool starts or resumes the user's questionnaire
+func newBeginRiskAssessmentTool() core.Tool {
+	return tools.New("begin_risk_assessment").
+		Description("Start (or resume) a risk tolerance questionnaire asked one question per turn. Returns the answers so far and the next question to ask; use answer_risk_question for each reply and finish_risk_assessment for the profile").
+		Schema(tools.ObjectSchema(map[string]interface{}{
+			"restart": map[string]interface{}{
+				"type":        "boolean",
+				"description": "Optional: discard any answers so far and start over",
+			},
+		})).
+		Handler(func(ctx context.Context, toolParams *core.ToolParams) (*core.ToolResult, error) {
+			var params struct {
+				Restart bool `json:"restart"`
+			}
+			if err := json.Unmarshal(toolParams.Input, &params); err != nil {
+				return &core.ToolResult{Success: false, Error: fmt.Sprintf("invalid input: %v", err)}, nil
+			}
+
+			user, now := userKey(toolParams.UserID), time.Now()
+			session, expired := riskSessions.load(user, now)
+			resumed := session != nil && !params.Restart
+			if !resumed {
+				session = riskSessions.start(user, now)
+			}
+			state := riskSessions.state(session)
+			state.Resumed = resumed
+			if expired {
+				state.Message = expiredSessionNote() + state.Message
+			}
+			return &core.ToolResult{Success: true, Data: state}, nil
+		}).
+		Build()
+}
+
+// newAnswerRiskQuestionTool records one answer and returns the next question
+func newAnswerRiskQuestionTool() core.Tool {
+	return tools.New("answer_risk_question").
+		Description("Record the user's answer to one risk questionnaire question and get the next unanswered question. Answers can come in any order; optional questions accept 'skip'").
+		Schema(tools.ObjectSchema(map[string]interface{}{
+			"question_id": tools.StringProperty("ID of the question being answered, e.g. 'age' or 'loss_reaction'"),
+			"answer":      tools.StringProperty("The user's answer: a number, or one of the question's options"),
+		}, "question_id", "answer")).
+		Handler(func(ctx context.Context, toolParams *core.ToolParams) (*core.ToolResult, error) {
+			var params struct {
+				QuestionID string `json:"question_id"`
+				Answer     string `json:"answer"`
+			}
+			if err := json.Unmarshal(toolParams.Input, &params); err != nil {
+				return &core.ToolResult{Success: false, Error: fmt.Sprintf("invalid input: %v", err)}, nil
+			}
+
+			q, ok := findRiskQuestion(strings.ToLower(strings.TrimSpace(params.QuestionID)))
+			if !ok {
+				ids := make([]string, len(riskQuestions))
+				for i, q := range riskQuestions {
+					ids[i] = q.ID
+				}
+				return &core.ToolResult{Success: false, Error: fmt.Sprintf("unknown question_id %q: expected one of %s", params.QuestionID, strings.Join(ids, ", "))}, nil
+			}
+			answer, err := normalizeRiskAnswer(q, params.Answer)
+			if err != nil {
+				return &core.ToolResult{Success: false, Error: err.Error()}, nil
+			}
+
+			user, now := userKey(toolParams.UserID), time.Now()
+			session, expired := riskSessions.load(user, now)
+			if session == nil {
+				session = riskSessions.start(user, now)
+			}
+			riskSessions.record(session, q.ID, answer, now)
+			state := riskSessions.state(session)
+			if expired {
+				state.Message = expiredSessionNote() + state.Message
+			}
+			return &core.ToolResult{Success: true, Data: state}, nil
+		}).
+		Build()
+}
+
+// newFinishRiskAssessmentTool scores the session into the same profile assess_investment_risk_profile returns
+func newFinishRiskAssessmentTool() core.Tool {
+	return tools.New("finish_risk_assessment").
+		Description("Score the risk questionnaire collected with answer_risk_question and return the risk profile (same format as assess_investment_risk_profile). Unanswered optional questions are left out of the score").
+		Schema(tools.ObjectSchema(map[string]interface{}{})).
+		Handler(func(ctx context.Context, toolParams *core.ToolParams) (*core.ToolResult, error) {
+			user, now := userKey(toolParams.UserID), time.Now()
+			session, expired := riskSessions.load(user, now)
+			if session == nil {
+				msg := "No risk questionnaire in progress: call begin_risk_assessment first."
+				if expired {
+					msg = fmt.Sprintf("The risk questionnaire was idle for over %.0f minutes and expired: call begin_risk_assessment to start again.", appConfig.RiskSessionTTL.Minutes())
+				}
+				return &core.ToolResult{Success: false, Error: msg}, nil
+			}
+
+			state := riskSessions.state(session)
+			if !state.ReadyToFinish {
+				var missing []string
+				for _, id := range state.Remaining {
+					if q, _ := findRiskQuestion(id); !q.Optional {
+						missing = append(missing, id)
+					}
+				}
+				return &core.ToolResult{Success: false, Error: fmt.Sprintf("questionnaire incomplete: still need %s", strings.Join(missing, ", "))}, nil
+			}
+
+			answers := riskAnswersFromSession(state.Answers)
+			profile, err := assessRiskProfile(answers, userLocale(ctx, toolParams.UserID))
+			if err != nil {
+				return &core.ToolResult{Success: false, Error: err.Error()}, nil
+			}
+			riskSessions.end(user)
+			saveExperience(ctx, toolParams.UserID, answers.Experience)
+			return &core.ToolResult{Success: true, Data: profile}, nil
+		}).
+		Build()
+}
@@ -0,0 +1,232 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"strings"
+)
+
+// ============================================
+// RISK SCORING
+// ============================================
+// The questionnaire score is a plain sum of per-factor points, kept free of tool
+// plumbing so the weights can be checked on their own. Age, horizon, downturn
+// comfort and experience are always scored; the newer answers are optional and
+// score zero when omitted, so older callers get the same result as before.
+//
+// Weighting (points per answer):
+//
+//	age                      18-34 +70, 35-49 +50, 50+ +30 (under 18: +70)
+//	years_to_retirement      under 3 -40, 3-4 -25, 5-9 -10, 10-19 0, 20-29 +10, 30+ +20
+//	market_downturn_comfort  very_uncomfortable +10 ... very_comfortable +75
+//	previous_experience      none -20, minimal -10, moderate 0, extensive +15
+//	income_stability         stable +10, moderate 0, unstable -15
+//	number_of_dependents     0 +5, 1-2 0, 3+ -10
+//	net_worth_invested       up to 25% +5, up to 50% 0, up to 75% -10, above 75% -20
+//	loss_reaction            sell -30, hold 0, buy_more +15
+//
+// The loss reaction carries the largest swing of the optional answers: what people
+// say they'd do in a drop predicts behaviour better than how comfortable they feel.
+
+// Risk score lookup tables
+var comfortRiskScore = map[string]int{
+	"very_uncomfortable":     10,
+	"somewhat_uncomfortable": 25,
+	"neutral":                40,
+	"comfortable":            60,
+	"very_comfortable":       75,
+}
+
+// downturnComforts lists comfortRiskScore's keys from least to most comfortable
+var downturnComforts = []string{"very_uncomfortable", "somewhat_uncomfortable", "neutral", "comfortable", "very_comfortable"}
+
+var experienceRiskScore = map[string]int{
+	"none":      -20,
+	"minimal":   -10,
+	"moderate":  0,
+	"extensive": 15,
+}
+
+// experienceLevels lists experienceRiskScore's keys for validation
+var experienceLevels = []string{"none", "minimal", "moderate", "extensive"}
+
+// incomeStabilityRiskScore uses the emergency fund calculator's stability vocabulary
+var incomeStabilityRiskScore = map[string]int{
+	"stable":   10,
+	"moderate": 0,
+	"unstable": -15,
+}
+
+// lossReactionRiskScore scores "if your $10k dropped to $8k in a month you would..."
+var lossReactionRiskScore = map[string]int{
+	"sell":     -30,
+	"hold":     0,
+	"buy_more": 15,
+}
+
+// lossReactions lists lossReactionRiskScore's keys for validation
+var lossReactions = []string{"sell", "hold", "buy_more"}
+
+// dependentsRiskBands: the first band whose maxDependents >= the count wins
+var dependentsRiskBands = []struct {
+	maxDependents int
+	score         int
+}{
+	{0, 5},
+	{2, 0},
+	{math.MaxInt, -10},
+}
+
+// netWorthRiskBands: the first band whose maxPercent >= the share invested wins
+var netWorthRiskBands = []struct {
+	maxPercent float64
+	score      int
+}{
+	{25, 5},
+	{50, 0},
+	{75, -10},
+	{math.Inf(1), -20},
+}
+
+// Score thresholds for the recommended level
+const (
+	riskScoreModerate             = 40 // above this: Moderate
+	riskScoreModerateToAggressive = 60 // above this: Moderate-to-Aggressive
+)
+
+// riskAnswers are the questionnaire answers; empty strings and nil pointers mean "not answered"
+type riskAnswers struct {
+	Age                 int
+	YearsToRetirement   int
+	DownturnComfort     string
+	Experience          string
+	IncomeStability     string
+	Dependents          *int
+	NetWorthInvestedPct *float64
+	LossReaction        string
+}
+
+// riskScore is the scored questionnaire
+type riskScore struct {
+	AgeBand     ageRiskBand
+	HorizonBand horizonRiskBand
+	Points      map[string]int // points per answered factor, keyed by input name
+	Total       int
+	Level       RiskLevel
+}
+
+// scoreRiskAnswers sums every answered factor's points and maps the total onto a risk level.
+// Unknown answers to the original four questions contribute nothing, as they always have.
+func scoreRiskAnswers(a riskAnswers) (riskScore, error) {
+	band, err := ageRiskBandFor(a.Age)
+	if err != nil {
+		return riskScore{}, err
+	}
+	horizon, err := horizonRiskBandFor(a.YearsToRetirement)
+	if err != nil {
+		return riskScore{}, err
+	}
+
+	s := riskScore{AgeBand: band, HorizonBand: horizon, Points: map[string]int{
+		"age":                     band.score,
+		"years_to_retirement":     horizon.score,
+		"market_downturn_comfort": comfortRiskScore[a.DownturnComfort],
+		"previous_experience":     experienceRiskScore[a.Experience],
+	}}
+	if a.IncomeStability != "" {
+		s.Points["income_stability"] = incomeStabilityRiskScore[a.IncomeStability]
+	}
+	if a.Dependents != nil {
+		if *a.Dependents < 0 {
+			return riskScore{}, fmt.Errorf("invalid number_of_dependents %d: cannot be negative", *a.Dependents)
+		}
+		for _, b := range dependentsRiskBands {
+			if *a.Dependents <= b.maxDependents {
+				s.Points["number_of_dependents"] = b.score
+				break
+			}
+		}
+	}
+	if a.NetWorthInvestedPct != nil {
+		pct := *a.NetWorthInvestedPct
+		if pct < 0 || pct > 100 {
+			return riskScore{}, fmt.Errorf("invalid net_worth_invested_percent %v: must be between 0 and 100", pct)
+		}
+		for _, b := range netWorthRiskBands {
+			if pct <= b.maxPercent {
+				s.Points["net_worth_invested_percent"] = b.score
+				break
+			}
+		}
+	}
+	if a.LossReaction != "" {
+		s.Points["loss_reaction"] = lossReactionRiskScore[a.LossReaction]
+	}
+
+	for _, p := range s.Points {
+		s.Total += p
+	}
+	s.Level = RiskConservative
+	if s.Total > riskScoreModerateToAggressive {
+		s.Level = RiskModerateToAggressive
+	} else if s.Total > riskScoreModerate {
+		s.Level = RiskModerate
+	}
+	return s, nil
+}
+
+// riskWeighting describes every factor's points from the tables above, so the explanation
+// the assistant gives can't drift from the scorer
+func riskWeighting() string {
+	points := func(n int) string {
+		if n == 0 {
+			return "0"
+		}
+		return fmt.Sprintf("%+d", n)
+	}
+	keyed := func(keys []string, score map[string]int) string {
+		parts := make([]string, len(keys))
+		for i, k := range keys {
+			parts[i] = k + " " + points(score[k])
+		}
+		return strings.Join(parts, ", ")
+	}
+
+	var age, horizon, dependents, netWorth []string
+	for _, b := range ageRiskBands {
+		age = append(age, b.label+" "+points(b.score))
+	}
+	for _, b := range horizonRiskBands {
+		horizon = append(horizon, b.label+" "+points(b.score))
+	}
+	low := 0
+	for _, b := range dependentsRiskBands {
+		label := fmt.Sprintf("%d-%d", low, b.maxDependents)
+		switch {
+		case b.maxDependents == math.MaxInt:
+			label = fmt.Sprintf("%d+", low)
+		case b.maxDependents == low:
+			label = fmt.Sprint(low)
+		}
+		dependents = append(dependents, label+" "+points(b.score))
+		low = b.maxDependents + 1
+	}
+	for i, b := range netWorthRiskBands {
+		label := fmt.Sprintf("up to %g%%", b.maxPercent)
+		if math.IsInf(b.maxPercent, 1) {
+			label = fmt.Sprintf("above %g%%", netWorthRiskBands[i-1].maxPercent)
+		}
+		netWorth = append(netWorth, label+" "+points(b.score))
+	}
+
+	return "points are summed per answer; optional answers left out score 0. " + strings.Join([]string{
+		"age: " + strings.Join(age, ", "),
+		"years_to_retirement: " + strings.Join(horizon, ", "),
+		"market_downturn_comfort: " + keyed(downturnComforts, comfortRiskScore),
+		"previous_experience: " + keyed(experienceLevels, experienceRiskScore),
+		"income_stability: " + keyed(incomeStabilities, incomeStabilityRiskScore),
+		"number_of_dependents: " + strings.Join(dependents, ", "),
+		"net_worth_invested_percent: " + strings.Join(netWorth, ", "),
+		"loss_reaction: " + keyed(lossReactions, lossReactionRiskScore),
+	}, "; ")
+}
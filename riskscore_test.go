@@ -0,0 +1,177 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestHorizonChangesRecommendedRiskLevel(t *testing.T) {
+	// Same person each time (age 40 +50, somewhat uncomfortable +25, minimal experience -10),
+	// so only the horizon moves the score
+	tests := []struct {
+		years int
+		band  string
+		score int
+		want  RiskLevel
+	}{
+		{years: 2, band: "under_3", score: 25, want: RiskConservative},
+		{years: 7, band: "5-9", score: 55, want: RiskModerate},
+		{years: 25, band: "20-29", score: 75, want: RiskModerateToAggressive},
+	}
+	for _, tt := range tests {
+		answers := riskAnswers{Age: 40, YearsToRetirement: tt.years, DownturnComfort: "somewhat_uncomfortable", Experience: "minimal"}
+		profile, err := assessRiskProfile(answers, "en")
+		if err != nil {
+			t.Fatalf("%d years: %v", tt.years, err)
+		}
+		if got := profile["recommended_risk_level"]; got != tt.want {
+			t.Errorf("%d years: recommended_risk_level = %v, want %v", tt.years, got, tt.want)
+		}
+		if got := profile["horizon_band"]; got != tt.band {
+			t.Errorf("%d years: horizon_band = %v, want %v", tt.years, got, tt.band)
+		}
+		if got := profile["risk_score"]; got != tt.score {
+			t.Errorf("%d years: risk_score = %v, want %v", tt.years, got, tt.score)
+		}
+	}
+}
+
+func TestScoreRiskAnswersBands(t *testing.T) {
+	intp := func(n int) *int { return &n }
+	floatp := func(f float64) *float64 { return &f }
+	// base scores 0 on everything but age (35-49, +50) and comfort (neutral, +40)
+	base := func() riskAnswers {
+		return riskAnswers{Age: 40, YearsToRetirement: 15, DownturnComfort: "neutral", Experience: "moderate"}
+	}
+	tests := []struct {
+		name   string
+		modify func(a *riskAnswers)
+		factor string
+		want   int
+	}{
+		{"age 17", func(a *riskAnswers) { a.Age = 17 }, "age", 70},
+		{"age 18", func(a *riskAnswers) { a.Age = 18 }, "age", 70},
+		{"age 34", func(a *riskAnswers) { a.Age = 34 }, "age", 70},
+		{"age 35", func(a *riskAnswers) { a.Age = 35 }, "age", 50},
+		{"age 49", func(a *riskAnswers) { a.Age = 49 }, "age", 50},
+		{"age 50", func(a *riskAnswers) { a.Age = 50 }, "age", 30},
+		{"age 100", func(a *riskAnswers) { a.Age = 100 }, "age", 30},
+		{"age 101", func(a *riskAnswers) { a.Age = 101 }, "age", 30},
+
+		{"horizon 0", func(a *riskAnswers) { a.YearsToRetirement = 0 }, "years_to_retirement", -40},
+		{"horizon 2", func(a *riskAnswers) { a.YearsToRetirement = 2 }, "years_to_retirement", -40},
+		{"horizon 3", func(a *riskAnswers) { a.YearsToRetirement = 3 }, "years_to_retirement", -25},
+		{"horizon 4", func(a *riskAnswers) { a.YearsToRetirement = 4 }, "years_to_retirement", -25},
+		{"horizon 5", func(a *riskAnswers) { a.YearsToRetirement = 5 }, "years_to_retirement", -10},
+		{"horizon 9", func(a *riskAnswers) { a.YearsToRetirement = 9 }, "years_to_retirement", -10},
+		{"horizon 10", func(a *riskAnswers) { a.YearsToRetirement = 10 }, "years_to_retirement", 0},
+		{"horizon 19", func(a *riskAnswers) { a.YearsToRetirement = 19 }, "years_to_retirement", 0},
+		{"horizon 20", func(a *riskAnswers) { a.YearsToRetirement = 20 }, "years_to_retirement", 10},
+		{"horizon 29", func(a *riskAnswers) { a.YearsToRetirement = 29 }, "years_to_retirement", 10},
+		{"horizon 30", func(a *riskAnswers) { a.YearsToRetirement = 30 }, "years_to_retirement", 20},
+
+		{"comfort unknown", func(a *riskAnswers) { a.DownturnComfort = "thrilled" }, "market_downturn_comfort", 0},
+		{"experience none", func(a *riskAnswers) { a.Experience = "none" }, "previous_experience", -20},
+		{"experience extensive", func(a *riskAnswers) { a.Experience = "extensive" }, "previous_experience", 15},
+		{"income unstable", func(a *riskAnswers) { a.IncomeStability = "unstable" }, "income_stability", -15},
+
+		{"dependents 0", func(a *riskAnswers) { a.Dependents = intp(0) }, "number_of_dependents", 5},
+		{"dependents 1", func(a *riskAnswers) { a.Dependents = intp(1) }, "number_of_dependents", 0},
+		{"dependents 2", func(a *riskAnswers) { a.Dependents = intp(2) }, "number_of_dependents", 0},
+		{"dependents 3", func(a *riskAnswers) { a.Dependents = intp(3) }, "number_of_dependents", -10},
+
+		{"net worth 0%", func(a *riskAnswers) { a.NetWorthInvestedPct = floatp(0) }, "net_worth_invested_percent", 5},
+		{"net worth 25%", func(a *riskAnswers) { a.NetWorthInvestedPct = floatp(25) }, "net_worth_invested_percent", 5},
+		{"net worth 25.1%", func(a *riskAnswers) { a.NetWorthInvestedPct = floatp(25.1) }, "net_worth_invested_percent", 0},
+		{"net worth 50%", func(a *riskAnswers) { a.NetWorthInvestedPct = floatp(50) }, "net_worth_invested_percent", 0},
+		{"net worth 75%", func(a *riskAnswers) { a.NetWorthInvestedPct = floatp(75) }, "net_worth_invested_percent", -10},
+		{"net worth 75.5%", func(a *riskAnswers) { a.NetWorthInvestedPct = floatp(75.5) }, "net_worth_invested_percent", -20},
+		{"net worth 100%", func(a *riskAnswers) { a.NetWorthInvestedPct = floatp(100) }, "net_worth_invested_percent", -20},
+
+		{"loss sell", func(a *riskAnswers) { a.LossReaction = "sell" }, "loss_reaction", -30},
+		{"loss buy more", func(a *riskAnswers) { a.LossReaction = "buy_more" }, "loss_reaction", 15},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a := base()
+			tt.modify(&a)
+			s, err := scoreRiskAnswers(a)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got, ok := s.Points[tt.factor]; !ok || got != tt.want {
+				t.Errorf("%s points = %v (scored %v), want %d", tt.factor, got, ok, tt.want)
+			}
+			total := 0
+			for _, p := range s.Points {
+				total += p
+			}
+			if s.Total != total {
+				t.Errorf("total = %d, want the sum of points %d", s.Total, total)
+			}
+		})
+	}
+}
+
+func TestScoreRiskAnswersThresholds(t *testing.T) {
+	none := 0
+	tests := []struct {
+		name    string
+		answers riskAnswers
+		total   int
+		want    RiskLevel
+	}{
+		// 30 age + 10 comfort
+		{"40 is still conservative", riskAnswers{Age: 60, YearsToRetirement: 15, DownturnComfort: "very_uncomfortable", Experience: "moderate"}, 40, RiskConservative},
+		// 30 age + 10 comfort + 5 dependents
+		{"45 is moderate", riskAnswers{Age: 60, YearsToRetirement: 15, DownturnComfort: "very_uncomfortable", Experience: "moderate", Dependents: &none}, 45, RiskModerate},
+		// 30 age - 10 horizon + 40 comfort
+		{"60 is still moderate", riskAnswers{Age: 60, YearsToRetirement: 7, DownturnComfort: "neutral", Experience: "moderate"}, 60, RiskModerate},
+		// 30 age - 10 horizon + 40 comfort + 5 dependents
+		{"65 is moderate-to-aggressive", riskAnswers{Age: 60, YearsToRetirement: 7, DownturnComfort: "neutral", Experience: "moderate", Dependents: &none}, 65, RiskModerateToAggressive},
+	}
+	for _, tt := range tests {
+		s, err := scoreRiskAnswers(tt.answers)
+		if err != nil {
+			t.Fatalf("%s: %v", tt.name, err)
+		}
+		if s.Total != tt.total || s.Level != tt.want {
+			t.Errorf("%s: total %d (%s), want %d (%s)", tt.name, s.Total, s.Level, tt.total, tt.want)
+		}
+	}
+}
+
+func TestScoreRiskAnswersRejectsOutOfRange(t *testing.T) {
+	negative := -1
+	for name, a := range map[string]riskAnswers{
+		"negative age":        {Age: -1},
+		"negative horizon":    {Age: 30, YearsToRetirement: -1},
+		"negative dependents": {Age: 30, Dependents: &negative},
+		"net worth over 100":  {Age: 30, NetWorthInvestedPct: func() *float64 { f := 100.5; return &f }()},
+		"net worth below 0":   {Age: 30, NetWorthInvestedPct: func() *float64 { f := -0.5; return &f }()},
+	} {
+		if _, err := scoreRiskAnswers(a); err == nil {
+			t.Errorf("%s: want an error", name)
+		}
+	}
+}
+
+func TestRiskWeightingCoversEveryFactor(t *testing.T) {
+	dependents, invested := 4, 80.0
+	s, err := scoreRiskAnswers(riskAnswers{Age: 30, YearsToRetirement: 35, DownturnComfort: "comfortable", Experience: "minimal",
+		IncomeStability: "stable", Dependents: &dependents, NetWorthInvestedPct: &invested, LossReaction: "hold"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	w := riskWeighting()
+	for factor := range s.Points {
+		if !strings.Contains(w, factor+": ") {
+			t.Errorf("weighting doesn't describe %s: %s", factor, w)
+		}
+	}
+	for _, want := range []string{"30_plus +20", "comfortable +60", "3+ -10", "above 75% -20", "sell -30, hold 0, buy_more +15"} {
+		if !strings.Contains(w, want) {
+			t.Errorf("weighting is missing %q: %s", want, w)
+		}
+	}
+}
@@ -0,0 +1,131 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/becomeliminal/nim-go-sdk/core"
+	"github.com/becomeliminal/nim-go-sdk/tools"
+)
+
+// ============================================
+// ROUND-UP MICRO-INVESTING
+// ============================================
+// Estimates what rounding every purchase up to the next dollar would have set aside,
+// using the user's real transactions. Money moving between the user's own accounts
+// (transfers, deposits, plan funding) isn't spending and is left out.
+
+// Round-up sampling bounds
+const (
+	minRoundUpPurchases  = 10  // fewer purchases than this is too small a sample to project
+	roundUpWidePageSize  = 500 // lookback used when the default page has too few purchases
+	roundUpProjectionYrs = 10.0
+)
+
+// roundUpPurchaseTypes are the transaction types that count as purchases. P2P "send"
+// transfers are not purchases: paying a friend back shouldn't round up or count as spending.
+var roundUpPurchaseTypes = map[string]bool{
+	"payment":      true,
+	"purchase":     true,
+	"card_payment": true,
+}
+
+// roundUpExcludedMarkers flag a purchase-typed transaction as money moving between the user's own accounts
+var roundUpExcludedMarkers = []string{"transfer", "deposit", "savings", "withdraw", "top_up", "topup"}
+
+// newRoundUpTool estimates a round-up program from the user's real transactions
+func newRoundUpTool(liminalExecutor core.ToolExecutor) core.Tool {
+	return tools.New("round_up_savings_estimate").
+		Description("Estimate how much rounding each of the user's recent purchases up to the next dollar would have set aside, and project that pace invested for 10 years").
+		Schema(tools.ObjectSchema(map[string]interface{}{
+			"expected_return": tools.StringProperty(fmt.Sprintf("Optional assumed annual return percentage for the projection (defaults to the server's assumption, currently %g)", appConfig.Assumptions.EquityReturnPct)),
+		})).
+		Handler(func(ctx context.Context, toolParams *core.ToolParams) (*core.ToolResult, error) {
+			var params struct {
+				ExpectedReturn string `json:"expected_return"`
+			}
+			if len(toolParams.Input) > 0 {
+				if err := json.Unmarshal(toolParams.Input, &params); err != nil {
+					return &core.ToolResult{Success: false, Error: fmt.Sprintf("invalid input: %v", err)}, nil
+				}
+			}
+
+			var v amountValidator
+			returnRate := v.returnRate("expected_return", params.ExpectedReturn, false)
+			if err := v.err(); err != nil {
+				return &core.ToolResult{Success: false, Error: err.Error()}, nil
+			}
+
+			txs, ok := fetchTransactions(ctx, liminalExecutor, toolParams.UserID, transactionPageSize)
+			if !ok {
+				return &core.ToolResult{Success: false, Error: "could not load transaction history"}, nil
+			}
+			now := time.Now()
+			result := roundUpEstimate(txs, returnRate, now)
+			if result.PurchasesRounded < minRoundUpPurchases && len(txs) >= transactionPageSize {
+				// The page was full but mostly non-purchases; look further back
+				if wider, ok := fetchTransactions(ctx, liminalExecutor, toolParams.UserID, roundUpWidePageSize); ok {
+					result = roundUpEstimate(wider, returnRate, now)
+					result.WidenedLookback = true
+				}
+			}
+			return &core.ToolResult{Success: true, Data: result}, nil
+		}).
+		Build()
+}
+
+// roundUpEstimate totals the round-ups over the transactions' window and projects the monthly pace
+func roundUpEstimate(txs []map[string]interface{}, returnRate float64, now time.Time) RoundUpResult {
+	r := RoundUpResult{Currency: activeCurrency().Code, TransactionsAnalyzed: len(txs), ExpectedReturnPercent: returnRate}
+	oldest := now
+	for _, tx := range txs {
+		if !isRoundUpPurchase(tx) {
+			r.ExcludedTransactions++
+			continue
+		}
+		r.PurchasesRounded++
+		r.RoundUpTotalUSD += roundUpAmount(math.Abs(transactionAmount(tx)))
+		if at, ok := transactionTime(tx); ok && at.Before(oldest) {
+			oldest = at
+		}
+	}
+	r.WindowDays = transactionWindowDays(oldest, now)
+
+	if r.PurchasesRounded < minRoundUpPurchases {
+		r.SampleTooSmall = true
+		r.Message = fmt.Sprintf("Only %d purchases found in recent history; at least %d are needed for a reliable round-up estimate. Try again after more card activity.",
+			r.PurchasesRounded, minRoundUpPurchases)
+		return r
+	}
+
+	r.AverageRoundUpUSD = r.RoundUpTotalUSD / float64(r.PurchasesRounded)
+	r.MonthlyPaceUSD = r.RoundUpTotalUSD / r.WindowDays * 30
+	projection := calculateCompoundGrowth(0, r.MonthlyPaceUSD, returnRate, roundUpProjectionYrs)
+	r.TenYearProjection = &projection
+	r.Message = fmt.Sprintf("Rounding up %d purchases over the last %.0f days would have set aside %s (about %s/month). Invested at %.1f%% for %.0f years, that pace grows to %s.",
+		r.PurchasesRounded, r.WindowDays, formatMoney(r.RoundUpTotalUSD), formatMoney(r.MonthlyPaceUSD), returnRate, roundUpProjectionYrs, projection.ProjectedTotal)
+	return r
+}
+
+// isRoundUpPurchase reports whether a transaction is spending rather than money moving between
+// the user's own accounts
+func isRoundUpPurchase(tx map[string]interface{}) bool {
+	kind, _ := tx["type"].(string)
+	if !roundUpPurchaseTypes[strings.ToLower(kind)] {
+		return false
+	}
+	if recipient, _ := tx["recipient"].(string); appConfig.InvestRecipient != "" && recipient == appConfig.InvestRecipient {
+		return false // automated plan funding
+	}
+	return !hasMarker(tx, roundUpExcludedMarkers)
+}
+
+// roundUpAmount is the change needed to reach the next whole dollar; whole-dollar purchases round up nothing
+func roundUpAmount(amount float64) float64 {
+	cents := int64(math.Round(amount * 100))
+	return float64((100-cents%100)%100) / 100
+}
@@ -0,0 +1,28 @@
+package main
+
+import "testing"
+
+func TestSendIsATransferNotAPurchase(t *testing.T) {
+	tests := []struct {
+		name         string
+		tx           map[string]interface{}
+		wantPurchase bool
+		wantCategory string
+	}{
+		{name: "card payment", tx: map[string]interface{}{"type": "card_payment", "amount": -12.40}, wantPurchase: true, wantCategory: spendingCategorySpend},
+		{name: "purchase", tx: map[string]interface{}{"type": "purchase", "amount": -3.75}, wantPurchase: true, wantCategory: spendingCategorySpend},
+		{name: "p2p send", tx: map[string]interface{}{"type": "send", "amount": -40.00}, wantCategory: spendingCategoryMove},
+		{name: "p2p send, unsigned", tx: map[string]interface{}{"type": "send", "amount": 40.00}, wantCategory: spendingCategoryMove},
+		{name: "savings deposit", tx: map[string]interface{}{"type": "deposit", "amount": -100.00}, wantCategory: spendingCategorySave},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := isRoundUpPurchase(tt.tx); got != tt.wantPurchase {
+				t.Errorf("isRoundUpPurchase = %v, want %v", got, tt.wantPurchase)
+			}
+			if got := spendingCategory(tt.tx); got != tt.wantCategory {
+				t.Errorf("spendingCategory = %q, want %q", got, tt.wantCategory)
+			}
+		})
+	}
+}
@@ -0,0 +1,40 @@
+package main
+
+import (
+	"fmt"
+	"math"
+)
+
+// ============================================
+// PROJECTION SCENARIOS
+// ============================================
+// With scenarios requested, calculate_investment_projection also projects the
+// same contributions at the expected return minus and plus
+// PROJECTION_SCENARIO_SPREAD_PCT, each with a year-end series for charting.
+
+// withScenarios adds pessimistic, expected and optimistic projections at returnRate ∓ the configured spread
+func (p ProjectionResult) withScenarios() ProjectionResult {
+	spread := appConfig.ScenarioSpread
+	for _, s := range []struct {
+		name  string
+		delta float64
+	}{{"pessimistic", -spread}, {"expected", 0}, {"optimistic", spread}} {
+		rate := p.AnnualReturnPercent + s.delta
+		sc := ProjectionScenario{Name: s.name, AnnualReturnPercent: rate}
+		for m := 12; ; m += 12 {
+			m = min(m, p.Months)
+			value := escalatingFutureValue(p.InitialInvestment, p.MonthlyContribution, rate, p.AnnualIncreasePercent, float64(m))
+			sc.Series = append(sc.Series, ScenarioPoint{Year: float64(m) / 12, ValueUSD: value})
+			if m == p.Months {
+				sc.EndingValueUSD = value
+				break
+			}
+		}
+		sc.EarningsUSD = sc.EndingValueUSD - p.TotalContributed
+		p.Scenarios = append(p.Scenarios, sc)
+	}
+	low, high := p.Scenarios[0], p.Scenarios[2]
+	p.ScenarioNote = fmt.Sprintf("Between %.1f%% and %.1f%% a year, the same plan ends anywhere from %s to %s after %g years; returns vary year to year, so treat the expected figure as the middle of a range.",
+		low.AnnualReturnPercent, high.AnnualReturnPercent, formatWholeMoney(low.EndingValueUSD), formatWholeMoney(high.EndingValueUSD), math.Round(p.Years*10)/10)
+	return p
+}
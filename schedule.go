@@ -0,0 +1,46 @@
+package main
+
+// ============================================
+// PROJECTION SCHEDULE
+// ============================================
+// With include_schedule, calculate_investment_projection lists the balance at
+// every year mark (and at the end of a final partial year) from the same
+// formulas as the headline total, so the last row always matches it.
+
+// maxScheduleEntries caps the year-by-year rows returned
+const maxScheduleEntries = 60
+
+// projectionMilestones are the balances reported in crossed_milestones, in the account currency
+var projectionMilestones = []float64{10000, 50000, 100000, 250000, 500000, 1000000}
+
+// withSchedule adds the year-by-year balances and the first year each milestone is passed
+func (p ProjectionResult) withSchedule() ProjectionResult {
+	for m := 12; len(p.Schedule) < maxScheduleEntries; m += 12 {
+		m = min(m, p.Months)
+		contributed := p.InitialInvestment + escalatingFutureValue(0, p.MonthlyContribution, 0, p.AnnualIncreasePercent, float64(m))
+		balance := escalatingFutureValue(p.InitialInvestment, p.MonthlyContribution, p.AnnualReturnPercent, p.AnnualIncreasePercent, float64(m))
+		p.Schedule = append(p.Schedule, ScheduleRow{
+			Year:                float64(m) / 12,
+			ContributionsToDate: contributed,
+			Balance:             balance,
+			EarningsToDate:      balance - contributed,
+		})
+		if m == p.Months {
+			break
+		}
+	}
+
+	p.CrossedMilestones = []Milestone{}
+	for _, amount := range projectionMilestones {
+		if p.InitialInvestment >= amount {
+			continue // passed before the projection starts
+		}
+		for _, row := range p.Schedule {
+			if row.Balance >= amount {
+				p.CrossedMilestones = append(p.CrossedMilestones, Milestone{Amount: amount, Label: formatWholeMoney(amount), Year: row.Year})
+				break
+			}
+		}
+	}
+	return p
+}
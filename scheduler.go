@@ -0,0 +1,360 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/becomeliminal/nim-go-sdk/core"
+
+	"vibe-invest/storage"
+)
+
+// ============================================
+// AUTOMATED PLAN EXECUTION
+// ============================================
+// Plans invest once a month on their start_date's day of month (clamped to the
+// month's last day). Each plan/month pair gets a deterministic execution ID that
+// is also sent to Liminal as the request ID, so a restart mid-cycle resumes the
+// same execution instead of investing twice. Plans with skip_on_spike_percent
+// set check the user's spending first and skip the month when it has spiked.
+
+// schedulerInterval is how often the scheduler looks for due plans
+const schedulerInterval = 24 * time.Hour
+
+// planScheduler runs due plans through the Liminal executor
+type planScheduler struct {
+	executor    core.ToolExecutor
+	maxAttempts int
+	backoff     time.Duration
+
+	mu       sync.Mutex
+	inFlight map[string]bool // execution IDs currently being attempted
+}
+
+// scheduler is set in main when the scheduler is enabled; the admin API uses it for retries
+var scheduler *planScheduler
+
+func newPlanScheduler(executor core.ToolExecutor) *planScheduler {
+	maxAttempts := appConfig.ExecMaxAttempts
+	if maxAttempts < 1 {
+		maxAttempts = 1
+	}
+	return &planScheduler{
+		executor:    executor,
+		maxAttempts: maxAttempts,
+		backoff:     appConfig.ExecRetryBackoff,
+		inFlight:    make(map[string]bool),
+	}
+}
+
+// start runs once immediately, then daily, until ctx is cancelled
+func (s *planScheduler) start(ctx context.Context) {
+	go func() {
+		ticker := time.NewTicker(schedulerInterval)
+		defer ticker.Stop()
+		for {
+			s.runDue(ctx, time.Now().UTC())
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+// runDue executes every plan that is due on day
+func (s *planScheduler) runDue(ctx context.Context, day time.Time) {
+	plans, err := store.ListAllPlans(ctx)
+	if err != nil {
+		log.Printf("❌ Scheduler could not load plans: %v\n", err)
+		return
+	}
+	for _, plan := range plans {
+		plan = resumeIfDue(ctx, plan, day)
+		if plan.Status != storage.PlanActive || !planDueOn(plan, day) {
+			continue
+		}
+		s.execute(ctx, plan, day.Format("2006-01"))
+	}
+}
+
+// running reports whether the execution is being attempted right now
+func (s *planScheduler) running(executionID string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.inFlight[executionID]
+}
+
+// execute invests for one plan and period, retrying with backoff. Periods that already
+// succeeded or exhausted their attempts are left alone.
+func (s *planScheduler) execute(ctx context.Context, plan storage.Plan, period string) {
+	// The daily run and an admin retry must not attempt the same execution at once
+	id := executionID(plan.ID, period)
+	s.mu.Lock()
+	if s.inFlight[id] {
+		s.mu.Unlock()
+		return
+	}
+	s.inFlight[id] = true
+	s.mu.Unlock()
+	defer func() {
+		s.mu.Lock()
+		delete(s.inFlight, id)
+		s.mu.Unlock()
+	}()
+
+	plan, ok := activePlan(ctx, plan)
+	if !ok {
+		return
+	}
+	exec, err := store.GetExecution(ctx, id)
+	switch {
+	case errors.Is(err, storage.ErrNotFound):
+		now := time.Now().UTC()
+		exec = storage.Execution{
+			ID:        id,
+			PlanID:    plan.ID,
+			UserID:    plan.UserID,
+			Period:    period,
+			Amount:    plan.MonthlyAmount,
+			Tool:      planTransferTool(plan),
+			Status:    storage.ExecutionPending,
+			CreatedAt: now,
+			UpdatedAt: now,
+		}
+		if report, spiked := s.spendingSpike(ctx, plan, now); spiked {
+			s.skip(ctx, plan, exec, report)
+			return
+		}
+	case err != nil:
+		log.Printf("❌ Scheduler could not load execution for plan %s: %v\n", plan.ID, err)
+		return
+	case exec.Status != storage.ExecutionPending:
+		return
+	}
+
+	for exec.Attempts < s.maxAttempts {
+		if exec.Attempts > 0 {
+			delay := s.backoff << (exec.Attempts - 1)
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(delay):
+			}
+			// A pause or cancellation during the backoff stops the retries
+			if plan, ok = activePlan(ctx, plan); !ok {
+				return
+			}
+		}
+
+		// Record the attempt before calling out, so a crash can't hide it
+		exec.Attempts++
+		exec.UpdatedAt = time.Now().UTC()
+		if err := store.SaveExecution(ctx, exec); err != nil {
+			log.Printf("❌ Scheduler could not record execution %s: %v\n", exec.ID, err)
+			return
+		}
+
+		response, err := s.transfer(ctx, plan, exec)
+		exec.UpdatedAt = time.Now().UTC()
+		if err == nil {
+			exec.Status = storage.ExecutionSucceeded
+			exec.LastError = ""
+			exec.Response = response
+			if err := store.SaveExecution(ctx, exec); err != nil {
+				log.Printf("❌ Scheduler could not record execution %s: %v\n", exec.ID, err)
+			}
+			recordAudit(ctx, plan.UserID, "scheduler", "execute_plan", exec.ID)
+			recordContribution(ctx, storage.Contribution{
+				ID:     exec.ID,
+				UserID: plan.UserID,
+				Date:   exec.UpdatedAt,
+				Amount: exec.Amount,
+				Source: storage.ContributionPlan,
+				Note:   fmt.Sprintf("%s plan %s", plan.InvestmentType, plan.ID),
+			})
+			return
+		}
+
+		exec.LastError = err.Error()
+		if err := store.SaveExecution(ctx, exec); err != nil {
+			log.Printf("❌ Scheduler could not record execution %s: %v\n", exec.ID, err)
+			return
+		}
+		log.Printf("⚠️  Plan %s attempt %d/%d failed: %v\n", plan.ID, exec.Attempts, s.maxAttempts, err)
+	}
+
+	exec.Status = storage.ExecutionFailed
+	exec.UpdatedAt = time.Now().UTC()
+	if err := store.SaveExecution(ctx, exec); err != nil {
+		log.Printf("❌ Scheduler could not record execution %s: %v\n", exec.ID, err)
+	}
+	s.recordFailure(ctx, plan.ID, plan.UserID, exec)
+}
+
+// activePlan reloads plan and reports whether it is still active, so a plan paused or cancelled
+// since it was read - by the user, or before an admin retry - never moves money
+func activePlan(ctx context.Context, plan storage.Plan) (storage.Plan, bool) {
+	current, err := store.GetPlan(ctx, plan.UserID, plan.ID)
+	if err != nil {
+		log.Printf("❌ Scheduler could not load plan %s: %v\n", plan.ID, err)
+		return plan, false
+	}
+	current = resumeIfDue(ctx, current, time.Now().UTC())
+	if current.Status != storage.PlanActive {
+		log.Printf("⏸️  Plan %s is %s; not investing\n", plan.ID, current.Status)
+		return current, false
+	}
+	return current, true
+}
+
+// recordFailure surfaces a failed period in the plan's history for list_automated_plans
+func (s *planScheduler) recordFailure(ctx context.Context, planID, userID string, exec storage.Execution) {
+	plan, err := store.GetPlan(ctx, userID, planID)
+	if err != nil {
+		log.Printf("❌ Scheduler could not load plan %s: %v\n", planID, err)
+		return
+	}
+	plan.History = append(plan.History, storage.PlanChange{
+		Time:     exec.UpdatedAt,
+		Field:    "execution",
+		OldValue: exec.Period,
+		NewValue: "failed: " + exec.LastError,
+	})
+	if err := store.SavePlan(ctx, plan); err != nil {
+		log.Printf("❌ Scheduler could not update plan %s: %v\n", planID, err)
+	}
+	recordAudit(ctx, userID, "scheduler", "execution_failed", exec.ID)
+}
+
+// spendingSpike reports whether plan is set to skip on a spike and the last 30 days' spending is one.
+// Without a readable transaction history the plan runs as usual.
+func (s *planScheduler) spendingSpike(ctx context.Context, plan storage.Plan, now time.Time) (SpendingAnomalyReport, bool) {
+	if plan.SkipOnSpikePct <= 0 {
+		return SpendingAnomalyReport{}, false
+	}
+	txs, ok := fetchTransactions(ctx, s.executor, plan.UserID, spendingPageSize)
+	if !ok {
+		log.Printf("⚠️  Plan %s could not check spending; investing as usual\n", plan.ID)
+		return SpendingAnomalyReport{}, false
+	}
+	report := detectSpendingAnomalies(txs, plan.SkipOnSpikePct, now)
+	return report, report.Spike
+}
+
+// skip records a period as skipped for a spending spike, in the execution and the plan's history
+func (s *planScheduler) skip(ctx context.Context, plan storage.Plan, exec storage.Execution, report SpendingAnomalyReport) {
+	exec.Status = storage.ExecutionSkipped
+	exec.LastError = fmt.Sprintf("spending in the last %d days up %.0f%% (%s) on the %d-period average, above the plan's %g%% limit",
+		anomalyWindowDays, report.TotalChangePercent, formatWholeMoney(report.TotalDeltaUSD), report.BaselinePeriods, plan.SkipOnSpikePct)
+	if err := store.SaveExecution(ctx, exec); err != nil {
+		log.Printf("❌ Scheduler could not record execution %s: %v\n", exec.ID, err)
+		return
+	}
+	plan.History = append(plan.History, storage.PlanChange{
+		Time:     exec.UpdatedAt,
+		Field:    "execution",
+		OldValue: exec.Period,
+		NewValue: "skipped: " + exec.LastError,
+	})
+	if err := store.SavePlan(ctx, plan); err != nil {
+		log.Printf("❌ Scheduler could not update plan %s: %v\n", plan.ID, err)
+	}
+	recordAudit(ctx, plan.UserID, "scheduler", "execution_skipped", exec.ID)
+}
+
+// transfer moves the money: deposit_savings for savings plans, send_money to the
+// configured brokerage recipient for everything else
+func (s *planScheduler) transfer(ctx context.Context, plan storage.Plan, exec storage.Execution) (string, error) {
+	input := map[string]interface{}{
+		"amount":   fmt.Sprintf("%.2f", exec.Amount),
+		"currency": appConfig.Currency,
+	}
+	if exec.Tool == "send_money" {
+		if appConfig.InvestRecipient == "" {
+			return "", errors.New("no investment recipient configured (INVEST_RECIPIENT)")
+		}
+		input["recipient"] = appConfig.InvestRecipient
+		input["note"] = fmt.Sprintf("InvestMate %s plan %s (%s)", plan.InvestmentType, plan.ID, exec.Period)
+	}
+	raw, err := json.Marshal(input)
+	if err != nil {
+		return "", err
+	}
+
+	resp, err := s.executor.Execute(ctx, &core.ExecuteRequest{
+		UserID:    plan.UserID,
+		Tool:      exec.Tool,
+		Input:     raw,
+		RequestID: exec.ID,
+	})
+	if err != nil {
+		return "", err
+	}
+	if !resp.Success {
+		return string(resp.Data), errors.New(resp.Error)
+	}
+	return string(resp.Data), nil
+}
+
+// executionID is deterministic per plan and period; it doubles as the idempotency key
+func executionID(planID, period string) string {
+	return "exec_" + planID + "_" + period
+}
+
+// planTransferTool picks the Liminal tool that funds a plan
+func planTransferTool(plan storage.Plan) string {
+	if plan.InvestmentType == "savings" {
+		return "deposit_savings"
+	}
+	return "send_money"
+}
+
+// resumeIfDue reactivates a paused plan once its resume_date has arrived. The scheduler
+// calls it for every plan, not just due ones, and plan reads call it too, so the plan
+// shows as active from its resume_date even while PLAN_SCHEDULER_ENABLED=false.
+func resumeIfDue(ctx context.Context, plan storage.Plan, day time.Time) storage.Plan {
+	if plan.Status != storage.PlanPaused || !planActiveOn(plan, day) {
+		return plan
+	}
+	resumed := plan
+	resumed.History = append(slices.Clone(plan.History), storage.PlanChange{Time: day, Field: "status", OldValue: plan.Status, NewValue: storage.PlanActive})
+	resumed.Status = storage.PlanActive
+	resumed.ResumeDate = ""
+	if err := store.SavePlan(ctx, resumed); err != nil {
+		log.Printf("❌ Could not resume plan %s: %v\n", plan.ID, err)
+		return plan
+	}
+	recordAudit(ctx, plan.UserID, "scheduler", "resume_plan", plan.ID)
+	return resumed
+}
+
+// planActiveOn reports whether a plan invests on day: active plans do,
+// paused plans only once their resume_date has arrived, cancelled plans never.
+func planActiveOn(plan storage.Plan, day time.Time) bool {
+	switch plan.Status {
+	case storage.PlanActive:
+		return true
+	case storage.PlanPaused:
+		return plan.ResumeDate != "" && plan.ResumeDate <= day.Format("2006-01-02")
+	}
+	return false
+}
+
+// planDueOn reports whether this month's investment date has arrived
+func planDueOn(plan storage.Plan, day time.Time) bool {
+	start, err := time.Parse("2006-01-02", plan.StartDate)
+	if err != nil || day.Before(start) {
+		return false
+	}
+	year, month, _ := day.Date()
+	lastDay := time.Date(year, month+1, 0, 0, 0, 0, 0, time.UTC).Day()
+	return day.Day() >= min(start.Day(), lastDay)
+}
@@ -0,0 +1,85 @@
+package main
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"vibe-invest/storage"
+)
+
+// withStore gives one test an empty store, so runDue only sees that test's plans
+func withStore(t *testing.T) {
+	t.Helper()
+	old := store
+	store = storage.NewMemory()
+	t.Cleanup(func() { store = old })
+}
+
+func TestRunDueResumesOnResumeDate(t *testing.T) {
+	ctx := context.Background()
+	withStore(t)
+	exec := &fakeExecutor{data: `{}`}
+	withScheduler(t, exec)
+	day := time.Date(2026, 10, 5, 9, 0, 0, 0, time.UTC)
+	// Invests on the 20th, resumes on the 5th
+	plan := storage.Plan{ID: "plan_resume", UserID: "scheduler_user", MonthlyAmount: 100, InvestmentType: "savings",
+		StartDate: "2026-01-20", Status: storage.PlanPaused, ResumeDate: "2026-10-05", CreatedAt: day}
+	if err := store.SavePlan(ctx, plan); err != nil {
+		t.Fatal(err)
+	}
+
+	scheduler.runDue(ctx, day)
+
+	got, err := store.GetPlan(ctx, plan.UserID, plan.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Status != storage.PlanActive || got.ResumeDate != "" {
+		t.Errorf("plan = %s until %q, want active with no resume date", got.Status, got.ResumeDate)
+	}
+	if len(exec.requests) != 0 {
+		t.Errorf("transfers = %+v, want none before the 20th", exec.requests)
+	}
+	entries, err := store.ListAudit(ctx, plan.UserID)
+	if err != nil || len(entries) != 1 || entries[0].Action != "resume_plan" {
+		t.Errorf("audit = %+v, %v; want one resume_plan entry", entries, err)
+	}
+}
+
+func TestPlanReadsResumeWithoutScheduler(t *testing.T) {
+	ctx := context.Background()
+	withStore(t)
+	old := scheduler
+	scheduler = nil
+	t.Cleanup(func() { scheduler = old })
+	now := time.Now().UTC()
+	tests := []struct {
+		resumeDate string
+		want       string
+	}{
+		{now.AddDate(0, 0, -3).Format("2006-01-02"), storage.PlanActive},
+		{now.Format("2006-01-02"), storage.PlanActive},
+		{now.AddDate(0, 0, 3).Format("2006-01-02"), storage.PlanPaused},
+		{"", storage.PlanPaused},
+	}
+	for _, tt := range tests {
+		t.Run("resume "+tt.resumeDate, func(t *testing.T) {
+			userID := "reader_" + tt.resumeDate
+			plan := storage.Plan{ID: "plan_" + tt.resumeDate, UserID: userKey(userID), MonthlyAmount: 100, InvestmentType: "savings",
+				StartDate: "2026-01-20", Status: storage.PlanPaused, ResumeDate: tt.resumeDate, CreatedAt: now}
+			if err := store.SavePlan(ctx, plan); err != nil {
+				t.Fatal(err)
+			}
+
+			plans, err := listPlans(ctx, userKey(userID))
+			if err != nil || len(plans) != 1 || plans[0].Status != tt.want {
+				t.Errorf("listPlans = %+v, %v; want one %s plan", plans, err, tt.want)
+			}
+			got, result := loadOwnedPlan(ctx, userID, plan.ID)
+			if result != nil || got.Status != tt.want {
+				t.Errorf("loadOwnedPlan = %s, %+v; want %s", got.Status, result, tt.want)
+			}
+		})
+	}
+}
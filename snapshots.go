@@ -0,0 +1,245 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/becomeliminal/nim-go-sdk/core"
+	"github.com/becomeliminal/nim-go-sdk/tools"
+
+	"vibe-invest/storage"
+)
+
+// ============================================
+// PORTFOLIO SNAPSHOTS & PERFORMANCE
+// ============================================
+// Once a day every stored portfolio's total value is written as a snapshot.
+// Holdings with a quantity and a ticker are valued from live quotes when the
+// market data provider is configured; everything else uses its stored value.
+// Days the server was down simply have no snapshot. get_portfolio_performance
+// interpolates between the snapshots either side of a period's start date and
+// never writes the interpolated values back. The same valuation feeds the daily
+// allocation drift check (see alerts.go).
+
+// snapshotInterval is how often portfolio values are recorded
+const snapshotInterval = 24 * time.Hour
+
+// snapshotJob records daily portfolio values; quotes may be nil when live quotes are disabled
+type snapshotJob struct {
+	quotes quoteProvider
+}
+
+// start records once immediately, then daily, until ctx is cancelled. A restart on the
+// same day replaces that day's snapshot rather than adding a second one.
+func (j *snapshotJob) start(ctx context.Context) {
+	go func() {
+		ticker := time.NewTicker(snapshotInterval)
+		defer ticker.Stop()
+		for {
+			j.record(ctx, time.Now().UTC())
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+// record writes day's snapshot for every stored portfolio
+func (j *snapshotJob) record(ctx context.Context, day time.Time) {
+	portfolios, err := store.ListAllPortfolios(ctx)
+	if err != nil {
+		log.Printf("❌ Snapshot job could not load portfolios: %v\n", err)
+		return
+	}
+	for _, p := range portfolios {
+		value, classes, priced := j.value(ctx, p)
+		err := store.SaveSnapshot(ctx, storage.Snapshot{
+			UserID:     p.UserID,
+			Date:       day.Format("2006-01-02"),
+			TotalValue: value,
+			Priced:     priced,
+			CreatedAt:  time.Now().UTC(),
+		})
+		if err != nil {
+			log.Printf("❌ Snapshot job could not save %s: %v\n", p.UserID, err)
+			continue
+		}
+		checkMilestones(ctx, p.UserID, time.Now().UTC())
+		checkDriftAlerts(ctx, p, classes, time.Now().UTC())
+	}
+}
+
+// value totals a portfolio and its asset classes (see holdingTotals); priced reports whether any
+// holding was valued from a live quote. Without holdings classes is nil: the profile only splits
+// stocks from savings, and reading that as zero bonds would look like drift that never goes away.
+func (j *snapshotJob) value(ctx context.Context, p storage.Portfolio) (total float64, classes map[string]float64, priced bool) {
+	if len(p.Holdings) == 0 {
+		return p.TotalBalance, nil, false
+	}
+	classes = holdingTotals(nil)
+	for _, h := range p.Holdings {
+		if j.quotes == nil || h.Quantity <= 0 || !tickerPattern.MatchString(h.Identifier) {
+			total += h.Value
+			classes[h.AssetClass] += h.Value
+			continue
+		}
+		quoteCtx, cancel := context.WithTimeout(ctx, quoteTimeout)
+		q, err := j.quotes.Quote(quoteCtx, h.Identifier)
+		cancel()
+		if err != nil {
+			log.Printf("⚠️  Snapshot using stored value for %s: %v\n", h.Identifier, err)
+			total += h.Value
+			classes[h.AssetClass] += h.Value
+			continue
+		}
+		total += h.Quantity * q.Price
+		classes[h.AssetClass] += h.Quantity * q.Price
+		priced = true
+	}
+	return total, classes, priced
+}
+
+// performancePeriods are the windows get_portfolio_performance reports, keyed to their start date
+var performancePeriods = []struct {
+	label string
+	start func(now time.Time) time.Time
+}{
+	{"1M", func(now time.Time) time.Time { return now.AddDate(0, -1, 0) }},
+	{"3M", func(now time.Time) time.Time { return now.AddDate(0, -3, 0) }},
+	{"YTD", func(now time.Time) time.Time { return time.Date(now.Year(), 1, 1, 0, 0, 0, 0, time.UTC) }},
+	{"1Y", func(now time.Time) time.Time { return now.AddDate(-1, 0, 0) }},
+}
+
+// valueOn is the portfolio value at date: the snapshot that day, or a straight line between the
+// snapshots either side. Before the first snapshot it returns the first one with partial set.
+func valueOn(snapshots []storage.Snapshot, date time.Time) (value float64, from time.Time, interpolated, partial bool) {
+	day := date.Format("2006-01-02")
+	for i, s := range snapshots {
+		if s.Date < day {
+			continue
+		}
+		at, _ := time.Parse("2006-01-02", s.Date)
+		if s.Date == day {
+			return s.TotalValue, at, false, false
+		}
+		if i == 0 {
+			return s.TotalValue, at, false, true
+		}
+		prev := snapshots[i-1]
+		before, _ := time.Parse("2006-01-02", prev.Date)
+		f := date.Sub(before).Hours() / at.Sub(before).Hours()
+		return prev.TotalValue + (s.TotalValue-prev.TotalValue)*f, date, true, false
+	}
+	last := snapshots[len(snapshots)-1]
+	at, _ := time.Parse("2006-01-02", last.Date)
+	return last.TotalValue, at, false, false
+}
+
+// periodPerformance measures one window. The return is a Modified Dietz estimate: market gain divided
+// by the starting value plus each contribution weighted by the share of the window it was invested.
+func periodPerformance(label string, snapshots []storage.Snapshot, contributions []storage.Contribution, start time.Time) PerformancePeriod {
+	start, _ = time.Parse("2006-01-02", start.Format("2006-01-02"))
+	startValue, startDate, interpolated, partial := valueOn(snapshots, start)
+	last := snapshots[len(snapshots)-1]
+	endDate, _ := time.Parse("2006-01-02", last.Date)
+
+	p := PerformancePeriod{
+		Period:        label,
+		StartDate:     startDate.Format("2006-01-02"),
+		EndDate:       last.Date,
+		StartValueUSD: startValue,
+		EndValueUSD:   last.TotalValue,
+		ChangeUSD:     last.TotalValue - startValue,
+		Interpolated:  interpolated,
+		Partial:       partial,
+	}
+	span := endDate.Sub(startDate).Hours()
+	weighted := startValue
+	for _, c := range contributions {
+		// Money added on the start day is already in the start value; on the end day, in the end value
+		if day := c.Date.Format("2006-01-02"); day <= p.StartDate || day > p.EndDate {
+			continue
+		}
+		p.ContributionsUSD += c.Amount
+		if span > 0 {
+			weighted += c.Amount * max(endDate.Sub(c.Date).Hours(), 0) / span
+		}
+	}
+	p.MarketGainUSD = p.ChangeUSD - p.ContributionsUSD
+	if weighted > 0 {
+		p.ReturnPercent = p.MarketGainUSD / weighted * 100
+	}
+	p.Change = formatMoney(p.ChangeUSD)
+	switch {
+	case partial:
+		p.Note = fmt.Sprintf("History only starts on %s, so this covers less than the full period", p.StartDate)
+	case interpolated:
+		p.Note = fmt.Sprintf("No snapshot on %s; the starting value is interpolated from the days either side", p.StartDate)
+	}
+	return p
+}
+
+// newPerformanceTool reports how the user's portfolio has done from its daily snapshots
+func newPerformanceTool() core.Tool {
+	return tools.New("get_portfolio_performance").
+		Description("Show how the user's portfolio has performed over the last month, 3 months, year to date and year: the change in value, how much came from contributions and how much from the market, and a money-weighted return").
+		Schema(tools.ObjectSchema(map[string]interface{}{})).
+		Handler(func(ctx context.Context, toolParams *core.ToolParams) (*core.ToolResult, error) {
+			now := time.Now().UTC()
+			earliest := now
+			for _, period := range performancePeriods {
+				earliest = minTime(earliest, period.start(now))
+			}
+			// Look back far enough to have a snapshot before the earliest start to interpolate from
+			snapshots, err := store.ListSnapshots(ctx, userKey(toolParams.UserID), earliest.AddDate(0, -1, 0).Format("2006-01-02"))
+			if err != nil {
+				return &core.ToolResult{Success: false, Error: fmt.Sprintf("could not load portfolio history: %v", err)}, nil
+			}
+			if len(snapshots) == 0 {
+				return &core.ToolResult{Success: true, Data: PerformanceResult{
+					Currency: activeCurrency().Code,
+					Periods:  []PerformancePeriod{},
+					Message:  "There's no portfolio history yet. Values are recorded once a day for saved portfolios, so check back after a few days.",
+				}}, nil
+			}
+			contributions, err := store.ListContributions(ctx, userKey(toolParams.UserID))
+			if err != nil {
+				return &core.ToolResult{Success: false, Error: fmt.Sprintf("could not load contributions: %v", err)}, nil
+			}
+
+			r := PerformanceResult{
+				Currency:        activeCurrency().Code,
+				CurrentValueUSD: snapshots[len(snapshots)-1].TotalValue,
+				AsOf:            snapshots[len(snapshots)-1].Date,
+				FirstSnapshot:   snapshots[0].Date,
+				Periods:         make([]PerformancePeriod, 0, len(performancePeriods)),
+				Assumptions: []string{
+					"Contributions come from the ledger: automated plan investments, confirmed savings deposits and anything reported with record_contribution; money added another way shows up as market gain",
+					"Days without a snapshot are skipped, never filled in; a period starting on one uses the straight line between its neighbours",
+				},
+			}
+			r.CurrentValue = formatMoney(r.CurrentValueUSD)
+			r.ContributedThisYear, r.ContributedLifetime = contributionTotals(contributions, now)
+			r.GrowthExclContributions, _, _ = growthExcludingContributions(snapshots, contributions)
+			for _, period := range performancePeriods {
+				r.Periods = append(r.Periods, periodPerformance(period.label, snapshots, contributions, period.start(now)))
+			}
+			ytd := r.Periods[2]
+			r.Message = fmt.Sprintf("Your portfolio is worth %s. Year to date it changed by %s: %s from contributions and %s from the market (%.1f%% money-weighted).",
+				r.CurrentValue, ytd.Change, formatMoney(ytd.ContributionsUSD), formatMoney(ytd.MarketGainUSD), ytd.ReturnPercent)
+			return &core.ToolResult{Success: true, Data: r}, nil
+		}).
+		Build()
+}
+
+// minTime returns the earlier of a and b
+func minTime(a, b time.Time) time.Time {
+	if b.Before(a) {
+		return b
+	}
+	return a
+}
@@ -0,0 +1,207 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/becomeliminal/nim-go-sdk/core"
+	"github.com/becomeliminal/nim-go-sdk/tools"
+)
+
+// ============================================
+// SPENDING ANALYSIS
+// ============================================
+// Reads the user's real outflows for the requested window and sorts them into
+// spending, transfers and savings deposits; only spending counts toward the
+// daily and monthly figures. The investment suggestion is a share of the
+// discretionary part of that spending. Without a usable history the result is
+// built from a fixed example and flagged simulated so it is never presented as
+// the user's own numbers.
+
+// Spending analysis bounds
+const (
+	maxSpendingDays     = 365
+	spendingPageSize    = 500  // enough history for a year of typical activity
+	simulatedDailySpend = 45.0 // example used when there is no real history
+)
+
+// Outflow categories
+const (
+	spendingCategorySpend = "spending"
+	spendingCategoryMove  = "transfer"
+	spendingCategorySave  = "savings_deposit"
+)
+
+// spendingSavingsMarkers flag an outflow as a deposit into the user's savings
+var spendingSavingsMarkers = []string{"deposit", "savings", "vault"}
+
+// spendingEssentialMarkers flag spending as a need rather than discretionary
+var spendingEssentialMarkers = []string{"rent", "mortgage", "utilit", "electric", "water", "grocer", "insurance",
+	"medical", "pharmacy", "health", "childcare", "tuition", "loan"}
+
+// newSpendingAnalysisTool analyzes the user's real transactions for an investable amount
+func newSpendingAnalysisTool(liminalExecutor core.ToolExecutor) core.Tool {
+	return tools.New("analyze_real_spending_patterns").
+		Description("Analyze actual spending patterns from the user's real Liminal transactions to identify investment opportunities: average daily and monthly spending, outflows split into spending, transfers and savings deposits, and an investable amount based on discretionary spending").
+		Schema(tools.ObjectSchema(map[string]interface{}{
+			"days": tools.StringProperty(fmt.Sprintf("Days of history to analyze, up to %d (e.g. 7, 30, 90, 365)", maxSpendingDays)),
+		}, "days")).
+		Handler(spendingAnalysisHandler(liminalExecutor)).
+		Build()
+}
+
+// spendingAnalysisHandler handles analyze_real_spending_patterns, reading transactions through liminalExecutor
+func spendingAnalysisHandler(liminalExecutor core.ToolExecutor) func(context.Context, *core.ToolParams) (*core.ToolResult, error) {
+	return func(ctx context.Context, toolParams *core.ToolParams) (*core.ToolResult, error) {
+		var params struct {
+			Days string `json:"days"`
+		}
+		if err := json.Unmarshal(toolParams.Input, &params); err != nil {
+			return &core.ToolResult{Success: false, Error: fmt.Sprintf("invalid input: %v", err)}, nil
+		}
+		var v amountValidator
+		days := v.positive("days", params.Days)
+		if days > maxSpendingDays {
+			v.fail("days", "must be at most %d (got %g)", maxSpendingDays, days)
+		}
+		if err := v.err(); err != nil {
+			return &core.ToolResult{Success: false, Error: err.Error()}, nil
+		}
+
+		now := time.Now()
+		txs, ok := fetchTransactions(ctx, liminalExecutor, toolParams.UserID, spendingPageSize)
+		if !ok {
+			return &core.ToolResult{Success: true, Data: simulatedSpending(days, "Your transaction history couldn't be loaded")}, nil
+		}
+		result := analyzeSpending(txs, days, now)
+		if result.TransactionsAnalyzed == 0 {
+			return &core.ToolResult{Success: true, Data: simulatedSpending(days, fmt.Sprintf("No transactions were found in the last %g days", days))}, nil
+		}
+		return &core.ToolResult{Success: true, Data: result}, nil
+	}
+}
+
+// analyzeSpending classifies the outflows from the last days and bases the suggestion on discretionary spend.
+// Transactions without a timestamp are assumed to fall inside the window. Averages are over the part of
+// the window the history covers: a month-old account asked about 90 days is averaged over its month.
+func analyzeSpending(txs []map[string]interface{}, days float64, now time.Time) SpendingAnalysis {
+	r := SpendingAnalysis{
+		Currency:           activeCurrency().Code,
+		AnalysisPeriodDays: days,
+		CoveredDays:        days,
+		OutflowsUSD:        map[string]float64{spendingCategorySpend: 0, spendingCategoryMove: 0, spendingCategorySave: 0},
+	}
+	since := now.Add(-time.Duration(days * 24 * float64(time.Hour)))
+	essential := 0.0
+	oldest, dated := now, false
+	for _, tx := range txs {
+		at, ok := transactionTime(tx)
+		if ok && at.Before(oldest) {
+			oldest, dated = at, true
+		}
+		if ok && at.Before(since) {
+			continue
+		}
+		r.TransactionsAnalyzed++
+		category := spendingCategory(tx)
+		if category == "" {
+			continue
+		}
+		amount := math.Abs(transactionAmount(tx))
+		r.OutflowsUSD[category] += amount
+		if category == spendingCategorySpend && hasMarker(tx, spendingEssentialMarkers) {
+			essential += amount
+		}
+	}
+
+	// The oldest transaction fetched bounds what is known, whether the account is new or the page ran out;
+	// a day is the shortest span averaged over, so one day's purchases aren't scaled up from hours
+	if dated {
+		r.CoveredDays = math.Round(min(days, max(now.Sub(oldest).Hours()/24, 1))*10) / 10
+	}
+	covered := r.CoveredDays
+
+	spent := r.OutflowsUSD[spendingCategorySpend]
+	r.AverageDailySpendingUSD = spent / covered
+	r.MonthlySpendingUSD = r.AverageDailySpendingUSD * 30
+	r.MonthlyDiscretionaryUSD = (spent - essential) / covered * 30
+	r.MonthlyEssentialUSD = essential / covered * 30
+	r.finish(appConfig.Assumptions.EquityReturnPct)
+	period := fmt.Sprintf("Over the last %g days", days)
+	if covered < days {
+		period = fmt.Sprintf("Your history only goes back %g of the %g days asked for. Over those %g days", covered, days, covered)
+	}
+	r.Message = fmt.Sprintf("%s you spent %s (%s/day, about %s/month), of which %s/month looks discretionary. Redirecting a quarter of that, %s/month, into investments would grow to %s in a year.",
+		period, formatMoney(spent), r.AverageDailySpending, r.MonthlySpending, formatMoney(r.MonthlyDiscretionaryUSD),
+		r.RecommendedMonthlyInvest, formatMoney(r.ProjectedFirstYearUSD))
+	return r
+}
+
+// simulatedSpending is the fallback when there is no real history, built from simulatedDailySpend
+func simulatedSpending(days float64, reason string) SpendingAnalysis {
+	r := SpendingAnalysis{
+		Currency:                activeCurrency().Code,
+		Simulated:               true,
+		AnalysisPeriodDays:      days,
+		CoveredDays:             days,
+		AverageDailySpendingUSD: simulatedDailySpend,
+		MonthlySpendingUSD:      simulatedDailySpend * 30,
+		MonthlyDiscretionaryUSD: simulatedDailySpend * 30,
+	}
+	r.finish(appConfig.Assumptions.EquityReturnPct)
+	r.Message = fmt.Sprintf("%s, so these figures are an example based on %s/day of spending, not the user's own. Don't present them as their real spending.",
+		reason, formatMoney(simulatedDailySpend))
+	return r
+}
+
+// finish fills the formatted amounts, the suggested investment and its first-year growth
+func (r *SpendingAnalysis) finish(returnRate float64) {
+	investable := calculateInvestableFromSpending(r.MonthlyDiscretionaryUSD)
+	firstYear := calculateCompoundGrowth(0, investable, returnRate, 1)
+	r.AverageDailySpending = formatMoney(r.AverageDailySpendingUSD)
+	r.MonthlySpending = formatMoney(r.MonthlySpendingUSD)
+	r.RecommendedMonthlyInvestUSD = investable
+	r.RecommendedMonthlyInvest = formatMoney(investable)
+	if r.MonthlySpendingUSD > 0 {
+		r.SavingsOpportunity = fmt.Sprintf("%.1f%% of monthly spending", investable/r.MonthlySpendingUSD*100)
+	}
+	r.InvestmentStrategy = "Dollar-cost average the recommended amount monthly"
+	r.PotentialAnnualGrowth = fmt.Sprintf("%s at %.1f%% annual return", firstYear.ProjectedTotal, returnRate)
+	r.BaselineComparison = firstYear.BaselineComparison
+	r.ProjectedFirstYearUSD = firstYear.ProjectedTotalUSD
+}
+
+// spendingCategory sorts an outflow into spending, transfer or savings_deposit; incoming money is ""
+func spendingCategory(tx map[string]interface{}) string {
+	kind, _ := tx["type"].(string)
+	kind = strings.ToLower(kind)
+	switch {
+	case affordInflowTypes[kind] || strings.Contains(kind, "withdraw"):
+		return "" // money coming into the wallet
+	case strings.Contains(kind, "deposit") || hasMarker(tx, spendingSavingsMarkers):
+		return spendingCategorySave
+	case isRoundUpPurchase(tx):
+		return spendingCategorySpend
+	case roundUpPurchaseTypes[kind] || kind == "send" || strings.Contains(kind, "transfer") || transactionAmount(tx) < 0:
+		return spendingCategoryMove
+	}
+	return ""
+}
+
+// hasMarker reports whether a transaction's category, note or description contains any of markers
+func hasMarker(tx map[string]interface{}, markers []string) bool {
+	for _, field := range []string{"category", "note", "description"} {
+		text, _ := tx[field].(string)
+		text = strings.ToLower(text)
+		for _, marker := range markers {
+			if strings.Contains(text, marker) {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,68 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/becomeliminal/nim-go-sdk/core"
+)
+
+func TestAnalyzeSpendingAveragesOverCoveredDays(t *testing.T) {
+	now := time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+	purchase := func(daysAgo int, amount float64) map[string]interface{} {
+		return map[string]interface{}{"type": "card_payment", "amount": -amount, "created_at": now.AddDate(0, 0, -daysAgo).Format(time.RFC3339)}
+	}
+	tests := []struct {
+		name        string
+		txs         []map[string]interface{}
+		days        float64
+		wantCovered float64
+		wantDaily   float64
+	}{
+		{name: "history longer than the window", days: 30, wantCovered: 30, wantDaily: 300.0 / 30,
+			txs: []map[string]interface{}{purchase(5, 100), purchase(20, 200), purchase(60, 999)}},
+		{name: "account newer than the window", days: 90, wantCovered: 30, wantDaily: 600.0 / 30,
+			txs: []map[string]interface{}{purchase(2, 100), purchase(10, 200), purchase(30, 300)}},
+		{name: "an incoming payment also dates the history", days: 90, wantCovered: 45, wantDaily: 450.0 / 45,
+			txs: []map[string]interface{}{purchase(3, 450), {"type": "receive", "amount": 2000.0, "created_at": now.AddDate(0, 0, -45).Format(time.RFC3339)}}},
+		{name: "same-day history counts as one day", days: 30, wantCovered: 1, wantDaily: 20,
+			txs: []map[string]interface{}{{"type": "card_payment", "amount": -20.0, "created_at": now.Add(-2 * time.Hour).Format(time.RFC3339)}}},
+		{name: "no timestamps assume the whole window", days: 30, wantCovered: 30, wantDaily: 90.0 / 30,
+			txs: []map[string]interface{}{{"type": "card_payment", "amount": -90.0}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := analyzeSpending(tt.txs, tt.days, now)
+			if r.AnalysisPeriodDays != tt.days || r.CoveredDays != tt.wantCovered {
+				t.Errorf("period %v covering %v days, want %v covering %v", r.AnalysisPeriodDays, r.CoveredDays, tt.days, tt.wantCovered)
+			}
+			if !approxEqual(r.AverageDailySpendingUSD, tt.wantDaily) || !approxEqual(r.MonthlySpendingUSD, tt.wantDaily*30) {
+				t.Errorf("daily %v, monthly %v; want %v and %v", r.AverageDailySpendingUSD, r.MonthlySpendingUSD, tt.wantDaily, tt.wantDaily*30)
+			}
+		})
+	}
+}
+
+func TestSpendingAnalysisRejectsBadInput(t *testing.T) {
+	handler := spendingAnalysisHandler(&fakeExecutor{data: `{"transactions": []}`})
+	tests := []struct {
+		name   string
+		input  string
+		fields []string
+	}{
+		{"zero days", `{"days": "0"}`, []string{"days"}},
+		{"negative days", `{"days": "-30"}`, []string{"days"}},
+		{"non-numeric days", `{"days": "a month"}`, []string{"days"}},
+		{"missing days", `{}`, []string{"days"}},
+		{"too many days", `{"days": "100000"}`, []string{"days"}},
+		{"a month", `{"days": "30"}`, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := handler(context.Background(), &core.ToolParams{UserID: "handler_user", Input: json.RawMessage(tt.input)})
+			checkToolResult(t, result, err, tt.fields)
+		})
+	}
+}
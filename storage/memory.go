@@ -0,0 +1,352 @@
+package storage
+
+import (
+	"context"
+	"slices"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Memory keeps everything in process memory; state is lost on restart
+type Memory struct {
+	mu         sync.RWMutex
+	plans      map[string]Plan // keyed by plan ID
+	goals      map[string]Goal // keyed by goal ID
+	executions map[string]Execution
+	portfolios map[string]Portfolio
+	snapshots  map[string]map[string]Snapshot // keyed by user, then date
+	ledger     map[string]Contribution        // keyed by contribution ID
+	milestones map[string][]Milestone         // keyed by user, oldest first
+	alerts     map[string][]Alert             // keyed by user, oldest first
+	learned    map[string][]LearnedConcept    // keyed by user, oldest first
+	quiz       map[string][]QuizAnswer        // keyed by user, oldest first
+	readOnly   map[string]bool
+	audit      map[string][]AuditEntry
+}
+
+// NewMemory returns an empty in-memory store
+func NewMemory() *Memory {
+	return &Memory{
+		plans:      make(map[string]Plan),
+		goals:      make(map[string]Goal),
+		executions: make(map[string]Execution),
+		portfolios: make(map[string]Portfolio),
+		snapshots:  make(map[string]map[string]Snapshot),
+		ledger:     make(map[string]Contribution),
+		milestones: make(map[string][]Milestone),
+		alerts:     make(map[string][]Alert),
+		learned:    make(map[string][]LearnedConcept),
+		quiz:       make(map[string][]QuizAnswer),
+		readOnly:   make(map[string]bool),
+		audit:      make(map[string][]AuditEntry),
+	}
+}
+
+func (m *Memory) SavePlan(ctx context.Context, plan Plan) error {
+	plan.History = slices.Clone(plan.History) // don't share the caller's backing array
+	m.mu.Lock()
+	m.plans[plan.ID] = plan
+	m.mu.Unlock()
+	return nil
+}
+
+func (m *Memory) GetPlan(ctx context.Context, userID, planID string) (Plan, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	plan, ok := m.plans[planID]
+	if !ok || plan.UserID != userID {
+		return Plan{}, ErrNotFound
+	}
+	return plan, nil
+}
+
+func (m *Memory) ListPlans(ctx context.Context, userID string) ([]Plan, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	plans := []Plan{}
+	for _, plan := range m.plans {
+		if plan.UserID == userID {
+			plans = append(plans, plan)
+		}
+	}
+	sort.Slice(plans, func(i, j int) bool { return plans[i].CreatedAt.Before(plans[j].CreatedAt) })
+	return plans, nil
+}
+
+func (m *Memory) ListAllPlans(ctx context.Context) ([]Plan, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	plans := make([]Plan, 0, len(m.plans))
+	for _, plan := range m.plans {
+		plans = append(plans, plan)
+	}
+	sort.Slice(plans, func(i, j int) bool { return plans[i].CreatedAt.Before(plans[j].CreatedAt) })
+	return plans, nil
+}
+
+func (m *Memory) SaveExecution(ctx context.Context, exec Execution) error {
+	m.mu.Lock()
+	m.executions[exec.ID] = exec
+	m.mu.Unlock()
+	return nil
+}
+
+func (m *Memory) GetExecution(ctx context.Context, executionID string) (Execution, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	exec, ok := m.executions[executionID]
+	if !ok {
+		return Execution{}, ErrNotFound
+	}
+	return exec, nil
+}
+
+func (m *Memory) ListExecutions(ctx context.Context, planID string) ([]Execution, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	execs := []Execution{}
+	for _, exec := range m.executions {
+		if exec.PlanID == planID {
+			execs = append(execs, exec)
+		}
+	}
+	sort.Slice(execs, func(i, j int) bool { return execs[i].CreatedAt.Before(execs[j].CreatedAt) })
+	return execs, nil
+}
+
+func (m *Memory) SaveGoal(ctx context.Context, goal Goal) error {
+	m.mu.Lock()
+	m.goals[goal.ID] = goal
+	m.mu.Unlock()
+	return nil
+}
+
+func (m *Memory) ListGoals(ctx context.Context, userID string) ([]Goal, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	goals := []Goal{}
+	for _, goal := range m.goals {
+		if goal.UserID == userID {
+			goals = append(goals, goal)
+		}
+	}
+	sort.Slice(goals, func(i, j int) bool { return goals[i].CreatedAt.Before(goals[j].CreatedAt) })
+	return goals, nil
+}
+
+func (m *Memory) SavePortfolio(ctx context.Context, portfolio Portfolio) error {
+	portfolio.Holdings = slices.Clone(portfolio.Holdings)
+	m.mu.Lock()
+	m.portfolios[portfolio.UserID] = portfolio
+	m.mu.Unlock()
+	return nil
+}
+
+func (m *Memory) GetPortfolio(ctx context.Context, userID string) (Portfolio, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	portfolio, ok := m.portfolios[userID]
+	if !ok {
+		return Portfolio{}, ErrNotFound
+	}
+	portfolio.Holdings = slices.Clone(portfolio.Holdings)
+	return portfolio, nil
+}
+
+func (m *Memory) ListAllPortfolios(ctx context.Context) ([]Portfolio, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	portfolios := make([]Portfolio, 0, len(m.portfolios))
+	for _, portfolio := range m.portfolios {
+		portfolio.Holdings = slices.Clone(portfolio.Holdings)
+		portfolios = append(portfolios, portfolio)
+	}
+	sort.Slice(portfolios, func(i, j int) bool { return portfolios[i].UserID < portfolios[j].UserID })
+	return portfolios, nil
+}
+
+func (m *Memory) SaveSnapshot(ctx context.Context, snapshot Snapshot) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.snapshots[snapshot.UserID] == nil {
+		m.snapshots[snapshot.UserID] = make(map[string]Snapshot)
+	}
+	m.snapshots[snapshot.UserID][snapshot.Date] = snapshot
+	return nil
+}
+
+func (m *Memory) ListSnapshots(ctx context.Context, userID, from string) ([]Snapshot, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	snapshots := []Snapshot{}
+	for date, snapshot := range m.snapshots[userID] {
+		if date >= from {
+			snapshots = append(snapshots, snapshot)
+		}
+	}
+	sort.Slice(snapshots, func(i, j int) bool { return snapshots[i].Date < snapshots[j].Date })
+	return snapshots, nil
+}
+
+func (m *Memory) SaveContribution(ctx context.Context, c Contribution) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if _, ok := m.ledger[c.ID]; ok {
+		return false, nil
+	}
+	m.ledger[c.ID] = c
+	return true, nil
+}
+
+func (m *Memory) ListContributions(ctx context.Context, userID string) ([]Contribution, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	contributions := []Contribution{}
+	for _, c := range m.ledger {
+		if c.UserID == userID {
+			contributions = append(contributions, c)
+		}
+	}
+	sort.Slice(contributions, func(i, j int) bool { return contributions[i].Date.Before(contributions[j].Date) })
+	return contributions, nil
+}
+
+func (m *Memory) SaveMilestone(ctx context.Context, milestone Milestone) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, existing := range m.milestones[milestone.UserID] {
+		if existing.Key == milestone.Key {
+			return false, nil
+		}
+	}
+	m.milestones[milestone.UserID] = append(m.milestones[milestone.UserID], milestone)
+	return true, nil
+}
+
+func (m *Memory) ListMilestones(ctx context.Context, userID string) ([]Milestone, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	milestones := append([]Milestone{}, m.milestones[userID]...)
+	sort.SliceStable(milestones, func(i, j int) bool { return milestones[i].AchievedAt.Before(milestones[j].AchievedAt) })
+	return milestones, nil
+}
+
+func (m *Memory) MarkMilestonesAnnounced(ctx context.Context, userID string, at time.Time) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for i, milestone := range m.milestones[userID] {
+		if milestone.AnnouncedAt == nil {
+			m.milestones[userID][i].AnnouncedAt = &at
+		}
+	}
+	return nil
+}
+
+func (m *Memory) RaiseAlert(ctx context.Context, alert Alert) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for i, existing := range m.alerts[alert.UserID] {
+		if existing.Key == alert.Key && existing.ClearedAt == nil {
+			m.alerts[alert.UserID][i].Message = alert.Message
+			m.alerts[alert.UserID][i].UpdatedAt = alert.UpdatedAt
+			return false, nil
+		}
+	}
+	m.alerts[alert.UserID] = append(m.alerts[alert.UserID], alert)
+	return true, nil
+}
+
+func (m *Memory) ClearAlert(ctx context.Context, userID, key string, at time.Time) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for i, existing := range m.alerts[userID] {
+		if existing.Key == key && existing.ClearedAt == nil {
+			m.alerts[userID][i].ClearedAt = &at
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (m *Memory) ListAlerts(ctx context.Context, userID string) ([]Alert, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return append([]Alert{}, m.alerts[userID]...), nil
+}
+
+func (m *Memory) MarkAlertsAnnounced(ctx context.Context, userID string, at time.Time) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for i, alert := range m.alerts[userID] {
+		if alert.AnnouncedAt == nil && alert.ClearedAt == nil {
+			m.alerts[userID][i].AnnouncedAt = &at
+		}
+	}
+	return nil
+}
+
+func (m *Memory) SaveLearnedConcept(ctx context.Context, c LearnedConcept) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, existing := range m.learned[c.UserID] {
+		if existing.Concept == c.Concept {
+			return false, nil
+		}
+	}
+	m.learned[c.UserID] = append(m.learned[c.UserID], c)
+	return true, nil
+}
+
+func (m *Memory) ListLearnedConcepts(ctx context.Context, userID string) ([]LearnedConcept, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	learned := append([]LearnedConcept{}, m.learned[userID]...)
+	sort.SliceStable(learned, func(i, j int) bool { return learned[i].ExplainedAt.Before(learned[j].ExplainedAt) })
+	return learned, nil
+}
+
+func (m *Memory) SaveQuizAnswer(ctx context.Context, a QuizAnswer) error {
+	m.mu.Lock()
+	m.quiz[a.UserID] = append(m.quiz[a.UserID], a)
+	m.mu.Unlock()
+	return nil
+}
+
+func (m *Memory) ListQuizAnswers(ctx context.Context, userID string) ([]QuizAnswer, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	answers := append([]QuizAnswer{}, m.quiz[userID]...)
+	sort.SliceStable(answers, func(i, j int) bool { return answers[i].AnsweredAt.Before(answers[j].AnsweredAt) })
+	return answers, nil
+}
+
+func (m *Memory) SetReadOnly(ctx context.Context, userID string, readOnly bool) error {
+	m.mu.Lock()
+	m.readOnly[userID] = readOnly
+	m.mu.Unlock()
+	return nil
+}
+
+func (m *Memory) IsReadOnly(ctx context.Context, userID string) (bool, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.readOnly[userID], nil
+}
+
+func (m *Memory) AppendAudit(ctx context.Context, entry AuditEntry) error {
+	m.mu.Lock()
+	m.audit[entry.UserID] = append(m.audit[entry.UserID], entry)
+	m.mu.Unlock()
+	return nil
+}
+
+func (m *Memory) ListAudit(ctx context.Context, userID string) ([]AuditEntry, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return append([]AuditEntry{}, m.audit[userID]...), nil
+}
+
+func (m *Memory) Close() error {
+	return nil
+}
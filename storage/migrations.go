@@ -0,0 +1,198 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+)
+
+// migrations evolve the SQLite schema. Append new steps; never edit or
+// reorder existing ones, since their index is the recorded schema version.
+var migrations = []string{
+	// 1: initial schema
+	`CREATE TABLE plans (
+		id              TEXT PRIMARY KEY,
+		user_id         TEXT NOT NULL,
+		monthly_amount  REAL NOT NULL,
+		investment_type TEXT NOT NULL,
+		strategy        TEXT NOT NULL,
+		start_date      TEXT NOT NULL,
+		created_at      TEXT NOT NULL
+	);
+	CREATE INDEX plans_user ON plans (user_id, created_at);
+
+	CREATE TABLE goals (
+		id                   TEXT PRIMARY KEY,
+		user_id              TEXT NOT NULL,
+		name                 TEXT NOT NULL,
+		target_amount        REAL NOT NULL,
+		target_date          TEXT NOT NULL,
+		monthly_contribution REAL NOT NULL,
+		investment_type      TEXT NOT NULL,
+		created_at           TEXT NOT NULL
+	);
+	CREATE INDEX goals_user ON goals (user_id, created_at);
+
+	CREATE TABLE portfolios (
+		user_id            TEXT PRIMARY KEY,
+		total_balance      REAL NOT NULL,
+		savings_allocation REAL NOT NULL,
+		stock_allocation   REAL NOT NULL,
+		risk_tolerance     TEXT NOT NULL,
+		monthly_savings    REAL NOT NULL,
+		age_group          TEXT NOT NULL,
+		updated_at         TEXT NOT NULL
+	);
+
+	CREATE TABLE user_flags (
+		user_id   TEXT PRIMARY KEY,
+		read_only INTEGER NOT NULL DEFAULT 0
+	);
+
+	CREATE TABLE audit_log (
+		id      INTEGER PRIMARY KEY AUTOINCREMENT,
+		time    TEXT NOT NULL,
+		user_id TEXT NOT NULL,
+		actor   TEXT NOT NULL,
+		action  TEXT NOT NULL,
+		detail  TEXT NOT NULL DEFAULT ''
+	);
+	CREATE INDEX audit_log_user ON audit_log (user_id, id);`,
+
+	// 2: plan lifecycle status
+	`ALTER TABLE plans ADD COLUMN status TEXT NOT NULL DEFAULT 'active';`,
+
+	// 3: cancellation timestamp
+	`ALTER TABLE plans ADD COLUMN cancelled_at TEXT;`,
+
+	// 4: plan change history, stored as a JSON array of PlanChange
+	`ALTER TABLE plans ADD COLUMN history TEXT NOT NULL DEFAULT '[]';`,
+
+	// 5: scheduled resume date for paused plans
+	`ALTER TABLE plans ADD COLUMN resume_date TEXT NOT NULL DEFAULT '';`,
+
+	// 6: scheduled plan executions, one per plan per period
+	`CREATE TABLE executions (
+		id         TEXT PRIMARY KEY,
+		plan_id    TEXT NOT NULL,
+		user_id    TEXT NOT NULL,
+		period     TEXT NOT NULL,
+		amount     REAL NOT NULL,
+		tool       TEXT NOT NULL,
+		status     TEXT NOT NULL,
+		attempts   INTEGER NOT NULL DEFAULT 0,
+		last_error TEXT NOT NULL DEFAULT '',
+		response   TEXT NOT NULL DEFAULT '',
+		created_at TEXT NOT NULL,
+		updated_at TEXT NOT NULL,
+		UNIQUE (plan_id, period)
+	);`,
+
+	// 7: individual holdings, stored as a JSON array of Holding
+	`ALTER TABLE portfolios ADD COLUMN holdings TEXT NOT NULL DEFAULT '[]';`,
+
+	// 8: daily portfolio value snapshots
+	`CREATE TABLE snapshots (
+		user_id     TEXT NOT NULL,
+		date        TEXT NOT NULL,
+		total_value REAL NOT NULL,
+		priced      INTEGER NOT NULL DEFAULT 0,
+		created_at  TEXT NOT NULL,
+		PRIMARY KEY (user_id, date)
+	);`,
+
+	// 9: contributions ledger, keyed by the execution or transaction it came from
+	`CREATE TABLE contributions (
+		id         TEXT PRIMARY KEY,
+		user_id    TEXT NOT NULL,
+		date       TEXT NOT NULL,
+		amount     REAL NOT NULL,
+		source     TEXT NOT NULL,
+		note       TEXT NOT NULL DEFAULT '',
+		created_at TEXT NOT NULL
+	);
+	CREATE INDEX contributions_user ON contributions (user_id, date);`,
+
+	// 10: per-plan spending spike threshold for skipping a month
+	`ALTER TABLE plans ADD COLUMN skip_on_spike_pct REAL NOT NULL DEFAULT 0;`,
+
+	// 11: milestones, one per user and key
+	`CREATE TABLE milestones (
+		user_id      TEXT NOT NULL,
+		key          TEXT NOT NULL,
+		label        TEXT NOT NULL,
+		achieved_at  TEXT NOT NULL,
+		announced_at TEXT,
+		PRIMARY KEY (user_id, key)
+	);`,
+	// 12: proactive alerts, at most one open per user and key
+	`CREATE TABLE alerts (
+		id           TEXT PRIMARY KEY,
+		user_id      TEXT NOT NULL,
+		kind         TEXT NOT NULL,
+		key          TEXT NOT NULL,
+		message      TEXT NOT NULL,
+		created_at   TEXT NOT NULL,
+		updated_at   TEXT NOT NULL,
+		announced_at TEXT,
+		cleared_at   TEXT
+	);
+	CREATE UNIQUE INDEX alerts_open ON alerts (user_id, key) WHERE cleared_at IS NULL;`,
+	// 13: concepts explained to each user, for suggest_next_concept
+	`CREATE TABLE learned_concepts (
+		user_id      TEXT NOT NULL,
+		concept      TEXT NOT NULL,
+		explained_at TEXT NOT NULL,
+		PRIMARY KEY (user_id, concept)
+	);`,
+	// 14: investing experience on the profile, for explanation levels
+	`ALTER TABLE portfolios ADD COLUMN experience TEXT NOT NULL DEFAULT '';`,
+	// 15: concept quiz answers, every attempt
+	`CREATE TABLE quiz_answers (
+		user_id     TEXT NOT NULL,
+		concept     TEXT NOT NULL,
+		question_id TEXT NOT NULL,
+		choice      INTEGER NOT NULL,
+		correct     INTEGER NOT NULL,
+		answered_at TEXT NOT NULL
+	);
+	CREATE INDEX quiz_answers_user ON quiz_answers (user_id, answered_at);`,
+	// 16: preferred language on the profile, for localized explanations
+	`ALTER TABLE portfolios ADD COLUMN locale TEXT NOT NULL DEFAULT '';`,
+	// 17: yearly raise in a goal's monthly contribution, for progress checks
+	`ALTER TABLE goals ADD COLUMN annual_increase_pct REAL NOT NULL DEFAULT 0;`,
+}
+
+// migrate applies every migration newer than the database's recorded version
+func migrate(ctx context.Context, db *sql.DB) error {
+	if _, err := db.ExecContext(ctx, `CREATE TABLE IF NOT EXISTS schema_migrations (version INTEGER PRIMARY KEY)`); err != nil {
+		return fmt.Errorf("create schema_migrations: %w", err)
+	}
+
+	var current int
+	if err := db.QueryRowContext(ctx, `SELECT COALESCE(MAX(version), 0) FROM schema_migrations`).Scan(&current); err != nil {
+		return fmt.Errorf("read schema version: %w", err)
+	}
+	if current > len(migrations) {
+		return fmt.Errorf("database schema version %d is newer than this binary (%d)", current, len(migrations))
+	}
+
+	for version := current + 1; version <= len(migrations); version++ {
+		tx, err := db.BeginTx(ctx, nil)
+		if err != nil {
+			return err
+		}
+		if _, err := tx.ExecContext(ctx, migrations[version-1]); err != nil {
+			tx.Rollback()
+			return fmt.Errorf("migration %d: %w", version, err)
+		}
+		if _, err := tx.ExecContext(ctx, `INSERT INTO schema_migrations (version) VALUES (?)`, version); err != nil {
+			tx.Rollback()
+			return fmt.Errorf("record migration %d: %w", version, err)
+		}
+		if err := tx.Commit(); err != nil {
+			return fmt.Errorf("commit migration %d: %w", version, err)
+		}
+	}
+	return nil
+}
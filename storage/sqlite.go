@@ -0,0 +1,594 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	_ "modernc.org/sqlite" // pure-Go driver, registered as "sqlite"
+)
+
+// SQLite persists state to a single database file
+type SQLite struct {
+	db *sql.DB
+}
+
+// OpenSQLite opens (or creates) the database at path and brings its schema up to date
+func OpenSQLite(path string) (*SQLite, error) {
+	db, err := sql.Open("sqlite", path+"?_pragma=busy_timeout(5000)&_pragma=journal_mode(WAL)")
+	if err != nil {
+		return nil, fmt.Errorf("open %s: %w", path, err)
+	}
+	// SQLite allows a single writer; one connection avoids SQLITE_BUSY between our own goroutines
+	db.SetMaxOpenConns(1)
+
+	if err := migrate(context.Background(), db); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("migrate %s: %w", path, err)
+	}
+	return &SQLite{db: db}, nil
+}
+
+// Times are stored as RFC 3339 text so the file stays readable with the sqlite3 CLI
+func formatTime(t time.Time) string {
+	return t.UTC().Format(time.RFC3339Nano)
+}
+
+func parseTime(s string) time.Time {
+	t, _ := time.Parse(time.RFC3339Nano, s)
+	return t
+}
+
+// Optional timestamps are stored as NULL when unset
+func formatOptionalTime(t *time.Time) sql.NullString {
+	if t == nil {
+		return sql.NullString{}
+	}
+	return sql.NullString{String: formatTime(*t), Valid: true}
+}
+
+func parseOptionalTime(s sql.NullString) *time.Time {
+	if !s.Valid {
+		return nil
+	}
+	t := parseTime(s.String)
+	return &t
+}
+
+func (s *SQLite) SavePlan(ctx context.Context, plan Plan) error {
+	history, err := json.Marshal(append([]PlanChange{}, plan.History...))
+	if err != nil {
+		return fmt.Errorf("encode plan history: %w", err)
+	}
+	_, err = s.db.ExecContext(ctx, `
+		INSERT INTO plans (id, user_id, monthly_amount, investment_type, strategy, start_date, status, created_at, cancelled_at, history, resume_date, skip_on_spike_pct)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT (id) DO UPDATE SET
+			monthly_amount = excluded.monthly_amount,
+			investment_type = excluded.investment_type,
+			strategy = excluded.strategy,
+			start_date = excluded.start_date,
+			status = excluded.status,
+			cancelled_at = excluded.cancelled_at,
+			history = excluded.history,
+			resume_date = excluded.resume_date,
+			skip_on_spike_pct = excluded.skip_on_spike_pct`,
+		plan.ID, plan.UserID, plan.MonthlyAmount, plan.InvestmentType, plan.Strategy, plan.StartDate, plan.Status,
+		formatTime(plan.CreatedAt), formatOptionalTime(plan.CancelledAt), string(history), plan.ResumeDate, plan.SkipOnSpikePct)
+	return err
+}
+
+const planColumns = `id, user_id, monthly_amount, investment_type, strategy, start_date, status, created_at, cancelled_at, history, resume_date, skip_on_spike_pct`
+
+func scanPlan(row interface{ Scan(...any) error }) (Plan, error) {
+	var p Plan
+	var createdAt, history string
+	var cancelledAt sql.NullString
+	if err := row.Scan(&p.ID, &p.UserID, &p.MonthlyAmount, &p.InvestmentType, &p.Strategy, &p.StartDate, &p.Status,
+		&createdAt, &cancelledAt, &history, &p.ResumeDate, &p.SkipOnSpikePct); err != nil {
+		return Plan{}, err
+	}
+	p.CreatedAt = parseTime(createdAt)
+	p.CancelledAt = parseOptionalTime(cancelledAt)
+	if err := json.Unmarshal([]byte(history), &p.History); err != nil {
+		return Plan{}, fmt.Errorf("decode history for plan %s: %w", p.ID, err)
+	}
+	return p, nil
+}
+
+func (s *SQLite) GetPlan(ctx context.Context, userID, planID string) (Plan, error) {
+	row := s.db.QueryRowContext(ctx, `SELECT `+planColumns+` FROM plans WHERE id = ? AND user_id = ?`, planID, userID)
+	plan, err := scanPlan(row)
+	if errors.Is(err, sql.ErrNoRows) {
+		return Plan{}, ErrNotFound
+	}
+	return plan, err
+}
+
+func (s *SQLite) ListPlans(ctx context.Context, userID string) ([]Plan, error) {
+	rows, err := s.db.QueryContext(ctx, `SELECT `+planColumns+` FROM plans WHERE user_id = ? ORDER BY created_at`, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	plans := []Plan{}
+	for rows.Next() {
+		plan, err := scanPlan(rows)
+		if err != nil {
+			return nil, err
+		}
+		plans = append(plans, plan)
+	}
+	return plans, rows.Err()
+}
+
+func (s *SQLite) ListAllPlans(ctx context.Context) ([]Plan, error) {
+	rows, err := s.db.QueryContext(ctx, `SELECT `+planColumns+` FROM plans ORDER BY created_at`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	plans := []Plan{}
+	for rows.Next() {
+		plan, err := scanPlan(rows)
+		if err != nil {
+			return nil, err
+		}
+		plans = append(plans, plan)
+	}
+	return plans, rows.Err()
+}
+
+func (s *SQLite) SaveExecution(ctx context.Context, e Execution) error {
+	_, err := s.db.ExecContext(ctx, `
+		INSERT INTO executions (id, plan_id, user_id, period, amount, tool, status, attempts, last_error, response, created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT (id) DO UPDATE SET
+			status = excluded.status,
+			attempts = excluded.attempts,
+			last_error = excluded.last_error,
+			response = excluded.response,
+			updated_at = excluded.updated_at`,
+		e.ID, e.PlanID, e.UserID, e.Period, e.Amount, e.Tool, e.Status, e.Attempts, e.LastError, e.Response,
+		formatTime(e.CreatedAt), formatTime(e.UpdatedAt))
+	return err
+}
+
+const executionColumns = `id, plan_id, user_id, period, amount, tool, status, attempts, last_error, response, created_at, updated_at`
+
+func scanExecution(row interface{ Scan(...any) error }) (Execution, error) {
+	var e Execution
+	var createdAt, updatedAt string
+	err := row.Scan(&e.ID, &e.PlanID, &e.UserID, &e.Period, &e.Amount, &e.Tool, &e.Status, &e.Attempts, &e.LastError, &e.Response,
+		&createdAt, &updatedAt)
+	e.CreatedAt = parseTime(createdAt)
+	e.UpdatedAt = parseTime(updatedAt)
+	return e, err
+}
+
+func (s *SQLite) GetExecution(ctx context.Context, executionID string) (Execution, error) {
+	exec, err := scanExecution(s.db.QueryRowContext(ctx, `SELECT `+executionColumns+` FROM executions WHERE id = ?`, executionID))
+	if errors.Is(err, sql.ErrNoRows) {
+		return Execution{}, ErrNotFound
+	}
+	return exec, err
+}
+
+func (s *SQLite) ListExecutions(ctx context.Context, planID string) ([]Execution, error) {
+	rows, err := s.db.QueryContext(ctx, `SELECT `+executionColumns+` FROM executions WHERE plan_id = ? ORDER BY created_at`, planID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	execs := []Execution{}
+	for rows.Next() {
+		exec, err := scanExecution(rows)
+		if err != nil {
+			return nil, err
+		}
+		execs = append(execs, exec)
+	}
+	return execs, rows.Err()
+}
+
+func (s *SQLite) SaveGoal(ctx context.Context, goal Goal) error {
+	_, err := s.db.ExecContext(ctx, `
+		INSERT INTO goals (id, user_id, name, target_amount, target_date, monthly_contribution, annual_increase_pct, investment_type, created_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT (id) DO UPDATE SET
+			name = excluded.name,
+			target_amount = excluded.target_amount,
+			target_date = excluded.target_date,
+			monthly_contribution = excluded.monthly_contribution,
+			annual_increase_pct = excluded.annual_increase_pct,
+			investment_type = excluded.investment_type`,
+		goal.ID, goal.UserID, goal.Name, goal.TargetAmount, goal.TargetDate, goal.MonthlyContribution, goal.AnnualIncreasePct, goal.InvestmentType, formatTime(goal.CreatedAt))
+	return err
+}
+
+func (s *SQLite) ListGoals(ctx context.Context, userID string) ([]Goal, error) {
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT id, user_id, name, target_amount, target_date, monthly_contribution, annual_increase_pct, investment_type, created_at
+		FROM goals WHERE user_id = ? ORDER BY created_at`, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	goals := []Goal{}
+	for rows.Next() {
+		var g Goal
+		var createdAt string
+		if err := rows.Scan(&g.ID, &g.UserID, &g.Name, &g.TargetAmount, &g.TargetDate, &g.MonthlyContribution, &g.AnnualIncreasePct, &g.InvestmentType, &createdAt); err != nil {
+			return nil, err
+		}
+		g.CreatedAt = parseTime(createdAt)
+		goals = append(goals, g)
+	}
+	return goals, rows.Err()
+}
+
+func (s *SQLite) SavePortfolio(ctx context.Context, p Portfolio) error {
+	holdings, err := json.Marshal(append([]Holding{}, p.Holdings...))
+	if err != nil {
+		return fmt.Errorf("encode holdings: %w", err)
+	}
+	_, err = s.db.ExecContext(ctx, `
+		INSERT INTO portfolios (user_id, total_balance, savings_allocation, stock_allocation, risk_tolerance, monthly_savings, age_group, experience, locale, holdings, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT (user_id) DO UPDATE SET
+			total_balance = excluded.total_balance,
+			savings_allocation = excluded.savings_allocation,
+			stock_allocation = excluded.stock_allocation,
+			risk_tolerance = excluded.risk_tolerance,
+			monthly_savings = excluded.monthly_savings,
+			age_group = excluded.age_group,
+			experience = excluded.experience,
+			locale = excluded.locale,
+			holdings = excluded.holdings,
+			updated_at = excluded.updated_at`,
+		p.UserID, p.TotalBalance, p.SavingsAllocation, p.StockAllocation, p.RiskTolerance, p.MonthlySavings, p.AgeGroup, p.Experience, p.Locale, string(holdings), formatTime(p.UpdatedAt))
+	return err
+}
+
+const portfolioColumns = `user_id, total_balance, savings_allocation, stock_allocation, risk_tolerance, monthly_savings, age_group, experience, locale, holdings, updated_at`
+
+func scanPortfolio(row interface{ Scan(...any) error }) (Portfolio, error) {
+	var p Portfolio
+	var holdings, updatedAt string
+	if err := row.Scan(&p.UserID, &p.TotalBalance, &p.SavingsAllocation, &p.StockAllocation, &p.RiskTolerance, &p.MonthlySavings, &p.AgeGroup,
+		&p.Experience, &p.Locale, &holdings, &updatedAt); err != nil {
+		return Portfolio{}, err
+	}
+	p.UpdatedAt = parseTime(updatedAt)
+	if err := json.Unmarshal([]byte(holdings), &p.Holdings); err != nil {
+		return Portfolio{}, fmt.Errorf("decode holdings for %s: %w", p.UserID, err)
+	}
+	return p, nil
+}
+
+func (s *SQLite) GetPortfolio(ctx context.Context, userID string) (Portfolio, error) {
+	p, err := scanPortfolio(s.db.QueryRowContext(ctx, `SELECT `+portfolioColumns+` FROM portfolios WHERE user_id = ?`, userID))
+	if errors.Is(err, sql.ErrNoRows) {
+		return Portfolio{}, ErrNotFound
+	}
+	return p, err
+}
+
+func (s *SQLite) ListAllPortfolios(ctx context.Context) ([]Portfolio, error) {
+	rows, err := s.db.QueryContext(ctx, `SELECT `+portfolioColumns+` FROM portfolios ORDER BY user_id`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	portfolios := []Portfolio{}
+	for rows.Next() {
+		p, err := scanPortfolio(rows)
+		if err != nil {
+			return nil, err
+		}
+		portfolios = append(portfolios, p)
+	}
+	return portfolios, rows.Err()
+}
+
+func (s *SQLite) SaveSnapshot(ctx context.Context, snap Snapshot) error {
+	_, err := s.db.ExecContext(ctx, `
+		INSERT INTO snapshots (user_id, date, total_value, priced, created_at)
+		VALUES (?, ?, ?, ?, ?)
+		ON CONFLICT (user_id, date) DO UPDATE SET
+			total_value = excluded.total_value,
+			priced = excluded.priced,
+			created_at = excluded.created_at`,
+		snap.UserID, snap.Date, snap.TotalValue, snap.Priced, formatTime(snap.CreatedAt))
+	return err
+}
+
+func (s *SQLite) ListSnapshots(ctx context.Context, userID, from string) ([]Snapshot, error) {
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT user_id, date, total_value, priced, created_at
+		FROM snapshots WHERE user_id = ? AND date >= ? ORDER BY date`, userID, from)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	snapshots := []Snapshot{}
+	for rows.Next() {
+		var snap Snapshot
+		var createdAt string
+		if err := rows.Scan(&snap.UserID, &snap.Date, &snap.TotalValue, &snap.Priced, &createdAt); err != nil {
+			return nil, err
+		}
+		snap.CreatedAt = parseTime(createdAt)
+		snapshots = append(snapshots, snap)
+	}
+	return snapshots, rows.Err()
+}
+
+func (s *SQLite) SaveContribution(ctx context.Context, c Contribution) (bool, error) {
+	res, err := s.db.ExecContext(ctx, `
+		INSERT INTO contributions (id, user_id, date, amount, source, note, created_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT (id) DO NOTHING`,
+		c.ID, c.UserID, formatTime(c.Date), c.Amount, c.Source, c.Note, formatTime(c.CreatedAt))
+	if err != nil {
+		return false, err
+	}
+	n, err := res.RowsAffected()
+	return n > 0, err
+}
+
+func (s *SQLite) ListContributions(ctx context.Context, userID string) ([]Contribution, error) {
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT id, user_id, date, amount, source, note, created_at
+		FROM contributions WHERE user_id = ? ORDER BY date`, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	contributions := []Contribution{}
+	for rows.Next() {
+		var c Contribution
+		var date, createdAt string
+		if err := rows.Scan(&c.ID, &c.UserID, &date, &c.Amount, &c.Source, &c.Note, &createdAt); err != nil {
+			return nil, err
+		}
+		c.Date = parseTime(date)
+		c.CreatedAt = parseTime(createdAt)
+		contributions = append(contributions, c)
+	}
+	return contributions, rows.Err()
+}
+
+func (s *SQLite) SaveMilestone(ctx context.Context, m Milestone) (bool, error) {
+	res, err := s.db.ExecContext(ctx, `
+		INSERT INTO milestones (user_id, key, label, achieved_at, announced_at)
+		VALUES (?, ?, ?, ?, ?)
+		ON CONFLICT (user_id, key) DO NOTHING`,
+		m.UserID, m.Key, m.Label, formatTime(m.AchievedAt), formatOptionalTime(m.AnnouncedAt))
+	if err != nil {
+		return false, err
+	}
+	n, err := res.RowsAffected()
+	return n > 0, err
+}
+
+func (s *SQLite) ListMilestones(ctx context.Context, userID string) ([]Milestone, error) {
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT user_id, key, label, achieved_at, announced_at
+		FROM milestones WHERE user_id = ? ORDER BY achieved_at`, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	milestones := []Milestone{}
+	for rows.Next() {
+		var m Milestone
+		var achievedAt string
+		var announcedAt sql.NullString
+		if err := rows.Scan(&m.UserID, &m.Key, &m.Label, &achievedAt, &announcedAt); err != nil {
+			return nil, err
+		}
+		m.AchievedAt = parseTime(achievedAt)
+		m.AnnouncedAt = parseOptionalTime(announcedAt)
+		milestones = append(milestones, m)
+	}
+	return milestones, rows.Err()
+}
+
+func (s *SQLite) MarkMilestonesAnnounced(ctx context.Context, userID string, at time.Time) error {
+	_, err := s.db.ExecContext(ctx, `
+		UPDATE milestones SET announced_at = ? WHERE user_id = ? AND announced_at IS NULL`,
+		formatTime(at), userID)
+	return err
+}
+
+func (s *SQLite) RaiseAlert(ctx context.Context, a Alert) (bool, error) {
+	res, err := s.db.ExecContext(ctx, `
+		UPDATE alerts SET message = ?, updated_at = ?
+		WHERE user_id = ? AND key = ? AND cleared_at IS NULL`,
+		a.Message, formatTime(a.UpdatedAt), a.UserID, a.Key)
+	if err != nil {
+		return false, err
+	}
+	if n, err := res.RowsAffected(); err != nil || n > 0 {
+		return false, err
+	}
+	// The partial unique index turns a racing second insert into a no-op rather than a duplicate
+	res, err = s.db.ExecContext(ctx, `
+		INSERT INTO alerts (id, user_id, kind, key, message, created_at, updated_at, announced_at, cleared_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT DO NOTHING`,
+		a.ID, a.UserID, a.Kind, a.Key, a.Message, formatTime(a.CreatedAt), formatTime(a.UpdatedAt),
+		formatOptionalTime(a.AnnouncedAt), formatOptionalTime(a.ClearedAt))
+	if err != nil {
+		return false, err
+	}
+	n, err := res.RowsAffected()
+	return n > 0, err
+}
+
+func (s *SQLite) ClearAlert(ctx context.Context, userID, key string, at time.Time) (bool, error) {
+	res, err := s.db.ExecContext(ctx, `
+		UPDATE alerts SET cleared_at = ? WHERE user_id = ? AND key = ? AND cleared_at IS NULL`,
+		formatTime(at), userID, key)
+	if err != nil {
+		return false, err
+	}
+	n, err := res.RowsAffected()
+	return n > 0, err
+}
+
+func (s *SQLite) ListAlerts(ctx context.Context, userID string) ([]Alert, error) {
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT id, user_id, kind, key, message, created_at, updated_at, announced_at, cleared_at
+		FROM alerts WHERE user_id = ? ORDER BY created_at`, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	alerts := []Alert{}
+	for rows.Next() {
+		var a Alert
+		var createdAt, updatedAt string
+		var announcedAt, clearedAt sql.NullString
+		if err := rows.Scan(&a.ID, &a.UserID, &a.Kind, &a.Key, &a.Message, &createdAt, &updatedAt, &announcedAt, &clearedAt); err != nil {
+			return nil, err
+		}
+		a.CreatedAt = parseTime(createdAt)
+		a.UpdatedAt = parseTime(updatedAt)
+		a.AnnouncedAt = parseOptionalTime(announcedAt)
+		a.ClearedAt = parseOptionalTime(clearedAt)
+		alerts = append(alerts, a)
+	}
+	return alerts, rows.Err()
+}
+
+func (s *SQLite) MarkAlertsAnnounced(ctx context.Context, userID string, at time.Time) error {
+	_, err := s.db.ExecContext(ctx, `
+		UPDATE alerts SET announced_at = ? WHERE user_id = ? AND announced_at IS NULL AND cleared_at IS NULL`,
+		formatTime(at), userID)
+	return err
+}
+
+func (s *SQLite) SaveLearnedConcept(ctx context.Context, c LearnedConcept) (bool, error) {
+	res, err := s.db.ExecContext(ctx, `
+		INSERT INTO learned_concepts (user_id, concept, explained_at)
+		VALUES (?, ?, ?)
+		ON CONFLICT (user_id, concept) DO NOTHING`,
+		c.UserID, c.Concept, formatTime(c.ExplainedAt))
+	if err != nil {
+		return false, err
+	}
+	n, err := res.RowsAffected()
+	return n > 0, err
+}
+
+func (s *SQLite) ListLearnedConcepts(ctx context.Context, userID string) ([]LearnedConcept, error) {
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT user_id, concept, explained_at
+		FROM learned_concepts WHERE user_id = ? ORDER BY explained_at`, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	learned := []LearnedConcept{}
+	for rows.Next() {
+		var c LearnedConcept
+		var explainedAt string
+		if err := rows.Scan(&c.UserID, &c.Concept, &explainedAt); err != nil {
+			return nil, err
+		}
+		c.ExplainedAt = parseTime(explainedAt)
+		learned = append(learned, c)
+	}
+	return learned, rows.Err()
+}
+
+func (s *SQLite) SaveQuizAnswer(ctx context.Context, a QuizAnswer) error {
+	_, err := s.db.ExecContext(ctx, `
+		INSERT INTO quiz_answers (user_id, concept, question_id, choice, correct, answered_at)
+		VALUES (?, ?, ?, ?, ?, ?)`,
+		a.UserID, a.Concept, a.QuestionID, a.Choice, a.Correct, formatTime(a.AnsweredAt))
+	return err
+}
+
+func (s *SQLite) ListQuizAnswers(ctx context.Context, userID string) ([]QuizAnswer, error) {
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT user_id, concept, question_id, choice, correct, answered_at
+		FROM quiz_answers WHERE user_id = ? ORDER BY answered_at, rowid`, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	answers := []QuizAnswer{}
+	for rows.Next() {
+		var a QuizAnswer
+		var answeredAt string
+		if err := rows.Scan(&a.UserID, &a.Concept, &a.QuestionID, &a.Choice, &a.Correct, &answeredAt); err != nil {
+			return nil, err
+		}
+		a.AnsweredAt = parseTime(answeredAt)
+		answers = append(answers, a)
+	}
+	return answers, rows.Err()
+}
+
+func (s *SQLite) SetReadOnly(ctx context.Context, userID string, readOnly bool) error {
+	_, err := s.db.ExecContext(ctx, `
+		INSERT INTO user_flags (user_id, read_only) VALUES (?, ?)
+		ON CONFLICT (user_id) DO UPDATE SET read_only = excluded.read_only`, userID, readOnly)
+	return err
+}
+
+func (s *SQLite) IsReadOnly(ctx context.Context, userID string) (bool, error) {
+	var readOnly bool
+	err := s.db.QueryRowContext(ctx, `SELECT read_only FROM user_flags WHERE user_id = ?`, userID).Scan(&readOnly)
+	if errors.Is(err, sql.ErrNoRows) {
+		return false, nil
+	}
+	return readOnly, err
+}
+
+func (s *SQLite) AppendAudit(ctx context.Context, e AuditEntry) error {
+	_, err := s.db.ExecContext(ctx, `INSERT INTO audit_log (time, user_id, actor, action, detail) VALUES (?, ?, ?, ?, ?)`,
+		formatTime(e.Time), e.UserID, e.Actor, e.Action, e.Detail)
+	return err
+}
+
+func (s *SQLite) ListAudit(ctx context.Context, userID string) ([]AuditEntry, error) {
+	rows, err := s.db.QueryContext(ctx, `SELECT time, user_id, actor, action, detail FROM audit_log WHERE user_id = ? ORDER BY id`, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	entries := []AuditEntry{}
+	for rows.Next() {
+		var e AuditEntry
+		var t string
+		if err := rows.Scan(&t, &e.UserID, &e.Actor, &e.Action, &e.Detail); err != nil {
+			return nil, err
+		}
+		e.Time = parseTime(t)
+		entries = append(entries, e)
+	}
+	return entries, rows.Err()
+}
+
+func (s *SQLite) Close() error {
+	return s.db.Close()
+}
@@ -0,0 +1,254 @@
+// Package storage persists per-user InvestMate state: plans, goals, portfolios,
+// daily portfolio snapshots, the contributions ledger, milestones, alerts and the audit log. An in-memory store is used for development; setting
+// DATA_PATH switches to SQLite so state survives restarts.
+package storage
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// ErrNotFound is returned when a requested record does not exist for the user
+var ErrNotFound = errors.New("not found")
+
+// Plan lifecycle states
+const (
+	PlanActive    = "active"
+	PlanPaused    = "paused"
+	PlanCancelled = "cancelled"
+)
+
+// PlanStatuses lists every plan state, in lifecycle order
+var PlanStatuses = []string{PlanActive, PlanPaused, PlanCancelled}
+
+// Plan is an automated investing plan created through start_automated_investing
+type Plan struct {
+	ID             string       `json:"plan_id"`
+	UserID         string       `json:"user_id"`
+	MonthlyAmount  float64      `json:"monthly_amount"`
+	InvestmentType string       `json:"investment_type"`
+	Strategy       string       `json:"strategy"`
+	StartDate      string       `json:"start_date"` // YYYY-MM-DD
+	Status         string       `json:"status"`
+	CreatedAt      time.Time    `json:"created_at"`
+	CancelledAt    *time.Time   `json:"cancelled_at,omitempty"`
+	ResumeDate     string       `json:"resume_date,omitempty"`           // YYYY-MM-DD; paused plans resume automatically on this date
+	SkipOnSpikePct float64      `json:"skip_on_spike_percent,omitempty"` // skip a month when spending is up more than this; 0 never skips
+	History        []PlanChange `json:"history,omitempty"`
+}
+
+// PlanChange is one field edit in a plan's change history
+type PlanChange struct {
+	Time     time.Time `json:"time"`
+	Field    string    `json:"field"`
+	OldValue string    `json:"old_value"`
+	NewValue string    `json:"new_value"`
+}
+
+// Execution states
+const (
+	ExecutionPending   = "pending"
+	ExecutionSucceeded = "succeeded"
+	ExecutionFailed    = "failed"
+	ExecutionSkipped   = "skipped" // not attempted because spending spiked that month
+	ExecutionExpired   = "expired" // stuck pending and expired by support; never attempted again
+)
+
+// Execution is one scheduled run of a plan. There is at most one per plan per
+// period, which is what keeps a restart mid-cycle from investing twice.
+type Execution struct {
+	ID        string    `json:"execution_id"`
+	PlanID    string    `json:"plan_id"`
+	UserID    string    `json:"user_id"`
+	Period    string    `json:"period"` // YYYY-MM
+	Amount    float64   `json:"amount"`
+	Tool      string    `json:"tool"` // Liminal tool invoked, e.g. deposit_savings
+	Status    string    `json:"status"`
+	Attempts  int       `json:"attempts"`
+	LastError string    `json:"last_error,omitempty"`
+	Response  string    `json:"response,omitempty"` // raw executor response data
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// Goal is an investment goal created through create_investment_goal_with_transfer
+type Goal struct {
+	ID                  string    `json:"goal_id"`
+	UserID              string    `json:"user_id"`
+	Name                string    `json:"goal_name"`
+	TargetAmount        float64   `json:"target_amount"`
+	TargetDate          string    `json:"target_date"` // YYYY-MM-DD
+	MonthlyContribution float64   `json:"monthly_contribution"`
+	AnnualIncreasePct   float64   `json:"annual_increase_percent,omitempty"` // yearly raise in the monthly contribution
+	InvestmentType      string    `json:"investment_type"`
+	CreatedAt           time.Time `json:"created_at"`
+}
+
+// Portfolio is a user's investment profile snapshot
+type Portfolio struct {
+	UserID            string    `json:"user_id"`
+	TotalBalance      float64   `json:"total_balance"`
+	SavingsAllocation float64   `json:"savings_allocation"`
+	StockAllocation   float64   `json:"stock_allocation"`
+	RiskTolerance     string    `json:"risk_tolerance"`
+	MonthlySavings    float64   `json:"monthly_savings"`
+	AgeGroup          string    `json:"age_group"`
+	Experience        string    `json:"experience,omitempty"` // investing experience: none, minimal, moderate or extensive
+	Locale            string    `json:"locale,omitempty"`     // language for explanations, e.g. es; empty uses the server's
+	Holdings          []Holding `json:"holdings,omitempty"`   // when present, the source of the user's allocation
+	UpdatedAt         time.Time `json:"updated_at"`
+}
+
+// Holding is one position the user told us they own
+type Holding struct {
+	ID         string    `json:"holding_id"`
+	Identifier string    `json:"identifier"`  // ticker or a short name such as "401k target fund"
+	AssetClass string    `json:"asset_class"` // stocks, international, reit, bonds or cash
+	Quantity   float64   `json:"quantity,omitempty"`
+	Value      float64   `json:"value"`
+	CostBasis  *float64  `json:"cost_basis,omitempty"`
+	UpdatedAt  time.Time `json:"updated_at"`
+}
+
+// Snapshot is a portfolio's total value recorded once a day; days the server
+// was down have no snapshot rather than a guessed one
+type Snapshot struct {
+	UserID     string    `json:"user_id"`
+	Date       string    `json:"date"` // YYYY-MM-DD
+	TotalValue float64   `json:"total_value"`
+	Priced     bool      `json:"priced"` // true when some holdings were valued from live quotes
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+// Contribution sources
+const (
+	ContributionPlan    = "plan"            // an automated plan execution
+	ContributionDeposit = "deposit_savings" // a confirmed deposit_savings call
+	ContributionManual  = "manual"          // reported by the user
+)
+
+// Contribution is money the user put in, as opposed to market growth. ID is the
+// execution or transaction ID it came from, so recording it twice is harmless.
+type Contribution struct {
+	ID        string    `json:"contribution_id"`
+	UserID    string    `json:"user_id"`
+	Date      time.Time `json:"date"`
+	Amount    float64   `json:"amount"`
+	Source    string    `json:"source"`
+	Note      string    `json:"note,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// Milestone is an achievement recorded once per user, e.g. crossing $10,000 contributed
+type Milestone struct {
+	UserID      string     `json:"user_id"`
+	Key         string     `json:"key"` // e.g. contributed_10000 or goal_funded_50:<goal id>
+	Label       string     `json:"label"`
+	AchievedAt  time.Time  `json:"achieved_at"`
+	AnnouncedAt *time.Time `json:"announced_at,omitempty"` // when it was first reported to the user
+}
+
+// LearnedConcept records that a concept was explained to a user, once per user and concept
+type LearnedConcept struct {
+	UserID      string    `json:"user_id"`
+	Concept     string    `json:"concept"`
+	ExplainedAt time.Time `json:"explained_at"` // first time it was explained
+}
+
+// QuizAnswer is one answer to a concept quiz question; every attempt is kept
+type QuizAnswer struct {
+	UserID     string    `json:"user_id"`
+	Concept    string    `json:"concept"`
+	QuestionID string    `json:"question_id"`
+	Choice     int       `json:"choice"` // index into the question's choices
+	Correct    bool      `json:"correct"`
+	AnsweredAt time.Time `json:"answered_at"`
+}
+
+// Alert kinds
+const (
+	AlertAllocationDrift = "allocation_drift" // an asset class is outside its target band
+)
+
+// Alert is a condition worth raising with the user before they ask, e.g. allocation drift.
+// A user has at most one open alert per key; it is cleared when the condition goes away,
+// and a later recurrence opens a new one.
+type Alert struct {
+	ID          string     `json:"alert_id"`
+	UserID      string     `json:"user_id"`
+	Kind        string     `json:"kind"`
+	Key         string     `json:"key"` // what the alert is about, e.g. allocation_drift:stocks
+	Message     string     `json:"message"`
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`             // last time the condition was seen
+	AnnouncedAt *time.Time `json:"announced_at,omitempty"` // when it was first reported to the user
+	ClearedAt   *time.Time `json:"cleared_at,omitempty"`
+}
+
+// AuditEntry records a state change made by a user (via tools) or an operator (via admin)
+type AuditEntry struct {
+	Time   time.Time `json:"time"`
+	UserID string    `json:"user_id"`
+	Actor  string    `json:"actor"` // "user" or "operator:<name>"
+	Action string    `json:"action"`
+	Detail string    `json:"detail,omitempty"`
+}
+
+// Store is the persistence boundary for tool handlers and the admin API.
+// Implementations must be safe for concurrent use. List methods return an
+// empty, non-nil slice when the user has no records.
+type Store interface {
+	SavePlan(ctx context.Context, plan Plan) error // insert or replace by ID
+	GetPlan(ctx context.Context, userID, planID string) (Plan, error)
+	ListPlans(ctx context.Context, userID string) ([]Plan, error)
+	ListAllPlans(ctx context.Context) ([]Plan, error) // every user's plans, for the scheduler
+
+	SaveExecution(ctx context.Context, exec Execution) error // insert or replace by ID
+	GetExecution(ctx context.Context, executionID string) (Execution, error)
+	ListExecutions(ctx context.Context, planID string) ([]Execution, error) // oldest first
+
+	SaveGoal(ctx context.Context, goal Goal) error // insert or replace by ID
+	ListGoals(ctx context.Context, userID string) ([]Goal, error)
+
+	SavePortfolio(ctx context.Context, portfolio Portfolio) error
+	GetPortfolio(ctx context.Context, userID string) (Portfolio, error)
+	ListAllPortfolios(ctx context.Context) ([]Portfolio, error) // every user's portfolio, for snapshots
+
+	SaveSnapshot(ctx context.Context, snapshot Snapshot) error                  // insert or replace by user and date
+	ListSnapshots(ctx context.Context, userID, from string) ([]Snapshot, error) // on or after from (YYYY-MM-DD), oldest first
+
+	SaveContribution(ctx context.Context, c Contribution) (bool, error)           // insert once by ID; reports whether it was new
+	ListContributions(ctx context.Context, userID string) ([]Contribution, error) // oldest first
+
+	SaveMilestone(ctx context.Context, m Milestone) (bool, error)                   // insert once by user and key; reports whether it was new
+	ListMilestones(ctx context.Context, userID string) ([]Milestone, error)         // oldest first
+	MarkMilestonesAnnounced(ctx context.Context, userID string, at time.Time) error // stamps every milestone not yet announced
+
+	RaiseAlert(ctx context.Context, a Alert) (bool, error)                          // opens a, or refreshes the open alert with its key; reports whether it was opened
+	ClearAlert(ctx context.Context, userID, key string, at time.Time) (bool, error) // closes the open alert with key; reports whether there was one
+	ListAlerts(ctx context.Context, userID string) ([]Alert, error)                 // open and cleared, oldest first
+	MarkAlertsAnnounced(ctx context.Context, userID string, at time.Time) error     // stamps every open alert not yet announced
+
+	SaveLearnedConcept(ctx context.Context, c LearnedConcept) (bool, error)           // insert once by user and concept; reports whether it was new
+	ListLearnedConcepts(ctx context.Context, userID string) ([]LearnedConcept, error) // oldest first
+
+	SaveQuizAnswer(ctx context.Context, a QuizAnswer) error                   // appends; answers are never replaced
+	ListQuizAnswers(ctx context.Context, userID string) ([]QuizAnswer, error) // oldest first
+
+	SetReadOnly(ctx context.Context, userID string, readOnly bool) error
+	IsReadOnly(ctx context.Context, userID string) (bool, error)
+
+	AppendAudit(ctx context.Context, entry AuditEntry) error
+	ListAudit(ctx context.Context, userID string) ([]AuditEntry, error)
+
+	Close() error
+}
+
+// Open returns an SQLite store at dataPath, or an in-memory store when dataPath is empty
+func Open(dataPath string) (Store, error) {
+	if dataPath == "" {
+		return NewMemory(), nil
+	}
+	return OpenSQLite(dataPath)
+}
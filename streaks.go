@@ -0,0 +1,177 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/becomeliminal/nim-go-sdk/core"
+	"github.com/becomeliminal/nim-go-sdk/tools"
+
+	"vibe-invest/storage"
+)
+
+// ============================================
+// SAVINGS STREAKS
+// ============================================
+// A streak counts consecutive calendar months in which the user invested: a
+// successful plan execution or a confirmed deposit_savings call, as recorded in
+// the contributions ledger. Self-reported contributions don't count. A month
+// without one:
+//   - freezes the streak (it neither grows nor breaks) when one of the user's
+//     plans was paused during that month or skipped it for a spending spike
+//   - breaks it otherwise, back to zero
+//
+// The current month only counts once something is invested; until it ends it
+// can't break the streak. Streaks are worked out from the ledger and plan
+// records on every read, so a retried execution never counts twice.
+
+// streakMilestones are the streak lengths, in months, worth celebrating
+var streakMilestones = []int{3, 6, 12, 24, 36, 60, 120}
+
+// Streak states
+const (
+	streakNone   = "none"   // nothing invested yet
+	streakActive = "active" // the last month that ended (or this one) was invested in
+	streakFrozen = "frozen" // the streak is held by paused or skipped months
+	streakBroken = "broken" // the last month that ended had no investment
+)
+
+// streakSources describes what counts toward a streak
+const streakSources = "plan executions and confirmed deposit_savings calls"
+
+// newSavingsStreakTool reports how many months in a row the user has invested
+func newSavingsStreakTool() core.Tool {
+	return tools.New("get_savings_streak").
+		Description("Get the user's savings streak: months in a row they have invested through a plan execution or a confirmed savings deposit, their longest streak, total months invested and the next milestone. Paused plans and spending-spike skips freeze the streak; other missed months break it. Use it to celebrate consistency (\"6 months in a row!\")").
+		Schema(tools.ObjectSchema(map[string]interface{}{})).
+		Handler(func(ctx context.Context, toolParams *core.ToolParams) (*core.ToolResult, error) {
+			streak, err := loadSavingsStreak(ctx, toolParams.UserID, time.Now().UTC())
+			if err != nil {
+				return &core.ToolResult{Success: false, Error: err.Error()}, nil
+			}
+			return &core.ToolResult{Success: true, Data: streak}, nil
+		}).
+		Build()
+}
+
+// loadSavingsStreak reads the user's ledger, plans and executions and works out their streak
+func loadSavingsStreak(ctx context.Context, userID string, now time.Time) (SavingsStreak, error) {
+	contributions, err := store.ListContributions(ctx, userKey(userID))
+	if err != nil {
+		return SavingsStreak{}, fmt.Errorf("could not load contributions: %v", err)
+	}
+	plans, err := listPlans(ctx, userKey(userID))
+	if err != nil {
+		return SavingsStreak{}, fmt.Errorf("could not load plans: %v", err)
+	}
+	var executions []storage.Execution
+	for _, p := range plans {
+		execs, err := store.ListExecutions(ctx, p.ID)
+		if err != nil {
+			return SavingsStreak{}, fmt.Errorf("could not load executions for %s: %v", p.ID, err)
+		}
+		executions = append(executions, execs...)
+	}
+	return savingsStreak(contributions, plans, executions, now), nil
+}
+
+// savingsStreak walks the months from the first investment to now's month
+func savingsStreak(contributions []storage.Contribution, plans []storage.Plan, executions []storage.Execution, now time.Time) SavingsStreak {
+	s := SavingsStreak{Status: streakNone, CountedSources: streakSources}
+	invested := map[string]bool{}
+	var first time.Time
+	for _, c := range contributions {
+		if c.Source != storage.ContributionPlan && c.Source != storage.ContributionDeposit {
+			continue
+		}
+		invested[c.Date.UTC().Format("2006-01")] = true
+		if first.IsZero() || c.Date.Before(first) {
+			first = c.Date.UTC()
+		}
+	}
+	skipped := map[string]bool{}
+	for _, e := range executions {
+		if e.Status == storage.ExecutionSkipped {
+			skipped[e.Period] = true
+		}
+	}
+	if first.IsZero() {
+		s.NextMilestone = streakMilestones[0]
+		s.Message = "No investments recorded yet - the first plan execution or savings deposit starts the streak."
+		return s
+	}
+
+	thisMonth := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
+	for month := time.Date(first.Year(), first.Month(), 1, 0, 0, 0, 0, time.UTC); !month.After(thisMonth); month = month.AddDate(0, 1, 0) {
+		key := month.Format("2006-01")
+		switch {
+		case invested[key]:
+			s.CurrentStreak++
+			s.TotalMonthsInvested++
+			s.LongestStreak = max(s.LongestStreak, s.CurrentStreak)
+			s.LastInvestedMonth = key
+			s.Status = streakActive
+		case month.Equal(thisMonth):
+			// Still time to invest this month
+		case skipped[key] || pausedDuring(plans, month, month.AddDate(0, 1, 0)):
+			s.FrozenMonths++
+			s.Status = streakFrozen
+		default:
+			s.CurrentStreak = 0
+			s.Status = streakBroken
+		}
+	}
+	s.InvestedThisMonth = invested[thisMonth.Format("2006-01")]
+
+	for _, m := range streakMilestones {
+		if m > s.CurrentStreak {
+			s.NextMilestone = m
+			break
+		}
+	}
+	if slices.Contains(streakMilestones, s.CurrentStreak) && s.InvestedThisMonth {
+		s.MilestoneReached = s.CurrentStreak
+	}
+
+	switch s.Status {
+	case streakBroken:
+		s.Message = fmt.Sprintf("The streak ended after a month without investing; the longest so far is %d month(s). Investing this month starts a new one.", s.LongestStreak)
+	case streakFrozen:
+		s.Message = fmt.Sprintf("%d month(s) in a row, held while plans were paused or skipped. Investing again picks it back up.", s.CurrentStreak)
+	default:
+		s.Message = fmt.Sprintf("%d month(s) in a row!", s.CurrentStreak)
+		if !s.InvestedThisMonth {
+			s.Message += " Invest this month to keep it going."
+		}
+	}
+	if s.NextMilestone > 0 {
+		s.Message += fmt.Sprintf(" Next milestone: %d months.", s.NextMilestone)
+	}
+	return s
+}
+
+// pausedDuring reports whether any of plans was paused at some point in [from, to). Plans are
+// created active, so their status history replays from there.
+func pausedDuring(plans []storage.Plan, from, to time.Time) bool {
+	for _, p := range plans {
+		status := storage.PlanActive
+		for _, change := range p.History {
+			if change.Field != "status" {
+				continue
+			}
+			if change.Time.Before(from) {
+				status = change.NewValue
+				continue
+			}
+			if change.Time.Before(to) && change.NewValue == storage.PlanPaused {
+				return true
+			}
+		}
+		if status == storage.PlanPaused {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,233 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+
+	"github.com/becomeliminal/nim-go-sdk/core"
+	"github.com/becomeliminal/nim-go-sdk/tools"
+)
+
+// ============================================
+// MARKET CRASH STRESS TEST
+// ============================================
+// The crash hits the equity share of the portfolio today. Equities then climb
+// back to their pre-crash price over recovery_years and grow at the expected
+// return afterwards; the non-equity share keeps earning the vault rate. The two
+// behaviours differ only in contributions: one keeps investing through the
+// recovery, the other pauses until prices have recovered and then resumes.
+
+// crashScenario is a named drawdown and how long prices took to get back
+type crashScenario struct {
+	DrawdownPct   float64
+	RecoveryYears float64
+	Label         string
+}
+
+// crashScenarios are rounded from US stock market history (price peak to trough, trough to prior peak)
+var crashScenarios = map[string]crashScenario{
+	"2008":    {DrawdownPct: 57, RecoveryYears: 4, Label: "2008 financial crisis"},
+	"dotcom":  {DrawdownPct: 49, RecoveryYears: 5, Label: "2000-2002 dot-com crash"},
+	"covid":   {DrawdownPct: 34, RecoveryYears: 0.5, Label: "2020 COVID crash"},
+	"1987":    {DrawdownPct: 34, RecoveryYears: 2, Label: "1987 Black Monday"},
+	"typical": {DrawdownPct: 20, RecoveryYears: 1, Label: "typical bear market"},
+}
+
+// Stress test defaults and bounds
+const (
+	defaultStressScenario     = "2008"
+	defaultStressHorizonYears = 10.0
+	maxStressDrawdownPct      = 95.0
+	maxStressRecoveryYears    = 20.0
+)
+
+// newStressTestTool shows what a crash does to a plan with and without continued contributions
+func newStressTestTool() core.Tool {
+	names := make([]string, 0, len(crashScenarios))
+	for name := range crashScenarios {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return tools.New("market_crash_stress_test").
+		Description("Stress-test a portfolio against a market crash (e.g. a 2008-style drop): the immediate loss on the equity share, then the recovery path if the user keeps investing every month versus pausing contributions until prices recover, and the difference in dollars between the two").
+		Schema(tools.ObjectSchema(map[string]interface{}{
+			"portfolio_value":         tools.StringProperty("Current portfolio value in the account currency"),
+			"stock_percent":           tools.StringProperty("Optional percentage of the portfolio in stocks (US, international and REITs); omit to use the user's profile"),
+			"monthly_contribution":    tools.StringProperty("Optional amount invested each month in the account currency; omit to use the user's profile"),
+			"scenario":                tools.StringProperty(fmt.Sprintf("Optional historical crash to replay: %s (default %s)", strings.Join(names, ", "), defaultStressScenario)),
+			"equity_drawdown_percent": tools.StringProperty("Optional custom drop in stock prices percentage; overrides the scenario's"),
+			"recovery_years":          tools.StringProperty("Optional custom years for stock prices to climb back to the pre-crash level; overrides the scenario's"),
+			"horizon_years":           tools.StringProperty(fmt.Sprintf("Optional years to project (default %.0f, and at least the recovery period)", defaultStressHorizonYears)),
+			"expected_return":         tools.StringProperty(fmt.Sprintf("Optional expected annual stock return after the recovery percentage (defaults to the server's assumption, currently %g)", appConfig.Assumptions.EquityReturnPct)),
+		}, "portfolio_value")).
+		Handler(func(ctx context.Context, toolParams *core.ToolParams) (*core.ToolResult, error) {
+			var params struct {
+				PortfolioValue        string `json:"portfolio_value"`
+				StockPercent          string `json:"stock_percent"`
+				MonthlyContribution   string `json:"monthly_contribution"`
+				Scenario              string `json:"scenario"`
+				EquityDrawdownPercent string `json:"equity_drawdown_percent"`
+				RecoveryYears         string `json:"recovery_years"`
+				HorizonYears          string `json:"horizon_years"`
+				ExpectedReturn        string `json:"expected_return"`
+			}
+			if err := json.Unmarshal(toolParams.Input, &params); err != nil {
+				return &core.ToolResult{Success: false, Error: fmt.Sprintf("invalid input: %v", err)}, nil
+			}
+
+			var v amountValidator
+			value := v.positive("portfolio_value", params.PortfolioValue)
+			stockPct := optionalPercent(&v, "stock_percent", params.StockPercent, 0, 100)
+			monthly := v.nonNegative("monthly_contribution", params.MonthlyContribution, false)
+			scenarioName := defaultStressScenario
+			if strings.TrimSpace(params.Scenario) != "" {
+				scenarioName = v.oneOf("scenario", params.Scenario, names)
+			}
+			scenario := crashScenarios[scenarioName]
+			scenario.DrawdownPct = optionalPercent(&v, "equity_drawdown_percent", params.EquityDrawdownPercent, scenario.DrawdownPct, maxStressDrawdownPct)
+			if strings.TrimSpace(params.RecoveryYears) != "" {
+				scenario.RecoveryYears = v.years("recovery_years", params.RecoveryYears, 0, maxStressRecoveryYears)
+			}
+			if strings.TrimSpace(params.RecoveryYears) != "" || strings.TrimSpace(params.EquityDrawdownPercent) != "" {
+				scenarioName, scenario.Label = "custom", "custom crash"
+			}
+			horizon := max(defaultStressHorizonYears, scenario.RecoveryYears)
+			if strings.TrimSpace(params.HorizonYears) != "" {
+				horizon = v.years("horizon_years", params.HorizonYears, minProjectionYears, maxProjectionYears)
+			}
+			returnRate := v.returnRate("expected_return", params.ExpectedReturn, false)
+			if len(v.errs) == 0 && horizon < scenario.RecoveryYears {
+				v.fail("horizon_years", "%g years is shorter than the %g-year recovery", horizon, scenario.RecoveryYears)
+			}
+			if err := v.err(); err != nil {
+				return &core.ToolResult{Success: false, Error: err.Error()}, nil
+			}
+
+			allocationSource, contributionSource := "user_provided", "user_provided"
+			profileStock := strings.TrimSpace(params.StockPercent) == ""
+			if profileStock || strings.TrimSpace(params.MonthlyContribution) == "" {
+				portfolio, err := loadPortfolio(ctx, toolParams.UserID)
+				if err != nil {
+					var missing []string
+					if profileStock {
+						missing = append(missing, "stock_percent")
+					}
+					if strings.TrimSpace(params.MonthlyContribution) == "" {
+						missing = append(missing, "monthly_contribution")
+					}
+					return &core.ToolResult{Success: false, Error: profileFieldError(err, missing...).Error()}, nil
+				}
+				if profileStock {
+					allocationSource = "profile"
+					if portfolio.TotalBalance > 0 {
+						stockPct = portfolio.StockAllocation / portfolio.TotalBalance * 100
+					}
+				}
+				if strings.TrimSpace(params.MonthlyContribution) == "" {
+					monthly, contributionSource = portfolio.MonthlySavings, "profile"
+				}
+			}
+
+			vaultRates.refresh(ctx, toolParams.UserID) // the non-equity share earns the live vault APY when available
+			result := stressTest(value, stockPct, monthly, scenario, horizon, returnRate)
+			result.Scenario = scenarioName
+			result.AllocationSource = allocationSource
+			result.ContributionSource = contributionSource
+			return &core.ToolResult{Success: true, Data: result}, nil
+		}).
+		Build()
+}
+
+// stressTest crashes the equity share today and projects both contribution behaviours month by month
+func stressTest(value, stockPct, monthly float64, scenario crashScenario, horizon, returnRate float64) StressTestResult {
+	cashAPY, live := vaultRates.current()
+	months := int(math.Round(horizon * 12))
+	recoveryMonths := int(math.Round(scenario.RecoveryYears * 12))
+	share := stockPct / 100
+	drop := scenario.DrawdownPct / 100
+
+	// Monthly equity growth: back to the pre-crash price by the end of the recovery, then the expected return
+	recoveryGrowth := 1.0
+	if recoveryMonths > 0 && drop < 1 {
+		recoveryGrowth = math.Pow(1/(1-drop), 1/float64(recoveryMonths))
+	}
+	normalGrowth := 1 + returnRate/100/12
+	cashGrowth := 1 + cashAPY/100/12
+
+	simulate := func(pauseDuringRecovery bool) StressPath {
+		equity := value * share * (1 - drop)
+		other := value * (1 - share)
+		p := StressPath{RecoveredMonth: -1}
+		if equity+other >= value {
+			p.RecoveredMonth = 0
+		}
+		for m := 1; m <= months; m++ {
+			if m <= recoveryMonths {
+				equity *= recoveryGrowth
+			} else {
+				equity *= normalGrowth
+			}
+			other *= cashGrowth
+			if !pauseDuringRecovery || m > recoveryMonths {
+				equity += monthly * share
+				other += monthly * (1 - share)
+				p.ContributedUSD += monthly
+			}
+			total := equity + other
+			if p.RecoveredMonth < 0 && total >= value {
+				p.RecoveredMonth = m
+			}
+			if m == recoveryMonths {
+				p.EndOfRecoveryUSD = total
+			}
+			if m%12 == 0 || m == months {
+				p.Path = append(p.Path, StressPoint{Month: m, ValueUSD: total})
+			}
+		}
+		p.FinalValueUSD = equity + other
+		if recoveryMonths == 0 {
+			p.EndOfRecoveryUSD = value * (1 - share*drop)
+		}
+		return p
+	}
+
+	r := StressTestResult{
+		Currency:              activeCurrency().Code,
+		ScenarioLabel:         scenario.Label,
+		PortfolioValueUSD:     value,
+		StockPercent:          stockPct,
+		MonthlyContribution:   monthly,
+		EquityDrawdownPercent: scenario.DrawdownPct,
+		RecoveryYears:         scenario.RecoveryYears,
+		HorizonYears:          horizon,
+		ExpectedReturnPercent: returnRate,
+		CashAPY:               cashAPY,
+		CashRateSource:        rateSource(live),
+		CrashLossUSD:          value * share * drop,
+		ValueAfterCrashUSD:    value * (1 - share*drop),
+		KeepInvesting:         simulate(false),
+		PauseContributions:    simulate(true),
+	}
+	r.KeepInvesting.Behavior = "Keep investing every month through the recovery"
+	r.PauseContributions.Behavior = "Pause contributions until stock prices recover, then resume"
+	r.DifferenceUSD = r.KeepInvesting.FinalValueUSD - r.PauseContributions.FinalValueUSD
+	r.SkippedContributionsUSD = r.KeepInvesting.ContributedUSD - r.PauseContributions.ContributedUSD
+	r.RecoveryBonusUSD = r.DifferenceUSD - r.SkippedContributionsUSD
+
+	r.Message = fmt.Sprintf("A %s (stocks down %g%%) would cut this portfolio from %s to %s right away. ",
+		scenario.Label, scenario.DrawdownPct, formatWholeMoney(value), formatWholeMoney(r.ValueAfterCrashUSD))
+	if monthly > 0 && recoveryMonths > 0 {
+		r.Message += fmt.Sprintf("Staying the course ends %g years out with %s versus %s for pausing until prices recover: %s more, of which %s is the skipped contributions and %s is the extra growth from buying while prices were low.",
+			horizon, formatWholeMoney(r.KeepInvesting.FinalValueUSD), formatWholeMoney(r.PauseContributions.FinalValueUSD),
+			formatWholeMoney(r.DifferenceUSD), formatWholeMoney(r.SkippedContributionsUSD), formatWholeMoney(r.RecoveryBonusUSD))
+	} else {
+		r.Message += fmt.Sprintf("With no contributions during the recovery, both behaviours end at %s after %g years; what matters is not selling at the bottom.",
+			formatWholeMoney(r.KeepInvesting.FinalValueUSD), horizon)
+	}
+	return r
+}
@@ -0,0 +1,235 @@
+package main
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/becomeliminal/nim-go-sdk/core"
+	"github.com/becomeliminal/nim-go-sdk/tools"
+)
+
+// ============================================
+// RECURRING SUBSCRIPTIONS
+// ============================================
+// Purchases are grouped by counterparty; a group is a subscription when its
+// charges are similar in size and evenly spaced at one of the cadences below.
+// Amounts may drift within subscriptionAmountTolerance of the group's median so
+// price increases don't hide a subscription. One skipped charge (a gap of two
+// periods) is allowed, but a group whose last charge is more than
+// subscriptionLapsePeriods periods old has been cancelled and is left out.
+// Large regular payments (rent, loan installments) look the same, so anything
+// above SUBSCRIPTION_MAX_AMOUNT a charge is left out too.
+
+// Subscription detection bounds
+const (
+	subscriptionAmountTolerance = 0.25 // fraction of the median charge an amount may differ by
+	subscriptionRedirectShare   = 0.5  // share of the monthly total suggested for investing
+	subscriptionProjectionYrs   = 10.0
+	subscriptionLapsePeriods    = 2 // missed periods after which a subscription counts as cancelled
+)
+
+// subscriptionCadences are the billing intervals recognized, with the slack in days each gap may
+// differ by and the fewest charges needed before a group counts
+var subscriptionCadences = []struct {
+	name       string
+	days       float64
+	slack      float64
+	minCharges int
+	perMonth   float64 // charges per month
+}{
+	{"weekly", 7, 2, 4, 52.0 / 12},
+	{"monthly", 30.4, 4, 3, 1},
+	{"quarterly", 91.3, 10, 2, 1.0 / 3},
+	{"annual", 365.25, 20, 2, 1.0 / 12},
+}
+
+// subscriptionCounterpartyKeys are the transaction fields tried, in order, for who was paid
+var subscriptionCounterpartyKeys = []string{"merchant", "counterparty", "recipient", "description", "note"}
+
+// newSubscriptionsTool finds recurring charges in the user's transactions
+func newSubscriptionsTool(liminalExecutor core.ToolExecutor) core.Tool {
+	return tools.New("detect_recurring_subscriptions").
+		Description("Find the user's recurring subscriptions in their real transactions (same merchant, similar amount, regular cadence), total their monthly cost, and project what investing half of it would grow to over 10 years").
+		Schema(tools.ObjectSchema(map[string]interface{}{
+			"expected_return": tools.StringProperty(fmt.Sprintf("Optional assumed annual return percentage for the projection (defaults to the server's assumption, currently %g)", appConfig.Assumptions.EquityReturnPct)),
+		})).
+		Handler(func(ctx context.Context, toolParams *core.ToolParams) (*core.ToolResult, error) {
+			var params struct {
+				ExpectedReturn string `json:"expected_return"`
+			}
+			if len(toolParams.Input) > 0 {
+				if err := json.Unmarshal(toolParams.Input, &params); err != nil {
+					return &core.ToolResult{Success: false, Error: fmt.Sprintf("invalid input: %v", err)}, nil
+				}
+			}
+
+			var v amountValidator
+			returnRate := v.returnRate("expected_return", params.ExpectedReturn, false)
+			if err := v.err(); err != nil {
+				return &core.ToolResult{Success: false, Error: err.Error()}, nil
+			}
+
+			txs, ok := fetchTransactions(ctx, liminalExecutor, toolParams.UserID, spendingPageSize)
+			if !ok {
+				return &core.ToolResult{Success: false, Error: "could not load transaction history"}, nil
+			}
+			return &core.ToolResult{Success: true, Data: detectSubscriptions(txs, returnRate, appConfig.SubscriptionMax, time.Now())}, nil
+		}).
+		Build()
+}
+
+// subscriptionCharge is one purchase considered for a subscription
+type subscriptionCharge struct {
+	at     time.Time
+	amount float64
+}
+
+// detectSubscriptions groups purchases by counterparty and keeps the groups that recur and are
+// still being charged at now
+func detectSubscriptions(txs []map[string]interface{}, returnRate, maxCharge float64, now time.Time) SubscriptionsResult {
+	r := SubscriptionsResult{
+		Currency:              activeCurrency().Code,
+		TransactionsAnalyzed:  len(txs),
+		Subscriptions:         []Subscription{},
+		ExpectedReturnPercent: returnRate,
+	}
+	groups := map[string][]subscriptionCharge{}
+	names := map[string]string{}
+	for _, tx := range txs {
+		if !isRoundUpPurchase(tx) {
+			continue
+		}
+		at, ok := transactionTime(tx)
+		name := subscriptionCounterparty(tx)
+		if !ok || name == "" {
+			continue
+		}
+		key := strings.ToLower(name)
+		groups[key] = append(groups[key], subscriptionCharge{at: at, amount: math.Abs(transactionAmount(tx))})
+		names[key] = name
+	}
+
+	for key, charges := range groups {
+		s, ok := recurringCharge(charges)
+		if !ok {
+			continue
+		}
+		if lapsed(s, now) {
+			r.Lapsed++
+			continue
+		}
+		if s.AmountUSD > maxCharge {
+			r.SkippedLargeCharges++
+			continue
+		}
+		s.Counterparty = names[key]
+		r.Subscriptions = append(r.Subscriptions, s)
+		r.MonthlyTotalUSD += s.MonthlyUSD
+	}
+	slices.SortFunc(r.Subscriptions, func(a, b Subscription) int {
+		if c := cmp.Compare(b.MonthlyUSD, a.MonthlyUSD); c != 0 {
+			return c
+		}
+		return strings.Compare(a.Counterparty, b.Counterparty)
+	})
+	r.Count = len(r.Subscriptions)
+	r.AnnualTotalUSD = r.MonthlyTotalUSD * 12
+
+	if r.Count == 0 {
+		r.Message = "No recurring subscriptions were found in recent transactions."
+		return r
+	}
+	r.RedirectMonthlyUSD = r.MonthlyTotalUSD * subscriptionRedirectShare
+	projection := calculateCompoundGrowth(0, r.RedirectMonthlyUSD, returnRate, subscriptionProjectionYrs)
+	r.TenYearProjection = &projection
+	r.Message = fmt.Sprintf("You're paying for %d subscription(s) totaling %s/month (%s a year). Cutting half of that and investing the %s/month at %.1f%% would grow to %s in %.0f years.",
+		r.Count, formatMoney(r.MonthlyTotalUSD), formatWholeMoney(r.AnnualTotalUSD), formatMoney(r.RedirectMonthlyUSD), returnRate, projection.ProjectedTotal, subscriptionProjectionYrs)
+	return r
+}
+
+// recurringCharge reports whether charges are similar in size and evenly spaced at a known cadence.
+// A single gap of two periods, one skipped or failed charge, doesn't break the spacing.
+func recurringCharge(charges []subscriptionCharge) (Subscription, bool) {
+	slices.SortFunc(charges, func(a, b subscriptionCharge) int { return a.at.Compare(b.at) })
+	amounts := make([]float64, len(charges))
+	for i, c := range charges {
+		amounts[i] = c.amount
+	}
+	typical := median(amounts)
+	if typical <= 0 {
+		return Subscription{}, false
+	}
+	for _, a := range amounts {
+		if math.Abs(a-typical)/typical > subscriptionAmountTolerance {
+			return Subscription{}, false
+		}
+	}
+
+	gaps := make([]float64, 0, len(charges)-1)
+	for i := 1; i < len(charges); i++ {
+		gaps = append(gaps, charges[i].at.Sub(charges[i-1].at).Hours()/24)
+	}
+	for _, cadence := range subscriptionCadences {
+		if len(charges) < cadence.minCharges {
+			continue
+		}
+		regular, skipped := true, false
+		for _, gap := range gaps {
+			switch {
+			case math.Abs(gap-cadence.days) <= cadence.slack:
+			case !skipped && math.Abs(gap-2*cadence.days) <= 2*cadence.slack:
+				skipped = true
+			default:
+				regular = false
+			}
+			if !regular {
+				break
+			}
+		}
+		if !regular {
+			continue
+		}
+		last := charges[len(charges)-1]
+		return Subscription{
+			AmountUSD:    last.amount,
+			Cadence:      cadence.name,
+			MonthlyUSD:   last.amount * cadence.perMonth,
+			Charges:      len(charges),
+			LastCharged:  last.at.Format("2006-01-02"),
+			NextExpected: last.at.Add(time.Duration(cadence.days * 24 * float64(time.Hour))).Format("2006-01-02"),
+			PriceChanged: last.amount != charges[0].amount,
+		}, true
+	}
+	return Subscription{}, false
+}
+
+// lapsed reports whether s has gone more than subscriptionLapsePeriods periods without a charge by now
+func lapsed(s Subscription, now time.Time) bool {
+	last, err := time.Parse("2006-01-02", s.LastCharged)
+	if err != nil {
+		return false
+	}
+	for _, cadence := range subscriptionCadences {
+		if cadence.name == s.Cadence {
+			overdue := subscriptionLapsePeriods*cadence.days + cadence.slack
+			return now.Sub(last).Hours()/24 > overdue
+		}
+	}
+	return false
+}
+
+// subscriptionCounterparty is who a purchase paid, with whitespace collapsed
+func subscriptionCounterparty(tx map[string]interface{}) string {
+	for _, key := range subscriptionCounterpartyKeys {
+		if name, _ := tx[key].(string); strings.TrimSpace(name) != "" {
+			return strings.Join(strings.Fields(name), " ")
+		}
+	}
+	return ""
+}
@@ -0,0 +1,46 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+func TestDetectSubscriptionsGapsAndLapses(t *testing.T) {
+	now := time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+	// charges builds a monthly Netflix history from charge dates given as days ago
+	charges := func(daysAgo ...int) []map[string]interface{} {
+		txs := make([]map[string]interface{}, len(daysAgo))
+		for i, d := range daysAgo {
+			txs[i] = map[string]interface{}{"type": "card_payment", "amount": -15.49, "merchant": "Netflix",
+				"created_at": now.AddDate(0, 0, -d).Format(time.RFC3339)}
+		}
+		return txs
+	}
+	tests := []struct {
+		name       string
+		txs        []map[string]interface{}
+		wantFound  bool
+		wantLapsed int
+	}{
+		{name: "every month", txs: charges(5, 35, 66, 96), wantFound: true},
+		{name: "one skipped month", txs: charges(5, 35, 96, 127), wantFound: true},
+		{name: "two skipped months in a row", txs: charges(5, 35, 127, 157)},
+		{name: "two separate skipped months", txs: charges(5, 66, 96, 157)},
+		{name: "one period overdue", txs: charges(40, 70, 101), wantFound: true},
+		{name: "two periods overdue", txs: charges(70, 101, 131), wantLapsed: 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := detectSubscriptions(tt.txs, 7, 200, now)
+			if found := r.Count == 1; found != tt.wantFound {
+				t.Errorf("found = %v, want %v (%+v)", found, tt.wantFound, r.Subscriptions)
+			}
+			if r.Lapsed != tt.wantLapsed {
+				t.Errorf("lapsed = %d, want %d", r.Lapsed, tt.wantLapsed)
+			}
+			if tt.wantFound && (r.Subscriptions[0].Cadence != "monthly" || !approxEqual(r.MonthlyTotalUSD, 15.49)) {
+				t.Errorf("subscription = %+v, want monthly at 15.49", r.Subscriptions[0])
+			}
+		})
+	}
+}
@@ -0,0 +1,75 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/becomeliminal/nim-go-sdk/core"
+)
+
+// ============================================
+// TRANSACTION HISTORY
+// ============================================
+// Helpers for tools that read the user's real transactions through get_transactions.
+// Amounts and timestamps are read leniently since they arrive as loosely typed JSON.
+
+// transactionLookbackDays is the history window used when transactions carry no timestamps
+const transactionLookbackDays = 90
+
+// transactionPageSize is how many transactions a tool asks for by default
+const transactionPageSize = 100
+
+// fetchTransactions returns up to limit of the user's most recent transactions; ok is false when
+// the executor call fails or the response can't be read
+func fetchTransactions(ctx context.Context, liminalExecutor core.ToolExecutor, userID string, limit int) ([]map[string]interface{}, bool) {
+	resp, err := liminalExecutor.Execute(ctx, &core.ExecuteRequest{
+		UserID:    userID,
+		Tool:      "get_transactions",
+		Input:     json.RawMessage(fmt.Sprintf(`{"limit": %d}`, limit)),
+		RequestID: "req_" + generateRandomID(),
+	})
+	if err != nil || !resp.Success {
+		return nil, false
+	}
+	var data struct {
+		Transactions []map[string]interface{} `json:"transactions"`
+	}
+	if err := json.Unmarshal(resp.Data, &data); err != nil {
+		return nil, false
+	}
+	return data.Transactions, true
+}
+
+// transactionAmount reads a transaction's amount, which may be a number or a string
+func transactionAmount(tx map[string]interface{}) float64 {
+	switch a := tx["amount"].(type) {
+	case float64:
+		return a
+	case string:
+		amount, _ := parseAmount(a)
+		return amount
+	}
+	return 0
+}
+
+// transactionTime reads a transaction's created_at; ok is false when missing or malformed
+func transactionTime(tx map[string]interface{}) (time.Time, bool) {
+	raw, ok := tx["created_at"].(string)
+	if !ok {
+		return time.Time{}, false
+	}
+	at, err := time.Parse(time.RFC3339, raw)
+	return at, err == nil
+}
+
+// transactionWindowDays is the span from oldest to now in days, or transactionLookbackDays
+// when the transactions carry no usable timestamps
+func transactionWindowDays(oldest, now time.Time) float64 {
+	days := now.Sub(oldest).Hours() / 24
+	if days < 1 {
+		return transactionLookbackDays
+	}
+	return days
+}
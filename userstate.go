@@ -0,0 +1,93 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"vibe-invest/storage"
+)
+
+// ============================================
+// PER-USER STATE
+// ============================================
+
+// defaultUserID keys state when the SDK gives us no authenticated user
+const defaultUserID = "default"
+
+// store persists plans, goals, portfolios and the audit log.
+// In-memory by default; main swaps in SQLite when DATA_PATH is set.
+var store storage.Store = storage.NewMemory()
+
+func userKey(userID string) string {
+	if userID == "" {
+		return defaultUserID
+	}
+	return userID
+}
+
+// isReadOnly reports whether support has frozen the account
+func isReadOnly(ctx context.Context, userID string) (bool, error) {
+	return store.IsReadOnly(ctx, userKey(userID))
+}
+
+// recordAudit appends an audit entry; a failed write is logged rather than failing the action
+func recordAudit(ctx context.Context, userID, actor, action, detail string) {
+	err := store.AppendAudit(ctx, storage.AuditEntry{
+		Time:   time.Now().UTC(),
+		UserID: userKey(userID),
+		Actor:  actor,
+		Action: action,
+		Detail: detail,
+	})
+	if err != nil {
+		log.Printf("⚠️  Failed to write audit entry %s for %s: %v\n", action, userKey(userID), err)
+	}
+}
+
+// errNoProfile is loadPortfolio's error for a user who hasn't saved a profile
+var errNoProfile = errors.New("profile not set")
+
+// noProfileMessage is the field error for a value that was left to the profile when there is none
+const noProfileMessage = "is required: no investment profile is saved to read it from (complete_onboarding or update_investment_profile saves one)"
+
+// loadPortfolio returns the user's stored portfolio, or errNoProfile when they have none
+func loadPortfolio(ctx context.Context, userID string) (InvestmentPortfolio, error) {
+	p, err := store.GetPortfolio(ctx, userKey(userID))
+	if errors.Is(err, storage.ErrNotFound) {
+		return InvestmentPortfolio{}, errNoProfile
+	}
+	if err != nil {
+		return InvestmentPortfolio{}, err
+	}
+	risk, err := normalizeRiskLevel(p.RiskTolerance)
+	if err != nil {
+		risk = RiskModerate
+	}
+	return InvestmentPortfolio{
+		TotalBalance:      p.TotalBalance,
+		SavingsAllocation: p.SavingsAllocation,
+		StockAllocation:   p.StockAllocation,
+		RiskTolerance:     risk,
+		MonthlySavings:    p.MonthlySavings,
+		AgeGroup:          p.AgeGroup,
+		Experience:        p.Experience,
+		Locale:            p.Locale,
+		Holdings:          p.Holdings,
+	}, nil
+}
+
+// profileFieldError is the error for fields left out to be read from a profile that couldn't be
+// loaded. Without a saved profile they are simply required; nothing is assumed in their place.
+func profileFieldError(err error, fields ...string) error {
+	if !errors.Is(err, errNoProfile) {
+		return fmt.Errorf("could not load profile: %v", err)
+	}
+	var v amountValidator
+	for _, field := range fields {
+		v.fail(field, noProfileMessage)
+	}
+	return v.err()
+}
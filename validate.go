@@ -0,0 +1,192 @@
+package main
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// ============================================
+// SHARED INPUT VALIDATION
+// ============================================
+
+// fieldError names the input that failed so the assistant can ask for just that field
+type fieldError struct {
+	Field   string
+	Message string
+}
+
+func (e *fieldError) Error() string {
+	return fmt.Sprintf("%s: %s", e.Field, e.Message)
+}
+
+// amountValidator collects per-field errors across several inputs. Messages are in locale,
+// or the server's LOCALE when it is empty.
+type amountValidator struct {
+	errs   []error
+	locale string
+}
+
+func (v *amountValidator) fail(field, format string, args ...interface{}) {
+	v.errs = append(v.errs, &fieldError{Field: field, Message: localizef(cmp.Or(v.locale, appConfig.Locale), format, args...)})
+}
+
+// money writes an amount for a message, in the messages' locale
+func (v *amountValidator) money(amount float64) string {
+	return formatMoneyIn(cmp.Or(v.locale, appConfig.Locale), amount)
+}
+
+// parse reads a money field via the parse cache; empty optional fields are zero
+func (v *amountValidator) parse(field, raw string, required bool) (float64, bool) {
+	return v.parseWith(field, raw, required, parseCachedAmount)
+}
+
+// parseRate reads a rate, percentage or years field, where "6.875" is never thousands-grouped
+func (v *amountValidator) parseRate(field, raw string, required bool) (float64, bool) {
+	return v.parseWith(field, raw, required, parseCachedRate)
+}
+
+func (v *amountValidator) parseWith(field, raw string, required bool, parse func(string) (float64, error)) (float64, bool) {
+	if strings.TrimSpace(raw) == "" {
+		if required {
+			v.fail(field, "is required")
+			return 0, false
+		}
+		return 0, true
+	}
+	n, err := parse(raw)
+	if err != nil {
+		v.fail(field, "%q is not a number", raw)
+		return 0, false
+	}
+	return n, true
+}
+
+// positive parses a field that must be greater than zero
+func (v *amountValidator) positive(field, raw string) float64 {
+	n, ok := v.parse(field, raw, true)
+	if ok && n <= 0 {
+		v.fail(field, "must be greater than zero (got %v)", n)
+	}
+	return n
+}
+
+// positiveRate is positive for a rate or percentage field
+func (v *amountValidator) positiveRate(field, raw string) float64 {
+	n, ok := v.parseRate(field, raw, true)
+	if ok && n <= 0 {
+		v.fail(field, "must be greater than zero (got %v)", n)
+	}
+	return n
+}
+
+// nonNegative parses a field that may be zero but not negative
+func (v *amountValidator) nonNegative(field, raw string, required bool) float64 {
+	n, ok := v.parse(field, raw, required)
+	if ok && n < 0 {
+		v.fail(field, "cannot be negative (got %v)", n)
+	}
+	return n
+}
+
+// yearUnitSuffixes are dropped so "10 years" parses like "10"
+var yearUnitSuffixes = []string{"years", "year", "yrs", "yr", "y"}
+
+// years parses a required duration in years and checks it falls within [lo, hi]
+func (v *amountValidator) years(field, raw string, lo, hi float64) float64 {
+	trimmed := strings.ToLower(strings.TrimSpace(raw))
+	for _, suffix := range yearUnitSuffixes {
+		if strings.HasSuffix(trimmed, suffix) {
+			trimmed = strings.TrimSpace(strings.TrimSuffix(trimmed, suffix))
+			break
+		}
+	}
+	if trimmed == "" && strings.TrimSpace(raw) != "" {
+		v.fail(field, "%q is not a number of years", raw)
+		return 0
+	}
+	n, ok := v.parseRate(field, trimmed, true)
+	if ok && (n < lo || n > hi) {
+		v.fail(field, "must be between %v and %v years (got %v)", lo, hi, n)
+	}
+	return n
+}
+
+// returnRate parses an annual return %; an omitted optional rate uses the configured assumption
+func (v *amountValidator) returnRate(field, raw string, required bool) float64 {
+	if !required && strings.TrimSpace(raw) == "" {
+		return appConfig.Assumptions.EquityReturnPct
+	}
+	n, ok := v.parseRate(field, raw, true)
+	if ok && (n < minReturnRate || n > maxReturnRate) {
+		v.fail(field, "must be between %.0f%% and %.0f%% (got %v%%)", minReturnRate, maxReturnRate, n)
+	}
+	return n
+}
+
+// inflationRate parses an optional annual inflation %; omitted uses the configured assumption
+func (v *amountValidator) inflationRate(field, raw string) float64 {
+	if strings.TrimSpace(raw) == "" {
+		return appConfig.Assumptions.InflationPct
+	}
+	n, ok := v.parseRate(field, raw, true)
+	if ok && (n < minInflationRate || n > maxInflationRate) {
+		v.fail(field, "must be between %.0f%% and %.0f%% (got %v%%)", minInflationRate, maxInflationRate, n)
+	}
+	return n
+}
+
+// taxRate parses a required marginal tax rate %
+func (v *amountValidator) taxRate(field, raw string) float64 {
+	n, ok := v.parseRate(field, raw, true)
+	if ok && (n < 0 || n > maxTaxRatePct) {
+		v.fail(field, "must be between 0%% and %.0f%% (got %v%%)", maxTaxRatePct, n)
+	}
+	return n
+}
+
+// feeRate parses a required annual fee %
+func (v *amountValidator) feeRate(field, raw string) float64 {
+	n, ok := v.parseRate(field, raw, true)
+	if ok && (n < 0 || n > maxFeePct) {
+		v.fail(field, "must be between 0%% and %.0f%% (got %v%%)", maxFeePct, n)
+	}
+	return n
+}
+
+// percent parses a percentage within [0, hi]
+func (v *amountValidator) percent(field, raw string, required bool, hi float64) float64 {
+	n, ok := v.parseRate(field, raw, required)
+	if ok && (n < 0 || n > hi) {
+		v.fail(field, "must be between 0%% and %.0f%% (got %v%%)", hi, n)
+	}
+	return n
+}
+
+// riskLevel parses a field naming a risk level in any spelling normalizeRiskLevel accepts
+func (v *amountValidator) riskLevel(field, raw string) RiskLevel {
+	level, err := normalizeRiskLevel(raw)
+	if err != nil {
+		v.fail(field, "%q must be one of %s", raw, strings.Join(riskLevelKeys(), ", "))
+	}
+	return level
+}
+
+// oneOf normalizes a field and checks it against an allowed set
+func (v *amountValidator) oneOf(field, raw string, allowed []string) string {
+	value := strings.ToLower(strings.TrimSpace(raw))
+	if !slices.Contains(allowed, value) {
+		v.fail(field, "%q must be one of %s", raw, strings.Join(allowed, ", "))
+	}
+	return value
+}
+
+// err returns every collected field error, or nil
+func (v *amountValidator) err() error {
+	if len(v.errs) == 0 {
+		return nil
+	}
+	return fmt.Errorf(localize(cmp.Or(v.locale, appConfig.Locale), "invalid input: %w"), errors.Join(v.errs...))
+}
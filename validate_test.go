@@ -0,0 +1,164 @@
+package main
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestAmountValidator(t *testing.T) {
+	tests := []struct {
+		name    string
+		check   func(v *amountValidator, raw string) float64
+		raw     string
+		want    float64
+		wantErr string // the field error, "" when the input is valid
+	}{
+		{name: "positive: amount", check: positiveAmount, raw: "1500", want: 1500},
+		{name: "positive: formatted amount", check: positiveAmount, raw: "$1,500", want: 1500},
+		{name: "positive: zero", check: positiveAmount, raw: "0", wantErr: "amount: must be greater than zero (got 0)"},
+		{name: "positive: negative", check: positiveAmount, raw: "-50", wantErr: "amount: must be greater than zero (got -50)"},
+		{name: "positive: not a number", check: positiveAmount, raw: "lots", wantErr: `amount: "lots" is not a number`},
+		{name: "positive: empty", check: positiveAmount, raw: "", wantErr: "amount: is required"},
+
+		{name: "nonNegative: zero", check: requiredNonNegative, raw: "0", want: 0},
+		{name: "nonNegative: amount", check: requiredNonNegative, raw: "250.75", want: 250.75},
+		{name: "nonNegative: negative", check: requiredNonNegative, raw: "-0.01", wantErr: "amount: cannot be negative (got -0.01)"},
+		{name: "nonNegative: not a number", check: requiredNonNegative, raw: "NaN-ish", wantErr: `amount: "NaN-ish" is not a number`},
+		{name: "nonNegative: empty required", check: requiredNonNegative, raw: " ", wantErr: "amount: is required"},
+		{name: "nonNegative: empty optional", check: optionalNonNegative, raw: "", want: 0},
+
+		{name: "returnRate: percent", check: requiredReturnRate, raw: "7%", want: 7},
+		{name: "returnRate: zero", check: requiredReturnRate, raw: "0", want: 0},
+		{name: "returnRate: negative within range", check: requiredReturnRate, raw: "-10", want: -10},
+		{name: "returnRate: too negative", check: requiredReturnRate, raw: "-60", wantErr: "return: must be between -50% and 50% (got -60%)"},
+		{name: "returnRate: too high", check: requiredReturnRate, raw: "75", wantErr: "return: must be between -50% and 50% (got 75%)"},
+		{name: "returnRate: not a number", check: requiredReturnRate, raw: "high", wantErr: `return: "high" is not a number`},
+		{name: "returnRate: empty required", check: requiredReturnRate, raw: "", wantErr: "return: is required"},
+		{name: "returnRate: empty optional uses assumption", check: optionalReturnRate, raw: "", want: appConfig.Assumptions.EquityReturnPct},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var v amountValidator
+			got := tt.check(&v, tt.raw)
+			err := v.err()
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				if got != tt.want {
+					t.Errorf("got %v, want %v", got, tt.want)
+				}
+				return
+			}
+
+			var fe *fieldError
+			if !errors.As(err, &fe) {
+				t.Fatalf("error %v is not a field error", err)
+			}
+			if fe.Error() != tt.wantErr {
+				t.Errorf("field error = %q, want %q", fe.Error(), tt.wantErr)
+			}
+			if want := "invalid input: " + tt.wantErr; err.Error() != want {
+				t.Errorf("error = %q, want %q", err.Error(), want)
+			}
+		})
+	}
+}
+
+func TestAmountValidatorCollectsEveryField(t *testing.T) {
+	var v amountValidator
+	v.positive("monthly_income", "0")
+	v.nonNegative("total", "-1", true)
+	v.returnRate("expected_return", "abc", false)
+
+	want := "invalid input: monthly_income: must be greater than zero (got 0)\n" +
+		"total: cannot be negative (got -1)\n" +
+		`expected_return: "abc" is not a number`
+	if err := v.err(); err == nil || err.Error() != want {
+		t.Errorf("error = %v, want %q", err, want)
+	}
+}
+
+func TestAmountValidatorRatesNeverGroupThousands(t *testing.T) {
+	withCurrency(t, "USD")
+	tests := []struct {
+		name    string
+		check   func(v *amountValidator, raw string) float64
+		raw     string
+		want    float64
+		wantErr string
+	}{
+		{name: "money: 6.875", check: positiveAmount, raw: "6.875", want: 6875},
+		{name: "money: 7.125", check: positiveAmount, raw: "7.125", want: 7125},
+		{name: "money: 1.500", check: positiveAmount, raw: "1.500", want: 1500},
+
+		{name: "return: 6.875", check: requiredReturnRate, raw: "6.875", want: 6.875},
+		{name: "return: 7.125", check: requiredReturnRate, raw: "7.125", want: 7.125},
+		{name: "return: 1.500", check: requiredReturnRate, raw: "1.500", want: 1.5},
+
+		{name: "apr: 6.875", check: debtAPR, raw: "6.875", want: 6.875},
+		{name: "apr: 7.125", check: debtAPR, raw: "7.125", want: 7.125},
+		{name: "apr: 1.500", check: debtAPR, raw: "1.500", want: 1.5},
+		{name: "apr: 100", check: debtAPR, raw: "100", want: 100},
+		{name: "apr: over 100", check: debtAPR, raw: "6875", wantErr: "apr: must be between 0% and 100% (got 6875%)"},
+		{name: "apr: negative", check: debtAPR, raw: "-1", wantErr: "apr: must be between 0% and 100% (got -1%)"},
+
+		{name: "tax: 1.500", check: marginalTaxRate, raw: "1.500", want: 1.5},
+		{name: "fee: 1.500", check: annualFee, raw: "1.500", want: 1.5},
+		{name: "inflation: 2.125", check: inflation, raw: "2.125", want: 2.125},
+		{name: "years: 7.125", check: horizonYears, raw: "7.125 years", want: 7.125},
+		{name: "years: 1.500", check: horizonYears, raw: "1.500", want: 1.5},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var v amountValidator
+			got := tt.check(&v, tt.raw)
+			err := v.err()
+			if tt.wantErr != "" {
+				var fe *fieldError
+				if !errors.As(err, &fe) || fe.Error() != tt.wantErr {
+					t.Errorf("error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !approxEqual(got, tt.want) {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// Each validator in the shape of the tables above
+
+func positiveAmount(v *amountValidator, raw string) float64 { return v.positive("amount", raw) }
+
+func requiredNonNegative(v *amountValidator, raw string) float64 {
+	return v.nonNegative("amount", raw, true)
+}
+
+func optionalNonNegative(v *amountValidator, raw string) float64 {
+	return v.nonNegative("amount", raw, false)
+}
+
+func requiredReturnRate(v *amountValidator, raw string) float64 {
+	return v.returnRate("return", raw, true)
+}
+
+func optionalReturnRate(v *amountValidator, raw string) float64 {
+	return v.returnRate("return", raw, false)
+}
+
+func debtAPR(v *amountValidator, raw string) float64 {
+	return v.percent("apr", raw, true, maxDebtAPRPct)
+}
+
+func marginalTaxRate(v *amountValidator, raw string) float64 { return v.taxRate("tax", raw) }
+
+func annualFee(v *amountValidator, raw string) float64 { return v.feeRate("fee", raw) }
+
+func inflation(v *amountValidator, raw string) float64 { return v.inflationRate("inflation", raw) }
+
+func horizonYears(v *amountValidator, raw string) float64 { return v.years("years", raw, 1, 50) }
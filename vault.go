@@ -0,0 +1,180 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/becomeliminal/nim-go-sdk/core"
+)
+
+// ============================================
+// SAVINGS VAULT BASELINE
+// ============================================
+
+// vaultRateCache holds the most recent vault APY seen from get_vault_rates
+type vaultRateCache struct {
+	mu        sync.RWMutex
+	apy       float64 // annual %, e.g. 4.5
+	updatedAt time.Time
+
+	executor core.ToolExecutor // set in main; nil leaves every tool on DEFAULT_VAULT_APY
+}
+
+var vaultRates vaultRateCache
+
+// vaultAPYKeys are the get_vault_rates fields we accept as an APY, at the top level or per vault
+var vaultAPYKeys = []string{"apy", "current_apy", "rate"}
+
+// vaultRateTimeout bounds how long a tool waits on get_vault_rates before falling back
+const vaultRateTimeout = 3 * time.Second
+
+// maxPlausibleVaultAPY is the highest reading accepted as a percent; anything above it is
+// treated as a bad response rather than projected
+const maxPlausibleVaultAPY = 25.0
+
+// set records a freshly observed vault APY
+func (c *vaultRateCache) set(apy float64) {
+	c.mu.Lock()
+	c.apy = apy
+	c.updatedAt = time.Now()
+	c.mu.Unlock()
+}
+
+// current returns the cached APY, or the configured default when nothing fresh has been cached
+func (c *vaultRateCache) current() (apy float64, live bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	if c.updatedAt.IsZero() || time.Since(c.updatedAt) > appConfig.VaultRateTTL {
+		return appConfig.Assumptions.SavingsAPY, false
+	}
+	return c.apy, true
+}
+
+// refresh fetches the live APY through get_vault_rates, as userID, once the cached one is older
+// than VAULT_RATE_TTL. Savings-oriented tools call it before projecting; failures leave current()
+// on the default.
+func (c *vaultRateCache) refresh(ctx context.Context, userID string) {
+	c.mu.RLock()
+	fresh := !c.updatedAt.IsZero() && time.Since(c.updatedAt) <= appConfig.VaultRateTTL
+	executor := c.executor
+	c.mu.RUnlock()
+	if fresh || executor == nil {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, vaultRateTimeout)
+	defer cancel()
+	resp, err := executor.Execute(ctx, &core.ExecuteRequest{
+		UserID:    userID,
+		Tool:      "get_vault_rates",
+		Input:     json.RawMessage(`{}`),
+		RequestID: "req_" + generateRandomID(),
+	})
+	if err != nil {
+		log.Printf("⚠️  get_vault_rates unavailable, using default APY: %v\n", err)
+		return
+	}
+	if !resp.Success {
+		log.Printf("⚠️  get_vault_rates failed, using default APY: %s\n", resp.Error)
+		return
+	}
+	apy, ok := parseVaultAPY(resp.Data)
+	if !ok {
+		return
+	}
+	if apy, ok = vaultAPYPercent(apy); !ok {
+		log.Printf("⚠️  get_vault_rates returned an implausible APY %v, using default APY\n", apy)
+		return
+	}
+	c.set(apy)
+}
+
+// vaultAPYPercent normalizes a get_vault_rates reading to percent. Readings below 1 are taken
+// as fractions (0.045 is 4.5%); a vault genuinely paying under 1% APY would be read 100 times
+// too high, and is caught by the 25% ceiling unless it pays under 0.25%. ok is false outside (0, 25].
+func vaultAPYPercent(apy float64) (float64, bool) {
+	if apy > 0 && apy < 1 {
+		apy *= 100
+	}
+	return apy, apy > 0 && apy <= maxPlausibleVaultAPY
+}
+
+// parseVaultAPY reads an APY from a get_vault_rates response: a top-level rate, or the best
+// rate among "rates"/"vaults" entries
+func parseVaultAPY(data json.RawMessage) (float64, bool) {
+	var body map[string]interface{}
+	if err := json.Unmarshal(data, &body); err != nil {
+		return 0, false
+	}
+	if apy, ok := vaultAPYField(body); ok {
+		return apy, true
+	}
+	best, found := 0.0, false
+	for _, key := range []string{"rates", "vaults"} {
+		entries, _ := body[key].([]interface{})
+		for _, entry := range entries {
+			fields, _ := entry.(map[string]interface{})
+			if apy, ok := vaultAPYField(fields); ok && (!found || apy > best) {
+				best, found = apy, true
+			}
+		}
+	}
+	return best, found
+}
+
+// vaultAPYField returns the first vaultAPYKeys field that holds a number
+func vaultAPYField(fields map[string]interface{}) (float64, bool) {
+	for _, key := range vaultAPYKeys {
+		switch v := fields[key].(type) {
+		case float64:
+			return v, true
+		case string:
+			if n, err := parseRate(v); err == nil {
+				return n, true
+			}
+		}
+	}
+	return 0, false
+}
+
+// rateSource labels where a rate came from for the assistant
+func rateSource(live bool) string {
+	if live {
+		return "live"
+	}
+	return "default"
+}
+
+// baselineComparison contrasts a projected total against the same cash flows left in the vault.
+// The caller computes baselineTotal with the same contribution timing as projectedTotal.
+func baselineComparison(projectedTotal, baselineTotal, vaultAPY float64, live bool) BaselineComparison {
+	delta := projectedTotal - baselineTotal
+	multiple := 0.0
+	if baselineTotal > 0 {
+		multiple = projectedTotal / baselineTotal
+	}
+
+	return BaselineComparison{
+		VaultAPY:          vaultAPY,
+		RateSource:        rateSource(live),
+		BaselineTotalUSD:  baselineTotal,
+		ProjectedTotalUSD: projectedTotal,
+		DeltaUSD:          delta,
+		Multiple:          multiple,
+		Summary:           formatBaselineSummary(delta, multiple, vaultAPY),
+	}
+}
+
+func formatBaselineSummary(delta, multiple, vaultAPY float64) string {
+	direction := "more"
+	if delta < 0 {
+		direction = "less"
+	}
+	return fmt.Sprintf("%s %s than leaving the same money in savings at %.2f%% APY (%.2fx)",
+		formatMoney(math.Abs(delta)), direction, vaultAPY, multiple)
+}
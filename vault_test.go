@@ -0,0 +1,143 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/becomeliminal/nim-go-sdk/core"
+)
+
+// fakeExecutor answers every call with data and records the requests it saw
+type fakeExecutor struct {
+	data     string
+	requests []*core.ExecuteRequest
+}
+
+func (f *fakeExecutor) Execute(_ context.Context, req *core.ExecuteRequest) (*core.ExecuteResponse, error) {
+	f.requests = append(f.requests, req)
+	return &core.ExecuteResponse{Success: true, Data: json.RawMessage(f.data)}, nil
+}
+
+// clearVaultRate forgets any cached live APY so current() falls back to the default
+func clearVaultRate(t *testing.T) {
+	t.Helper()
+	vaultRates.mu.Lock()
+	vaultRates.apy, vaultRates.updatedAt = 0, time.Time{}
+	vaultRates.mu.Unlock()
+	t.Cleanup(func() {
+		vaultRates.mu.Lock()
+		vaultRates.apy, vaultRates.updatedAt = 0, time.Time{}
+		vaultRates.mu.Unlock()
+	})
+}
+
+func TestBaselineComparisonDeltaIsConsistent(t *testing.T) {
+	tests := []struct {
+		name        string
+		initial     float64
+		monthly     float64
+		returnRate  float64
+		increasePct float64
+		months      int
+		vaultAPY    float64 // 0 leaves the cache empty so the default rate applies
+	}{
+		{name: "flat contributions, live rate", initial: 1000, monthly: 200, returnRate: 7, months: 120, vaultAPY: 4.5},
+		{name: "escalating contributions, live rate", initial: 0, monthly: 300, returnRate: 8, increasePct: 3, months: 240, vaultAPY: 4},
+		{name: "partial final year", initial: 5000, monthly: 100, returnRate: 6, increasePct: 5, months: 30, vaultAPY: 3.25},
+		{name: "lump sum only", initial: 10000, returnRate: 7, months: 60, vaultAPY: 4.5},
+		{name: "loss beats nothing", initial: 1000, monthly: 50, returnRate: -2, months: 36, vaultAPY: 4.5},
+		{name: "default rate", initial: 2000, monthly: 150, returnRate: 7, months: 180},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clearVaultRate(t)
+			wantAPY, wantSource := appConfig.Assumptions.SavingsAPY, "default"
+			if tt.vaultAPY > 0 {
+				vaultRates.set(tt.vaultAPY)
+				wantAPY, wantSource = tt.vaultAPY, "live"
+			}
+
+			p := calculateEscalatingGrowthMonths(tt.initial, tt.monthly, tt.returnRate, tt.increasePct, tt.months)
+			b := p.BaselineComparison
+
+			if b.VaultAPY != wantAPY || b.RateSource != wantSource {
+				t.Errorf("rate = %v (%s), want %v (%s)", b.VaultAPY, b.RateSource, wantAPY, wantSource)
+			}
+			if !approxEqual(b.ProjectedTotalUSD, p.ProjectedTotalUSD) {
+				t.Errorf("baseline projected total = %v, projection = %v", b.ProjectedTotalUSD, p.ProjectedTotalUSD)
+			}
+			wantBaseline := escalatingFutureValue(tt.initial, tt.monthly, wantAPY, tt.increasePct, float64(tt.months))
+			if !approxEqual(b.BaselineTotalUSD, wantBaseline) {
+				t.Errorf("baseline total = %v, want %v", b.BaselineTotalUSD, wantBaseline)
+			}
+			if !approxEqual(b.DeltaUSD, b.ProjectedTotalUSD-b.BaselineTotalUSD) {
+				t.Errorf("delta = %v, want projected %v - baseline %v", b.DeltaUSD, b.ProjectedTotalUSD, b.BaselineTotalUSD)
+			}
+			if !approxEqual(b.Multiple*b.BaselineTotalUSD, b.ProjectedTotalUSD) {
+				t.Errorf("multiple %v x baseline %v != projected %v", b.Multiple, b.BaselineTotalUSD, b.ProjectedTotalUSD)
+			}
+		})
+	}
+}
+
+func TestBaselineComparisonUsesProjectionTiming(t *testing.T) {
+	// At the vault's own rate the two projections are the same cash flows, so nothing separates them
+	clearVaultRate(t)
+	vaultRates.set(5)
+	for _, increasePct := range []float64{0, 4} {
+		b := calculateEscalatingGrowthMonths(2500, 250, 5, increasePct, 123).BaselineComparison
+		if !approxEqual(b.DeltaUSD, 0) || !approxEqual(b.Multiple, 1) {
+			t.Errorf("increase %v%%: delta = %v, multiple = %v, want 0 and 1", increasePct, b.DeltaUSD, b.Multiple)
+		}
+	}
+}
+
+func TestBaselineComparisonWithoutBaseline(t *testing.T) {
+	b := baselineComparison(0, 0, 4.5, true)
+	if b.DeltaUSD != 0 || b.Multiple != 0 {
+		t.Errorf("empty projection: delta = %v, multiple = %v, want 0 and 0", b.DeltaUSD, b.Multiple)
+	}
+}
+
+func TestVaultRefreshUnits(t *testing.T) {
+	tests := []struct {
+		name     string
+		data     string
+		wantAPY  float64
+		wantLive bool
+	}{
+		{name: "percent", data: `{"apy": 4.5}`, wantAPY: 4.5, wantLive: true},
+		{name: "fraction", data: `{"apy": 0.045}`, wantAPY: 4.5, wantLive: true},
+		{name: "fraction in vault list", data: `{"vaults": [{"rate": 0.031}, {"rate": 0.042}]}`, wantAPY: 4.2, wantLive: true},
+		{name: "percent string", data: `{"current_apy": "3.75%"}`, wantAPY: 3.75, wantLive: true},
+		{name: "implausibly high", data: `{"apy": 450}`, wantLive: false},
+		{name: "zero", data: `{"apy": 0}`, wantLive: false},
+		{name: "negative", data: `{"apy": -2}`, wantLive: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			exec := &fakeExecutor{data: tt.data}
+			cache := vaultRateCache{executor: exec}
+			cache.refresh(context.Background(), "user_42")
+
+			if len(exec.requests) != 1 || exec.requests[0].UserID != "user_42" || exec.requests[0].Tool != "get_vault_rates" {
+				t.Fatalf("requests = %+v, want one get_vault_rates call as user_42", exec.requests)
+			}
+			apy, live := cache.current()
+			if live != tt.wantLive {
+				t.Fatalf("live = %v, want %v (apy %v)", live, tt.wantLive, apy)
+			}
+			if live && !approxEqual(apy, tt.wantAPY) {
+				t.Errorf("apy = %v, want %v", apy, tt.wantAPY)
+			}
+		})
+	}
+}
+
+// approxEqual compares money amounts to within a hundredth of a cent
+func approxEqual(a, b float64) bool {
+	return math.Abs(a-b) <= 1e-4*math.Max(1, math.Abs(b))
+}
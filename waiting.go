@@ -0,0 +1,91 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+
+	"github.com/becomeliminal/nim-go-sdk/core"
+	"github.com/becomeliminal/nim-go-sdk/tools"
+)
+
+// ============================================
+// COST OF WAITING
+// ============================================
+// Both starters invest the same monthly amount and stop at the same date; the
+// delayed one simply has fewer months of contributions and compounding.
+
+// newCostOfWaitingTool compares starting to invest now against starting after a delay
+func newCostOfWaitingTool() core.Tool {
+	return tools.New("cost_of_waiting_calculator").
+		Description("Show what delaying investing costs: ending value when starting now vs after a delay with the same monthly contribution and end date, the amount lost by waiting, and the extra monthly contribution the late starter needs to catch up").
+		Schema(tools.ObjectSchema(map[string]interface{}{
+			"monthly_contribution": tools.StringProperty("Amount invested each month in the account currency"),
+			"years":                tools.StringProperty("Total horizon in years from today, 1-60"),
+			"delay_years":          tools.StringProperty("How many years the user is considering waiting before starting (e.g., '2' or '0.5')"),
+			"expected_return":      tools.StringProperty(fmt.Sprintf("Optional expected annual return percentage (defaults to the server's assumption, currently %g)", appConfig.Assumptions.EquityReturnPct)),
+		}, "monthly_contribution", "years", "delay_years")).
+		HandlerFunc(func(ctx context.Context, input json.RawMessage) (interface{}, error) {
+			var params struct {
+				MonthlyContribution string `json:"monthly_contribution"`
+				Years               string `json:"years"`
+				DelayYears          string `json:"delay_years"`
+				ExpectedReturn      string `json:"expected_return"`
+			}
+			if err := json.Unmarshal(input, &params); err != nil {
+				return nil, fmt.Errorf("invalid input: %w", err)
+			}
+
+			var v amountValidator
+			monthly := v.positive("monthly_contribution", params.MonthlyContribution)
+			years := v.years("years", params.Years, minProjectionYears, maxProjectionYears)
+			delay := v.years("delay_years", params.DelayYears, 0, maxProjectionYears)
+			returnRate := v.returnRate("expected_return", params.ExpectedReturn, false)
+			if len(v.errs) == 0 && delay == 0 {
+				v.fail("delay_years", "must be greater than zero")
+			}
+			if err := v.err(); err != nil {
+				return nil, err
+			}
+
+			return costOfWaiting(monthly, returnRate, years, delay), nil
+		}).
+		Build()
+}
+
+// costOfWaiting projects both starters to the same end date and sizes the late starter's catch-up
+func costOfWaiting(monthly, returnRate, years, delay float64) CostOfWaitingResult {
+	totalMonths := int(math.Round(years * 12))
+	delayMonths := int(math.Round(delay * 12))
+	r := CostOfWaitingResult{
+		Currency:              activeCurrency().Code,
+		MonthlyContribution:   monthly,
+		Years:                 years,
+		DelayYears:            delay,
+		ExpectedReturnPercent: returnRate,
+	}
+	if delayMonths >= totalMonths {
+		r.Message = fmt.Sprintf("A %g-year delay uses up the whole %g-year horizon, so the delayed starter never invests. Use a delay shorter than the horizon to compare.", delay, years)
+		return r
+	}
+
+	now := calculateCompoundGrowthMonths(0, monthly, returnRate, totalMonths)
+	later := calculateCompoundGrowthMonths(0, monthly, returnRate, totalMonths-delayMonths)
+	required := requiredMonthlyContribution(now.ProjectedTotalUSD, 0, returnRate, float64(totalMonths-delayMonths))
+
+	r.StartNowTotalUSD = now.ProjectedTotalUSD
+	r.StartNowContributedUSD = now.TotalContributed
+	r.StartLaterTotalUSD = later.ProjectedTotalUSD
+	r.StartLaterContributedUSD = later.TotalContributed
+	r.CostOfWaitingUSD = now.ProjectedTotalUSD - later.ProjectedTotalUSD
+	r.CatchUpMonthlyUSD = required
+	r.ExtraMonthlyToCatchUpUSD = required - monthly
+	if now.ProjectedTotalUSD > 0 {
+		r.CostPercent = r.CostOfWaitingUSD / now.ProjectedTotalUSD * 100
+	}
+	r.Message = fmt.Sprintf("Starting %s/month now grows to %s in %g years; waiting %g years first ends at %s, %s less (%.0f%%) even though only %s fewer dollars go in. To catch up, the late starter would need %s/month, %s more each month.",
+		formatMoney(monthly), formatMoney(r.StartNowTotalUSD), years, delay, formatMoney(r.StartLaterTotalUSD), formatMoney(r.CostOfWaitingUSD), r.CostPercent,
+		formatMoney(r.StartNowContributedUSD-r.StartLaterContributedUSD), formatMoney(required), formatMoney(r.ExtraMonthlyToCatchUpUSD))
+	return r
+}
@@ -0,0 +1,305 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/becomeliminal/nim-go-sdk/core"
+	"github.com/becomeliminal/nim-go-sdk/tools"
+)
+
+// ============================================
+// WINDFALL ALLOCATION
+// ============================================
+// A bonus, tax refund or inheritance is applied in a fixed order: expensive debt,
+// then the emergency fund, then goals due within 3 years (the gap their planned
+// contributions won't cover), then tax-advantaged room, then taxable investing
+// split by the user's risk allocation.
+
+// Windfall bucket names, in the order they are funded
+const (
+	bucketHighInterestDebt = "high_interest_debt"
+	bucketEmergencyFund    = "emergency_fund"
+	bucketNearTermGoals    = "near_term_goals"
+	bucketTaxAdvantaged    = "tax_advantaged"
+	bucketTaxable          = "taxable_investing"
+)
+
+// defaultWindfallHorizonYrs is how far invested buckets are projected when no horizon is given
+const defaultWindfallHorizonYrs = 10.0
+
+// nearTermGoalMonths is the horizon under which a goal's money stays in savings
+const nearTermGoalMonths = 36
+
+// windfallGoal is a goal the windfall may top up
+type windfallGoal struct {
+	Name         string
+	TargetAmount float64
+	Monthly      float64 // planned monthly contribution
+	TargetDate   time.Time
+}
+
+// newWindfallTool turns a one-off sum into an ordered dollar allocation
+func newWindfallTool(liminalExecutor core.ToolExecutor) core.Tool {
+	return tools.New("windfall_allocation_planner").
+		Description("Plan what to do with a one-off sum (bonus, tax refund, inheritance): an ordered dollar allocation across high-interest debt, emergency fund top-up, near-term goals, tax-advantaged accounts and taxable investing, with the reasoning and projected long-term value of each bucket").
+		Schema(tools.ObjectSchema(map[string]interface{}{
+			"amount": tools.StringProperty("Windfall amount in the account currency"),
+			"debts": map[string]interface{}{
+				"type":        "array",
+				"description": "Optional debts the user carries",
+				"items": tools.ObjectSchema(map[string]interface{}{
+					"name":    tools.StringProperty("Debt name, e.g. 'Visa card'"),
+					"balance": tools.StringProperty("Outstanding balance in the account currency"),
+					"apr":     tools.StringProperty("Annual percentage rate from 0 to 100 (e.g., '24.9')"),
+				}, "name", "balance", "apr"),
+			},
+			"monthly_expenses":    tools.StringProperty("Optional average monthly spending in the account currency; omit to derive it from transaction history"),
+			"emergency_savings":   tools.StringProperty("Optional amount already in the emergency fund in the account currency; omit to use the savings balance"),
+			"income_stability":    tools.StringProperty("Optional income stability: 'stable' (3 months), 'moderate' (6 months, default), 'unstable' (12 months)"),
+			"tax_advantaged_room": tools.StringProperty(fmt.Sprintf("Optional unused IRA/401(k) contribution room this year in the account currency (default: a full IRA, %s)", formatWholeMoney(annualContributionLimits["ira"]))),
+			"risk_level":          tools.StringProperty("Optional risk level for the taxable portion; omit to use the user's profile"),
+			"expected_return":     tools.StringProperty(fmt.Sprintf("Optional expected annual return percentage (defaults to the server's assumption, currently %g)", appConfig.Assumptions.EquityReturnPct)),
+			"horizon_years":       tools.StringProperty(fmt.Sprintf("Optional years to project invested buckets over (default %.0f)", defaultWindfallHorizonYrs)),
+		}, "amount")).
+		Handler(func(ctx context.Context, toolParams *core.ToolParams) (*core.ToolResult, error) {
+			var params struct {
+				Amount string `json:"amount"`
+				Debts  []struct {
+					Name    string `json:"name"`
+					Balance string `json:"balance"`
+					APR     string `json:"apr"`
+				} `json:"debts"`
+				MonthlyExpenses   string `json:"monthly_expenses"`
+				EmergencySavings  string `json:"emergency_savings"`
+				IncomeStability   string `json:"income_stability"`
+				TaxAdvantagedRoom string `json:"tax_advantaged_room"`
+				RiskLevel         string `json:"risk_level"`
+				ExpectedReturn    string `json:"expected_return"`
+				HorizonYears      string `json:"horizon_years"`
+			}
+			if err := json.Unmarshal(toolParams.Input, &params); err != nil {
+				return &core.ToolResult{Success: false, Error: fmt.Sprintf("invalid input: %v", err)}, nil
+			}
+			if strings.TrimSpace(params.IncomeStability) == "" {
+				params.IncomeStability = "moderate"
+			}
+
+			var v amountValidator
+			amount := v.positive("amount", params.Amount)
+			debts := make([]debt, 0, len(params.Debts))
+			for i, d := range params.Debts {
+				field := fmt.Sprintf("debts[%d]", i)
+				debts = append(debts, debt{
+					Name:    d.Name,
+					Balance: v.positive(field+".balance", d.Balance),
+					APR:     v.percent(field+".apr", d.APR, true, maxDebtAPRPct),
+				})
+			}
+			stability := v.oneOf("income_stability", params.IncomeStability, incomeStabilities)
+			expenses := 0.0
+			if strings.TrimSpace(params.MonthlyExpenses) != "" {
+				expenses = v.positive("monthly_expenses", params.MonthlyExpenses)
+			}
+			savings := v.nonNegative("emergency_savings", params.EmergencySavings, false)
+			room := annualContributionLimits["ira"]
+			if strings.TrimSpace(params.TaxAdvantagedRoom) != "" {
+				room = v.nonNegative("tax_advantaged_room", params.TaxAdvantagedRoom, true)
+			}
+			returnRate := v.returnRate("expected_return", params.ExpectedReturn, false)
+			horizon := defaultWindfallHorizonYrs
+			if strings.TrimSpace(params.HorizonYears) != "" {
+				horizon = v.years("horizon_years", params.HorizonYears, minProjectionYears, maxProjectionYears)
+			}
+			if err := v.err(); err != nil {
+				return &core.ToolResult{Success: false, Error: err.Error()}, nil
+			}
+
+			riskSource := "user_provided"
+			rawRisk := params.RiskLevel
+			if strings.TrimSpace(rawRisk) == "" {
+				portfolio, err := loadPortfolio(ctx, toolParams.UserID)
+				if err != nil {
+					return &core.ToolResult{Success: false, Error: profileFieldError(err, "risk_level").Error()}, nil
+				}
+				rawRisk, riskSource = string(portfolio.RiskTolerance), "profile"
+			}
+			risk, err := normalizeRiskLevel(rawRisk)
+			if err != nil {
+				return &core.ToolResult{Success: false, Error: fmt.Sprintf("invalid input: risk_level: %v", err)}, nil
+			}
+
+			// The emergency bucket is skipped rather than guessed when spending can't be found
+			now := time.Now()
+			expenseSource := "user_reported"
+			if strings.TrimSpace(params.MonthlyExpenses) == "" {
+				expenseSource = "unavailable"
+				if spend, ok := fetchMonthlySpend(ctx, liminalExecutor, toolParams.UserID, now); ok {
+					expenses, expenseSource = spend, "transaction_history"
+				}
+			}
+			if strings.TrimSpace(params.EmergencySavings) == "" {
+				if balance, ok := fetchSavingsBalance(ctx, liminalExecutor, toolParams.UserID); ok {
+					savings = balance
+				}
+			}
+
+			stored, err := store.ListGoals(ctx, userKey(toolParams.UserID))
+			if err != nil {
+				return &core.ToolResult{Success: false, Error: fmt.Sprintf("could not load goals: %v", err)}, nil
+			}
+			var goals []windfallGoal
+			for _, g := range stored {
+				target, err := time.Parse("2006-01-02", g.TargetDate)
+				if err != nil || isEmergencyGoal(g.Name) {
+					continue // the emergency fund has its own bucket
+				}
+				goals = append(goals, windfallGoal{Name: g.Name, TargetAmount: g.TargetAmount, Monthly: g.MonthlyContribution, TargetDate: target})
+			}
+
+			vaultRates.refresh(ctx, toolParams.UserID)
+			result := allocateWindfall(amount, debts, expenses*float64(emergencyMonthsByStability[stability]), savings,
+				goals, room, risk, returnRate, horizon, now)
+			result.RiskSource = riskSource
+			result.ExpenseSource = expenseSource
+			return &core.ToolResult{Success: true, Data: result}, nil
+		}).
+		Build()
+}
+
+// allocateWindfall fills each bucket in order until the windfall runs out
+func allocateWindfall(amount float64, debts []debt, emergencyTarget, emergencySavings float64, goals []windfallGoal,
+	room float64, risk RiskLevel, returnRate, horizon float64, now time.Time) WindfallAllocation {
+	months := math.Round(horizon * 12)
+	apy, live := vaultRates.current()
+	remaining := amount
+	take := func(want float64) float64 {
+		give := min(max(want, 0), remaining)
+		remaining -= give
+		return give
+	}
+
+	result := WindfallAllocation{
+		Currency:              activeCurrency().Code,
+		WindfallUSD:           amount,
+		RiskLevel:             risk,
+		ExpectedReturnPercent: returnRate,
+		HorizonYears:          horizon,
+		VaultAPY:              apy,
+		VaultRateSource:       rateSource(live),
+		Buckets:               []WindfallBucket{},
+		AllInvestedValueUSD:   futureValue(amount, 0, returnRate, months),
+	}
+	add := func(b WindfallBucket) {
+		if b.AmountUSD > 0 {
+			b.Order = len(result.Buckets) + 1
+			result.Buckets = append(result.Buckets, b)
+		}
+	}
+
+	// 1. Debt costing more than the market is expected to return, highest APR first
+	sort.SliceStable(debts, func(i, j int) bool { return debts[i].APR > debts[j].APR })
+	for _, d := range debts {
+		if d.APR < appConfig.HighAPRThreshold && d.APR <= returnRate {
+			result.DebtsKept = append(result.DebtsKept, d.Name)
+			continue
+		}
+		paid := take(d.Balance)
+		add(WindfallBucket{
+			Bucket:                    bucketHighInterestDebt,
+			Label:                     "Pay off " + d.Name,
+			AmountUSD:                 paid,
+			InterestAvoidedPerYearUSD: paid * d.APR / 100,
+			Reasoning: fmt.Sprintf("%.1f%% APR is a guaranteed return on every dollar paid, more than the %.1f%% investing is expected to earn",
+				d.APR, returnRate),
+		})
+	}
+
+	// 2. Emergency fund up to its target, kept in the vault
+	if emergencyTarget > 0 {
+		topUp := take(emergencyTarget - emergencySavings)
+		add(WindfallBucket{
+			Bucket:            bucketEmergencyFund,
+			Label:             "Top up emergency fund",
+			AmountUSD:         topUp,
+			AnnualReturn:      apy,
+			ProjectedValueUSD: futureValue(topUp, 0, apy, months),
+			Reasoning: fmt.Sprintf("Brings the fund from %s to its %s target so a surprise bill never forces selling investments or new debt",
+				formatMoney(emergencySavings), formatMoney(min(emergencySavings+topUp, emergencyTarget))),
+		})
+	} else {
+		result.Notes = append(result.Notes, "Emergency fund skipped: spending couldn't be read from transaction history; pass monthly_expenses to include it")
+	}
+
+	// 3. Goals due within 3 years: the gap their planned contributions won't close in time
+	sort.SliceStable(goals, func(i, j int) bool { return goals[i].TargetDate.Before(goals[j].TargetDate) })
+	for _, g := range goals {
+		left := monthsUntil(now, g.TargetDate)
+		if left < 1 || left > nearTermGoalMonths {
+			continue
+		}
+		gap := g.TargetAmount - futureValue(0, g.Monthly, apy, float64(left))
+		funded := take(gap)
+		add(WindfallBucket{
+			Bucket:            bucketNearTermGoals,
+			Label:             "Fund " + g.Name,
+			AmountUSD:         funded,
+			AnnualReturn:      apy,
+			ProjectedValueUSD: futureValue(funded, 0, apy, float64(left)),
+			Reasoning: fmt.Sprintf("Due %s; planned contributions of %s/month leave a %s gap, and money needed within 3 years belongs in savings rather than the market",
+				g.TargetDate.Format("2006-01-02"), formatMoney(g.Monthly), formatMoney(gap)),
+		})
+	}
+
+	// 4. Tax-advantaged room
+	sheltered := take(room)
+	add(WindfallBucket{
+		Bucket:            bucketTaxAdvantaged,
+		Label:             "Contribute to IRA/401(k)",
+		AmountUSD:         sheltered,
+		AnnualReturn:      returnRate,
+		ProjectedValueUSD: futureValue(sheltered, 0, returnRate, months),
+		Reasoning:         fmt.Sprintf("Growth in a tax-advantaged account isn't taxed each year; this year's unused room is %s and doesn't carry over", formatMoney(room)),
+	})
+
+	// 5. Everything else invested per the risk allocation
+	taxable := take(remaining)
+	split := make(map[string]float64, len(rebalanceAssets))
+	for _, asset := range rebalanceAssets {
+		split[asset] = taxable * targetAllocationTable[risk][asset] / 100
+	}
+	add(WindfallBucket{
+		Bucket:            bucketTaxable,
+		Label:             "Invest in a taxable account",
+		AmountUSD:         taxable,
+		AnnualReturn:      returnRate,
+		ProjectedValueUSD: futureValue(taxable, 0, returnRate, months),
+		AllocationUSD:     split,
+		Reasoning: fmt.Sprintf("Split %.0f/%.0f/%.0f/%.0f/%.0f across US stocks, international stocks, REITs, bonds and cash to match a %s risk level",
+			targetAllocationTable[risk]["stocks"], targetAllocationTable[risk]["international"], targetAllocationTable[risk]["reit"],
+			targetAllocationTable[risk]["bonds"], targetAllocationTable[risk]["cash"], risk),
+	})
+
+	for _, b := range result.Buckets {
+		if b.Bucket != bucketNearTermGoals { // goal money is spent on its target date, not held to the horizon
+			result.PlanValueUSD += b.ProjectedValueUSD
+		}
+		result.InterestAvoidedPerYearUSD += b.InterestAvoidedPerYearUSD
+	}
+	if len(result.DebtsKept) > 0 {
+		result.Notes = append(result.Notes, fmt.Sprintf("Kept paying the minimum on %s: APR below both the %.0f%% high-interest threshold and the expected return",
+			strings.Join(result.DebtsKept, ", "), appConfig.HighAPRThreshold))
+	}
+	result.Summary = fmt.Sprintf("Investing all %s would project to %s in %.0f years; this plan projects %s in savings and investments",
+		formatMoney(amount), formatMoney(result.AllInvestedValueUSD), horizon, formatMoney(result.PlanValueUSD))
+	if result.InterestAvoidedPerYearUSD > 0 {
+		result.Summary += fmt.Sprintf(" and avoids %s a year in interest", formatMoney(result.InterestAvoidedPerYearUSD))
+	}
+	result.Summary += ". The difference is the price of lower risk: no costly debt and cash on hand for emergencies and near-term goals."
+	return result
+}